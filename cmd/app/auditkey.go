@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+
+	"github.com/device-management-toolkit/console/config"
+	secrets "github.com/device-management-toolkit/console/pkg/secrets/vault"
+)
+
+// Names of the audit trail's keys in the secret store. The previous keys,
+// comma separated, still verify the entries they signed before a rotation.
+const (
+	auditKeyName          = "audit-key"
+	previousAuditKeysName = "previous-audit-keys"
+)
+
+// handleAuditKey loads the key that signs the audit trail from the secret
+// store, or the local keyring without one, unless it is set in the config or
+// environment.
+func handleAuditKey(cfg *config.Config) {
+	if cfg.AuditKey != "" {
+		return
+	}
+
+	storage, err := handleSecretsConfig(cfg)
+	if err != nil {
+		storage = security.NewKeyRingStorage("device-management-toolkit")
+	}
+
+	if err := loadAuditKey(cfg, storage); err != nil {
+		log.Printf("Warning: Failed to load the audit key: %v", err)
+	}
+}
+
+// loadAuditKey reads the audit key and the previous ones from storage. A
+// key that is not there yet is generated and saved: entries signed before
+// with another key would no longer verify, so it only is on first start.
+func loadAuditKey(cfg *config.Config, storage security.Storager) error {
+	key, err := storage.GetKeyValue(auditKeyName)
+
+	switch {
+	case err == nil:
+		cfg.AuditKey = key
+	case keyNotFound(err):
+		key = security.Crypto{}.GenerateKey()
+		if err := storage.SetKeyValue(auditKeyName, key); err != nil {
+			return err
+		}
+
+		cfg.AuditKey = key
+
+		log.Println("Audit key generated")
+	default:
+		return err
+	}
+
+	if len(cfg.PreviousAuditKeys) > 0 {
+		return nil
+	}
+
+	previous, err := storage.GetKeyValue(previousAuditKeysName)
+	if err != nil {
+		if keyNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, k := range strings.Split(previous, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			cfg.PreviousAuditKeys = append(cfg.PreviousAuditKeys, k)
+		}
+	}
+
+	return nil
+}
+
+// keyNotFound tells a key missing from the secret store or the local keyring
+// from a failure to read it.
+func keyNotFound(err error) bool {
+	return errors.Is(err, security.ErrKeyNotFound) || errors.Is(err, secrets.ErrKeyNotFound) || errors.Is(err, secrets.ErrSecretNotFound)
+}
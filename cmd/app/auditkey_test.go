@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/config"
+)
+
+func TestLoadAuditKey(t *testing.T) {
+	t.Parallel()
+
+	// Generated and saved on first start.
+	store := memoryStore{}
+	cfg := &config.Config{}
+
+	require.NoError(t, loadAuditKey(cfg, store))
+	require.NotEmpty(t, cfg.AuditKey)
+	require.Equal(t, cfg.AuditKey, store[auditKeyName])
+	require.Empty(t, cfg.PreviousAuditKeys)
+
+	// Read back, with the previous keys of a rotation.
+	store = memoryStore{auditKeyName: "new", previousAuditKeysName: "old, older"}
+	cfg = &config.Config{}
+
+	require.NoError(t, loadAuditKey(cfg, store))
+	require.Equal(t, "new", cfg.AuditKey)
+	require.Equal(t, []string{"old", "older"}, cfg.PreviousAuditKeys)
+}
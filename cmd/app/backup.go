@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/app"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// passphraseEnv holds the backup passphrase when no file is given, so that
+// it does not show in the process list.
+const passphraseEnv = "BACKUP_PASSPHRASE"
+
+// archiveFileMode keeps the archive, which holds every secret, private.
+const archiveFileMode = 0o600
+
+var (
+	errFlagRequired     = errors.New("flag required")
+	errNoPassphrase     = errors.New("no passphrase: use -passphrase-file or set " + passphraseEnv)
+	errUnknownConflicts = errors.New("-on-conflict must be skip, overwrite or rename")
+)
+
+// Function pointers for better testability.
+var (
+	backupFunc  = app.Backup
+	restoreFunc = app.Restore
+)
+
+func runBackup(ctx context.Context, cfg *config.Config, l logger.Interface, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	out := fs.String("out", "", "file to write the archive to")
+	includeDevices := fs.Bool("include-devices", false, "include devices and their credentials")
+	tenant := fs.String("tenant", "", "tenant to back up")
+	passphraseFile := fs.String("passphrase-file", "", "file holding the passphrase (default $"+passphraseEnv+")")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return fmt.Errorf("%w: -out", errFlagRequired)
+	}
+
+	passphrase, err := readPassphrase(*passphraseFile)
+	if err != nil {
+		return err
+	}
+
+	archive, err := backupFunc(ctx, cfg, l, dto.BackupRequest{Passphrase: passphrase, IncludeDevices: *includeDevices}, *tenant)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(*out, archive, archiveFileMode)
+}
+
+func runRestore(ctx context.Context, cfg *config.Config, l logger.Interface, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	in := fs.String("in", "", "archive to restore")
+	onConflict := fs.String("on-conflict", dto.ConflictSkip, "what to do with entities that exist: skip, overwrite or rename")
+	dryRun := fs.Bool("dry-run", false, "validate and report without writing")
+	tenant := fs.String("tenant", "", "tenant to restore into")
+	passphraseFile := fs.String("passphrase-file", "", "file holding the passphrase (default $"+passphraseEnv+")")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *in == "" {
+		return fmt.Errorf("%w: -in", errFlagRequired)
+	}
+
+	switch *onConflict {
+	case dto.ConflictSkip, dto.ConflictOverwrite, dto.ConflictRename:
+	default:
+		return errUnknownConflicts
+	}
+
+	passphrase, err := readPassphrase(*passphraseFile)
+	if err != nil {
+		return err
+	}
+
+	archive, err := os.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+
+	result, err := restoreFunc(ctx, cfg, l, dto.RestoreRequest{
+		Passphrase: passphrase,
+		OnConflict: *onConflict,
+		DryRun:     *dryRun,
+		Archive:    archive,
+	}, *tenant)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range result.Items {
+		line := fmt.Sprintf("%s %q: %s", item.Kind, item.Name, item.Action)
+		if item.RestoredAs != "" {
+			line += fmt.Sprintf(" as %q", item.RestoredAs)
+		}
+
+		if item.Note != "" {
+			line += " (" + item.Note + ")"
+		}
+
+		fmt.Fprintln(stdout, line)
+	}
+
+	if result.DryRun {
+		fmt.Fprintln(stdout, "dry run: nothing was written")
+	}
+
+	return nil
+}
+
+func readPassphrase(file string) (string, error) {
+	if file == "" {
+		if passphrase := os.Getenv(passphraseEnv); passphrase != "" {
+			return passphrase, nil
+		}
+
+		return "", errNoPassphrase
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func TestRunCommand(t *testing.T) { //nolint:paralleltest // cannot have simultaneous tests modifying env variables and function pointers.
+	t.Setenv(passphraseEnv, "correct horse battery")
+
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "console.backup")
+	l := logger.New("error")
+
+	backupFunc = func(_ context.Context, _ *config.Config, _ logger.Interface, req dto.BackupRequest, tenantID string) ([]byte, error) {
+		require.Equal(t, dto.BackupRequest{Passphrase: "correct horse battery", IncludeDevices: true}, req)
+		require.Equal(t, "t1", tenantID)
+
+		return []byte(`{}`), nil
+	}
+
+	restoreFunc = func(_ context.Context, _ *config.Config, _ logger.Interface, req dto.RestoreRequest, _ string) (*dto.RestoreResult, error) {
+		require.Equal(t, dto.ConflictRename, req.OnConflict)
+		require.JSONEq(t, `{}`, string(req.Archive))
+
+		return &dto.RestoreResult{DryRun: req.DryRun, Items: []dto.RestoreItem{
+			{Kind: "ciraConfig", Name: "cira", Action: dto.RestoreRenamed, RestoredAs: "cira-restored"},
+		}}, nil
+	}
+
+	var stdout bytes.Buffer
+
+	require.Equal(t, 0, runCommand(&config.Config{}, l, "backup", []string{"-out", archive, "-include-devices", "-tenant", "t1"}, &stdout))
+
+	info, err := os.Stat(archive)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(archiveFileMode), info.Mode().Perm())
+
+	require.Equal(t, 0, runCommand(&config.Config{}, l, "restore", []string{"-in", archive, "-on-conflict", "rename", "-dry-run"}, &stdout))
+	require.Equal(t, "ciraConfig \"cira\": renamed as \"cira-restored\"\ndry run: nothing was written\n", stdout.String())
+
+	require.Equal(t, 1, runCommand(&config.Config{}, l, "restore", []string{"-in", archive, "-on-conflict", "merge"}, &stdout))
+	require.Equal(t, 1, runCommand(&config.Config{}, l, "backup", nil, &stdout))
+	require.Equal(t, 1, runCommand(&config.Config{}, l, "serve", nil, &stdout))
+}
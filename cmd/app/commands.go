@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var errUnknownCommand = errors.New("unknown command")
+
+// runCommand runs a command given after the flags, such as
+// `console -config config.yml backup -out console.backup`, instead of the
+// server, and returns the exit code.
+func runCommand(cfg *config.Config, l logger.Interface, name string, args []string, stdout io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+
+	switch name {
+	case "backup":
+		err = runBackup(ctx, cfg, l, args)
+	case "restore":
+		err = runRestore(ctx, cfg, l, args, stdout)
+	case "migrate-db":
+		err = runMigrateDB(ctx, cfg, l, args, stdout)
+	case "rotate-key":
+		err = runRotateKey(ctx, cfg, l, args, stdout)
+	case "migrate-credentials":
+		err = runMigrateCredentials(ctx, cfg, l, args, stdout)
+	default:
+		err = fmt.Errorf("%w %q (want backup, restore, migrate-db, rotate-key or migrate-credentials)", errUnknownCommand, name)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+
+		return 1
+	}
+
+	return 0
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -66,6 +67,12 @@ func main() {
 	l := logger.New(cfg.Level)
 
 	handleEncryptionKey(cfg)
+	handleAuditKey(cfg)
+
+	if command := flag.Arg(0); command != "" {
+		os.Exit(runCommand(cfg, l, command, flag.Args()[1:], os.Stdout))
+	}
+
 	handleAdminPassword(cfg)
 
 	// Run with system tray (if built with tray tag and --tray flag) or standard mode
@@ -132,6 +139,13 @@ func handleSecretsConfig(cfg *config.Config) (security.Storager, error) {
 	return secretsClient, nil
 }
 
+// Names of the encryption keys in the secret store. The previous key is kept
+// while a key rotation is in progress.
+const (
+	encryptionKeyName         = "default-security-key"
+	previousEncryptionKeyName = "previous-security-key"
+)
+
 func handleEncryptionKey(cfg *config.Config) {
 	// If encryption key is already provided via config/env, just use it
 	if cfg.EncryptionKey != "" {
@@ -176,18 +190,19 @@ func tryRemoteStorage(cfg *config.Config, remoteStorage security.Storager) bool
 
 	if cfg.EncryptionKey != "" {
 		// Store static key in secret store (not recommended)
-		if err := remoteStorage.SetKeyValue("default-security-key", cfg.EncryptionKey); err == nil {
+		if err := remoteStorage.SetKeyValue(encryptionKeyName, cfg.EncryptionKey); err == nil {
 			log.Println("Encryption key stored in secret store")
 
 			return true
 		}
 	} else {
 		// Retrieve from secret store
-		key, err := remoteStorage.GetKeyValue("default-security-key")
+		key, err := remoteStorage.GetKeyValue(encryptionKeyName)
 		if err == nil {
 			cfg.EncryptionKey = key
 
 			log.Println("Encryption key loaded from secret store")
+			loadPreviousKey(cfg, remoteStorage)
 
 			return true
 		}
@@ -201,16 +216,17 @@ func tryLocalStorage(cfg *config.Config, localStorage, remoteStorage security.St
 	var err error
 
 	if cfg.EncryptionKey != "" {
-		err = localStorage.SetKeyValue("default-security-key", cfg.EncryptionKey)
+		err = localStorage.SetKeyValue(encryptionKeyName, cfg.EncryptionKey)
 		if err == nil {
 			log.Println("Encryption key stored in local keyring")
 
 			return true
 		}
 	} else {
-		cfg.EncryptionKey, err = localStorage.GetKeyValue("default-security-key")
+		cfg.EncryptionKey, err = localStorage.GetKeyValue(encryptionKeyName)
 		if err == nil {
 			log.Println("Encryption key loaded from local keyring")
+			loadPreviousKey(cfg, localStorage)
 			syncKeyToRemote(cfg.EncryptionKey, remoteStorage)
 
 			return true
@@ -230,13 +246,30 @@ func tryLocalStorage(cfg *config.Config, localStorage, remoteStorage security.St
 	return false
 }
 
+// loadPreviousKey loads the previous encryption key, there while a key
+// rotation is in progress, unless it is set in the config or environment.
+func loadPreviousKey(cfg *config.Config, storage security.Storager) {
+	if cfg.PreviousEncryptionKey != "" {
+		return
+	}
+
+	key, err := storage.GetKeyValue(previousEncryptionKeyName)
+	if err != nil || key == cfg.EncryptionKey {
+		return
+	}
+
+	cfg.PreviousEncryptionKey = key
+
+	log.Println("Previous encryption key loaded: a key rotation is in progress")
+}
+
 // syncKeyToRemote syncs an encryption key to the remote storage if available.
 func syncKeyToRemote(key string, remoteStorage security.Storager) {
 	if remoteStorage == nil {
 		return
 	}
 
-	if err := remoteStorage.SetKeyValue("default-security-key", key); err != nil {
+	if err := remoteStorage.SetKeyValue(encryptionKeyName, key); err != nil {
 		log.Printf("Warning: Failed to sync key to secret store: %v", err)
 	} else {
 		log.Println("Encryption key synced to secret store")
@@ -245,7 +278,7 @@ func syncKeyToRemote(key string, remoteStorage security.Storager) {
 
 func saveEncryptionKey(key string, remoteStorage, localStorage security.Storager) error {
 	if remoteStorage != nil {
-		err := remoteStorage.SetKeyValue("default-security-key", key)
+		err := remoteStorage.SetKeyValue(encryptionKeyName, key)
 		if err == nil {
 			log.Println("Encryption key saved to secret store")
 
@@ -255,7 +288,7 @@ func saveEncryptionKey(key string, remoteStorage, localStorage security.Storager
 		return err
 	}
 
-	err := localStorage.SetKeyValue("default-security-key", key)
+	err := localStorage.SetKeyValue(encryptionKeyName, key)
 	if err == nil {
 		log.Println("Encryption key saved to local keyring")
 
@@ -23,7 +23,7 @@ func TestMainFunction(_ *testing.T) { //nolint:paralleltest // cannot have simul
 	initializeConfigFunc = func() (*config.Config, error) {
 		return &config.Config{
 			HTTP: config.HTTP{Port: "8080"},
-			App:  config.App{EncryptionKey: "test"},
+			App:  config.App{EncryptionKey: "test", AuditKey: "test"},
 			Log:  config.Log{Level: "info"},
 			Auth: config.Auth{AdminPassword: "test"},
 		}, nil
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/app"
+	"github.com/device-management-toolkit/console/internal/usecase/credentials"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// Function pointer for better testability.
+var migrateCredentialsFunc = app.MigrateCredentials
+
+func runMigrateCredentials(ctx context.Context, cfg *config.Config, l logger.Interface, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("migrate-credentials", flag.ContinueOnError)
+	batchSize := fs.Int("batch-size", credentials.DefaultBatchSize, "entities to read per query")
+	tenants := fs.String("tenants", "", "comma separated tenants to migrate besides the default one and those in the audit trail")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := credentials.Options{BatchSize: *batchSize}
+
+	for _, tenant := range strings.Split(*tenants, ",") {
+		if tenant = strings.TrimSpace(tenant); tenant != "" {
+			opts.Tenants = append(opts.Tenants, tenant)
+		}
+	}
+
+	report, err := migrateCredentialsFunc(ctx, cfg, l, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "tenants: %q\n", report.Tenants)
+	fmt.Fprintf(stdout, "%-20s %8s %8s\n", "", "moved", "stored")
+
+	for _, k := range report.Kinds {
+		fmt.Fprintf(stdout, "%-20s %8d %8d\n", k.Kind, k.Moved, k.Stored)
+	}
+
+	return nil
+}
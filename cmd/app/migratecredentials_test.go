@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/usecase/credentials"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func TestRunMigrateCredentials(t *testing.T) { //nolint:paralleltest // cannot have simultaneous tests modifying env variables and function pointers.
+	migrateCredentialsFunc = func(_ context.Context, _ *config.Config, _ logger.Interface, opts credentials.Options) (*credentials.Report, error) {
+		require.Equal(t, credentials.Options{Tenants: []string{"a"}, BatchSize: 50}, opts)
+
+		return &credentials.Report{
+			Tenants: []string{"", "a"},
+			Kinds:   []credentials.KindReport{{Kind: credentials.KindDevices, Moved: 2, Stored: 1}},
+		}, nil
+	}
+
+	var stdout bytes.Buffer
+
+	require.Equal(t, 0, runCommand(&config.Config{}, logger.New("error"), "migrate-credentials", []string{"-tenants", "a", "-batch-size", "50"}, &stdout))
+	require.Equal(t, "tenants: [\"\" \"a\"]\n"+
+		"                        moved   stored\n"+
+		"devices                     2        1\n", stdout.String())
+
+	require.Equal(t, 1, runCommand(&config.Config{}, logger.New("error"), "migrate-credentials", []string{"-bogus"}, &stdout))
+}
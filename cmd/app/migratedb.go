@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/app"
+	"github.com/device-management-toolkit/console/internal/usecase/migrate"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// targetURLEnv holds the target database URL when -to-url is not given, so
+// that its credentials do not show in the process list.
+const targetURLEnv = "TARGET_DB_URL"
+
+// checksumWidth is how much of each checksum is printed.
+const checksumWidth = 16
+
+// Function pointer for better testability.
+var migrateDatabaseFunc = app.MigrateDatabase
+
+func runMigrateDB(ctx context.Context, cfg *config.Config, l logger.Interface, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("migrate-db", flag.ContinueOnError)
+	to := fs.String("to", "", "provider to migrate to: postgres, mongo or sqlite")
+	toURL := fs.String("to-url", "", "URL of the target database (default $"+targetURLEnv+")")
+	tenants := fs.String("tenants", "", "comma separated tenants to migrate besides the default one and those in the audit trail")
+	dryRun := fs.Bool("dry-run", false, "read the source and check the target without writing data")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *to == "" {
+		return fmt.Errorf("%w: -to", errFlagRequired)
+	}
+
+	if *toURL == "" {
+		*toURL = os.Getenv(targetURLEnv)
+	}
+
+	opts := migrate.Options{DryRun: *dryRun}
+
+	for _, tenant := range strings.Split(*tenants, ",") {
+		if tenant = strings.TrimSpace(tenant); tenant != "" {
+			opts.Tenants = append(opts.Tenants, tenant)
+		}
+	}
+
+	report, err := migrateDatabaseFunc(ctx, cfg, l, *to, *toURL, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "tenants: %q\n", report.Tenants)
+
+	for _, k := range report.Kinds {
+		fmt.Fprintf(stdout, "%-20s %6d  %s\n", k.Kind, k.Count, k.Checksum[:checksumWidth])
+	}
+
+	if report.DryRun {
+		fmt.Fprintln(stdout, "dry run: nothing was written")
+	} else {
+		fmt.Fprintf(stdout, "verified: counts and checksums match; set db.provider to %q to switch over\n", *to)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/usecase/migrate"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func TestRunMigrateDB(t *testing.T) { //nolint:paralleltest // cannot have simultaneous tests modifying env variables and function pointers.
+	t.Setenv(targetURLEnv, "postgres://console@db/console")
+
+	migrateDatabaseFunc = func(_ context.Context, _ *config.Config, _ logger.Interface, provider, url string, opts migrate.Options) (*migrate.Report, error) {
+		require.Equal(t, "postgres", provider)
+		require.Equal(t, "postgres://console@db/console", url)
+		require.Equal(t, migrate.Options{Tenants: []string{"a", "b"}, DryRun: true}, opts)
+
+		return &migrate.Report{
+			Tenants: []string{"", "a", "b"},
+			DryRun:  true,
+			Kinds:   []migrate.KindReport{{Kind: migrate.KindProfiles, Count: 3, Checksum: "0123456789abcdef0123456789abcdef"}},
+		}, nil
+	}
+
+	var stdout bytes.Buffer
+
+	l := logger.New("error")
+
+	require.Equal(t, 0, runCommand(&config.Config{}, l, "migrate-db", []string{"-to", "postgres", "-tenants", "a, b", "-dry-run"}, &stdout))
+	require.Equal(t, "tenants: [\"\" \"a\" \"b\"]\n"+
+		"profiles                  3  0123456789abcdef\n"+
+		"dry run: nothing was written\n", stdout.String())
+
+	require.Equal(t, 1, runCommand(&config.Config{}, l, "migrate-db", nil, &stdout))
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/app"
+	"github.com/device-management-toolkit/console/internal/usecase/keyrotation"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var errKeyNotStored = errors.New("the encryption key is not in the secret store or local keyring; " +
+	"set APP_PREVIOUS_ENCRYPTION_KEY to the current key and APP_ENCRYPTION_KEY to a new one to rotate it")
+
+// Function pointers for better testability.
+var (
+	rotateKeyFunc = app.RotateKey
+	keyStoresFunc = keyStores
+)
+
+// keyStores lists the stores holding the encryption key: the secret store,
+// if configured, and the local keyring.
+func keyStores(cfg *config.Config) []security.Storager {
+	candidates := []security.Storager{security.NewKeyRingStorage("device-management-toolkit")}
+
+	if remoteStorage, err := handleSecretsConfig(cfg); err == nil {
+		candidates = append([]security.Storager{remoteStorage}, candidates...)
+	}
+
+	stores := make([]security.Storager, 0, len(candidates))
+
+	for _, store := range candidates {
+		if key, err := store.GetKeyValue(encryptionKeyName); err == nil && key == cfg.EncryptionKey {
+			stores = append(stores, store)
+		}
+	}
+
+	return stores
+}
+
+// runRotateKey rotates the encryption key in two steps. The first stores a
+// new key, keeping the current one as the previous key, and exits: every
+// console must restart to load both before any secret is re-encrypted, or
+// one still on the old key alone could not read the rotated secrets. Run
+// again, with both keys loaded, it re-encrypts the stored secrets, resuming
+// a rotation interrupted before. Once every secret is rotated the previous
+// key is dropped.
+func runRotateKey(ctx context.Context, cfg *config.Config, l logger.Interface, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("rotate-key", flag.ContinueOnError)
+	batchSize := fs.Int("batch-size", keyrotation.DefaultBatchSize, "entities to read per query")
+	tenants := fs.String("tenants", "", "comma separated tenants to rotate besides the default one and those that own entities")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := keyrotation.Options{BatchSize: *batchSize}
+
+	for _, tenant := range strings.Split(*tenants, ",") {
+		if tenant = strings.TrimSpace(tenant); tenant != "" {
+			opts.Tenants = append(opts.Tenants, tenant)
+		}
+	}
+
+	stores := keyStoresFunc(cfg)
+	previousKey, key := cfg.PreviousEncryptionKey, cfg.EncryptionKey
+
+	if previousKey == "" || previousKey == key {
+		if len(stores) == 0 {
+			return errKeyNotStored
+		}
+
+		previousKey, key = key, security.Crypto{}.GenerateKey()
+
+		// The previous key first, so that the current one is never lost.
+		for _, store := range stores {
+			if err := store.SetKeyValue(previousEncryptionKeyName, previousKey); err != nil {
+				return err
+			}
+
+			if err := store.SetKeyValue(encryptionKeyName, key); err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintln(stdout, "new encryption key staged with the previous one; restart every console, then run rotate-key again to re-encrypt the secrets")
+
+		return nil
+	}
+
+	fmt.Fprintln(stdout, "re-encrypting the secrets with the new key")
+
+	report, err := rotateKeyFunc(ctx, cfg, l, previousKey, key, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "tenants: %q\n", report.Tenants)
+	fmt.Fprintf(stdout, "%-20s %8s %8s %10s\n", "", "rotated", "current", "unreadable")
+
+	for _, k := range report.Kinds {
+		fmt.Fprintf(stdout, "%-20s %8d %8d %10d\n", k.Kind, k.Rotated, k.Current, k.Unreadable)
+	}
+
+	if report.Trashed > 0 {
+		fmt.Fprintf(stdout, "%d deleted entities in the trash keep the previous key; restore or purge them and run rotate-key again\n", report.Trashed)
+
+		return nil
+	}
+
+	if !report.Complete {
+		fmt.Fprintln(stdout, "secrets were written with the previous key meanwhile; make sure every console was restarted and run rotate-key again")
+
+		return nil
+	}
+
+	for _, store := range stores {
+		if err := store.DeleteKeyValue(previousEncryptionKeyName); err != nil && !errors.Is(err, security.ErrKeyNotFound) {
+			return err
+		}
+	}
+
+	if len(stores) == 0 {
+		fmt.Fprintln(stdout, "rotation complete: unset APP_PREVIOUS_ENCRYPTION_KEY and restart the consoles")
+
+		return nil
+	}
+
+	fmt.Fprintln(stdout, "rotation complete: the previous key was dropped; restart the consoles")
+
+	return nil
+}
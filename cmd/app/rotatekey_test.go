@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/usecase/keyrotation"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// memoryStore is a security.Storager in memory.
+type memoryStore map[string]string
+
+func (m memoryStore) GetKeyValue(key string) (string, error) {
+	if value, ok := m[key]; ok {
+		return value, nil
+	}
+
+	return "", security.ErrKeyNotFound
+}
+
+func (m memoryStore) SetKeyValue(key, value string) error {
+	m[key] = value
+
+	return nil
+}
+
+func (m memoryStore) DeleteKeyValue(key string) error {
+	delete(m, key)
+
+	return nil
+}
+
+func TestRunRotateKey(t *testing.T) { //nolint:paralleltest // cannot have simultaneous tests modifying env variables and function pointers.
+	store := memoryStore{encryptionKeyName: "old"}
+	keyStoresFunc = func(_ *config.Config) []security.Storager { return []security.Storager{store} }
+
+	rotated := 0
+	complete := false
+	rotateKeyFunc = func(_ context.Context, _ *config.Config, _ logger.Interface, previousKey, key string, opts keyrotation.Options) (*keyrotation.Report, error) {
+		require.Equal(t, "old", previousKey)
+		require.Equal(t, store[encryptionKeyName], key)
+		require.Equal(t, keyrotation.Options{Tenants: []string{"a"}, BatchSize: 10}, opts)
+
+		rotated++
+
+		return &keyrotation.Report{
+			Tenants:  []string{"", "a"},
+			Kinds:    []keyrotation.KindReport{{Kind: keyrotation.KindDevices, Rotated: 3, Current: 1}},
+			Complete: complete,
+		}, nil
+	}
+
+	var stdout bytes.Buffer
+
+	l := logger.New("error")
+	cfg := &config.Config{}
+	cfg.EncryptionKey = "old"
+
+	// The first run only stages the new key.
+	require.Equal(t, 0, runCommand(cfg, l, "rotate-key", []string{"-batch-size", "10", "-tenants", "a"}, &stdout))
+	require.NotEqual(t, "old", store[encryptionKeyName])
+	require.Equal(t, "old", store[previousEncryptionKeyName])
+	require.Contains(t, stdout.String(), "new encryption key staged")
+	require.Zero(t, rotated, "nothing is re-encrypted before the consoles restart")
+
+	// The restarted console loads both keys and rotates.
+	cfg.EncryptionKey, cfg.PreviousEncryptionKey = store[encryptionKeyName], "old"
+
+	stdout.Reset()
+	require.Equal(t, 0, runCommand(cfg, l, "rotate-key", []string{"-batch-size", "10", "-tenants", "a"}, &stdout))
+	require.Equal(t, 1, rotated)
+	require.Contains(t, stdout.String(), "devices                     3        1          0\n")
+	require.Contains(t, stdout.String(), "run rotate-key again")
+	require.Equal(t, "old", store[previousEncryptionKeyName], "kept until the rotation completes")
+
+	complete = true
+
+	stdout.Reset()
+	require.Equal(t, 0, runCommand(cfg, l, "rotate-key", []string{"-batch-size", "10", "-tenants", "a"}, &stdout))
+	require.Contains(t, stdout.String(), "rotation complete")
+	require.NotContains(t, store, previousEncryptionKeyName)
+
+	// A key from the environment is not in any store.
+	keyStoresFunc = func(_ *config.Config) []security.Storager { return nil }
+
+	require.Equal(t, 1, runCommand(&config.Config{}, l, "rotate-key", nil, &stdout))
+}
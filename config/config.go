@@ -7,7 +7,9 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
@@ -22,6 +24,13 @@ var TrayMode bool
 var (
 	ErrJWTExpirationInvalid            = errors.New("config: auth.jwtExpiration must be at least 1 minute (e.g. 24h) — very short expirations render tokens unusable")
 	ErrRedirectionJWTExpirationInvalid = errors.New("config: auth.redirectionJWTExpiration must be at least 1 minute (e.g. 5m) — very short expirations render redirection tokens unusable")
+	ErrRefreshTokenExpirationInvalid   = errors.New("config: auth.refreshTokenExpiration must be zero (off) or at least auth.jwtExpiration — a refresh token must outlive the access token it renews")
+	ErrLockoutDurationInvalid          = errors.New("config: auth.lockout.duration must be at least 1 second when auth.lockout.maxAttempts or maxAttemptsPerIp is set")
+	ErrCompressionLevelInvalid         = errors.New("config: redirection compression_level must be between 0 (off) and 9")
+	ErrLDAPConfigInvalid               = errors.New("config: auth.ldap needs a baseDn, a userFilter with one %s for the username and, with a groupBaseDn, a groupFilter with one %s for the user's DN")
+	ErrLDAPInsecure                    = errors.New("config: auth.ldap needs an ldaps:// url or startTls — passwords must not cross the network in the clear")
+	ErrClientCertsInvalid              = errors.New("config: http.tls.clientCerts needs http.tls.enabled and a caFile (also when a crlFile is set)")
+	ErrClientCertIdentityInvalid       = errors.New("config: each http.tls.clientCerts identity needs a username and a match of subject:, cn:, dns:, email:, uri: or ip:")
 )
 
 const defaultHost = "localhost"
@@ -49,17 +58,30 @@ type (
 		EA      `yaml:"ea"`
 		Auth    `yaml:"auth"`
 		UI      `yaml:"ui"`
+
+		// Named rather than embedded so their fields stay scoped (SSH.Port
+		// would otherwise collide with HTTP.Port).
+		Redirection Redirection `yaml:"redirection"`
+		SSH         SSH         `yaml:"ssh"`
 	}
 
 	// App -.
 	App struct {
-		Name                 string `env-required:"true" yaml:"name" env:"APP_NAME"`
-		Repo                 string `env-required:"true" yaml:"repo" env:"APP_REPO"`
-		Version              string `env-required:"true"`
-		CommonName           string `env-required:"true" yaml:"common_name" env:"APP_COMMON_NAME"`
-		EncryptionKey        string `yaml:"encryption_key" env:"APP_ENCRYPTION_KEY"`
-		AllowInsecureCiphers bool   `yaml:"allow_insecure_ciphers" env:"APP_ALLOW_INSECURE_CIPHERS"`
-		DisableCIRA          bool   `yaml:"disable_cira" env:"APP_DISABLE_CIRA"`
+		Name          string `env-required:"true" yaml:"name" env:"APP_NAME"`
+		Repo          string `env-required:"true" yaml:"repo" env:"APP_REPO"`
+		Version       string `env-required:"true"`
+		CommonName    string `env-required:"true" yaml:"common_name" env:"APP_COMMON_NAME"`
+		EncryptionKey string `yaml:"encryption_key" env:"APP_ENCRYPTION_KEY"`
+		// PreviousEncryptionKey still decrypts secrets while a key rotation
+		// is in progress.
+		PreviousEncryptionKey string `yaml:"previous_encryption_key" env:"APP_PREVIOUS_ENCRYPTION_KEY"`
+		// AuditKey signs the audit trail. To rotate it, add the current key to
+		// PreviousAuditKeys and set a new one: entries name the key that
+		// signed them, so older ones verify as long as their key is listed.
+		AuditKey             string   `yaml:"audit_key" env:"APP_AUDIT_KEY"`
+		PreviousAuditKeys    []string `yaml:"previous_audit_keys" env:"APP_PREVIOUS_AUDIT_KEYS"`
+		AllowInsecureCiphers bool     `yaml:"allow_insecure_ciphers" env:"APP_ALLOW_INSECURE_CIPHERS"`
+		DisableCIRA          bool     `yaml:"disable_cira" env:"APP_DISABLE_CIRA"`
 	}
 
 	// HTTP -.
@@ -69,7 +91,6 @@ type (
 		AllowedOrigins   []string `env-required:"true" yaml:"allowed_origins" env:"HTTP_ALLOWED_ORIGINS"`
 		AllowedHeaders   []string `env-required:"true" yaml:"allowed_headers" env:"HTTP_ALLOWED_HEADERS"`
 		AllowCredentials bool     `yaml:"allow_credentials" env:"HTTP_ALLOW_CREDENTIALS"`
-		WSCompression    bool     `yaml:"ws_compression" env:"WS_COMPRESSION"`
 		TLS              TLS      `yaml:"tls"`
 	}
 
@@ -78,6 +99,30 @@ type (
 		Enabled  bool   `yaml:"enabled" env:"HTTP_TLS_ENABLED"`
 		CertFile string `yaml:"certFile" env:"HTTP_TLS_CERT_FILE"`
 		KeyFile  string `yaml:"keyFile" env:"HTTP_TLS_KEY_FILE"`
+		// ClientCerts lets API clients authenticate with an X.509 client
+		// certificate instead of a bearer token.
+		ClientCerts ClientCerts `yaml:"clientCerts"`
+	}
+
+	// ClientCerts -. Certificates must chain to caFile and, when crlFile is
+	// set, not be revoked by it. The file is re-read when it changes; a CRL
+	// past its next update refuses its CA's certificates until replaced.
+	// Identities map a verified certificate to a console user; the first
+	// match wins, and a certificate matching none is refused.
+	ClientCerts struct {
+		CAFile     string               `yaml:"caFile" env:"HTTP_TLS_CLIENT_CA_FILE"`
+		CRLFile    string               `yaml:"crlFile" env:"HTTP_TLS_CLIENT_CRL_FILE"`
+		Identities []ClientCertIdentity `yaml:"identities"`
+	}
+
+	// ClientCertIdentity -. Match is "subject:<DN>", "cn:<common name>" or a
+	// SAN entry: "dns:", "email:", "uri:" or "ip:". An empty tenantId is the
+	// default tenant.
+	ClientCertIdentity struct {
+		Match    string `yaml:"match"`
+		Username string `yaml:"username"`
+		Role     string `yaml:"role"`
+		TenantID string `yaml:"tenantId"`
 	}
 
 	// Log -.
@@ -90,16 +135,25 @@ type (
 		Address string `yaml:"address" env:"SECRETS_ADDR"`
 		Token   string `yaml:"token" env:"SECRETS_TOKEN"`
 		Path    string `yaml:"path" env:"SECRETS_PATH"`
+		// StoreCredentials keeps device and profile passwords in the secret
+		// store, with the database only referencing them.
+		StoreCredentials bool `yaml:"store_credentials" env:"SECRETS_STORE_CREDENTIALS"`
 	}
 
 	// DB -.
 	//
 	// Provider selects the backend: "postgres", "sqlite" (default), or "mongo".
 	// See internal/app/repos.go for the per-provider rules around DB_URL.
+	// QueryTimeout bounds every repository call, on top of the request's own
+	// deadline; zero disables it. Deleted devices and configurations stay in
+	// the trash for TrashRetention before they are purged; zero keeps them
+	// until purged by hand. Adding one under a trashed one's name purges it.
 	DB struct {
-		Provider string `yaml:"provider" env:"DB_PROVIDER"`
-		PoolMax  int    `env-required:"true" yaml:"pool_max" env:"DB_POOL_MAX"`
-		URL      string `env:"DB_URL"`
+		Provider       string        `yaml:"provider" env:"DB_PROVIDER"`
+		PoolMax        int           `env-required:"true" yaml:"pool_max" env:"DB_POOL_MAX"`
+		URL            string        `env:"DB_URL"`
+		QueryTimeout   time.Duration `yaml:"query_timeout" env:"DB_QUERY_TIMEOUT"`
+		TrashRetention time.Duration `yaml:"trash_retention" env:"DB_TRASH_RETENTION"`
 	}
 
 	// EA -.
@@ -130,6 +184,85 @@ type (
 		CookieSecure             bool          `yaml:"cookieSecure" env:"AUTH_COOKIE_SECURE"`
 		CookieSameSite           string        `yaml:"cookieSameSite" env:"AUTH_COOKIE_SAME_SITE"`
 		UI                       UIAuthConfig  `yaml:"ui"`
+
+		// RefreshTokenExpiration is how long a refresh token stays usable.
+		// Each use replaces it with a new one; zero issues none.
+		RefreshTokenExpiration time.Duration `yaml:"refreshTokenExpiration" env:"AUTH_REFRESH_TOKEN_EXPIRATION"`
+
+		// Role-based access control. Roles come from RolesClaim, or from
+		// GroupsClaim via GroupRoles (group name to role); IdP tokens carrying
+		// neither get DefaultRole, and an empty DefaultRole denies them.
+		// Tokens the console signs always carry a role and never get it.
+		RolesClaim  string            `yaml:"rolesClaim" env:"AUTH_ROLES_CLAIM"`
+		GroupsClaim string            `yaml:"groupsClaim" env:"AUTH_GROUPS_CLAIM"`
+		GroupRoles  map[string]string `yaml:"groupRoles" env:"AUTH_GROUP_ROLES"`
+		DefaultRole string            `yaml:"defaultRole" env:"AUTH_DEFAULT_ROLE"`
+
+		// Multi-tenancy. Requests act in the tenant named by TenantClaim;
+		// tokens without it act in the default tenant unless RequireTenant is
+		// set, which denies them. Super-admins may name another tenant in the
+		// X-Tenant-ID header.
+		TenantClaim   string `yaml:"tenantClaim" env:"AUTH_TENANT_CLAIM"`
+		RequireTenant bool   `yaml:"requireTenant" env:"AUTH_REQUIRE_TENANT"`
+
+		// PasswordPolicy applies to local user accounts, not the built-in admin.
+		PasswordPolicy PasswordPolicy `yaml:"passwordPolicy"`
+
+		// Lockout throttles password guessing at login, for local users and
+		// the built-in admin alike.
+		Lockout LoginLockout `yaml:"lockout"`
+
+		// LDAP authenticates logins against a directory such as Active
+		// Directory when they match no local account.
+		LDAP LDAP `yaml:"ldap"`
+	}
+
+	// LDAP -. Logins are checked by binding as the user found by searching
+	// BaseDN with UserFilter, where %s is the escaped username. The search
+	// binds as BindDN, or anonymously when it is empty. An empty URL turns
+	// LDAP off. The user is known by their UsernameAttribute, in lower case,
+	// whatever case or alias they logged in with.
+	//
+	// Roles come from the user's groups: those in GroupAttribute of their
+	// entry and, with GroupBaseDN, the entries GroupFilter finds there (%s is
+	// the user's DN). GroupRoles maps a group's DN or CN to a role; the most
+	// privileged wins. Users in no mapped group get DefaultRole, and an empty
+	// DefaultRole denies them.
+	LDAP struct {
+		URL               string            `yaml:"url" env:"AUTH_LDAP_URL"`
+		StartTLS          bool              `yaml:"startTls" env:"AUTH_LDAP_START_TLS"`
+		TLSSkipVerify     bool              `yaml:"tlsSkipVerify" env:"AUTH_LDAP_TLS_SKIP_VERIFY"`
+		CACertFile        string            `yaml:"caCertFile" env:"AUTH_LDAP_CA_CERT_FILE"`
+		Timeout           time.Duration     `yaml:"timeout" env:"AUTH_LDAP_TIMEOUT"`
+		BindDN            string            `yaml:"bindDn" env:"AUTH_LDAP_BIND_DN"`
+		BindPassword      string            `yaml:"bindPassword" env:"AUTH_LDAP_BIND_PASSWORD"`
+		BaseDN            string            `yaml:"baseDn" env:"AUTH_LDAP_BASE_DN"`
+		UserFilter        string            `yaml:"userFilter" env:"AUTH_LDAP_USER_FILTER"`
+		UsernameAttribute string            `yaml:"usernameAttribute" env:"AUTH_LDAP_USERNAME_ATTRIBUTE"`
+		GroupAttribute    string            `yaml:"groupAttribute" env:"AUTH_LDAP_GROUP_ATTRIBUTE"`
+		GroupBaseDN       string            `yaml:"groupBaseDn" env:"AUTH_LDAP_GROUP_BASE_DN"`
+		GroupFilter       string            `yaml:"groupFilter" env:"AUTH_LDAP_GROUP_FILTER"`
+		GroupRoles        map[string]string `yaml:"groupRoles" env:"AUTH_LDAP_GROUP_ROLES"`
+		DefaultRole       string            `yaml:"defaultRole" env:"AUTH_LDAP_DEFAULT_ROLE"`
+	}
+
+	// LoginLockout -. Failed logins are counted per username and per client
+	// IP, and forgotten Duration after the last one. Each failure makes the
+	// username wait Delay, doubled per failure, before its next attempt. At
+	// MaxAttempts failures the username, or at MaxAttemptsPerIP the client
+	// IP, is locked out for Duration. A zero maximum turns that count off.
+	LoginLockout struct {
+		MaxAttempts      int           `yaml:"maxAttempts" env:"AUTH_LOCKOUT_MAX_ATTEMPTS"`
+		MaxAttemptsPerIP int           `yaml:"maxAttemptsPerIp" env:"AUTH_LOCKOUT_MAX_ATTEMPTS_PER_IP"`
+		Delay            time.Duration `yaml:"delay" env:"AUTH_LOCKOUT_DELAY"`
+		Duration         time.Duration `yaml:"duration" env:"AUTH_LOCKOUT_DURATION"`
+	}
+
+	// PasswordPolicy -. MinCharacterClasses counts lower case, upper case,
+	// digits and symbols.
+	PasswordPolicy struct {
+		MinLength           int `yaml:"minLength" env:"AUTH_PASSWORD_MIN_LENGTH"`
+		MinCharacterClasses int `yaml:"minCharacterClasses" env:"AUTH_PASSWORD_MIN_CHARACTER_CLASSES"`
 	}
 
 	// UIAuthConfig -.
@@ -143,6 +276,50 @@ type (
 		StrictDiscoveryDocumentValidation bool   `yaml:"strictDiscoveryDocumentValidation" env:"AUTH_UI_STRICT_DISCOVERY"`
 	}
 
+	// Redirection -.
+	//
+	// Per-mode limits for KVM, SOL and IDER sessions so a forgotten tab cannot
+	// hold the device's redirection slot. Zero disables a limit. The browser is
+	// warned WarningPeriod before either limit ends the session.
+	//
+	// BandwidthLimit caps the combined throughput of all sessions in bytes per
+	// second; each mode's BandwidthLimit caps a single session. Zero is
+	// unlimited.
+	Redirection struct {
+		KVM            RedirectionPolicy `yaml:"kvm" env-prefix:"REDIRECTION_KVM_"`
+		SOL            RedirectionPolicy `yaml:"sol" env-prefix:"REDIRECTION_SOL_"`
+		IDER           RedirectionPolicy `yaml:"ider" env-prefix:"REDIRECTION_IDER_"`
+		WarningPeriod  time.Duration     `yaml:"warning_period" env:"REDIRECTION_WARNING_PERIOD"`
+		BandwidthLimit int               `yaml:"bandwidth_limit" env:"REDIRECTION_BANDWIDTH_LIMIT"`
+	}
+
+	// RedirectionPolicy -.
+	//
+	// CompressionLevel is the websocket (permessage-deflate) level for the
+	// mode: 0 disables compression, 1 (fastest) to 9 (smallest).
+	RedirectionPolicy struct {
+		IdleTimeout      time.Duration `yaml:"idle_timeout" env:"IDLE_TIMEOUT"`
+		MaxDuration      time.Duration `yaml:"max_duration" env:"MAX_DURATION"`
+		BandwidthLimit   int           `yaml:"bandwidth_limit" env:"BANDWIDTH_LIMIT"`
+		CompressionLevel int           `yaml:"compression_level" env:"COMPRESSION_LEVEL"`
+	}
+
+	// SSH -.
+	//
+	// SSH gateway for Serial-over-LAN: `ssh <guid>@console` opens the device's
+	// serial console. Operators authenticate with the admin credentials or a key
+	// listed in AuthorizedKeysFile. An empty HostKeyFile uses a key generated at
+	// startup; a missing file is created. SessionLogDir, when set, receives one
+	// transcript per session.
+	SSH struct {
+		Enabled            bool   `yaml:"enabled" env:"SSH_ENABLED"`
+		Host               string `yaml:"host" env:"SSH_HOST"`
+		Port               string `yaml:"port" env:"SSH_PORT"`
+		HostKeyFile        string `yaml:"host_key_file" env:"SSH_HOST_KEY_FILE"`
+		AuthorizedKeysFile string `yaml:"authorized_keys_file" env:"SSH_AUTHORIZED_KEYS_FILE"`
+		SessionLogDir      string `yaml:"session_log_dir" env:"SSH_SESSION_LOG_DIR"`
+	}
+
 	// UI -.
 	UI struct {
 		ExternalURL string `yaml:"externalUrl" env:"UI_EXTERNAL_URL"`
@@ -155,6 +332,27 @@ func (a Auth) CookieAuthEnabled() bool {
 	return a.CookieEnabled && a.ClientID == ""
 }
 
+// Mode returns the settings for a redirection mode ("kvm", "sol" or "ider").
+// Unknown modes get no limits and no compression.
+func (r Redirection) Mode(mode string) RedirectionPolicy {
+	switch mode {
+	case "kvm":
+		return r.KVM
+	case "sol":
+		return r.SOL
+	case "ider":
+		return r.IDER
+	default:
+		return RedirectionPolicy{}
+	}
+}
+
+// CompressionEnabled reports whether any mode uses websocket compression, so
+// the upgrader should negotiate it.
+func (r Redirection) CompressionEnabled() bool {
+	return r.KVM.CompressionLevel > 0 || r.SOL.CompressionLevel > 0 || r.IDER.CompressionLevel > 0
+}
+
 // getPreferredIPAddress detects the most likely candidate IP address for this machine.
 // It prefers non-loopback IPv4 addresses and excludes link-local addresses.
 func getPreferredIPAddress() string {
@@ -181,13 +379,15 @@ func getPreferredIPAddress() string {
 func defaultConfig() *Config {
 	return &Config{
 		App: App{
-			Name:                 "console",
-			Repo:                 "device-management-toolkit/console",
-			Version:              "DEVELOPMENT",
-			CommonName:           getPreferredIPAddress(),
-			EncryptionKey:        "",
-			AllowInsecureCiphers: false,
-			DisableCIRA:          true,
+			Name:                  "console",
+			Repo:                  "device-management-toolkit/console",
+			Version:               "DEVELOPMENT",
+			CommonName:            getPreferredIPAddress(),
+			EncryptionKey:         "",
+			PreviousEncryptionKey: "",
+			AuditKey:              "",
+			AllowInsecureCiphers:  false,
+			DisableCIRA:           true,
 		},
 		HTTP: HTTP{
 			Host:             "",
@@ -195,7 +395,6 @@ func defaultConfig() *Config {
 			AllowedOrigins:   []string{"*"},
 			AllowedHeaders:   []string{"*"},
 			AllowCredentials: false,
-			WSCompression:    true,
 			TLS: TLS{
 				Enabled:  true,
 				CertFile: "",
@@ -209,11 +408,15 @@ func defaultConfig() *Config {
 			Address: "http://localhost:8200",
 			Token:   "",
 			Path:    "secret/data/console",
+
+			StoreCredentials: false,
 		},
 		DB: DB{
-			Provider: "sqlite",
-			PoolMax:  2,
-			URL:      "",
+			Provider:       "sqlite",
+			PoolMax:        2,
+			URL:            "",
+			QueryTimeout:   30 * time.Second,
+			TrashRetention: 30 * 24 * time.Hour,
 		},
 		EA: EA{
 			URL:      "http://localhost:8000",
@@ -226,6 +429,7 @@ func defaultConfig() *Config {
 			JWTKey:                   "your_secret_jwt_key",
 			JWTExpiration:            24 * time.Hour,
 			RedirectionJWTExpiration: 5 * time.Minute,
+			RefreshTokenExpiration:   7 * 24 * time.Hour,
 			CookieEnabled:            true,
 			CookieName:               DefaultSessionCookieName,
 			CookieSecure:             true,
@@ -242,10 +446,49 @@ func defaultConfig() *Config {
 				RequireHTTPS:                      false,
 				StrictDiscoveryDocumentValidation: true,
 			},
+			RolesClaim:  "roles",
+			GroupsClaim: "groups",
+			// The least privileged role: an IdP token naming no role may
+			// look, but not act. Raise it only while roles or group
+			// mappings are being set up.
+			DefaultRole: "read-only",
+			TenantClaim: "tenantId",
+			PasswordPolicy: PasswordPolicy{
+				MinLength:           12,
+				MinCharacterClasses: 3,
+			},
+			Lockout: LoginLockout{
+				MaxAttempts:      5,
+				MaxAttemptsPerIP: 20,
+				Delay:            time.Second,
+				Duration:         15 * time.Minute,
+			},
+			LDAP: LDAP{
+				Timeout:           10 * time.Second,
+				UserFilter:        "(uid=%s)",
+				UsernameAttribute: "uid",
+				GroupAttribute:    "memberOf",
+				GroupFilter:       "(member=%s)",
+			},
 		},
 		UI: UI{
 			ExternalURL: "",
 		},
+		Redirection: Redirection{
+			KVM:            RedirectionPolicy{IdleTimeout: 15 * time.Minute, CompressionLevel: 1},
+			SOL:            RedirectionPolicy{IdleTimeout: 15 * time.Minute, CompressionLevel: 1},
+			IDER:           RedirectionPolicy{CompressionLevel: 1},
+			WarningPeriod:  time.Minute,
+			BandwidthLimit: 0,
+		},
+		SSH: SSH{
+			Enabled:            false,
+			Host:               "",
+			Port:               "2222",
+			HostKeyFile:        "",
+			AuthorizedKeysFile: "",
+			SessionLogDir:      "",
+		},
 	}
 }
 
@@ -417,6 +660,84 @@ func (c *Config) validate() error {
 		return ErrRedirectionJWTExpirationInvalid
 	}
 
+	if c.RefreshTokenExpiration != 0 && c.RefreshTokenExpiration < c.JWTExpiration {
+		return ErrRefreshTokenExpirationInvalid
+	}
+
+	if (c.Lockout.MaxAttempts > 0 || c.Lockout.MaxAttemptsPerIP > 0) && c.Lockout.Duration < time.Second {
+		return ErrLockoutDurationInvalid
+	}
+
+	if err := c.LDAP.validate(); err != nil {
+		return err
+	}
+
+	if err := c.TLS.ClientCerts.validate(c.TLS.Enabled); err != nil {
+		return err
+	}
+
+	for _, policy := range []RedirectionPolicy{c.Redirection.KVM, c.Redirection.SOL, c.Redirection.IDER} {
+		if policy.CompressionLevel < 0 || policy.CompressionLevel > 9 {
+			return ErrCompressionLevelInvalid
+		}
+	}
+
+	return nil
+}
+
+// clientCertMatchKinds are the prefixes of ClientCertIdentity.Match.
+var clientCertMatchKinds = []string{"subject", "cn", "dns", "email", "uri", "ip"}
+
+// Enabled reports whether API clients may authenticate by certificate.
+func (cc ClientCerts) Enabled() bool {
+	return cc.CAFile != ""
+}
+
+func (cc ClientCerts) validate(tlsEnabled bool) error {
+	if !cc.Enabled() {
+		if cc.CRLFile != "" || len(cc.Identities) > 0 {
+			return ErrClientCertsInvalid
+		}
+
+		return nil
+	}
+
+	if !tlsEnabled {
+		return ErrClientCertsInvalid
+	}
+
+	for _, id := range cc.Identities {
+		kind, value, ok := strings.Cut(id.Match, ":")
+		if !ok || value == "" || id.Username == "" || !slices.Contains(clientCertMatchKinds, strings.ToLower(kind)) {
+			return ErrClientCertIdentityInvalid
+		}
+	}
+
+	return nil
+}
+
+// Enabled reports whether logins may be checked against the directory.
+func (l LDAP) Enabled() bool {
+	return l.URL != ""
+}
+
+func (l LDAP) validate() error {
+	if !l.Enabled() {
+		return nil
+	}
+
+	if l.BaseDN == "" || strings.Count(l.UserFilter, "%s") != 1 {
+		return ErrLDAPConfigInvalid
+	}
+
+	if l.GroupBaseDN != "" && strings.Count(l.GroupFilter, "%s") != 1 {
+		return ErrLDAPConfigInvalid
+	}
+
+	if !strings.HasPrefix(strings.ToLower(l.URL), "ldaps://") && !l.StartTLS {
+		return ErrLDAPInsecure
+	}
+
 	return nil
 }
 
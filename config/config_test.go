@@ -477,6 +477,33 @@ func TestValidate_SubMinuteRedirectionJWTExpiration(t *testing.T) {
 	require.ErrorIs(t, err, ErrRedirectionJWTExpirationInvalid)
 }
 
+func TestValidate_RefreshTokenExpiration(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	cfg.RefreshTokenExpiration = cfg.JWTExpiration - time.Minute
+
+	require.ErrorIs(t, cfg.validate(), ErrRefreshTokenExpirationInvalid)
+
+	cfg.RefreshTokenExpiration = 0
+
+	require.NoError(t, cfg.validate())
+}
+
+func TestValidate_LockoutDuration(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	cfg.Lockout.Duration = 0
+
+	require.ErrorIs(t, cfg.validate(), ErrLockoutDurationInvalid)
+
+	cfg.Lockout.MaxAttempts = 0
+	cfg.Lockout.MaxAttemptsPerIP = 0
+
+	require.NoError(t, cfg.validate())
+}
+
 func TestValidate_ValidDefaults(t *testing.T) {
 	t.Parallel()
 
@@ -485,3 +512,83 @@ func TestValidate_ValidDefaults(t *testing.T) {
 	err := cfg.validate()
 	require.NoError(t, err)
 }
+
+func TestValidate_CompressionLevelOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	cfg.Redirection.SOL.CompressionLevel = 10
+
+	err := cfg.validate()
+	require.ErrorIs(t, err, ErrCompressionLevelInvalid)
+}
+
+func TestValidate_LDAP(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	cfg.LDAP.URL = "ldaps://ad.example.com"
+
+	require.ErrorIs(t, cfg.validate(), ErrLDAPConfigInvalid, "no base DN")
+
+	cfg.LDAP.BaseDN = "dc=example,dc=com"
+	require.NoError(t, cfg.validate())
+
+	cfg.LDAP.UserFilter = "(sAMAccountName=jdoe)"
+	require.ErrorIs(t, cfg.validate(), ErrLDAPConfigInvalid, "no username placeholder")
+
+	cfg.LDAP.UserFilter = "(sAMAccountName=%s)"
+	cfg.LDAP.GroupBaseDN = "ou=groups,dc=example,dc=com"
+	cfg.LDAP.GroupFilter = "(objectClass=group)"
+	require.ErrorIs(t, cfg.validate(), ErrLDAPConfigInvalid, "no user DN placeholder")
+
+	cfg.LDAP.GroupFilter = "(member=%s)"
+	cfg.LDAP.URL = "ldap://ad.example.com"
+	require.ErrorIs(t, cfg.validate(), ErrLDAPInsecure)
+
+	cfg.LDAP.StartTLS = true
+	require.NoError(t, cfg.validate())
+}
+
+func TestValidate_ClientCerts(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	cfg.TLS.ClientCerts.CRLFile = "crl.pem"
+
+	require.ErrorIs(t, cfg.validate(), ErrClientCertsInvalid, "CRL without a CA")
+
+	cfg.TLS.ClientCerts.CAFile = "ca.pem"
+	require.NoError(t, cfg.validate())
+
+	cfg.TLS.Enabled = false
+	require.ErrorIs(t, cfg.validate(), ErrClientCertsInvalid, "TLS off")
+
+	cfg.TLS.Enabled = true
+	cfg.TLS.ClientCerts.Identities = []ClientCertIdentity{{Match: "dns:ci.example.com", Username: "ci", Role: "operator"}}
+	require.NoError(t, cfg.validate())
+
+	cfg.TLS.ClientCerts.Identities = append(cfg.TLS.ClientCerts.Identities, ClientCertIdentity{Match: "serial:01", Username: "ci"})
+	require.ErrorIs(t, cfg.validate(), ErrClientCertIdentityInvalid, "unknown match kind")
+
+	cfg.TLS.ClientCerts.Identities[1] = ClientCertIdentity{Match: "cn:ci"}
+	require.ErrorIs(t, cfg.validate(), ErrClientCertIdentityInvalid, "no username")
+}
+
+func TestRedirection_Mode(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	cfg.Redirection.KVM.BandwidthLimit = 1024
+	cfg.Redirection.IDER.CompressionLevel = 0
+
+	assert.Equal(t, 1024, cfg.Redirection.Mode("kvm").BandwidthLimit)
+	assert.Equal(t, 1, cfg.Redirection.Mode("sol").CompressionLevel)
+	assert.Equal(t, RedirectionPolicy{}, cfg.Redirection.Mode("unknown"))
+	assert.True(t, cfg.Redirection.CompressionEnabled())
+
+	cfg.Redirection.KVM.CompressionLevel = 0
+	cfg.Redirection.SOL.CompressionLevel = 0
+
+	assert.False(t, cfg.Redirection.CompressionEnabled())
+}
@@ -18,8 +18,10 @@ import (
 	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
 
 	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/clientcert"
 	"github.com/device-management-toolkit/console/internal/controller/httpapi"
 	"github.com/device-management-toolkit/console/internal/controller/tcp/cira"
+	"github.com/device-management-toolkit/console/internal/controller/tcp/sshgateway"
 	wsv1 "github.com/device-management-toolkit/console/internal/controller/ws/v1"
 	"github.com/device-management-toolkit/console/internal/usecase"
 	"github.com/device-management-toolkit/console/pkg/httpserver"
@@ -57,15 +59,30 @@ func Run(cfg *config.Config, log logger.Interface) {
 
 	ciraServer := setupCIRAServer(cfg, log, repos.Closer, usecases)
 
-	httpServer := httpserver.New(
-		handler,
+	sshServer := setupSSHGateway(cfg, log, repos.Closer, usecases)
+
+	stopTrashPurge := startTrashPurge(cfg, log, usecases.Trash)
+	defer stopTrashPurge()
+
+	serverOptions := []httpserver.Option{
 		httpserver.Port(cfg.Host, cfg.Port),
 		httpserver.TLS(cfg.TLS.Enabled, cfg.TLS.CertFile, cfg.TLS.KeyFile),
 		httpserver.Logger(log),
-	)
+	}
+
+	if cfg.TLS.ClientCerts.Enabled() {
+		tlsConfig, err := clientcert.TLSConfig(cfg.TLS.ClientCerts)
+		if err != nil {
+			log.Fatal(fmt.Errorf("app - Run - clientcert.TLSConfig: %w", err))
+		}
+
+		serverOptions = append(serverOptions, httpserver.TLSConfig(tlsConfig))
+	}
 
-	waitForShutdown(log, httpServer, ciraServer)
-	shutdownServers(log, httpServer, ciraServer)
+	httpServer := httpserver.New(handler, serverOptions...)
+
+	waitForShutdown(log, httpServer, ciraServer, sshServer)
+	shutdownServers(log, httpServer, ciraServer, sshServer)
 }
 
 func setupHTTPHandler(cfg *config.Config, log logger.Interface, usecases *usecase.Usecases) *gin.Engine {
@@ -81,6 +98,8 @@ func setupHTTPHandler(cfg *config.Config, log logger.Interface, usecases *usecas
 	defaultConfig := cors.DefaultConfig()
 	defaultConfig.AllowOrigins = cfg.AllowedOrigins
 	defaultConfig.AllowHeaders = cfg.AllowedHeaders
+	// Browsers hide ETag from cross-origin callers, who need it for If-Match.
+	defaultConfig.ExposeHeaders = []string{"ETag"}
 	defaultConfig.AllowCredentials = cfg.AllowCredentials && !slices.Contains(cfg.AllowedOrigins, "*")
 
 	handler.Use(cors.New(defaultConfig))
@@ -97,10 +116,10 @@ func setupHTTPHandler(cfg *config.Config, log logger.Interface, usecases *usecas
 		WriteBufferSize:   64 * 1024,
 		Subprotocols:      []string{"direct"},
 		CheckOrigin:       func(_ *http.Request) bool { return true },
-		EnableCompression: cfg.WSCompression,
+		EnableCompression: cfg.Redirection.CompressionEnabled(),
 	}
 
-	wsv1.RegisterRoutes(handler, log, usecases.Devices, upgrader)
+	wsv1.RegisterRoutes(handler, log, usecases.Devices, usecases.Tokens, upgrader)
 
 	return handler
 }
@@ -132,30 +151,49 @@ func setupCIRAServer(cfg *config.Config, log logger.Interface, closer io.Closer,
 	return ciraServer
 }
 
-func waitForShutdown(log logger.Interface, httpServer *httpserver.Server, ciraServer *cira.Server) {
+func setupSSHGateway(cfg *config.Config, log logger.Interface, closer io.Closer, usecases *usecase.Usecases) *sshgateway.Server {
+	if !cfg.SSH.Enabled {
+		return nil
+	}
+
+	sshServer, err := sshgateway.NewServer(cfg.SSH, cfg.Auth, usecases.Devices, usecases.Users, usecases.Lockouts, usecases.AccessPolicies, log)
+	if err != nil {
+		_ = closer.Close()
+
+		log.Fatal("SSH gateway failed: %v", err)
+	}
+
+	return sshServer
+}
+
+func waitForShutdown(log logger.Interface, httpServer *httpserver.Server, ciraServer *cira.Server, sshServer *sshgateway.Server) {
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
+	// A nil channel never receives, so disabled servers drop out of the select.
+	var ciraNotify, sshNotify <-chan error
+
 	if ciraServer != nil {
-		select {
-		case s := <-interrupt:
-			log.Info("app - Run - signal: " + s.String())
-		case err := <-httpServer.Notify():
-			log.Error(fmt.Errorf("app - Run - httpServer.Notify: %w", err))
-		case ciraErr := <-ciraServer.Notify():
-			log.Error(fmt.Errorf("app - Run - ciraServer.Notify: %w", ciraErr))
-		}
-	} else {
-		select {
-		case s := <-interrupt:
-			log.Info("app - Run - signal: " + s.String())
-		case err := <-httpServer.Notify():
-			log.Error(fmt.Errorf("app - Run - httpServer.Notify: %w", err))
-		}
+		ciraNotify = ciraServer.Notify()
+	}
+
+	if sshServer != nil {
+		sshNotify = sshServer.Notify()
+	}
+
+	select {
+	case s := <-interrupt:
+		log.Info("app - Run - signal: " + s.String())
+	case err := <-httpServer.Notify():
+		log.Error(fmt.Errorf("app - Run - httpServer.Notify: %w", err))
+	case ciraErr := <-ciraNotify:
+		log.Error(fmt.Errorf("app - Run - ciraServer.Notify: %w", ciraErr))
+	case sshErr := <-sshNotify:
+		log.Error(fmt.Errorf("app - Run - sshServer.Notify: %w", sshErr))
 	}
 }
 
-func shutdownServers(log logger.Interface, httpServer *httpserver.Server, ciraServer *cira.Server) {
+func shutdownServers(log logger.Interface, httpServer *httpserver.Server, ciraServer *cira.Server, sshServer *sshgateway.Server) {
 	if err := httpServer.Shutdown(); err != nil {
 		log.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
 	}
@@ -165,4 +203,10 @@ func shutdownServers(log logger.Interface, httpServer *httpserver.Server, ciraSe
 			log.Error(fmt.Errorf("app - Run - ciraServer.Shutdown: %w", err))
 		}
 	}
+
+	if sshServer != nil {
+		if err := sshServer.Shutdown(); err != nil {
+			log.Error(fmt.Errorf("app - Run - sshServer.Shutdown: %w", err))
+		}
+	}
 }
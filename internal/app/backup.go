@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/usecase"
+	"github.com/device-management-toolkit/console/internal/usecase/backup"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// Backup returns an archive of the tenant's configuration, for the backup
+// command. It opens the configured database itself and closes it again.
+func Backup(ctx context.Context, cfg *config.Config, log logger.Interface, req dto.BackupRequest, tenantID string) ([]byte, error) {
+	var archive []byte
+
+	err := withBackup(cfg, log, func(uc backup.Feature) error {
+		var err error
+
+		archive, err = uc.Backup(ctx, req, tenantID)
+
+		return err
+	})
+
+	return archive, err
+}
+
+// Restore restores an archive into the tenant, for the restore command.
+func Restore(ctx context.Context, cfg *config.Config, log logger.Interface, req dto.RestoreRequest, tenantID string) (*dto.RestoreResult, error) {
+	var result *dto.RestoreResult
+
+	err := withBackup(cfg, log, func(uc backup.Feature) error {
+		var err error
+
+		result, err = uc.Restore(ctx, req, tenantID)
+
+		return err
+	})
+
+	return result, err
+}
+
+func withBackup(cfg *config.Config, log logger.Interface, fn func(uc backup.Feature) error) error {
+	repos, err := buildRepos(cfg, log)
+	if err != nil {
+		return fmt.Errorf("app.withBackup: buildRepos: %w", err)
+	}
+
+	defer closeRepos(repos, log)
+
+	return fn(usecase.NewUseCases(repos, log, CertStore).Backup)
+}
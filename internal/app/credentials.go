@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/usecase/credentials"
+	"github.com/device-management-toolkit/console/internal/usecase/keyrotation"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var (
+	errNoSecretStore          = errors.New("no secret store is configured")
+	errStoreCredentialsNotSet = errors.New("set secrets.store_credentials so that the console resolves the references")
+)
+
+// MigrateCredentials moves the device and profile passwords in the configured
+// database to the secret store.
+func MigrateCredentials(ctx context.Context, cfg *config.Config, log logger.Interface, opts credentials.Options) (*credentials.Report, error) {
+	if !cfg.StoreCredentials {
+		return nil, fmt.Errorf("app.MigrateCredentials: %w", errStoreCredentialsNotSet)
+	}
+
+	store, ok := CertStore.(credentials.ObjectStorager)
+	if !ok {
+		return nil, fmt.Errorf("app.MigrateCredentials: %w", errNoSecretStore)
+	}
+
+	repos, err := buildRepos(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("app.MigrateCredentials: %w", err)
+	}
+
+	defer closeRepos(repos, log)
+
+	crypto := keyrotation.NewCryptor(cfg.EncryptionKey, cfg.PreviousEncryptionKey)
+
+	return credentials.NewMigration(credentials.Repositories{
+		Devices:  repos.Devices,
+		Profiles: repos.Profiles,
+		Trash:    repos.Trash,
+		Audit:    repos.Audit,
+	}, store, crypto, log).Run(ctx, opts)
+}
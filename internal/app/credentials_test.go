@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/credentials"
+	"github.com/device-management-toolkit/console/pkg/logger"
+	secrets "github.com/device-management-toolkit/console/pkg/secrets/vault"
+)
+
+// objectStore is a credentials.ObjectStorager in memory.
+type objectStore map[string]map[string]string
+
+func (s objectStore) GetKeyValue(_ string) (string, error) { return "", security.ErrKeyNotFound }
+
+func (s objectStore) SetKeyValue(_, _ string) error { return nil }
+
+func (s objectStore) DeleteKeyValue(key string) error {
+	delete(s, key)
+
+	return nil
+}
+
+func (s objectStore) GetObject(key string) (map[string]string, error) {
+	if object, ok := s[key]; ok {
+		return object, nil
+	}
+
+	return nil, secrets.ErrSecretNotFound
+}
+
+func (s objectStore) SetObject(key string, data map[string]string) error {
+	s[key] = data
+
+	return nil
+}
+
+func TestMigrateCredentials_SQLite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repos := migratedSQLite(t)
+	store := objectStore{}
+	crypto := security.Crypto{EncryptionKey: security.Crypto{}.GenerateKey()}
+	log := logger.New("error")
+
+	seal := func(plainText string) string {
+		cipherText, err := crypto.Encrypt(plainText)
+		require.NoError(t, err)
+
+		return cipherText
+	}
+
+	mebx := seal("mebx")
+
+	_, err := repos.Devices.Insert(ctx, &entity.Device{GUID: "guid", Hostname: "host", Password: seal("amt"), MEBXPassword: &mebx, TenantID: "tenant-b"})
+	require.NoError(t, err)
+	_, err = repos.Devices.Insert(ctx, &entity.Device{GUID: "no-password", Hostname: "host"})
+	require.NoError(t, err)
+	_, err = repos.Profiles.Insert(ctx, &entity.Profile{ProfileName: "acm", Activation: "acmactivate", AMTPassword: seal("admin"), MEBXPassword: seal("mebx")})
+	require.NoError(t, err)
+
+	migration := credentials.NewMigration(credentials.Repositories{
+		Devices:  repos.Devices,
+		Profiles: repos.Profiles,
+		Trash:    repos.Trash,
+		Audit:    repos.Audit,
+	}, store, crypto, log)
+
+	report, err := migration.Run(ctx, credentials.Options{Tenants: []string{"tenant-b"}, BatchSize: 1})
+	require.NoError(t, err)
+	require.Equal(t, []credentials.KindReport{
+		{Kind: credentials.KindDevices, Moved: 1, Stored: 1},
+		{Kind: credentials.KindProfiles, Moved: 1},
+	}, report.Kinds)
+
+	require.Equal(t, map[string]string{"password": "amt", "mebxpassword": "mebx"}, store["credentials/devices/tenant-b/guid"])
+	require.Equal(t, map[string]string{"amtpassword": "admin", "mebxpassword": "mebx"}, store["credentials/profiles//acm"])
+
+	row, err := repos.Devices.GetByID(ctx, "guid", "tenant-b")
+	require.NoError(t, err)
+	require.True(t, credentials.IsReference(row.Password), "the database only references the password")
+
+	device, err := credentials.NewDeviceRepository(repos.Devices, repos.Trash, store, crypto, log).GetByID(ctx, "guid", "tenant-b")
+	require.NoError(t, err)
+
+	password, err := crypto.Decrypt(device.Password)
+	require.NoError(t, err)
+	require.Equal(t, "amt", password)
+
+	// Running it again finds nothing left to move.
+	report, err = migration.Run(ctx, credentials.Options{Tenants: []string{"tenant-b"}})
+	require.NoError(t, err)
+	require.Equal(t, credentials.KindReport{Kind: credentials.KindDevices, Stored: 2}, report.Kinds[0])
+}
+
+func TestMigrateCredentials_NotEnabled(t *testing.T) {
+	t.Parallel()
+
+	_, err := MigrateCredentials(context.Background(), &config.Config{}, logger.New("error"), credentials.Options{})
+	require.ErrorIs(t, err, errStoreCredentialsNotSet)
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/usecase"
+	"github.com/device-management-toolkit/console/internal/usecase/migrate"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var errSameDatabase = errors.New("target is the configured database")
+
+// MigrateDatabase copies everything from the configured database to the one
+// of provider and url, for the migrate-db command. The target schema is
+// created first, even on a dry run; the target must hold no data.
+func MigrateDatabase(ctx context.Context, cfg *config.Config, log logger.Interface, provider, url string, opts migrate.Options) (*migrate.Report, error) {
+	target := *cfg
+	target.Provider, target.DB.URL = provider, url
+
+	// Embedded SQLite has one database, at a fixed path.
+	if provider == ProviderSQLite || provider == "" {
+		target.Provider, target.DB.URL = ProviderSQLite, ""
+	}
+
+	if isSQLite(cfg.Provider) && isSQLite(target.Provider) || cfg.Provider == target.Provider && cfg.DB.URL == target.DB.URL {
+		return nil, fmt.Errorf("app.MigrateDatabase: %w", errSameDatabase)
+	}
+
+	source, err := buildRepos(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("app.MigrateDatabase: source: %w", err)
+	}
+
+	defer closeRepos(source, log)
+
+	// Also on a dry run, which then checks the target is reachable and empty.
+	if err = Init(&target); err != nil {
+		return nil, fmt.Errorf("app.MigrateDatabase: target schema: %w", err)
+	}
+
+	dest, err := buildRepos(&target, log)
+	if err != nil {
+		return nil, fmt.Errorf("app.MigrateDatabase: target: %w", err)
+	}
+
+	defer closeRepos(dest, log)
+
+	return migrate.New(migrateRepos(source), migrateRepos(dest), log).Run(ctx, opts)
+}
+
+func isSQLite(provider string) bool {
+	return provider == ProviderSQLite || provider == ""
+}
+
+func migrateRepos(repos *usecase.Repos) migrate.Repositories {
+	return migrate.Repositories{
+		Devices:            repos.Devices,
+		Domains:            repos.Domains,
+		Profiles:           repos.Profiles,
+		ProfileWiFiConfigs: repos.ProfileWiFiConfigs,
+		IEEE8021xConfigs:   repos.IEEE8021xConfigs,
+		CIRAConfigs:        repos.CIRAConfigs,
+		WirelessConfigs:    repos.WirelessConfigs,
+		Users:              repos.Users,
+		APIKeys:            repos.APIKeys,
+		Tokens:             repos.Tokens,
+		Audit:              repos.Audit,
+		AccessPolicies:     repos.AccessPolicies,
+		DeviceGroups:       repos.DeviceGroups,
+		Tenants:            repos.Tenants,
+		Trash:              repos.Trash,
+	}
+}
+
+func closeRepos(repos *usecase.Repos, log logger.Interface) {
+	if err := repos.Closer.Close(); err != nil {
+		log.Error(fmt.Errorf("app: repos.Closer.Close: %w", err))
+	}
+}
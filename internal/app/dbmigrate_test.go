@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase"
+	dbmigrate "github.com/device-management-toolkit/console/internal/usecase/migrate"
+	"github.com/device-management-toolkit/console/pkg/db"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// migratedSQLite opens a SQLite database in a temporary directory with the
+// console schema.
+func migratedSQLite(t *testing.T) *usecase.Repos {
+	t.Helper()
+
+	conn, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "console.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { conn.Close() })
+
+	migrationsSource, err := iofs.New(content, "migrations")
+	require.NoError(t, err)
+
+	driver, err := sqlite.WithInstance(conn, &sqlite.Config{})
+	require.NoError(t, err)
+
+	m, err := migrate.NewWithInstance("iofs", migrationsSource, "console", driver)
+	require.NoError(t, err)
+
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		require.NoError(t, err)
+	}
+
+	return usecase.NewSQLRepos(&db.SQL{
+		Builder:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		Pool:       conn,
+		IsEmbedded: true,
+	}, logger.New("error"))
+}
+
+func TestMigrateDatabase_SQLite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	source, target := migratedSQLite(t), migratedSQLite(t)
+
+	ieee, cira, wifi := "eap", "cira", "wifi"
+	now := time.Now().UTC()
+
+	for _, tenantID := range []string{"", "tenant-b"} {
+		_, err := source.IEEE8021xConfigs.Insert(ctx, &entity.IEEE8021xConfig{ProfileName: ieee, AuthenticationProtocol: 0, TenantID: tenantID})
+		require.NoError(t, err)
+		_, err = source.CIRAConfigs.Insert(ctx, &entity.CIRAConfig{ConfigName: cira, MPSAddress: "mps.example.com", Password: "sealed", TenantID: tenantID})
+		require.NoError(t, err)
+		_, err = source.WirelessConfigs.Insert(ctx, &entity.WirelessConfig{ProfileName: wifi, SSID: "ssid", PSKPassphrase: "sealed", IEEE8021xProfileName: &ieee, TenantID: tenantID})
+		require.NoError(t, err)
+		_, err = source.Domains.Insert(ctx, &entity.Domain{ProfileName: "dom", DomainSuffix: "example.com" + tenantID, ProvisioningCert: "pfx", ProvisioningCertPassword: "sealed", TenantID: tenantID})
+		require.NoError(t, err)
+		_, err = source.Profiles.Insert(ctx, &entity.Profile{ProfileName: "acm", Activation: "acmactivate", AMTPassword: "sealed", CIRAConfigName: &cira, TenantID: tenantID})
+		require.NoError(t, err)
+		_, err = source.ProfileWiFiConfigs.Insert(ctx, &entity.ProfileWiFiConfigs{Priority: 1, ProfileName: "acm", WirelessProfileName: wifi, TenantID: tenantID})
+		require.NoError(t, err)
+		_, err = source.Devices.Insert(ctx, &entity.Device{GUID: "guid-" + tenantID, Hostname: "host", Password: "sealed", ConnectionStatus: true, TenantID: tenantID})
+		require.NoError(t, err)
+		require.NoError(t, source.Devices.UpdateLastSeen(ctx, "guid-"+tenantID))
+		_, err = source.Users.Insert(ctx, &entity.User{Username: "admin" + tenantID, PasswordHash: "hash", Role: "admin", TenantID: tenantID})
+		require.NoError(t, err)
+		_, err = source.APIKeys.Insert(ctx, &entity.APIKey{ID: "key" + tenantID, Name: "ci", KeyHash: "hash", Role: "operator", TenantID: tenantID})
+		require.NoError(t, err)
+		require.NoError(t, source.AccessPolicies.Insert(ctx, &entity.AccessPolicy{ID: "policy" + tenantID, Name: "site", Subject: "role:operator", Tags: "site", Method: "any", TenantID: tenantID}))
+		require.NoError(t, source.Tokens.InsertRefreshToken(ctx, &entity.RefreshToken{TokenHash: "refresh" + tenantID, SessionID: "s", Username: "admin" + tenantID, TenantID: tenantID}))
+		require.NoError(t, source.DeviceGroups.Insert(ctx, &entity.DeviceGroup{ID: "group" + tenantID, Name: "lab", TenantID: tenantID}))
+		_, err = source.DeviceGroups.AddMembers(ctx, "group"+tenantID, []string{"guid-" + tenantID}, tenantID)
+		require.NoError(t, err)
+	}
+
+	// The device of tenant-b is migrated in the trash, with its membership.
+	_, err := source.Devices.Delete(ctx, "guid-tenant-b", "tenant-b")
+	require.NoError(t, err)
+
+	trashed, err := source.Trash.Get(ctx, entity.TrashDevices, 10, 0, "tenant-b")
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+
+	require.NoError(t, source.Tokens.Revoke(ctx, &entity.RevokedToken{ID: "jti", Subject: "admin", ExpiresAt: now.Add(time.Hour).Format(time.RFC3339)}))
+	require.NoError(t, source.Audit.Insert(ctx, &entity.AuditEvent{Seq: 1, Actor: "admin", Action: "POST /api/v1/admin/profiles", Hash: "h1"}))
+
+	uc := dbmigrate.New(migrateRepos(source), migrateRepos(target), logger.New("error"))
+
+	report, err := uc.Run(ctx, dbmigrate.Options{DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "tenant-b"}, report.Tenants)
+
+	count, err := target.Profiles.GetCount(ctx, "")
+	require.NoError(t, err)
+	require.Zero(t, count, "a dry run writes nothing")
+
+	migrated, err := uc.Run(ctx, dbmigrate.Options{})
+	require.NoError(t, err)
+	require.Equal(t, report.Kinds, migrated.Kinds)
+
+	for _, k := range migrated.Kinds {
+		want := 2
+		if k.Kind == dbmigrate.KindRevokedTokens || k.Kind == dbmigrate.KindAuditEvents || k.Kind == dbmigrate.KindTrash {
+			want = 1
+		}
+
+		require.Equal(t, want, k.Count, k.Kind)
+	}
+
+	device, err := target.Devices.GetByID(ctx, "guid-", "")
+	require.NoError(t, err)
+	require.Equal(t, "sealed", device.Password, "secrets are copied as stored")
+
+	device, err = target.Devices.GetByID(ctx, "guid-tenant-b", "tenant-b")
+	require.NoError(t, err)
+	require.Nil(t, device)
+
+	moved, err := target.Trash.Get(ctx, entity.TrashDevices, 10, 0, "tenant-b")
+	require.NoError(t, err)
+	require.Equal(t, trashed, moved, "the trash keeps its deletion times")
+
+	_, err = uc.Run(ctx, dbmigrate.Options{})
+	require.ErrorContains(t, err, "target database is not empty")
+}
+
+func TestMigrateDatabase_SameDatabase(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	cfg.Provider = ProviderSQLite
+
+	_, err := MigrateDatabase(context.Background(), cfg, logger.New("error"), "", "", dbmigrate.Options{})
+	require.ErrorIs(t, err, errSameDatabase)
+}
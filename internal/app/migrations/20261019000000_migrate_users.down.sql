@@ -0,0 +1,7 @@
+/*********************************************************************
+* Copyright (c) Intel Corporation 2023
+* SPDX-License-Identifier: Apache-2.0
+**********************************************************************/
+
+DROP INDEX IF EXISTS lower_username_idx;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,18 @@
+/*********************************************************************
+* Copyright (c) Intel Corporation 2023
+* SPDX-License-Identifier: Apache-2.0
+**********************************************************************/
+
+CREATE TABLE IF NOT EXISTS users(
+  username TEXT NOT NULL,
+  password_hash TEXT NOT NULL,
+  role TEXT NOT NULL,
+  must_change_password BOOLEAN NOT NULL,
+  password_changed_at TEXT,
+  creation_date TEXT,
+  created_by TEXT,
+  tenant_id TEXT NOT NULL,
+  PRIMARY KEY (username)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS lower_username_idx ON users (LOWER(username));
@@ -0,0 +1,19 @@
+/*********************************************************************
+* Copyright (c) Intel Corporation 2023
+* SPDX-License-Identifier: Apache-2.0
+**********************************************************************/
+
+CREATE TABLE IF NOT EXISTS api_keys(
+  id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  key_hash TEXT NOT NULL,
+  role TEXT NOT NULL,
+  routes TEXT,
+  tags TEXT,
+  expires_at TEXT,
+  last_used_at TEXT,
+  creation_date TEXT,
+  created_by TEXT,
+  tenant_id TEXT NOT NULL,
+  PRIMARY KEY (id)
+);
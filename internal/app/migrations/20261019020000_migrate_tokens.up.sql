@@ -0,0 +1,26 @@
+/*********************************************************************
+* Copyright (c) Intel Corporation 2023
+* SPDX-License-Identifier: Apache-2.0
+**********************************************************************/
+
+CREATE TABLE IF NOT EXISTS revoked_tokens(
+  id TEXT NOT NULL,
+  subject TEXT,
+  revoked_at TEXT NOT NULL,
+  expires_at TEXT NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens(
+  token_hash TEXT NOT NULL,
+  session_id TEXT NOT NULL,
+  username TEXT NOT NULL,
+  used BOOLEAN NOT NULL,
+  expires_at TEXT NOT NULL,
+  creation_date TEXT,
+  tenant_id TEXT NOT NULL,
+  PRIMARY KEY (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_session_idx ON refresh_tokens (session_id);
+CREATE INDEX IF NOT EXISTS refresh_tokens_username_idx ON refresh_tokens (username, tenant_id);
@@ -0,0 +1,22 @@
+/*********************************************************************
+* Copyright (c) Intel Corporation 2023
+* SPDX-License-Identifier: Apache-2.0
+**********************************************************************/
+
+CREATE TABLE IF NOT EXISTS audit_events(
+  seq BIGINT NOT NULL,
+  occurred_at TEXT NOT NULL,
+  actor TEXT,
+  tenant_id TEXT NOT NULL,
+  action TEXT NOT NULL,
+  target TEXT,
+  parameters TEXT,
+  result TEXT NOT NULL,
+  status INTEGER,
+  source_ip TEXT,
+  prev_hash TEXT NOT NULL,
+  hash TEXT NOT NULL,
+  PRIMARY KEY (seq)
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_tenant_idx ON audit_events (tenant_id, occurred_at);
@@ -0,0 +1,20 @@
+/*********************************************************************
+* Copyright (c) Intel Corporation 2023
+* SPDX-License-Identifier: Apache-2.0
+**********************************************************************/
+
+CREATE TABLE IF NOT EXISTS access_policies(
+  id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  subject TEXT NOT NULL,
+  tags TEXT NOT NULL,
+  method TEXT NOT NULL,
+  permissions TEXT,
+  creation_date TEXT,
+  created_by TEXT,
+  tenant_id TEXT NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE (name, tenant_id)
+);
+
+CREATE INDEX IF NOT EXISTS access_policies_subject_idx ON access_policies (tenant_id, subject);
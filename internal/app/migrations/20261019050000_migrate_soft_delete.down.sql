@@ -0,0 +1,18 @@
+/*********************************************************************
+* Copyright (c) Intel Corporation 2023
+* SPDX-License-Identifier: Apache-2.0
+**********************************************************************/
+
+DROP INDEX IF EXISTS devices_deleted_at_idx;
+DROP INDEX IF EXISTS profiles_deleted_at_idx;
+DROP INDEX IF EXISTS ciraconfigs_deleted_at_idx;
+DROP INDEX IF EXISTS wirelessconfigs_deleted_at_idx;
+DROP INDEX IF EXISTS ieee8021xconfigs_deleted_at_idx;
+DROP INDEX IF EXISTS domains_deleted_at_idx;
+
+ALTER TABLE devices DROP COLUMN deleted_at;
+ALTER TABLE profiles DROP COLUMN deleted_at;
+ALTER TABLE ciraconfigs DROP COLUMN deleted_at;
+ALTER TABLE wirelessconfigs DROP COLUMN deleted_at;
+ALTER TABLE ieee8021xconfigs DROP COLUMN deleted_at;
+ALTER TABLE domains DROP COLUMN deleted_at;
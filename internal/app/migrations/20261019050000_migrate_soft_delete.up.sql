@@ -0,0 +1,18 @@
+/*********************************************************************
+* Copyright (c) Intel Corporation 2023
+* SPDX-License-Identifier: Apache-2.0
+**********************************************************************/
+
+ALTER TABLE devices ADD COLUMN deleted_at TEXT;
+ALTER TABLE profiles ADD COLUMN deleted_at TEXT;
+ALTER TABLE ciraconfigs ADD COLUMN deleted_at TEXT;
+ALTER TABLE wirelessconfigs ADD COLUMN deleted_at TEXT;
+ALTER TABLE ieee8021xconfigs ADD COLUMN deleted_at TEXT;
+ALTER TABLE domains ADD COLUMN deleted_at TEXT;
+
+CREATE INDEX IF NOT EXISTS devices_deleted_at_idx ON devices (deleted_at);
+CREATE INDEX IF NOT EXISTS profiles_deleted_at_idx ON profiles (deleted_at);
+CREATE INDEX IF NOT EXISTS ciraconfigs_deleted_at_idx ON ciraconfigs (deleted_at);
+CREATE INDEX IF NOT EXISTS wirelessconfigs_deleted_at_idx ON wirelessconfigs (deleted_at);
+CREATE INDEX IF NOT EXISTS ieee8021xconfigs_deleted_at_idx ON ieee8021xconfigs (deleted_at);
+CREATE INDEX IF NOT EXISTS domains_deleted_at_idx ON domains (deleted_at);
@@ -0,0 +1,11 @@
+/*********************************************************************
+* Copyright (c) Intel Corporation 2023
+* SPDX-License-Identifier: Apache-2.0
+**********************************************************************/
+
+ALTER TABLE devices ADD COLUMN version TEXT NOT NULL DEFAULT '1';
+ALTER TABLE profiles ADD COLUMN version TEXT NOT NULL DEFAULT '1';
+ALTER TABLE ciraconfigs ADD COLUMN version TEXT NOT NULL DEFAULT '1';
+ALTER TABLE wirelessconfigs ADD COLUMN version TEXT NOT NULL DEFAULT '1';
+ALTER TABLE ieee8021xconfigs ADD COLUMN version TEXT NOT NULL DEFAULT '1';
+ALTER TABLE domains ADD COLUMN version TEXT NOT NULL DEFAULT '1';
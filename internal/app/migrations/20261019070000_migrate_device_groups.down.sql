@@ -0,0 +1,8 @@
+/*********************************************************************
+* Copyright (c) Intel Corporation 2023
+* SPDX-License-Identifier: Apache-2.0
+**********************************************************************/
+
+DROP TABLE IF EXISTS device_group_members;
+DROP TABLE IF EXISTS device_group_rules;
+DROP TABLE IF EXISTS device_groups;
@@ -0,0 +1,42 @@
+/*********************************************************************
+* Copyright (c) Intel Corporation 2023
+* SPDX-License-Identifier: Apache-2.0
+**********************************************************************/
+
+CREATE TABLE IF NOT EXISTS device_groups(
+  id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  description TEXT,
+  parent_id TEXT,
+  creation_date TEXT,
+  created_by TEXT,
+  tenant_id TEXT NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE (name, tenant_id),
+  FOREIGN KEY (parent_id) REFERENCES device_groups(id)
+);
+
+CREATE INDEX IF NOT EXISTS device_groups_parent_idx ON device_groups (tenant_id, parent_id);
+
+CREATE TABLE IF NOT EXISTS device_group_rules(
+  group_id TEXT NOT NULL,
+  position INTEGER NOT NULL,
+  field TEXT NOT NULL,
+  operator TEXT NOT NULL,
+  value TEXT NOT NULL,
+  PRIMARY KEY (group_id, position),
+  FOREIGN KEY (group_id) REFERENCES device_groups(id) ON DELETE CASCADE
+);
+
+-- Members are added by hand, or matched by the group's rules when dynamic.
+CREATE TABLE IF NOT EXISTS device_group_members(
+  group_id TEXT NOT NULL,
+  guid TEXT NOT NULL,
+  dynamic BOOLEAN NOT NULL,
+  tenant_id TEXT NOT NULL,
+  PRIMARY KEY (group_id, guid, dynamic),
+  FOREIGN KEY (group_id) REFERENCES device_groups(id) ON DELETE CASCADE,
+  FOREIGN KEY (guid, tenant_id) REFERENCES devices(guid, tenantid) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS device_group_members_guid_idx ON device_group_members (guid, tenant_id);
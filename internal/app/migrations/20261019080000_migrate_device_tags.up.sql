@@ -0,0 +1,35 @@
+/*********************************************************************
+* Copyright (c) Intel Corporation 2023
+* SPDX-License-Identifier: Apache-2.0
+**********************************************************************/
+
+-- One row per tag of a device. devices.tags keeps the comma-joined list the
+-- API returns; this table is what tag queries use.
+CREATE TABLE IF NOT EXISTS device_tags(
+  guid TEXT NOT NULL,
+  tenant_id TEXT NOT NULL,
+  tag TEXT NOT NULL,
+  PRIMARY KEY (guid, tenant_id, tag),
+  FOREIGN KEY (guid, tenant_id) REFERENCES devices(guid, tenantid) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS device_tags_tag_idx ON device_tags (tenant_id, tag);
+
+-- Split the tags of the existing devices one character at a time, which
+-- SQLite and Postgres both can: done holds a tag once its comma is reached.
+WITH RECURSIVE split(guid, tenant_id, tag, done, rest) AS (
+  SELECT guid, tenantid, CAST('' AS TEXT), CAST(NULL AS TEXT), CAST(tags || ',' AS TEXT)
+  FROM devices
+  WHERE COALESCE(tags, '') <> ''
+  UNION ALL
+  SELECT guid, tenant_id,
+    CASE WHEN substr(rest, 1, 1) = ',' THEN CAST('' AS TEXT) ELSE tag || substr(rest, 1, 1) END,
+    CASE WHEN substr(rest, 1, 1) = ',' THEN tag ELSE CAST(NULL AS TEXT) END,
+    substr(rest, 2)
+  FROM split
+  WHERE rest <> ''
+)
+INSERT INTO device_tags (guid, tenant_id, tag)
+SELECT DISTINCT guid, tenant_id, trim(done)
+FROM split
+WHERE done IS NOT NULL AND trim(done) <> '';
@@ -58,7 +58,7 @@ func buildPostgresRepos(cfg *config.Config, log logger.Interface) (*usecase.Repo
 		return nil, fmt.Errorf("app.buildPostgresRepos: %w for provider %q", errDBURLRequired, ProviderPostgres)
 	}
 
-	database, err := db.New(cfg.DB.URL, sql.Open, db.MaxPoolSize(cfg.PoolMax), db.EnableForeignKeys(true))
+	database, err := db.New(cfg.DB.URL, sql.Open, db.MaxPoolSize(cfg.PoolMax), db.EnableForeignKeys(true), db.QueryTimeout(cfg.DB.QueryTimeout))
 	if err != nil {
 		return nil, fmt.Errorf("app.buildPostgresRepos: %w", err)
 	}
@@ -69,7 +69,7 @@ func buildPostgresRepos(cfg *config.Config, log logger.Interface) (*usecase.Repo
 func buildSQLiteRepos(cfg *config.Config, log logger.Interface) (*usecase.Repos, error) {
 	// Embedded SQLite ignores any URL — db.New routes to the on-disk path
 	// when the URL is empty.
-	database, err := db.New("", sql.Open, db.MaxPoolSize(cfg.PoolMax), db.EnableForeignKeys(true))
+	database, err := db.New("", sql.Open, db.MaxPoolSize(cfg.PoolMax), db.EnableForeignKeys(true), db.QueryTimeout(cfg.DB.QueryTimeout))
 	if err != nil {
 		return nil, fmt.Errorf("app.buildSQLiteRepos: %w", err)
 	}
@@ -87,7 +87,7 @@ func buildMongoRepos(cfg *config.Config, log logger.Interface) (*usecase.Repos,
 	startupCtx, cancel := context.WithTimeout(context.Background(), mongoStartupTimeout)
 	defer cancel()
 
-	client, database, err := mongodb.Connect(startupCtx, cfg.DB.URL, log)
+	client, database, err := mongodb.Connect(startupCtx, cfg.DB.URL, cfg.DB.QueryTimeout, log)
 	if err != nil {
 		return nil, fmt.Errorf("app.buildMongoRepos: %w", err)
 	}
@@ -100,6 +100,16 @@ func buildMongoRepos(cfg *config.Config, log logger.Interface) (*usecase.Repos,
 		IEEE8021xConfigs:   mongodb.NewIEEE8021xRepo(database),
 		CIRAConfigs:        mongodb.NewCIRARepo(database),
 		WirelessConfigs:    mongodb.NewWirelessRepo(database, log),
+		Users:              mongodb.NewUserRepo(database),
+		APIKeys:            mongodb.NewAPIKeyRepo(database),
+		Tokens:             mongodb.NewTokenRepo(database),
+		Audit:              mongodb.NewAuditRepo(database),
+		AccessPolicies:     mongodb.NewAccessPolicyRepo(database),
+		Trash:              mongodb.NewTrashRepo(database),
+		DeviceGroups:       mongodb.NewDeviceGroupRepo(database),
+		Tags:               mongodb.NewTagRepo(database),
+		Tenants:            mongodb.NewTenantRepo(database),
+		Transactor:         mongodb.NewTransactor(database),
 		Closer: usecase.CloserFunc(func() error {
 			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), mongoShutdownTimeout)
 			defer shutdownCancel()
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/usecase"
+	"github.com/device-management-toolkit/console/internal/usecase/keyrotation"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// RotateKey re-encrypts the secrets in the configured database from the
+// previous key to the new one.
+func RotateKey(ctx context.Context, cfg *config.Config, log logger.Interface, previousKey, key string, opts keyrotation.Options) (*keyrotation.Report, error) {
+	repos, err := buildRepos(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("app.RotateKey: %w", err)
+	}
+
+	defer closeRepos(repos, log)
+
+	return keyrotation.New(keyRotationRepos(repos), previousKey, key, log).Rotate(ctx, opts)
+}
+
+func keyRotationRepos(repos *usecase.Repos) keyrotation.Repositories {
+	return keyrotation.Repositories{
+		CIRAConfigs:     repos.CIRAConfigs,
+		WirelessConfigs: repos.WirelessConfigs,
+		Domains:         repos.Domains,
+		Profiles:        repos.Profiles,
+		Devices:         repos.Devices,
+		Tenants:         repos.Tenants,
+		Trash:           repos.Trash,
+	}
+}
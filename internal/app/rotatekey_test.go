@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/keyrotation"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func TestRotateKey_SQLite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repos := migratedSQLite(t)
+
+	previous := security.Crypto{EncryptionKey: security.Crypto{}.GenerateKey()}
+	current := security.Crypto{EncryptionKey: security.Crypto{}.GenerateKey()}
+
+	seal := func(c security.Crypto, plainText string) string {
+		cipherText, err := c.Encrypt(plainText)
+		require.NoError(t, err)
+
+		return cipherText
+	}
+
+	mps, mebx := seal(previous, "mps"), seal(current, "mebx")
+
+	for _, tenantID := range []string{"", "tenant-b"} {
+		_, err := repos.CIRAConfigs.Insert(ctx, &entity.CIRAConfig{ConfigName: "cira", MPSAddress: "mps.example.com", Password: seal(previous, "cira"), TenantID: tenantID})
+		require.NoError(t, err)
+		_, err = repos.WirelessConfigs.Insert(ctx, &entity.WirelessConfig{ProfileName: "wifi", SSID: "ssid", PSKPassphrase: seal(previous, "psk"), TenantID: tenantID})
+		require.NoError(t, err)
+		_, err = repos.Domains.Insert(ctx, &entity.Domain{ProfileName: "dom", DomainSuffix: "example.com" + tenantID, ProvisioningCert: "pfx", TenantID: tenantID})
+		require.NoError(t, err)
+		_, err = repos.Profiles.Insert(ctx, &entity.Profile{ProfileName: "acm", Activation: "acmactivate", AMTPassword: seal(previous, "amt"), MEBXPassword: seal(current, "mebx"), TenantID: tenantID})
+		require.NoError(t, err)
+		_, err = repos.Devices.Insert(ctx, &entity.Device{GUID: "guid-" + tenantID, Hostname: "host", Password: seal(previous, "amt"), MPSPassword: &mps, MEBXPassword: &mebx, TenantID: tenantID})
+		require.NoError(t, err)
+	}
+
+	_, err := repos.Devices.Insert(ctx, &entity.Device{GUID: "garbled", Hostname: "host", Password: "not encrypted"})
+	require.NoError(t, err)
+	_, err = repos.Devices.Insert(ctx, &entity.Device{GUID: "stored", Hostname: "host", Password: "secretstore:credentials/devices//stored#password"})
+	require.NoError(t, err)
+
+	// No audit event names tenant-b: its entities do.
+	uc := keyrotation.New(keyRotationRepos(repos), previous.EncryptionKey, current.EncryptionKey, logger.New("error"))
+
+	report, err := uc.Rotate(ctx, keyrotation.Options{BatchSize: 1})
+	require.NoError(t, err)
+	require.True(t, report.Complete)
+	require.Equal(t, []string{"", "tenant-b"}, report.Tenants)
+	require.Equal(t, []keyrotation.KindReport{
+		{Kind: keyrotation.KindCIRAConfigs, Rotated: 2},
+		{Kind: keyrotation.KindWirelessConfigs, Rotated: 2},
+		{Kind: keyrotation.KindDomains, Current: 2},
+		{Kind: keyrotation.KindProfiles, Rotated: 2},
+		{Kind: keyrotation.KindDevices, Rotated: 2, Current: 1, Unreadable: 1},
+	}, report.Kinds)
+
+	device, err := repos.Devices.GetByID(ctx, "guid-tenant-b", "tenant-b")
+	require.NoError(t, err)
+
+	for secret, want := range map[string]string{device.Password: "amt", *device.MPSPassword: "mps", *device.MEBXPassword: "mebx"} {
+		plainText, err := current.Decrypt(secret)
+		require.NoError(t, err)
+		require.Equal(t, want, plainText)
+	}
+
+	require.Equal(t, mebx, *device.MEBXPassword, "secrets under the new key are kept")
+
+	// Running it again resumes, with nothing left to rotate.
+	report, err = uc.Rotate(ctx, keyrotation.Options{})
+	require.NoError(t, err)
+	require.True(t, report.Complete)
+	require.Equal(t, keyrotation.KindReport{Kind: keyrotation.KindProfiles, Current: 2}, report.Kinds[3])
+
+	// The trash is out of reach, so the previous key is kept for it.
+	_, err = repos.Devices.Delete(ctx, "guid-tenant-b", "tenant-b")
+	require.NoError(t, err)
+
+	report, err = uc.Rotate(ctx, keyrotation.Options{})
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Trashed)
+	require.False(t, report.Complete)
+
+	_, err = keyrotation.New(keyRotationRepos(repos), current.EncryptionKey, current.EncryptionKey, logger.New("error")).Rotate(ctx, keyrotation.Options{})
+	require.Error(t, err)
+}
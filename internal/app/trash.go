@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// trashPurgeInterval is how often the trash is searched for expired entities.
+const trashPurgeInterval = time.Hour
+
+// startTrashPurge purges expired entities from the trash now and then every
+// trashPurgeInterval, until the returned function is called. With no
+// retention period it does nothing.
+func startTrashPurge(cfg *config.Config, log logger.Interface, t trash.Feature) (stop func()) {
+	if cfg.TrashRetention <= 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(trashPurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := t.PurgeExpired(ctx, time.Now()); err != nil && ctx.Err() == nil {
+				log.Error(fmt.Errorf("app - Run - trash.PurgeExpired: %w", err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
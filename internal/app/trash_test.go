@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/sqldb"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func TestSQLTrash(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repos := migratedSQLite(t)
+
+	cira := "cira"
+
+	_, err := repos.CIRAConfigs.Insert(ctx, &entity.CIRAConfig{ConfigName: cira, MPSAddress: "mps.example.com", Password: "sealed"})
+	require.NoError(t, err)
+	_, err = repos.WirelessConfigs.Insert(ctx, &entity.WirelessConfig{ProfileName: "wifi", SSID: "ssid", PSKPassphrase: "sealed"})
+	require.NoError(t, err)
+	_, err = repos.Profiles.Insert(ctx, &entity.Profile{ProfileName: "acm", Activation: "acmactivate", AMTPassword: "sealed", CIRAConfigName: &cira})
+	require.NoError(t, err)
+	_, err = repos.ProfileWiFiConfigs.Insert(ctx, &entity.ProfileWiFiConfigs{Priority: 1, ProfileName: "acm", WirelessProfileName: "wifi"})
+	require.NoError(t, err)
+	_, err = repos.Devices.Insert(ctx, &entity.Device{GUID: "guid", Hostname: "host", Password: "sealed"})
+	require.NoError(t, err)
+
+	// Deleting hides the device, which the trash lists.
+	deleted, err := repos.Devices.Delete(ctx, "guid", "")
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	device, err := repos.Devices.GetByID(ctx, "guid", "")
+	require.NoError(t, err)
+	require.Nil(t, device)
+
+	devices, err := repos.Devices.Get(ctx, 10, 0, "")
+	require.NoError(t, err)
+	require.Empty(t, devices)
+
+	items, err := repos.Trash.Get(ctx, entity.TrashDevices, 10, 0, "")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "host", items[0].Name)
+
+	deleted, err = repos.Devices.Delete(ctx, "guid", "")
+	require.NoError(t, err)
+	require.False(t, deleted, "already in the trash")
+
+	restored, err := repos.Trash.Restore(ctx, entity.TrashDevices, "guid", "")
+	require.NoError(t, err)
+	require.True(t, restored)
+
+	device, err = repos.Devices.GetByID(ctx, "guid", "")
+	require.NoError(t, err)
+	require.Equal(t, "sealed", device.Password, "the password comes back with the device")
+
+	// A CIRA config used by a live profile stays.
+	_, err = repos.CIRAConfigs.Delete(ctx, cira, "")
+	require.ErrorAs(t, err, &sqldb.ForeignKeyViolationError{})
+
+	_, err = repos.Profiles.Delete(ctx, "acm", "")
+	require.NoError(t, err)
+	_, err = repos.CIRAConfigs.Delete(ctx, cira, "")
+	require.NoError(t, err)
+
+	// A profile comes back only once its CIRA config has, and with its wifi
+	// configs.
+	_, err = repos.Trash.Restore(ctx, entity.TrashProfiles, "acm", "")
+	require.ErrorAs(t, err, &sqldb.ForeignKeyViolationError{})
+
+	_, err = repos.Trash.Restore(ctx, entity.TrashCIRAConfigs, cira, "")
+	require.NoError(t, err)
+
+	restored, err = repos.Trash.Restore(ctx, entity.TrashProfiles, "acm", "")
+	require.NoError(t, err)
+	require.True(t, restored)
+
+	links, err := repos.ProfileWiFiConfigs.GetByProfileName(ctx, "acm", "")
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+
+	// Purging deletes for good; a live entity is not purged.
+	purged, err := repos.Trash.Purge(ctx, entity.TrashProfiles, "acm", "")
+	require.NoError(t, err)
+	require.False(t, purged)
+
+	_, err = repos.Profiles.Delete(ctx, "acm", "")
+	require.NoError(t, err)
+
+	purged, err = repos.Trash.Purge(ctx, entity.TrashProfiles, "acm", "")
+	require.NoError(t, err)
+	require.True(t, purged)
+
+	links, err = repos.ProfileWiFiConfigs.GetByProfileName(ctx, "acm", "")
+	require.NoError(t, err)
+	require.Empty(t, links)
+
+	_, err = repos.Profiles.Insert(ctx, &entity.Profile{ProfileName: "acm", Activation: "acmactivate", AMTPassword: "sealed"})
+	require.NoError(t, err, "the name is free again")
+
+	// Expired entities are purged automatically.
+	_, err = repos.Devices.Delete(ctx, "guid", "")
+	require.NoError(t, err)
+
+	uc := trash.New(repos.Trash, time.Hour, logger.New("error"))
+
+	purgedCount, err := uc.PurgeExpired(ctx, time.Now())
+	require.NoError(t, err)
+	require.Zero(t, purgedCount, "deleted within the retention period")
+
+	purgedCount, err = uc.PurgeExpired(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, purgedCount)
+
+	count, err := repos.Trash.GetCount(ctx, entity.TrashDevices, "")
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
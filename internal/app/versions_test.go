@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/versions"
+)
+
+func TestSQLVersions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repos := migratedSQLite(t)
+
+	inserted, err := repos.Domains.Insert(ctx, &entity.Domain{ProfileName: "d1", DomainSuffix: "example.com"})
+	require.NoError(t, err)
+
+	domain, err := repos.Domains.GetByName(ctx, "d1", "")
+	require.NoError(t, err)
+	require.Equal(t, inserted, domain.Version)
+
+	// An update at the expected version moves the domain to a new one.
+	updated, err := repos.Domains.Update(versions.WithIfMatch(ctx, []string{inserted}), domain)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	domain, err = repos.Domains.GetByName(ctx, "d1", "")
+	require.NoError(t, err)
+	require.NotEqual(t, inserted, domain.Version)
+
+	// A change at the old version is refused, and leaves the domain alone.
+	stale := versions.WithIfMatch(ctx, []string{inserted})
+
+	_, err = repos.Domains.Update(stale, domain)
+	require.ErrorAs(t, err, &repoerrors.PreconditionFailedError{})
+
+	_, err = repos.Domains.Delete(stale, "d1", "")
+	require.ErrorAs(t, err, &repoerrors.PreconditionFailedError{})
+
+	// A missing domain is still just missing.
+	updated, err = repos.Domains.Update(stale, &entity.Domain{ProfileName: "ghost"})
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	deleted, err := repos.Domains.Delete(versions.WithIfMatch(ctx, []string{domain.Version}), "d1", "")
+	require.NoError(t, err)
+	require.True(t, deleted)
+}
@@ -0,0 +1,137 @@
+// Package clientcert authenticates API clients by X.509 certificate: the TLS
+// handshake verifies the certificate against the configured CA bundle and
+// CRL, and Identify maps the verified certificate to a console user.
+package clientcert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/device-management-toolkit/console/config"
+)
+
+var (
+	// ErrNoCertificates means the CA bundle holds no PEM certificate.
+	ErrNoCertificates = errors.New("clientcert: no PEM certificates in CA file")
+	// ErrRevoked means a certificate in the client's chain is on the CRL.
+	ErrRevoked = errors.New("clientcert: certificate revoked")
+)
+
+// TLSConfig returns a server TLS config that asks clients for a certificate
+// and verifies any they present. Clients without one still connect, so
+// bearer tokens keep working.
+func TLSConfig(cfg config.ClientCerts) (*tls.Config, error) {
+	cas, err := loadCertificates(cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	for _, ca := range cas {
+		pool.AddCert(ca)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS13,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientCAs:  pool,
+	}
+
+	if cfg.CRLFile != "" {
+		crl := &revocationList{path: cfg.CRLFile, issuers: cas}
+		if _, err := crl.current(); err != nil {
+			return nil, err
+		}
+
+		tlsConfig.VerifyPeerCertificate = func(_ [][]byte, chains [][]*x509.Certificate) error {
+			return crl.check(chains)
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// Identify returns the first identity matching cert, if any.
+func Identify(cert *x509.Certificate, identities []config.ClientCertIdentity) (config.ClientCertIdentity, bool) {
+	for _, id := range identities {
+		if matches(cert, id.Match) {
+			return id, true
+		}
+	}
+
+	return config.ClientCertIdentity{}, false
+}
+
+func matches(cert *x509.Certificate, match string) bool {
+	kind, value, _ := strings.Cut(match, ":")
+
+	switch strings.ToLower(kind) {
+	case "subject":
+		return strings.EqualFold(cert.Subject.String(), value)
+	case "cn":
+		return cert.Subject.CommonName == value
+	case "dns":
+		return containsFold(cert.DNSNames, value)
+	case "email":
+		return containsFold(cert.EmailAddresses, value)
+	case "uri":
+		for _, uri := range cert.URIs {
+			if uri.String() == value {
+				return true
+			}
+		}
+	case "ip":
+		ip := net.ParseIP(value)
+		for _, addr := range cert.IPAddresses {
+			if addr.Equal(ip) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func loadCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("clientcert - loadCertificates - os.ReadFile: %w", err)
+	}
+
+	var certs []*x509.Certificate
+
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("clientcert - loadCertificates - x509.ParseCertificate: %w", err)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, ErrNoCertificates
+	}
+
+	return certs, nil
+}
@@ -0,0 +1,266 @@
+package clientcert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/config"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, name string) testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return testCA{cert: cert, key: key}
+}
+
+// issue signs a client certificate for template's subject and SANs.
+func (ca testCA) issue(t *testing.T, serial int64, template *x509.Certificate) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(serial)
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func (ca testCA) crl(t *testing.T, number int64, revoked ...int64) []byte {
+	t.Helper()
+
+	return ca.crlUntil(t, number, time.Now().Add(time.Hour), revoked...)
+}
+
+// crlUntil signs a CRL whose next update is due at nextUpdate.
+func (ca testCA) crlUntil(t *testing.T, number int64, nextUpdate time.Time, revoked ...int64) []byte {
+	t.Helper()
+
+	entries := make([]x509.RevocationListEntry, 0, len(revoked))
+	for _, serial := range revoked {
+		entries = append(entries, x509.RevocationListEntry{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(number),
+		ThisUpdate:                nextUpdate.Add(-2 * time.Hour),
+		NextUpdate:                nextUpdate,
+		RevokedCertificateEntries: entries,
+	}, ca.cert, ca.key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func certPEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+// handshake reports whether a client presenting cert completes a request
+// against a server using tlsConfig.
+func handshake(t *testing.T, tlsConfig *tls.Config, cert tls.Certificate) bool {
+	t.Helper()
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.VerifiedChains) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	srv.TLS = tlsConfig.Clone()
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	client := srv.Client()
+	transport, _ := client.Transport.(*http.Transport)
+	transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
+
+func TestTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCA(t, "Client CA")
+	other := newTestCA(t, "Other CA")
+
+	good := ca.issue(t, 10, &x509.Certificate{Subject: pkix.Name{CommonName: "ci"}})
+	revokedCert := ca.issue(t, 11, &x509.Certificate{Subject: pkix.Name{CommonName: "old-ci"}})
+	stranger := other.issue(t, 10, &x509.Certificate{Subject: pkix.Name{CommonName: "ci"}})
+
+	cfg := config.ClientCerts{
+		CAFile:  writeFile(t, dir, "ca.pem", certPEM(ca.cert)),
+		CRLFile: writeFile(t, dir, "crl.pem", ca.crl(t, 1, 11)),
+	}
+
+	tlsConfig, err := TLSConfig(cfg)
+	require.NoError(t, err)
+
+	require.True(t, handshake(t, tlsConfig, good))
+	require.False(t, handshake(t, tlsConfig, revokedCert), "revoked")
+	require.False(t, handshake(t, tlsConfig, stranger), "unknown CA")
+
+	// A newly published CRL applies without a restart.
+	later := time.Now().Add(time.Minute)
+
+	writeFile(t, dir, "crl.pem", ca.crl(t, 2, 10, 11))
+	require.NoError(t, os.Chtimes(cfg.CRLFile, later, later))
+	require.False(t, handshake(t, tlsConfig, good), "revoked later")
+}
+
+func TestTLSConfigRejectsExpiredCRL(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCA(t, "Client CA")
+	good := ca.issue(t, 10, &x509.Certificate{Subject: pkix.Name{CommonName: "ci"}})
+
+	cfg := config.ClientCerts{
+		CAFile:  writeFile(t, dir, "ca.pem", certPEM(ca.cert)),
+		CRLFile: writeFile(t, dir, "crl.pem", ca.crlUntil(t, 1, time.Now().Add(-time.Minute))),
+	}
+
+	tlsConfig, err := TLSConfig(cfg)
+	require.NoError(t, err)
+
+	require.False(t, handshake(t, tlsConfig, good), "stale CRL")
+
+	crl := &revocationList{path: cfg.CRLFile, issuers: []*x509.Certificate{ca.cert}}
+	require.ErrorIs(t, crl.check([][]*x509.Certificate{{good.Leaf, ca.cert}}), ErrCRLExpired)
+
+	// Publishing a fresh list lets the client back in.
+	later := time.Now().Add(time.Minute)
+
+	writeFile(t, dir, "crl.pem", ca.crl(t, 2))
+	require.NoError(t, os.Chtimes(cfg.CRLFile, later, later))
+	require.True(t, handshake(t, tlsConfig, good))
+}
+
+func TestTLSConfigRejectsBadFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCA(t, "Client CA")
+	other := newTestCA(t, "Other CA")
+	caFile := writeFile(t, dir, "ca.pem", certPEM(ca.cert))
+
+	_, err := TLSConfig(config.ClientCerts{CAFile: writeFile(t, dir, "empty.pem", []byte("not a cert"))})
+	require.ErrorIs(t, err, ErrNoCertificates)
+
+	_, err = TLSConfig(config.ClientCerts{CAFile: caFile, CRLFile: writeFile(t, dir, "other-crl.pem", other.crl(t, 1, 10))})
+	require.ErrorIs(t, err, ErrCRLIssuer)
+
+	_, err = TLSConfig(config.ClientCerts{CAFile: caFile, CRLFile: writeFile(t, dir, "no-crl.pem", certPEM(ca.cert))})
+	require.ErrorIs(t, err, ErrNoCRL)
+}
+
+func TestIdentify(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t, "Client CA")
+	spiffe, err := url.Parse("spiffe://example.org/ci")
+	require.NoError(t, err)
+
+	cert := ca.issue(t, 10, &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "ci", Organization: []string{"Example"}},
+		DNSNames:       []string{"ci.example.org"},
+		EmailAddresses: []string{"ci@example.org"},
+		URIs:           []*url.URL{spiffe},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.5")},
+	}).Leaf
+
+	tests := []struct {
+		match string
+		found bool
+	}{
+		{"subject:CN=ci,O=Example", true},
+		{"subject:CN=ci", false},
+		{"cn:ci", true},
+		{"cn:CI", false},
+		{"dns:CI.example.org", true},
+		{"email:ci@example.org", true},
+		{"uri:spiffe://example.org/ci", true},
+		{"ip:10.0.0.5", true},
+		{"ip:10.0.0.6", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.match, func(t *testing.T) {
+			t.Parallel()
+
+			id, ok := Identify(cert, []config.ClientCertIdentity{
+				{Match: "dns:other.example.org", Username: "other"},
+				{Match: tc.match, Username: "ci", Role: "operator"},
+			})
+			require.Equal(t, tc.found, ok)
+
+			if tc.found {
+				require.Equal(t, "ci", id.Username)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package clientcert
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoCRL means the CRL file holds no revocation list.
+	ErrNoCRL = errors.New("clientcert: no revocation list in CRL file")
+	// ErrCRLIssuer means a revocation list is not signed by a CA in the bundle.
+	ErrCRLIssuer = errors.New("clientcert: revocation list not signed by a client CA")
+	// ErrCRLExpired means the issuer's revocation list is past its next
+	// update, so revocations published since may be missing from it.
+	ErrCRLExpired = errors.New("clientcert: revocation list past its next update")
+)
+
+// revocationList holds the CRLs in a file, re-reading it when it changes so
+// a published CRL takes effect without a restart.
+type revocationList struct {
+	path    string
+	issuers []*x509.Certificate
+
+	mu      sync.Mutex
+	modTime time.Time
+	lists   []*x509.RevocationList
+}
+
+// check refuses chains holding a certificate its issuer has revoked, or
+// whose issuer's list is past its next update: a stale list fails closed
+// until a fresh one is published. A certificate whose issuer published no
+// list passes.
+func (r *revocationList) check(chains [][]*x509.Certificate) error {
+	lists, err := r.current()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, chain := range chains {
+		for i := 0; i+1 < len(chain); i++ {
+			if err := checkCertificate(lists, chain[i], now); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkCertificate(lists []*x509.RevocationList, cert *x509.Certificate, now time.Time) error {
+	for _, list := range lists {
+		if !bytes.Equal(list.RawIssuer, cert.RawIssuer) {
+			continue
+		}
+
+		// NextUpdate is optional in CRLs; one without it never goes stale.
+		if !list.NextUpdate.IsZero() && now.After(list.NextUpdate) {
+			return ErrCRLExpired
+		}
+
+		for _, entry := range list.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				return ErrRevoked
+			}
+		}
+	}
+
+	return nil
+}
+
+// current returns the lists, reloading the file if it changed. A file that
+// no longer loads fails every check rather than letting revoked clients in.
+func (r *revocationList) current() ([]*x509.RevocationList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("clientcert - revocationList - os.Stat: %w", err)
+	}
+
+	if r.lists != nil && info.ModTime().Equal(r.modTime) {
+		return r.lists, nil
+	}
+
+	lists, err := r.load()
+	if err != nil {
+		r.lists = nil
+
+		return nil, err
+	}
+
+	r.lists, r.modTime = lists, info.ModTime()
+
+	return lists, nil
+}
+
+// load parses PEM "X509 CRL" blocks, or a single DER list. Every list must be
+// signed by a CA in the bundle.
+func (r *revocationList) load() ([]*x509.RevocationList, error) {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("clientcert - revocationList - os.ReadFile: %w", err)
+	}
+
+	var ders [][]byte
+
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == "X509 CRL" {
+			ders = append(ders, block.Bytes)
+		}
+	}
+
+	if len(ders) == 0 && len(data) > 0 && !bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----")) {
+		ders = append(ders, data)
+	}
+
+	if len(ders) == 0 {
+		return nil, ErrNoCRL
+	}
+
+	lists := make([]*x509.RevocationList, 0, len(ders))
+
+	for _, der := range ders {
+		list, err := x509.ParseRevocationList(der)
+		if err != nil {
+			return nil, fmt.Errorf("clientcert - revocationList - x509.ParseRevocationList: %w", err)
+		}
+
+		if !r.signedByIssuer(list) {
+			return nil, ErrCRLIssuer
+		}
+
+		lists = append(lists, list)
+	}
+
+	return lists, nil
+}
+
+func (r *revocationList) signedByIssuer(list *x509.RevocationList) bool {
+	for _, ca := range r.issuers {
+		if bytes.Equal(ca.RawSubject, list.RawIssuer) && list.CheckSignatureFrom(ca) == nil {
+			return true
+		}
+	}
+
+	return false
+}
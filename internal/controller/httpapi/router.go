@@ -15,6 +15,7 @@ import (
 	v2 "github.com/device-management-toolkit/console/internal/controller/httpapi/v2"
 	openapi "github.com/device-management-toolkit/console/internal/controller/openapi"
 	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/rbac"
 	"github.com/device-management-toolkit/console/internal/usecase"
 	"github.com/device-management-toolkit/console/pkg/logger"
 )
@@ -38,10 +39,15 @@ func NewRouter(handler *gin.Engine, l logger.Interface, t usecase.Usecases, cfg
 	fuegoAdapter.AddToGinRouter(handler)
 
 	// Public routes
-	login := v1.NewLoginRoute(cfg)
-	handler.POST("/api/v1/authorize", login.Login)
+	login := v1.NewLoginRoute(cfg, t.Users, t.APIKeys, t.Tokens, t.Lockouts, t.LDAP)
+	audit := v1.AuditMiddleware(t.Audit, l)
+	handler.POST("/api/v1/authorize", audit, login.Login)
+	// Public, as a user whose password must be changed cannot log in.
+	handler.POST("/api/v1/authorize/password", audit, login.ChangePassword)
 	// Public so an expired session can still clear its cookies.
-	handler.POST("/api/v1/authorize/logout", login.Logout)
+	handler.POST("/api/v1/authorize/logout", audit, login.Logout)
+	// Public, as the access token being renewed may have expired.
+	handler.POST("/api/v1/authorize/refresh", audit, login.Refresh)
 
 	// Setup UI routes (no-op in noui builds)
 	setupUIRoutes(handler, l, cfg)
@@ -59,9 +65,21 @@ func NewRouter(handler *gin.Engine, l logger.Interface, t usecase.Usecases, cfg
 	// Protected routes using JWT middleware
 	var protected *gin.RouterGroup
 	if cfg.Disabled {
-		protected = handler.Group("/api")
+		protected = handler.Group("/api", audit)
 	} else {
-		protected = handler.Group("/api", login.JWTAuthMiddleware())
+		if _, ok := rbac.ParseRole(cfg.DefaultRole); !ok && cfg.DefaultRole != "" {
+			l.Warn("auth.defaultRole " + cfg.DefaultRole + " is not a known role; tokens without a role will be denied")
+		}
+
+		for _, id := range cfg.TLS.ClientCerts.Identities {
+			if _, ok := rbac.ParseRole(id.Role); !ok {
+				l.Warn("http.tls.clientCerts identity " + id.Username + " has no known role; its requests will be denied")
+			}
+		}
+
+		// Audit first, so calls refused by either check are recorded.
+		protected = handler.Group("/api", audit, login.JWTAuthMiddleware(), login.RBACMiddleware(),
+			v1.DeviceScopeMiddleware(t.AccessPolicies, l))
 	}
 
 	registerCustomValidators(l)
@@ -82,6 +100,16 @@ func NewRouter(handler *gin.Engine, l logger.Interface, t usecase.Usecases, cfg
 		v1.NewProfileRoutes(h, t.Profiles, l)
 		v1.NewWirelessConfigRoutes(h, t.WirelessProfiles, l)
 		v1.NewIEEE8021xConfigRoutes(h, t.IEEE8021xProfiles, l)
+		v1.NewRedirectionSessionRoutes(h, t.Devices, l)
+		v1.NewUserRoutes(h, t.Users, t.Tokens, l, cfg)
+		v1.NewAPIKeyRoutes(h, t.APIKeys, l)
+		v1.NewLockoutRoutes(h, t.Lockouts, l)
+		v1.NewAuditRoutes(h, t.Audit, l)
+		v1.NewAccessPolicyRoutes(h, t.AccessPolicies, l)
+		v1.NewBackupRoutes(h, t.Backup, l)
+		v1.NewTrashRoutes(h, t.Trash, l)
+		v1.NewDeviceGroupRoutes(h, t.DeviceGroups, l)
+		v1.NewTagRoutes(h, t.Tags, l)
 	}
 
 	h3 := protected.Group("/v2")
@@ -105,4 +133,5 @@ func registerCustomValidators(l logger.Interface) {
 	registerValidation("alphanumhyphenunderscore", dto.ValidateAlphaNumHyphenUnderscore)
 	registerValidation("wifistate", dto.ValidateWirelessState)
 	registerValidation("wirelessprofile", dto.ValidateWirelessProfile)
+	registerValidation("username", dto.ValidateUsername)
 }
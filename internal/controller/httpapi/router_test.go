@@ -0,0 +1,57 @@
+package httpapi
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/internal/usecase"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var ginParam = regexp.MustCompile(`:([^/]+)`)
+
+// TestRouterPermissionTable fails when a protected route is added without a
+// permission, which the RBAC middleware would otherwise deny to everyone.
+//
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestRouterPermissionTable(t *testing.T) {
+	prev := config.ConsoleConfig
+
+	t.Cleanup(func() { config.ConsoleConfig = prev })
+
+	cfg := &config.Config{}
+	cfg.JWTKey = "test-jwt-key"
+	config.ConsoleConfig = cfg
+
+	engine := gin.New()
+	NewRouter(engine, logger.New("error"), usecase.Usecases{}, cfg)
+
+	public := map[string]bool{
+		"/api/v1/authorize":          true,
+		"/api/v1/authorize/logout":   true,
+		"/api/v1/authorize/password": true,
+		"/api/v1/authorize/refresh":  true,
+		"/api/openapi.json":          true,
+	}
+
+	checked := 0
+
+	for _, route := range engine.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/") || public[route.Path] {
+			continue
+		}
+
+		checked++
+
+		_, ok := rbac.Required(route.Method, ginParam.ReplaceAllString(route.Path, "{$1}"))
+		require.True(t, ok, "no permission for "+route.Method+" "+route.Path)
+	}
+
+	require.NotZero(t, checked)
+}
@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/usecase/accesspolicies"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var ErrValidationAccessPolicies = dto.NotValidError{Console: consoleerrors.CreateConsoleError("AccessPoliciesAPI")}
+
+type accessPolicyRoutes struct {
+	t accesspolicies.Feature
+	l logger.Interface
+}
+
+// NewAccessPolicyRoutes -.
+func NewAccessPolicyRoutes(handler *gin.RouterGroup, t accesspolicies.Feature, l logger.Interface) {
+	r := &accessPolicyRoutes{t, l}
+
+	h := handler.Group("/accesspolicies")
+	{
+		h.GET("", r.get)
+		h.GET(":id", r.getByID)
+		h.POST("", r.insert)
+		h.PUT(":id", r.update)
+		h.DELETE(":id", r.delete)
+	}
+}
+
+type AccessPolicyCountResponse struct {
+	Count int                `json:"totalCount"`
+	Data  []dto.AccessPolicy `json:"data"`
+}
+
+func (r *accessPolicyRoutes) get(c *gin.Context) {
+	var odata OData
+	if err := odata.BindAndValidate(c); err != nil {
+		validationErr := ErrValidationAccessPolicies.Wrap("get", "BindAndValidate", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - get")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	if odata.Count {
+		count, err := r.t.GetCount(c.Request.Context(), Tenant(c))
+		if err != nil {
+			r.l.Error(err, "http - v1 - getCount")
+			ErrorResponse(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, AccessPolicyCountResponse{
+			Count: count,
+			Data:  items,
+		})
+	} else {
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func (r *accessPolicyRoutes) getByID(c *gin.Context) {
+	item, err := r.t.GetByID(c.Request.Context(), c.Param("id"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getByID")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (r *accessPolicyRoutes) insert(c *gin.Context) {
+	var policy dto.AccessPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		validationErr := ErrValidationAccessPolicies.Wrap("insert", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	policy.CreatedBy = Subject(c)
+	policy.TenantID = Tenant(c)
+
+	created, err := r.t.Insert(c.Request.Context(), &policy)
+	if err != nil {
+		r.l.Error(err, "http - v1 - insert")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (r *accessPolicyRoutes) update(c *gin.Context) {
+	var policy dto.AccessPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		validationErr := ErrValidationAccessPolicies.Wrap("update", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	policy.ID = c.Param("id")
+	policy.TenantID = Tenant(c)
+
+	updated, err := r.t.Update(c.Request.Context(), &policy)
+	if err != nil {
+		r.l.Error(err, "http - v1 - update")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (r *accessPolicyRoutes) delete(c *gin.Context) {
+	err := r.t.Delete(c.Request.Context(), c.Param("id"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - delete")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,123 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/accesspolicies"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func TestAccessPolicyRoutes(t *testing.T) {
+	t.Parallel()
+
+	policy := dto.AccessPolicy{ID: "p1", Name: "site-a", Subject: "role:helpdesk", Tags: []string{"site-a"}, Method: "any"}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         interface{}
+		mock         func(feature *mocks.MockAccessPoliciesFeature)
+		response     interface{}
+		expectedCode int
+	}{
+		{
+			name:   "get all policies",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/accesspolicies",
+			mock: func(feature *mocks.MockAccessPoliciesFeature) {
+				feature.EXPECT().Get(context.Background(), 25, 0, "").Return([]dto.AccessPolicy{policy}, nil)
+			},
+			response:     []dto.AccessPolicy{policy},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "get policy - not found",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/accesspolicies/missing",
+			mock: func(feature *mocks.MockAccessPoliciesFeature) {
+				feature.EXPECT().GetByID(context.Background(), "missing", "").Return(nil, accesspolicies.ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "create policy",
+			method: http.MethodPost,
+			url:    "/api/v1/admin/accesspolicies",
+			body:   dto.AccessPolicy{Name: "site-a", Subject: "role:helpdesk", Tags: []string{"site-a"}},
+			mock: func(feature *mocks.MockAccessPoliciesFeature) {
+				feature.EXPECT().
+					Insert(context.Background(), &dto.AccessPolicy{Name: "site-a", Subject: "role:helpdesk", Tags: []string{"site-a"}, CreatedBy: "admin"}).
+					Return(&policy, nil)
+			},
+			response:     policy,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "update policy",
+			method: http.MethodPut,
+			url:    "/api/v1/admin/accesspolicies/p1",
+			body:   dto.AccessPolicy{Name: "site-a", Subject: "role:helpdesk", Tags: []string{"site-a"}},
+			mock: func(feature *mocks.MockAccessPoliciesFeature) {
+				feature.EXPECT().
+					Update(context.Background(), &dto.AccessPolicy{ID: "p1", Name: "site-a", Subject: "role:helpdesk", Tags: []string{"site-a"}}).
+					Return(&policy, nil)
+			},
+			response:     policy,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "delete policy",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/accesspolicies/p1",
+			mock: func(feature *mocks.MockAccessPoliciesFeature) {
+				feature.EXPECT().Delete(context.Background(), "p1", "").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			feature := mocks.NewMockAccessPoliciesFeature(gomock.NewController(t))
+			tc.mock(feature)
+
+			engine := gin.New()
+			handler := engine.Group("/api/v1/admin", func(c *gin.Context) { c.Set(subjectContextKey, "admin") })
+			NewAccessPolicyRoutes(handler, feature, logger.New("error"))
+
+			var body bytes.Buffer
+
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+
+			req, err := http.NewRequest(tc.method, tc.url, &body)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.response != nil {
+				expected, err := json.Marshal(tc.response)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/usecase/apikeys"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var ErrValidationAPIKeys = dto.NotValidError{Console: consoleerrors.CreateConsoleError("APIKeysAPI")}
+
+type apiKeyRoutes struct {
+	t apikeys.Feature
+	l logger.Interface
+}
+
+// NewAPIKeyRoutes -. Keys cannot be edited; revoke one and issue another.
+func NewAPIKeyRoutes(handler *gin.RouterGroup, t apikeys.Feature, l logger.Interface) {
+	r := &apiKeyRoutes{t, l}
+
+	h := handler.Group("/apikeys")
+	{
+		h.GET("", r.get)
+		h.GET(":id", r.getByID)
+		h.POST("", r.insert)
+		h.DELETE(":id", r.delete)
+	}
+}
+
+type APIKeyCountResponse struct {
+	Count int          `json:"totalCount"`
+	Data  []dto.APIKey `json:"data"`
+}
+
+func (r *apiKeyRoutes) get(c *gin.Context) {
+	var odata OData
+	if err := odata.BindAndValidate(c); err != nil {
+		validationErr := ErrValidationAPIKeys.Wrap("get", "BindAndValidate", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - get")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	if odata.Count {
+		count, err := r.t.GetCount(c.Request.Context(), Tenant(c))
+		if err != nil {
+			r.l.Error(err, "http - v1 - getCount")
+			ErrorResponse(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, APIKeyCountResponse{
+			Count: count,
+			Data:  items,
+		})
+	} else {
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func (r *apiKeyRoutes) getByID(c *gin.Context) {
+	item, err := r.t.GetByID(c.Request.Context(), c.Param("id"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getByID")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (r *apiKeyRoutes) insert(c *gin.Context) {
+	var key dto.APIKey
+	if err := c.ShouldBindJSON(&key); err != nil {
+		validationErr := ErrValidationAPIKeys.Wrap("insert", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	if !mayGrant(c, key.Role) {
+		return
+	}
+
+	key.CreatedBy = Subject(c)
+	key.TenantID = Tenant(c)
+
+	created, err := r.t.Insert(c.Request.Context(), &key)
+	if err != nil {
+		r.l.Error(err, "http - v1 - insert")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	// The secret is in this response only; make sure nothing caches it.
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, created)
+}
+
+func (r *apiKeyRoutes) delete(c *gin.Context) {
+	err := r.t.Delete(c.Request.Context(), c.Param("id"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - delete")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
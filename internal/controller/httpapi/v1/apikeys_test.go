@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/apikeys"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+const testAPIKey = apikeys.Prefix + "3f2a9c1b7d4e8a60_c2VjcmV0"
+
+func TestAPIKeyRoutes(t *testing.T) {
+	t.Parallel()
+
+	key := dto.APIKey{ID: "3f2a9c1b7d4e8a60", Name: "ci", Role: "operator"}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         interface{}
+		mock         func(feature *mocks.MockAPIKeysFeature)
+		response     interface{}
+		expectedCode int
+	}{
+		{
+			name:   "get all keys",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/apikeys",
+			mock: func(feature *mocks.MockAPIKeysFeature) {
+				feature.EXPECT().Get(context.Background(), 25, 0, "").Return([]dto.APIKey{key}, nil)
+			},
+			response:     []dto.APIKey{key},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "get key - not found",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/apikeys/missing",
+			mock: func(feature *mocks.MockAPIKeysFeature) {
+				feature.EXPECT().GetByID(context.Background(), "missing", "").Return(nil, apikeys.ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "create key returns the secret once",
+			method: http.MethodPost,
+			url:    "/api/v1/admin/apikeys",
+			body:   dto.APIKey{Name: "ci", Role: "operator"},
+			mock: func(feature *mocks.MockAPIKeysFeature) {
+				feature.EXPECT().
+					Insert(context.Background(), &dto.APIKey{Name: "ci", Role: "operator", CreatedBy: "admin"}).
+					Return(&dto.APIKeyCreated{APIKey: key, Key: testAPIKey}, nil)
+			},
+			response:     dto.APIKeyCreated{APIKey: key, Key: testAPIKey},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "revoke key",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/apikeys/3f2a9c1b7d4e8a60",
+			mock: func(feature *mocks.MockAPIKeysFeature) {
+				feature.EXPECT().Delete(context.Background(), "3f2a9c1b7d4e8a60", "").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			feature := mocks.NewMockAPIKeysFeature(gomock.NewController(t))
+			tc.mock(feature)
+
+			engine := gin.New()
+			handler := engine.Group("/api/v1/admin", func(c *gin.Context) { c.Set(subjectContextKey, "admin") })
+			NewAPIKeyRoutes(handler, feature, logger.New("error"))
+
+			var body bytes.Buffer
+
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+
+			req, err := http.NewRequest(tc.method, tc.url, &body)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.response != nil {
+				expected, err := json.Marshal(tc.response)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), w.Body.String())
+			}
+		})
+	}
+}
+
+// newAPIKeyAuthEngine wires the auth and RBAC middleware in front of routes
+// that echo the caller's subject and, for the device list, its query.
+func newAPIKeyAuthEngine(t *testing.T) (*mocks.MockAPIKeysFeature, *gin.Engine) {
+	t.Helper()
+
+	cfg := cookieAuthTestConfig()
+
+	prev := config.ConsoleConfig
+
+	t.Cleanup(func() { config.ConsoleConfig = prev })
+
+	config.ConsoleConfig = cfg
+
+	feature := mocks.NewMockAPIKeysFeature(gomock.NewController(t))
+	route := LoginRoute{Config: cfg, APIKeys: feature}
+
+	echo := func(c *gin.Context) { c.String(http.StatusOK, Subject(c)+" "+c.Request.URL.RawQuery) }
+
+	engine := gin.New()
+	protected := engine.Group("/api", route.JWTAuthMiddleware(), route.RBACMiddleware())
+	protected.GET("/v1/devices", echo)
+	protected.POST("/v1/amt/power/action/:guid", echo)
+	protected.GET("/v1/amt/power/state/:guid", echo)
+	protected.GET("/v1/admin/domains", echo)
+
+	return feature, engine
+}
+
+func callWithKey(t *testing.T, engine *gin.Engine, method, url string, header string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, http.NoBody)
+	require.NoError(t, err)
+
+	if header == apiKeyHeader {
+		req.Header.Set(apiKeyHeader, testAPIKey)
+	} else {
+		req.Header.Set(authorizationHeader, bearerPrefix+testAPIKey)
+	}
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	return w
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestAPIKeyAuth(t *testing.T) {
+	feature, engine := newAPIKeyAuthEngine(t)
+
+	feature.EXPECT().Authenticate(gomock.Any(), testAPIKey).
+		Return(&dto.APIKey{ID: "k1", Role: "helpdesk"}, nil).Times(3)
+
+	// Bearer and X-API-Key are both accepted; the subject names the key.
+	w := callWithKey(t, engine, http.MethodPost, "/api/v1/amt/power/action/guid-1", authorizationHeader)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "apikey:k1 ", w.Body.String())
+
+	w = callWithKey(t, engine, http.MethodPost, "/api/v1/amt/power/action/guid-1", apiKeyHeader)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// The key's role still applies.
+	w = callWithKey(t, engine, http.MethodGet, "/api/v1/admin/domains", authorizationHeader)
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestAPIKeyAuthRejected(t *testing.T) {
+	feature, engine := newAPIKeyAuthEngine(t)
+
+	feature.EXPECT().Authenticate(gomock.Any(), testAPIKey).Return(nil, apikeys.ErrKeyExpired)
+	feature.EXPECT().Authenticate(gomock.Any(), testAPIKey).Return(nil, apikeys.ErrInvalidKey)
+
+	w := callWithKey(t, engine, http.MethodGet, "/api/v1/devices", authorizationHeader)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), "expired")
+
+	w = callWithKey(t, engine, http.MethodGet, "/api/v1/devices", authorizationHeader)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestAPIKeyRouteScope(t *testing.T) {
+	feature, engine := newAPIKeyAuthEngine(t)
+
+	key := &dto.APIKey{ID: "k1", Role: "admin", Routes: []string{"/api/v1/amt/power/*"}}
+
+	feature.EXPECT().Authenticate(gomock.Any(), testAPIKey).Return(key, nil).Times(2)
+
+	w := callWithKey(t, engine, http.MethodGet, "/api/v1/amt/power/state/guid-1", authorizationHeader)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = callWithKey(t, engine, http.MethodGet, "/api/v1/admin/domains", authorizationHeader)
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), "not scoped to GET /api/v1/admin/domains")
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestAPIKeyTagScope(t *testing.T) {
+	feature, engine := newAPIKeyAuthEngine(t)
+
+	key := &dto.APIKey{ID: "k1", Role: "admin", Tags: []string{"lab", "ci"}}
+
+	feature.EXPECT().Authenticate(gomock.Any(), testAPIKey).Return(key, nil).Times(4)
+	feature.EXPECT().DeviceInScope(gomock.Any(), key, "in-scope").Return(true, nil)
+	feature.EXPECT().DeviceInScope(gomock.Any(), key, "out-of-scope").Return(false, nil)
+
+	w := callWithKey(t, engine, http.MethodPost, "/api/v1/amt/power/action/in-scope", authorizationHeader)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = callWithKey(t, engine, http.MethodPost, "/api/v1/amt/power/action/out-of-scope", authorizationHeader)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// The device list is narrowed to the key's tags, whatever was asked for.
+	w = callWithKey(t, engine, http.MethodGet, "/api/v1/devices?hostname=other&$top=5", authorizationHeader)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "apikey:k1 %24top=5&method=any&tags=lab%2Cci", w.Body.String())
+
+	// Routes not about a single device are refused.
+	w = callWithKey(t, engine, http.MethodGet, "/api/v1/admin/domains", authorizationHeader)
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
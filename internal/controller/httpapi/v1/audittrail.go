@@ -0,0 +1,309 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/internal/usecase/audit"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// maxAuditedBody bounds how much of a request body is kept as parameters.
+// Larger bodies are recorded without them.
+const maxAuditedBody = 64 << 10
+
+var (
+	ErrValidationAudit = dto.NotValidError{Console: consoleerrors.CreateConsoleError("AuditAPI")}
+
+	errAuditFormat = errors.New("format must be csv or json")
+)
+
+// AuditMiddleware records every mutating API call in the audit trail, along
+// with GET routes that act on a device, such as requesting a consent code.
+// It runs before authentication, so refused calls are recorded too. Failing
+// to record is logged and does not fail the call.
+func AuditMiddleware(t audit.Feature, l logger.Interface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Unmatched requests carry no route; they change nothing.
+		if c.FullPath() == "" || !audited(c.Request.Method, routeTemplate(c.FullPath())) {
+			c.Next()
+
+			return
+		}
+
+		body := readBody(c)
+
+		c.Next()
+
+		e := &dto.AuditEvent{
+			Actor:      Subject(c),
+			TenantID:   Tenant(c),
+			Action:     c.Request.Method + " " + routeTemplate(c.FullPath()),
+			Target:     auditTarget(c),
+			Parameters: auditParameters(c, body),
+			Result:     auditResult(c.Writer.Status()),
+			Status:     c.Writer.Status(),
+			SourceIP:   c.ClientIP(),
+		}
+
+		// Logins name their user in the body.
+		if b, ok := e.Parameters["body"].(map[string]interface{}); ok && e.Actor == "" {
+			e.Actor, _ = b["username"].(string)
+		}
+
+		// Recorded even if the client has gone away.
+		if err := t.Record(context.WithoutCancel(c.Request.Context()), e); err != nil {
+			l.Error(err, "http - v1 - audit")
+		}
+	}
+}
+
+func audited(method, route string) bool {
+	if method != http.MethodGet {
+		return method != http.MethodHead && method != http.MethodOptions
+	}
+
+	permission, _ := rbac.Required(method, route)
+
+	return permission == rbac.DevicesControl || permission == rbac.DevicesConfigure || permission == rbac.DevicesErase
+}
+
+// readBody returns the request body, leaving it in place for the handler.
+func readBody(c *gin.Context) []byte {
+	if c.Request.Body == nil || !strings.Contains(c.ContentType(), "json") {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxAuditedBody+1))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), c.Request.Body), c.Request.Body}
+
+	if err != nil || len(body) > maxAuditedBody {
+		return nil
+	}
+
+	return body
+}
+
+// auditTarget is the device the call acts on, else the resource it names.
+func auditTarget(c *gin.Context) string {
+	if guid := c.Param("guid"); guid != "" {
+		return guid
+	}
+
+	if len(c.Params) > 0 {
+		return c.Params[len(c.Params)-1].Value
+	}
+
+	return ""
+}
+
+func auditParameters(c *gin.Context, body []byte) map[string]interface{} {
+	params := map[string]interface{}{}
+
+	if len(c.Params) > 0 {
+		path := map[string]interface{}{}
+		for _, p := range c.Params {
+			path[p.Key] = p.Value
+		}
+
+		params["path"] = path
+	}
+
+	if query := c.Request.URL.Query(); len(query) > 0 {
+		q := map[string]interface{}{}
+		for k, v := range query {
+			q[k] = strings.Join(v, ",")
+		}
+
+		params["query"] = q
+	}
+
+	var decoded interface{}
+	if len(body) > 0 && json.Unmarshal(body, &decoded) == nil {
+		params["body"] = decoded
+	}
+
+	return params
+}
+
+func auditResult(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return audit.ResultDenied
+	case status >= http.StatusBadRequest:
+		return audit.ResultFailure
+	default:
+		return audit.ResultSuccess
+	}
+}
+
+type auditRoutes struct {
+	t audit.Feature
+	l logger.Interface
+}
+
+// NewAuditRoutes -. The trail is read-only; there is no route to change it.
+func NewAuditRoutes(handler *gin.RouterGroup, t audit.Feature, l logger.Interface) {
+	r := &auditRoutes{t, l}
+
+	h := handler.Group("/audit")
+	{
+		h.GET("", r.get)
+		h.GET("export", r.export)
+		h.GET("verify", r.verify)
+	}
+}
+
+type AuditEventCountResponse struct {
+	Count int              `json:"totalCount"`
+	Data  []dto.AuditEvent `json:"data"`
+}
+
+func (r *auditRoutes) filter(c *gin.Context) (dto.AuditFilter, bool) {
+	var filter dto.AuditFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		ErrorResponse(c, ErrValidationAudit.Wrap("filter", "ShouldBindQuery", err))
+
+		return filter, false
+	}
+
+	filter.TenantID = Tenant(c)
+
+	return filter, true
+}
+
+func (r *auditRoutes) get(c *gin.Context) {
+	var odata OData
+	if err := odata.BindAndValidate(c); err != nil {
+		validationErr := ErrValidationAudit.Wrap("get", "BindAndValidate", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	filter, ok := r.filter(c)
+	if !ok {
+		return
+	}
+
+	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, filter)
+	if err != nil {
+		r.l.Error(err, "http - v1 - get")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	if odata.Count {
+		count, err := r.t.GetCount(c.Request.Context(), filter)
+		if err != nil {
+			r.l.Error(err, "http - v1 - getCount")
+			ErrorResponse(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, AuditEventCountResponse{
+			Count: count,
+			Data:  items,
+		})
+	} else {
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+// export downloads every matching entry, oldest first, as CSV or JSON.
+func (r *auditRoutes) export(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		ErrorResponse(c, ErrValidationAudit.Wrap("export", "format", errAuditFormat))
+
+		return
+	}
+
+	filter, ok := r.filter(c)
+	if !ok {
+		return
+	}
+
+	var events []dto.AuditEvent
+
+	for skip := 0; ; skip += MaxPageSize {
+		page, err := r.t.Get(c.Request.Context(), MaxPageSize, skip, filter)
+		if err != nil {
+			r.l.Error(err, "http - v1 - export")
+			ErrorResponse(c, err)
+
+			return
+		}
+
+		events = append(events, page...)
+
+		if len(page) < MaxPageSize {
+			break
+		}
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=console_audit."+format)
+
+	if format == "json" {
+		c.JSON(http.StatusOK, events)
+
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"seq", "timestamp", "actor", "tenantId", "action", "target", "parameters", "result", "status", "sourceIp", "prevHash", "hash"})
+
+	for i := range events {
+		e := &events[i]
+
+		params := ""
+		if len(e.Parameters) > 0 {
+			b, _ := json.Marshal(e.Parameters)
+			params = string(b)
+		}
+
+		_ = w.Write([]string{
+			strconv.FormatInt(e.Seq, DecimalBase), e.Timestamp.Format(time.RFC3339Nano), e.Actor, e.TenantID, e.Action,
+			e.Target, params, e.Result, strconv.Itoa(e.Status), e.SourceIP, e.PrevHash, e.Hash,
+		})
+	}
+
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		r.l.Error(err, "http - v1 - export")
+	}
+}
+
+func (r *auditRoutes) verify(c *gin.Context) {
+	result, err := r.t.Verify(c.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - verify")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
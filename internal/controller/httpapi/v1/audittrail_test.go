@@ -0,0 +1,258 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/audit"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func auditTest(t *testing.T) (*mocks.MockAuditFeature, *gin.Engine) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	feature := mocks.NewMockAuditFeature(mockCtl)
+
+	engine := gin.New()
+	handler := engine.Group("/api/v1/admin")
+
+	NewAuditRoutes(handler, feature, logger.New("error"))
+
+	return feature, engine
+}
+
+func TestAuditRoutes(t *testing.T) {
+	t.Parallel()
+
+	events := []dto.AuditEvent{{
+		Seq:        1,
+		Timestamp:  time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+		Actor:      "jdoe",
+		Action:     "POST /api/v1/amt/power/action/{guid}",
+		Target:     "guid-1",
+		Parameters: map[string]interface{}{"body": map[string]interface{}{"action": float64(10)}},
+		Result:     audit.ResultSuccess,
+		Status:     http.StatusOK,
+		Hash:       "h1",
+	}}
+
+	tests := []struct {
+		name         string
+		url          string
+		mock         func(*mocks.MockAuditFeature)
+		response     interface{}
+		body         string
+		expectedCode int
+	}{
+		{
+			name: "query with filters",
+			url:  "/api/v1/admin/audit?$count=true&actor=jdoe&from=2026-10-19T00:00:00Z",
+			mock: func(f *mocks.MockAuditFeature) {
+				filter := dto.AuditFilter{Actor: "jdoe", From: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)}
+				f.EXPECT().Get(context.Background(), 25, 0, gomock.Cond(func(got dto.AuditFilter) bool {
+					return got.Actor == filter.Actor && got.From.Equal(filter.From)
+				})).Return(events, nil)
+				f.EXPECT().GetCount(context.Background(), gomock.Any()).Return(1, nil)
+			},
+			response:     AuditEventCountResponse{Count: 1, Data: events},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid from",
+			url:          "/api/v1/admin/audit?from=yesterday",
+			mock:         func(*mocks.MockAuditFeature) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "export csv",
+			url:  "/api/v1/admin/audit/export",
+			mock: func(f *mocks.MockAuditFeature) {
+				f.EXPECT().Get(context.Background(), MaxPageSize, 0, dto.AuditFilter{}).Return(events, nil)
+			},
+			body: "seq,timestamp,actor,tenantId,action,target,parameters,result,status,sourceIp,prevHash,hash\n" +
+				`1,2026-10-19T12:00:00Z,jdoe,,POST /api/v1/amt/power/action/{guid},guid-1,"{""body"":{""action"":10}}",success,200,,,h1` + "\n",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "export json",
+			url:  "/api/v1/admin/audit/export?format=json",
+			mock: func(f *mocks.MockAuditFeature) {
+				f.EXPECT().Get(context.Background(), MaxPageSize, 0, dto.AuditFilter{}).Return(events, nil)
+			},
+			response:     events,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "export unknown format",
+			url:          "/api/v1/admin/audit/export?format=xml",
+			mock:         func(*mocks.MockAuditFeature) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "verify",
+			url:  "/api/v1/admin/audit/verify",
+			mock: func(f *mocks.MockAuditFeature) {
+				f.EXPECT().Verify(context.Background()).Return(&dto.AuditVerification{Valid: true, Count: 1, LastHash: "h1"}, nil)
+			},
+			response:     dto.AuditVerification{Valid: true, Count: 1, LastHash: "h1"},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			feature, engine := auditTest(t)
+			tc.mock(feature)
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, tc.url, http.NoBody)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.response != nil {
+				expected, _ := json.Marshal(tc.response)
+				require.JSONEq(t, string(expected), w.Body.String())
+			}
+
+			if tc.body != "" {
+				require.Equal(t, tc.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		status int
+		want   *dto.AuditEvent
+	}{
+		{
+			name:   "power action",
+			method: http.MethodPost,
+			url:    "/api/v1/amt/power/action/guid-1?force=true",
+			body:   `{"action":10}`,
+			status: http.StatusOK,
+			want: &dto.AuditEvent{
+				Actor:  "jdoe",
+				Action: "POST /api/v1/amt/power/action/{guid}",
+				Target: "guid-1",
+				Parameters: map[string]interface{}{
+					"path":  map[string]interface{}{"guid": "guid-1"},
+					"query": map[string]interface{}{"force": "true"},
+					"body":  map[string]interface{}{"action": float64(10)},
+				},
+				Result:   audit.ResultSuccess,
+				Status:   http.StatusOK,
+				SourceIP: "192.0.2.1",
+			},
+		},
+		{
+			name:   "denied profile change",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/profiles/office",
+			status: http.StatusForbidden,
+			want: &dto.AuditEvent{
+				Actor:      "jdoe",
+				Action:     "DELETE /api/v1/admin/profiles/{profileName}",
+				Target:     "office",
+				Parameters: map[string]interface{}{"path": map[string]interface{}{"profileName": "office"}},
+				Result:     audit.ResultDenied,
+				Status:     http.StatusForbidden,
+				SourceIP:   "192.0.2.1",
+			},
+		},
+		{
+			name:   "consent code request acts on the device",
+			method: http.MethodGet,
+			url:    "/api/v1/amt/userConsentCode/guid-1",
+			status: http.StatusOK,
+			want: &dto.AuditEvent{
+				Actor:      "jdoe",
+				Action:     "GET /api/v1/amt/userConsentCode/{guid}",
+				Target:     "guid-1",
+				Parameters: map[string]interface{}{"path": map[string]interface{}{"guid": "guid-1"}},
+				Result:     audit.ResultSuccess,
+				Status:     http.StatusOK,
+				SourceIP:   "192.0.2.1",
+			},
+		},
+		{
+			name:   "reads are not recorded",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/profiles/office",
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtl := gomock.NewController(t)
+			feature := mocks.NewMockAuditFeature(mockCtl)
+
+			var got *dto.AuditEvent
+
+			if tc.want != nil {
+				feature.EXPECT().Record(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *dto.AuditEvent) error {
+					got = e
+
+					return nil
+				})
+			}
+
+			var handlerBody string
+
+			engine := gin.New()
+			engine.Use(func(c *gin.Context) { c.Set(subjectContextKey, "jdoe") }, AuditMiddleware(feature, logger.New("error")))
+
+			handle := func(c *gin.Context) {
+				b, _ := c.GetRawData()
+				handlerBody = string(b)
+
+				c.Status(tc.status)
+			}
+
+			engine.POST("/api/v1/amt/power/action/:guid", handle)
+			engine.DELETE("/api/v1/admin/profiles/:profileName", handle)
+			engine.GET("/api/v1/admin/profiles/:profileName", handle)
+			engine.GET("/api/v1/amt/userConsentCode/:guid", handle)
+
+			req, err := http.NewRequestWithContext(context.Background(), tc.method, tc.url, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.RemoteAddr = "192.0.2.1:51234"
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tc.status, w.Code)
+			require.Equal(t, tc.body, handlerBody, "the handler still reads the body")
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
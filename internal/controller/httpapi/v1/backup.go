@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/usecase/backup"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var ErrValidationBackup = dto.NotValidError{Console: consoleerrors.CreateConsoleError("BackupAPI")}
+
+type backupRoutes struct {
+	t backup.Feature
+	l logger.Interface
+}
+
+// NewBackupRoutes -.
+func NewBackupRoutes(handler *gin.RouterGroup, t backup.Feature, l logger.Interface) {
+	r := &backupRoutes{t, l}
+
+	handler.POST("/backup", r.backup)
+	handler.POST("/restore", r.restore)
+}
+
+func (r *backupRoutes) backup(c *gin.Context) {
+	var req dto.BackupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := ErrValidationBackup.Wrap("backup", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	archive, err := r.t.Backup(c.Request.Context(), req, Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - backup")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=console_backup_"+time.Now().UTC().Format("20060102T150405Z")+".json")
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/json", archive)
+}
+
+func (r *backupRoutes) restore(c *gin.Context) {
+	var req dto.RestoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErr := ErrValidationBackup.Wrap("restore", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	result, err := r.t.Restore(c.Request.Context(), req, Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - restore")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
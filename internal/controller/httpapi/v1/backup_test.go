@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/backup"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func TestBackupRoutes(t *testing.T) {
+	t.Parallel()
+
+	archive := json.RawMessage(`{"format":"device-management-toolkit/console-backup"}`)
+	result := &dto.RestoreResult{Items: []dto.RestoreItem{{Kind: "profile", Name: "acm", Action: dto.RestoreCreated}}}
+
+	tests := []struct {
+		name         string
+		url          string
+		body         interface{}
+		mock         func(feature *mocks.MockBackupFeature)
+		response     interface{}
+		expectedCode int
+	}{
+		{
+			name: "backup",
+			url:  "/api/v1/admin/backup",
+			body: dto.BackupRequest{Passphrase: "correct horse battery", IncludeDevices: true},
+			mock: func(feature *mocks.MockBackupFeature) {
+				feature.EXPECT().
+					Backup(context.Background(), dto.BackupRequest{Passphrase: "correct horse battery", IncludeDevices: true}, "").
+					Return([]byte(archive), nil)
+			},
+			response:     archive,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "backup - short passphrase",
+			url:  "/api/v1/admin/backup",
+			body: dto.BackupRequest{Passphrase: "short"},
+			mock: func(feature *mocks.MockBackupFeature) {
+				feature.EXPECT().
+					Backup(context.Background(), dto.BackupRequest{Passphrase: "short"}, "").
+					Return(nil, backup.ErrNotValid.Wrap("Backup", "validate", errors.New("passphrase too short")))
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "restore",
+			url:  "/api/v1/admin/restore",
+			body: dto.RestoreRequest{Passphrase: "correct horse battery", OnConflict: dto.ConflictRename, Archive: archive},
+			mock: func(feature *mocks.MockBackupFeature) {
+				feature.EXPECT().
+					Restore(context.Background(), dto.RestoreRequest{Passphrase: "correct horse battery", OnConflict: dto.ConflictRename, Archive: archive}, "").
+					Return(result, nil)
+			},
+			response:     result,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "restore - wrong passphrase",
+			url:  "/api/v1/admin/restore",
+			body: dto.RestoreRequest{Passphrase: "wrong horse battery", Archive: archive},
+			mock: func(feature *mocks.MockBackupFeature) {
+				feature.EXPECT().
+					Restore(context.Background(), dto.RestoreRequest{Passphrase: "wrong horse battery", Archive: archive}, "").
+					Return(nil, backup.ErrNotValid.Wrap("Restore", "open", errors.New("cannot decrypt archive")))
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			feature := mocks.NewMockBackupFeature(gomock.NewController(t))
+			tc.mock(feature)
+
+			engine := gin.New()
+			handler := engine.Group("/api/v1/admin")
+			NewBackupRoutes(handler, feature, logger.New("error"))
+
+			var body bytes.Buffer
+
+			require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+
+			req, err := http.NewRequest(http.MethodPost, tc.url, &body)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.response != nil {
+				expected, err := json.Marshal(tc.response)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), w.Body.String())
+			}
+		})
+	}
+}
@@ -19,7 +19,7 @@ type ciraConfigRoutes struct {
 func NewCIRAConfigRoutes(handler *gin.RouterGroup, t ciraconfigs.Feature, l logger.Interface, cfg *config.Config) {
 	r := &ciraConfigRoutes{t, l}
 
-	h := handler.Group("/ciraconfigs")
+	h := handler.Group("/ciraconfigs", IfMatchMiddleware())
 	h.Use(ciraDisabledMiddleware(cfg.DisableCIRA))
 	{
 		h.GET("", r.get)
@@ -32,14 +32,14 @@ func NewCIRAConfigRoutes(handler *gin.RouterGroup, t ciraconfigs.Feature, l logg
 
 func (r *ciraConfigRoutes) get(c *gin.Context) {
 	var odata OData
-	if err := odata.BindAndValidate(c); err != nil {
+	if err := odata.BindQuery(c, ciraConfigQueryFields); err != nil {
 		r.l.Error(err, "http - CIRA configs - v1 - get")
 		ErrorResponse(c, err)
 
 		return
 	}
 
-	configs, err := r.cira.Get(c.Request.Context(), odata.Top, odata.Skip, "")
+	configs, err := r.cira.Get(c.Request.Context(), odata.Top, odata.Skip, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - CIRA configs - v1 - get")
 		ErrorResponse(c, err)
@@ -48,7 +48,7 @@ func (r *ciraConfigRoutes) get(c *gin.Context) {
 	}
 
 	if odata.Count {
-		count, err := r.cira.GetCount(c.Request.Context(), "")
+		count, err := r.cira.GetCount(c.Request.Context(), Tenant(c))
 		if err != nil {
 			r.l.Error(err, "http - CIRA configs - v1 - getCount")
 			ErrorResponse(c, err)
@@ -61,16 +61,16 @@ func (r *ciraConfigRoutes) get(c *gin.Context) {
 			Data:  configs,
 		}
 
-		c.JSON(http.StatusOK, countResponse)
+		odata.JSON(c, countResponse)
 	} else {
-		c.JSON(http.StatusOK, configs)
+		odata.JSON(c, configs)
 	}
 }
 
 func (r *ciraConfigRoutes) getByName(c *gin.Context) {
 	configName := c.Param("ciraConfigName")
 
-	foundConfig, err := r.cira.GetByName(c.Request.Context(), configName, "")
+	foundConfig, err := r.cira.GetByName(c.Request.Context(), configName, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - CIRA configs - v1 - getByName")
 		ErrorResponse(c, err)
@@ -78,6 +78,7 @@ func (r *ciraConfigRoutes) getByName(c *gin.Context) {
 		return
 	}
 
+	setETag(c, foundConfig.Version)
 	c.JSON(http.StatusOK, foundConfig)
 }
 
@@ -90,6 +91,8 @@ func (r *ciraConfigRoutes) insert(c *gin.Context) {
 		return
 	}
 
+	ciraConfig.TenantID = Tenant(c)
+
 	newCiraConfig, err := r.cira.Insert(c.Request.Context(), &ciraConfig)
 	if err != nil {
 		r.l.Error(err, "http - CIRA configs - v1 - insert")
@@ -98,6 +101,7 @@ func (r *ciraConfigRoutes) insert(c *gin.Context) {
 		return
 	}
 
+	setETag(c, newCiraConfig.Version)
 	c.JSON(http.StatusCreated, newCiraConfig)
 }
 
@@ -110,6 +114,8 @@ func (r *ciraConfigRoutes) update(c *gin.Context) {
 		return
 	}
 
+	ciraConfig.TenantID = Tenant(c)
+
 	updatedConfig, err := r.cira.Update(c.Request.Context(), &ciraConfig)
 	if err != nil {
 		r.l.Error(err, "http - CIRA configs - v1 - update")
@@ -118,13 +124,14 @@ func (r *ciraConfigRoutes) update(c *gin.Context) {
 		return
 	}
 
+	setETag(c, updatedConfig.Version)
 	c.JSON(http.StatusOK, updatedConfig)
 }
 
 func (r *ciraConfigRoutes) delete(c *gin.Context) {
 	configName := c.Param("ciraConfigName")
 
-	err := r.cira.Delete(c.Request.Context(), configName, "")
+	err := r.cira.Delete(c.Request.Context(), configName, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - CIRA configs - v1 - delete")
 		ErrorResponse(c, err)
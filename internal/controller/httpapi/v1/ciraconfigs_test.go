@@ -48,7 +48,7 @@ type ciraconfigTest struct {
 
 var (
 	requestCIRAConfig  = dto.CIRAConfig{ConfigName: "ciraconfig", MPSAddress: "https://example.com", MPSPort: 4433, Username: "username", Password: "password", CommonName: "example.com", ServerAddressFormat: 201, AuthMethod: 2, MPSRootCertificate: "-----BEGIN CERTIFICATE-----\n...", ProxyDetails: "http://example.com", TenantID: "abc123", GenerateRandomPassword: true, Version: "1.0.0"}
-	responseCIRAConfig = dto.CIRAConfig{ConfigName: "ciraconfig", MPSAddress: "https://example.com", MPSPort: 4433, Username: "username", Password: "password", CommonName: "example.com", ServerAddressFormat: 201, AuthMethod: 2, MPSRootCertificate: "-----BEGIN CERTIFICATE-----\n...", ProxyDetails: "http://example.com", TenantID: "abc123", GenerateRandomPassword: true, Version: "1.0.0"}
+	responseCIRAConfig = dto.CIRAConfig{ConfigName: "ciraconfig", MPSAddress: "https://example.com", MPSPort: 4433, Username: "username", Password: "password", CommonName: "example.com", ServerAddressFormat: 201, AuthMethod: 2, MPSRootCertificate: "-----BEGIN CERTIFICATE-----\n...", ProxyDetails: "http://example.com", TenantID: "", GenerateRandomPassword: true, Version: "1.0.0"}
 )
 
 func TestCIRAConfigRoutes(t *testing.T) {
@@ -128,7 +128,7 @@ func TestCIRAConfigRoutes(t *testing.T) {
 					AuthMethod:             2,
 					MPSRootCertificate:     "-----BEGIN CERTIFICATE-----\n...",
 					ProxyDetails:           "http://example.com",
-					TenantID:               "abc123",
+					TenantID:               "",
 					GenerateRandomPassword: true,
 					Version:                "1.0.0",
 				}
@@ -154,7 +154,7 @@ func TestCIRAConfigRoutes(t *testing.T) {
 					AuthMethod:             2,
 					MPSRootCertificate:     "-----BEGIN CERTIFICATE-----\n...",
 					ProxyDetails:           "http://example.com",
-					TenantID:               "abc123",
+					TenantID:               "",
 					GenerateRandomPassword: true,
 					Version:                "1.0.0",
 				}
@@ -175,7 +175,7 @@ func TestCIRAConfigRoutes(t *testing.T) {
 					AuthMethod:             2,
 					MPSRootCertificate:     "-----BEGIN CERTIFICATE-----\n...",
 					ProxyDetails:           "http://example.com",
-					TenantID:               "abc123",
+					TenantID:               "",
 					GenerateRandomPassword: true,
 					Version:                "1.0.0",
 				}
@@ -221,7 +221,7 @@ func TestCIRAConfigRoutes(t *testing.T) {
 					AuthMethod:             2,
 					MPSRootCertificate:     "-----BEGIN CERTIFICATE-----\n...",
 					ProxyDetails:           "http://example.com",
-					TenantID:               "abc123",
+					TenantID:               "",
 					GenerateRandomPassword: true,
 					Version:                "1.0.0",
 				}
@@ -247,7 +247,7 @@ func TestCIRAConfigRoutes(t *testing.T) {
 					AuthMethod:             2,
 					MPSRootCertificate:     "-----BEGIN CERTIFICATE-----\n...",
 					ProxyDetails:           "http://example.com",
-					TenantID:               "abc123",
+					TenantID:               "",
 					GenerateRandomPassword: true,
 					Version:                "1.0.0",
 				}
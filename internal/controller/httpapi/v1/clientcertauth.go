@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"crypto/x509"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/clientcert"
+	"github.com/device-management-toolkit/console/internal/rbac"
+)
+
+// clientCertificate returns the leaf of the request's verified TLS client
+// certificate chain, or nil when the client presented none.
+func clientCertificate(c *gin.Context) *x509.Certificate {
+	if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 || len(c.Request.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	return c.Request.TLS.VerifiedChains[0][0]
+}
+
+// clientCertAuth authenticates a request without a token as the identity
+// its client certificate maps to. It stands in for the JWT checks in
+// JWTAuthMiddleware.
+func (lr LoginRoute) clientCertAuth(c *gin.Context, cert *x509.Certificate) {
+	id, ok := clientcert.Identify(cert, lr.Config.TLS.ClientCerts.Identities)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{errorKey: "client certificate maps to no identity"})
+
+		return
+	}
+
+	c.Set(subjectContextKey, id.Username)
+
+	if role, ok := rbac.ParseRole(id.Role); ok {
+		c.Set(rolesContextKey, []rbac.Role{role})
+	}
+
+	if !lr.setTenant(c, id.TenantID, true) {
+		return
+	}
+
+	c.Next()
+}
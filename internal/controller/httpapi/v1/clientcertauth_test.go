@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/config"
+)
+
+func newClientCertAuthEngine(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	cfg := cookieAuthTestConfig()
+	cfg.TLS.ClientCerts = config.ClientCerts{
+		CAFile: "ca.pem",
+		Identities: []config.ClientCertIdentity{
+			{Match: "dns:ci.example.org", Username: "ci-runner", Role: "helpdesk"},
+			{Match: "cn:inventory", Username: "inventory", Role: "read-only", TenantID: "tenant-a"},
+		},
+	}
+
+	prev := config.ConsoleConfig
+
+	t.Cleanup(func() { config.ConsoleConfig = prev })
+
+	config.ConsoleConfig = cfg
+
+	route := LoginRoute{Config: cfg}
+
+	echo := func(c *gin.Context) { c.String(http.StatusOK, Subject(c)+" "+Tenant(c)) }
+
+	engine := gin.New()
+	protected := engine.Group("/api", route.JWTAuthMiddleware(), route.RBACMiddleware())
+	protected.GET("/v1/devices", echo)
+	protected.POST("/v1/amt/power/action/:guid", echo)
+
+	return engine
+}
+
+// callWithCert calls the engine as if over TLS with a verified client
+// certificate for subject.
+func callWithCert(t *testing.T, engine *gin.Engine, method, url string, cert *x509.Certificate, header string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, http.NoBody)
+	require.NoError(t, err)
+
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+
+	if header != "" {
+		req.Header.Set(authorizationHeader, header)
+	}
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	return w
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestClientCertAuth(t *testing.T) {
+	engine := newClientCertAuthEngine(t)
+
+	ci := &x509.Certificate{Subject: pkix.Name{CommonName: "runner-7"}, DNSNames: []string{"ci.example.org"}}
+	inventory := &x509.Certificate{Subject: pkix.Name{CommonName: "inventory"}}
+	stranger := &x509.Certificate{Subject: pkix.Name{CommonName: "stranger"}}
+
+	w := callWithCert(t, engine, http.MethodPost, "/api/v1/amt/power/action/guid-1", ci, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "ci-runner ", w.Body.String())
+
+	// The identity's role and tenant apply.
+	w = callWithCert(t, engine, http.MethodPost, "/api/v1/amt/power/action/guid-1", inventory, "")
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	w = callWithCert(t, engine, http.MethodGet, "/api/v1/devices", inventory, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "inventory tenant-a", w.Body.String())
+
+	w = callWithCert(t, engine, http.MethodGet, "/api/v1/devices", stranger, "")
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), "maps to no identity")
+
+	// A bearer token wins over the certificate.
+	token := signedToken(t, jwt.MapClaims{"sub": testAdminUser, "roles": []string{"read-only"}})
+
+	w = callWithCert(t, engine, http.MethodGet, "/api/v1/devices", stranger, bearerPrefix+token)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, testAdminUser+" ", w.Body.String())
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestClientCertAuthDisabled(t *testing.T) {
+	engine := newClientCertAuthEngine(t)
+	config.ConsoleConfig.TLS.ClientCerts = config.ClientCerts{}
+
+	ci := &x509.Certificate{DNSNames: []string{"ci.example.org"}}
+
+	require.Equal(t, http.StatusUnauthorized, callWithCert(t, engine, http.MethodGet, "/api/v1/devices", ci, "").Code)
+}
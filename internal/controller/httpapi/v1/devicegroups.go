@@ -0,0 +1,232 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/usecase/devicegroups"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var ErrValidationDeviceGroups = dto.NotValidError{Console: consoleerrors.CreateConsoleError("DeviceGroupsAPI")}
+
+type deviceGroupRoutes struct {
+	t devicegroups.Feature
+	l logger.Interface
+}
+
+// NewDeviceGroupRoutes -.
+func NewDeviceGroupRoutes(handler *gin.RouterGroup, t devicegroups.Feature, l logger.Interface) {
+	r := &deviceGroupRoutes{t, l}
+
+	h := handler.Group("/groups")
+	{
+		h.GET("", r.get)
+		h.GET(":id", r.getByID)
+		h.POST("", r.insert)
+		h.PUT(":id", r.update)
+		h.DELETE(":id", r.delete)
+		h.GET(":id/members", r.getMembers)
+		h.POST(":id/members", r.addMembers)
+		h.DELETE(":id/members/:guid", r.removeMember)
+		h.POST(":id/refresh", r.refresh)
+	}
+}
+
+type DeviceGroupCountResponse struct {
+	Count int               `json:"totalCount"`
+	Data  []dto.DeviceGroup `json:"data"`
+}
+
+type DeviceGroupMemberCountResponse struct {
+	Count int          `json:"totalCount"`
+	Data  []dto.Device `json:"data"`
+}
+
+func (r *deviceGroupRoutes) get(c *gin.Context) {
+	var odata OData
+	if err := odata.BindAndValidate(c); err != nil {
+		validationErr := ErrValidationDeviceGroups.Wrap("get", "BindAndValidate", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - get")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	if odata.Count {
+		count, err := r.t.GetCount(c.Request.Context(), Tenant(c))
+		if err != nil {
+			r.l.Error(err, "http - v1 - getCount")
+			ErrorResponse(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, DeviceGroupCountResponse{
+			Count: count,
+			Data:  items,
+		})
+	} else {
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func (r *deviceGroupRoutes) getByID(c *gin.Context) {
+	item, err := r.t.GetByID(c.Request.Context(), c.Param("id"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getByID")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (r *deviceGroupRoutes) insert(c *gin.Context) {
+	var group dto.DeviceGroup
+	if err := c.ShouldBindJSON(&group); err != nil {
+		validationErr := ErrValidationDeviceGroups.Wrap("insert", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	group.CreatedBy = Subject(c)
+	group.TenantID = Tenant(c)
+
+	created, err := r.t.Insert(c.Request.Context(), &group)
+	if err != nil {
+		r.l.Error(err, "http - v1 - insert")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (r *deviceGroupRoutes) update(c *gin.Context) {
+	var group dto.DeviceGroup
+	if err := c.ShouldBindJSON(&group); err != nil {
+		validationErr := ErrValidationDeviceGroups.Wrap("update", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	group.ID = c.Param("id")
+	group.TenantID = Tenant(c)
+
+	updated, err := r.t.Update(c.Request.Context(), &group)
+	if err != nil {
+		r.l.Error(err, "http - v1 - update")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (r *deviceGroupRoutes) delete(c *gin.Context) {
+	err := r.t.Delete(c.Request.Context(), c.Param("id"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - delete")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// getMembers lists the devices of the group and of its subgroups.
+func (r *deviceGroupRoutes) getMembers(c *gin.Context) {
+	var odata OData
+	if err := odata.BindAndValidate(c); err != nil {
+		validationErr := ErrValidationDeviceGroups.Wrap("getMembers", "BindAndValidate", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	items, err := r.t.GetMembers(c.Request.Context(), c.Param("id"), odata.Top, odata.Skip, Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getMembers")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	if odata.Count {
+		count, err := r.t.GetMemberCount(c.Request.Context(), c.Param("id"), Tenant(c))
+		if err != nil {
+			r.l.Error(err, "http - v1 - getMemberCount")
+			ErrorResponse(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, DeviceGroupMemberCountResponse{
+			Count: count,
+			Data:  items,
+		})
+	} else {
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func (r *deviceGroupRoutes) addMembers(c *gin.Context) {
+	var selection dto.DeviceSelection
+	if err := c.ShouldBindJSON(&selection); err != nil {
+		validationErr := ErrValidationDeviceGroups.Wrap("addMembers", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	added, err := r.t.AddMembers(c.Request.Context(), c.Param("id"), selection, Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - addMembers")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeviceGroupMembersAdded{Added: added})
+}
+
+func (r *deviceGroupRoutes) removeMember(c *gin.Context) {
+	err := r.t.RemoveMember(c.Request.Context(), c.Param("id"), c.Param("guid"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - removeMember")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (r *deviceGroupRoutes) refresh(c *gin.Context) {
+	err := r.t.Refresh(c.Request.Context(), c.Param("id"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - refresh")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/devicegroups"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func TestDeviceGroupRoutes(t *testing.T) {
+	t.Parallel()
+
+	rules := []dto.DeviceGroupRule{{Field: "hostname", Operator: "match", Value: "lab-*"}}
+	group := dto.DeviceGroup{ID: "g1", Name: "lab", Rules: rules}
+	device := dto.Device{GUID: "guid", Hostname: "lab-1"}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         interface{}
+		mock         func(feature *mocks.MockDeviceGroupsFeature)
+		response     interface{}
+		expectedCode int
+	}{
+		{
+			name:   "get all groups",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/groups?$count=true",
+			mock: func(feature *mocks.MockDeviceGroupsFeature) {
+				feature.EXPECT().Get(context.Background(), 25, 0, "").Return([]dto.DeviceGroup{group}, nil)
+				feature.EXPECT().GetCount(context.Background(), "").Return(1, nil)
+			},
+			response:     DeviceGroupCountResponse{Count: 1, Data: []dto.DeviceGroup{group}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "get group - not found",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/groups/missing",
+			mock: func(feature *mocks.MockDeviceGroupsFeature) {
+				feature.EXPECT().GetByID(context.Background(), "missing", "").Return(nil, devicegroups.ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "create group",
+			method: http.MethodPost,
+			url:    "/api/v1/admin/groups",
+			body:   dto.DeviceGroup{Name: "lab", Rules: rules},
+			mock: func(feature *mocks.MockDeviceGroupsFeature) {
+				feature.EXPECT().
+					Insert(context.Background(), &dto.DeviceGroup{Name: "lab", Rules: rules, CreatedBy: "admin"}).
+					Return(&group, nil)
+			},
+			response:     group,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "update group",
+			method: http.MethodPut,
+			url:    "/api/v1/admin/groups/g1",
+			body:   dto.DeviceGroup{Name: "lab", Rules: rules},
+			mock: func(feature *mocks.MockDeviceGroupsFeature) {
+				feature.EXPECT().
+					Update(context.Background(), &dto.DeviceGroup{ID: "g1", Name: "lab", Rules: rules}).
+					Return(&group, nil)
+			},
+			response:     group,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "delete group",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/groups/g1",
+			mock: func(feature *mocks.MockDeviceGroupsFeature) {
+				feature.EXPECT().Delete(context.Background(), "g1", "").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "get members",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/groups/g1/members?$top=10&$count=true",
+			mock: func(feature *mocks.MockDeviceGroupsFeature) {
+				feature.EXPECT().GetMembers(context.Background(), "g1", 10, 0, "").Return([]dto.Device{device}, nil)
+				feature.EXPECT().GetMemberCount(context.Background(), "g1", "").Return(1, nil)
+			},
+			response:     DeviceGroupMemberCountResponse{Count: 1, Data: []dto.Device{device}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "add members",
+			method: http.MethodPost,
+			url:    "/api/v1/admin/groups/g1/members",
+			body:   dto.DeviceSelection{GUIDs: []string{"guid"}, Groups: []string{"g2"}},
+			mock: func(feature *mocks.MockDeviceGroupsFeature) {
+				feature.EXPECT().
+					AddMembers(context.Background(), "g1", dto.DeviceSelection{GUIDs: []string{"guid"}, Groups: []string{"g2"}}, "").
+					Return(2, nil)
+			},
+			response:     dto.DeviceGroupMembersAdded{Added: 2},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "remove member",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/groups/g1/members/guid",
+			mock: func(feature *mocks.MockDeviceGroupsFeature) {
+				feature.EXPECT().RemoveMember(context.Background(), "g1", "guid", "").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "refresh group",
+			method: http.MethodPost,
+			url:    "/api/v1/admin/groups/g1/refresh",
+			mock: func(feature *mocks.MockDeviceGroupsFeature) {
+				feature.EXPECT().Refresh(context.Background(), "g1", "").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			feature := mocks.NewMockDeviceGroupsFeature(gomock.NewController(t))
+			tc.mock(feature)
+
+			engine := gin.New()
+			handler := engine.Group("/api/v1/admin", func(c *gin.Context) { c.Set(subjectContextKey, "admin") })
+			NewDeviceGroupRoutes(handler, feature, logger.New("error"))
+
+			var body bytes.Buffer
+
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+
+			req, err := http.NewRequest(tc.method, tc.url, &body)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.response != nil {
+				expected, err := json.Marshal(tc.response)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), w.Body.String())
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ func NewAmtRoutes(handler *gin.RouterGroup, d devices.Feature, amt amtexplorer.F
 	r.registerNetworkRoutes(h)
 	r.registerExplorerAndCertificateRoutes(h)
 	r.registerKVMAndLinkRoutes(h)
+	r.registerSOLCaptureRoutes(h)
 }
 
 func (r *deviceManagementRoutes) registerCoreRoutes(h *gin.RouterGroup) {
@@ -89,6 +90,13 @@ func (r *deviceManagementRoutes) registerExplorerAndCertificateRoutes(h *gin.Rou
 func (r *deviceManagementRoutes) registerKVMAndLinkRoutes(h *gin.RouterGroup) {
 	h.GET("kvm/displays/:guid", r.getKVMDisplays)
 	h.PUT("kvm/displays/:guid", r.setKVMDisplays)
+	h.GET("kvm/screenshot/:guid", r.getKVMScreenshot)
 
 	h.POST("network/linkPreference/:guid", r.setLinkPreference)
 }
+
+func (r *deviceManagementRoutes) registerSOLCaptureRoutes(h *gin.RouterGroup) {
+	h.POST("sol/capture/:guid", r.startSOLCapture)
+	h.GET("sol/capture/:guid", r.getSOLCaptures)
+	h.GET("sol/capture/:guid/:id", r.getSOLCapture)
+}
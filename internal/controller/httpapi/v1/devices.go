@@ -10,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 
 	"github.com/device-management-toolkit/console/config"
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
@@ -30,7 +31,7 @@ func NewDeviceRoutes(handler *gin.RouterGroup, t devices.Feature, l logger.Inter
 
 	handler.GET("authorize/redirection/:id", r.LoginRedirection)
 
-	h := handler.Group("/devices")
+	h := handler.Group("/devices", IfMatchMiddleware())
 	{
 		h.GET("", r.get)
 		h.GET("stats", r.getStats)
@@ -47,7 +48,7 @@ func NewDeviceRoutes(handler *gin.RouterGroup, t devices.Feature, l logger.Inter
 }
 
 func (dr *deviceRoutes) getStats(c *gin.Context) {
-	count, err := dr.t.GetCount(c.Request.Context(), "")
+	count, err := dr.t.GetCount(c.Request.Context(), Tenant(c))
 	if err != nil {
 		dr.l.Error(err, "http - devices - v1 - getCount")
 		ErrorResponse(c, err)
@@ -65,7 +66,7 @@ func (dr *deviceRoutes) getStats(c *gin.Context) {
 func (dr *deviceRoutes) LoginRedirection(c *gin.Context) {
 	deviceID := c.Param("id")
 
-	_, err := dr.t.GetByID(c.Request.Context(), deviceID, "", false)
+	_, err := dr.t.GetByID(c.Request.Context(), deviceID, Tenant(c), false)
 	if err != nil {
 		dr.l.Error(err, "http - devices - v1 - LoginRedirection")
 		ErrorResponse(c, err)
@@ -78,7 +79,21 @@ func (dr *deviceRoutes) LoginRedirection(c *gin.Context) {
 	claims := jwt.MapClaims{
 		"exp":      expirationTime.Unix(),
 		"iss":      config.ConsoleConfig.Issuer,
+		"aud":      redirectionAudience,
 		"deviceId": strings.ToLower(deviceID),
+		// The websocket looks the device up in the tenant it was found in.
+		"tenantId": Tenant(c),
+		"jti":      uuid.NewString(),
+	}
+
+	// Carried through so the session is attributed to the requesting user.
+	if sub := Subject(c); sub != "" {
+		claims["sub"] = sub
+	}
+
+	// Ending the login session also revokes redirection tokens issued in it.
+	if sid := Session(c); sid != "" {
+		claims["sid"] = sid
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -95,7 +110,7 @@ func (dr *deviceRoutes) LoginRedirection(c *gin.Context) {
 
 func (dr *deviceRoutes) get(c *gin.Context) {
 	var odata OData
-	if err := odata.BindAndValidate(c); err != nil {
+	if err := odata.BindQuery(c, deviceQueryFields); err != nil {
 		ErrorResponse(c, err)
 
 		return
@@ -105,22 +120,28 @@ func (dr *deviceRoutes) get(c *gin.Context) {
 	hostname := c.Query("hostname")
 	friendlyName := c.Query("friendlyName")
 
+	if odata.Filtered() && (tags != "" || hostname != "" || friendlyName != "") {
+		ErrorResponse(c, errFilterWithSearch)
+
+		return
+	}
+
 	var items []dto.Device
 
 	var err error
 
 	switch {
 	case hostname != "":
-		items, err = dr.getByColumnOrTags(c, "HostName", hostname, odata.Top, odata.Skip, "")
+		items, err = dr.getByColumnOrTags(c, "HostName", hostname, odata.Top, odata.Skip, Tenant(c))
 
 	case friendlyName != "":
-		items, err = dr.getByColumnOrTags(c, "FriendlyName", friendlyName, odata.Top, odata.Skip, "")
+		items, err = dr.getByColumnOrTags(c, "FriendlyName", friendlyName, odata.Top, odata.Skip, Tenant(c))
 
 	case tags != "":
-		items, err = dr.getByColumnOrTags(c, "Tags", tags, odata.Top, odata.Skip, "")
+		items, err = dr.getByColumnOrTags(c, "Tags", tags, odata.Top, odata.Skip, Tenant(c))
 
 	default:
-		items, err = dr.t.Get(c.Request.Context(), odata.Top, odata.Skip, "")
+		items, err = dr.t.Get(c.Request.Context(), odata.Top, odata.Skip, Tenant(c))
 	}
 
 	if err != nil {
@@ -131,7 +152,7 @@ func (dr *deviceRoutes) get(c *gin.Context) {
 	}
 
 	if odata.Count {
-		count, err := dr.t.GetCount(c.Request.Context(), "")
+		count, err := dr.t.GetCount(c.Request.Context(), Tenant(c))
 		if err != nil {
 			dr.l.Error(err, "http - devices - v1 - get")
 			ErrorResponse(c, err)
@@ -144,9 +165,9 @@ func (dr *deviceRoutes) get(c *gin.Context) {
 			Data:  items,
 		}
 
-		c.JSON(http.StatusOK, countResponse)
+		odata.JSON(c, countResponse)
 	} else {
-		c.JSON(http.StatusOK, items)
+		odata.JSON(c, items)
 	}
 }
 
@@ -159,7 +180,7 @@ func (dr *deviceRoutes) getByColumnOrTags(c *gin.Context, column, value string,
 	if column == "Tags" {
 		items, err = dr.t.GetByTags(ctx, value, c.Query("method"), limit, skip, tenantID)
 	} else {
-		items, err = dr.t.GetByColumn(ctx, column, value, "")
+		items, err = dr.t.GetByColumn(ctx, column, value, tenantID)
 	}
 
 	if err != nil {
@@ -179,7 +200,7 @@ func (dr *deviceRoutes) getByID(c *gin.Context) {
 
 	guid := c.Param("guid")
 
-	item, err := dr.t.GetByID(c.Request.Context(), guid, "", false)
+	item, err := dr.t.GetByID(c.Request.Context(), guid, Tenant(c), false)
 	if err != nil {
 		dr.l.Error(err, "http - devices - v1 - get")
 		ErrorResponse(c, err)
@@ -187,6 +208,7 @@ func (dr *deviceRoutes) getByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, item.Version)
 	c.JSON(http.StatusOK, item)
 }
 
@@ -227,6 +249,8 @@ func (dr *deviceRoutes) insert(c *gin.Context) {
 		device.AllowSelfSigned = true
 	}
 
+	device.TenantID = Tenant(c)
+
 	newDevice, err := dr.t.Insert(c.Request.Context(), &device)
 	if err != nil {
 		dr.l.Error(err, "http - devices - v1 - insert")
@@ -235,6 +259,7 @@ func (dr *deviceRoutes) insert(c *gin.Context) {
 		return
 	}
 
+	setETag(c, newDevice.Version)
 	c.JSON(http.StatusCreated, newDevice)
 }
 
@@ -321,6 +346,8 @@ func (dr *deviceRoutes) update(c *gin.Context) {
 		return
 	}
 
+	device.TenantID = Tenant(c)
+
 	updatedDevice, err := dr.t.Update(c.Request.Context(), &device, fields)
 	if err != nil {
 		dr.l.Error(err, "http - devices - v1 - update")
@@ -329,13 +356,14 @@ func (dr *deviceRoutes) update(c *gin.Context) {
 		return
 	}
 
+	setETag(c, updatedDevice.Version)
 	c.JSON(http.StatusOK, updatedDevice)
 }
 
 func (dr *deviceRoutes) delete(c *gin.Context) {
 	guid := c.Param("guid")
 
-	err := dr.t.Delete(c.Request.Context(), guid, "")
+	err := dr.t.Delete(c.Request.Context(), guid, Tenant(c))
 	if err != nil {
 		dr.l.Error(err, "http - devices - v1 - delete")
 		ErrorResponse(c, err)
@@ -356,7 +384,7 @@ func (dr *deviceRoutes) redirectStatus(c *gin.Context) {
 }
 
 func (dr *deviceRoutes) getTags(c *gin.Context) {
-	tags, err := dr.t.GetDistinctTags(c.Request.Context(), "")
+	tags, err := dr.t.GetDistinctTags(c.Request.Context(), Tenant(c))
 	if err != nil {
 		dr.l.Error(err, "http - devices - v1 - tags")
 		ErrorResponse(c, err)
@@ -377,7 +405,7 @@ func (dr *deviceRoutes) getDeviceCertificate(c *gin.Context) {
 
 	guid := c.Param("guid")
 
-	item, err := dr.t.GetByID(c.Request.Context(), guid, "", false)
+	item, err := dr.t.GetByID(c.Request.Context(), guid, Tenant(c), false)
 	if err != nil {
 		dr.l.Error(err, "http - devices - v1 - cert")
 		ErrorResponse(c, err)
@@ -408,7 +436,7 @@ func (dr *deviceRoutes) pinDeviceCertificate(c *gin.Context) {
 
 	guid := c.Param("guid")
 
-	item, err := dr.t.GetByID(c.Request.Context(), guid, "", true)
+	item, err := dr.t.GetByID(c.Request.Context(), guid, Tenant(c), true)
 	if err != nil {
 		dr.l.Error(err, "http - devices - v1 - deleteDeviceCertificate - getById")
 		ErrorResponse(c, err)
@@ -439,7 +467,7 @@ func (dr *deviceRoutes) deleteDeviceCertificate(c *gin.Context) {
 
 	guid := c.Param("guid")
 
-	item, err := dr.t.GetByID(c.Request.Context(), guid, "", true)
+	item, err := dr.t.GetByID(c.Request.Context(), guid, Tenant(c), true)
 	if err != nil {
 		dr.l.Error(err, "http - devices - v1 - deleteDeviceCertificate - getById")
 		ErrorResponse(c, err)
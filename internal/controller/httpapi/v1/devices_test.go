@@ -69,7 +69,7 @@ type deviceTest struct {
 var (
 	timeNow        = time.Now().UTC()
 	requestDevice  = dto.Device{ConnectionStatus: true, MPSInstance: "mpsInstance", Hostname: "hostname", GUID: "guid", MPSUsername: "mpsusername", Tags: []string{"tag1", "tag2"}, TenantID: "tenantId", FriendlyName: "friendlyName", DNSSuffix: "dnsSuffix", Username: "admin", Password: "password", UseTLS: true, AllowSelfSigned: true, LastConnected: &timeNow, LastSeen: &timeNow, LastDisconnected: &timeNow}
-	responseDevice = dto.Device{ConnectionStatus: true, MPSInstance: "mpsInstance", Hostname: "hostname", GUID: "guid", MPSUsername: "mpsusername", Tags: []string{"tag1", "tag2"}, TenantID: "tenantId", FriendlyName: "friendlyName", DNSSuffix: "dnsSuffix", Username: "admin", Password: "password", UseTLS: true, AllowSelfSigned: true, LastConnected: &timeNow, LastSeen: &timeNow, LastDisconnected: &timeNow}
+	responseDevice = dto.Device{ConnectionStatus: true, MPSInstance: "mpsInstance", Hostname: "hostname", GUID: "guid", MPSUsername: "mpsusername", Tags: []string{"tag1", "tag2"}, TenantID: "", FriendlyName: "friendlyName", DNSSuffix: "dnsSuffix", Username: "admin", Password: "password", UseTLS: true, AllowSelfSigned: true, LastConnected: &timeNow, LastSeen: &timeNow, LastDisconnected: &timeNow}
 
 	requestDeviceFields = map[string]bool{
 		"connectionstatus": true,
@@ -167,7 +167,7 @@ func TestDevicesRoutes(t *testing.T) {
 					GUID:             "guid",
 					MPSUsername:      "mpsusername",
 					Tags:             []string{"tag1", "tag2"},
-					TenantID:         "tenantId",
+					TenantID:         "",
 					FriendlyName:     "friendlyName",
 					DNSSuffix:        "dnsSuffix",
 					Username:         "admin",
@@ -196,7 +196,7 @@ func TestDevicesRoutes(t *testing.T) {
 					GUID:             "guid",
 					MPSUsername:      "mpsusername",
 					Tags:             []string{"tag1", "tag2"},
-					TenantID:         "tenantId",
+					TenantID:         "",
 					FriendlyName:     "friendlyName",
 					DNSSuffix:        "dnsSuffix",
 					Username:         "admin",
@@ -245,7 +245,7 @@ func TestDevicesRoutes(t *testing.T) {
 					GUID:             "guid",
 					MPSUsername:      "mpsusername",
 					Tags:             []string{"tag1", "tag2"},
-					TenantID:         "tenantId",
+					TenantID:         "",
 					FriendlyName:     "friendlyName",
 					DNSSuffix:        "dnsSuffix",
 					Username:         "admin",
@@ -274,7 +274,7 @@ func TestDevicesRoutes(t *testing.T) {
 					GUID:             "guid",
 					MPSUsername:      "mpsusername",
 					Tags:             []string{"tag1", "tag2"},
-					TenantID:         "tenantId",
+					TenantID:         "",
 					FriendlyName:     "friendlyName",
 					DNSSuffix:        "dnsSuffix",
 					Username:         "admin",
@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/internal/usecase/accesspolicies"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func TestDeviceScopeMiddleware(t *testing.T) {
+	t.Parallel()
+
+	siteA := &devicescope.Scope{Expressions: []devicescope.Expression{{Tags: []string{"site-a"}}}}
+	roles := []rbac.Role{rbac.RoleHelpdesk}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		mock         func(feature *mocks.MockAccessPoliciesFeature)
+		expectedCode int
+		scoped       bool
+	}{
+		{
+			name:   "device list is scoped",
+			method: http.MethodGet,
+			url:    "/api/v1/devices",
+			mock: func(feature *mocks.MockAccessPoliciesFeature) {
+				feature.EXPECT().Scope(context.Background(), "alice", roles, rbac.DevicesRead, "").Return(siteA, nil)
+			},
+			expectedCode: http.StatusOK,
+			scoped:       true,
+		},
+		{
+			name:   "redirection token is scoped by the control permission",
+			method: http.MethodGet,
+			url:    "/api/v1/authorize/redirection/guid-1",
+			mock: func(feature *mocks.MockAccessPoliciesFeature) {
+				feature.EXPECT().Scope(context.Background(), "alice", roles, rbac.DevicesControl, "").Return(siteA, nil)
+			},
+			expectedCode: http.StatusOK,
+			scoped:       true,
+		},
+		{
+			name:   "callers without policies are not scoped",
+			method: http.MethodPost,
+			url:    "/api/v1/amt/power/action/guid-1",
+			mock: func(feature *mocks.MockAccessPoliciesFeature) {
+				feature.EXPECT().Scope(context.Background(), "alice", roles, rbac.DevicesControl, "").Return(nil, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "configuration routes are not scoped",
+			method:       http.MethodGet,
+			url:          "/api/v1/admin/domains",
+			mock:         func(_ *mocks.MockAccessPoliciesFeature) {},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "policy lookup failure",
+			method: http.MethodGet,
+			url:    "/api/v1/devices",
+			mock: func(feature *mocks.MockAccessPoliciesFeature) {
+				feature.EXPECT().Scope(context.Background(), "alice", roles, rbac.DevicesRead, "").Return(nil, accesspolicies.ErrDatabase)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			feature := mocks.NewMockAccessPoliciesFeature(gomock.NewController(t))
+			tc.mock(feature)
+
+			var scope *devicescope.Scope
+
+			handler := func(c *gin.Context) {
+				scope = devicescope.FromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			}
+
+			engine := gin.New()
+			protected := engine.Group("/api", func(c *gin.Context) {
+				c.Set(subjectContextKey, "alice")
+				c.Set(rolesContextKey, roles)
+			}, DeviceScopeMiddleware(feature, logger.New("error")))
+			protected.GET("/v1/devices", handler)
+			protected.GET("/v1/authorize/redirection/:id", handler)
+			protected.POST("/v1/amt/power/action/:guid", handler)
+			protected.GET("/v1/admin/domains", handler)
+
+			req, err := http.NewRequestWithContext(context.Background(), tc.method, tc.url, http.NoBody)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.scoped {
+				require.Equal(t, siteA, scope)
+			} else {
+				require.Nil(t, scope)
+			}
+		})
+	}
+}
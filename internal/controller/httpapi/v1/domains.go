@@ -21,7 +21,7 @@ type domainRoutes struct {
 func NewDomainRoutes(handler *gin.RouterGroup, t domains.Feature, l logger.Interface) {
 	r := &domainRoutes{t, l}
 
-	h := handler.Group("/domains")
+	h := handler.Group("/domains", IfMatchMiddleware())
 	{
 		h.GET("", r.get)
 		h.GET(":name", r.getByName)
@@ -38,14 +38,14 @@ type DomainCountResponse struct {
 
 func (r *domainRoutes) get(c *gin.Context) {
 	var odata OData
-	if err := odata.BindAndValidate(c); err != nil {
-		validationErr := ErrValidationDomains.Wrap("get", "BindAndValidate", err)
+	if err := odata.BindQuery(c, domainQueryFields); err != nil {
+		validationErr := ErrValidationDomains.Wrap("get", "BindQuery", err)
 		ErrorResponse(c, validationErr)
 
 		return
 	}
 
-	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, "")
+	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - getCount")
 		ErrorResponse(c, err)
@@ -54,7 +54,7 @@ func (r *domainRoutes) get(c *gin.Context) {
 	}
 
 	if odata.Count {
-		count, err := r.t.GetCount(c.Request.Context(), "")
+		count, err := r.t.GetCount(c.Request.Context(), Tenant(c))
 		if err != nil {
 			r.l.Error(err, "http - v1 - getCount")
 			ErrorResponse(c, err)
@@ -65,16 +65,16 @@ func (r *domainRoutes) get(c *gin.Context) {
 			Data:  items,
 		}
 
-		c.JSON(http.StatusOK, countResponse)
+		odata.JSON(c, countResponse)
 	} else {
-		c.JSON(http.StatusOK, items)
+		odata.JSON(c, items)
 	}
 }
 
 func (r *domainRoutes) getByName(c *gin.Context) {
 	name := c.Param("name")
 
-	item, err := r.t.GetByName(c.Request.Context(), name, "")
+	item, err := r.t.GetByName(c.Request.Context(), name, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - getByName")
 		ErrorResponse(c, err)
@@ -82,6 +82,7 @@ func (r *domainRoutes) getByName(c *gin.Context) {
 		return
 	}
 
+	setETag(c, item.Version)
 	c.JSON(http.StatusOK, item)
 }
 
@@ -94,6 +95,8 @@ func (r *domainRoutes) insert(c *gin.Context) {
 		return
 	}
 
+	domain.TenantID = Tenant(c)
+
 	newDomain, err := r.t.Insert(c.Request.Context(), &domain)
 	if err != nil {
 		r.l.Error(err, "http - v1 - insert")
@@ -102,6 +105,7 @@ func (r *domainRoutes) insert(c *gin.Context) {
 		return
 	}
 
+	setETag(c, newDomain.Version)
 	c.JSON(http.StatusCreated, newDomain)
 }
 
@@ -114,6 +118,8 @@ func (r *domainRoutes) update(c *gin.Context) {
 		return
 	}
 
+	domain.TenantID = Tenant(c)
+
 	updatedDomain, err := r.t.Update(c.Request.Context(), &domain)
 	if err != nil {
 		r.l.Error(err, "http - v1 - update")
@@ -122,13 +128,14 @@ func (r *domainRoutes) update(c *gin.Context) {
 		return
 	}
 
+	setETag(c, updatedDomain.Version)
 	c.JSON(http.StatusOK, updatedDomain)
 }
 
 func (r *domainRoutes) delete(c *gin.Context) {
 	name := c.Param("name")
 
-	err := r.t.Delete(c.Request.Context(), name, "")
+	err := r.t.Delete(c.Request.Context(), name, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - delete")
 		ErrorResponse(c, err)
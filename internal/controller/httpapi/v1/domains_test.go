@@ -53,7 +53,7 @@ type test struct {
 
 var (
 	requestDomain  = dto.Domain{ProfileName: "newProfile", TenantID: "tenant1", DomainSuffix: "domain.com", ProvisioningCert: "cert", ProvisioningCertStorageFormat: "string", ProvisioningCertPassword: "password"}
-	responseDomain = dto.Domain{ProfileName: "newProfile", TenantID: "tenant1", DomainSuffix: "domain.com", ProvisioningCert: "cert", ProvisioningCertStorageFormat: "string", ProvisioningCertPassword: "password"}
+	responseDomain = dto.Domain{ProfileName: "newProfile", TenantID: "", DomainSuffix: "domain.com", ProvisioningCert: "cert", ProvisioningCertStorageFormat: "string", ProvisioningCertPassword: "password"}
 )
 
 func TestDomainRoutes(t *testing.T) {
@@ -122,7 +122,7 @@ func TestDomainRoutes(t *testing.T) {
 			method: http.MethodPost,
 			url:    "/api/v1/admin/domains",
 			mock: func(domain *mocks.MockDomainsFeature) {
-				domainTest := &dto.Domain{ProfileName: "newProfile", TenantID: "tenant1", DomainSuffix: "domain.com", ProvisioningCert: "cert", ProvisioningCertStorageFormat: "string", ProvisioningCertPassword: "password"}
+				domainTest := &dto.Domain{ProfileName: "newProfile", TenantID: "", DomainSuffix: "domain.com", ProvisioningCert: "cert", ProvisioningCertStorageFormat: "string", ProvisioningCertPassword: "password"}
 				domain.EXPECT().Insert(context.Background(), domainTest).Return(domainTest, nil)
 			},
 			response:     responseDomain,
@@ -134,7 +134,7 @@ func TestDomainRoutes(t *testing.T) {
 			method: http.MethodPost,
 			url:    "/api/v1/admin/domains",
 			mock: func(domain *mocks.MockDomainsFeature) {
-				domainTest := &dto.Domain{ProfileName: "newProfile", TenantID: "tenant1", DomainSuffix: "domain.com", ProvisioningCert: "cert", ProvisioningCertStorageFormat: "string", ProvisioningCertPassword: "password"}
+				domainTest := &dto.Domain{ProfileName: "newProfile", TenantID: "", DomainSuffix: "domain.com", ProvisioningCert: "cert", ProvisioningCertStorageFormat: "string", ProvisioningCertPassword: "password"}
 				domain.EXPECT().Insert(context.Background(), domainTest).Return(nil, domains.ErrDatabase)
 			},
 			response:     domains.ErrDatabase,
@@ -146,7 +146,7 @@ func TestDomainRoutes(t *testing.T) {
 			method: http.MethodPost,
 			url:    "/api/v1/admin/domains",
 			mock: func(domain *mocks.MockDomainsFeature) {
-				domain400Test := &dto.Domain{ProfileName: "p1", TenantID: "t1", DomainSuffix: "domain1.com", ProvisioningCert: "cert1", ProvisioningCertStorageFormat: "string1"}
+				domain400Test := &dto.Domain{ProfileName: "p1", TenantID: "", DomainSuffix: "domain1.com", ProvisioningCert: "cert1", ProvisioningCertStorageFormat: "string1"}
 				domain.EXPECT().Insert(context.Background(), domain400Test).Return(nil, domains.ErrDatabase)
 			},
 			response:     domains.ErrDatabase,
@@ -178,7 +178,7 @@ func TestDomainRoutes(t *testing.T) {
 			method: http.MethodPatch,
 			url:    "/api/v1/admin/domains",
 			mock: func(domain *mocks.MockDomainsFeature) {
-				domainTest := &dto.Domain{ProfileName: "newProfile", TenantID: "tenant1", DomainSuffix: "domain.com", ProvisioningCert: "cert", ProvisioningCertStorageFormat: "string", ProvisioningCertPassword: "password"}
+				domainTest := &dto.Domain{ProfileName: "newProfile", TenantID: "", DomainSuffix: "domain.com", ProvisioningCert: "cert", ProvisioningCertStorageFormat: "string", ProvisioningCertPassword: "password"}
 				domain.EXPECT().Update(context.Background(), domainTest).Return(domainTest, nil)
 			},
 			response:     responseDomain,
@@ -190,7 +190,7 @@ func TestDomainRoutes(t *testing.T) {
 			method: http.MethodPatch,
 			url:    "/api/v1/admin/domains",
 			mock: func(domain *mocks.MockDomainsFeature) {
-				domainTest := &dto.Domain{ProfileName: "newProfile", TenantID: "tenant1", DomainSuffix: "domain.com", ProvisioningCert: "cert", ProvisioningCertStorageFormat: "string", ProvisioningCertPassword: "password"}
+				domainTest := &dto.Domain{ProfileName: "newProfile", TenantID: "", DomainSuffix: "domain.com", ProvisioningCert: "cert", ProvisioningCertStorageFormat: "string", ProvisioningCertPassword: "password"}
 				domain.EXPECT().Update(context.Background(), domainTest).Return(nil, domains.ErrDatabase)
 			},
 			response:     domains.ErrDatabase,
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"errors"
 	"net"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"github.com/go-playground/validator/v10"
 
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/odata"
 	"github.com/device-management-toolkit/console/internal/repoerrors"
 	"github.com/device-management-toolkit/console/internal/usecase/devices"
 	wsmanAPI "github.com/device-management-toolkit/console/internal/usecase/devices/wsman"
@@ -43,7 +45,8 @@ func handleValidationErrors(c *gin.Context, err error) bool {
 
 	switch {
 	case errors.As(err, &odataValidationErr) || errors.Is(err, ErrInvalidInteger) ||
-		errors.Is(err, ErrExceedsMaxRange) || errors.Is(err, ErrNegativeValue) || errors.Is(err, ErrInvalidBoolean):
+		errors.Is(err, ErrExceedsMaxRange) || errors.Is(err, ErrNegativeValue) || errors.Is(err, ErrInvalidBoolean) ||
+		errors.Is(err, odata.ErrInvalidQuery):
 		msg := err.Error()
 		c.AbortWithStatusJSON(http.StatusBadRequest, response{Error: msg, Message: msg})
 
@@ -103,6 +106,7 @@ func handleTypedErrors(c *gin.Context, err error) {
 		nfErr        repoerrors.NotFoundError
 		dbErr        repoerrors.DatabaseError
 		notUniqueErr repoerrors.NotUniqueError
+		staleErr     repoerrors.PreconditionFailedError
 		amtErr       devices.AMTError
 	)
 
@@ -117,6 +121,8 @@ func handleTypedErrors(c *gin.Context, err error) {
 		dbErrorHandle(c, dbErr)
 	case errors.As(err, &notUniqueErr):
 		notUniqueErrorHandle(c, notUniqueErr)
+	case errors.As(err, &staleErr):
+		preconditionFailedErrorHandle(c, staleErr)
 	case errors.As(err, &amtErr):
 		amtErrorHandle(c, amtErr)
 	default:
@@ -139,6 +145,21 @@ func handleSentinelErrors(c *gin.Context, err error) bool {
 		msg := wsmanAPI.ErrCIRADeviceNotConnected.Error()
 		c.AbortWithStatusJSON(http.StatusServiceUnavailable, response{Error: msg, Message: msg})
 
+		return true
+	case errors.Is(err, devices.ErrUserConsentRequired):
+		msg := devices.ErrUserConsentRequired.Error()
+		c.AbortWithStatusJSON(http.StatusPreconditionRequired, response{Error: msg, Message: msg})
+
+		return true
+	case errors.Is(err, devices.ErrRedirectionBusy):
+		msg := devices.ErrRedirectionBusy.Error()
+		c.AbortWithStatusJSON(http.StatusConflict, response{Error: msg, Message: msg})
+
+		return true
+	case errors.Is(err, context.DeadlineExceeded):
+		msg := "request timed out"
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, response{Error: msg, Message: msg})
+
 		return true
 	}
 
@@ -199,12 +220,20 @@ func dbErrorHandle(c *gin.Context, err repoerrors.DatabaseError) {
 
 	var foreignKeyViolationErr sqldb.ForeignKeyViolationError
 
+	var staleErr repoerrors.PreconditionFailedError
+
 	if errors.As(err.Console.OriginalError, &notUniqueErr) {
 		notUniqueErrorHandle(c, notUniqueErr)
 
 		return
 	}
 
+	if errors.As(err.Console.OriginalError, &staleErr) {
+		preconditionFailedErrorHandle(c, staleErr)
+
+		return
+	}
+
 	if errors.As(err.Console.OriginalError, &foreignKeyViolationErr) {
 		msg := foreignKeyViolationErr.Console.FriendlyMessage()
 		c.AbortWithStatusJSON(http.StatusBadRequest, response{Error: msg, Message: msg})
@@ -233,3 +262,12 @@ func notUniqueErrorHandle(c *gin.Context, err repoerrors.NotUniqueError) {
 
 	c.AbortWithStatusJSON(http.StatusConflict, response{Error: msg, Message: msg})
 }
+
+func preconditionFailedErrorHandle(c *gin.Context, err repoerrors.PreconditionFailedError) {
+	msg := err.Console.FriendlyMessage()
+	if msg == "" {
+		msg = "resource has changed"
+	}
+
+	c.AbortWithStatusJSON(http.StatusPreconditionFailed, response{Error: msg, Message: msg})
+}
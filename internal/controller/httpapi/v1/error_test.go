@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/device-management-toolkit/console/internal/repoerrors"
 	wsmanAPI "github.com/device-management-toolkit/console/internal/usecase/devices/wsman"
 	"github.com/device-management-toolkit/console/internal/usecase/profiles"
 )
@@ -44,6 +46,16 @@ func TestErrorResponse_CIRADeviceNotConnected(t *testing.T) {
 	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
 }
 
+func TestErrorResponse_QueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	w := runErrorResponse(t, repoerrors.DatabaseError{}.Wrap("Get", "r.Pool.Query", context.DeadlineExceeded))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+
+	w = runErrorResponse(t, repoerrors.DatabaseError{}.Wrap("Get", "r.Pool.Query", errors.New("syntax error")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestHandleSentinelErrors_CIRADeviceNotConnected(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/versions"
+)
+
+// IfMatchMiddleware hands the entity tags of a PATCH, PUT or DELETE
+// request's If-Match header to the repositories through its context, so
+// that they refuse to change an entity that changed since the caller read
+// it; the refusal answers 412. A missing header or "*" sets no condition.
+// Weak tags never match, as the strong comparison If-Match calls for.
+func IfMatchMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPatch, http.MethodPut, http.MethodDelete:
+		default:
+			c.Next()
+
+			return
+		}
+
+		header := strings.TrimSpace(c.GetHeader("If-Match"))
+		if header == "" || header == "*" {
+			c.Next()
+
+			return
+		}
+
+		c.Request = c.Request.WithContext(versions.WithIfMatch(c.Request.Context(), strongETags(header)))
+
+		c.Next()
+	}
+}
+
+// strongETags returns the versions named by the strong entity tags in an
+// If-Match header.
+func strongETags(header string) []string {
+	tags := []string{}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || strings.HasPrefix(tag, "W/") {
+			continue
+		}
+
+		tags = append(tags, strings.Trim(tag, `"`))
+	}
+
+	return tags
+}
+
+// setETag tags the response with the version of the entity it carries, for
+// the caller to send back in If-Match.
+func setETag(c *gin.Context, version string) {
+	if version != "" {
+		c.Header("ETag", `"`+version+`"`)
+	}
+}
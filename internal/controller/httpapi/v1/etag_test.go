@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/profiles"
+	"github.com/device-management-toolkit/console/internal/versions"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+)
+
+func TestStrongETags(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"a"}, strongETags(`"a"`))
+	require.Equal(t, []string{"a", "c"}, strongETags(`"a", W/"b" ,"c"`))
+	require.Empty(t, strongETags(`W/"a"`), "weak tags match no version")
+}
+
+func TestIfMatch(t *testing.T) {
+	t.Parallel()
+
+	feature, engine := profilesTest(t)
+
+	// Reads carry the version as an ETag and ignore If-Match.
+	feature.EXPECT().GetByName(gomock.Any(), "p1", "").DoAndReturn(func(ctx context.Context, _, _ string) (*dto.Profile, error) {
+		_, ok := versions.IfMatch(ctx)
+		require.False(t, ok)
+
+		return &dto.Profile{ProfileName: "p1", Version: "v2"}, nil
+	})
+
+	w := serveIfMatch(engine, http.MethodGet, "/api/v1/admin/profiles/p1", `"v1"`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, `"v2"`, w.Header().Get("ETag"))
+
+	// Changes expect the versions If-Match names.
+	feature.EXPECT().Delete(gomock.Any(), "p1", "").DoAndReturn(func(ctx context.Context, _, _ string) error {
+		expected, ok := versions.IfMatch(ctx)
+		require.True(t, ok)
+		require.Equal(t, []string{"v2"}, expected)
+
+		return nil
+	})
+
+	w = serveIfMatch(engine, http.MethodDelete, "/api/v1/admin/profiles/p1", `"v2"`)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	// "*" expects none.
+	feature.EXPECT().Delete(gomock.Any(), "p1", "").DoAndReturn(func(ctx context.Context, _, _ string) error {
+		_, ok := versions.IfMatch(ctx)
+		require.False(t, ok)
+
+		return nil
+	})
+
+	w = serveIfMatch(engine, http.MethodDelete, "/api/v1/admin/profiles/p1", "*")
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	// A stale version answers 412.
+	stale := repoerrors.PreconditionFailedError{Console: consoleerrors.CreateConsoleError("test")}.Wrap("profile p1 has changed")
+	feature.EXPECT().Delete(gomock.Any(), "p1", "").Return(profiles.ErrDatabase.Wrap("Delete", "uc.repo.Delete", stale))
+
+	w = serveIfMatch(engine, http.MethodDelete, "/api/v1/admin/profiles/p1", `"v1"`)
+	require.Equal(t, http.StatusPreconditionFailed, w.Code)
+	require.Contains(t, w.Body.String(), "profile p1 has changed")
+}
+
+func serveIfMatch(engine http.Handler, method, url, ifMatch string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequestWithContext(context.Background(), method, url, http.NoBody)
+	req.Header.Set("If-Match", ifMatch)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	return w
+}
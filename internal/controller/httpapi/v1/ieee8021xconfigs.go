@@ -32,7 +32,7 @@ func NewIEEE8021xConfigRoutes(handler *gin.RouterGroup, t ieee8021xconfigs.Featu
 		}
 	}
 
-	h := handler.Group("/ieee8021xconfigs")
+	h := handler.Group("/ieee8021xconfigs", IfMatchMiddleware())
 	{
 		h.GET("", r.get)
 		h.GET(":profileName", r.getByName)
@@ -44,14 +44,14 @@ func NewIEEE8021xConfigRoutes(handler *gin.RouterGroup, t ieee8021xconfigs.Featu
 
 func (r *ieee8021xConfigRoutes) get(c *gin.Context) {
 	var odata OData
-	if err := odata.BindAndValidate(c); err != nil {
-		validationErr := ErrValidation8021xConfig.Wrap("get", "BindAndValidate", err)
+	if err := odata.BindQuery(c, ieee8021xConfigQueryFields); err != nil {
+		validationErr := ErrValidation8021xConfig.Wrap("get", "BindQuery", err)
 		ErrorResponse(c, validationErr)
 
 		return
 	}
 
-	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, "")
+	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - IEEE8021x configs - v1 - getCount")
 		ErrorResponse(c, err)
@@ -60,7 +60,7 @@ func (r *ieee8021xConfigRoutes) get(c *gin.Context) {
 	}
 
 	if odata.Count {
-		count, err := r.t.GetCount(c.Request.Context(), "")
+		count, err := r.t.GetCount(c.Request.Context(), Tenant(c))
 		if err != nil {
 			r.l.Error(err, "http - IEEE8021x configs - v1 - getCount")
 			ErrorResponse(c, err)
@@ -71,16 +71,16 @@ func (r *ieee8021xConfigRoutes) get(c *gin.Context) {
 			Data:  items,
 		}
 
-		c.JSON(http.StatusOK, countResponse)
+		odata.JSON(c, countResponse)
 	} else {
-		c.JSON(http.StatusOK, items)
+		odata.JSON(c, items)
 	}
 }
 
 func (r *ieee8021xConfigRoutes) getByName(c *gin.Context) {
 	configName := c.Param("profileName")
 
-	config, err := r.t.GetByName(c.Request.Context(), configName, "")
+	config, err := r.t.GetByName(c.Request.Context(), configName, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - IEEE8021x configs - v1 - getByName")
 		ErrorResponse(c, err)
@@ -88,6 +88,7 @@ func (r *ieee8021xConfigRoutes) getByName(c *gin.Context) {
 		return
 	}
 
+	setETag(c, config.Version)
 	c.JSON(http.StatusOK, config)
 }
 
@@ -100,6 +101,8 @@ func (r *ieee8021xConfigRoutes) insert(c *gin.Context) {
 		return
 	}
 
+	config.TenantID = Tenant(c)
+
 	newConfig, err := r.t.Insert(c.Request.Context(), &config)
 	if err != nil {
 		r.l.Error(err, "http - IEEE8021x configs - v1 - insert")
@@ -108,6 +111,7 @@ func (r *ieee8021xConfigRoutes) insert(c *gin.Context) {
 		return
 	}
 
+	setETag(c, newConfig.Version)
 	c.JSON(http.StatusCreated, newConfig)
 }
 
@@ -120,6 +124,8 @@ func (r *ieee8021xConfigRoutes) update(c *gin.Context) {
 		return
 	}
 
+	config.TenantID = Tenant(c)
+
 	updatedConfig, err := r.t.Update(c.Request.Context(), &config)
 	if err != nil {
 		r.l.Error(err, "http - IEEE8021x configs - v1 - update")
@@ -128,13 +134,14 @@ func (r *ieee8021xConfigRoutes) update(c *gin.Context) {
 		return
 	}
 
+	setETag(c, updatedConfig.Version)
 	c.JSON(http.StatusOK, updatedConfig)
 }
 
 func (r *ieee8021xConfigRoutes) delete(c *gin.Context) {
 	configName := c.Param("profileName")
 
-	err := r.t.Delete(c.Request.Context(), configName, "")
+	err := r.t.Delete(c.Request.Context(), configName, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - IEEE8021x configs - v1 - delete")
 		ErrorResponse(c, err)
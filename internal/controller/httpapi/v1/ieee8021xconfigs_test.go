@@ -51,7 +51,7 @@ var ieee8021xconfigTest = dto.IEEE8021xConfig{
 	ProfileName:            "newprofile",
 	AuthenticationProtocol: 2,
 	PXETimeout:             &pxeTime,
-	TenantID:               "tenant1",
+	TenantID:               "",
 	Version:                "1.0",
 	WiredInterface:         false,
 }
@@ -23,6 +23,22 @@ func (r *deviceManagementRoutes) getKVMDisplays(c *gin.Context) {
 	c.JSON(http.StatusOK, settings)
 }
 
+// getKVMScreenshot returns the device's current screen as a PNG
+func (r *deviceManagementRoutes) getKVMScreenshot(c *gin.Context) {
+	guid := c.Param("guid")
+
+	image, err := r.d.GetKVMScreenshot(c.Request.Context(), guid)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getKVMScreenshot")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/png", image)
+}
+
 // setKVMDisplays updates IPS_ScreenSettingData for the device
 func (r *deviceManagementRoutes) setKVMDisplays(c *gin.Context) {
 	guid := c.Param("guid")
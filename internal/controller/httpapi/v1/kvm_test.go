@@ -14,6 +14,7 @@ import (
 
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
 	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
 	"github.com/device-management-toolkit/console/pkg/logger"
 )
 
@@ -66,3 +67,61 @@ func TestKVMDisplaysEndpoints(t *testing.T) {
 		require.Equal(t, http.StatusOK, rr.Code)
 	})
 }
+
+func TestKVMScreenshotEndpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		image       []byte
+		err         error
+		status      int
+		contentType string
+	}{
+		{
+			name:        "returns png",
+			image:       []byte("\x89PNG\r\n\x1a\n"),
+			status:      http.StatusOK,
+			contentType: "image/png",
+		},
+		{
+			name:        "user consent required",
+			err:         devices.ErrUserConsentRequired,
+			status:      http.StatusPreconditionRequired,
+			contentType: "application/json; charset=utf-8",
+		},
+		{
+			name:        "redirection busy",
+			err:         devices.ErrRedirectionBusy,
+			status:      http.StatusConflict,
+			contentType: "application/json; charset=utf-8",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtl := gomock.NewController(t)
+			defer mockCtl.Finish()
+
+			deviceManagement := mocks.NewMockDeviceManagementFeature(mockCtl)
+			engine := gin.New()
+			handler := engine.Group("/api/v1")
+			NewAmtRoutes(handler, deviceManagement, mocks.NewMockAMTExplorerFeature(mockCtl), mocks.NewMockExporter(mockCtl), logger.New("error"))
+
+			deviceManagement.EXPECT().GetKVMScreenshot(context.Background(), "guid1").Return(tc.image, tc.err)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/amt/kvm/screenshot/guid1", http.NoBody)
+			rr := httptest.NewRecorder()
+			engine.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code)
+			require.Equal(t, tc.contentType, rr.Header().Get("Content-Type"))
+
+			if tc.err == nil {
+				require.Equal(t, tc.image, rr.Body.Bytes())
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/usecase/lockouts"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+type lockoutRoutes struct {
+	lo lockouts.Feature
+	l  logger.Interface
+}
+
+// NewLockoutRoutes lets administrators see which usernames and client IPs
+// are throttled after failed logins, and lift a lockout.
+func NewLockoutRoutes(handler *gin.RouterGroup, lo lockouts.Feature, l logger.Interface) {
+	r := &lockoutRoutes{lo, l}
+
+	h := handler.Group("/lockouts")
+	{
+		h.GET("", r.get)
+		h.DELETE(":kind/:key", r.clear)
+	}
+}
+
+func (r *lockoutRoutes) get(c *gin.Context) {
+	items, err := r.lo.Get(c.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - getLockouts")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (r *lockoutRoutes) clear(c *gin.Context) {
+	err := r.lo.Clear(c.Request.Context(), c.Param("kind"), c.Param("key"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - clearLockout")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/lockouts"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func lockoutsTest(t *testing.T) (*mocks.MockLockoutsFeature, *gin.Engine) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	feature := mocks.NewMockLockoutsFeature(mockCtl)
+
+	engine := gin.New()
+	handler := engine.Group("/api/v1/admin")
+
+	NewLockoutRoutes(handler, feature, logger.New("error"))
+
+	return feature, engine
+}
+
+func TestLockoutRoutes(t *testing.T) {
+	t.Parallel()
+
+	failed := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	until := failed.Add(15 * time.Minute)
+	items := []dto.Lockout{{Kind: lockouts.KindUsername, Key: "jdoe", Failures: 5, LastFailureAt: failed, BlockedUntil: &until, Locked: true}}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		mock         func(*mocks.MockLockoutsFeature)
+		response     interface{}
+		expectedCode int
+	}{
+		{
+			name:   "list lockouts",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/lockouts",
+			mock: func(f *mocks.MockLockoutsFeature) {
+				f.EXPECT().Get(context.Background()).Return(items, nil)
+			},
+			response:     items,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "clear lockout",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/lockouts/ip/10.0.0.1",
+			mock: func(f *mocks.MockLockoutsFeature) {
+				f.EXPECT().Clear(context.Background(), lockouts.KindIP, "10.0.0.1").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "clear unknown lockout",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/lockouts/username/nobody",
+			mock: func(f *mocks.MockLockoutsFeature) {
+				f.EXPECT().Clear(context.Background(), lockouts.KindUsername, "nobody").
+					Return(lockouts.ErrNotFound.WrapWithMessage("Clear", "uc.records", "no failed logins recorded"))
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			feature, engine := lockoutsTest(t)
+			tc.mock(feature)
+
+			req, err := http.NewRequestWithContext(context.Background(), tc.method, tc.url, http.NoBody)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.response != nil {
+				expected, _ := json.Marshal(tc.response)
+				require.JSONEq(t, string(expected), w.Body.String())
+			}
+		})
+	}
+}
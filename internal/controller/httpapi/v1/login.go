@@ -2,19 +2,31 @@ package v1
 
 import (
 	"context"
+	"crypto/subtle"
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 
 	"github.com/device-management-toolkit/console/config"
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/tenancy"
+	"github.com/device-management-toolkit/console/internal/usecase/apikeys"
+	"github.com/device-management-toolkit/console/internal/usecase/ldapauth"
+	"github.com/device-management-toolkit/console/internal/usecase/lockouts"
+	"github.com/device-management-toolkit/console/internal/usecase/tokens"
+	"github.com/device-management-toolkit/console/internal/usecase/users"
 	"github.com/device-management-toolkit/console/pkg/consoleerrors"
 )
 
@@ -24,6 +36,13 @@ const (
 
 	authorizationHeader = "Authorization"
 	bearerPrefix        = "Bearer "
+
+	subjectContextKey = "sub"
+	sessionContextKey = "sid"
+
+	// redirectionAudience marks tokens that only open a device's redirection
+	// websocket.
+	redirectionAudience = "redirection"
 )
 
 var (
@@ -34,12 +53,25 @@ var (
 type LoginRoute struct {
 	Config   *config.Config
 	Verifier *oidc.IDTokenVerifier
+	Users    users.Feature
+	APIKeys  apikeys.Feature
+	Tokens   tokens.Feature
+	Lockouts lockouts.Feature
+	LDAP     ldapauth.Feature
 }
 
-// NewVersionRoute creates a new version route
-func NewLoginRoute(configData *config.Config) *LoginRoute {
+// NewLoginRoute authenticates the built-in admin from config and, when set,
+// local user accounts (u), API keys (k) and directory users (d). With t,
+// tokens it issues can be refreshed and revoked. With lo, repeated failed
+// logins are throttled.
+func NewLoginRoute(configData *config.Config, u users.Feature, k apikeys.Feature, t tokens.Feature, lo lockouts.Feature, d ldapauth.Feature) *LoginRoute {
 	lr := &LoginRoute{
-		Config: configData,
+		Config:   configData,
+		Users:    u,
+		APIKeys:  k,
+		Tokens:   t,
+		Lockouts: lo,
+		LDAP:     d,
 	}
 
 	if config.ConsoleConfig.ClientID != "" {
@@ -83,16 +115,137 @@ func (lr LoginRoute) Login(c *gin.Context) {
 }
 
 func (lr LoginRoute) handleBasicAuth(creds dto.Credentials, c *gin.Context) {
-	if creds.Username != lr.Config.AdminUsername || creds.Password != lr.Config.AdminPassword {
-		c.JSON(http.StatusUnauthorized, gin.H{errorKey: "invalid credentials", messageKey: "Incorrect Username and/or Password!"})
+	if lr.throttled(c, creds.Username) {
+		return
+	}
+
+	// The built-in admin is checked first, so it keeps working if the user
+	// store is unavailable.
+	// It is the super-admin of a multi-tenant install, so it can set up the
+	// tenants' own admins.
+	if creds.Username == lr.Config.AdminUsername &&
+		subtle.ConstantTimeCompare([]byte(creds.Password), []byte(lr.Config.AdminPassword)) == 1 {
+		lr.loginSucceeded(c, creds.Username)
+		lr.issueToken(c, lr.Config.AdminUsername, string(rbac.RoleSuperAdmin), tenancy.Default)
 
 		return
 	}
 
-	// Create JWT token
+	if lr.Users == nil {
+		lr.handleDirectoryAuth(creds, c)
+
+		return
+	}
+
+	user, err := lr.Users.Authenticate(c.Request.Context(), creds.Username, creds.Password)
+
+	switch {
+	// Local accounts shadow directory users of the same name.
+	case errors.Is(err, users.ErrUnknownUser):
+		lr.handleDirectoryAuth(creds, c)
+	case errors.Is(err, users.ErrInvalidCredentials):
+		lr.rejectCredentials(c, creds.Username)
+	case errors.Is(err, users.ErrPasswordChangeRequired):
+		// The password was right.
+		lr.loginSucceeded(c, creds.Username)
+		c.JSON(http.StatusForbidden, gin.H{errorKey: "password change required", messageKey: err.Error()})
+	case err != nil:
+		ErrorResponse(c, err)
+	default:
+		lr.loginSucceeded(c, creds.Username)
+		lr.issueToken(c, user.Username, user.Role, user.TenantID)
+	}
+}
+
+// handleDirectoryAuth checks a login that matches no local account against
+// the LDAP directory, if one is configured.
+func (lr LoginRoute) handleDirectoryAuth(creds dto.Credentials, c *gin.Context) {
+	if lr.LDAP == nil {
+		lr.rejectCredentials(c, creds.Username)
+
+		return
+	}
+
+	user, err := lr.LDAP.Authenticate(c.Request.Context(), creds.Username, creds.Password)
+
+	switch {
+	case errors.Is(err, ldapauth.ErrInvalidCredentials):
+		lr.rejectCredentials(c, creds.Username)
+	case errors.Is(err, ldapauth.ErrNoRole):
+		// The password was right.
+		lr.loginSucceeded(c, creds.Username)
+		c.JSON(http.StatusForbidden, gin.H{errorKey: "forbidden", messageKey: err.Error()})
+	case err != nil:
+		ErrorResponse(c, err)
+	default:
+		lr.loginSucceeded(c, creds.Username)
+		lr.issueToken(c, user.Username, user.Role, user.TenantID)
+	}
+}
+
+// throttled refuses a password attempt while the username or the client IP
+// must wait after failed logins, and tells the client when to retry.
+func (lr LoginRoute) throttled(c *gin.Context, username string) bool {
+	if lr.Lockouts == nil {
+		return false
+	}
+
+	wait := lr.Lockouts.Check(c.Request.Context(), username, c.ClientIP())
+	if wait <= 0 {
+		return false
+	}
+
+	// Rounded up, so a client that waits as told is not refused again.
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, gin.H{errorKey: "too many failed logins", messageKey: "Too many failed logins, try again later"})
+
+	return true
+}
+
+// rejectCredentials answers a wrong username or password and counts the
+// failure.
+func (lr LoginRoute) rejectCredentials(c *gin.Context, username string) {
+	if lr.Lockouts != nil {
+		lr.Lockouts.Failed(c.Request.Context(), username, c.ClientIP())
+	}
+
+	c.JSON(http.StatusUnauthorized, gin.H{errorKey: "invalid credentials", messageKey: "Incorrect Username and/or Password!"})
+}
+
+func (lr LoginRoute) loginSucceeded(c *gin.Context, username string) {
+	if lr.Lockouts != nil {
+		lr.Lockouts.Succeeded(c.Request.Context(), username)
+	}
+}
+
+// issueToken starts a login session and signs its first token.
+func (lr LoginRoute) issueToken(c *gin.Context, subject, role, tenantID string) {
+	session := &dto.TokenSession{Username: subject, TenantID: tenantID}
+
+	if lr.Tokens != nil {
+		var err error
+
+		session, err = lr.Tokens.Start(c.Request.Context(), subject, tenantID)
+		if err != nil {
+			ErrorResponse(c, err)
+
+			return
+		}
+	}
+
+	lr.signToken(c, session, role)
+}
+
+// signToken signs an access token naming the user as its subject, so actions
+// can be attributed to them, and the tenant they act in. Its jti, and the sid
+// of the session it belongs to, let it be revoked.
+func (lr LoginRoute) signToken(c *gin.Context, session *dto.TokenSession, role string) {
 	expirationTime := time.Now().Add(config.ConsoleConfig.JWTExpiration)
 	claims := jwt.MapClaims{
-		"exp": expirationTime.Unix(),
+		"exp":           expirationTime.Unix(),
+		"sub":           session.Username,
+		"jti":           uuid.NewString(),
+		lr.rolesClaim(): []string{role},
 	}
 
 	// Conditional so the claim set still matches what callers saw before.
@@ -100,6 +253,14 @@ func (lr LoginRoute) handleBasicAuth(creds dto.Credentials, c *gin.Context) {
 		claims["iss"] = config.ConsoleConfig.Issuer
 	}
 
+	if session.TenantID != tenancy.Default && lr.Config.TenantClaim != "" {
+		claims[lr.Config.TenantClaim] = session.TenantID
+	}
+
+	if session.ID != "" {
+		claims["sid"] = session.ID
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	tokenString, err := token.SignedString([]byte(lr.Config.JWTKey))
@@ -112,13 +273,165 @@ func (lr LoginRoute) handleBasicAuth(creds dto.Credentials, c *gin.Context) {
 	setSessionCookies(c, tokenString, expirationTime)
 
 	// Token stays in the body for bearer clients, which ignore Set-Cookie.
-	c.JSON(http.StatusOK, gin.H{"token": tokenString})
+	body := gin.H{"token": tokenString}
+	if session.RefreshToken != "" {
+		body["refreshToken"] = session.RefreshToken
+	}
+
+	c.JSON(http.StatusOK, body)
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh token.
+// Public, as the access token may have expired. The role is looked up again,
+// so a changed or deleted account does not keep its old one.
+func (lr LoginRoute) Refresh(c *gin.Context) {
+	var refresh dto.TokenRefresh
+
+	if err := c.ShouldBindJSON(&refresh); err != nil || refresh.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{errorKey: "invalid request"})
+
+		return
+	}
+
+	if lr.Tokens == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{errorKey: "invalid refresh token"})
+
+		return
+	}
+
+	session, err := lr.Tokens.Refresh(c.Request.Context(), refresh.RefreshToken)
+	if errors.Is(err, tokens.ErrInvalidRefreshToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{errorKey: "invalid refresh token"})
+
+		return
+	}
+
+	if err != nil {
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	role, err := lr.sessionRole(c.Request.Context(), session)
+	if errors.Is(err, users.ErrInvalidCredentials) || errors.Is(err, users.ErrPasswordChangeRequired) {
+		if endErr := lr.Tokens.End(c.Request.Context(), session.ID, session.Username); endErr != nil {
+			ErrorResponse(c, endErr)
+
+			return
+		}
+
+		c.JSON(http.StatusUnauthorized, gin.H{errorKey: "invalid refresh token", messageKey: err.Error()})
+
+		return
+	}
+
+	if err != nil {
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	lr.signToken(c, session, role)
 }
 
-// Logout expires the session cookies. Public, so an already-expired session can
-// still clear its own. Not revocation: the JWT stays valid until it expires.
+// sessionRole returns the current role of the session's user, or an error
+// when they may no longer log in.
+func (lr LoginRoute) sessionRole(ctx context.Context, session *dto.TokenSession) (string, error) {
+	if session.Username == lr.Config.AdminUsername && session.TenantID == tenancy.Default {
+		return string(rbac.RoleSuperAdmin), nil
+	}
+
+	if lr.Users == nil {
+		return lr.directoryRole(ctx, session)
+	}
+
+	var notFound repoerrors.NotFoundError
+
+	user, err := lr.Users.GetByUsername(ctx, session.Username, session.TenantID)
+	if errors.As(err, &notFound) {
+		return lr.directoryRole(ctx, session)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if user.MustChangePassword {
+		return "", users.ErrPasswordChangeRequired
+	}
+
+	return user.Role, nil
+}
+
+// directoryRole returns the current role of a directory user's session.
+func (lr LoginRoute) directoryRole(ctx context.Context, session *dto.TokenSession) (string, error) {
+	if lr.LDAP == nil || session.TenantID != tenancy.Default {
+		return "", users.ErrInvalidCredentials
+	}
+
+	user, err := lr.LDAP.Lookup(ctx, session.Username)
+	if errors.Is(err, ldapauth.ErrInvalidCredentials) || errors.Is(err, ldapauth.ErrNoRole) {
+		return "", users.ErrInvalidCredentials
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return user.Role, nil
+}
+
+// ChangePassword sets a local user's new password given the current one. It
+// is public, as a user whose password must be changed cannot log in.
+func (lr LoginRoute) ChangePassword(c *gin.Context) {
+	var change dto.PasswordChange
+
+	if err := c.ShouldBindJSON(&change); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{errorKey: "invalid request"})
+
+		return
+	}
+
+	// It checks the current password, so guesses here count as failed logins.
+	if lr.throttled(c, change.Username) {
+		return
+	}
+
+	if lr.Users == nil {
+		lr.rejectCredentials(c, change.Username)
+
+		return
+	}
+
+	err := lr.Users.ChangePassword(c.Request.Context(), change)
+	if errors.Is(err, users.ErrInvalidCredentials) {
+		lr.rejectCredentials(c, change.Username)
+
+		return
+	}
+
+	if err != nil {
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	lr.loginSucceeded(c, change.Username)
+	c.Status(http.StatusNoContent)
+}
+
+// Logout expires the session cookies and ends the session of the presented
+// token, so neither it nor its refresh token can be used again. Public, so an
+// already-expired session can still clear its own cookies.
 func (lr LoginRoute) Logout(c *gin.Context) {
 	clearSessionCookies(c)
+
+	if err := lr.revokeToken(c); err != nil {
+		ErrorResponse(c, err)
+
+		return
+	}
+
 	c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
 	c.Header("Pragma", "no-cache")
 	c.Header("Expires", "0")
@@ -126,12 +439,52 @@ func (lr LoginRoute) Logout(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{messageKey: "logged out"})
 }
 
+// revokeToken ends the session of the presented token, or revokes the token
+// alone when it belongs to none. Invalid tokens need no revoking.
+func (lr LoginRoute) revokeToken(c *gin.Context) error {
+	tokenString := resolveToken(c)
+	if lr.Tokens == nil || tokenString == "" || apikeys.IsKey(tokenString) {
+		return nil
+	}
+
+	claims, ok := lr.verifyToken(c, tokenString)
+	if !ok {
+		return nil
+	}
+
+	sub, _ := claims["sub"].(string)
+
+	if sid, _ := claims["sid"].(string); sid != "" {
+		return lr.Tokens.End(c.Request.Context(), sid, sub)
+	}
+
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return nil
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return nil
+	}
+
+	return lr.Tokens.Revoke(c.Request.Context(), jti, sub, expiresAt.Time)
+}
+
 // JWTAuthMiddleware accepts either the Authorization header (REST clients) or
 // the session cookie (browser). The header wins, so REST clients are unchanged.
+// API keys are accepted as a bearer credential or in the X-API-Key header.
 func (lr LoginRoute) JWTAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := resolveToken(c)
 
+		// A token, when sent, wins over the client certificate.
+		if cert := clientCertificate(c); tokenString == "" && cert != nil && lr.Config.TLS.ClientCerts.Enabled() {
+			lr.clientCertAuth(c, cert)
+
+			return
+		}
+
 		if tokenString == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{errorKey: "request does not contain an access token"})
 			c.Abort()
@@ -139,23 +492,81 @@ func (lr LoginRoute) JWTAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if !lr.verifyToken(c, tokenString) {
+		if apikeys.IsKey(tokenString) {
+			lr.apiKeyAuth(c, tokenString)
+
+			return
+		}
+
+		claims, ok := lr.verifyToken(c, tokenString)
+		if !ok || isRedirectionToken(claims) {
 			c.JSON(http.StatusUnauthorized, gin.H{errorKey: "invalid access token"})
 			c.Abort()
 
 			return
 		}
 
+		c.Set(rolesContextKey, lr.rolesFromClaims(claims))
+
+		if sub, ok := claims["sub"].(string); ok {
+			c.Set(subjectContextKey, sub)
+		}
+
+		if sid, ok := claims["sid"].(string); ok {
+			c.Set(sessionContextKey, sid)
+		}
+
+		tenantID, named := lr.tenantFromClaims(claims)
+		if !lr.setTenant(c, tenantID, named) {
+			return
+		}
+
 		c.Next()
 	}
 }
 
+// isRedirectionToken reports whether claims belong to a redirection token.
+// Those are signed with the same key but carry no roles, and must not be
+// usable against the API. Tokens issued before the audience was added are
+// recognized by their deviceId.
+func isRedirectionToken(claims jwt.MapClaims) bool {
+	if _, ok := claims["deviceId"]; ok {
+		return true
+	}
+
+	audience, _ := claims.GetAudience()
+
+	for _, aud := range audience {
+		if aud == redirectionAudience {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Subject returns the authenticated user's name from the token subject, or ""
+// when the token names none.
+func Subject(c *gin.Context) string {
+	return c.GetString(subjectContextKey)
+}
+
+// Session returns the login session of the request's token, or "" when it
+// belongs to none.
+func Session(c *gin.Context) string {
+	return c.GetString(sessionContextKey)
+}
+
 // resolveToken prefers the Authorization header, falling back to the cookie.
 func resolveToken(c *gin.Context) string {
 	if header := c.GetHeader(authorizationHeader); header != "" {
 		return strings.Replace(header, bearerPrefix, "", 1)
 	}
 
+	if key := c.GetHeader(apiKeyHeader); key != "" {
+		return key
+	}
+
 	if !cookieAuthEnabled() {
 		return ""
 	}
@@ -168,18 +579,41 @@ func resolveToken(c *gin.Context) string {
 	return cookie
 }
 
-// verifyToken reports whether the token is valid.
-func (lr LoginRoute) verifyToken(c *gin.Context, tokenString string) bool {
+// verifyToken reports whether the token is valid and not revoked, and returns
+// its claims.
+func (lr LoginRoute) verifyToken(c *gin.Context, tokenString string) (jwt.MapClaims, bool) {
+	claims, ok := lr.parseToken(c, tokenString)
+	if !ok || lr.Tokens == nil {
+		return claims, ok
+	}
+
+	jti, _ := claims["jti"].(string)
+	sid, _ := claims["sid"].(string)
+
+	// Fails closed: a token that cannot be checked is not accepted.
+	revoked, err := lr.Tokens.IsRevoked(c.Request.Context(), jti, sid)
+	if err != nil || revoked {
+		return nil, false
+	}
+
+	return claims, true
+}
+
+// parseToken checks the token's signature and expiry.
+func (lr LoginRoute) parseToken(c *gin.Context, tokenString string) (jwt.MapClaims, bool) {
+	claims := jwt.MapClaims{}
+
 	// if clientID is set, use the oidc verifier
 	if config.ConsoleConfig.ClientID != "" {
-		_, err := lr.Verifier.Verify(c.Request.Context(), tokenString)
+		idToken, err := lr.Verifier.Verify(c.Request.Context(), tokenString)
+		if err != nil {
+			return nil, false
+		}
 
-		return err == nil
+		return claims, idToken.Claims(&claims) == nil
 	}
 
-	claims := &jwt.MapClaims{}
-
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("%w: %v", ErrUnexpectedSigningMethod, token.Header["alg"])
 		}
@@ -187,7 +621,7 @@ func (lr LoginRoute) verifyToken(c *gin.Context, tokenString string) bool {
 		return []byte(lr.Config.JWTKey), nil
 	})
 
-	return err == nil && token.Valid
+	return claims, err == nil && token.Valid
 }
 
 // cookieAuthEnabled reports whether session cookies are in use. An unconfigured
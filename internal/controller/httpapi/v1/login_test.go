@@ -2,6 +2,7 @@ package v1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -9,9 +10,20 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 
 	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/tenancy"
+	"github.com/device-management-toolkit/console/internal/usecase/ldapauth"
+	"github.com/device-management-toolkit/console/internal/usecase/lockouts"
+	tokenusecase "github.com/device-management-toolkit/console/internal/usecase/tokens"
+	"github.com/device-management-toolkit/console/internal/usecase/users"
+	"github.com/device-management-toolkit/console/pkg/logger"
 )
 
 const (
@@ -313,7 +325,7 @@ func TestNewLoginRoute(t *testing.T) {
 	t.Run("no ClientID returns route with nil Verifier", func(t *testing.T) {
 		config.ConsoleConfig = &config.Config{}
 
-		lr := NewLoginRoute(&config.Config{})
+		lr := NewLoginRoute(&config.Config{}, nil, nil, nil, nil, nil)
 
 		require.NotNil(t, lr)
 		require.Nil(t, lr.Verifier)
@@ -327,7 +339,7 @@ func TestNewLoginRoute(t *testing.T) {
 		config.ConsoleConfig.Issuer = srv.URL
 		config.ConsoleConfig.TLSSkipVerify = true
 
-		lr := NewLoginRoute(&config.Config{})
+		lr := NewLoginRoute(&config.Config{}, nil, nil, nil, nil, nil)
 
 		require.NotNil(t, lr, "expected provider discovery to succeed with TLSSkipVerify=true")
 		require.NotNil(t, lr.Verifier)
@@ -341,8 +353,278 @@ func TestNewLoginRoute(t *testing.T) {
 		config.ConsoleConfig.Issuer = srv.URL
 		config.ConsoleConfig.TLSSkipVerify = false
 
-		lr := NewLoginRoute(&config.Config{})
+		lr := NewLoginRoute(&config.Config{}, nil, nil, nil, nil, nil)
 
 		require.Nil(t, lr, "expected provider discovery to fail against self-signed cert without skip verify")
 	})
 }
+
+const testRefreshURL = "/api/v1/authorize/refresh"
+
+// newTokenTestEngine wires authorize, refresh, logout and one protected route
+// against a mocked token store.
+func newTokenTestEngine(t *testing.T) (*mocks.MockTokensFeature, *gin.Engine) {
+	t.Helper()
+
+	cfg := cookieAuthTestConfig()
+	cfg.CookieEnabled = false
+	cfg.RolesClaim = "roles"
+
+	prev := config.ConsoleConfig
+
+	t.Cleanup(func() { config.ConsoleConfig = prev })
+
+	config.ConsoleConfig = cfg
+
+	tokens := mocks.NewMockTokensFeature(gomock.NewController(t))
+	route := LoginRoute{Config: cfg, Tokens: tokens}
+
+	engine := gin.New()
+	engine.POST(testAuthorizeURL, route.Login)
+	engine.POST(testRefreshURL, route.Refresh)
+	engine.POST(testLogoutURL, route.Logout)
+	engine.GET(testProtectedURL, route.JWTAuthMiddleware(), func(c *gin.Context) { c.String(http.StatusOK, Session(c)) })
+
+	return tokens, engine
+}
+
+func tokenClaims(t *testing.T, token string) jwt.MapClaims {
+	t.Helper()
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) { return []byte(testJWTKey), nil })
+	require.NoError(t, err)
+
+	return claims
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestLoginStartsRevocableSession(t *testing.T) {
+	tokens, engine := newTokenTestEngine(t)
+
+	tokens.EXPECT().Start(gomock.Any(), testAdminUser, "").
+		Return(&dto.TokenSession{ID: "session-1", Username: testAdminUser, RefreshToken: "refresh-1"}, nil)
+
+	w := postJSON(t, engine, testAuthorizeURL, testCredsBody)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]string
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, "refresh-1", body["refreshToken"])
+
+	claims := tokenClaims(t, body["token"])
+	require.Equal(t, "session-1", claims["sid"])
+
+	jti, _ := claims["jti"].(string)
+	require.NotEmpty(t, jti)
+
+	gomock.InOrder(
+		tokens.EXPECT().IsRevoked(gomock.Any(), jti, "session-1").Return(false, nil),
+		tokens.EXPECT().IsRevoked(gomock.Any(), jti, "session-1").Return(true, nil),
+		tokens.EXPECT().IsRevoked(gomock.Any(), jti, "session-1").Return(false, repoerrors.DatabaseError{}),
+	)
+
+	w = get(t, engine, withBearer(body["token"]))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "session-1", w.Body.String())
+
+	require.Equal(t, http.StatusUnauthorized, get(t, engine, withBearer(body["token"])).Code, "a revoked token is refused")
+	require.Equal(t, http.StatusUnauthorized, get(t, engine, withBearer(body["token"])).Code, "an unchecked token is refused")
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestRefresh(t *testing.T) {
+	tokens, engine := newTokenTestEngine(t)
+
+	t.Run("rotates the refresh token", func(t *testing.T) {
+		tokens.EXPECT().Refresh(gomock.Any(), "refresh-1").
+			Return(&dto.TokenSession{ID: "session-1", Username: testAdminUser, RefreshToken: "refresh-2"}, nil)
+
+		w := postJSON(t, engine, testRefreshURL, `{"refreshToken":"refresh-1"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body map[string]string
+
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Equal(t, "refresh-2", body["refreshToken"])
+
+		claims := tokenClaims(t, body["token"])
+		require.Equal(t, "session-1", claims["sid"])
+		require.Equal(t, []interface{}{"super-admin"}, claims["roles"])
+	})
+
+	t.Run("refuses an invalid refresh token", func(t *testing.T) {
+		tokens.EXPECT().Refresh(gomock.Any(), "refresh-1").Return(nil, tokenusecase.ErrInvalidRefreshToken)
+
+		require.Equal(t, http.StatusUnauthorized, postJSON(t, engine, testRefreshURL, `{"refreshToken":"refresh-1"}`).Code)
+	})
+
+	t.Run("ends the session of a user who may no longer log in", func(t *testing.T) {
+		tokens.EXPECT().Refresh(gomock.Any(), "refresh-3").
+			Return(&dto.TokenSession{ID: "session-2", Username: "jdoe", RefreshToken: "refresh-4"}, nil)
+		tokens.EXPECT().End(gomock.Any(), "session-2", "jdoe").Return(nil)
+
+		require.Equal(t, http.StatusUnauthorized, postJSON(t, engine, testRefreshURL, `{"refreshToken":"refresh-3"}`).Code)
+	})
+
+	t.Run("requires a refresh token", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, postJSON(t, engine, testRefreshURL, `{}`).Code)
+	})
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestLogoutEndsSession(t *testing.T) {
+	tokens, engine := newTokenTestEngine(t)
+
+	token := signedToken(t, jwt.MapClaims{"sub": testAdminUser, "jti": "token-1", "sid": "session-1"})
+
+	tokens.EXPECT().IsRevoked(gomock.Any(), "token-1", "session-1").Return(false, nil)
+	tokens.EXPECT().End(gomock.Any(), "session-1", testAdminUser).Return(nil)
+
+	req, err := http.NewRequest(http.MethodPost, testLogoutURL, http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestLoginThrottled(t *testing.T) {
+	cfg := cookieAuthTestConfig()
+	cfg.CookieEnabled = false
+
+	prev := config.ConsoleConfig
+
+	t.Cleanup(func() { config.ConsoleConfig = prev })
+
+	config.ConsoleConfig = cfg
+
+	lockout := lockouts.New(logger.New("error"), config.LoginLockout{MaxAttempts: 2, Duration: time.Minute})
+	route := LoginRoute{Config: cfg, Lockouts: lockout}
+
+	engine := gin.New()
+	engine.POST(testAuthorizeURL, route.Login)
+
+	wrong := `{"username":"admin","password":"wrong"}`
+
+	require.Equal(t, http.StatusUnauthorized, postJSON(t, engine, testAuthorizeURL, wrong).Code)
+	require.Equal(t, http.StatusUnauthorized, postJSON(t, engine, testAuthorizeURL, wrong).Code)
+
+	// Locked out: even the right password is refused.
+	w := postJSON(t, engine, testAuthorizeURL, testCredsBody)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "60", w.Header().Get("Retry-After"))
+
+	require.NoError(t, lockout.Clear(context.Background(), lockouts.KindUsername, "Admin"))
+	require.Equal(t, http.StatusOK, postJSON(t, engine, testAuthorizeURL, testCredsBody).Code)
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestDirectoryLogin(t *testing.T) {
+	cfg := cookieAuthTestConfig()
+	cfg.CookieEnabled = false
+	cfg.RolesClaim = "roles"
+
+	prev := config.ConsoleConfig
+
+	t.Cleanup(func() { config.ConsoleConfig = prev })
+
+	config.ConsoleConfig = cfg
+
+	mockCtl := gomock.NewController(t)
+	localUsers := mocks.NewMockUsersFeature(mockCtl)
+	directory := mocks.NewMockLDAPFeature(mockCtl)
+	route := LoginRoute{Config: cfg, Users: localUsers, LDAP: directory}
+
+	engine := gin.New()
+	engine.POST(testAuthorizeURL, route.Login)
+
+	directoryUser := &dto.User{Username: "jdoe", Role: "operator", TenantID: tenancy.Default}
+
+	t.Run("falls back to the directory for unknown local users", func(t *testing.T) {
+		localUsers.EXPECT().Authenticate(gomock.Any(), "jdoe", "pw").Return(nil, users.ErrUnknownUser)
+		directory.EXPECT().Authenticate(gomock.Any(), "jdoe", "pw").Return(directoryUser, nil)
+
+		w := postJSON(t, engine, testAuthorizeURL, `{"username":"jdoe","password":"pw"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body map[string]string
+
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+		claims := tokenClaims(t, body["token"])
+		require.Equal(t, "jdoe", claims["sub"])
+		require.Equal(t, []interface{}{"operator"}, claims["roles"])
+	})
+
+	t.Run("does not ask the directory about a local user's wrong password", func(t *testing.T) {
+		localUsers.EXPECT().Authenticate(gomock.Any(), "alice", "wrong").Return(nil, users.ErrInvalidCredentials)
+
+		require.Equal(t, http.StatusUnauthorized, postJSON(t, engine, testAuthorizeURL, `{"username":"alice","password":"wrong"}`).Code)
+	})
+
+	t.Run("rejects a wrong directory password", func(t *testing.T) {
+		localUsers.EXPECT().Authenticate(gomock.Any(), "jdoe", "wrong").Return(nil, users.ErrUnknownUser)
+		directory.EXPECT().Authenticate(gomock.Any(), "jdoe", "wrong").Return(nil, ldapauth.ErrInvalidCredentials)
+
+		require.Equal(t, http.StatusUnauthorized, postJSON(t, engine, testAuthorizeURL, `{"username":"jdoe","password":"wrong"}`).Code)
+	})
+
+	t.Run("forbids directory users without a role", func(t *testing.T) {
+		localUsers.EXPECT().Authenticate(gomock.Any(), "guest", "pw").Return(nil, users.ErrUnknownUser)
+		directory.EXPECT().Authenticate(gomock.Any(), "guest", "pw").Return(nil, ldapauth.ErrNoRole)
+
+		require.Equal(t, http.StatusForbidden, postJSON(t, engine, testAuthorizeURL, `{"username":"guest","password":"pw"}`).Code)
+	})
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestDirectoryRefresh(t *testing.T) {
+	cfg := cookieAuthTestConfig()
+	cfg.CookieEnabled = false
+	cfg.RolesClaim = "roles"
+
+	prev := config.ConsoleConfig
+
+	t.Cleanup(func() { config.ConsoleConfig = prev })
+
+	config.ConsoleConfig = cfg
+
+	mockCtl := gomock.NewController(t)
+	tokens := mocks.NewMockTokensFeature(mockCtl)
+	localUsers := mocks.NewMockUsersFeature(mockCtl)
+	directory := mocks.NewMockLDAPFeature(mockCtl)
+	route := LoginRoute{Config: cfg, Users: localUsers, Tokens: tokens, LDAP: directory}
+
+	engine := gin.New()
+	engine.POST(testRefreshURL, route.Refresh)
+
+	t.Run("takes the current role from the directory", func(t *testing.T) {
+		tokens.EXPECT().Refresh(gomock.Any(), "refresh-1").
+			Return(&dto.TokenSession{ID: "session-1", Username: "jdoe", TenantID: tenancy.Default, RefreshToken: "refresh-2"}, nil)
+		localUsers.EXPECT().GetByUsername(gomock.Any(), "jdoe", tenancy.Default).Return(nil, repoerrors.NotFoundError{})
+		directory.EXPECT().Lookup(gomock.Any(), "jdoe").Return(&dto.User{Username: "jdoe", Role: "viewer"}, nil)
+
+		w := postJSON(t, engine, testRefreshURL, `{"refreshToken":"refresh-1"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body map[string]string
+
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Equal(t, []interface{}{"viewer"}, tokenClaims(t, body["token"])["roles"])
+	})
+
+	t.Run("ends the session of a user gone from the directory", func(t *testing.T) {
+		tokens.EXPECT().Refresh(gomock.Any(), "refresh-3").
+			Return(&dto.TokenSession{ID: "session-2", Username: "jdoe", TenantID: tenancy.Default, RefreshToken: "refresh-4"}, nil)
+		localUsers.EXPECT().GetByUsername(gomock.Any(), "jdoe", tenancy.Default).Return(nil, repoerrors.NotFoundError{})
+		directory.EXPECT().Lookup(gomock.Any(), "jdoe").Return(nil, ldapauth.ErrInvalidCredentials)
+		tokens.EXPECT().End(gomock.Any(), "session-2", "jdoe").Return(nil)
+
+		require.Equal(t, http.StatusUnauthorized, postJSON(t, engine, testRefreshURL, `{"refreshToken":"refresh-3"}`).Code)
+	})
+}
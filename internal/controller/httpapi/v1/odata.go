@@ -1,12 +1,16 @@
 package v1
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/odata"
 )
 
 const (
@@ -31,6 +35,8 @@ var (
 	ErrNegativeValue = errors.New("parameter must be non-negative")
 	// ErrInvalidBoolean is returned when a boolean query parameter is not a valid boolean.
 	ErrInvalidBoolean = errors.New("parameter must be a boolean value")
+
+	errFilterWithSearch = fmt.Errorf("%w: $filter and $orderby cannot be combined with hostname, friendlyName or tags", odata.ErrInvalidQuery)
 )
 
 // ValidationError represents a query parameter validation error with additional context.
@@ -46,8 +52,105 @@ type OData struct {
 	Top   int  `form:"$top,default=25"`
 	Skip  int  `form:"$skip"`
 	Count bool `form:"$count"`
+
+	// Query holds $filter, $orderby and $select once BindQuery has parsed them.
+	Query *odata.Query `form:"-"`
 }
 
+// The allowlists of the list endpoints' $filter, $orderby and $select
+// fields. Filterable fields must be mapped by both database providers;
+// opaque ones can only be selected.
+var (
+	deviceQueryFields = odata.Fields{
+		"guid":             odata.String,
+		"hostname":         odata.String,
+		"friendlyName":     odata.String,
+		"dnsSuffix":        odata.String,
+		"mpsInstance":      odata.String,
+		"mpsusername":      odata.String,
+		"connectionStatus": odata.Bool,
+		"useTLS":           odata.Bool,
+		"allowSelfSigned":  odata.Bool,
+		"tags":             odata.Opaque,
+		"tenantId":         odata.Opaque,
+		"lastConnected":    odata.Opaque,
+		"lastSeen":         odata.Opaque,
+		"lastDisconnected": odata.Opaque,
+		"deviceInfo":       odata.Opaque,
+		"username":         odata.Opaque,
+		"certHash":         odata.Opaque,
+	}
+
+	profileQueryFields = odata.Fields{
+		"profileName":                odata.String,
+		"activation":                 odata.String,
+		"ciraConfigName":             odata.String,
+		"ieee8021xProfileName":       odata.String,
+		"generateRandomPassword":     odata.Bool,
+		"dhcpEnabled":                odata.Bool,
+		"tlsMode":                    odata.Int,
+		"tlsSigningAuthority":        odata.String,
+		"userConsent":                odata.String,
+		"iderEnabled":                odata.Bool,
+		"kvmEnabled":                 odata.Bool,
+		"solEnabled":                 odata.Bool,
+		"generateRandomMEBxPassword": odata.Opaque,
+		"ipSyncEnabled":              odata.Opaque,
+		"localWifiSyncEnabled":       odata.Opaque,
+		"uefiWifiSyncEnabled":        odata.Opaque,
+		"tags":                       odata.Opaque,
+		"wifiConfigs":                odata.Opaque,
+		"ieee8021xProfile":           odata.Opaque,
+		"tenantId":                   odata.Opaque,
+		"version":                    odata.Opaque,
+	}
+
+	domainQueryFields = odata.Fields{
+		"profileName":                   odata.String,
+		"domainSuffix":                  odata.String,
+		"provisioningCertStorageFormat": odata.String,
+		"expirationDate":                odata.Opaque,
+		"tenantId":                      odata.Opaque,
+		"version":                       odata.Opaque,
+	}
+
+	ciraConfigQueryFields = odata.Fields{
+		"configName":             odata.String,
+		"mpsServerAddress":       odata.String,
+		"mpsPort":                odata.Int,
+		"username":               odata.String,
+		"commonName":             odata.String,
+		"serverAddressFormat":    odata.Int,
+		"authMethod":             odata.Int,
+		"generateRandomPassword": odata.Bool,
+		"mpsRootCertificate":     odata.Opaque,
+		"proxyDetails":           odata.Opaque,
+		"tenantId":               odata.Opaque,
+		"version":                odata.Opaque,
+	}
+
+	wirelessConfigQueryFields = odata.Fields{
+		"profileName":            odata.String,
+		"authenticationMethod":   odata.Int,
+		"encryptionMethod":       odata.Int,
+		"ssid":                   odata.String,
+		"ieee8021xProfileName":   odata.String,
+		"linkPolicy":             odata.Opaque,
+		"ieee8021xProfileObject": odata.Opaque,
+		"tenantId":               odata.Opaque,
+		"version":                odata.Opaque,
+	}
+
+	ieee8021xConfigQueryFields = odata.Fields{
+		"profileName":            odata.String,
+		"authenticationProtocol": odata.Int,
+		"pxeTimeout":             odata.Int,
+		"wiredInterface":         odata.Bool,
+		"tenantId":               odata.Opaque,
+		"version":                odata.Opaque,
+	}
+)
+
 // BindAndValidate binds query parameters and validates them against overflow and range limits.
 func (o *OData) BindAndValidate(c *gin.Context) error {
 	// Validate $top parameter
@@ -85,6 +188,93 @@ func (o *OData) BindAndValidate(c *gin.Context) error {
 	return nil
 }
 
+// BindQuery binds and validates the paging parameters like BindAndValidate,
+// then parses $filter, $orderby and $select against fields. A $filter or
+// $orderby is attached to the request's context for the repositories;
+// $select is applied by JSON.
+func (o *OData) BindQuery(c *gin.Context, fields odata.Fields) error {
+	if err := o.BindAndValidate(c); err != nil {
+		return err
+	}
+
+	q, err := odata.Parse(c.Query("$filter"), c.Query("$orderby"), c.Query("$select"), fields)
+	if err != nil {
+		return err
+	}
+
+	o.Query = q
+
+	if o.Filtered() {
+		c.Request = c.Request.WithContext(odata.WithQuery(c.Request.Context(), q))
+	}
+
+	return nil
+}
+
+// Filtered reports whether the query has a $filter or $orderby.
+func (o *OData) Filtered() bool {
+	return o.Query != nil && (o.Query.Filter != nil || len(o.Query.OrderBy) > 0)
+}
+
+// JSON writes body with status 200, keeping only the $select fields of each
+// item. body is a list of items or a count response with the list in data.
+func (o *OData) JSON(c *gin.Context, body any) {
+	if o.Query == nil || len(o.Query.Select) == 0 {
+		c.JSON(http.StatusOK, body)
+
+		return
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	if wrapper, ok := decoded.(map[string]any); ok {
+		wrapper["data"] = o.project(wrapper["data"])
+		c.JSON(http.StatusOK, wrapper)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, o.project(decoded))
+}
+
+func (o *OData) project(items any) any {
+	list, ok := items.([]any)
+	if !ok {
+		return items
+	}
+
+	for i, item := range list {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		selected := make(map[string]any, len(o.Query.Select))
+
+		for _, name := range o.Query.Select {
+			if v, ok := fields[name]; ok {
+				selected[name] = v
+			}
+		}
+
+		list[i] = selected
+	}
+
+	return list
+}
+
 // parseAndValidateInt safely parses a string to int and validates range.
 func parseAndValidateInt(value string, maxAllowed int) (int, error) {
 	// First try to parse as int64 to detect overflow beyond int range
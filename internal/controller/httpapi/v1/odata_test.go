@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"net/http"
@@ -10,6 +11,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/odata"
 )
 
 func TestOData_BindAndValidate(t *testing.T) {
@@ -255,3 +260,55 @@ func TestParseAndValidateInt(t *testing.T) {
 		})
 	}
 }
+
+func TestOData_BindQuery(t *testing.T) {
+	t.Parallel()
+
+	domain, engine := domainsTest(t)
+
+	var queried *odata.Query
+
+	domain.EXPECT().Get(gomock.Any(), 25, 0, "").DoAndReturn(func(ctx context.Context, _, _ int, _ string) ([]dto.Domain, error) {
+		queried = odata.FromContext(ctx)
+
+		return []dto.Domain{{ProfileName: "lab", DomainSuffix: "lab.example.com", TenantID: "t1"}}, nil
+	})
+	domain.EXPECT().GetCount(gomock.Any(), "").DoAndReturn(func(ctx context.Context, _ string) (int, error) {
+		require.Same(t, queried, odata.FromContext(ctx), "the count honours the filter")
+
+		return 1, nil
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet,
+		"/api/v1/admin/domains?$count=true&$filter=startswith(profileName,'la')&$orderby=domainSuffix%20desc&$select=profileName,tenantId", http.NoBody)
+	engine.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"totalCount":1,"data":[{"profileName":"lab","tenantId":"t1"}]}`, w.Body.String())
+	require.Equal(t, &odata.Expr{Op: odata.StartsWith, Field: "profileName", Value: "la"}, queried.Filter)
+	require.Equal(t, []odata.Order{{Field: "domainSuffix", Desc: true}}, queried.OrderBy)
+}
+
+func TestOData_BindQueryRejects(t *testing.T) {
+	t.Parallel()
+
+	_, domains := domainsTest(t)
+	_, devices := devicesTest(t)
+
+	for _, tc := range []struct {
+		engine *gin.Engine
+		url    string
+	}{
+		{domains, "/api/v1/admin/domains?$filter=provisioningCertPassword%20eq%20'x'"},
+		{domains, "/api/v1/admin/domains?$orderby=expirationDate"},
+		{domains, "/api/v1/admin/domains?$select=provisioningCert"},
+		{devices, "/api/v1/devices?$filter=contains(hostname,'lab')&tags=site-a"},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, tc.url, http.NoBody)
+		tc.engine.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code, tc.url)
+	}
+}
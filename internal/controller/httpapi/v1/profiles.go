@@ -34,7 +34,7 @@ func NewProfileRoutes(handler *gin.RouterGroup, t profiles.Feature, l logger.Int
 		}
 	}
 
-	h := handler.Group("/profiles")
+	h := handler.Group("/profiles", IfMatchMiddleware())
 	{
 		h.GET("", r.get)
 		h.GET(":name", r.getByName)
@@ -47,14 +47,14 @@ func NewProfileRoutes(handler *gin.RouterGroup, t profiles.Feature, l logger.Int
 
 func (r *profileRoutes) get(c *gin.Context) {
 	var odata OData
-	if err := odata.BindAndValidate(c); err != nil {
-		validationErr := ErrValidationProfile.Wrap("get", "BindAndValidate", err)
+	if err := odata.BindQuery(c, profileQueryFields); err != nil {
+		validationErr := ErrValidationProfile.Wrap("get", "BindQuery", err)
 		ErrorResponse(c, validationErr)
 
 		return
 	}
 
-	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, "")
+	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - get")
 		ErrorResponse(c, err)
@@ -63,7 +63,7 @@ func (r *profileRoutes) get(c *gin.Context) {
 	}
 
 	if odata.Count {
-		count, err := r.t.GetCount(c.Request.Context(), "")
+		count, err := r.t.GetCount(c.Request.Context(), Tenant(c))
 		if err != nil {
 			r.l.Error(err, "http - v1 - getCount")
 			ErrorResponse(c, err)
@@ -74,16 +74,16 @@ func (r *profileRoutes) get(c *gin.Context) {
 			Data:  items,
 		}
 
-		c.JSON(http.StatusOK, countResponse)
+		odata.JSON(c, countResponse)
 	} else {
-		c.JSON(http.StatusOK, items)
+		odata.JSON(c, items)
 	}
 }
 
 func (r *profileRoutes) getByName(c *gin.Context) {
 	name := c.Param("name")
 
-	item, err := r.t.GetByName(c.Request.Context(), name, "")
+	item, err := r.t.GetByName(c.Request.Context(), name, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - getByName")
 		ErrorResponse(c, err)
@@ -91,6 +91,7 @@ func (r *profileRoutes) getByName(c *gin.Context) {
 		return
 	}
 
+	setETag(c, item.Version)
 	c.JSON(http.StatusOK, item)
 }
 
@@ -98,7 +99,7 @@ func (r *profileRoutes) export(c *gin.Context) {
 	name := c.Param("name")
 	domainName := c.Query("domainName")
 
-	item, key, err := r.t.Export(c.Request.Context(), name, domainName, "")
+	item, key, err := r.t.Export(c.Request.Context(), name, domainName, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - export")
 		ErrorResponse(c, err)
@@ -125,6 +126,8 @@ func (r *profileRoutes) insert(c *gin.Context) {
 		return
 	}
 
+	profile.TenantID = Tenant(c)
+
 	newProfile, err := r.t.Insert(c.Request.Context(), &profile)
 	if err != nil {
 		r.l.Error(err, "http - v1 - insert")
@@ -133,6 +136,7 @@ func (r *profileRoutes) insert(c *gin.Context) {
 		return
 	}
 
+	setETag(c, newProfile.Version)
 	c.JSON(http.StatusCreated, newProfile)
 }
 
@@ -161,6 +165,8 @@ func (r *profileRoutes) update(c *gin.Context) {
 		return
 	}
 
+	profile.TenantID = Tenant(c)
+
 	updatedProfile, err := r.t.Update(c.Request.Context(), &profile, fields)
 	if err != nil {
 		r.l.Error(err, "http - v1 - update")
@@ -169,13 +175,14 @@ func (r *profileRoutes) update(c *gin.Context) {
 		return
 	}
 
+	setETag(c, updatedProfile.Version)
 	c.JSON(http.StatusOK, updatedProfile)
 }
 
 func (r *profileRoutes) delete(c *gin.Context) {
 	name := c.Param("name")
 
-	err := r.t.Delete(c.Request.Context(), name, "")
+	err := r.t.Delete(c.Request.Context(), name, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - delete")
 		ErrorResponse(c, err)
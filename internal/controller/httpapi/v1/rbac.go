@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/device-management-toolkit/console/internal/rbac"
+)
+
+const (
+	rolesContextKey   = "roles"
+	defaultRolesClaim = "roles"
+)
+
+// ginParam matches gin path parameters so routes can be looked up by their
+// OpenAPI template.
+var ginParam = regexp.MustCompile(`[:*]([^/]+)`)
+
+// RBACMiddleware rejects requests whose token roles lack the permission the
+// route requires. It runs after JWTAuthMiddleware, which resolves the roles.
+func (lr LoginRoute) RBACMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Unmatched requests carry no route; let them 404.
+		if c.FullPath() == "" {
+			c.Next()
+
+			return
+		}
+
+		route := routeTemplate(c.FullPath())
+
+		permission, ok := rbac.Required(c.Request.Method, route)
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{
+				errorKey:   "forbidden",
+				messageKey: "no permission is defined for " + c.Request.Method + " " + route,
+			})
+			c.Abort()
+
+			return
+		}
+
+		roles := Roles(c)
+		if !rbac.Allowed(roles, permission) {
+			c.JSON(http.StatusForbidden, gin.H{
+				errorKey: "forbidden",
+				messageKey: describeRoles(roles) + " lacks the " + string(permission) +
+					" permission required for " + c.Request.Method + " " + route,
+			})
+			c.Abort()
+
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Roles returns the roles JWTAuthMiddleware resolved for the request.
+func Roles(c *gin.Context) []rbac.Role {
+	roles, _ := c.Get(rolesContextKey)
+	r, _ := roles.([]rbac.Role)
+
+	return r
+}
+
+// rolesFromClaims reads roles from the roles claim and maps the groups claim
+// through the configured group mappings. Unknown names are ignored; an IdP
+// token with no known role gets the default role, if any. Tokens the console
+// signs itself always name their role, so one without gets none.
+func (lr LoginRoute) rolesFromClaims(claims jwt.MapClaims) []rbac.Role {
+	var roles []rbac.Role
+
+	for _, name := range claimStrings(claims[lr.rolesClaim()]) {
+		if role, ok := rbac.ParseRole(name); ok {
+			roles = append(roles, role)
+		}
+	}
+
+	if lr.Config.GroupsClaim != "" {
+		for _, group := range claimStrings(claims[lr.Config.GroupsClaim]) {
+			if role, ok := rbac.ParseRole(lr.Config.GroupRoles[group]); ok {
+				roles = append(roles, role)
+			}
+		}
+	}
+
+	if len(roles) == 0 && lr.Config.ClientID != "" {
+		if role, ok := rbac.ParseRole(lr.Config.DefaultRole); ok {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+func (lr LoginRoute) rolesClaim() string {
+	if lr.Config.RolesClaim != "" {
+		return lr.Config.RolesClaim
+	}
+
+	return defaultRolesClaim
+}
+
+// claimStrings accepts a claim holding one name, a list of names or a space
+// separated string, as IdPs differ.
+func claimStrings(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+
+		return values
+	default:
+		return nil
+	}
+}
+
+// routeTemplate converts a gin route (/devices/:guid) to the OpenAPI template
+// (/devices/{guid}) the permission table uses.
+func routeTemplate(fullPath string) string {
+	return ginParam.ReplaceAllString(fullPath, "{$1}")
+}
+
+func describeRoles(roles []rbac.Role) string {
+	if len(roles) == 0 {
+		return "a token with no role"
+	}
+
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+
+	return "role " + strings.Join(names, ", ")
+}
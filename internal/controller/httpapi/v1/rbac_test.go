@@ -0,0 +1,203 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+const testPowerActionURL = "/api/v1/amt/power/action/:guid"
+
+func newRBACTestEngine(t *testing.T, cfg *config.Config) *gin.Engine {
+	t.Helper()
+
+	prev := config.ConsoleConfig
+
+	t.Cleanup(func() { config.ConsoleConfig = prev })
+
+	config.ConsoleConfig = cfg
+
+	route := LoginRoute{Config: cfg}
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	engine := gin.New()
+	engine.POST(testAuthorizeURL, route.Login)
+
+	api := engine.Group("", route.JWTAuthMiddleware(), route.RBACMiddleware())
+	api.GET(testProtectedURL, ok)
+	api.POST(testPowerActionURL, ok)
+	api.GET("/api/v1/unlisted", ok)
+
+	return engine
+}
+
+func signedToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTKey))
+	require.NoError(t, err)
+
+	return token
+}
+
+func call(t *testing.T, engine *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req, err := http.NewRequest(method, path, http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	return w
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestRBACMiddleware(t *testing.T) {
+	cfg := cookieAuthTestConfig()
+	cfg.RolesClaim = "roles"
+	engine := newRBACTestEngine(t, cfg)
+
+	t.Run("read-only may read", func(t *testing.T) {
+		token := signedToken(t, jwt.MapClaims{"roles": []string{"read-only"}})
+
+		require.Equal(t, http.StatusOK, call(t, engine, http.MethodGet, testProtectedURL, token).Code)
+	})
+
+	t.Run("read-only may not power cycle", func(t *testing.T) {
+		token := signedToken(t, jwt.MapClaims{"roles": "read-only"})
+
+		w := call(t, engine, http.MethodPost, "/api/v1/amt/power/action/guid", token)
+		require.Equal(t, http.StatusForbidden, w.Code)
+
+		var body map[string]string
+
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Equal(t, "forbidden", body[errorKey])
+		require.Equal(t, "role read-only lacks the devices:control permission required for POST /api/v1/amt/power/action/{guid}", body[messageKey])
+	})
+
+	t.Run("helpdesk may power cycle", func(t *testing.T) {
+		token := signedToken(t, jwt.MapClaims{"roles": []string{"helpdesk"}})
+
+		require.Equal(t, http.StatusOK, call(t, engine, http.MethodPost, "/api/v1/amt/power/action/guid", token).Code)
+	})
+
+	t.Run("token without a role is denied", func(t *testing.T) {
+		token := signedToken(t, jwt.MapClaims{})
+
+		require.Equal(t, http.StatusForbidden, call(t, engine, http.MethodGet, testProtectedURL, token).Code)
+	})
+
+	t.Run("route missing from the table is denied", func(t *testing.T) {
+		token := signedToken(t, jwt.MapClaims{"roles": []string{"admin"}})
+
+		require.Equal(t, http.StatusForbidden, call(t, engine, http.MethodGet, "/api/v1/unlisted", token).Code)
+	})
+
+	t.Run("local login is admin", func(t *testing.T) {
+		token, _ := login(t, engine)
+
+		require.Equal(t, http.StatusOK, call(t, engine, http.MethodPost, "/api/v1/amt/power/action/guid", token).Code)
+	})
+}
+
+// A redirection token is signed with the same key as access tokens; it must
+// not open the API, where it would get no role of its own.
+//
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestRedirectionTokenIsNotAnAccessToken(t *testing.T) {
+	cfg := cookieAuthTestConfig()
+	cfg.RedirectionJWTExpiration = 5 * time.Minute
+
+	prev := config.ConsoleConfig
+
+	t.Cleanup(func() { config.ConsoleConfig = prev })
+
+	config.ConsoleConfig = cfg
+
+	device := mocks.NewMockDeviceManagementFeature(gomock.NewController(t))
+	device.EXPECT().GetByID(gomock.Any(), "guid", "", false).Return(&dto.Device{GUID: "guid"}, nil)
+
+	route := LoginRoute{Config: cfg}
+	engine := gin.New()
+	NewDeviceRoutes(engine.Group("/api/v1", route.JWTAuthMiddleware(), route.RBACMiddleware()), device, logger.New("error"))
+
+	w := call(t, engine, http.MethodGet, "/api/v1/authorize/redirection/guid", signedToken(t, jwt.MapClaims{"roles": []string{"helpdesk"}, "sub": "jdoe"}))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]string
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+	for _, defaultRole := range []string{"", "admin"} {
+		cfg.DefaultRole = defaultRole
+
+		require.Equal(t, http.StatusUnauthorized, call(t, engine, http.MethodGet, testProtectedURL, body["token"]).Code)
+	}
+}
+
+func TestRolesFromClaims(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	cfg.ClientID = "console"
+	cfg.RolesClaim = "console_roles"
+	cfg.GroupsClaim = "groups"
+	cfg.GroupRoles = map[string]string{"it-helpdesk": "helpdesk", "ops": "operator"}
+	cfg.DefaultRole = "read-only"
+
+	lr := LoginRoute{Config: cfg}
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   []rbac.Role
+	}{
+		{"roles claim", jwt.MapClaims{"console_roles": []interface{}{"Admin", "unknown"}}, []rbac.Role{rbac.RoleAdmin}},
+		{"mapped groups", jwt.MapClaims{"groups": []interface{}{"ops", "staff", "it-helpdesk"}}, []rbac.Role{rbac.RoleOperator, rbac.RoleHelpdesk}},
+		{"default role", jwt.MapClaims{"groups": []interface{}{"staff"}}, []rbac.Role{rbac.RoleReadOnly}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, lr.rolesFromClaims(tc.claims))
+		})
+	}
+
+	t.Run("no default role", func(t *testing.T) {
+		t.Parallel()
+
+		denying := *cfg
+		denying.DefaultRole = ""
+
+		require.Empty(t, LoginRoute{Config: &denying}.rolesFromClaims(jwt.MapClaims{}))
+	})
+
+	t.Run("locally signed token gets no default role", func(t *testing.T) {
+		t.Parallel()
+
+		local := *cfg
+		local.ClientID = ""
+
+		require.Empty(t, LoginRoute{Config: &local}.rolesFromClaims(jwt.MapClaims{}))
+	})
+}
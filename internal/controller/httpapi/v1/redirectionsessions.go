@@ -0,0 +1,53 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+type redirectionSessionRoutes struct {
+	d devices.Feature
+	l logger.Interface
+}
+
+// NewRedirectionSessionRoutes lets administrators see and end active KVM,
+// SOL and IDER sessions.
+func NewRedirectionSessionRoutes(handler *gin.RouterGroup, d devices.Feature, l logger.Interface) {
+	r := &redirectionSessionRoutes{d, l}
+
+	h := handler.Group("/redirection/sessions")
+	{
+		h.GET("", r.get)
+		h.DELETE(":id", r.terminate)
+	}
+}
+
+func (r *redirectionSessionRoutes) get(c *gin.Context) {
+	sessions, err := r.d.GetRedirectionSessions(c.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - getRedirectionSessions")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+func (r *redirectionSessionRoutes) terminate(c *gin.Context) {
+	id := c.Param("id")
+
+	err := r.d.TerminateRedirectionSession(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - terminateRedirectionSession")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func redirectionSessionsTest(t *testing.T) (*mocks.MockDeviceManagementFeature, *gin.Engine) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	feature := mocks.NewMockDeviceManagementFeature(mockCtl)
+
+	engine := gin.New()
+	handler := engine.Group("/api/v1/admin")
+
+	NewRedirectionSessionRoutes(handler, feature, logger.New("error"))
+
+	return feature, engine
+}
+
+func TestRedirectionSessionRoutes(t *testing.T) {
+	t.Parallel()
+
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	sessions := []dto.RedirectionSession{{ID: "abc", GUID: "guid", Mode: "kvm", StartedAt: started, LastInputAt: started}}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		mock         func(*mocks.MockDeviceManagementFeature)
+		response     interface{}
+		expectedCode int
+	}{
+		{
+			name:   "list sessions",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/redirection/sessions",
+			mock: func(f *mocks.MockDeviceManagementFeature) {
+				f.EXPECT().GetRedirectionSessions(context.Background()).Return(sessions, nil)
+			},
+			response:     sessions,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "terminate session",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/redirection/sessions/abc",
+			mock: func(f *mocks.MockDeviceManagementFeature) {
+				f.EXPECT().TerminateRedirectionSession(context.Background(), "abc").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "terminate unknown session",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/redirection/sessions/missing",
+			mock: func(f *mocks.MockDeviceManagementFeature) {
+				f.EXPECT().TerminateRedirectionSession(context.Background(), "missing").
+					Return(devices.ErrNotFound.WrapWithMessage("TerminateRedirectionSession", "uc.sessions", "redirection session not found"))
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			feature, engine := redirectionSessionsTest(t)
+			tc.mock(feature)
+
+			req, err := http.NewRequestWithContext(context.Background(), tc.method, tc.url, http.NoBody)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.response != nil {
+				expected, _ := json.Marshal(tc.response)
+				require.JSONEq(t, string(expected), w.Body.String())
+			}
+		})
+	}
+}
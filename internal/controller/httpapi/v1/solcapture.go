@@ -0,0 +1,62 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+// startSOLCapture begins recording the device's serial console in the background
+func (r *deviceManagementRoutes) startSOLCapture(c *gin.Context) {
+	guid := c.Param("guid")
+
+	var req dto.SOLCaptureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	capture, err := r.d.StartSOLCapture(c.Request.Context(), guid, req)
+	if err != nil {
+		r.l.Error(err, "http - v1 - startSOLCapture")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusAccepted, capture)
+}
+
+// getSOLCaptures lists the captures kept for the device
+func (r *deviceManagementRoutes) getSOLCaptures(c *gin.Context) {
+	guid := c.Param("guid")
+
+	captures, err := r.d.GetSOLCaptures(c.Request.Context(), guid)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getSOLCaptures")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, captures)
+}
+
+// getSOLCapture returns one capture with the text recorded so far
+func (r *deviceManagementRoutes) getSOLCapture(c *gin.Context) {
+	guid := c.Param("guid")
+	id := c.Param("id")
+
+	capture, err := r.d.GetSOLCapture(c.Request.Context(), guid, id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getSOLCapture")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, capture)
+}
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func solCaptureTest(t *testing.T) (*mocks.MockDeviceManagementFeature, *gin.Engine) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	deviceManagement := mocks.NewMockDeviceManagementFeature(mockCtl)
+
+	engine := gin.New()
+	handler := engine.Group("/api/v1")
+	NewAmtRoutes(handler, deviceManagement, mocks.NewMockAMTExplorerFeature(mockCtl), mocks.NewMockExporter(mockCtl), logger.New("error"))
+
+	return deviceManagement, engine
+}
+
+func TestSOLCaptureRoutes(t *testing.T) {
+	t.Parallel()
+
+	capture := dto.SOLCapture{ID: "capture1", GUID: "guid1", Status: devices.SOLCaptureRunning, Duration: 60, Pattern: "login:"}
+
+	t.Run("start", func(t *testing.T) {
+		t.Parallel()
+
+		deviceManagement, engine := solCaptureTest(t)
+		req := dto.SOLCaptureRequest{Duration: 60, PowerAction: 10, Pattern: "login:"}
+		deviceManagement.EXPECT().StartSOLCapture(context.Background(), "guid1", req).Return(capture, nil)
+
+		body, _ := json.Marshal(req)
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/amt/sol/capture/guid1", bytes.NewReader(body)))
+
+		require.Equal(t, http.StatusAccepted, rr.Code)
+
+		var got dto.SOLCapture
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+		require.Equal(t, capture, got)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		t.Parallel()
+
+		deviceManagement, engine := solCaptureTest(t)
+		deviceManagement.EXPECT().GetSOLCaptures(context.Background(), "guid1").Return([]dto.SOLCapture{capture}, nil)
+
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/amt/sol/capture/guid1", http.NoBody))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("get unknown capture", func(t *testing.T) {
+		t.Parallel()
+
+		deviceManagement, engine := solCaptureTest(t)
+		deviceManagement.EXPECT().GetSOLCapture(context.Background(), "guid1", "missing").
+			Return(dto.SOLCapture{}, devices.ErrNotFound.WrapWithMessage("GetSOLCapture", "uc.captures", "SOL capture not found"))
+
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/amt/sol/capture/guid1/missing", http.NoBody))
+
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		require.Contains(t, rr.Body.String(), "SOL capture not found")
+	})
+}
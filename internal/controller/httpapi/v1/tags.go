@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/usecase/tags"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var ErrValidationTags = dto.NotValidError{Console: consoleerrors.CreateConsoleError("TagsAPI")}
+
+type tagRoutes struct {
+	t tags.Feature
+	l logger.Interface
+}
+
+// NewTagRoutes -.
+func NewTagRoutes(handler *gin.RouterGroup, t tags.Feature, l logger.Interface) {
+	r := &tagRoutes{t, l}
+
+	h := handler.Group("/tags")
+	{
+		h.GET("", r.get)
+		h.PUT(":tag", r.rename)
+		h.DELETE(":tag", r.delete)
+		h.POST("merge", r.merge)
+		h.POST("devices", r.update)
+	}
+}
+
+func (r *tagRoutes) get(c *gin.Context) {
+	items, err := r.t.Get(c.Request.Context(), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - get")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (r *tagRoutes) rename(c *gin.Context) {
+	var rename dto.TagRename
+	if err := c.ShouldBindJSON(&rename); err != nil {
+		validationErr := ErrValidationTags.Wrap("rename", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	changed, err := r.t.Rename(c.Request.Context(), c.Param("tag"), rename.Name, Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - rename")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TagsUpdated{Devices: changed})
+}
+
+func (r *tagRoutes) delete(c *gin.Context) {
+	err := r.t.Delete(c.Request.Context(), c.Param("tag"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - delete")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (r *tagRoutes) merge(c *gin.Context) {
+	var merge dto.TagMerge
+	if err := c.ShouldBindJSON(&merge); err != nil {
+		validationErr := ErrValidationTags.Wrap("merge", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	changed, err := r.t.Merge(c.Request.Context(), merge, Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - merge")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TagsUpdated{Devices: changed})
+}
+
+// update adds and removes tags on a selection of devices.
+func (r *tagRoutes) update(c *gin.Context) {
+	var update dto.TagUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		validationErr := ErrValidationTags.Wrap("update", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	changed, err := r.t.Update(c.Request.Context(), update, Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - update")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TagsUpdated{Devices: changed})
+}
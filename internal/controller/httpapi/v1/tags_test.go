@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/tags"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func TestTagRoutes(t *testing.T) {
+	t.Parallel()
+
+	selection := dto.DeviceSelection{Groups: []string{"g1"}}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         interface{}
+		mock         func(feature *mocks.MockTagsFeature)
+		response     interface{}
+		expectedCode int
+	}{
+		{
+			name:   "get tags",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/tags",
+			mock: func(feature *mocks.MockTagsFeature) {
+				feature.EXPECT().Get(context.Background(), "").Return([]dto.Tag{{Name: "lab", Devices: 2}}, nil)
+			},
+			response:     []dto.Tag{{Name: "lab", Devices: 2}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "rename tag",
+			method: http.MethodPut,
+			url:    "/api/v1/admin/tags/lab",
+			body:   dto.TagRename{Name: "bench"},
+			mock: func(feature *mocks.MockTagsFeature) {
+				feature.EXPECT().Rename(context.Background(), "lab", "bench", "").Return(2, nil)
+			},
+			response:     dto.TagsUpdated{Devices: 2},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "rename tag - not found",
+			method: http.MethodPut,
+			url:    "/api/v1/admin/tags/missing",
+			body:   dto.TagRename{Name: "bench"},
+			mock: func(feature *mocks.MockTagsFeature) {
+				feature.EXPECT().Rename(context.Background(), "missing", "bench", "").Return(0, tags.ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "delete tag",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/tags/lab",
+			mock: func(feature *mocks.MockTagsFeature) {
+				feature.EXPECT().Delete(context.Background(), "lab", "").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "merge tags",
+			method: http.MethodPost,
+			url:    "/api/v1/admin/tags/merge",
+			body:   dto.TagMerge{Tags: []string{"site-a-old"}, Into: "site-a"},
+			mock: func(feature *mocks.MockTagsFeature) {
+				feature.EXPECT().
+					Merge(context.Background(), dto.TagMerge{Tags: []string{"site-a-old"}, Into: "site-a"}, "").
+					Return(3, nil)
+			},
+			response:     dto.TagsUpdated{Devices: 3},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "update device tags",
+			method: http.MethodPost,
+			url:    "/api/v1/admin/tags/devices",
+			body:   dto.TagUpdate{Devices: selection, Add: []string{"lab"}},
+			mock: func(feature *mocks.MockTagsFeature) {
+				feature.EXPECT().
+					Update(context.Background(), dto.TagUpdate{Devices: selection, Add: []string{"lab"}}, "").
+					Return(4, nil)
+			},
+			response:     dto.TagsUpdated{Devices: 4},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			feature := mocks.NewMockTagsFeature(gomock.NewController(t))
+			tc.mock(feature)
+
+			engine := gin.New()
+			handler := engine.Group("/api/v1/admin")
+			NewTagRoutes(handler, feature, logger.New("error"))
+
+			var body bytes.Buffer
+
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+
+			req, err := http.NewRequest(tc.method, tc.url, &body)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.response != nil {
+				expected, err := json.Marshal(tc.response)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/internal/tenancy"
+)
+
+// tenantHeader names the tenant a super-admin acts in, in place of their own.
+const tenantHeader = "X-Tenant-ID"
+
+// setTenant scopes the request to tenantID, the caller's own tenant, or to
+// the one named in the X-Tenant-ID header when the caller may act in any
+// tenant. named reports whether the caller's identity names a tenant at all.
+// It reports false, having responded, when the request is refused.
+func (lr LoginRoute) setTenant(c *gin.Context, tenantID string, named bool) bool {
+	crossTenant := rbac.Allowed(Roles(c), rbac.TenantsAll)
+
+	if requested := c.Request.Header.Values(tenantHeader); len(requested) > 0 && requested[0] != tenantID {
+		if !crossTenant {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				errorKey:   "forbidden",
+				messageKey: "acting in another tenant requires the " + string(rbac.TenantsAll) + " permission",
+			})
+
+			return false
+		}
+
+		tenantID, named = requested[0], true
+	}
+
+	if !named && lr.Config.RequireTenant && !crossTenant {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			errorKey:   "forbidden",
+			messageKey: "the access token names no tenant",
+		})
+
+		return false
+	}
+
+	c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), tenantID))
+
+	return true
+}
+
+// Tenant returns the tenant the request acts in. Every repository call made
+// for the request is scoped to it.
+func Tenant(c *gin.Context) string {
+	return tenancy.FromContext(c.Request.Context())
+}
+
+// tenantFromClaims reads the configured tenant claim. ok is false when the
+// token carries none.
+func (lr LoginRoute) tenantFromClaims(claims jwt.MapClaims) (tenantID string, ok bool) {
+	if lr.Config.TenantClaim == "" {
+		return tenancy.Default, false
+	}
+
+	tenantID, ok = claims[lr.Config.TenantClaim].(string)
+
+	return tenantID, ok && tenantID != ""
+}
+
+// mayGrant reports whether the caller may give role to a user or API key,
+// responding when not. Only callers who act across tenants may create more
+// of themselves; anyone else could use it to leave their tenant.
+func mayGrant(c *gin.Context, role string) bool {
+	parsed, _ := rbac.ParseRole(role)
+	if parsed != rbac.RoleSuperAdmin || rbac.Allowed(Roles(c), rbac.TenantsAll) {
+		return true
+	}
+
+	c.JSON(http.StatusForbidden, gin.H{
+		errorKey:   "forbidden",
+		messageKey: "granting the " + string(rbac.RoleSuperAdmin) + " role requires the " + string(rbac.TenantsAll) + " permission",
+	})
+
+	return false
+}
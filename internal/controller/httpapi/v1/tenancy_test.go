@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/config"
+)
+
+const testTenantURL = "/api/v1/tenant"
+
+func newTenantTestEngine(t *testing.T, cfg *config.Config) *gin.Engine {
+	t.Helper()
+
+	prev := config.ConsoleConfig
+
+	t.Cleanup(func() { config.ConsoleConfig = prev })
+
+	config.ConsoleConfig = cfg
+
+	route := LoginRoute{Config: cfg}
+
+	engine := gin.New()
+	engine.POST(testAuthorizeURL, route.Login)
+	engine.GET(testTenantURL, route.JWTAuthMiddleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, Tenant(c))
+	})
+	engine.POST(testTenantURL, route.JWTAuthMiddleware(), func(c *gin.Context) {
+		if mayGrant(c, c.Query("role")) {
+			c.Status(http.StatusOK)
+		}
+	})
+
+	return engine
+}
+
+func callAsTenant(t *testing.T, engine *gin.Engine, method, path, token, tenantID string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req, err := http.NewRequest(method, path, http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	if tenantID != "" {
+		req.Header.Set(tenantHeader, tenantID)
+	}
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	return w
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestTenantFromIdentity(t *testing.T) {
+	cfg := cookieAuthTestConfig()
+	cfg.RolesClaim = "roles"
+	cfg.TenantClaim = "tenantId"
+	engine := newTenantTestEngine(t, cfg)
+
+	operator := signedToken(t, jwt.MapClaims{"roles": "operator", "tenantId": "acme"})
+	superAdmin := signedToken(t, jwt.MapClaims{"roles": "super-admin"})
+
+	t.Run("tenant comes from the token", func(t *testing.T) {
+		w := callAsTenant(t, engine, http.MethodGet, testTenantURL, operator, "")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "acme", w.Body.String())
+	})
+
+	t.Run("naming the own tenant is allowed", func(t *testing.T) {
+		w := callAsTenant(t, engine, http.MethodGet, testTenantURL, operator, "acme")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "acme", w.Body.String())
+	})
+
+	t.Run("naming another tenant is forbidden", func(t *testing.T) {
+		w := callAsTenant(t, engine, http.MethodGet, testTenantURL, operator, "globex")
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("super-admin may act in another tenant", func(t *testing.T) {
+		w := callAsTenant(t, engine, http.MethodGet, testTenantURL, superAdmin, "globex")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "globex", w.Body.String())
+	})
+
+	t.Run("local login is super-admin", func(t *testing.T) {
+		token, _ := login(t, engine)
+		w := callAsTenant(t, engine, http.MethodGet, testTenantURL, token, "globex")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "globex", w.Body.String())
+	})
+
+	t.Run("only super-admin may grant super-admin", func(t *testing.T) {
+		path := testTenantURL + "?role=super-admin"
+
+		require.Equal(t, http.StatusForbidden, callAsTenant(t, engine, http.MethodPost, path, operator, "").Code)
+		require.Equal(t, http.StatusOK, callAsTenant(t, engine, http.MethodPost, path, superAdmin, "").Code)
+		require.Equal(t, http.StatusOK, callAsTenant(t, engine, http.MethodPost, testTenantURL+"?role=admin", operator, "").Code)
+	})
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestRequireTenant(t *testing.T) {
+	cfg := cookieAuthTestConfig()
+	cfg.RolesClaim = "roles"
+	cfg.TenantClaim = "tenantId"
+	cfg.RequireTenant = true
+	engine := newTenantTestEngine(t, cfg)
+
+	t.Run("token without a tenant is refused", func(t *testing.T) {
+		token := signedToken(t, jwt.MapClaims{"roles": "admin"})
+
+		require.Equal(t, http.StatusForbidden, callAsTenant(t, engine, http.MethodGet, testTenantURL, token, "").Code)
+	})
+
+	t.Run("token with a tenant is accepted", func(t *testing.T) {
+		token := signedToken(t, jwt.MapClaims{"roles": "admin", "tenantId": "acme"})
+
+		require.Equal(t, http.StatusOK, callAsTenant(t, engine, http.MethodGet, testTenantURL, token, "").Code)
+	})
+
+	t.Run("super-admin needs no tenant", func(t *testing.T) {
+		token := signedToken(t, jwt.MapClaims{"roles": "super-admin"})
+
+		require.Equal(t, http.StatusOK, callAsTenant(t, engine, http.MethodGet, testTenantURL, token, "").Code)
+	})
+}
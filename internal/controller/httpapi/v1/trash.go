@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var ErrValidationTrash = dto.NotValidError{Console: consoleerrors.CreateConsoleError("TrashAPI")}
+
+type trashRoutes struct {
+	t trash.Feature
+	l logger.Interface
+}
+
+// NewTrashRoutes -. Kind is one of the entity.TrashKinds.
+func NewTrashRoutes(handler *gin.RouterGroup, t trash.Feature, l logger.Interface) {
+	r := &trashRoutes{t, l}
+
+	h := handler.Group("/trash")
+	{
+		h.GET(":kind", r.get)
+		h.POST(":kind/:id/restore", r.restore)
+		h.DELETE(":kind/:id", r.purge)
+	}
+}
+
+func (r *trashRoutes) get(c *gin.Context) {
+	var odata OData
+	if err := odata.BindAndValidate(c); err != nil {
+		validationErr := ErrValidationTrash.Wrap("get", "BindAndValidate", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	items, err := r.t.Get(c.Request.Context(), c.Param("kind"), odata.Top, odata.Skip, Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - get")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	if odata.Count {
+		count, err := r.t.GetCount(c.Request.Context(), c.Param("kind"), Tenant(c))
+		if err != nil {
+			r.l.Error(err, "http - v1 - getCount")
+			ErrorResponse(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.TrashCountResponse{
+			Count: count,
+			Data:  items,
+		})
+	} else {
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func (r *trashRoutes) restore(c *gin.Context) {
+	err := r.t.Restore(c.Request.Context(), c.Param("kind"), c.Param("id"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - restore")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (r *trashRoutes) purge(c *gin.Context) {
+	err := r.t.Purge(c.Request.Context(), c.Param("kind"), c.Param("id"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - purge")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
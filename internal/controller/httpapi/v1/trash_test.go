@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func TestTrashRoutes(t *testing.T) {
+	t.Parallel()
+
+	item := dto.TrashItem{Kind: "devices", ID: "guid", Name: "host", DeletedAt: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		mock         func(feature *mocks.MockTrashFeature)
+		response     interface{}
+		expectedCode int
+	}{
+		{
+			name:   "list trashed devices",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/trash/devices?$count=true",
+			mock: func(feature *mocks.MockTrashFeature) {
+				feature.EXPECT().Get(context.Background(), "devices", 25, 0, "").Return([]dto.TrashItem{item}, nil)
+				feature.EXPECT().GetCount(context.Background(), "devices", "").Return(1, nil)
+			},
+			response:     dto.TrashCountResponse{Count: 1, Data: []dto.TrashItem{item}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "list unknown kind",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/trash/users",
+			mock: func(feature *mocks.MockTrashFeature) {
+				feature.EXPECT().Get(context.Background(), "users", 25, 0, "").Return(nil, trash.ErrNotValid)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "restore device",
+			method: http.MethodPost,
+			url:    "/api/v1/admin/trash/devices/guid/restore",
+			mock: func(feature *mocks.MockTrashFeature) {
+				feature.EXPECT().Restore(context.Background(), "devices", "guid", "").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "restore device - not in the trash",
+			method: http.MethodPost,
+			url:    "/api/v1/admin/trash/devices/missing/restore",
+			mock: func(feature *mocks.MockTrashFeature) {
+				feature.EXPECT().Restore(context.Background(), "devices", "missing", "").Return(trash.ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "purge profile",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/trash/profiles/acm",
+			mock: func(feature *mocks.MockTrashFeature) {
+				feature.EXPECT().Purge(context.Background(), "profiles", "acm", "").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			feature := mocks.NewMockTrashFeature(gomock.NewController(t))
+			tc.mock(feature)
+
+			engine := gin.New()
+			NewTrashRoutes(engine.Group("/api/v1/admin"), feature, logger.New("error"))
+
+			req, err := http.NewRequest(tc.method, tc.url, http.NoBody)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.response != nil {
+				expected, err := json.Marshal(tc.response)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/usecase/tokens"
+	"github.com/device-management-toolkit/console/internal/usecase/users"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var (
+	ErrValidationUsers = dto.NotValidError{Console: consoleerrors.CreateConsoleError("UsersAPI")}
+
+	errReservedUsername = errors.New("username is reserved for the built-in admin account")
+)
+
+type userRoutes struct {
+	t      users.Feature
+	tokens tokens.Feature
+	l      logger.Interface
+	cfg    *config.Config
+}
+
+func NewUserRoutes(handler *gin.RouterGroup, t users.Feature, tk tokens.Feature, l logger.Interface, cfg *config.Config) {
+	r := &userRoutes{t, tk, l, cfg}
+
+	h := handler.Group("/users")
+	{
+		h.GET("", r.get)
+		h.GET(":username", r.getByUsername)
+		h.POST("", r.insert)
+		h.PATCH("", r.update)
+		h.DELETE(":username", r.delete)
+		h.DELETE(":username/sessions", r.endSessions)
+	}
+}
+
+type UserCountResponse struct {
+	Count int        `json:"totalCount"`
+	Data  []dto.User `json:"data"`
+}
+
+func (r *userRoutes) get(c *gin.Context) {
+	var odata OData
+	if err := odata.BindAndValidate(c); err != nil {
+		validationErr := ErrValidationUsers.Wrap("get", "BindAndValidate", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - get")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	if odata.Count {
+		count, err := r.t.GetCount(c.Request.Context(), Tenant(c))
+		if err != nil {
+			r.l.Error(err, "http - v1 - getCount")
+			ErrorResponse(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, UserCountResponse{
+			Count: count,
+			Data:  items,
+		})
+	} else {
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func (r *userRoutes) getByUsername(c *gin.Context) {
+	item, err := r.t.GetByUsername(c.Request.Context(), c.Param("username"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getByUsername")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (r *userRoutes) insert(c *gin.Context) {
+	var user dto.User
+	if err := c.ShouldBindJSON(&user); err != nil {
+		validationErr := ErrValidationUsers.Wrap("insert", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	// The built-in admin is checked first at login, so a namesake could
+	// never sign in.
+	if strings.EqualFold(user.Username, r.cfg.AdminUsername) {
+		ErrorResponse(c, ErrValidationUsers.Wrap("insert", "AdminUsername", errReservedUsername))
+
+		return
+	}
+
+	if !mayGrant(c, user.Role) {
+		return
+	}
+
+	user.CreatedBy = Subject(c)
+	user.TenantID = Tenant(c)
+
+	newUser, err := r.t.Insert(c.Request.Context(), &user)
+	if err != nil {
+		r.l.Error(err, "http - v1 - insert")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, newUser)
+}
+
+func (r *userRoutes) update(c *gin.Context) {
+	var user dto.User
+	if err := c.ShouldBindJSON(&user); err != nil {
+		validationErr := ErrValidationUsers.Wrap("update", "ShouldBindJSON", err)
+		ErrorResponse(c, validationErr)
+
+		return
+	}
+
+	if !mayGrant(c, user.Role) {
+		return
+	}
+
+	user.TenantID = Tenant(c)
+
+	updatedUser, err := r.t.Update(c.Request.Context(), &user)
+	if err != nil {
+		r.l.Error(err, "http - v1 - update")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedUser)
+}
+
+// delete also ends the user's sessions, so tokens issued to them stop working.
+func (r *userRoutes) delete(c *gin.Context) {
+	err := r.t.Delete(c.Request.Context(), c.Param("username"), Tenant(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - delete")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	if _, err := r.tokens.EndAll(c.Request.Context(), c.Param("username"), Tenant(c)); err != nil {
+		r.l.Error(err, "http - v1 - delete")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// endSessions revokes every token issued to a user, and their refresh tokens.
+// The built-in admin has sessions too, though it is not a stored account.
+func (r *userRoutes) endSessions(c *gin.Context) {
+	if _, err := r.tokens.EndAll(c.Request.Context(), c.Param("username"), Tenant(c)); err != nil {
+		r.l.Error(err, "http - v1 - endSessions")
+		ErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,263 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/users"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func usersTest(t *testing.T) (*mocks.MockUsersFeature, *mocks.MockTokensFeature, *gin.Engine) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	feature := mocks.NewMockUsersFeature(mockCtl)
+	tokens := mocks.NewMockTokensFeature(mockCtl)
+
+	engine := gin.New()
+	handler := engine.Group("/api/v1/admin", func(c *gin.Context) { c.Set(subjectContextKey, "admin") })
+
+	NewUserRoutes(handler, feature, tokens, logger.New("error"), &config.Config{Auth: config.Auth{AdminUsername: "admin"}})
+
+	return feature, tokens, engine
+}
+
+func TestUserRoutes(t *testing.T) {
+	t.Parallel()
+
+	user := dto.User{Username: "jdoe", Role: "operator"}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         interface{}
+		mock         func(feature *mocks.MockUsersFeature, tokens *mocks.MockTokensFeature)
+		response     interface{}
+		expectedCode int
+	}{
+		{
+			name:   "get all users - with count",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/users?$top=10&$skip=1&$count=true",
+			mock: func(feature *mocks.MockUsersFeature, _ *mocks.MockTokensFeature) {
+				feature.EXPECT().Get(context.Background(), 10, 1, "").Return([]dto.User{user}, nil)
+				feature.EXPECT().GetCount(context.Background(), "").Return(1, nil)
+			},
+			response:     UserCountResponse{Count: 1, Data: []dto.User{user}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "get user by username",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/users/jdoe",
+			mock: func(feature *mocks.MockUsersFeature, _ *mocks.MockTokensFeature) {
+				feature.EXPECT().GetByUsername(context.Background(), "jdoe", "").Return(&user, nil)
+			},
+			response:     user,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "get user by username - not found",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/users/ghost",
+			mock: func(feature *mocks.MockUsersFeature, _ *mocks.MockTokensFeature) {
+				feature.EXPECT().GetByUsername(context.Background(), "ghost", "").Return(nil, users.ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "insert user records the creator",
+			method: http.MethodPost,
+			url:    "/api/v1/admin/users",
+			body:   dto.User{Username: "jdoe", Role: "operator", Password: "Correct-Horse-42"},
+			mock: func(feature *mocks.MockUsersFeature, _ *mocks.MockTokensFeature) {
+				feature.EXPECT().
+					Insert(context.Background(), &dto.User{Username: "jdoe", Role: "operator", Password: "Correct-Horse-42", CreatedBy: "admin"}).
+					Return(&user, nil)
+			},
+			response:     user,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "insert user - reserved username",
+			method:       http.MethodPost,
+			url:          "/api/v1/admin/users",
+			body:         dto.User{Username: "Admin", Role: "admin", Password: "Correct-Horse-42"},
+			mock:         func(_ *mocks.MockUsersFeature, _ *mocks.MockTokensFeature) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "update user",
+			method: http.MethodPatch,
+			url:    "/api/v1/admin/users",
+			body:   user,
+			mock: func(feature *mocks.MockUsersFeature, _ *mocks.MockTokensFeature) {
+				feature.EXPECT().Update(context.Background(), &user).Return(&user, nil)
+			},
+			response:     user,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "delete user",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/users/jdoe",
+			mock: func(feature *mocks.MockUsersFeature, tokens *mocks.MockTokensFeature) {
+				feature.EXPECT().Delete(context.Background(), "jdoe", "").Return(nil)
+				tokens.EXPECT().EndAll(context.Background(), "jdoe", "").Return(2, nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "end user sessions",
+			method: http.MethodDelete,
+			url:    "/api/v1/admin/users/jdoe/sessions",
+			mock: func(_ *mocks.MockUsersFeature, tokens *mocks.MockTokensFeature) {
+				tokens.EXPECT().EndAll(context.Background(), "jdoe", "").Return(1, nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			feature, tokens, engine := usersTest(t)
+
+			tc.mock(feature, tokens)
+
+			var body bytes.Buffer
+
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+
+			req, err := http.NewRequest(tc.method, tc.url, &body)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.response != nil {
+				expected, err := json.Marshal(tc.response)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), w.Body.String())
+			}
+		})
+	}
+}
+
+// newUserLoginEngine wires authorize and password change against a mocked
+// user store.
+func newUserLoginEngine(t *testing.T) (*mocks.MockUsersFeature, *gin.Engine) {
+	t.Helper()
+
+	cfg := cookieAuthTestConfig()
+	cfg.CookieEnabled = false
+
+	prev := config.ConsoleConfig
+
+	t.Cleanup(func() { config.ConsoleConfig = prev })
+
+	config.ConsoleConfig = cfg
+
+	feature := mocks.NewMockUsersFeature(gomock.NewController(t))
+	route := LoginRoute{Config: cfg, Users: feature}
+
+	engine := gin.New()
+	engine.POST(testAuthorizeURL, route.Login)
+	engine.POST("/api/v1/authorize/password", route.ChangePassword)
+	engine.GET(testProtectedURL, route.JWTAuthMiddleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, Subject(c))
+	})
+
+	return feature, engine
+}
+
+func postJSON(t *testing.T, engine *gin.Engine, url, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	return w
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestLoginLocalUser(t *testing.T) {
+	feature, engine := newUserLoginEngine(t)
+
+	feature.EXPECT().
+		Authenticate(gomock.Any(), "jdoe", "Correct-Horse-42").
+		Return(&dto.User{Username: "jdoe", Role: "helpdesk"}, nil)
+
+	w := postJSON(t, engine, testAuthorizeURL, `{"username":"jdoe","password":"Correct-Horse-42"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]string
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(body["token"], &claims, func(*jwt.Token) (interface{}, error) { return []byte(testJWTKey), nil })
+	require.NoError(t, err)
+	require.Equal(t, "jdoe", claims["sub"])
+	require.Equal(t, []interface{}{"helpdesk"}, claims["roles"])
+
+	// The subject is available to handlers for attribution.
+	w = get(t, engine, withBearer(body["token"]))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "jdoe", w.Body.String())
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestLoginLocalUserRejected(t *testing.T) {
+	feature, engine := newUserLoginEngine(t)
+
+	feature.EXPECT().Authenticate(gomock.Any(), "jdoe", "wrong").Return(nil, users.ErrInvalidCredentials)
+	feature.EXPECT().Authenticate(gomock.Any(), "jdoe", "Initial-Passw0rd").Return(nil, users.ErrPasswordChangeRequired)
+
+	w := postJSON(t, engine, testAuthorizeURL, `{"username":"jdoe","password":"wrong"}`)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = postJSON(t, engine, testAuthorizeURL, `{"username":"jdoe","password":"Initial-Passw0rd"}`)
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), "password change required")
+}
+
+//nolint:paralleltest // shared global config.ConsoleConfig
+func TestChangePassword(t *testing.T) {
+	feature, engine := newUserLoginEngine(t)
+
+	change := dto.PasswordChange{Username: "jdoe", Password: "Initial-Passw0rd", NewPassword: "Correct-Horse-42"}
+	body := `{"username":"jdoe","password":"Initial-Passw0rd","newPassword":"Correct-Horse-42"}`
+
+	feature.EXPECT().ChangePassword(gomock.Any(), change).Return(nil)
+	feature.EXPECT().ChangePassword(gomock.Any(), change).Return(users.ErrInvalidCredentials)
+	feature.EXPECT().ChangePassword(gomock.Any(), change).Return(users.ErrNotValid.Wrap("ChangePassword", "checkPasswordPolicy", errReservedUsername))
+
+	require.Equal(t, http.StatusNoContent, postJSON(t, engine, "/api/v1/authorize/password", body).Code)
+	require.Equal(t, http.StatusUnauthorized, postJSON(t, engine, "/api/v1/authorize/password", body).Code)
+	require.Equal(t, http.StatusBadRequest, postJSON(t, engine, "/api/v1/authorize/password", body).Code)
+}
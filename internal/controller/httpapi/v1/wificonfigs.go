@@ -32,7 +32,7 @@ func NewWirelessConfigRoutes(handler *gin.RouterGroup, t wificonfigs.Feature, l
 		}
 	}
 
-	h := handler.Group("/wirelessconfigs")
+	h := handler.Group("/wirelessconfigs", IfMatchMiddleware())
 	{
 		h.GET("", r.get)
 		h.GET(":profileName", r.getByName)
@@ -44,14 +44,14 @@ func NewWirelessConfigRoutes(handler *gin.RouterGroup, t wificonfigs.Feature, l
 
 func (r *WirelessConfigRoutes) get(c *gin.Context) {
 	var odata OData
-	if err := odata.BindAndValidate(c); err != nil {
-		validationErr := ErrValidationWifiConfig.Wrap("get", "BindAndValidate", err)
+	if err := odata.BindQuery(c, wirelessConfigQueryFields); err != nil {
+		validationErr := ErrValidationWifiConfig.Wrap("get", "BindQuery", err)
 		ErrorResponse(c, validationErr)
 
 		return
 	}
 
-	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, "")
+	items, err := r.t.Get(c.Request.Context(), odata.Top, odata.Skip, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - wireless configs - v1 - getCount")
 		ErrorResponse(c, err)
@@ -60,7 +60,7 @@ func (r *WirelessConfigRoutes) get(c *gin.Context) {
 	}
 
 	if odata.Count {
-		count, err := r.t.GetCount(c.Request.Context(), "")
+		count, err := r.t.GetCount(c.Request.Context(), Tenant(c))
 		if err != nil {
 			r.l.Error(err, "http - wireless configs - v1 - getCount")
 			ErrorResponse(c, err)
@@ -71,16 +71,16 @@ func (r *WirelessConfigRoutes) get(c *gin.Context) {
 			Data:  items,
 		}
 
-		c.JSON(http.StatusOK, countResponse)
+		odata.JSON(c, countResponse)
 	} else {
-		c.JSON(http.StatusOK, items)
+		odata.JSON(c, items)
 	}
 }
 
 func (r *WirelessConfigRoutes) getByName(c *gin.Context) {
 	profileName := c.Param("profileName")
 
-	config, err := r.t.GetByName(c.Request.Context(), profileName, "")
+	config, err := r.t.GetByName(c.Request.Context(), profileName, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - wireless configs - v1 - getByName")
 		ErrorResponse(c, err)
@@ -88,6 +88,7 @@ func (r *WirelessConfigRoutes) getByName(c *gin.Context) {
 		return
 	}
 
+	setETag(c, config.Version)
 	c.JSON(http.StatusOK, config)
 }
 
@@ -100,6 +101,8 @@ func (r *WirelessConfigRoutes) insert(c *gin.Context) {
 		return
 	}
 
+	config.TenantID = Tenant(c)
+
 	insertedConfig, err := r.t.Insert(c.Request.Context(), &config)
 	if err != nil {
 		r.l.Error(err, "http - wireless configs - v1 - insert")
@@ -109,6 +112,7 @@ func (r *WirelessConfigRoutes) insert(c *gin.Context) {
 		return
 	}
 
+	setETag(c, insertedConfig.Version)
 	c.JSON(http.StatusCreated, insertedConfig)
 }
 
@@ -121,6 +125,8 @@ func (r *WirelessConfigRoutes) update(c *gin.Context) {
 		return
 	}
 
+	config.TenantID = Tenant(c)
+
 	updatedWirelessConfig, err := r.t.Update(c.Request.Context(), &config)
 	if err != nil {
 		r.l.Error(err, "http - wireless configs - v1 - update")
@@ -129,13 +135,14 @@ func (r *WirelessConfigRoutes) update(c *gin.Context) {
 		return
 	}
 
+	setETag(c, updatedWirelessConfig.Version)
 	c.JSON(http.StatusOK, updatedWirelessConfig)
 }
 
 func (r *WirelessConfigRoutes) delete(c *gin.Context) {
 	configName := c.Param("profileName")
 
-	err := r.t.Delete(c.Request.Context(), configName, "")
+	err := r.t.Delete(c.Request.Context(), configName, Tenant(c))
 	if err != nil {
 		r.l.Error(err, "http - wireless configs - v1 - delete")
 		ErrorResponse(c, err)
@@ -47,7 +47,7 @@ type wifiConfigTest struct {
 
 var (
 	requestWiFiConfig  = dto.WirelessConfig{AuthenticationMethod: 4, EncryptionMethod: 3, SSID: "exampleSSID", PSKValue: 12345, PSKPassphrase: "examplepassphrase", ProfileName: "newprofile", LinkPolicy: []int{1, 2, 3}, TenantID: "tenant1", Version: "1.0"}
-	responseWiFiConfig = dto.WirelessConfig{AuthenticationMethod: 4, EncryptionMethod: 3, SSID: "exampleSSID", PSKValue: 12345, PSKPassphrase: "examplepassphrase", ProfileName: "newprofile", LinkPolicy: []int{1, 2, 3}, TenantID: "tenant1", Version: "1.0"}
+	responseWiFiConfig = dto.WirelessConfig{AuthenticationMethod: 4, EncryptionMethod: 3, SSID: "exampleSSID", PSKValue: 12345, PSKPassphrase: "examplepassphrase", ProfileName: "newprofile", LinkPolicy: []int{1, 2, 3}, TenantID: "", Version: "1.0"}
 )
 
 func TestWiFiConfigRoutes(t *testing.T) {
@@ -124,7 +124,7 @@ func TestWiFiConfigRoutes(t *testing.T) {
 					PSKPassphrase:        "examplepassphrase",
 					ProfileName:          "newprofile",
 					LinkPolicy:           []int{1, 2, 3},
-					TenantID:             "tenant1",
+					TenantID:             "",
 					Version:              "1.0",
 				}
 				wificonfig.EXPECT().Insert(context.Background(), wificonfigTest).Return(wificonfigTest, nil)
@@ -146,7 +146,7 @@ func TestWiFiConfigRoutes(t *testing.T) {
 					PSKPassphrase:        "examplepassphrase",
 					ProfileName:          "newprofile",
 					LinkPolicy:           []int{1, 2, 3},
-					TenantID:             "tenant1",
+					TenantID:             "",
 					Version:              "1.0",
 				}
 				wificonfig.EXPECT().Insert(context.Background(), wificonfigTest).Return(nil, wificonfigs.ErrDatabase)
@@ -168,7 +168,7 @@ func TestWiFiConfigRoutes(t *testing.T) {
 					PSKPassphrase:        "examplepassphrase",
 					ProfileName:          "newprofile",
 					LinkPolicy:           []int{1, 2, 3},
-					TenantID:             "tenant1",
+					TenantID:             "",
 					Version:              "1.0",
 				}
 				wificonfig.EXPECT().Insert(context.Background(), wificonfigTest).Return(nil, wificonfigs.ErrDatabase)
@@ -210,7 +210,7 @@ func TestWiFiConfigRoutes(t *testing.T) {
 					PSKPassphrase:        "examplepassphrase",
 					ProfileName:          "newprofile",
 					LinkPolicy:           []int{1, 2, 3},
-					TenantID:             "tenant1",
+					TenantID:             "",
 					Version:              "1.0",
 				}
 				wificonfig.EXPECT().Update(context.Background(), wificonfigTest).Return(wificonfigTest, nil)
@@ -232,7 +232,7 @@ func TestWiFiConfigRoutes(t *testing.T) {
 					PSKPassphrase:        "examplepassphrase",
 					ProfileName:          "newprofile",
 					LinkPolicy:           []int{1, 2, 3},
-					TenantID:             "tenant1",
+					TenantID:             "",
 					Version:              "1.0",
 				}
 				wificonfig.EXPECT().Update(context.Background(), wificonfigTest).Return(nil, wificonfigs.ErrDatabase)
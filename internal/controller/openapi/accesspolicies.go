@@ -0,0 +1,94 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+type AccessPolicyCountResponse struct {
+	Count int                `json:"totalCount"`
+	Data  []dto.AccessPolicy `json:"data"`
+}
+
+func (f *FuegoAdapter) RegisterAccessPolicyRoutes() {
+	fuego.Get(f.server, "/api/v1/admin/accesspolicies", f.getAccessPolicies,
+		fuego.OptionTags("Access Policies"),
+		fuego.OptionSummary("List Access Policies"),
+		fuego.OptionDescription("Retrieve all access policies with optional pagination"),
+		fuego.OptionQueryInt("$top", "Number of records to return"),
+		fuego.OptionQueryInt("$skip", "Number of records to skip"),
+		fuego.OptionQueryBool("$count", "Include total count"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Get(f.server, "/api/v1/admin/accesspolicies/{id}", f.getAccessPolicyByID,
+		fuego.OptionTags("Access Policies"),
+		fuego.OptionSummary("Get Access Policy by ID"),
+		fuego.OptionDescription("Retrieve an access policy"),
+		fuego.OptionPath("id", "Access policy ID"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Post(f.server, "/api/v1/admin/accesspolicies", f.createAccessPolicy,
+		fuego.OptionTags("Access Policies"),
+		fuego.OptionSummary("Create Access Policy"),
+		fuego.OptionDescription("Grant a user (`user:<username>`) or role (`role:<role>`) rights over the devices "+
+			"carrying any of `tags`, or all of them when `method` is `all`.\n\n"+
+			"Once a policy names a caller, device routes only reach devices their policies match: lookups "+
+			"answer 404, lists leave other devices out, and redirection tokens are refused. `permissions` "+
+			"limits a policy to some device permissions; without it the policy covers them all. The "+
+			"caller's roles must still grant the permission."),
+		fuego.OptionDefaultStatusCode(http.StatusCreated),
+		protectedRouteOptions(),
+	)
+
+	fuego.Put(f.server, "/api/v1/admin/accesspolicies/{id}", f.updateAccessPolicy,
+		fuego.OptionTags("Access Policies"),
+		fuego.OptionSummary("Update Access Policy"),
+		fuego.OptionDescription("Replace an access policy. It applies from the next request."),
+		fuego.OptionPath("id", "Access policy ID"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Delete(f.server, "/api/v1/admin/accesspolicies/{id}", f.deleteAccessPolicy,
+		fuego.OptionTags("Access Policies"),
+		fuego.OptionSummary("Delete Access Policy"),
+		fuego.OptionDescription("Remove an access policy"),
+		fuego.OptionPath("id", "Access policy ID"),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		protectedRouteOptions(),
+	)
+}
+
+func (f *FuegoAdapter) getAccessPolicies(_ fuego.ContextNoBody) (AccessPolicyCountResponse, error) {
+	return AccessPolicyCountResponse{Count: 0, Data: []dto.AccessPolicy{}}, nil
+}
+
+func (f *FuegoAdapter) getAccessPolicyByID(_ fuego.ContextNoBody) (dto.AccessPolicy, error) {
+	return dto.AccessPolicy{}, nil
+}
+
+func (f *FuegoAdapter) createAccessPolicy(c fuego.ContextWithBody[dto.AccessPolicy]) (dto.AccessPolicy, error) {
+	body, err := c.Body()
+	if err != nil {
+		return dto.AccessPolicy{}, err
+	}
+
+	return body, nil
+}
+
+func (f *FuegoAdapter) updateAccessPolicy(c fuego.ContextWithBody[dto.AccessPolicy]) (dto.AccessPolicy, error) {
+	body, err := c.Body()
+	if err != nil {
+		return dto.AccessPolicy{}, err
+	}
+
+	return body, nil
+}
+
+func (f *FuegoAdapter) deleteAccessPolicy(_ fuego.ContextNoBody) (NoContentResponse, error) {
+	return NoContentResponse{}, nil
+}
@@ -23,6 +23,10 @@ const (
 
 	bearerAuthScheme = "bearerAuth"
 	cookieAuthScheme = "cookieAuth"
+	apiKeyAuthScheme = "apiKeyAuth"
+
+	// permissionExtension names the permission an operation requires.
+	permissionExtension = "x-required-permission"
 )
 
 type FuegoAdapter struct {
@@ -52,7 +56,17 @@ func securitySchemes() openapi3.SecuritySchemes {
 			Value: openapi3.NewSecurityScheme().
 				WithType("http").
 				WithScheme("bearer").
-				WithBearerFormat("JWT"),
+				WithBearerFormat("JWT").
+				WithDescription("A token from POST /api/v1/authorize. API keys are accepted here too. Where " +
+					"`http.tls.clientCerts` is configured, a request without a token may instead present a " +
+					"TLS client certificate."),
+		},
+		apiKeyAuthScheme: &openapi3.SecuritySchemeRef{
+			Value: openapi3.NewSecurityScheme().
+				WithType("apiKey").
+				WithIn("header").
+				WithName("X-API-Key").
+				WithDescription("An API key issued by POST /api/v1/admin/apikeys."),
 		},
 	}
 
@@ -102,6 +116,24 @@ func (f *FuegoAdapter) RegisterRoutes() {
 
 	// Server features
 	f.RegisterServerRoutes()
+
+	// Redirection sessions
+	f.RegisterRedirectionSessionRoutes()
+
+	// Users
+	f.RegisterUserRoutes()
+
+	// API keys
+	f.RegisterAPIKeyRoutes()
+
+	// Login lockouts
+	f.RegisterLockoutRoutes()
+	f.RegisterAuditRoutes()
+	f.RegisterAccessPolicyRoutes()
+	f.RegisterBackupRoutes()
+	f.RegisterTrashRoutes()
+	f.RegisterDeviceGroupRoutes()
+	f.RegisterTagRoutes()
 }
 
 // Generates OpenAPI specification as JSON.
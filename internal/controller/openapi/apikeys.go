@@ -0,0 +1,76 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+type APIKeyCountResponse struct {
+	Count int          `json:"totalCount"`
+	Data  []dto.APIKey `json:"data"`
+}
+
+func (f *FuegoAdapter) RegisterAPIKeyRoutes() {
+	fuego.Get(f.server, "/api/v1/admin/apikeys", f.getAPIKeys,
+		fuego.OptionTags("API Keys"),
+		fuego.OptionSummary("List API Keys"),
+		fuego.OptionDescription("Retrieve all API keys with optional pagination. Secrets are never returned."),
+		fuego.OptionQueryInt("$top", "Number of records to return"),
+		fuego.OptionQueryInt("$skip", "Number of records to skip"),
+		fuego.OptionQueryBool("$count", "Include total count"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Get(f.server, "/api/v1/admin/apikeys/{id}", f.getAPIKeyByID,
+		fuego.OptionTags("API Keys"),
+		fuego.OptionSummary("Get API Key by ID"),
+		fuego.OptionDescription("Retrieve an API key, including when it was last used"),
+		fuego.OptionPath("id", "API key ID"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Post(f.server, "/api/v1/admin/apikeys", f.createAPIKey,
+		fuego.OptionTags("API Keys"),
+		fuego.OptionSummary("Create API Key"),
+		fuego.OptionDescription("Issue an API key. The `key` in the response is shown only once; only a hash is stored.\n\n"+
+			"Send it as `Authorization: Bearer <key>` or `X-API-Key: <key>`. The key acts with its `role`. "+
+			"`routes` limits it to matching API paths (a trailing `*` matches any suffix). `tags` limits it "+
+			"to devices carrying one of the tags: routes naming a device are checked, the device list is "+
+			"filtered, and other routes are refused."),
+		fuego.OptionDefaultStatusCode(http.StatusCreated),
+		protectedRouteOptions(),
+	)
+
+	fuego.Delete(f.server, "/api/v1/admin/apikeys/{id}", f.deleteAPIKey,
+		fuego.OptionTags("API Keys"),
+		fuego.OptionSummary("Revoke API Key"),
+		fuego.OptionDescription("Revoke an API key. It stops working immediately."),
+		fuego.OptionPath("id", "API key ID"),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		protectedRouteOptions(),
+	)
+}
+
+func (f *FuegoAdapter) getAPIKeys(_ fuego.ContextNoBody) (APIKeyCountResponse, error) {
+	return APIKeyCountResponse{Count: 0, Data: []dto.APIKey{}}, nil
+}
+
+func (f *FuegoAdapter) getAPIKeyByID(_ fuego.ContextNoBody) (dto.APIKey, error) {
+	return dto.APIKey{}, nil
+}
+
+func (f *FuegoAdapter) createAPIKey(c fuego.ContextWithBody[dto.APIKey]) (dto.APIKeyCreated, error) {
+	body, err := c.Body()
+	if err != nil {
+		return dto.APIKeyCreated{}, err
+	}
+
+	return dto.APIKeyCreated{APIKey: body}, nil
+}
+
+func (f *FuegoAdapter) deleteAPIKey(_ fuego.ContextNoBody) (NoContentResponse, error) {
+	return NoContentResponse{}, nil
+}
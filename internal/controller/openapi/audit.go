@@ -0,0 +1,74 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+type AuditEventCountResponse struct {
+	Count int              `json:"totalCount"`
+	Data  []dto.AuditEvent `json:"data"`
+}
+
+// auditFilterOptions documents the filters shared by the query and export routes.
+func auditFilterOptions() fuego.RouteOption {
+	return fuego.GroupOptions(
+		fuego.OptionQuery("actor", "Only entries by this user or API key"),
+		fuego.OptionQuery("action", "Only this action, such as `POST /api/v1/amt/power/action/{guid}` or `redirection.started`"),
+		fuego.OptionQuery("target", "Only entries for this device GUID or resource"),
+		fuego.OptionQuery("result", "`success`, `failure` or `denied`"),
+		fuego.OptionQuery("from", "Only entries at or after this RFC 3339 time"),
+		fuego.OptionQuery("to", "Only entries at or before this RFC 3339 time"),
+	)
+}
+
+func (f *FuegoAdapter) RegisterAuditRoutes() {
+	fuego.Get(f.server, "/api/v1/admin/audit", f.getAuditEvents,
+		fuego.OptionTags("Audit"),
+		fuego.OptionSummary("Query Audit Trail"),
+		fuego.OptionDescription("List the console's audit trail for the tenant, oldest first: every mutating API "+
+			"call and redirection session, with who made it, from where, and how it ended. Secrets in "+
+			"`parameters` are redacted."),
+		fuego.OptionQueryInt("$top", "Number of records to return"),
+		fuego.OptionQueryInt("$skip", "Number of records to skip"),
+		fuego.OptionQueryBool("$count", "Include total count"),
+		auditFilterOptions(),
+		protectedRouteOptions(),
+	)
+
+	fuego.Get(f.server, "/api/v1/admin/audit/export", f.exportAuditEvents,
+		fuego.OptionTags("Audit"),
+		fuego.OptionSummary("Export Audit Trail"),
+		fuego.OptionDescription("Download every matching entry, oldest first, as CSV or JSON"),
+		fuego.OptionQuery("format", "`csv` (default) or `json`"),
+		auditFilterOptions(),
+		fuego.OptionAddResponse(http.StatusOK, "OK", fuego.Response{Type: "", ContentTypes: []string{"text/csv", "application/json"}}),
+		protectedRouteOptions(),
+	)
+
+	fuego.Get(f.server, "/api/v1/admin/audit/verify", f.verifyAuditEvents,
+		fuego.OptionTags("Audit"),
+		fuego.OptionSummary("Verify Audit Trail"),
+		fuego.OptionDescription("Check the hash chain of the whole audit trail, across tenants. Each entry's hash, "+
+			"an HMAC keyed with the console's audit key, covers its contents and the previous entry's hash, so an "+
+			"edited, removed or reordered entry is "+
+			"reported in `brokenAt`. Keep `lastHash`: a later check reporting a different hash for the same "+
+			"`count` means the trail was rewritten."),
+		protectedRouteOptions(),
+	)
+}
+
+func (f *FuegoAdapter) getAuditEvents(_ fuego.ContextNoBody) (AuditEventCountResponse, error) {
+	return AuditEventCountResponse{}, nil
+}
+
+func (f *FuegoAdapter) exportAuditEvents(_ fuego.ContextNoBody) (string, error) {
+	return "seq,timestamp,actor,tenantId,action,target,parameters,result,status,sourceIp,prevHash,hash\n", nil
+}
+
+func (f *FuegoAdapter) verifyAuditEvents(_ fuego.ContextNoBody) (dto.AuditVerification, error) {
+	return dto.AuditVerification{}, nil
+}
@@ -0,0 +1,41 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+func (f *FuegoAdapter) RegisterBackupRoutes() {
+	fuego.Post(f.server, "/api/v1/admin/backup", f.backup,
+		fuego.OptionTags("Backup"),
+		fuego.OptionSummary("Back Up Configuration"),
+		fuego.OptionDescription("Download every profile, domain (with its provisioning certificate), CIRA, wireless "+
+			"and 802.1x config of the tenant, and its devices with their credentials if `includeDevices`, as one "+
+			"archive encrypted with `passphrase`. Keep the passphrase: without it the archive cannot be restored."),
+		fuego.OptionAddResponse(http.StatusOK, "OK", fuego.Response{Type: "", ContentTypes: []string{"application/json"}}),
+		protectedRouteOptions(),
+	)
+
+	fuego.Post(f.server, "/api/v1/admin/restore", f.restore,
+		fuego.OptionTags("Backup"),
+		fuego.OptionSummary("Restore Configuration"),
+		fuego.OptionDescription("Restore an archive made by a backup, here or on another console, into the tenant. "+
+			"`archive` is the downloaded file as is.\n\n"+
+			"The archive is validated first, and everything is restored in one transaction or nothing is. An "+
+			"entity that already exists is skipped, overwritten or restored under a new name (`-restored`), as "+
+			"`onConflict` says, and references to renamed entities follow them. Devices are never renamed. "+
+			"With `dryRun` nothing is written and the response tells what would be done."),
+		protectedRouteOptions(),
+	)
+}
+
+func (f *FuegoAdapter) backup(_ fuego.ContextWithBody[dto.BackupRequest]) (string, error) {
+	return "", nil
+}
+
+func (f *FuegoAdapter) restore(_ fuego.ContextWithBody[dto.RestoreRequest]) (dto.RestoreResult, error) {
+	return dto.RestoreResult{}, nil
+}
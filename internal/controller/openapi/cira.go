@@ -16,13 +16,14 @@ func (f *FuegoAdapter) RegisterCIRAConfigRoutes() {
 		fuego.OptionQueryInt("$top", "Number of records to return"),
 		fuego.OptionQueryInt("$skip", "Number of records to skip"),
 		fuego.OptionQueryBool("$count", "Include total count"),
+		odataQueryOptions(),
 		protectedRouteOptions(),
 	)
 
 	fuego.Get(f.server, "/api/v1/admin/ciraconfigs/{ciraConfigName}", f.getCIRAConfigByName,
 		fuego.OptionTags("CIRA"),
 		fuego.OptionSummary("Get CIRA Configuration by Name"),
-		fuego.OptionDescription("Retrieve a specific CIRA configuration by profile name"),
+		fuego.OptionDescription("Retrieve a specific CIRA configuration by profile name; the ETag header carries its version, for If-Match"),
 		fuego.OptionPath("ciraConfigName", "Profile name"),
 		protectedRouteOptions(),
 	)
@@ -39,15 +40,17 @@ func (f *FuegoAdapter) RegisterCIRAConfigRoutes() {
 		fuego.OptionTags("CIRA"),
 		fuego.OptionSummary("Update CIRA Configuration"),
 		fuego.OptionDescription("Update an existing CIRA configuration"),
+		ifMatchOptions(),
 		protectedRouteOptions(),
 	)
 
 	fuego.Delete(f.server, "/api/v1/admin/ciraconfigs/{ciraConfigName}", f.deleteCIRAConfig,
 		fuego.OptionTags("CIRA"),
 		fuego.OptionSummary("Delete CIRA Configuration"),
-		fuego.OptionDescription("Delete a CIRA configuration by profile name"),
+		fuego.OptionDescription("Move a CIRA configuration to the trash, from which it can be restored until purged"),
 		fuego.OptionPath("ciraConfigName", "Profile name"),
 		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		ifMatchOptions(),
 		protectedRouteOptions(),
 	)
 }
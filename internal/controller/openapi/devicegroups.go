@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+type DeviceGroupCountResponse struct {
+	Count int               `json:"totalCount"`
+	Data  []dto.DeviceGroup `json:"data"`
+}
+
+type DeviceGroupMemberCountResponse struct {
+	Count int          `json:"totalCount"`
+	Data  []dto.Device `json:"data"`
+}
+
+func (f *FuegoAdapter) RegisterDeviceGroupRoutes() {
+	fuego.Get(f.server, "/api/v1/admin/groups", f.getDeviceGroups,
+		fuego.OptionTags("Device Groups"),
+		fuego.OptionSummary("List Device Groups"),
+		fuego.OptionDescription("Retrieve all device groups with optional pagination"),
+		fuego.OptionQueryInt("$top", "Number of records to return"),
+		fuego.OptionQueryInt("$skip", "Number of records to skip"),
+		fuego.OptionQueryBool("$count", "Include total count"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Get(f.server, "/api/v1/admin/groups/{id}", f.getDeviceGroupByID,
+		fuego.OptionTags("Device Groups"),
+		fuego.OptionSummary("Get Device Group by ID"),
+		fuego.OptionDescription("Retrieve a device group"),
+		fuego.OptionPath("id", "Device group ID"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Post(f.server, "/api/v1/admin/groups", f.createDeviceGroup,
+		fuego.OptionTags("Device Groups"),
+		fuego.OptionSummary("Create Device Group"),
+		fuego.OptionDescription("Create a group of devices. Devices are added by hand through the members "+
+			"route, or by `rules`: a device matching every rule is a member for as long as it matches. "+
+			"Rules are matched as devices are added, changed, connect or disconnect.\n\n"+
+			"A group with a `parentId` is a subgroup: its members are members of the parent too."),
+		fuego.OptionDefaultStatusCode(http.StatusCreated),
+		protectedRouteOptions(),
+	)
+
+	fuego.Put(f.server, "/api/v1/admin/groups/{id}", f.updateDeviceGroup,
+		fuego.OptionTags("Device Groups"),
+		fuego.OptionSummary("Update Device Group"),
+		fuego.OptionDescription("Replace a device group. Its rules are matched anew; members added by hand stay."),
+		fuego.OptionPath("id", "Device group ID"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Delete(f.server, "/api/v1/admin/groups/{id}", f.deleteDeviceGroup,
+		fuego.OptionTags("Device Groups"),
+		fuego.OptionSummary("Delete Device Group"),
+		fuego.OptionDescription("Remove a device group. A group with subgroups is refused with 400; move or "+
+			"delete them first."),
+		fuego.OptionPath("id", "Device group ID"),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		protectedRouteOptions(),
+	)
+
+	fuego.Get(f.server, "/api/v1/admin/groups/{id}/members", f.getDeviceGroupMembers,
+		fuego.OptionTags("Device Groups"),
+		fuego.OptionSummary("List Device Group Members"),
+		fuego.OptionDescription("List the devices of a group and of its subgroups, in GUID order. Devices in "+
+			"the trash, and those outside the caller's access policies, are left out."),
+		fuego.OptionPath("id", "Device group ID"),
+		fuego.OptionQueryInt("$top", "Number of records to return"),
+		fuego.OptionQueryInt("$skip", "Number of records to skip"),
+		fuego.OptionQueryBool("$count", "Include total count"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Post(f.server, "/api/v1/admin/groups/{id}/members", f.addDeviceGroupMembers,
+		fuego.OptionTags("Device Groups"),
+		fuego.OptionSummary("Add Device Group Members"),
+		fuego.OptionDescription("Add devices to a group by hand: those of `guids`, and the members of "+
+			"`groups`. Unknown GUIDs are skipped."),
+		fuego.OptionPath("id", "Device group ID"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Delete(f.server, "/api/v1/admin/groups/{id}/members/{guid}", f.removeDeviceGroupMember,
+		fuego.OptionTags("Device Groups"),
+		fuego.OptionSummary("Remove Device Group Member"),
+		fuego.OptionDescription("Remove a device added to a group by hand. A device the group's rules match "+
+			"stays a member."),
+		fuego.OptionPath("id", "Device group ID"),
+		fuego.OptionPath("guid", "Device GUID"),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		protectedRouteOptions(),
+	)
+
+	fuego.Post(f.server, "/api/v1/admin/groups/{id}/refresh", f.refreshDeviceGroup,
+		fuego.OptionTags("Device Groups"),
+		fuego.OptionSummary("Refresh Device Group"),
+		fuego.OptionDescription("Match the group's rules against every device anew, to catch up with changes "+
+			"made outside the console, such as a database restore."),
+		fuego.OptionPath("id", "Device group ID"),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		protectedRouteOptions(),
+	)
+}
+
+func (f *FuegoAdapter) getDeviceGroups(_ fuego.ContextNoBody) (DeviceGroupCountResponse, error) {
+	return DeviceGroupCountResponse{Count: 0, Data: []dto.DeviceGroup{}}, nil
+}
+
+func (f *FuegoAdapter) getDeviceGroupByID(_ fuego.ContextNoBody) (dto.DeviceGroup, error) {
+	return dto.DeviceGroup{}, nil
+}
+
+func (f *FuegoAdapter) createDeviceGroup(c fuego.ContextWithBody[dto.DeviceGroup]) (dto.DeviceGroup, error) {
+	body, err := c.Body()
+	if err != nil {
+		return dto.DeviceGroup{}, err
+	}
+
+	return body, nil
+}
+
+func (f *FuegoAdapter) updateDeviceGroup(c fuego.ContextWithBody[dto.DeviceGroup]) (dto.DeviceGroup, error) {
+	body, err := c.Body()
+	if err != nil {
+		return dto.DeviceGroup{}, err
+	}
+
+	return body, nil
+}
+
+func (f *FuegoAdapter) deleteDeviceGroup(_ fuego.ContextNoBody) (NoContentResponse, error) {
+	return NoContentResponse{}, nil
+}
+
+func (f *FuegoAdapter) getDeviceGroupMembers(_ fuego.ContextNoBody) (DeviceGroupMemberCountResponse, error) {
+	return DeviceGroupMemberCountResponse{Count: 0, Data: []dto.Device{}}, nil
+}
+
+func (f *FuegoAdapter) addDeviceGroupMembers(_ fuego.ContextWithBody[dto.DeviceSelection]) (dto.DeviceGroupMembersAdded, error) {
+	return dto.DeviceGroupMembersAdded{}, nil
+}
+
+func (f *FuegoAdapter) removeDeviceGroupMember(_ fuego.ContextNoBody) (NoContentResponse, error) {
+	return NoContentResponse{}, nil
+}
+
+func (f *FuegoAdapter) refreshDeviceGroup(_ fuego.ContextNoBody) (NoContentResponse, error) {
+	return NoContentResponse{}, nil
+}
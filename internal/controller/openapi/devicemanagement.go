@@ -19,6 +19,7 @@ func (f *FuegoAdapter) RegisterDeviceManagementRoutes() {
 	f.registerPowerRoutes()
 	f.registerLogsAndAlarmRoutes()
 	f.registerVersionAndHardwareRoutes()
+	f.registerSOLCaptureRoutes()
 }
 
 func (f *FuegoAdapter) registerKVMAndCertificateRoutes() {
@@ -41,6 +42,17 @@ func (f *FuegoAdapter) registerKVMAndCertificateRoutes() {
 		protectedRouteOptions(),
 	)
 
+	fuego.Get(
+		f.server, "/api/v1/amt/kvm/screenshot/{guid}", f.getKVMScreenshot,
+		fuego.OptionTags("Device Management"),
+		fuego.OptionSummary("Get KVM screenshot"),
+		fuego.OptionDescription("Capture the device screen as a PNG over a short KVM session. Returns 428 when user consent is required; the consent code is then shown on the device and the request can be retried once it has been submitted"),
+		fuego.OptionPath("guid", "Device GUID"),
+		fuego.OptionAddResponse(http.StatusOK, "OK", fuego.Response{Type: "", ContentTypes: []string{"image/png"}}),
+		errorResponseOption(http.StatusPreconditionRequired, "User Consent Required"),
+		protectedRouteOptions(),
+	)
+
 	// Certificates
 	fuego.Get(
 		f.server, "/api/v1/amt/certificates/{guid}", f.getCertificates,
@@ -485,6 +497,10 @@ func (f *FuegoAdapter) getKVMDisplays(_ fuego.ContextNoBody) (dto.KVMScreenSetti
 	}, nil
 }
 
+func (f *FuegoAdapter) getKVMScreenshot(_ fuego.ContextNoBody) (string, error) {
+	return "", nil
+}
+
 func (f *FuegoAdapter) setKVMDisplays(c fuego.ContextWithBody[dto.KVMScreenSettingsRequest]) (dto.KVMScreenSettings, error) {
 	req, err := c.Body()
 	if err != nil {
@@ -38,3 +38,46 @@ func TestRegisterPowerRoutes_IncludesBootEndpoints(t *testing.T) {
 	require.Contains(t, remoteErasePath, "get", "remote erase GET operation should be registered")
 	require.Contains(t, remoteErasePath, "post", "remote erase POST operation should be registered")
 }
+
+func TestRegisterRoutes_DocumentPermissions(t *testing.T) {
+	t.Parallel()
+
+	f := newTestAdapter()
+	f.RegisterRoutes()
+
+	specBytes, err := f.GetOpenAPISpec()
+	require.NoError(t, err)
+
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(specBytes, &spec))
+
+	paths, ok := spec["paths"].(map[string]interface{})
+	require.True(t, ok)
+
+	public := map[string]bool{
+		"/api/v1/authorize":          true,
+		"/api/v1/authorize/logout":   true,
+		"/api/v1/authorize/password": true,
+		"/api/v1/authorize/refresh":  true,
+	}
+
+	for path, item := range paths {
+		if public[path] {
+			continue
+		}
+
+		operations, ok := item.(map[string]interface{})
+		require.True(t, ok)
+
+		for method, op := range operations {
+			operation, ok := op.(map[string]interface{})
+			require.True(t, ok)
+
+			require.Contains(t, operation, permissionExtension, method+" "+path)
+
+			responses, ok := operation["responses"].(map[string]interface{})
+			require.True(t, ok)
+			require.Contains(t, responses, "403", method+" "+path)
+		}
+	}
+}
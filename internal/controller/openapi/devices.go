@@ -27,16 +27,45 @@ func (f *FuegoAdapter) registerDeviceAuthRoutes() {
 			"set as an HttpOnly session cookie so browsers need not store it. The cookie is "+
 			"named `"+config.DefaultSessionCookieName+"` unless the deployment overrides "+
 			"`auth.cookieName`, and is not issued at all when cookie auth is disabled or "+
-			"OIDC is configured."),
+			"OIDC is configured.\n\n"+
+			"Unless `auth.refreshTokenExpiration` is zero, the body also carries a `refreshToken` "+
+			"for `/api/v1/authorize/refresh`.\n\n"+
+			"With `auth.ldap` configured, usernames with no local account are checked against "+
+			"the LDAP directory, and the user's groups decide their role."),
 		fuego.OptionAddResponse(http.StatusUnauthorized, "Unauthorized: invalid credentials", fuego.Response{Type: ErrorResponse{}}),
+		fuego.OptionAddResponse(http.StatusForbidden, "Forbidden: the local user must change their password first, or the directory user's groups grant no role", fuego.Response{Type: ErrorResponse{}}),
+		fuego.OptionAddResponse(http.StatusTooManyRequests, "Too Many Requests: too many failed logins for the username or client IP; retry after the Retry-After header's seconds", fuego.Response{Type: ErrorResponse{}}),
+	)
+
+	fuego.Post(f.server, "/api/v1/authorize/password", f.changePassword,
+		fuego.OptionTags("Devices"),
+		fuego.OptionSummary("Change Password"),
+		fuego.OptionDescription("Set a new password for a local user account, given the current one.\n\n"+
+			"Public, as a user whose password must be changed cannot log in. New users and "+
+			"users whose password an admin reset must call this before `/api/v1/authorize` succeeds."),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		fuego.OptionAddResponse(http.StatusBadRequest, "Bad Request: the new password does not meet the password policy", fuego.Response{Type: ErrorResponse{}}),
+		fuego.OptionAddResponse(http.StatusUnauthorized, "Unauthorized: invalid credentials", fuego.Response{Type: ErrorResponse{}}),
+		fuego.OptionAddResponse(http.StatusTooManyRequests, "Too Many Requests: too many failed logins for the username or client IP", fuego.Response{Type: ErrorResponse{}}),
+	)
+
+	fuego.Post(f.server, "/api/v1/authorize/refresh", f.refresh,
+		fuego.OptionTags("Devices"),
+		fuego.OptionSummary("Refresh Token"),
+		fuego.OptionDescription("Exchange a refresh token for a new access token and a new refresh token.\n\n"+
+			"Each refresh token can be used once. Presenting one that was already used ends "+
+			"the whole session, as the token must have been copied."),
+		fuego.OptionAddResponse(http.StatusBadRequest, "Bad Request: no refresh token", fuego.Response{Type: ErrorResponse{}}),
+		fuego.OptionAddResponse(http.StatusUnauthorized, "Unauthorized: unknown, expired or reused refresh token", fuego.Response{Type: ErrorResponse{}}),
 	)
 
 	fuego.Post(f.server, "/api/v1/authorize/logout", f.logout,
 		fuego.OptionTags("Devices"),
 		fuego.OptionSummary("Logout"),
-		fuego.OptionDescription("Expire the session cookie.\n\n"+
-			"Public, so an already-expired session can still clear it. Not revocation: "+
-			"the JWT is stateless and stays valid until it expires."),
+		fuego.OptionDescription("End the session and expire the session cookie.\n\n"+
+			"The presented token, every other token of its session and the session's "+
+			"refresh token are revoked. Public, so an already-expired session can still "+
+			"clear the cookie."),
 	)
 
 	fuego.Get(f.server, "/api/v1/authorize/redirection/{id}", f.loginRedirection,
@@ -56,6 +85,7 @@ func (f *FuegoAdapter) registerDeviceQueryRoutes() {
 		fuego.OptionQueryInt("$top", "Number of records to return"),
 		fuego.OptionQueryInt("$skip", "Number of records to skip"),
 		fuego.OptionQueryBool("$count", "Include total count"),
+		odataQueryOptions(),
 		fuego.OptionQuery("tags", "Comma-separated list of tags to filter devices"),
 		fuego.OptionQuery("method", "Method to filter tags (any/all)"),
 		fuego.OptionQuery("hostname", "Filter devices by host name"),
@@ -81,7 +111,7 @@ func (f *FuegoAdapter) registerDeviceQueryRoutes() {
 	fuego.Get(f.server, "/api/v1/devices/{guid}", f.getDeviceByID,
 		fuego.OptionTags("Devices"),
 		fuego.OptionSummary("Get Device by ID"),
-		fuego.OptionDescription("Retrieve a specific device by ID"),
+		fuego.OptionDescription("Retrieve a specific device by ID; the ETag header carries its version, for If-Match"),
 		fuego.OptionPath("guid", "Device GUID"),
 		protectedRouteOptions(),
 	)
@@ -133,15 +163,17 @@ func (f *FuegoAdapter) registerDeviceMutationRoutes() {
 		fuego.OptionTags("Devices"),
 		fuego.OptionSummary("Update Device"),
 		fuego.OptionDescription("Update an existing device"),
+		ifMatchOptions(),
 		protectedRouteOptions(),
 	)
 
 	fuego.Delete(f.server, "/api/v1/devices/{guid}", f.deleteDevice,
 		fuego.OptionTags("Devices"),
 		fuego.OptionSummary("Delete Device"),
-		fuego.OptionDescription("Delete a device by ID"),
+		fuego.OptionDescription("Move a device to the trash, from which it can be restored until purged"),
 		fuego.OptionPath("guid", "Device GUID"),
 		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		ifMatchOptions(),
 		protectedRouteOptions(),
 	)
 }
@@ -150,20 +182,44 @@ type AuthorizeRedirectionResponse struct {
 	Token string `json:"token"`
 }
 
-func (f *FuegoAdapter) login(c fuego.ContextWithBody[dto.Credentials]) (AuthorizeRedirectionResponse, error) {
+// AuthorizeResponse is an access token and, when enabled, its refresh token.
+type AuthorizeResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+func (f *FuegoAdapter) login(c fuego.ContextWithBody[dto.Credentials]) (AuthorizeResponse, error) {
 	_, err := c.Body()
 	if err != nil {
-		return AuthorizeRedirectionResponse{}, err
+		return AuthorizeResponse{}, err
 	}
 
-	return AuthorizeRedirectionResponse{Token: "example-token"}, nil
+	return AuthorizeResponse{Token: "example-token", RefreshToken: "example-refresh-token"}, nil
+}
+
+func (f *FuegoAdapter) refresh(c fuego.ContextWithBody[dto.TokenRefresh]) (AuthorizeResponse, error) {
+	_, err := c.Body()
+	if err != nil {
+		return AuthorizeResponse{}, err
+	}
+
+	return AuthorizeResponse{Token: "example-token", RefreshToken: "example-refresh-token"}, nil
+}
+
+func (f *FuegoAdapter) changePassword(c fuego.ContextWithBody[dto.PasswordChange]) (NoContentResponse, error) {
+	_, err := c.Body()
+	if err != nil {
+		return NoContentResponse{}, err
+	}
+
+	return NoContentResponse{}, nil
 }
 
 func (f *FuegoAdapter) loginRedirection(_ fuego.ContextNoBody) (AuthorizeRedirectionResponse, error) {
 	return AuthorizeRedirectionResponse{Token: "example-token"}, nil
 }
 
-// LogoutResponse acknowledges that the session cookie was expired.
+// LogoutResponse acknowledges that the session was ended.
 type LogoutResponse struct {
 	Message string `json:"message" example:"logged out"`
 }
@@ -21,13 +21,14 @@ func (f *FuegoAdapter) RegisterDomainRoutes() {
 		fuego.OptionQueryInt("$top", "Number of records to return"),
 		fuego.OptionQueryInt("$skip", "Number of records to skip"),
 		fuego.OptionQueryBool("$count", "Include total count"),
+		odataQueryOptions(),
 		protectedRouteOptions(),
 	)
 
 	fuego.Get(f.server, "/api/v1/admin/domains/{name}", f.getDomainByName,
 		fuego.OptionTags("Domains"),
 		fuego.OptionSummary("Get Domain by Name"),
-		fuego.OptionDescription("Retrieve a specific domain by name"),
+		fuego.OptionDescription("Retrieve a specific domain by name; the ETag header carries its version, for If-Match"),
 		fuego.OptionPath("name", "Domain profile name"),
 		protectedRouteOptions(),
 	)
@@ -44,15 +45,17 @@ func (f *FuegoAdapter) RegisterDomainRoutes() {
 		fuego.OptionTags("Domains"),
 		fuego.OptionSummary("Update Domain"),
 		fuego.OptionDescription("Update an existing domain"),
+		ifMatchOptions(),
 		protectedRouteOptions(),
 	)
 
 	fuego.Delete(f.server, "/api/v1/admin/domains/{name}", f.deleteDomain,
 		fuego.OptionTags("Domains"),
 		fuego.OptionSummary("Delete Domain"),
-		fuego.OptionDescription("Delete a domain by name"),
+		fuego.OptionDescription("Move a domain to the trash, from which it can be restored until purged"),
 		fuego.OptionPath("name", "Domain profile name"),
 		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		ifMatchOptions(),
 		protectedRouteOptions(),
 	)
 }
@@ -16,6 +16,7 @@ func (f *FuegoAdapter) RegisterIEEE8021xConfigRoutes() {
 		fuego.OptionQueryInt("$top", "Number of records to return"),
 		fuego.OptionQueryInt("$skip", "Number of records to skip"),
 		fuego.OptionQueryBool("$count", "Include total count"),
+		odataQueryOptions(),
 		protectedRouteOptions(),
 	)
 
@@ -30,7 +31,7 @@ func (f *FuegoAdapter) RegisterIEEE8021xConfigRoutes() {
 	fuego.Get(f.server, "/api/v1/admin/ieee8021xconfigs/{profileName}", f.getIEEE8021xConfigByName,
 		fuego.OptionTags("IEEE 802.1x"),
 		fuego.OptionSummary("Get IEEE 802.1x Configuration by Name"),
-		fuego.OptionDescription("Retrieve a specific IEEE 802.1x configuration by name"),
+		fuego.OptionDescription("Retrieve a specific IEEE 802.1x configuration by name; the ETag header carries its version, for If-Match"),
 		fuego.OptionPath("profileName", "Configuration name"),
 		protectedRouteOptions(),
 	)
@@ -39,15 +40,17 @@ func (f *FuegoAdapter) RegisterIEEE8021xConfigRoutes() {
 		fuego.OptionTags("IEEE 802.1x"),
 		fuego.OptionSummary("Update IEEE 802.1x Configuration"),
 		fuego.OptionDescription("Update an existing IEEE 802.1x configuration"),
+		ifMatchOptions(),
 		protectedRouteOptions(),
 	)
 
 	fuego.Delete(f.server, "/api/v1/admin/ieee8021xconfigs/{profileName}", f.deleteIEEE8021xConfig,
 		fuego.OptionTags("IEEE 802.1x"),
 		fuego.OptionSummary("Delete IEEE 802.1x Configuration"),
-		fuego.OptionDescription("Delete an IEEE 802.1x configuration by name"),
+		fuego.OptionDescription("Move an IEEE 802.1x configuration to the trash, from which it can be restored until purged"),
 		fuego.OptionPath("profileName", "Configuration name"),
 		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		ifMatchOptions(),
 		protectedRouteOptions(),
 	)
 }
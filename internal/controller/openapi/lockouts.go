@@ -0,0 +1,38 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+func (f *FuegoAdapter) RegisterLockoutRoutes() {
+	fuego.Get(f.server, "/api/v1/admin/lockouts", f.getLockouts,
+		fuego.OptionTags("Login Lockouts"),
+		fuego.OptionSummary("List Login Lockouts"),
+		fuego.OptionDescription("List the usernames and client IPs with recent failed logins. `blockedUntil` is set "+
+			"while their next attempt is refused, and `locked` when that is a lockout rather than the delay "+
+			"after a single failure."),
+		protectedRouteOptions(),
+	)
+
+	fuego.Delete(f.server, "/api/v1/admin/lockouts/{kind}/{key}", f.clearLockout,
+		fuego.OptionTags("Login Lockouts"),
+		fuego.OptionSummary("Clear Login Lockout"),
+		fuego.OptionDescription("Forget the failed logins of a username or client IP, lifting any lockout"),
+		fuego.OptionPath("kind", "`username` or `ip`"),
+		fuego.OptionPath("key", "Username or client IP"),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		protectedRouteOptions(),
+	)
+}
+
+func (f *FuegoAdapter) getLockouts(_ fuego.ContextNoBody) ([]dto.Lockout, error) {
+	return []dto.Lockout{}, nil
+}
+
+func (f *FuegoAdapter) clearLockout(_ fuego.ContextNoBody) (NoContentResponse, error) {
+	return NoContentResponse{}, nil
+}
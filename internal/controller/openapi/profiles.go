@@ -16,13 +16,14 @@ func (f *FuegoAdapter) RegisterProfileRoutes() {
 		fuego.OptionQueryInt("$top", "Number of records to return"),
 		fuego.OptionQueryInt("$skip", "Number of records to skip"),
 		fuego.OptionQueryBool("$count", "Include total count"),
+		odataQueryOptions(),
 		protectedRouteOptions(),
 	)
 
 	fuego.Get(f.server, "/api/v1/admin/profiles/{name}", f.getProfileByName,
 		fuego.OptionTags("Profiles"),
 		fuego.OptionSummary("Get Profile by Name"),
-		fuego.OptionDescription("Retrieve a specific profile by name"),
+		fuego.OptionDescription("Retrieve a specific profile by name; the ETag header carries its version, for If-Match"),
 		fuego.OptionPath("name", "Profile name"),
 		protectedRouteOptions(),
 	)
@@ -39,15 +40,17 @@ func (f *FuegoAdapter) RegisterProfileRoutes() {
 		fuego.OptionTags("Profiles"),
 		fuego.OptionSummary("Update Profile"),
 		fuego.OptionDescription("Update an existing profile"),
+		ifMatchOptions(),
 		protectedRouteOptions(),
 	)
 
 	fuego.Delete(f.server, "/api/v1/admin/profiles/{name}", f.deleteProfile,
 		fuego.OptionTags("Profiles"),
 		fuego.OptionSummary("Delete Profile"),
-		fuego.OptionDescription("Delete a profile by name"),
+		fuego.OptionDescription("Move a profile to the trash, from which it can be restored until purged"),
 		fuego.OptionPath("name", "Profile name"),
 		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		ifMatchOptions(),
 		protectedRouteOptions(),
 	)
 
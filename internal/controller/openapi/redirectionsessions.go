@@ -0,0 +1,35 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+func (f *FuegoAdapter) RegisterRedirectionSessionRoutes() {
+	fuego.Get(f.server, "/api/v1/admin/redirection/sessions", f.getRedirectionSessions,
+		fuego.OptionTags("Redirection Sessions"),
+		fuego.OptionSummary("List Redirection Sessions"),
+		fuego.OptionDescription("List active KVM, SOL and IDER sessions, including headless sessions such as the SSH gateway"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Delete(f.server, "/api/v1/admin/redirection/sessions/{id}", f.terminateRedirectionSession,
+		fuego.OptionTags("Redirection Sessions"),
+		fuego.OptionSummary("Terminate Redirection Session"),
+		fuego.OptionDescription("End an active redirection session and free the device's redirection slot"),
+		fuego.OptionPath("id", "Session ID"),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		protectedRouteOptions(),
+	)
+}
+
+func (f *FuegoAdapter) getRedirectionSessions(_ fuego.ContextNoBody) ([]dto.RedirectionSession, error) {
+	return []dto.RedirectionSession{}, nil
+}
+
+func (f *FuegoAdapter) terminateRedirectionSession(_ fuego.ContextNoBody) (NoContentResponse, error) {
+	return NoContentResponse{}, nil
+}
@@ -2,11 +2,13 @@ package openapi
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-fuego/fuego"
 
 	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/rbac"
 )
 
 // specCookieAuthEnabled mirrors the auth middleware. cmd/openapi-gen runs
@@ -26,9 +28,11 @@ func apiRouteOptions() fuego.RouteOption {
 }
 
 func protectedRouteOptions() fuego.RouteOption {
-	// Alternatives, not both: a bearer header or the session cookie.
+	// Alternatives, not combined: a bearer header, an API key header or the
+	// session cookie.
 	security := []openapi3.SecurityRequirement{
 		{bearerAuthScheme: []string{}},
+		{apiKeyAuthScheme: []string{}},
 	}
 
 	if specCookieAuthEnabled() {
@@ -38,6 +42,8 @@ func protectedRouteOptions() fuego.RouteOption {
 	return routeOptionGroup(
 		apiRouteOptions(),
 		fuego.OptionSecurity(security...),
+		permissionOption(),
+		fuego.OptionHeader("X-Tenant-ID", "Tenant to act in instead of the caller's own; requires `"+string(rbac.TenantsAll)+"`"),
 		errorResponseOption(http.StatusNotFound, "Not Found"),
 		errorResponseOption(http.StatusRequestTimeout, "Request Timeout"),
 		errorResponseOption(http.StatusConflict, "Conflict"),
@@ -46,6 +52,53 @@ func protectedRouteOptions() fuego.RouteOption {
 	)
 }
 
+// permissionOption documents the permission the RBAC middleware enforces for
+// the route, read from the same table.
+func permissionOption() fuego.RouteOption {
+	return func(route *fuego.BaseRoute) {
+		permission, ok := rbac.Required(route.Method, route.Path)
+		if !ok {
+			return
+		}
+
+		roles := rbac.RolesWith(permission)
+		names := make([]string, len(roles))
+
+		for i, role := range roles {
+			names[i] = string(role)
+		}
+
+		errorResponseOption(http.StatusForbidden,
+			"Forbidden _(requires `"+string(permission)+"`, granted to "+strings.Join(names, ", ")+")_")(route)
+
+		if route.Operation.Extensions == nil {
+			route.Operation.Extensions = map[string]interface{}{}
+		}
+
+		route.Operation.Extensions[permissionExtension] = string(permission)
+	}
+}
+
+// odataQueryOptions documents the $filter, $orderby and $select options of
+// the list routes that accept them.
+func odataQueryOptions() fuego.RouteOption {
+	return routeOptionGroup(
+		fuego.OptionQuery("$filter", "OData filter: eq, ne, gt, lt, and, or, contains() and startswith(), e.g. `startswith(hostname,'lab-') and connectionStatus eq true`"),
+		fuego.OptionQuery("$orderby", "Comma-separated fields to order by, each optionally followed by `asc` or `desc`"),
+		fuego.OptionQuery("$select", "Comma-separated fields to return"),
+		errorResponseOption(http.StatusBadRequest, "Bad Request _(invalid query option)_"),
+	)
+}
+
+// ifMatchOptions documents the If-Match condition of a change to a
+// versioned entity.
+func ifMatchOptions() fuego.RouteOption {
+	return routeOptionGroup(
+		fuego.OptionHeader("If-Match", "ETag of the version to change, from the GET or change that returned it; omit to change any version"),
+		errorResponseOption(http.StatusPreconditionFailed, "Precondition Failed _(the entity changed since the If-Match ETag was read)_"),
+	)
+}
+
 func errorResponseOption(statusCode int, description string) fuego.RouteOption {
 	return fuego.OptionAddResponse(statusCode, description, fuego.Response{Type: fuego.HTTPError{}})
 }
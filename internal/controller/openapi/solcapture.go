@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+func (f *FuegoAdapter) registerSOLCaptureRoutes() {
+	fuego.Post(
+		f.server, "/api/v1/amt/sol/capture/{guid}", f.startSOLCapture,
+		fuego.OptionTags("Device Management"),
+		fuego.OptionSummary("Start SOL capture"),
+		fuego.OptionDescription("Record the device's serial console in the background for a time window, optionally sending a power action first so the boot is captured. The capture stops early when the pattern appears"),
+		fuego.OptionPath("guid", "Device GUID"),
+		fuego.OptionDefaultStatusCode(http.StatusAccepted),
+		protectedRouteOptions(),
+	)
+
+	fuego.Get(
+		f.server, "/api/v1/amt/sol/capture/{guid}", f.getSOLCaptures,
+		fuego.OptionTags("Device Management"),
+		fuego.OptionSummary("List SOL captures"),
+		fuego.OptionDescription("List the most recent SOL captures for a device, newest first. Captures are kept in memory"),
+		fuego.OptionPath("guid", "Device GUID"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Get(
+		f.server, "/api/v1/amt/sol/capture/{guid}/{id}", f.getSOLCapture,
+		fuego.OptionTags("Device Management"),
+		fuego.OptionSummary("Get SOL capture"),
+		fuego.OptionDescription("Retrieve a SOL capture with the text recorded so far"),
+		fuego.OptionPath("guid", "Device GUID"),
+		fuego.OptionPath("id", "Capture ID"),
+		protectedRouteOptions(),
+	)
+}
+
+func (f *FuegoAdapter) startSOLCapture(c fuego.ContextWithBody[dto.SOLCaptureRequest]) (dto.SOLCapture, error) {
+	req, err := c.Body()
+	if err != nil {
+		return dto.SOLCapture{}, err
+	}
+
+	return dto.SOLCapture{Status: "running", Duration: req.Duration, PowerAction: req.PowerAction, Pattern: req.Pattern}, nil
+}
+
+func (f *FuegoAdapter) getSOLCaptures(_ fuego.ContextNoBody) ([]dto.SOLCapture, error) {
+	return []dto.SOLCapture{}, nil
+}
+
+func (f *FuegoAdapter) getSOLCapture(_ fuego.ContextNoBody) (dto.SOLCapture, error) {
+	return dto.SOLCapture{}, nil
+}
@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+func (f *FuegoAdapter) RegisterTagRoutes() {
+	fuego.Get(f.server, "/api/v1/admin/tags", f.getTagCounts,
+		fuego.OptionTags("Tags"),
+		fuego.OptionSummary("List Tags"),
+		fuego.OptionDescription("Retrieve the tags in use, by name, with how many devices carry each. "+
+			"Devices in the trash are not counted."),
+		protectedRouteOptions(),
+	)
+
+	fuego.Put(f.server, "/api/v1/admin/tags/{tag}", f.renameTag,
+		fuego.OptionTags("Tags"),
+		fuego.OptionSummary("Rename Tag"),
+		fuego.OptionDescription("Rename a tag on every device carrying it, those in the trash included. "+
+			"The new name must not be in use already; to fold a tag into another, merge them. A tag that an "+
+			"access policy or a group rule names is refused with 400; change those first."),
+		fuego.OptionPath("tag", "Tag"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Delete(f.server, "/api/v1/admin/tags/{tag}", f.deleteTag,
+		fuego.OptionTags("Tags"),
+		fuego.OptionSummary("Delete Tag"),
+		fuego.OptionDescription("Remove a tag from every device carrying it. A tag that an access policy or a "+
+			"group rule names is refused with 400; change those first."),
+		fuego.OptionPath("tag", "Tag"),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		protectedRouteOptions(),
+	)
+
+	fuego.Post(f.server, "/api/v1/admin/tags/merge", f.mergeTags,
+		fuego.OptionTags("Tags"),
+		fuego.OptionSummary("Merge Tags"),
+		fuego.OptionDescription("Replace each of `tags` with `into` on every device carrying it. Tags that an "+
+			"access policy or a group rule names are refused with 400; change those first."),
+		protectedRouteOptions(),
+	)
+
+	fuego.Post(f.server, "/api/v1/admin/tags/devices", f.updateDeviceTags,
+		fuego.OptionTags("Tags"),
+		fuego.OptionSummary("Update Device Tags"),
+		fuego.OptionDescription("Add the tags of `add` to the selected devices, and remove those of `remove`. "+
+			"Devices are selected by GUID and by group, subgroups included; unknown GUIDs, and devices outside the "+
+			"caller's device scope, are skipped. Removing a tag that an access policy or a group rule names is "+
+			"refused with 400, as is a change that would move a device out of the caller's scope. Requires "+
+			"devices:write."),
+		protectedRouteOptions(),
+	)
+}
+
+func (f *FuegoAdapter) getTagCounts(_ fuego.ContextNoBody) ([]dto.Tag, error) {
+	return []dto.Tag{}, nil
+}
+
+func (f *FuegoAdapter) renameTag(_ fuego.ContextWithBody[dto.TagRename]) (dto.TagsUpdated, error) {
+	return dto.TagsUpdated{}, nil
+}
+
+func (f *FuegoAdapter) deleteTag(_ fuego.ContextNoBody) (NoContentResponse, error) {
+	return NoContentResponse{}, nil
+}
+
+func (f *FuegoAdapter) mergeTags(_ fuego.ContextWithBody[dto.TagMerge]) (dto.TagsUpdated, error) {
+	return dto.TagsUpdated{}, nil
+}
+
+func (f *FuegoAdapter) updateDeviceTags(_ fuego.ContextWithBody[dto.TagUpdate]) (dto.TagsUpdated, error) {
+	return dto.TagsUpdated{}, nil
+}
@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+const trashKindDescription = "Kind of entity: `devices`, `profiles`, `ciraconfigs`, `wirelessconfigs`, `ieee8021xconfigs` or `domains`"
+
+func (f *FuegoAdapter) RegisterTrashRoutes() {
+	fuego.Get(f.server, "/api/v1/admin/trash/{kind}", f.getTrash,
+		fuego.OptionTags("Trash"),
+		fuego.OptionSummary("List Trash"),
+		fuego.OptionDescription("List the deleted entities of a kind, latest deleted first. Deleting a device or "+
+			"configuration moves it to the trash, with its stored credentials, where it stays until restored, "+
+			"purged, or, `purgeAt`, purged automatically once the retention period has passed."),
+		fuego.OptionPath("kind", trashKindDescription),
+		fuego.OptionQueryInt("$top", "Number of records to return"),
+		fuego.OptionQueryInt("$skip", "Number of records to skip"),
+		fuego.OptionQueryBool("$count", "Include total count"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Post(f.server, "/api/v1/admin/trash/{kind}/{id}/restore", f.restoreTrash,
+		fuego.OptionTags("Trash"),
+		fuego.OptionSummary("Restore from Trash"),
+		fuego.OptionDescription("Take a deleted entity out of the trash. A profile or wireless config that uses "+
+			"another entity still in the trash is refused with 400; restore that first."),
+		fuego.OptionPath("kind", trashKindDescription),
+		fuego.OptionPath("id", "Device GUID or configuration name"),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		protectedRouteOptions(),
+	)
+
+	fuego.Delete(f.server, "/api/v1/admin/trash/{kind}/{id}", f.purgeTrash,
+		fuego.OptionTags("Trash"),
+		fuego.OptionSummary("Purge from Trash"),
+		fuego.OptionDescription("Delete an entity in the trash for good, with its stored credentials. One used by "+
+			"another entity in the trash is refused with 400; purge that first."),
+		fuego.OptionPath("kind", trashKindDescription),
+		fuego.OptionPath("id", "Device GUID or configuration name"),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		protectedRouteOptions(),
+	)
+}
+
+func (f *FuegoAdapter) getTrash(_ fuego.ContextNoBody) (dto.TrashCountResponse, error) {
+	return dto.TrashCountResponse{Count: 0, Data: []dto.TrashItem{}}, nil
+}
+
+func (f *FuegoAdapter) restoreTrash(_ fuego.ContextNoBody) (NoContentResponse, error) {
+	return NoContentResponse{}, nil
+}
+
+func (f *FuegoAdapter) purgeTrash(_ fuego.ContextNoBody) (NoContentResponse, error) {
+	return NoContentResponse{}, nil
+}
@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+type UserCountResponse struct {
+	Count int        `json:"totalCount"`
+	Data  []dto.User `json:"data"`
+}
+
+func (f *FuegoAdapter) RegisterUserRoutes() {
+	fuego.Get(f.server, "/api/v1/admin/users", f.getUsers,
+		fuego.OptionTags("Users"),
+		fuego.OptionSummary("List Users"),
+		fuego.OptionDescription("Retrieve all local user accounts with optional pagination. Password hashes are never returned."),
+		fuego.OptionQueryInt("$top", "Number of records to return"),
+		fuego.OptionQueryInt("$skip", "Number of records to skip"),
+		fuego.OptionQueryBool("$count", "Include total count"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Get(f.server, "/api/v1/admin/users/{username}", f.getUserByUsername,
+		fuego.OptionTags("Users"),
+		fuego.OptionSummary("Get User by Username"),
+		fuego.OptionDescription("Retrieve a local user account. Usernames match case-insensitively."),
+		fuego.OptionPath("username", "Username"),
+		protectedRouteOptions(),
+	)
+
+	fuego.Post(f.server, "/api/v1/admin/users", f.createUser,
+		fuego.OptionTags("Users"),
+		fuego.OptionSummary("Create User"),
+		fuego.OptionDescription("Create a local user account with an initial password, which must meet the "+
+			"password policy. The user must change it at first login via `/api/v1/authorize/password`."),
+		fuego.OptionDefaultStatusCode(http.StatusCreated),
+		protectedRouteOptions(),
+	)
+
+	fuego.Patch(f.server, "/api/v1/admin/users", f.updateUser,
+		fuego.OptionTags("Users"),
+		fuego.OptionSummary("Update User"),
+		fuego.OptionDescription("Change a user's role. Including a password resets it, and the user must "+
+			"change it at their next login."),
+		protectedRouteOptions(),
+	)
+
+	fuego.Delete(f.server, "/api/v1/admin/users/{username}", f.deleteUser,
+		fuego.OptionTags("Users"),
+		fuego.OptionSummary("Delete User"),
+		fuego.OptionDescription("Delete a local user account and end its sessions"),
+		fuego.OptionPath("username", "Username"),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		protectedRouteOptions(),
+	)
+
+	fuego.Delete(f.server, "/api/v1/admin/users/{username}/sessions", f.endUserSessions,
+		fuego.OptionTags("Users"),
+		fuego.OptionSummary("End User Sessions"),
+		fuego.OptionDescription("Revoke every access and refresh token issued to a user, signing them out "+
+			"everywhere. The account itself is kept."),
+		fuego.OptionPath("username", "Username"),
+		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		protectedRouteOptions(),
+	)
+}
+
+func (f *FuegoAdapter) getUsers(_ fuego.ContextNoBody) (UserCountResponse, error) {
+	return UserCountResponse{Count: 0, Data: []dto.User{}}, nil
+}
+
+func (f *FuegoAdapter) getUserByUsername(_ fuego.ContextNoBody) (dto.User, error) {
+	return dto.User{}, nil
+}
+
+func (f *FuegoAdapter) createUser(c fuego.ContextWithBody[dto.User]) (dto.User, error) {
+	body, err := c.Body()
+	if err != nil {
+		return dto.User{}, err
+	}
+
+	return body, nil
+}
+
+func (f *FuegoAdapter) updateUser(c fuego.ContextWithBody[dto.User]) (dto.User, error) {
+	body, err := c.Body()
+	if err != nil {
+		return dto.User{}, err
+	}
+
+	return body, nil
+}
+
+func (f *FuegoAdapter) deleteUser(_ fuego.ContextNoBody) (NoContentResponse, error) {
+	return NoContentResponse{}, nil
+}
+
+func (f *FuegoAdapter) endUserSessions(_ fuego.ContextNoBody) (NoContentResponse, error) {
+	return NoContentResponse{}, nil
+}
@@ -16,13 +16,14 @@ func (f *FuegoAdapter) RegisterWirelessConfigRoutes() {
 		fuego.OptionQueryInt("$top", "Number of records to return"),
 		fuego.OptionQueryInt("$skip", "Number of records to skip"),
 		fuego.OptionQueryBool("$count", "Include total count"),
+		odataQueryOptions(),
 		protectedRouteOptions(),
 	)
 
 	fuego.Get(f.server, "/api/v1/admin/wirelessconfigs/{profileName}", f.getWirelessConfigByName,
 		fuego.OptionTags("Wireless"),
 		fuego.OptionSummary("Get Wireless Configuration by Name"),
-		fuego.OptionDescription("Retrieve a specific wireless configuration by profile name"),
+		fuego.OptionDescription("Retrieve a specific wireless configuration by profile name; the ETag header carries its version, for If-Match"),
 		fuego.OptionPath("profileName", "Profile name"),
 		protectedRouteOptions(),
 	)
@@ -39,15 +40,17 @@ func (f *FuegoAdapter) RegisterWirelessConfigRoutes() {
 		fuego.OptionTags("Wireless"),
 		fuego.OptionSummary("Update Wireless Configuration"),
 		fuego.OptionDescription("Update an existing wireless configuration"),
+		ifMatchOptions(),
 		protectedRouteOptions(),
 	)
 
 	fuego.Delete(f.server, "/api/v1/admin/wirelessconfigs/{profileName}", f.deleteWirelessConfig,
 		fuego.OptionTags("Wireless"),
 		fuego.OptionSummary("Delete Wireless Configuration"),
-		fuego.OptionDescription("Delete a wireless configuration by profile name"),
+		fuego.OptionDescription("Move a wireless configuration to the trash, from which it can be restored until purged"),
 		fuego.OptionPath("profileName", "Profile name"),
 		fuego.OptionDefaultStatusCode(http.StatusNoContent),
+		ifMatchOptions(),
 		protectedRouteOptions(),
 	)
 }
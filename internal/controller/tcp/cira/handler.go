@@ -85,7 +85,7 @@ func (h *APFHandler) validateCredentials(username, password string) bool {
 	ctx := context.Background()
 
 	// Fetch device from database using the UUID
-	device, err := h.devices.GetByID(ctx, h.deviceID, "", true)
+	device, err := h.devices.GetByGUID(ctx, h.deviceID, true)
 	if err != nil {
 		h.log.Warn("Failed to fetch device %s from database: %v", h.deviceID, err)
 
@@ -0,0 +1,454 @@
+// Package sshgateway exposes device Serial-over-LAN consoles over SSH.
+//
+// Operators connect with the device GUID as the SSH user name, prefixed by
+// their account when they log in as a local user (`ssh <guid>@console` for the
+// built-in admin or an authorized key, `ssh <user>@<guid>@console` for a local
+// user); the gateway opens a SOL redirection session through devices.Feature
+// and relays the serial stream over the SSH channel.
+package sshgateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/internal/tenancy"
+	"github.com/device-management-toolkit/console/internal/usecase/accesspolicies"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/internal/usecase/lockouts"
+	"github.com/device-management-toolkit/console/internal/usecase/users"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+const (
+	handshakeTimeout = 30 * time.Second
+	hostKeyFilePerm  = 0o600
+	hostKeyDirPerm   = 0o700
+	operatorKey      = "operator"
+	guidKey          = "guid"
+	tenantKey        = "tenant"
+	anonymousUser    = "anonymous"
+)
+
+// scopeKey holds, in ssh.Permissions.ExtraData, the devices the operator may
+// reach; a connection without one reaches every device of its tenant.
+type scopeKey struct{}
+
+var (
+	// ErrAuthenticationFailed is returned to SSH clients that fail authentication.
+	ErrAuthenticationFailed = errors.New("ssh gateway: authentication failed")
+	// ErrNotPermitted is returned to users whose role may not open serial consoles.
+	ErrNotPermitted = errors.New("ssh gateway: role may not open serial consoles")
+	// ErrThrottled is returned to password logins refused after repeated failures.
+	ErrThrottled = errors.New("ssh gateway: too many failed logins")
+)
+
+type Server struct {
+	sshConfig *ssh.ServerConfig
+	logDir    string
+	notify    chan error
+	listener  net.Listener
+	devices   devices.Feature
+	log       logger.Interface
+}
+
+// NewServer binds the gateway listener and starts accepting connections.
+// Passwords are checked against the built-in admin and, when u is set, local
+// user accounts. With lo, repeated failed passwords are throttled as they are
+// on the REST login. With p, operators only reach the devices their access
+// policies grant them devices:control on, as on the REST SOL routes.
+func NewServer(cfg config.SSH, auth config.Auth, d devices.Feature, u users.Feature, lo lockouts.Feature, p accesspolicies.Feature, l logger.Interface) (*Server, error) {
+	hostKey, err := loadHostKey(cfg.HostKeyFile, l)
+	if err != nil {
+		return nil, err
+	}
+
+	authorizedKeys, err := loadAuthorizedKeys(cfg.AuthorizedKeysFile)
+	if err != nil {
+		return nil, err
+	}
+
+	sshConfig := newServerConfig(auth, authorizedKeys, passwordAuth{auth: auth, users: u, lockouts: lo, policies: p, log: l})
+	sshConfig.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(cfg.Host, cfg.Port))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		sshConfig: sshConfig,
+		logDir:    cfg.SessionLogDir,
+		notify:    make(chan error, 1),
+		listener:  listener,
+		devices:   d,
+		log:       l,
+	}
+
+	s.start()
+
+	return s, nil
+}
+
+func (s *Server) start() {
+	go func() {
+		s.notify <- s.serve()
+
+		close(s.notify)
+	}()
+}
+
+// Notify returns the error channel for server notifications.
+func (s *Server) Notify() <-chan error {
+	return s.notify
+}
+
+// Addr returns the address the gateway is listening on.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+func (s *Server) serve() error {
+	s.log.Info(fmt.Sprintf("SSH gateway running on %s", s.listener.Addr().String()))
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return err
+		}
+
+		go s.handleConnection(conn)
+	}
+}
+
+// Shutdown stops accepting connections. Established sessions run until
+// either side closes them.
+func (s *Server) Shutdown() error {
+	return s.listener.Close()
+}
+
+func (s *Server) handleConnection(conn net.Conn) {
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	sshConn, channels, requests, err := ssh.NewServerConn(conn, s.sshConfig)
+	if err != nil {
+		s.log.Warn(fmt.Sprintf("SSH gateway handshake from %s failed: %v", conn.RemoteAddr(), err))
+
+		return
+	}
+
+	defer sshConn.Close()
+
+	_ = conn.SetDeadline(time.Time{})
+
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only session channels are supported")
+
+			continue
+		}
+
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			s.log.Warn(fmt.Sprintf("SSH gateway could not accept channel from %s: %v", conn.RemoteAddr(), err))
+
+			continue
+		}
+
+		go s.handleSession(sshConn, channel, channelRequests)
+	}
+}
+
+// newServerConfig accepts the console admin credentials and local user
+// accounts (basic auth mode only) and any key in the authorized keys file. A
+// bare device GUID as the SSH user name logs in as the built-in admin or by
+// key, in the default tenant and as a super-admin; <user>@<guid> logs in as a
+// local user, with their role applied, in their tenant. Access policies
+// naming the operator or their role confine each login alike.
+func newServerConfig(auth config.Auth, authorizedKeys map[string]string, pa passwordAuth) *ssh.ServerConfig {
+	sshConfig := &ssh.ServerConfig{
+		NoClientAuth: auth.Disabled,
+		NoClientAuthCallback: func(meta ssh.ConnMetadata) (*ssh.Permissions, error) {
+			_, guid := splitUser(meta.User())
+
+			return withOperator(anonymousUser, guid, tenancy.Default), nil
+		},
+	}
+
+	if auth.ClientID == "" && (auth.AdminPassword != "" || pa.users != nil) {
+		sshConfig.PasswordCallback = pa.callback
+	}
+
+	if len(authorizedKeys) > 0 {
+		sshConfig.PublicKeyCallback = func(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			operator, ok := authorizedKeys[string(key.Marshal())]
+			if !ok {
+				return nil, ErrAuthenticationFailed
+			}
+
+			_, guid := splitUser(meta.User())
+
+			return pa.withScope(context.Background(), withOperator(operator, guid, tenancy.Default), rbac.RoleSuperAdmin)
+		}
+	}
+
+	return sshConfig
+}
+
+// passwordAuth checks SSH passwords as the REST login does: the built-in
+// admin first, then local user accounts, with failed attempts counted against
+// the account and the client IP.
+type passwordAuth struct {
+	auth     config.Auth
+	users    users.Feature
+	lockouts lockouts.Feature
+	policies accesspolicies.Feature
+	log      logger.Interface
+}
+
+func (pa passwordAuth) callback(meta ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+	ctx := context.Background()
+	username, guid := splitUser(meta.User())
+	ip := remoteIP(meta.RemoteAddr())
+
+	// A bare GUID logs in as the built-in admin, so failures count against it.
+	account := username
+	if account == "" {
+		account = pa.auth.AdminUsername
+	}
+
+	if pa.lockouts != nil && pa.lockouts.Check(ctx, account, ip) > 0 {
+		pa.log.Warn(fmt.Sprintf("SSH gateway refused login for %s from %s: too many failed logins", account, ip))
+
+		return nil, ErrThrottled
+	}
+
+	perms, err := pa.check(ctx, username, guid, password)
+
+	switch {
+	case pa.lockouts == nil:
+	case err == nil, errors.Is(err, ErrNotPermitted), errors.Is(err, users.ErrPasswordChangeRequired):
+		// The password was right.
+		pa.lockouts.Succeeded(ctx, account)
+	case errors.Is(err, ErrAuthenticationFailed):
+		pa.lockouts.Failed(ctx, account, ip)
+	}
+
+	return perms, err
+}
+
+func (pa passwordAuth) check(ctx context.Context, username, guid string, password []byte) (*ssh.Permissions, error) {
+	if username == "" || username == pa.auth.AdminUsername {
+		if pa.auth.AdminPassword != "" && subtle.ConstantTimeCompare(password, []byte(pa.auth.AdminPassword)) == 1 {
+			return pa.withScope(ctx, withOperator(pa.auth.AdminUsername, guid, tenancy.Default), rbac.RoleSuperAdmin)
+		}
+
+		if username == "" {
+			return nil, ErrAuthenticationFailed
+		}
+	}
+
+	return pa.authenticateUser(ctx, username, string(password), guid)
+}
+
+// authenticateUser checks a local user's password and that their role may
+// open serial consoles, as the REST API's SOL routes require.
+func (pa passwordAuth) authenticateUser(ctx context.Context, username, password, guid string) (*ssh.Permissions, error) {
+	if pa.users == nil {
+		return nil, ErrAuthenticationFailed
+	}
+
+	user, err := pa.users.Authenticate(ctx, username, password)
+
+	switch {
+	case errors.Is(err, users.ErrInvalidCredentials):
+		return nil, ErrAuthenticationFailed
+	case errors.Is(err, users.ErrPasswordChangeRequired):
+		return nil, err
+	case err != nil:
+		pa.log.Warn(fmt.Sprintf("SSH gateway could not authenticate %s: %v", username, err))
+
+		return nil, err
+	}
+
+	role, _ := rbac.ParseRole(user.Role)
+	if !rbac.Allowed([]rbac.Role{role}, rbac.DevicesControl) {
+		pa.log.Warn(fmt.Sprintf("SSH gateway refused %s: role %s lacks the %s permission", username, user.Role, rbac.DevicesControl))
+
+		return nil, ErrNotPermitted
+	}
+
+	return pa.withScope(ctx, withOperator(user.Username, guid, user.TenantID), role)
+}
+
+// withScope confines perms to the devices the access policies of its
+// operator and role grant devices:control on, as DeviceScopeMiddleware does
+// for the REST SOL routes.
+func (pa passwordAuth) withScope(ctx context.Context, perms *ssh.Permissions, role rbac.Role) (*ssh.Permissions, error) {
+	if pa.policies == nil {
+		return perms, nil
+	}
+
+	operator := perms.Extensions[operatorKey]
+
+	scope, err := pa.policies.Scope(ctx, operator, []rbac.Role{role}, rbac.DevicesControl, perms.Extensions[tenantKey])
+	if err != nil {
+		pa.log.Warn(fmt.Sprintf("SSH gateway could not read the access policies of %s: %v", operator, err))
+
+		return nil, err
+	}
+
+	if scope != nil {
+		perms.ExtraData = map[any]any{scopeKey{}: scope}
+	}
+
+	return perms, nil
+}
+
+// scopeOf returns the device scope withScope recorded, or nil.
+func scopeOf(perms *ssh.Permissions) *devicescope.Scope {
+	scope, _ := perms.ExtraData[scopeKey{}].(*devicescope.Scope)
+
+	return scope
+}
+
+// remoteIP drops the port, so reconnecting does not reset the IP's count.
+func remoteIP(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+
+	return host
+}
+
+// withOperator records who logged in, the device they asked for and the
+// tenant it is looked up in, for the session.
+func withOperator(name, guid, tenantID string) *ssh.Permissions {
+	return &ssh.Permissions{Extensions: map[string]string{operatorKey: name, guidKey: guid, tenantKey: tenantID}}
+}
+
+// splitUser separates the SSH user name into the local account, if one is
+// named, and the device GUID. The GUID holds no "@", so the last one splits.
+func splitUser(name string) (username, guid string) {
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+
+	return "", name
+}
+
+// loadAuthorizedKeys parses an OpenSSH authorized_keys file. The key comment
+// names the operator; keys without one fall back to their fingerprint.
+func loadAuthorizedKeys(path string) (map[string]string, error) {
+	keys := map[string]string{}
+
+	if path == "" {
+		return keys, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for len(bytes.TrimSpace(data)) > 0 {
+		key, comment, _, rest, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("ssh gateway: %s: %w", path, err)
+		}
+
+		if comment == "" {
+			comment = ssh.FingerprintSHA256(key)
+		}
+
+		keys[string(key.Marshal())] = comment
+		data = rest
+	}
+
+	return keys, nil
+}
+
+// loadHostKey reads the gateway host key, creating the file with a new
+// ed25519 key if it does not exist. An empty path yields an in-memory key, so
+// clients will see a new host key after every restart.
+func loadHostKey(path string, l logger.Interface) (ssh.Signer, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return ssh.ParsePrivateKey(data)
+		}
+
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		l.Warn("SSH gateway host key file not configured; using a temporary host key")
+
+		return ssh.NewSignerFromKey(key)
+	}
+
+	block, err := ssh.MarshalPrivateKey(key, "console ssh gateway")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), hostKeyDirPerm); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), hostKeyFilePerm); err != nil {
+		return nil, err
+	}
+
+	l.Info(fmt.Sprintf("SSH gateway generated host key %s", path))
+
+	return ssh.NewSignerFromKey(key)
+}
+
+// sanitizeForFilename keeps transcript names predictable regardless of what
+// the client sent as its user name.
+func sanitizeForFilename(value string) string {
+	var b strings.Builder
+
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	return b.String()
+}
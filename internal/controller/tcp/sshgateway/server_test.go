@@ -0,0 +1,374 @@
+package sshgateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/internal/tenancy"
+	"github.com/device-management-toolkit/console/internal/usecase/accesspolicies"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/internal/usecase/lockouts"
+	"github.com/device-management-toolkit/console/internal/usecase/users"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+const (
+	testGUID     = "8c4f2a2b-6d7e-4b1a-9f3e-0d5c2b1a7e6f"
+	testPassword = "P@ssw0rd"
+)
+
+// pipeSOL stands in for the device side of a SOL session.
+type pipeSOL struct {
+	net.Conn
+}
+
+func startTestServer(t *testing.T, d devices.Feature, u users.Feature, p accesspolicies.Feature, logDir string) *Server {
+	t.Helper()
+
+	s, err := NewServer(
+		config.SSH{Host: "127.0.0.1", Port: "0", SessionLogDir: logDir},
+		config.Auth{AdminUsername: "standalone", AdminPassword: testPassword},
+		d,
+		u,
+		nil,
+		p,
+		logger.New("error"),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = s.Shutdown() })
+
+	return s
+}
+
+func dial(t *testing.T, s *Server, user, password string) (*ssh.Client, error) {
+	t.Helper()
+
+	return ssh.Dial("tcp", s.Addr().String(), &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.Password(password)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint:gosec // test server with a throwaway key
+		Timeout:         5 * time.Second,
+	})
+}
+
+func TestGatewayRelaysSerialConsole(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	feature := mocks.NewMockDeviceManagementFeature(mockCtl)
+
+	deviceSide, gatewaySide := net.Pipe()
+	feature.EXPECT().OpenSOL(gomock.Any(), testGUID).Return(pipeSOL{gatewaySide}, nil)
+
+	logDir := t.TempDir()
+	s := startTestServer(t, feature, nil, nil, logDir)
+
+	client, err := dial(t, s, testGUID, testPassword)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	sess, err := client.NewSession()
+	require.NoError(t, err)
+
+	stdin, err := sess.StdinPipe()
+	require.NoError(t, err)
+
+	stdout, err := sess.StdoutPipe()
+	require.NoError(t, err)
+
+	require.NoError(t, sess.RequestPty("xterm", 24, 80, ssh.TerminalModes{}))
+	require.NoError(t, sess.Shell())
+	require.NoError(t, sess.WindowChange(50, 132))
+
+	go func() {
+		_, _ = deviceSide.Write([]byte("login: "))
+	}()
+
+	buf := make([]byte, len("login: "))
+	_, err = io.ReadFull(stdout, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "login: ", string(buf))
+
+	_, err = stdin.Write([]byte("root\r"))
+	require.NoError(t, err)
+
+	typed := make([]byte, len("root\r"))
+	_, err = io.ReadFull(deviceSide, typed)
+	require.NoError(t, err)
+	assert.Equal(t, "root\r", string(typed))
+
+	// Device hangs up: the operator sees the session end cleanly.
+	require.NoError(t, deviceSide.Close())
+	require.NoError(t, sess.Wait())
+
+	assert.Eventually(t, func() bool {
+		entries, _ := os.ReadDir(logDir)
+		if len(entries) != 1 {
+			return false
+		}
+
+		data, _ := os.ReadFile(filepath.Join(logDir, entries[0].Name()))
+
+		return string(data) == "login: "
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestGatewayRejectsWrongPassword(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	s := startTestServer(t, mocks.NewMockDeviceManagementFeature(mockCtl), nil, nil, "")
+
+	_, err := dial(t, s, testGUID, "wrong")
+	require.Error(t, err)
+}
+
+func TestGatewayReportsUnknownDevice(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	feature := mocks.NewMockDeviceManagementFeature(mockCtl)
+	feature.EXPECT().OpenSOL(gomock.Any(), testGUID).Return(nil, devices.ErrNotFound)
+
+	s := startTestServer(t, feature, nil, nil, "")
+
+	client, err := dial(t, s, testGUID, testPassword)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	sess, err := client.NewSession()
+	require.NoError(t, err)
+
+	var stderr bytes.Buffer
+
+	sess.Stderr = &stderr
+
+	require.NoError(t, sess.Shell())
+
+	var exitErr *ssh.ExitError
+
+	require.ErrorAs(t, sess.Wait(), &exitErr)
+	assert.Equal(t, exitStatusFailure, exitErr.ExitStatus())
+	assert.Contains(t, stderr.String(), "unknown device")
+}
+
+func TestGatewayOpensDeviceInUsersTenant(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	feature := mocks.NewMockDeviceManagementFeature(mockCtl)
+	accounts := mocks.NewMockUsersFeature(mockCtl)
+
+	accounts.EXPECT().Authenticate(gomock.Any(), "jdoe", "secret").
+		Return(&dto.User{Username: "jdoe", Role: "operator", TenantID: "tenant-a"}, nil)
+
+	tenants := make(chan string, 1)
+
+	feature.EXPECT().OpenSOL(gomock.Any(), testGUID).DoAndReturn(func(ctx context.Context, _ string) (io.ReadWriteCloser, error) {
+		tenants <- tenancy.FromContext(ctx)
+
+		return nil, devices.ErrNotFound
+	})
+
+	s := startTestServer(t, feature, accounts, nil, "")
+
+	client, err := dial(t, s, "jdoe@"+testGUID, "secret")
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	sess, err := client.NewSession()
+	require.NoError(t, err)
+	require.NoError(t, sess.Shell())
+	require.Error(t, sess.Wait())
+
+	assert.Equal(t, "tenant-a", <-tenants)
+}
+
+func TestGatewayConfinesUsersToTheirDeviceScope(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	feature := mocks.NewMockDeviceManagementFeature(mockCtl)
+	accounts := mocks.NewMockUsersFeature(mockCtl)
+	policies := mocks.NewMockAccessPoliciesFeature(mockCtl)
+
+	accounts.EXPECT().Authenticate(gomock.Any(), "jdoe", "secret").
+		Return(&dto.User{Username: "jdoe", Role: "helpdesk", TenantID: "tenant-a"}, nil)
+	policies.EXPECT().Scope(gomock.Any(), "jdoe", []rbac.Role{rbac.RoleHelpdesk}, rbac.DevicesControl, "tenant-a").
+		Return(&devicescope.Scope{Expressions: []devicescope.Expression{{Tags: []string{"site-a"}}}}, nil)
+
+	// The device carries site-b only; the devices use case hides it from a
+	// scope that does not allow it.
+	feature.EXPECT().OpenSOL(gomock.Any(), testGUID).DoAndReturn(func(ctx context.Context, _ string) (io.ReadWriteCloser, error) {
+		if !devicescope.FromContext(ctx).Allows([]string{"site-b"}) {
+			return nil, devices.ErrNotFound
+		}
+
+		return nil, devices.ErrRedirectionBusy
+	})
+
+	s := startTestServer(t, feature, accounts, policies, "")
+
+	client, err := dial(t, s, "jdoe@"+testGUID, "secret")
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	sess, err := client.NewSession()
+	require.NoError(t, err)
+
+	var stderr bytes.Buffer
+
+	sess.Stderr = &stderr
+
+	require.NoError(t, sess.Shell())
+	require.Error(t, sess.Wait())
+	assert.Contains(t, stderr.String(), "unknown device")
+}
+
+func TestGatewayAcceptsAuthorizedKey(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+
+	keysFile := filepath.Join(t.TempDir(), "authorized_keys")
+	line := bytes.TrimSpace(ssh.MarshalAuthorizedKey(sshPub))
+	require.NoError(t, os.WriteFile(keysFile, append(line, []byte(" alice@example\n")...), 0o600))
+
+	keys, err := loadAuthorizedKeys(keysFile)
+	require.NoError(t, err)
+
+	perms, err := newServerConfig(config.Auth{}, keys, passwordAuth{}).PublicKeyCallback(connMeta(testGUID), signer.PublicKey())
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example", perms.Extensions[operatorKey])
+	assert.Equal(t, testGUID, perms.Extensions[guidKey])
+}
+
+func TestGatewayAuthenticatesLocalUsers(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	accounts := mocks.NewMockUsersFeature(mockCtl)
+
+	accounts.EXPECT().Authenticate(gomock.Any(), "jdoe", "secret").Return(&dto.User{Username: "jdoe", Role: "helpdesk"}, nil)
+	accounts.EXPECT().Authenticate(gomock.Any(), "jdoe", "wrong").Return(nil, users.ErrInvalidCredentials)
+	accounts.EXPECT().Authenticate(gomock.Any(), "viewer", "secret").Return(&dto.User{Username: "viewer", Role: "read-only"}, nil)
+
+	auth := config.Auth{AdminUsername: "standalone", AdminPassword: testPassword}
+	sshConfig := newServerConfig(auth, nil, passwordAuth{auth: auth, users: accounts, log: logger.New("error")})
+
+	perms, err := sshConfig.PasswordCallback(connMeta("jdoe@"+testGUID), []byte("secret"))
+	require.NoError(t, err)
+	assert.Equal(t, "jdoe", perms.Extensions[operatorKey])
+	assert.Equal(t, testGUID, perms.Extensions[guidKey])
+
+	_, err = sshConfig.PasswordCallback(connMeta("jdoe@"+testGUID), []byte("wrong"))
+	require.ErrorIs(t, err, ErrAuthenticationFailed)
+
+	// The role applies: read-only may not open a serial console.
+	_, err = sshConfig.PasswordCallback(connMeta("viewer@"+testGUID), []byte("secret"))
+	require.ErrorIs(t, err, ErrNotPermitted)
+
+	// The built-in admin may also name itself.
+	perms, err = sshConfig.PasswordCallback(connMeta("standalone@"+testGUID), []byte(testPassword))
+	require.NoError(t, err)
+	assert.Equal(t, "standalone", perms.Extensions[operatorKey])
+}
+
+func TestGatewayThrottlesFailedPasswords(t *testing.T) {
+	t.Parallel()
+
+	auth := config.Auth{AdminUsername: "standalone", AdminPassword: testPassword}
+	lockout := lockouts.New(logger.New("error"), config.LoginLockout{MaxAttempts: 2, Duration: time.Minute})
+	sshConfig := newServerConfig(auth, nil, passwordAuth{auth: auth, lockouts: lockout, log: logger.New("error")})
+
+	for range 2 {
+		_, err := sshConfig.PasswordCallback(connMeta(testGUID), []byte("wrong"))
+		require.ErrorIs(t, err, ErrAuthenticationFailed)
+	}
+
+	// Locked out: even the right password is refused, under either name.
+	_, err := sshConfig.PasswordCallback(connMeta(testGUID), []byte(testPassword))
+	require.ErrorIs(t, err, ErrThrottled)
+
+	_, err = sshConfig.PasswordCallback(connMeta("standalone@"+testGUID), []byte(testPassword))
+	require.ErrorIs(t, err, ErrThrottled)
+
+	require.NoError(t, lockout.Clear(context.Background(), lockouts.KindUsername, "standalone"))
+
+	_, err = sshConfig.PasswordCallback(connMeta(testGUID), []byte(testPassword))
+	require.NoError(t, err)
+}
+
+// connMeta is the connection metadata of a client logging in as user.
+type connMeta string
+
+func (c connMeta) User() string        { return string(c) }
+func (connMeta) SessionID() []byte     { return nil }
+func (connMeta) ClientVersion() []byte { return nil }
+func (connMeta) ServerVersion() []byte { return nil }
+func (connMeta) RemoteAddr() net.Addr  { return &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 50022} }
+func (connMeta) LocalAddr() net.Addr   { return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 2222} }
+
+func TestLoadHostKeyPersistsGeneratedKey(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "ssh", "host_key")
+	log := logger.New("error")
+
+	first, err := loadHostKey(path, log)
+	require.NoError(t, err)
+
+	second, err := loadHostKey(path, log)
+	require.NoError(t, err)
+
+	assert.Equal(t, first.PublicKey().Marshal(), second.PublicKey().Marshal())
+}
+
+func TestParsePtyRequest(t *testing.T) {
+	t.Parallel()
+
+	payload := ssh.Marshal(struct {
+		Term          string
+		Cols, Rows    uint32
+		Width, Height uint32
+		Modes         string
+	}{Term: "xterm", Cols: 80, Rows: 24})
+
+	term, size, ok := parsePtyRequest(payload)
+	require.True(t, ok)
+	assert.Equal(t, "xterm", term)
+	assert.Equal(t, window{cols: 80, rows: 24}, size)
+
+	_, _, ok = parsePtyRequest([]byte{0, 0, 0, 9, 'x'})
+	assert.False(t, ok)
+}
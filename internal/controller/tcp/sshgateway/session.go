@@ -0,0 +1,267 @@
+package sshgateway
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/tenancy"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+)
+
+const (
+	transcriptFilePerm = 0o600
+	transcriptDirPerm  = 0o700
+	exitStatusFailure  = 1
+)
+
+// window is the operator's terminal size from pty-req / window-change.
+// Resizes are not forwarded: AMT SOL is a plain serial byte stream with no
+// out-of-band size message (unlike SSH or telnet NAWS), so the host keeps
+// whatever its serial getty assumes until the operator runs
+// `stty rows R cols C` (or `resize`) there. The size is tracked for logging.
+type window struct {
+	cols, rows uint32
+}
+
+type session struct {
+	server   *Server
+	conn     *ssh.ServerConn
+	channel  ssh.Channel
+	guid     string
+	operator string
+	tenantID string
+	scope    *devicescope.Scope
+
+	mu      sync.Mutex
+	term    string
+	window  window
+	started bool
+
+	bytesIn  atomic.Int64
+	bytesOut atomic.Int64
+}
+
+func (s *Server) handleSession(conn *ssh.ServerConn, channel ssh.Channel, requests <-chan *ssh.Request) {
+	sess := &session{
+		server:   s,
+		conn:     conn,
+		channel:  channel,
+		guid:     conn.Permissions.Extensions[guidKey],
+		operator: conn.Permissions.Extensions[operatorKey],
+		tenantID: conn.Permissions.Extensions[tenantKey],
+		scope:    scopeOf(conn.Permissions),
+	}
+
+	for req := range requests {
+		ok := sess.handleRequest(req)
+
+		if req.WantReply {
+			_ = req.Reply(ok, nil)
+		}
+
+		// Start relaying only after the shell request is acknowledged, so
+		// clients see the reply before any console output or error.
+		if ok && req.Type == "shell" {
+			go sess.run()
+		}
+	}
+}
+
+func (sess *session) handleRequest(req *ssh.Request) bool {
+	switch req.Type {
+	case "pty-req":
+		term, size, ok := parsePtyRequest(req.Payload)
+		if ok {
+			sess.mu.Lock()
+			sess.term, sess.window = term, size
+			sess.mu.Unlock()
+		}
+
+		return ok
+	case "window-change":
+		size, ok := parseWindow(req.Payload)
+		if ok {
+			sess.mu.Lock()
+			sess.window = size
+			sess.mu.Unlock()
+
+			sess.server.log.Debug("SSH gateway window change", "guid", sess.guid, "cols", size.cols, "rows", size.rows)
+		}
+
+		return ok
+	case "env":
+		return true
+	case "shell":
+		sess.mu.Lock()
+		started := sess.started
+		sess.started = true
+		sess.mu.Unlock()
+
+		return !started
+	default:
+		// exec and subsystem requests have no meaning on a serial console.
+		return false
+	}
+}
+
+// run opens the SOL session and relays until either side disconnects. The
+// device is looked up in the operator's tenant and device scope, so one
+// outside it is unknown.
+func (sess *session) run() {
+	defer sess.channel.Close()
+
+	remote := sess.conn.RemoteAddr().String()
+	start := time.Now()
+
+	ctx := tenancy.WithTenant(context.Background(), sess.tenantID)
+	if sess.scope != nil {
+		ctx = devicescope.WithScope(ctx, sess.scope)
+	}
+
+	ctx, cancel := context.WithCancel(devices.WithSessionOrigin(ctx, devices.SessionOrigin{
+		User:       sess.operator,
+		RemoteAddr: remote,
+	}))
+	defer cancel()
+
+	sol, err := sess.server.devices.OpenSOL(ctx, sess.guid)
+	if err != nil {
+		sess.server.log.Warn(fmt.Sprintf("SSH gateway SOL session for %s by %s from %s failed: %v", sess.guid, sess.operator, remote, err))
+		sess.fail(err)
+
+		return
+	}
+
+	defer sol.Close()
+
+	transcript, err := sess.openTranscript(start)
+	if err != nil {
+		sess.server.log.Error(fmt.Sprintf("SSH gateway transcript for %s: %v", sess.guid, err))
+	}
+
+	if transcript != nil {
+		defer transcript.Close()
+	}
+
+	sess.mu.Lock()
+	term, size := sess.term, sess.window
+	sess.mu.Unlock()
+
+	sess.server.log.Info(fmt.Sprintf("SSH gateway SOL session started: guid=%s operator=%s remote=%s term=%s size=%dx%d",
+		sess.guid, sess.operator, remote, term, size.cols, size.rows))
+
+	sess.relay(sol, transcript)
+
+	sess.server.log.Info(fmt.Sprintf("SSH gateway SOL session ended: guid=%s operator=%s remote=%s duration=%s in=%d out=%d",
+		sess.guid, sess.operator, remote, time.Since(start).Round(time.Second), sess.bytesIn.Load(), sess.bytesOut.Load()))
+
+	sess.exit(0)
+}
+
+// relay copies serial output to the operator (and transcript) and keystrokes
+// to the device. Only device output is recorded, so passwords typed at a
+// non-echoing prompt stay out of the transcript.
+func (sess *session) relay(sol io.ReadWriteCloser, transcript io.Writer) {
+	done := make(chan struct{}, 2)
+
+	go func() {
+		var out io.Writer = sess.channel
+		if transcript != nil {
+			out = io.MultiWriter(sess.channel, transcript)
+		}
+
+		n, _ := io.Copy(out, sol)
+		sess.bytesOut.Add(n)
+
+		done <- struct{}{}
+	}()
+
+	go func() {
+		n, _ := io.Copy(sol, sess.channel)
+		sess.bytesIn.Add(n)
+
+		done <- struct{}{}
+	}()
+
+	<-done
+
+	// Unblock whichever copy is still running.
+	_ = sol.Close()
+	_ = sess.channel.CloseWrite()
+}
+
+func (sess *session) openTranscript(start time.Time) (*os.File, error) {
+	if sess.server.logDir == "" {
+		return nil, nil //nolint:nilnil // transcripts are optional
+	}
+
+	if err := os.MkdirAll(sess.server.logDir, transcriptDirPerm); err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s-%s.log", sanitizeForFilename(sess.guid), start.UTC().Format("20060102T150405Z"))
+
+	return os.OpenFile(filepath.Join(sess.server.logDir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, transcriptFilePerm)
+}
+
+func (sess *session) fail(err error) {
+	message := "unable to open serial console: " + err.Error()
+
+	switch {
+	case errors.Is(err, devices.ErrNotFound):
+		message = fmt.Sprintf("unknown device %q", sess.guid)
+	case errors.Is(err, devices.ErrRedirectionBusy):
+		message = "serial console is in use by another session"
+	}
+
+	_, _ = fmt.Fprintf(sess.channel.Stderr(), "%s\r\n", message)
+
+	sess.exit(exitStatusFailure)
+}
+
+func (sess *session) exit(status uint32) {
+	_, _ = sess.channel.SendRequest("exit-status", false, binary.BigEndian.AppendUint32(nil, status))
+}
+
+// parsePtyRequest decodes RFC 4254 section 6.2: string TERM, uint32 cols,
+// uint32 rows, uint32 width px, uint32 height px, string modes.
+func parsePtyRequest(payload []byte) (string, window, bool) {
+	if len(payload) < 4 {
+		return "", window{}, false
+	}
+
+	termLen := binary.BigEndian.Uint32(payload)
+	if uint64(len(payload)) < 4+uint64(termLen) {
+		return "", window{}, false
+	}
+
+	term := string(payload[4 : 4+termLen])
+
+	size, ok := parseWindow(payload[4+termLen:])
+
+	return term, size, ok
+}
+
+// parseWindow decodes the leading uint32 cols, uint32 rows pair shared by
+// pty-req and window-change.
+func parseWindow(payload []byte) (window, bool) {
+	if len(payload) < 8 {
+		return window{}, false
+	}
+
+	return window{
+		cols: binary.BigEndian.Uint32(payload),
+		rows: binary.BigEndian.Uint32(payload[4:]),
+	}, true
+}
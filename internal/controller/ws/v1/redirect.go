@@ -13,21 +13,27 @@ import (
 	"github.com/gorilla/websocket"
 
 	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/tenancy"
 	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/internal/usecase/tokens"
 	"github.com/device-management-toolkit/console/pkg/logger"
 )
 
 var ErrUnexpectedSigningMethod = errors.New("unexpected signing method")
 
 type RedirectRoutes struct {
-	d devices.Feature
-	l logger.Interface
-	u Upgrader
+	d      devices.Feature
+	tokens tokens.Feature
+	l      logger.Interface
+	u      Upgrader
 }
 
-func RegisterRoutes(r *gin.Engine, l logger.Interface, t devices.Feature, u Upgrader) {
+// RegisterRoutes serves redirection websockets. With tk, revoked redirection
+// tokens are refused.
+func RegisterRoutes(r *gin.Engine, l logger.Interface, t devices.Feature, tk tokens.Feature, u Upgrader) {
 	rr := &RedirectRoutes{
 		t,
+		tk,
 		l,
 		u,
 	}
@@ -38,7 +44,8 @@ func (r *RedirectRoutes) websocketHandler(c *gin.Context) {
 	tokenString := c.GetHeader("Sec-Websocket-Protocol")
 
 	// validate the jwt token in the Sec-Websocket-protocol header
-	if !r.validateRedirectionToken(c, tokenString) {
+	user, tenantID, ok := r.validateRedirectionToken(c, tokenString)
+	if !ok {
 		return
 	}
 
@@ -62,10 +69,12 @@ func (r *RedirectRoutes) websocketHandler(c *gin.Context) {
 		return
 	}
 
-	// Optimize websocket data path for streaming; respect config compression toggle
-	if config.ConsoleConfig.WSCompression {
+	// Compression is chosen per mode: KVM framebuffers compress well, while
+	// IDER disk traffic may not be worth the CPU.
+	level := config.ConsoleConfig.Redirection.Mode(c.Query("mode")).CompressionLevel
+	if level > flate.NoCompression {
 		conn.EnableWriteCompression(true)
-		_ = conn.SetCompressionLevel(flate.BestSpeed)
+		_ = conn.SetCompressionLevel(min(level, flate.BestCompression))
 	} else {
 		conn.EnableWriteCompression(false)
 		_ = conn.SetCompressionLevel(flate.NoCompression)
@@ -75,7 +84,9 @@ func (r *RedirectRoutes) websocketHandler(c *gin.Context) {
 
 	// KVM_TIMING: Measure total connection time
 	totalStart := time.Now()
-	err = r.d.Redirect(c, conn, c.Query("host"), c.Query("mode"))
+	ctx := tenancy.WithTenant(c, tenantID)
+	ctx = devices.WithSessionOrigin(ctx, devices.SessionOrigin{User: user, RemoteAddr: c.ClientIP()})
+	err = r.d.Redirect(ctx, conn, c.Query("host"), c.Query("mode"))
 	totalDuration := time.Since(totalStart)
 	devices.RecordTotalConnection(totalDuration, c.Query("mode"))
 	r.l.Debug("KVM_TIMING: Total connection time", "duration_ms", totalDuration.Milliseconds(), "mode", c.Query("mode"))
@@ -86,16 +97,18 @@ func (r *RedirectRoutes) websocketHandler(c *gin.Context) {
 	}
 }
 
-// validateRedirectionToken checks the JWT and that its deviceId matches the host.
-func (r *RedirectRoutes) validateRedirectionToken(c *gin.Context, tokenString string) bool {
+// validateRedirectionToken checks the JWT and that its deviceId matches the
+// host. It returns the user the token was issued to, if it names one, and
+// the tenant the device was found in.
+func (r *RedirectRoutes) validateRedirectionToken(c *gin.Context, tokenString string) (user, tenantID string, ok bool) {
 	if config.ConsoleConfig.Disabled {
-		return true
+		return "", tenancy.Default, true
 	}
 
 	if tokenString == "" {
 		http.Error(c.Writer, "request does not contain an access token", http.StatusUnauthorized)
 
-		return false
+		return "", "", false
 	}
 
 	claims := &jwt.MapClaims{}
@@ -111,7 +124,7 @@ func (r *RedirectRoutes) validateRedirectionToken(c *gin.Context, tokenString st
 	if err != nil || !token.Valid {
 		http.Error(c.Writer, "invalid access token", http.StatusUnauthorized)
 
-		return false
+		return "", "", false
 	}
 
 	// deviceId must be present and match host; blocks other-device and login tokens.
@@ -121,8 +134,24 @@ func (r *RedirectRoutes) validateRedirectionToken(c *gin.Context, tokenString st
 		r.l.Warn("redirection token not authorized for requested device", "host", c.Query("host"))
 		http.Error(c.Writer, "token not authorized for this device", http.StatusForbidden)
 
-		return false
+		return "", "", false
 	}
 
-	return true
+	// The token, or the login session it was issued in, may have been revoked.
+	if r.tokens != nil {
+		jti, _ := (*claims)["jti"].(string)
+		sid, _ := (*claims)["sid"].(string)
+
+		revoked, err := r.tokens.IsRevoked(c.Request.Context(), jti, sid)
+		if err != nil || revoked {
+			http.Error(c.Writer, "invalid access token", http.StatusUnauthorized)
+
+			return "", "", false
+		}
+	}
+
+	user, _ = (*claims)["sub"].(string)
+	tenantID, _ = (*claims)["tenantId"].(string)
+
+	return user, tenantID, true
 }
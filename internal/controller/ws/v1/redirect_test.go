@@ -90,7 +90,7 @@ func TestWebSocketHandler(t *testing.T) { //nolint:paralleltest // logging libra
 			}
 
 			r := gin.Default()
-			RegisterRoutes(r, mockLogger, mockFeature, mockUpgrader)
+			RegisterRoutes(r, mockLogger, mockFeature, nil, mockUpgrader)
 
 			req := httptest.NewRequest(http.MethodGet, "/relay/webrelay.ashx?host=someHost&mode=someMode", http.NoBody)
 			w := httptest.NewRecorder()
@@ -133,7 +133,7 @@ func TestWebSocketHandlerDeviceBinding(t *testing.T) { //nolint:paralleltest //
 		mockLogger.EXPECT().Warn("redirection token not authorized for requested device", "host", "deviceB")
 
 		r := gin.Default()
-		RegisterRoutes(r, mockLogger, mockFeature, mockUpgrader)
+		RegisterRoutes(r, mockLogger, mockFeature, nil, mockUpgrader)
 
 		req := httptest.NewRequest(http.MethodGet, "/relay/webrelay.ashx?host=deviceB&mode=kvm", http.NoBody)
 		req.Header.Set("Sec-Websocket-Protocol", tokenFor("deviceA"))
@@ -151,7 +151,7 @@ func TestWebSocketHandlerDeviceBinding(t *testing.T) { //nolint:paralleltest //
 		mockLogger.EXPECT().Warn("redirection token not authorized for requested device", "host", "deviceA")
 
 		r := gin.Default()
-		RegisterRoutes(r, mockLogger, mockFeature, mockUpgrader)
+		RegisterRoutes(r, mockLogger, mockFeature, nil, mockUpgrader)
 
 		req := httptest.NewRequest(http.MethodGet, "/relay/webrelay.ashx?host=deviceA&mode=kvm", http.NoBody)
 		req.Header.Set("Sec-Websocket-Protocol", tokenFor("")) // no deviceId == login token
@@ -169,7 +169,7 @@ func TestWebSocketHandlerDeviceBinding(t *testing.T) { //nolint:paralleltest //
 		mockLogger.EXPECT().Warn("redirection token not authorized for requested device", "host", "")
 
 		r := gin.Default()
-		RegisterRoutes(r, mockLogger, mockFeature, mockUpgrader)
+		RegisterRoutes(r, mockLogger, mockFeature, nil, mockUpgrader)
 
 		req := httptest.NewRequest(http.MethodGet, "/relay/webrelay.ashx?mode=kvm", http.NoBody)
 		req.Header.Set("Sec-Websocket-Protocol", tokenFor("")) // no deviceId, no host
@@ -193,7 +193,7 @@ func TestWebSocketHandlerDeviceBinding(t *testing.T) { //nolint:paralleltest //
 		mockLogger.EXPECT().Debug("KVM_TIMING: Total connection time", "duration_ms", gomock.Any(), "mode", "kvm")
 
 		r := gin.Default()
-		RegisterRoutes(r, mockLogger, mockFeature, mockUpgrader)
+		RegisterRoutes(r, mockLogger, mockFeature, nil, mockUpgrader)
 
 		req := httptest.NewRequest(http.MethodGet, "/relay/webrelay.ashx?host=deviceA&mode=kvm", http.NoBody)
 		req.Header.Set("Sec-Websocket-Protocol", tokenFor("deviceA"))
@@ -217,7 +217,7 @@ func TestWebSocketHandlerDeviceBinding(t *testing.T) { //nolint:paralleltest //
 		mockLogger.EXPECT().Debug("KVM_TIMING: Total connection time", "duration_ms", gomock.Any(), "mode", "kvm")
 
 		r := gin.Default()
-		RegisterRoutes(r, mockLogger, mockFeature, mockUpgrader)
+		RegisterRoutes(r, mockLogger, mockFeature, nil, mockUpgrader)
 
 		req := httptest.NewRequest(http.MethodGet, "/relay/webrelay.ashx?host=DeviceA&mode=kvm", http.NoBody)
 		req.Header.Set("Sec-Websocket-Protocol", tokenFor("devicea"))
@@ -229,6 +229,44 @@ func TestWebSocketHandlerDeviceBinding(t *testing.T) { //nolint:paralleltest //
 	})
 }
 
+// TestWebSocketHandlerRevokedToken: WS rejects a token that, or whose login
+// session, was revoked.
+func TestWebSocketHandlerRevokedToken(t *testing.T) { //nolint:paralleltest // logging library is not thread-safe for tests
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	_, _ = config.NewConfig()
+
+	config.ConsoleConfig.Disabled = false
+	config.ConsoleConfig.JWTKey = "test-jwt-key"
+
+	claims := jwt.MapClaims{
+		"exp":      time.Now().Add(5 * time.Minute).Unix(),
+		"deviceId": "deviceA",
+		"jti":      "token-id",
+		"sid":      "session-id",
+	}
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.ConsoleConfig.JWTKey))
+
+	mockFeature := mocks.NewMockDeviceManagementFeature(ctrl)
+	mockTokens := mocks.NewMockTokensFeature(ctrl)
+	mockUpgrader := mocks.NewMockUpgrader(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+
+	mockTokens.EXPECT().IsRevoked(gomock.Any(), "token-id", "session-id").Return(true, nil)
+
+	r := gin.Default()
+	RegisterRoutes(r, mockLogger, mockFeature, mockTokens, mockUpgrader)
+
+	req := httptest.NewRequest(http.MethodGet, "/relay/webrelay.ashx?host=deviceA&mode=kvm", http.NoBody)
+	req.Header.Set("Sec-Websocket-Protocol", token)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
 // TestWebSocketHandlerTokenValidation: WS rejects missing and unverifiable tokens.
 func TestWebSocketHandlerTokenValidation(t *testing.T) { //nolint:paralleltest // logging library is not thread-safe for tests
 	ctrl := gomock.NewController(t)
@@ -280,7 +318,7 @@ func TestWebSocketHandlerTokenValidation(t *testing.T) { //nolint:paralleltest /
 			mockLogger := mocks.NewMockLogger(ctrl)
 
 			r := gin.Default()
-			RegisterRoutes(r, mockLogger, mockFeature, mockUpgrader)
+			RegisterRoutes(r, mockLogger, mockFeature, nil, mockUpgrader)
 
 			req := httptest.NewRequest(http.MethodGet, "/relay/webrelay.ashx?host=deviceA&mode=kvm", http.NoBody)
 			if tc.token != "" {
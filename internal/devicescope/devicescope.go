@@ -0,0 +1,71 @@
+// Package devicescope carries the devices a request may reach through its
+// context, so the devices use case can hide the others without each signature
+// naming the caller. A context without a scope reaches every device.
+package devicescope
+
+import (
+	"context"
+	"slices"
+)
+
+// Expression matches devices by tag, as the device list's tag filter does:
+// carrying any of Tags, or all of them when All is set.
+type Expression struct {
+	Tags []string
+	All  bool
+}
+
+// Matches reports whether a device carrying tags matches e.
+func (e Expression) Matches(tags []string) bool {
+	if len(e.Tags) == 0 {
+		return false
+	}
+
+	for _, tag := range e.Tags {
+		found := slices.Contains(tags, tag)
+		if found && !e.All {
+			return true
+		}
+
+		if !found && e.All {
+			return false
+		}
+	}
+
+	return e.All
+}
+
+// Scope is the union of its expressions. An empty scope reaches no device.
+type Scope struct {
+	Expressions []Expression
+}
+
+// Allows reports whether a device carrying tags is in scope. A nil scope
+// allows every device.
+func (s *Scope) Allows(tags []string) bool {
+	if s == nil {
+		return true
+	}
+
+	for _, e := range s.Expressions {
+		if e.Matches(tags) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type contextKey struct{}
+
+// WithScope returns a copy of ctx limited to s.
+func WithScope(ctx context.Context, s *Scope) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// FromContext returns the scope of ctx, or nil when it is unrestricted.
+func FromContext(ctx context.Context) *Scope {
+	s, _ := ctx.Value(contextKey{}).(*Scope)
+
+	return s
+}
@@ -0,0 +1,35 @@
+package devicescope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope(t *testing.T) {
+	t.Parallel()
+
+	scope := &Scope{Expressions: []Expression{
+		{Tags: []string{"site-a", "site-b"}},
+		{Tags: []string{"lab", "kiosk"}, All: true},
+	}}
+
+	require.True(t, scope.Allows([]string{"site-b"}))
+	require.True(t, scope.Allows([]string{"kiosk", "lab"}))
+	require.False(t, scope.Allows([]string{"lab"}), "all tags of an expression are required")
+	require.False(t, scope.Allows(nil))
+	require.False(t, (&Scope{}).Allows([]string{"site-a"}), "an empty scope reaches nothing")
+
+	var unrestricted *Scope
+	require.True(t, unrestricted.Allows(nil))
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, FromContext(context.Background()))
+
+	scope := &Scope{}
+	require.Same(t, scope, FromContext(WithScope(context.Background(), scope)))
+}
@@ -0,0 +1,17 @@
+package entity
+
+// AccessPolicy limits the devices a user or role may reach to those matching
+// a tag expression. Subject is "user:<username>" or "role:<role>". Tags and
+// Permissions are comma separated, like Device.Tags; no Permissions means
+// every device permission.
+type AccessPolicy struct {
+	ID           string `bson:"id"`
+	Name         string `bson:"name"`
+	Subject      string `bson:"subject"`
+	Tags         string `bson:"tags"`
+	Method       string `bson:"method"`
+	Permissions  string `bson:"permissions"`
+	CreationDate string `bson:"creationdate"`
+	CreatedBy    string `bson:"createdby"`
+	TenantID     string `bson:"tenantid"`
+}
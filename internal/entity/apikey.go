@@ -0,0 +1,18 @@
+package entity
+
+// APIKey is a long-lived credential for automation. KeyHash is the SHA-256 of
+// the secret; the secret itself is shown once, on creation. Routes and Tags
+// are comma separated, like Device.Tags.
+type APIKey struct {
+	ID           string `bson:"id"`
+	Name         string `bson:"name"`
+	KeyHash      string `bson:"keyhash"`
+	Role         string `bson:"role"`
+	Routes       string `bson:"routes"`
+	Tags         string `bson:"tags"`
+	ExpiresAt    string `bson:"expiresat"`
+	LastUsedAt   string `bson:"lastusedat"`
+	CreationDate string `bson:"creationdate"`
+	CreatedBy    string `bson:"createdby"`
+	TenantID     string `bson:"tenantid"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+// AuditEvent is one entry of the console's audit trail. Entries are only ever
+// appended: Seq numbers them without gaps, and Hash, an HMAC keyed with the
+// audit key, covers every other field and the previous entry's Hash, so an
+// edited, removed or reordered entry breaks the chain. Parameters is redacted
+// JSON.
+type AuditEvent struct {
+	Seq        int64  `bson:"seq"`
+	Timestamp  string `bson:"timestamp"`
+	Actor      string `bson:"actor"`
+	TenantID   string `bson:"tenantid"`
+	Action     string `bson:"action"`
+	Target     string `bson:"target"`
+	Parameters string `bson:"parameters"`
+	Result     string `bson:"result"`
+	Status     int    `bson:"status"`
+	SourceIP   string `bson:"sourceip"`
+	PrevHash   string `bson:"prevhash"`
+	Hash       string `bson:"hash"`
+}
+
+// AuditFilter narrows an audit query. From and To are timestamps in the
+// stored layout, inclusive; empty fields match everything.
+type AuditFilter struct {
+	TenantID string
+	Actor    string
+	Action   string
+	Target   string
+	Result   string
+	From     string
+	To       string
+}
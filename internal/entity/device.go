@@ -25,6 +25,7 @@ type Device struct {
 	UseTLS           bool       `bson:"usetls"`
 	AllowSelfSigned  bool       `bson:"allowselfsigned"`
 	CertHash         *string    `bson:"certhash"`
+	Version          string     `bson:"version"`
 }
 
 type Explorer struct {
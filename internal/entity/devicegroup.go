@@ -0,0 +1,31 @@
+package entity
+
+// DeviceGroup gathers devices by hand and by rule. A device is a member if it
+// was added to the group, if it matches every one of Rules (when there are
+// any), or if it is a member of a subgroup, a group whose ParentID is ID.
+type DeviceGroup struct {
+	ID           string            `bson:"id"`
+	Name         string            `bson:"name"`
+	Description  string            `bson:"description"`
+	ParentID     string            `bson:"parentid"`
+	Rules        []DeviceGroupRule `bson:"rules"`
+	CreationDate string            `bson:"creationdate"`
+	CreatedBy    string            `bson:"createdby"`
+	TenantID     string            `bson:"tenantid"`
+}
+
+// DeviceGroupRule matches a device field against Value with Operator.
+type DeviceGroupRule struct {
+	Field    string `bson:"field"`
+	Operator string `bson:"operator"`
+	Value    string `bson:"value"`
+}
+
+// DeviceGroupMember records one way a device belongs to a group: added by
+// hand, or, when Dynamic, matched by the group's rules.
+type DeviceGroupMember struct {
+	GroupID  string `bson:"groupid"`
+	GUID     string `bson:"guid"`
+	Dynamic  bool   `bson:"dynamic"`
+	TenantID string `bson:"tenantid"`
+}
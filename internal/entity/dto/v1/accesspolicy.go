@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+// AccessPolicy grants a user or role rights over the devices matching a tag
+// expression: carrying any of Tags, or all of them when Method is "all".
+// Once any policy names a caller, they reach only the devices their policies
+// match, and only with the policies' permissions; role permissions still
+// apply on top.
+type AccessPolicy struct {
+	ID           string     `json:"id" example:"2f1e6c0a-5b7d-4c8e-9a3f-1d2b3c4d5e6f"`
+	Name         string     `json:"name" binding:"required,max=64" example:"site-a-helpdesk"`
+	Subject      string     `json:"subject" binding:"required" example:"role:helpdesk"`
+	Tags         []string   `json:"tags" binding:"required,min=1"`
+	Method       string     `json:"method,omitempty" binding:"omitempty,oneof=any all" example:"any"`
+	Permissions  []string   `json:"permissions,omitempty"`
+	CreationDate *time.Time `json:"creationDate,omitempty" example:"2026-10-19T00:00:00Z"`
+	CreatedBy    string     `json:"createdBy,omitempty" example:"admin"`
+	TenantID     string     `json:"tenantId" example:"abc123"`
+}
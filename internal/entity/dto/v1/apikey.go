@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type (
+	// APIKey describes an API key. The secret is never returned after
+	// creation.
+	APIKey struct {
+		ID           string     `json:"id" example:"3f2a9c1b7d4e8a60"`
+		Name         string     `json:"name" binding:"required,max=64" example:"ci-pipeline"`
+		Role         string     `json:"role" binding:"required,oneof=super-admin admin operator helpdesk read-only" example:"operator"`
+		Routes       []string   `json:"routes,omitempty"`
+		Tags         []string   `json:"tags,omitempty"`
+		ExpiresAt    *time.Time `json:"expiresAt,omitempty" example:"2027-01-01T00:00:00Z"`
+		LastUsedAt   *time.Time `json:"lastUsedAt,omitempty" example:"2026-10-19T00:00:00Z"`
+		CreationDate *time.Time `json:"creationDate,omitempty" example:"2026-10-19T00:00:00Z"`
+		CreatedBy    string     `json:"createdBy,omitempty" example:"admin"`
+		TenantID     string     `json:"tenantId" example:"abc123"`
+	}
+
+	// APIKeyCreated is returned once, when a key is created. Key is the only
+	// copy of the secret.
+	APIKeyCreated struct {
+		APIKey
+		Key string `json:"key" example:"dmt_3f2a9c1b7d4e8a60_Zm9vYmFy"`
+	}
+)
@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+type (
+	// AuditEvent records who did what to which device or resource, and how it
+	// ended. Action is the API route, or an event such as
+	// "redirection.started". Parameters has secrets redacted.
+	AuditEvent struct {
+		Seq        int64                  `json:"seq" example:"42"`
+		Timestamp  time.Time              `json:"timestamp"`
+		Actor      string                 `json:"actor" example:"jdoe"`
+		TenantID   string                 `json:"tenantId" example:"abc123"`
+		Action     string                 `json:"action" example:"POST /api/v1/amt/power/action/{guid}"`
+		Target     string                 `json:"target,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+		Parameters map[string]interface{} `json:"parameters,omitempty"`
+		Result     string                 `json:"result" example:"success"`
+		Status     int                    `json:"status,omitempty" example:"200"`
+		SourceIP   string                 `json:"sourceIp,omitempty" example:"192.168.1.20"`
+		PrevHash   string                 `json:"prevHash"`
+		Hash       string                 `json:"hash"`
+	}
+
+	// AuditFilter narrows an audit query. From and To bound the timestamp,
+	// inclusive; empty fields match everything.
+	AuditFilter struct {
+		TenantID string    `form:"-"`
+		Actor    string    `form:"actor"`
+		Action   string    `form:"action"`
+		Target   string    `form:"target"`
+		Result   string    `form:"result"`
+		From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+		To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	}
+
+	// AuditVerification is the outcome of checking the audit trail's hash
+	// chain. Keep LastHash somewhere safe: a later check reporting a
+	// different hash for the same Count means the tail was rewritten.
+	AuditVerification struct {
+		Valid    bool   `json:"valid"`
+		Count    int64  `json:"count" example:"42"`
+		LastHash string `json:"lastHash,omitempty"`
+		BrokenAt int64  `json:"brokenAt,omitempty" example:"17"`
+		Reason   string `json:"reason,omitempty" example:"hash mismatch"`
+	}
+)
@@ -0,0 +1,55 @@
+package dto
+
+import "encoding/json"
+
+// Conflict policies for a restore, applied to each entity that already
+// exists by name (by GUID for devices).
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictRename    = "rename"
+)
+
+// Restore actions reported per entity.
+const (
+	RestoreCreated     = "created"
+	RestoreOverwritten = "overwritten"
+	RestoreSkipped     = "skipped"
+	RestoreRenamed     = "renamed"
+)
+
+type (
+	// BackupRequest asks for an archive of the tenant's configuration,
+	// encrypted with Passphrase. Devices and their credentials are included
+	// only when asked for.
+	BackupRequest struct {
+		Passphrase     string `json:"passphrase" binding:"required,min=12"`
+		IncludeDevices bool   `json:"includeDevices"`
+	}
+
+	// RestoreRequest restores an archive made by a backup. OnConflict
+	// defaults to skip; DryRun validates and reports what would be done
+	// without writing anything.
+	RestoreRequest struct {
+		Passphrase string          `json:"passphrase" binding:"required"`
+		OnConflict string          `json:"onConflict,omitempty" binding:"omitempty,oneof=skip overwrite rename" example:"rename"`
+		DryRun     bool            `json:"dryRun"`
+		Archive    json.RawMessage `json:"archive" binding:"required"`
+	}
+
+	// RestoreResult lists what a restore did, or would do on a dry run.
+	RestoreResult struct {
+		DryRun bool          `json:"dryRun"`
+		Items  []RestoreItem `json:"items"`
+	}
+
+	// RestoreItem is the outcome for one entity of the archive. RestoredAs is
+	// set when the entity was renamed.
+	RestoreItem struct {
+		Kind       string `json:"kind" example:"profile"`
+		Name       string `json:"name" example:"acm-profile"`
+		Action     string `json:"action" example:"renamed"`
+		RestoredAs string `json:"restoredAs,omitempty" example:"acm-profile-restored"`
+		Note       string `json:"note,omitempty"`
+	}
+)
@@ -35,6 +35,7 @@ type Device struct {
 	UseTLS           bool        `json:"useTLS"`
 	AllowSelfSigned  bool        `json:"allowSelfSigned"`
 	CertHash         string      `json:"certHash"`
+	Version          string      `json:"version,omitempty"`
 }
 
 type DeviceInfo struct {
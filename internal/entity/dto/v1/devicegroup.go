@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+// DeviceGroup gathers devices by hand, through the members API, and by
+// rule: a device matching every one of Rules is a member for as long as it
+// matches. A group with a ParentID is a subgroup, and its members are members
+// of the parent too.
+type DeviceGroup struct {
+	ID           string            `json:"id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Name         string            `json:"name" binding:"required,max=64" example:"site-a-lab"`
+	Description  string            `json:"description,omitempty" binding:"max=256" example:"Lab machines at site A"`
+	ParentID     string            `json:"parentId,omitempty" example:"2f1e6c0a-5b7d-4c8e-9a3f-1d2b3c4d5e6f"`
+	Rules        []DeviceGroupRule `json:"rules,omitempty" binding:"dive"`
+	CreationDate *time.Time        `json:"creationDate,omitempty" example:"2026-10-19T00:00:00Z"`
+	CreatedBy    string            `json:"createdBy,omitempty" example:"admin"`
+	TenantID     string            `json:"tenantId" example:"abc123"`
+}
+
+// DeviceGroupRule matches one device field:
+//
+//   - tags: any, all or none of the comma separated tags of Value
+//   - hostname: eq, or match against a pattern of * and ?
+//   - amtVersion: eq, gte or lte a dotted version such as 16.1
+//   - connectionStatus: eq true or false
+//   - deviceInfo.<field>: eq, or match against a pattern, for fwVersion,
+//     fwBuild, fwSku, currentMode, features, ipAddress, lmsVersion or tlsMode
+//
+// Text comparisons ignore case.
+type DeviceGroupRule struct {
+	Field    string `json:"field" binding:"required" example:"hostname"`
+	Operator string `json:"operator" binding:"required,oneof=eq match any all none gte lte" example:"match"`
+	Value    string `json:"value" example:"lab-*"`
+}
+
+// DeviceSelection names devices for an operation on many of them: those of
+// GUIDs, and the members of Groups, subgroups included. Adding group members
+// and bulk tag updates take one; the AMT operations act on one device each
+// and have no bulk form to target groups with yet.
+type DeviceSelection struct {
+	GUIDs  []string `json:"guids,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+// DeviceGroupMembersAdded tells how many devices an add to a group's members
+// made members by hand; those already added, and unknown GUIDs, don't count.
+type DeviceGroupMembersAdded struct {
+	Added int `json:"added" example:"12"`
+}
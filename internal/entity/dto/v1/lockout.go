@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// Lockout is the failed-login record of a username or client IP. Attempts
+// are refused until BlockedUntil; Locked tells a lockout apart from the delay
+// after a single failure.
+type Lockout struct {
+	Kind          string     `json:"kind" example:"username"`
+	Key           string     `json:"key" example:"jdoe"`
+	Failures      int        `json:"failures" example:"5"`
+	LastFailureAt time.Time  `json:"lastFailureAt"`
+	BlockedUntil  *time.Time `json:"blockedUntil,omitempty"`
+	Locked        bool       `json:"locked"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type (
+	// RedirectionSession describes an active KVM, SOL or IDER session.
+	RedirectionSession struct {
+		ID          string     `json:"id" example:"3f1e9c1a-5a52-4d0b-9a4f-6a1c6c1d2e3f"`
+		GUID        string     `json:"guid" example:"123e4567-e89b-12d3-a456-426614174000"`
+		Mode        string     `json:"mode" example:"kvm"`
+		Headless    bool       `json:"headless"`
+		User        string     `json:"user,omitempty" example:"standalone"`
+		RemoteAddr  string     `json:"remoteAddr,omitempty" example:"192.168.1.20"`
+		StartedAt   time.Time  `json:"startedAt"`
+		LastInputAt time.Time  `json:"lastInputAt"`
+		ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	}
+
+	// RedirectionSessionWarning is sent to the browser as a websocket text
+	// frame before the console ends a session. Binary frames stay reserved for
+	// the redirection protocol.
+	RedirectionSessionWarning struct {
+		Type             string `json:"type" example:"sessionWarning"`
+		Reason           string `json:"reason" example:"idle timeout"`
+		SecondsRemaining int    `json:"secondsRemaining" example:"60"`
+	}
+)
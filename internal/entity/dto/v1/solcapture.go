@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type (
+	// SOLCaptureRequest starts a headless Serial-over-LAN capture.
+	SOLCaptureRequest struct {
+		Duration    int    `json:"duration" binding:"required,min=1,max=3600" example:"120"`
+		PowerAction int    `json:"powerAction,omitempty" binding:"omitempty,min=0" example:"10"`
+		Pattern     string `json:"pattern,omitempty" binding:"max=256" example:"login:"`
+	}
+
+	// SOLCapture is the state and text of a SOL capture. Status is "running",
+	// "completed" or "failed"; Matched reports whether Pattern was seen.
+	SOLCapture struct {
+		ID          string     `json:"id" example:"3f1e9c1a-5a52-4d0b-9a4f-6a1c6c1d2e3f"`
+		GUID        string     `json:"guid" example:"123e4567-e89b-12d3-a456-426614174000"`
+		Status      string     `json:"status" example:"completed"`
+		Duration    int        `json:"duration" example:"120"`
+		PowerAction int        `json:"powerAction,omitempty" example:"10"`
+		Pattern     string     `json:"pattern,omitempty" example:"login:"`
+		Matched     bool       `json:"matched"`
+		StartedAt   time.Time  `json:"startedAt"`
+		EndedAt     *time.Time `json:"endedAt,omitempty"`
+		Truncated   bool       `json:"truncated"`
+		Text        string     `json:"text" example:"Ubuntu 24.04 LTS host ttyS0\r\n\r\nhost login: "`
+		Error       string     `json:"error,omitempty"`
+	}
+)
@@ -0,0 +1,34 @@
+package dto
+
+// Tag is a device tag with the number of devices, not in the trash, that
+// carry it.
+type Tag struct {
+	Name    string `json:"name" example:"site-a"`
+	Devices int    `json:"devices" example:"42"`
+}
+
+// TagRename renames a tag on every device carrying it. Name must not be in
+// use already; to fold a tag into another, merge them.
+type TagRename struct {
+	Name string `json:"name" binding:"required,max=64" example:"site-b"`
+}
+
+// TagMerge replaces each of Tags with Into on every device carrying it.
+type TagMerge struct {
+	Tags []string `json:"tags" binding:"required,min=1" example:"site-a,site-a-old"`
+	Into string   `json:"into" binding:"required,max=64" example:"site-a"`
+}
+
+// TagUpdate adds the tags of Add to the selected devices and removes those
+// of Remove.
+type TagUpdate struct {
+	Devices DeviceSelection `json:"devices"`
+	Add     []string        `json:"add,omitempty" example:"lab"`
+	Remove  []string        `json:"remove,omitempty" example:"staging"`
+}
+
+// TagsUpdated tells how many devices a tag operation changed; devices that
+// already had the resulting tags don't count.
+type TagsUpdated struct {
+	Devices int `json:"devices" example:"12"`
+}
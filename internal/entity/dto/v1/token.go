@@ -0,0 +1,18 @@
+package dto
+
+type (
+	// TokenSession is a login session: every token issued for one login, and
+	// for the refreshes that followed it. RefreshToken is the session's
+	// current refresh token, empty when refresh tokens are turned off.
+	TokenSession struct {
+		ID           string `json:"id"`
+		Username     string `json:"username"`
+		TenantID     string `json:"tenantId"`
+		RefreshToken string `json:"refreshToken,omitempty"`
+	}
+
+	// TokenRefresh exchanges a refresh token for a new token pair.
+	TokenRefresh struct {
+		RefreshToken string `json:"refreshToken" binding:"required" example:"3q2-7wEAAAB..."`
+	}
+)
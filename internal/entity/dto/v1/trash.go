@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+// TrashItem is a deleted device or configuration, kept until it is restored
+// or purged. ID addresses it: a device's GUID or a configuration's name.
+// PurgeAt is when it is purged automatically, if ever.
+type TrashItem struct {
+	Kind      string     `json:"kind" example:"devices"`
+	ID        string     `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name      string     `json:"name,omitempty" example:"host1.example.com"`
+	DeletedAt time.Time  `json:"deletedAt" example:"2026-10-19T00:00:00Z"`
+	PurgeAt   *time.Time `json:"purgeAt,omitempty" example:"2026-11-18T00:00:00Z"`
+	TenantID  string     `json:"tenantId" example:"abc123"`
+}
+
+type TrashCountResponse struct {
+	Count int         `json:"totalCount"`
+	Data  []TrashItem `json:"data"`
+}
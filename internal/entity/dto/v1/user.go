@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// usernameRegex allows e-mail style names as well as plain identifiers.
+var usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9._@-]{1,64}$`)
+
+type (
+	// User is a local console account. Password is write-only: it is accepted
+	// when creating a user or resetting their password and never returned.
+	User struct {
+		Username           string     `json:"username" binding:"required,username" example:"jdoe"`
+		Role               string     `json:"role" binding:"required,oneof=super-admin admin operator helpdesk read-only" example:"operator"`
+		Password           string     `json:"password,omitempty" example:"Correct-Horse-42"`
+		MustChangePassword bool       `json:"mustChangePassword"`
+		PasswordChangedAt  *time.Time `json:"passwordChangedAt,omitempty" example:"2024-01-01T00:00:00Z"`
+		CreationDate       *time.Time `json:"creationDate,omitempty" example:"2024-01-01T00:00:00Z"`
+		CreatedBy          string     `json:"createdBy,omitempty" example:"admin"`
+		TenantID           string     `json:"tenantId" example:"abc123"`
+	}
+
+	// PasswordChange lets a user set a new password, including the change
+	// forced on first login.
+	PasswordChange struct {
+		Username    string `json:"username" binding:"required" example:"jdoe"`
+		Password    string `json:"password" binding:"required" example:"Initial-Password-1"`
+		NewPassword string `json:"newPassword" binding:"required" example:"Correct-Horse-42"`
+	}
+)
+
+// ValidateUsername validates a local account name.
+func ValidateUsername(fl validator.FieldLevel) bool {
+	return usernameRegex.MatchString(fl.Field().String())
+}
@@ -25,7 +25,7 @@ type Profile struct {
 	UEFIWiFiSyncEnabled        bool    `bson:"uefiwifisyncenabled"`
 
 	// columns to populate from join query — never persisted (bson:"-").
-	Version                string `bson:"-"`
+	Version                string `bson:"version"`
 	AuthenticationProtocol *int   `bson:"-"`
 	ServerName             string `bson:"-"`
 	Domain                 string `bson:"-"`
@@ -0,0 +1,7 @@
+package entity
+
+// Tag is a device tag with the number of devices carrying it.
+type Tag struct {
+	Name    string `bson:"_id"`
+	Devices int    `bson:"devices"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+// RevokedToken is an entry in the token revocation list. ID is the jti of an
+// access or redirection token, or the ID of a login session, which every
+// token issued in it carries as sid. The entry can be dropped at ExpiresAt,
+// when the tokens it names have expired anyway.
+type RevokedToken struct {
+	ID        string `bson:"id"`
+	Subject   string `bson:"subject"`
+	RevokedAt string `bson:"revokedat"`
+	ExpiresAt string `bson:"expiresat"`
+}
+
+// RefreshToken is one refresh token of a login session. TokenHash is the
+// SHA-256 of the token; the token itself is only given to the client. Each
+// refresh replaces the session's token and marks the old one Used, so
+// presenting it again shows it was stolen.
+type RefreshToken struct {
+	TokenHash    string `bson:"tokenhash"`
+	SessionID    string `bson:"sessionid"`
+	Username     string `bson:"username"`
+	Used         bool   `bson:"used"`
+	ExpiresAt    string `bson:"expiresat"`
+	CreationDate string `bson:"creationdate"`
+	TenantID     string `bson:"tenantid"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+// Kinds of soft-deleted entity in the trash.
+const (
+	TrashDevices          = "devices"
+	TrashProfiles         = "profiles"
+	TrashCIRAConfigs      = "ciraconfigs"
+	TrashWirelessConfigs  = "wirelessconfigs"
+	TrashIEEE8021xConfigs = "ieee8021xconfigs"
+	TrashDomains          = "domains"
+)
+
+// TrashKinds lists the kinds of entity deleted softly, in the order they are
+// purged: entities before those they reference.
+var TrashKinds = []string{TrashDevices, TrashProfiles, TrashDomains, TrashWirelessConfigs, TrashCIRAConfigs, TrashIEEE8021xConfigs}
+
+// TrashItem is a soft-deleted entity. ID is its GUID or name; Name is its
+// hostname, domain suffix or, otherwise, name again. DeletedAt is RFC 3339.
+type TrashItem struct {
+	Kind      string
+	ID        string
+	Name      string
+	TenantID  string
+	DeletedAt string
+}
@@ -0,0 +1,14 @@
+package entity
+
+// User is a local console account. PasswordHash is a bcrypt hash; the
+// password itself is never stored.
+type User struct {
+	Username           string `bson:"username"`
+	PasswordHash       string `bson:"passwordhash"`
+	Role               string `bson:"role"`
+	MustChangePassword bool   `bson:"mustchangepassword"`
+	PasswordChangedAt  string `bson:"passwordchangedat"`
+	CreationDate       string `bson:"creationdate"`
+	CreatedBy          string `bson:"createdby"`
+	TenantID           string `bson:"tenantid"`
+}
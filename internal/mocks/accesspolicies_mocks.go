@@ -0,0 +1,277 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/accesspolicies/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source ./internal/usecase/accesspolicies/interfaces.go -package mocks -mock_names Repository=MockAccessPoliciesRepository,Feature=MockAccessPoliciesFeature
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	devicescope "github.com/device-management-toolkit/console/internal/devicescope"
+	entity "github.com/device-management-toolkit/console/internal/entity"
+	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	rbac "github.com/device-management-toolkit/console/internal/rbac"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAccessPoliciesRepository is a mock of Repository interface.
+type MockAccessPoliciesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessPoliciesRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAccessPoliciesRepositoryMockRecorder is the mock recorder for MockAccessPoliciesRepository.
+type MockAccessPoliciesRepositoryMockRecorder struct {
+	mock *MockAccessPoliciesRepository
+}
+
+// NewMockAccessPoliciesRepository creates a new mock instance.
+func NewMockAccessPoliciesRepository(ctrl *gomock.Controller) *MockAccessPoliciesRepository {
+	mock := &MockAccessPoliciesRepository{ctrl: ctrl}
+	mock.recorder = &MockAccessPoliciesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessPoliciesRepository) EXPECT() *MockAccessPoliciesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockAccessPoliciesRepository) Delete(ctx context.Context, id, tenantID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, tenantID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAccessPoliciesRepositoryMockRecorder) Delete(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccessPoliciesRepository)(nil).Delete), ctx, id, tenantID)
+}
+
+// Get mocks base method.
+func (m *MockAccessPoliciesRepository) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.AccessPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, top, skip, tenantID)
+	ret0, _ := ret[0].([]entity.AccessPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAccessPoliciesRepositoryMockRecorder) Get(ctx, top, skip, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAccessPoliciesRepository)(nil).Get), ctx, top, skip, tenantID)
+}
+
+// GetByID mocks base method.
+func (m *MockAccessPoliciesRepository) GetByID(ctx context.Context, id, tenantID string) (*entity.AccessPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id, tenantID)
+	ret0, _ := ret[0].(*entity.AccessPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAccessPoliciesRepositoryMockRecorder) GetByID(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAccessPoliciesRepository)(nil).GetByID), ctx, id, tenantID)
+}
+
+// GetBySubjects mocks base method.
+func (m *MockAccessPoliciesRepository) GetBySubjects(ctx context.Context, subjects []string, tenantID string) ([]entity.AccessPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySubjects", ctx, subjects, tenantID)
+	ret0, _ := ret[0].([]entity.AccessPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySubjects indicates an expected call of GetBySubjects.
+func (mr *MockAccessPoliciesRepositoryMockRecorder) GetBySubjects(ctx, subjects, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySubjects", reflect.TypeOf((*MockAccessPoliciesRepository)(nil).GetBySubjects), ctx, subjects, tenantID)
+}
+
+// GetCount mocks base method.
+func (m *MockAccessPoliciesRepository) GetCount(ctx context.Context, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCount", ctx, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCount indicates an expected call of GetCount.
+func (mr *MockAccessPoliciesRepositoryMockRecorder) GetCount(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCount", reflect.TypeOf((*MockAccessPoliciesRepository)(nil).GetCount), ctx, tenantID)
+}
+
+// Insert mocks base method.
+func (m *MockAccessPoliciesRepository) Insert(ctx context.Context, p *entity.AccessPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockAccessPoliciesRepositoryMockRecorder) Insert(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockAccessPoliciesRepository)(nil).Insert), ctx, p)
+}
+
+// Update mocks base method.
+func (m *MockAccessPoliciesRepository) Update(ctx context.Context, p *entity.AccessPolicy) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, p)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAccessPoliciesRepositoryMockRecorder) Update(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAccessPoliciesRepository)(nil).Update), ctx, p)
+}
+
+// MockAccessPoliciesFeature is a mock of Feature interface.
+type MockAccessPoliciesFeature struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessPoliciesFeatureMockRecorder
+	isgomock struct{}
+}
+
+// MockAccessPoliciesFeatureMockRecorder is the mock recorder for MockAccessPoliciesFeature.
+type MockAccessPoliciesFeatureMockRecorder struct {
+	mock *MockAccessPoliciesFeature
+}
+
+// NewMockAccessPoliciesFeature creates a new mock instance.
+func NewMockAccessPoliciesFeature(ctrl *gomock.Controller) *MockAccessPoliciesFeature {
+	mock := &MockAccessPoliciesFeature{ctrl: ctrl}
+	mock.recorder = &MockAccessPoliciesFeatureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessPoliciesFeature) EXPECT() *MockAccessPoliciesFeatureMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockAccessPoliciesFeature) Delete(ctx context.Context, id, tenantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, tenantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAccessPoliciesFeatureMockRecorder) Delete(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccessPoliciesFeature)(nil).Delete), ctx, id, tenantID)
+}
+
+// Get mocks base method.
+func (m *MockAccessPoliciesFeature) Get(ctx context.Context, top, skip int, tenantID string) ([]dto.AccessPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, top, skip, tenantID)
+	ret0, _ := ret[0].([]dto.AccessPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAccessPoliciesFeatureMockRecorder) Get(ctx, top, skip, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAccessPoliciesFeature)(nil).Get), ctx, top, skip, tenantID)
+}
+
+// GetByID mocks base method.
+func (m *MockAccessPoliciesFeature) GetByID(ctx context.Context, id, tenantID string) (*dto.AccessPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id, tenantID)
+	ret0, _ := ret[0].(*dto.AccessPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAccessPoliciesFeatureMockRecorder) GetByID(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAccessPoliciesFeature)(nil).GetByID), ctx, id, tenantID)
+}
+
+// GetCount mocks base method.
+func (m *MockAccessPoliciesFeature) GetCount(ctx context.Context, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCount", ctx, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCount indicates an expected call of GetCount.
+func (mr *MockAccessPoliciesFeatureMockRecorder) GetCount(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCount", reflect.TypeOf((*MockAccessPoliciesFeature)(nil).GetCount), ctx, tenantID)
+}
+
+// Insert mocks base method.
+func (m *MockAccessPoliciesFeature) Insert(ctx context.Context, p *dto.AccessPolicy) (*dto.AccessPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, p)
+	ret0, _ := ret[0].(*dto.AccessPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockAccessPoliciesFeatureMockRecorder) Insert(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockAccessPoliciesFeature)(nil).Insert), ctx, p)
+}
+
+// Scope mocks base method.
+func (m *MockAccessPoliciesFeature) Scope(ctx context.Context, username string, roles []rbac.Role, permission rbac.Permission, tenantID string) (*devicescope.Scope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scope", ctx, username, roles, permission, tenantID)
+	ret0, _ := ret[0].(*devicescope.Scope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Scope indicates an expected call of Scope.
+func (mr *MockAccessPoliciesFeatureMockRecorder) Scope(ctx, username, roles, permission, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scope", reflect.TypeOf((*MockAccessPoliciesFeature)(nil).Scope), ctx, username, roles, permission, tenantID)
+}
+
+// Update mocks base method.
+func (m *MockAccessPoliciesFeature) Update(ctx context.Context, p *dto.AccessPolicy) (*dto.AccessPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, p)
+	ret0, _ := ret[0].(*dto.AccessPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAccessPoliciesFeatureMockRecorder) Update(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAccessPoliciesFeature)(nil).Update), ctx, p)
+}
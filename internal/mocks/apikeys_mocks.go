@@ -0,0 +1,261 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/apikeys/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source ./internal/usecase/apikeys/interfaces.go -package mocks -mock_names Repository=MockAPIKeysRepository,Feature=MockAPIKeysFeature
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/device-management-toolkit/console/internal/entity"
+	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAPIKeysRepository is a mock of Repository interface.
+type MockAPIKeysRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeysRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeysRepositoryMockRecorder is the mock recorder for MockAPIKeysRepository.
+type MockAPIKeysRepositoryMockRecorder struct {
+	mock *MockAPIKeysRepository
+}
+
+// NewMockAPIKeysRepository creates a new mock instance.
+func NewMockAPIKeysRepository(ctrl *gomock.Controller) *MockAPIKeysRepository {
+	mock := &MockAPIKeysRepository{ctrl: ctrl}
+	mock.recorder = &MockAPIKeysRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeysRepository) EXPECT() *MockAPIKeysRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockAPIKeysRepository) Delete(ctx context.Context, id, tenantID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, tenantID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAPIKeysRepositoryMockRecorder) Delete(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAPIKeysRepository)(nil).Delete), ctx, id, tenantID)
+}
+
+// Get mocks base method.
+func (m *MockAPIKeysRepository) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, top, skip, tenantID)
+	ret0, _ := ret[0].([]entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAPIKeysRepositoryMockRecorder) Get(ctx, top, skip, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAPIKeysRepository)(nil).Get), ctx, top, skip, tenantID)
+}
+
+// GetByID mocks base method.
+func (m *MockAPIKeysRepository) GetByID(ctx context.Context, id string) (*entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAPIKeysRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAPIKeysRepository)(nil).GetByID), ctx, id)
+}
+
+// GetCount mocks base method.
+func (m *MockAPIKeysRepository) GetCount(ctx context.Context, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCount", ctx, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCount indicates an expected call of GetCount.
+func (mr *MockAPIKeysRepositoryMockRecorder) GetCount(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCount", reflect.TypeOf((*MockAPIKeysRepository)(nil).GetCount), ctx, tenantID)
+}
+
+// Insert mocks base method.
+func (m *MockAPIKeysRepository) Insert(ctx context.Context, k *entity.APIKey) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, k)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockAPIKeysRepositoryMockRecorder) Insert(ctx, k any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockAPIKeysRepository)(nil).Insert), ctx, k)
+}
+
+// UpdateLastUsed mocks base method.
+func (m *MockAPIKeysRepository) UpdateLastUsed(ctx context.Context, id, lastUsedAt string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastUsed", ctx, id, lastUsedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLastUsed indicates an expected call of UpdateLastUsed.
+func (mr *MockAPIKeysRepositoryMockRecorder) UpdateLastUsed(ctx, id, lastUsedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastUsed", reflect.TypeOf((*MockAPIKeysRepository)(nil).UpdateLastUsed), ctx, id, lastUsedAt)
+}
+
+// MockAPIKeysFeature is a mock of Feature interface.
+type MockAPIKeysFeature struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeysFeatureMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeysFeatureMockRecorder is the mock recorder for MockAPIKeysFeature.
+type MockAPIKeysFeatureMockRecorder struct {
+	mock *MockAPIKeysFeature
+}
+
+// NewMockAPIKeysFeature creates a new mock instance.
+func NewMockAPIKeysFeature(ctrl *gomock.Controller) *MockAPIKeysFeature {
+	mock := &MockAPIKeysFeature{ctrl: ctrl}
+	mock.recorder = &MockAPIKeysFeatureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeysFeature) EXPECT() *MockAPIKeysFeatureMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAPIKeysFeature) Authenticate(ctx context.Context, key string) (*dto.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, key)
+	ret0, _ := ret[0].(*dto.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAPIKeysFeatureMockRecorder) Authenticate(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAPIKeysFeature)(nil).Authenticate), ctx, key)
+}
+
+// Delete mocks base method.
+func (m *MockAPIKeysFeature) Delete(ctx context.Context, id, tenantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, tenantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAPIKeysFeatureMockRecorder) Delete(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAPIKeysFeature)(nil).Delete), ctx, id, tenantID)
+}
+
+// DeviceInScope mocks base method.
+func (m *MockAPIKeysFeature) DeviceInScope(ctx context.Context, k *dto.APIKey, guid string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeviceInScope", ctx, k, guid)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeviceInScope indicates an expected call of DeviceInScope.
+func (mr *MockAPIKeysFeatureMockRecorder) DeviceInScope(ctx, k, guid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeviceInScope", reflect.TypeOf((*MockAPIKeysFeature)(nil).DeviceInScope), ctx, k, guid)
+}
+
+// Get mocks base method.
+func (m *MockAPIKeysFeature) Get(ctx context.Context, top, skip int, tenantID string) ([]dto.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, top, skip, tenantID)
+	ret0, _ := ret[0].([]dto.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAPIKeysFeatureMockRecorder) Get(ctx, top, skip, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAPIKeysFeature)(nil).Get), ctx, top, skip, tenantID)
+}
+
+// GetByID mocks base method.
+func (m *MockAPIKeysFeature) GetByID(ctx context.Context, id, tenantID string) (*dto.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id, tenantID)
+	ret0, _ := ret[0].(*dto.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAPIKeysFeatureMockRecorder) GetByID(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAPIKeysFeature)(nil).GetByID), ctx, id, tenantID)
+}
+
+// GetCount mocks base method.
+func (m *MockAPIKeysFeature) GetCount(ctx context.Context, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCount", ctx, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCount indicates an expected call of GetCount.
+func (mr *MockAPIKeysFeatureMockRecorder) GetCount(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCount", reflect.TypeOf((*MockAPIKeysFeature)(nil).GetCount), ctx, tenantID)
+}
+
+// Insert mocks base method.
+func (m *MockAPIKeysFeature) Insert(ctx context.Context, k *dto.APIKey) (*dto.APIKeyCreated, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, k)
+	ret0, _ := ret[0].(*dto.APIKeyCreated)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockAPIKeysFeatureMockRecorder) Insert(ctx, k any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockAPIKeysFeature)(nil).Insert), ctx, k)
+}
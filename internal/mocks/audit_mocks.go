@@ -0,0 +1,200 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/audit/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source ./internal/usecase/audit/interfaces.go -package mocks -mock_names Repository=MockAuditRepository,Feature=MockAuditFeature
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/device-management-toolkit/console/internal/entity"
+	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuditRepository is a mock of Repository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAuditRepository) Get(ctx context.Context, top, skip int, filter entity.AuditFilter) ([]entity.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, top, skip, filter)
+	ret0, _ := ret[0].([]entity.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAuditRepositoryMockRecorder) Get(ctx, top, skip, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAuditRepository)(nil).Get), ctx, top, skip, filter)
+}
+
+// GetAfter mocks base method.
+func (m *MockAuditRepository) GetAfter(ctx context.Context, seq int64, limit int) ([]entity.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAfter", ctx, seq, limit)
+	ret0, _ := ret[0].([]entity.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAfter indicates an expected call of GetAfter.
+func (mr *MockAuditRepositoryMockRecorder) GetAfter(ctx, seq, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAfter", reflect.TypeOf((*MockAuditRepository)(nil).GetAfter), ctx, seq, limit)
+}
+
+// GetCount mocks base method.
+func (m *MockAuditRepository) GetCount(ctx context.Context, filter entity.AuditFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCount", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCount indicates an expected call of GetCount.
+func (mr *MockAuditRepositoryMockRecorder) GetCount(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCount", reflect.TypeOf((*MockAuditRepository)(nil).GetCount), ctx, filter)
+}
+
+// GetLast mocks base method.
+func (m *MockAuditRepository) GetLast(ctx context.Context) (*entity.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLast", ctx)
+	ret0, _ := ret[0].(*entity.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLast indicates an expected call of GetLast.
+func (mr *MockAuditRepositoryMockRecorder) GetLast(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLast", reflect.TypeOf((*MockAuditRepository)(nil).GetLast), ctx)
+}
+
+// Insert mocks base method.
+func (m *MockAuditRepository) Insert(ctx context.Context, e *entity.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockAuditRepositoryMockRecorder) Insert(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockAuditRepository)(nil).Insert), ctx, e)
+}
+
+// MockAuditFeature is a mock of Feature interface.
+type MockAuditFeature struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditFeatureMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditFeatureMockRecorder is the mock recorder for MockAuditFeature.
+type MockAuditFeatureMockRecorder struct {
+	mock *MockAuditFeature
+}
+
+// NewMockAuditFeature creates a new mock instance.
+func NewMockAuditFeature(ctrl *gomock.Controller) *MockAuditFeature {
+	mock := &MockAuditFeature{ctrl: ctrl}
+	mock.recorder = &MockAuditFeatureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditFeature) EXPECT() *MockAuditFeatureMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAuditFeature) Get(ctx context.Context, top, skip int, filter dto.AuditFilter) ([]dto.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, top, skip, filter)
+	ret0, _ := ret[0].([]dto.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAuditFeatureMockRecorder) Get(ctx, top, skip, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAuditFeature)(nil).Get), ctx, top, skip, filter)
+}
+
+// GetCount mocks base method.
+func (m *MockAuditFeature) GetCount(ctx context.Context, filter dto.AuditFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCount", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCount indicates an expected call of GetCount.
+func (mr *MockAuditFeatureMockRecorder) GetCount(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCount", reflect.TypeOf((*MockAuditFeature)(nil).GetCount), ctx, filter)
+}
+
+// Record mocks base method.
+func (m *MockAuditFeature) Record(ctx context.Context, e *dto.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockAuditFeatureMockRecorder) Record(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockAuditFeature)(nil).Record), ctx, e)
+}
+
+// Verify mocks base method.
+func (m *MockAuditFeature) Verify(ctx context.Context) (*dto.AuditVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx)
+	ret0, _ := ret[0].(*dto.AuditVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockAuditFeatureMockRecorder) Verify(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockAuditFeature)(nil).Verify), ctx)
+}
@@ -0,0 +1,150 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/backup/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source ./internal/usecase/backup/interfaces.go -package mocks -mock_names Transactor=MockTransactor,DomainCerts=MockDomainCerts,Feature=MockBackupFeature
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/device-management-toolkit/console/internal/entity"
+	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// InTx mocks base method.
+func (m *MockTransactor) InTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTx indicates an expected call of InTx.
+func (mr *MockTransactorMockRecorder) InTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockTransactor)(nil).InTx), ctx, fn)
+}
+
+// MockDomainCerts is a mock of DomainCerts interface.
+type MockDomainCerts struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainCertsMockRecorder
+	isgomock struct{}
+}
+
+// MockDomainCertsMockRecorder is the mock recorder for MockDomainCerts.
+type MockDomainCertsMockRecorder struct {
+	mock *MockDomainCerts
+}
+
+// NewMockDomainCerts creates a new mock instance.
+func NewMockDomainCerts(ctrl *gomock.Controller) *MockDomainCerts {
+	mock := &MockDomainCerts{ctrl: ctrl}
+	mock.recorder = &MockDomainCertsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainCerts) EXPECT() *MockDomainCertsMockRecorder {
+	return m.recorder
+}
+
+// GetByNameWithCert mocks base method.
+func (m *MockDomainCerts) GetByNameWithCert(ctx context.Context, name, tenantID string) (*entity.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByNameWithCert", ctx, name, tenantID)
+	ret0, _ := ret[0].(*entity.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByNameWithCert indicates an expected call of GetByNameWithCert.
+func (mr *MockDomainCertsMockRecorder) GetByNameWithCert(ctx, name, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByNameWithCert", reflect.TypeOf((*MockDomainCerts)(nil).GetByNameWithCert), ctx, name, tenantID)
+}
+
+// MockBackupFeature is a mock of Feature interface.
+type MockBackupFeature struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupFeatureMockRecorder
+	isgomock struct{}
+}
+
+// MockBackupFeatureMockRecorder is the mock recorder for MockBackupFeature.
+type MockBackupFeatureMockRecorder struct {
+	mock *MockBackupFeature
+}
+
+// NewMockBackupFeature creates a new mock instance.
+func NewMockBackupFeature(ctrl *gomock.Controller) *MockBackupFeature {
+	mock := &MockBackupFeature{ctrl: ctrl}
+	mock.recorder = &MockBackupFeatureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackupFeature) EXPECT() *MockBackupFeatureMockRecorder {
+	return m.recorder
+}
+
+// Backup mocks base method.
+func (m *MockBackupFeature) Backup(ctx context.Context, req dto.BackupRequest, tenantID string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backup", ctx, req, tenantID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Backup indicates an expected call of Backup.
+func (mr *MockBackupFeatureMockRecorder) Backup(ctx, req, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backup", reflect.TypeOf((*MockBackupFeature)(nil).Backup), ctx, req, tenantID)
+}
+
+// Restore mocks base method.
+func (m *MockBackupFeature) Restore(ctx context.Context, req dto.RestoreRequest, tenantID string) (*dto.RestoreResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, req, tenantID)
+	ret0, _ := ret[0].(*dto.RestoreResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockBackupFeatureMockRecorder) Restore(ctx, req, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockBackupFeature)(nil).Restore), ctx, req, tenantID)
+}
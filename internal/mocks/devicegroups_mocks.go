@@ -0,0 +1,451 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/devicegroups/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source ./internal/usecase/devicegroups/interfaces.go -package mocks -mock_names Repository=MockDeviceGroupsRepository,Feature=MockDeviceGroupsFeature
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/device-management-toolkit/console/internal/entity"
+	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeviceGroupsRepository is a mock of Repository interface.
+type MockDeviceGroupsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceGroupsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceGroupsRepositoryMockRecorder is the mock recorder for MockDeviceGroupsRepository.
+type MockDeviceGroupsRepositoryMockRecorder struct {
+	mock *MockDeviceGroupsRepository
+}
+
+// NewMockDeviceGroupsRepository creates a new mock instance.
+func NewMockDeviceGroupsRepository(ctrl *gomock.Controller) *MockDeviceGroupsRepository {
+	mock := &MockDeviceGroupsRepository{ctrl: ctrl}
+	mock.recorder = &MockDeviceGroupsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceGroupsRepository) EXPECT() *MockDeviceGroupsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddMembers mocks base method.
+func (m *MockDeviceGroupsRepository) AddMembers(ctx context.Context, id string, guids []string, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMembers", ctx, id, guids, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMembers indicates an expected call of AddMembers.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) AddMembers(ctx, id, guids, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMembers", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).AddMembers), ctx, id, guids, tenantID)
+}
+
+// Delete mocks base method.
+func (m *MockDeviceGroupsRepository) Delete(ctx context.Context, id, tenantID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, tenantID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) Delete(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).Delete), ctx, id, tenantID)
+}
+
+// Get mocks base method.
+func (m *MockDeviceGroupsRepository) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.DeviceGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, top, skip, tenantID)
+	ret0, _ := ret[0].([]entity.DeviceGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) Get(ctx, top, skip, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).Get), ctx, top, skip, tenantID)
+}
+
+// GetByID mocks base method.
+func (m *MockDeviceGroupsRepository) GetByID(ctx context.Context, id, tenantID string) (*entity.DeviceGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id, tenantID)
+	ret0, _ := ret[0].(*entity.DeviceGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) GetByID(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).GetByID), ctx, id, tenantID)
+}
+
+// GetCount mocks base method.
+func (m *MockDeviceGroupsRepository) GetCount(ctx context.Context, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCount", ctx, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCount indicates an expected call of GetCount.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) GetCount(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCount", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).GetCount), ctx, tenantID)
+}
+
+// GetMemberCount mocks base method.
+func (m *MockDeviceGroupsRepository) GetMemberCount(ctx context.Context, ids []string, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberCount", ctx, ids, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberCount indicates an expected call of GetMemberCount.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) GetMemberCount(ctx, ids, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberCount", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).GetMemberCount), ctx, ids, tenantID)
+}
+
+// GetMemberGUIDs mocks base method.
+func (m *MockDeviceGroupsRepository) GetMemberGUIDs(ctx context.Context, ids []string, tenantID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberGUIDs", ctx, ids, tenantID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberGUIDs indicates an expected call of GetMemberGUIDs.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) GetMemberGUIDs(ctx, ids, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberGUIDs", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).GetMemberGUIDs), ctx, ids, tenantID)
+}
+
+// GetMembers mocks base method.
+func (m *MockDeviceGroupsRepository) GetMembers(ctx context.Context, ids []string, top, skip int, tenantID string) ([]entity.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", ctx, ids, top, skip, tenantID)
+	ret0, _ := ret[0].([]entity.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) GetMembers(ctx, ids, top, skip, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).GetMembers), ctx, ids, top, skip, tenantID)
+}
+
+// GetMemberships mocks base method.
+func (m *MockDeviceGroupsRepository) GetMemberships(ctx context.Context, id, tenantID string) ([]entity.DeviceGroupMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberships", ctx, id, tenantID)
+	ret0, _ := ret[0].([]entity.DeviceGroupMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberships indicates an expected call of GetMemberships.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) GetMemberships(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberships", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).GetMemberships), ctx, id, tenantID)
+}
+
+// Insert mocks base method.
+func (m *MockDeviceGroupsRepository) Insert(ctx context.Context, g *entity.DeviceGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, g)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) Insert(ctx, g any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).Insert), ctx, g)
+}
+
+// RemoveMembers mocks base method.
+func (m *MockDeviceGroupsRepository) RemoveMembers(ctx context.Context, id string, guids []string, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMembers", ctx, id, guids, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMembers indicates an expected call of RemoveMembers.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) RemoveMembers(ctx, id, guids, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMembers", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).RemoveMembers), ctx, id, guids, tenantID)
+}
+
+// SetDeviceMatches mocks base method.
+func (m *MockDeviceGroupsRepository) SetDeviceMatches(ctx context.Context, guid string, ids []string, tenantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeviceMatches", ctx, guid, ids, tenantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDeviceMatches indicates an expected call of SetDeviceMatches.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) SetDeviceMatches(ctx, guid, ids, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeviceMatches", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).SetDeviceMatches), ctx, guid, ids, tenantID)
+}
+
+// SetMatches mocks base method.
+func (m *MockDeviceGroupsRepository) SetMatches(ctx context.Context, id string, guids []string, tenantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMatches", ctx, id, guids, tenantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMatches indicates an expected call of SetMatches.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) SetMatches(ctx, id, guids, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMatches", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).SetMatches), ctx, id, guids, tenantID)
+}
+
+// Update mocks base method.
+func (m *MockDeviceGroupsRepository) Update(ctx context.Context, g *entity.DeviceGroup) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, g)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeviceGroupsRepositoryMockRecorder) Update(ctx, g any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeviceGroupsRepository)(nil).Update), ctx, g)
+}
+
+// MockDeviceGroupsFeature is a mock of Feature interface.
+type MockDeviceGroupsFeature struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceGroupsFeatureMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceGroupsFeatureMockRecorder is the mock recorder for MockDeviceGroupsFeature.
+type MockDeviceGroupsFeatureMockRecorder struct {
+	mock *MockDeviceGroupsFeature
+}
+
+// NewMockDeviceGroupsFeature creates a new mock instance.
+func NewMockDeviceGroupsFeature(ctrl *gomock.Controller) *MockDeviceGroupsFeature {
+	mock := &MockDeviceGroupsFeature{ctrl: ctrl}
+	mock.recorder = &MockDeviceGroupsFeatureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceGroupsFeature) EXPECT() *MockDeviceGroupsFeatureMockRecorder {
+	return m.recorder
+}
+
+// AddMembers mocks base method.
+func (m *MockDeviceGroupsFeature) AddMembers(ctx context.Context, id string, selection dto.DeviceSelection, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMembers", ctx, id, selection, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMembers indicates an expected call of AddMembers.
+func (mr *MockDeviceGroupsFeatureMockRecorder) AddMembers(ctx, id, selection, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMembers", reflect.TypeOf((*MockDeviceGroupsFeature)(nil).AddMembers), ctx, id, selection, tenantID)
+}
+
+// Delete mocks base method.
+func (m *MockDeviceGroupsFeature) Delete(ctx context.Context, id, tenantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, tenantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDeviceGroupsFeatureMockRecorder) Delete(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeviceGroupsFeature)(nil).Delete), ctx, id, tenantID)
+}
+
+// Get mocks base method.
+func (m *MockDeviceGroupsFeature) Get(ctx context.Context, top, skip int, tenantID string) ([]dto.DeviceGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, top, skip, tenantID)
+	ret0, _ := ret[0].([]dto.DeviceGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDeviceGroupsFeatureMockRecorder) Get(ctx, top, skip, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeviceGroupsFeature)(nil).Get), ctx, top, skip, tenantID)
+}
+
+// GetByID mocks base method.
+func (m *MockDeviceGroupsFeature) GetByID(ctx context.Context, id, tenantID string) (*dto.DeviceGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id, tenantID)
+	ret0, _ := ret[0].(*dto.DeviceGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDeviceGroupsFeatureMockRecorder) GetByID(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDeviceGroupsFeature)(nil).GetByID), ctx, id, tenantID)
+}
+
+// GetCount mocks base method.
+func (m *MockDeviceGroupsFeature) GetCount(ctx context.Context, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCount", ctx, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCount indicates an expected call of GetCount.
+func (mr *MockDeviceGroupsFeatureMockRecorder) GetCount(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCount", reflect.TypeOf((*MockDeviceGroupsFeature)(nil).GetCount), ctx, tenantID)
+}
+
+// GetMemberCount mocks base method.
+func (m *MockDeviceGroupsFeature) GetMemberCount(ctx context.Context, id, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberCount", ctx, id, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberCount indicates an expected call of GetMemberCount.
+func (mr *MockDeviceGroupsFeatureMockRecorder) GetMemberCount(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberCount", reflect.TypeOf((*MockDeviceGroupsFeature)(nil).GetMemberCount), ctx, id, tenantID)
+}
+
+// GetMembers mocks base method.
+func (m *MockDeviceGroupsFeature) GetMembers(ctx context.Context, id string, top, skip int, tenantID string) ([]dto.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", ctx, id, top, skip, tenantID)
+	ret0, _ := ret[0].([]dto.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockDeviceGroupsFeatureMockRecorder) GetMembers(ctx, id, top, skip, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockDeviceGroupsFeature)(nil).GetMembers), ctx, id, top, skip, tenantID)
+}
+
+// Insert mocks base method.
+func (m *MockDeviceGroupsFeature) Insert(ctx context.Context, g *dto.DeviceGroup) (*dto.DeviceGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, g)
+	ret0, _ := ret[0].(*dto.DeviceGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockDeviceGroupsFeatureMockRecorder) Insert(ctx, g any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockDeviceGroupsFeature)(nil).Insert), ctx, g)
+}
+
+// Refresh mocks base method.
+func (m *MockDeviceGroupsFeature) Refresh(ctx context.Context, id, tenantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, id, tenantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockDeviceGroupsFeatureMockRecorder) Refresh(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockDeviceGroupsFeature)(nil).Refresh), ctx, id, tenantID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockDeviceGroupsFeature) RemoveMember(ctx context.Context, id, guid, tenantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, id, guid, tenantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockDeviceGroupsFeatureMockRecorder) RemoveMember(ctx, id, guid, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockDeviceGroupsFeature)(nil).RemoveMember), ctx, id, guid, tenantID)
+}
+
+// Resolve mocks base method.
+func (m *MockDeviceGroupsFeature) Resolve(ctx context.Context, selection dto.DeviceSelection, tenantID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, selection, tenantID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockDeviceGroupsFeatureMockRecorder) Resolve(ctx, selection, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockDeviceGroupsFeature)(nil).Resolve), ctx, selection, tenantID)
+}
+
+// Update mocks base method.
+func (m *MockDeviceGroupsFeature) Update(ctx context.Context, g *dto.DeviceGroup) (*dto.DeviceGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, g)
+	ret0, _ := ret[0].(*dto.DeviceGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeviceGroupsFeatureMockRecorder) Update(ctx, g any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeviceGroupsFeature)(nil).Update), ctx, g)
+}
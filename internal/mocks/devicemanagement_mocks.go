@@ -11,6 +11,7 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	entity "github.com/device-management-toolkit/console/internal/entity"
@@ -322,6 +323,21 @@ func (mr *MockDeviceManagementRepositoryMockRecorder) GetByColumn(ctx, columnNam
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByColumn", reflect.TypeOf((*MockDeviceManagementRepository)(nil).GetByColumn), ctx, columnName, queryValue, tenantID)
 }
 
+// GetByGUID mocks base method.
+func (m *MockDeviceManagementRepository) GetByGUID(ctx context.Context, guid string) (*entity.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByGUID", ctx, guid)
+	ret0, _ := ret[0].(*entity.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByGUID indicates an expected call of GetByGUID.
+func (mr *MockDeviceManagementRepositoryMockRecorder) GetByGUID(ctx, guid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByGUID", reflect.TypeOf((*MockDeviceManagementRepository)(nil).GetByGUID), ctx, guid)
+}
+
 // GetByID mocks base method.
 func (m *MockDeviceManagementRepository) GetByID(ctx context.Context, guid, tenantID string) (*entity.Device, error) {
 	m.ctrl.T.Helper()
@@ -654,6 +670,21 @@ func (mr *MockDeviceManagementFeatureMockRecorder) GetByColumn(ctx, columnName,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByColumn", reflect.TypeOf((*MockDeviceManagementFeature)(nil).GetByColumn), ctx, columnName, queryValue, tenantID)
 }
 
+// GetByGUID mocks base method.
+func (m *MockDeviceManagementFeature) GetByGUID(ctx context.Context, guid string, includeSecrets bool) (*dto.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByGUID", ctx, guid, includeSecrets)
+	ret0, _ := ret[0].(*dto.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByGUID indicates an expected call of GetByGUID.
+func (mr *MockDeviceManagementFeatureMockRecorder) GetByGUID(ctx, guid, includeSecrets any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByGUID", reflect.TypeOf((*MockDeviceManagementFeature)(nil).GetByGUID), ctx, guid, includeSecrets)
+}
+
 // GetByID mocks base method.
 func (m *MockDeviceManagementFeature) GetByID(ctx context.Context, guid, tenantID string, includeSecrets bool) (*dto.Device, error) {
 	m.ctrl.T.Helper()
@@ -835,6 +866,21 @@ func (mr *MockDeviceManagementFeatureMockRecorder) GetKVMScreenSettings(c, guid
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKVMScreenSettings", reflect.TypeOf((*MockDeviceManagementFeature)(nil).GetKVMScreenSettings), c, guid)
 }
 
+// GetKVMScreenshot mocks base method.
+func (m *MockDeviceManagementFeature) GetKVMScreenshot(c context.Context, guid string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKVMScreenshot", c, guid)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKVMScreenshot indicates an expected call of GetKVMScreenshot.
+func (mr *MockDeviceManagementFeatureMockRecorder) GetKVMScreenshot(c, guid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKVMScreenshot", reflect.TypeOf((*MockDeviceManagementFeature)(nil).GetKVMScreenshot), c, guid)
+}
+
 // GetNetworkSettings mocks base method.
 func (m *MockDeviceManagementFeature) GetNetworkSettings(c context.Context, guid string) (dto.NetworkSettings, error) {
 	m.ctrl.T.Helper()
@@ -880,6 +926,21 @@ func (mr *MockDeviceManagementFeatureMockRecorder) GetPowerState(ctx, guid any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPowerState", reflect.TypeOf((*MockDeviceManagementFeature)(nil).GetPowerState), ctx, guid)
 }
 
+// GetRedirectionSessions mocks base method.
+func (m *MockDeviceManagementFeature) GetRedirectionSessions(ctx context.Context) ([]dto.RedirectionSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRedirectionSessions", ctx)
+	ret0, _ := ret[0].([]dto.RedirectionSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRedirectionSessions indicates an expected call of GetRedirectionSessions.
+func (mr *MockDeviceManagementFeatureMockRecorder) GetRedirectionSessions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedirectionSessions", reflect.TypeOf((*MockDeviceManagementFeature)(nil).GetRedirectionSessions), ctx)
+}
+
 // GetRemoteEraseCapabilities mocks base method.
 func (m *MockDeviceManagementFeature) GetRemoteEraseCapabilities(ctx context.Context, guid string) (dto.BootCapabilities, error) {
 	m.ctrl.T.Helper()
@@ -895,6 +956,36 @@ func (mr *MockDeviceManagementFeatureMockRecorder) GetRemoteEraseCapabilities(ct
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteEraseCapabilities", reflect.TypeOf((*MockDeviceManagementFeature)(nil).GetRemoteEraseCapabilities), ctx, guid)
 }
 
+// GetSOLCapture mocks base method.
+func (m *MockDeviceManagementFeature) GetSOLCapture(ctx context.Context, guid, id string) (dto.SOLCapture, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSOLCapture", ctx, guid, id)
+	ret0, _ := ret[0].(dto.SOLCapture)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSOLCapture indicates an expected call of GetSOLCapture.
+func (mr *MockDeviceManagementFeatureMockRecorder) GetSOLCapture(ctx, guid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSOLCapture", reflect.TypeOf((*MockDeviceManagementFeature)(nil).GetSOLCapture), ctx, guid, id)
+}
+
+// GetSOLCaptures mocks base method.
+func (m *MockDeviceManagementFeature) GetSOLCaptures(ctx context.Context, guid string) ([]dto.SOLCapture, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSOLCaptures", ctx, guid)
+	ret0, _ := ret[0].([]dto.SOLCapture)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSOLCaptures indicates an expected call of GetSOLCaptures.
+func (mr *MockDeviceManagementFeatureMockRecorder) GetSOLCaptures(ctx, guid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSOLCaptures", reflect.TypeOf((*MockDeviceManagementFeature)(nil).GetSOLCaptures), ctx, guid)
+}
+
 // GetTLSSettingData mocks base method.
 func (m *MockDeviceManagementFeature) GetTLSSettingData(c context.Context, guid string) ([]dto.SettingDataResponse, error) {
 	m.ctrl.T.Helper()
@@ -1016,6 +1107,21 @@ func (mr *MockDeviceManagementFeatureMockRecorder) Insert(ctx, d any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockDeviceManagementFeature)(nil).Insert), ctx, d)
 }
 
+// OpenSOL mocks base method.
+func (m *MockDeviceManagementFeature) OpenSOL(ctx context.Context, guid string) (io.ReadWriteCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenSOL", ctx, guid)
+	ret0, _ := ret[0].(io.ReadWriteCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenSOL indicates an expected call of OpenSOL.
+func (mr *MockDeviceManagementFeatureMockRecorder) OpenSOL(ctx, guid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenSOL", reflect.TypeOf((*MockDeviceManagementFeature)(nil).OpenSOL), ctx, guid)
+}
+
 // PatchWiredNetworkSettings mocks base method.
 func (m *MockDeviceManagementFeature) PatchWiredNetworkSettings(c context.Context, guid string, req dto.WiredNetworkConfigRequest) error {
 	m.ctrl.T.Helper()
@@ -1179,6 +1285,35 @@ func (mr *MockDeviceManagementFeatureMockRecorder) SetWirelessProfileSync(c, gui
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWirelessProfileSync", reflect.TypeOf((*MockDeviceManagementFeature)(nil).SetWirelessProfileSync), c, guid, req)
 }
 
+// StartSOLCapture mocks base method.
+func (m *MockDeviceManagementFeature) StartSOLCapture(ctx context.Context, guid string, req dto.SOLCaptureRequest) (dto.SOLCapture, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSOLCapture", ctx, guid, req)
+	ret0, _ := ret[0].(dto.SOLCapture)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartSOLCapture indicates an expected call of StartSOLCapture.
+func (mr *MockDeviceManagementFeatureMockRecorder) StartSOLCapture(ctx, guid, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSOLCapture", reflect.TypeOf((*MockDeviceManagementFeature)(nil).StartSOLCapture), ctx, guid, req)
+}
+
+// TerminateRedirectionSession mocks base method.
+func (m *MockDeviceManagementFeature) TerminateRedirectionSession(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TerminateRedirectionSession", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TerminateRedirectionSession indicates an expected call of TerminateRedirectionSession.
+func (mr *MockDeviceManagementFeatureMockRecorder) TerminateRedirectionSession(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TerminateRedirectionSession", reflect.TypeOf((*MockDeviceManagementFeature)(nil).TerminateRedirectionSession), ctx, id)
+}
+
 // Update mocks base method.
 func (m *MockDeviceManagementFeature) Update(ctx context.Context, d *dto.Device, fields map[string]bool) (*dto.Device, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,180 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/ldapauth/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source ./internal/usecase/ldapauth/interfaces.go -package mocks -mock_names Conn=MockLDAPConn,Dialer=MockLDAPDialer,Feature=MockLDAPFeature
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	ldapauth "github.com/device-management-toolkit/console/internal/usecase/ldapauth"
+	ldap "github.com/go-ldap/ldap/v3"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLDAPConn is a mock of Conn interface.
+type MockLDAPConn struct {
+	ctrl     *gomock.Controller
+	recorder *MockLDAPConnMockRecorder
+	isgomock struct{}
+}
+
+// MockLDAPConnMockRecorder is the mock recorder for MockLDAPConn.
+type MockLDAPConnMockRecorder struct {
+	mock *MockLDAPConn
+}
+
+// NewMockLDAPConn creates a new mock instance.
+func NewMockLDAPConn(ctrl *gomock.Controller) *MockLDAPConn {
+	mock := &MockLDAPConn{ctrl: ctrl}
+	mock.recorder = &MockLDAPConnMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLDAPConn) EXPECT() *MockLDAPConnMockRecorder {
+	return m.recorder
+}
+
+// Bind mocks base method.
+func (m *MockLDAPConn) Bind(username, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bind", username, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Bind indicates an expected call of Bind.
+func (mr *MockLDAPConnMockRecorder) Bind(username, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bind", reflect.TypeOf((*MockLDAPConn)(nil).Bind), username, password)
+}
+
+// Close mocks base method.
+func (m *MockLDAPConn) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockLDAPConnMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockLDAPConn)(nil).Close))
+}
+
+// Search mocks base method.
+func (m *MockLDAPConn) Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", searchRequest)
+	ret0, _ := ret[0].(*ldap.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockLDAPConnMockRecorder) Search(searchRequest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockLDAPConn)(nil).Search), searchRequest)
+}
+
+// MockLDAPDialer is a mock of Dialer interface.
+type MockLDAPDialer struct {
+	ctrl     *gomock.Controller
+	recorder *MockLDAPDialerMockRecorder
+	isgomock struct{}
+}
+
+// MockLDAPDialerMockRecorder is the mock recorder for MockLDAPDialer.
+type MockLDAPDialerMockRecorder struct {
+	mock *MockLDAPDialer
+}
+
+// NewMockLDAPDialer creates a new mock instance.
+func NewMockLDAPDialer(ctrl *gomock.Controller) *MockLDAPDialer {
+	mock := &MockLDAPDialer{ctrl: ctrl}
+	mock.recorder = &MockLDAPDialerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLDAPDialer) EXPECT() *MockLDAPDialerMockRecorder {
+	return m.recorder
+}
+
+// Dial mocks base method.
+func (m *MockLDAPDialer) Dial(ctx context.Context) (ldapauth.Conn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dial", ctx)
+	ret0, _ := ret[0].(ldapauth.Conn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dial indicates an expected call of Dial.
+func (mr *MockLDAPDialerMockRecorder) Dial(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dial", reflect.TypeOf((*MockLDAPDialer)(nil).Dial), ctx)
+}
+
+// MockLDAPFeature is a mock of Feature interface.
+type MockLDAPFeature struct {
+	ctrl     *gomock.Controller
+	recorder *MockLDAPFeatureMockRecorder
+	isgomock struct{}
+}
+
+// MockLDAPFeatureMockRecorder is the mock recorder for MockLDAPFeature.
+type MockLDAPFeatureMockRecorder struct {
+	mock *MockLDAPFeature
+}
+
+// NewMockLDAPFeature creates a new mock instance.
+func NewMockLDAPFeature(ctrl *gomock.Controller) *MockLDAPFeature {
+	mock := &MockLDAPFeature{ctrl: ctrl}
+	mock.recorder = &MockLDAPFeatureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLDAPFeature) EXPECT() *MockLDAPFeatureMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockLDAPFeature) Authenticate(ctx context.Context, username, password string) (*dto.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, username, password)
+	ret0, _ := ret[0].(*dto.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockLDAPFeatureMockRecorder) Authenticate(ctx, username, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockLDAPFeature)(nil).Authenticate), ctx, username, password)
+}
+
+// Lookup mocks base method.
+func (m *MockLDAPFeature) Lookup(ctx context.Context, username string) (*dto.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", ctx, username)
+	ret0, _ := ret[0].(*dto.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockLDAPFeatureMockRecorder) Lookup(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockLDAPFeature)(nil).Lookup), ctx, username)
+}
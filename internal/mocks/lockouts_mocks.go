@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/lockouts/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source ./internal/usecase/lockouts/interfaces.go -package mocks -mock_names Feature=MockLockoutsFeature
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLockoutsFeature is a mock of Feature interface.
+type MockLockoutsFeature struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockoutsFeatureMockRecorder
+	isgomock struct{}
+}
+
+// MockLockoutsFeatureMockRecorder is the mock recorder for MockLockoutsFeature.
+type MockLockoutsFeatureMockRecorder struct {
+	mock *MockLockoutsFeature
+}
+
+// NewMockLockoutsFeature creates a new mock instance.
+func NewMockLockoutsFeature(ctrl *gomock.Controller) *MockLockoutsFeature {
+	mock := &MockLockoutsFeature{ctrl: ctrl}
+	mock.recorder = &MockLockoutsFeatureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLockoutsFeature) EXPECT() *MockLockoutsFeatureMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLockoutsFeature) Check(ctx context.Context, username, ip string) time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, username, ip)
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLockoutsFeatureMockRecorder) Check(ctx, username, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLockoutsFeature)(nil).Check), ctx, username, ip)
+}
+
+// Clear mocks base method.
+func (m *MockLockoutsFeature) Clear(ctx context.Context, kind, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", ctx, kind, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockLockoutsFeatureMockRecorder) Clear(ctx, kind, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockLockoutsFeature)(nil).Clear), ctx, kind, key)
+}
+
+// Failed mocks base method.
+func (m *MockLockoutsFeature) Failed(ctx context.Context, username, ip string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Failed", ctx, username, ip)
+}
+
+// Failed indicates an expected call of Failed.
+func (mr *MockLockoutsFeatureMockRecorder) Failed(ctx, username, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Failed", reflect.TypeOf((*MockLockoutsFeature)(nil).Failed), ctx, username, ip)
+}
+
+// Get mocks base method.
+func (m *MockLockoutsFeature) Get(ctx context.Context) ([]dto.Lockout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx)
+	ret0, _ := ret[0].([]dto.Lockout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockLockoutsFeatureMockRecorder) Get(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockLockoutsFeature)(nil).Get), ctx)
+}
+
+// Succeeded mocks base method.
+func (m *MockLockoutsFeature) Succeeded(ctx context.Context, username string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Succeeded", ctx, username)
+}
+
+// Succeeded indicates an expected call of Succeeded.
+func (mr *MockLockoutsFeatureMockRecorder) Succeeded(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Succeeded", reflect.TypeOf((*MockLockoutsFeature)(nil).Succeeded), ctx, username)
+}
@@ -0,0 +1,279 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/tags/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source ./internal/usecase/tags/interfaces.go -package mocks -mock_names Repository=MockTagsRepository,Selector=MockTagsSelector,Referrer=MockTagsReferrer,Feature=MockTagsFeature
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/device-management-toolkit/console/internal/entity"
+	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTagsRepository is a mock of Repository interface.
+type MockTagsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTagsRepositoryMockRecorder is the mock recorder for MockTagsRepository.
+type MockTagsRepositoryMockRecorder struct {
+	mock *MockTagsRepository
+}
+
+// NewMockTagsRepository creates a new mock instance.
+func NewMockTagsRepository(ctrl *gomock.Controller) *MockTagsRepository {
+	mock := &MockTagsRepository{ctrl: ctrl}
+	mock.recorder = &MockTagsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagsRepository) EXPECT() *MockTagsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetCounts mocks base method.
+func (m *MockTagsRepository) GetCounts(ctx context.Context, tenantID string) ([]entity.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCounts", ctx, tenantID)
+	ret0, _ := ret[0].([]entity.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCounts indicates an expected call of GetCounts.
+func (mr *MockTagsRepositoryMockRecorder) GetCounts(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCounts", reflect.TypeOf((*MockTagsRepository)(nil).GetCounts), ctx, tenantID)
+}
+
+// GetGUIDs mocks base method.
+func (m *MockTagsRepository) GetGUIDs(ctx context.Context, tags []string, tenantID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGUIDs", ctx, tags, tenantID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGUIDs indicates an expected call of GetGUIDs.
+func (mr *MockTagsRepositoryMockRecorder) GetGUIDs(ctx, tags, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGUIDs", reflect.TypeOf((*MockTagsRepository)(nil).GetGUIDs), ctx, tags, tenantID)
+}
+
+// GetTags mocks base method.
+func (m *MockTagsRepository) GetTags(ctx context.Context, guids []string, tenantID string) ([]entity.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx, guids, tenantID)
+	ret0, _ := ret[0].([]entity.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockTagsRepositoryMockRecorder) GetTags(ctx, guids, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockTagsRepository)(nil).GetTags), ctx, guids, tenantID)
+}
+
+// Update mocks base method.
+func (m *MockTagsRepository) Update(ctx context.Context, guids, add, remove []string, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, guids, add, remove, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagsRepositoryMockRecorder) Update(ctx, guids, add, remove, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTagsRepository)(nil).Update), ctx, guids, add, remove, tenantID)
+}
+
+// MockTagsSelector is a mock of Selector interface.
+type MockTagsSelector struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagsSelectorMockRecorder
+	isgomock struct{}
+}
+
+// MockTagsSelectorMockRecorder is the mock recorder for MockTagsSelector.
+type MockTagsSelectorMockRecorder struct {
+	mock *MockTagsSelector
+}
+
+// NewMockTagsSelector creates a new mock instance.
+func NewMockTagsSelector(ctrl *gomock.Controller) *MockTagsSelector {
+	mock := &MockTagsSelector{ctrl: ctrl}
+	mock.recorder = &MockTagsSelectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagsSelector) EXPECT() *MockTagsSelectorMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockTagsSelector) Resolve(ctx context.Context, selection dto.DeviceSelection, tenantID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, selection, tenantID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockTagsSelectorMockRecorder) Resolve(ctx, selection, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockTagsSelector)(nil).Resolve), ctx, selection, tenantID)
+}
+
+// MockTagsReferrer is a mock of Referrer interface.
+type MockTagsReferrer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagsReferrerMockRecorder
+	isgomock struct{}
+}
+
+// MockTagsReferrerMockRecorder is the mock recorder for MockTagsReferrer.
+type MockTagsReferrerMockRecorder struct {
+	mock *MockTagsReferrer
+}
+
+// NewMockTagsReferrer creates a new mock instance.
+func NewMockTagsReferrer(ctrl *gomock.Controller) *MockTagsReferrer {
+	mock := &MockTagsReferrer{ctrl: ctrl}
+	mock.recorder = &MockTagsReferrerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagsReferrer) EXPECT() *MockTagsReferrerMockRecorder {
+	return m.recorder
+}
+
+// TagReferences mocks base method.
+func (m *MockTagsReferrer) TagReferences(ctx context.Context, tags []string, tenantID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagReferences", ctx, tags, tenantID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagReferences indicates an expected call of TagReferences.
+func (mr *MockTagsReferrerMockRecorder) TagReferences(ctx, tags, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagReferences", reflect.TypeOf((*MockTagsReferrer)(nil).TagReferences), ctx, tags, tenantID)
+}
+
+// MockTagsFeature is a mock of Feature interface.
+type MockTagsFeature struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagsFeatureMockRecorder
+	isgomock struct{}
+}
+
+// MockTagsFeatureMockRecorder is the mock recorder for MockTagsFeature.
+type MockTagsFeatureMockRecorder struct {
+	mock *MockTagsFeature
+}
+
+// NewMockTagsFeature creates a new mock instance.
+func NewMockTagsFeature(ctrl *gomock.Controller) *MockTagsFeature {
+	mock := &MockTagsFeature{ctrl: ctrl}
+	mock.recorder = &MockTagsFeatureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagsFeature) EXPECT() *MockTagsFeatureMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTagsFeature) Delete(ctx context.Context, tag, tenantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, tag, tenantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagsFeatureMockRecorder) Delete(ctx, tag, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTagsFeature)(nil).Delete), ctx, tag, tenantID)
+}
+
+// Get mocks base method.
+func (m *MockTagsFeature) Get(ctx context.Context, tenantID string) ([]dto.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, tenantID)
+	ret0, _ := ret[0].([]dto.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTagsFeatureMockRecorder) Get(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTagsFeature)(nil).Get), ctx, tenantID)
+}
+
+// Merge mocks base method.
+func (m *MockTagsFeature) Merge(ctx context.Context, merge dto.TagMerge, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, merge, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockTagsFeatureMockRecorder) Merge(ctx, merge, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockTagsFeature)(nil).Merge), ctx, merge, tenantID)
+}
+
+// Rename mocks base method.
+func (m *MockTagsFeature) Rename(ctx context.Context, tag, name, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, tag, name, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockTagsFeatureMockRecorder) Rename(ctx, tag, name, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockTagsFeature)(nil).Rename), ctx, tag, name, tenantID)
+}
+
+// Update mocks base method.
+func (m *MockTagsFeature) Update(ctx context.Context, update dto.TagUpdate, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, update, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagsFeatureMockRecorder) Update(ctx, update, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTagsFeature)(nil).Update), ctx, update, tenantID)
+}
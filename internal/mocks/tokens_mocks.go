@@ -0,0 +1,292 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/tokens/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source ./internal/usecase/tokens/interfaces.go -package mocks -mock_names Repository=MockTokensRepository,Feature=MockTokensFeature
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/device-management-toolkit/console/internal/entity"
+	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTokensRepository is a mock of Repository interface.
+type MockTokensRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokensRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTokensRepositoryMockRecorder is the mock recorder for MockTokensRepository.
+type MockTokensRepositoryMockRecorder struct {
+	mock *MockTokensRepository
+}
+
+// NewMockTokensRepository creates a new mock instance.
+func NewMockTokensRepository(ctrl *gomock.Controller) *MockTokensRepository {
+	mock := &MockTokensRepository{ctrl: ctrl}
+	mock.recorder = &MockTokensRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokensRepository) EXPECT() *MockTokensRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteSession mocks base method.
+func (m *MockTokensRepository) DeleteSession(ctx context.Context, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockTokensRepositoryMockRecorder) DeleteSession(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockTokensRepository)(nil).DeleteSession), ctx, sessionID)
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockTokensRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(*entity.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockTokensRepositoryMockRecorder) GetRefreshToken(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockTokensRepository)(nil).GetRefreshToken), ctx, tokenHash)
+}
+
+// GetRefreshTokensByUser mocks base method.
+func (m *MockTokensRepository) GetRefreshTokensByUser(ctx context.Context, username, tenantID string) ([]entity.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshTokensByUser", ctx, username, tenantID)
+	ret0, _ := ret[0].([]entity.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshTokensByUser indicates an expected call of GetRefreshTokensByUser.
+func (mr *MockTokensRepositoryMockRecorder) GetRefreshTokensByUser(ctx, username, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshTokensByUser", reflect.TypeOf((*MockTokensRepository)(nil).GetRefreshTokensByUser), ctx, username, tenantID)
+}
+
+// GetRevoked mocks base method.
+func (m *MockTokensRepository) GetRevoked(ctx context.Context, top, skip int) ([]entity.RevokedToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevoked", ctx, top, skip)
+	ret0, _ := ret[0].([]entity.RevokedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevoked indicates an expected call of GetRevoked.
+func (mr *MockTokensRepositoryMockRecorder) GetRevoked(ctx, top, skip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevoked", reflect.TypeOf((*MockTokensRepository)(nil).GetRevoked), ctx, top, skip)
+}
+
+// InsertRefreshToken mocks base method.
+func (m *MockTokensRepository) InsertRefreshToken(ctx context.Context, t *entity.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertRefreshToken", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertRefreshToken indicates an expected call of InsertRefreshToken.
+func (mr *MockTokensRepositoryMockRecorder) InsertRefreshToken(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRefreshToken", reflect.TypeOf((*MockTokensRepository)(nil).InsertRefreshToken), ctx, t)
+}
+
+// IsRevoked mocks base method.
+func (m *MockTokensRepository) IsRevoked(ctx context.Context, ids []string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", ctx, ids)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockTokensRepositoryMockRecorder) IsRevoked(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockTokensRepository)(nil).IsRevoked), ctx, ids)
+}
+
+// MarkRefreshTokenUsed mocks base method.
+func (m *MockTokensRepository) MarkRefreshTokenUsed(ctx context.Context, tokenHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRefreshTokenUsed", ctx, tokenHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRefreshTokenUsed indicates an expected call of MarkRefreshTokenUsed.
+func (mr *MockTokensRepositoryMockRecorder) MarkRefreshTokenUsed(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRefreshTokenUsed", reflect.TypeOf((*MockTokensRepository)(nil).MarkRefreshTokenUsed), ctx, tokenHash)
+}
+
+// Prune mocks base method.
+func (m *MockTokensRepository) Prune(ctx context.Context, now string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockTokensRepositoryMockRecorder) Prune(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockTokensRepository)(nil).Prune), ctx, now)
+}
+
+// Revoke mocks base method.
+func (m *MockTokensRepository) Revoke(ctx context.Context, t *entity.RevokedToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockTokensRepositoryMockRecorder) Revoke(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockTokensRepository)(nil).Revoke), ctx, t)
+}
+
+// MockTokensFeature is a mock of Feature interface.
+type MockTokensFeature struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokensFeatureMockRecorder
+	isgomock struct{}
+}
+
+// MockTokensFeatureMockRecorder is the mock recorder for MockTokensFeature.
+type MockTokensFeatureMockRecorder struct {
+	mock *MockTokensFeature
+}
+
+// NewMockTokensFeature creates a new mock instance.
+func NewMockTokensFeature(ctrl *gomock.Controller) *MockTokensFeature {
+	mock := &MockTokensFeature{ctrl: ctrl}
+	mock.recorder = &MockTokensFeatureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokensFeature) EXPECT() *MockTokensFeatureMockRecorder {
+	return m.recorder
+}
+
+// End mocks base method.
+func (m *MockTokensFeature) End(ctx context.Context, sessionID, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "End", ctx, sessionID, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// End indicates an expected call of End.
+func (mr *MockTokensFeatureMockRecorder) End(ctx, sessionID, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "End", reflect.TypeOf((*MockTokensFeature)(nil).End), ctx, sessionID, username)
+}
+
+// EndAll mocks base method.
+func (m *MockTokensFeature) EndAll(ctx context.Context, username, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndAll", ctx, username, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EndAll indicates an expected call of EndAll.
+func (mr *MockTokensFeatureMockRecorder) EndAll(ctx, username, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndAll", reflect.TypeOf((*MockTokensFeature)(nil).EndAll), ctx, username, tenantID)
+}
+
+// IsRevoked mocks base method.
+func (m *MockTokensFeature) IsRevoked(ctx context.Context, ids ...string) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "IsRevoked", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockTokensFeatureMockRecorder) IsRevoked(ctx any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockTokensFeature)(nil).IsRevoked), varargs...)
+}
+
+// Refresh mocks base method.
+func (m *MockTokensFeature) Refresh(ctx context.Context, refreshToken string) (*dto.TokenSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, refreshToken)
+	ret0, _ := ret[0].(*dto.TokenSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockTokensFeatureMockRecorder) Refresh(ctx, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockTokensFeature)(nil).Refresh), ctx, refreshToken)
+}
+
+// Revoke mocks base method.
+func (m *MockTokensFeature) Revoke(ctx context.Context, jti, subject string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, jti, subject, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockTokensFeatureMockRecorder) Revoke(ctx, jti, subject, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockTokensFeature)(nil).Revoke), ctx, jti, subject, expiresAt)
+}
+
+// Start mocks base method.
+func (m *MockTokensFeature) Start(ctx context.Context, username, tenantID string) (*dto.TokenSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, username, tenantID)
+	ret0, _ := ret[0].(*dto.TokenSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockTokensFeatureMockRecorder) Start(ctx, username, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockTokensFeature)(nil).Start), ctx, username, tenantID)
+}
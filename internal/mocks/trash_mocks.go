@@ -0,0 +1,231 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/trash/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source ./internal/usecase/trash/interfaces.go -package mocks -mock_names Repository=MockTrashRepository,Feature=MockTrashFeature
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/device-management-toolkit/console/internal/entity"
+	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTrashRepository is a mock of Repository interface.
+type MockTrashRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTrashRepositoryMockRecorder is the mock recorder for MockTrashRepository.
+type MockTrashRepositoryMockRecorder struct {
+	mock *MockTrashRepository
+}
+
+// NewMockTrashRepository creates a new mock instance.
+func NewMockTrashRepository(ctrl *gomock.Controller) *MockTrashRepository {
+	mock := &MockTrashRepository{ctrl: ctrl}
+	mock.recorder = &MockTrashRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrashRepository) EXPECT() *MockTrashRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockTrashRepository) Get(ctx context.Context, kind string, top, skip int, tenantID string) ([]entity.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, kind, top, skip, tenantID)
+	ret0, _ := ret[0].([]entity.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTrashRepositoryMockRecorder) Get(ctx, kind, top, skip, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTrashRepository)(nil).Get), ctx, kind, top, skip, tenantID)
+}
+
+// GetCount mocks base method.
+func (m *MockTrashRepository) GetCount(ctx context.Context, kind, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCount", ctx, kind, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCount indicates an expected call of GetCount.
+func (mr *MockTrashRepositoryMockRecorder) GetCount(ctx, kind, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCount", reflect.TypeOf((*MockTrashRepository)(nil).GetCount), ctx, kind, tenantID)
+}
+
+// GetExpired mocks base method.
+func (m *MockTrashRepository) GetExpired(ctx context.Context, kind, deletedBefore string, top, skip int) ([]entity.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpired", ctx, kind, deletedBefore, top, skip)
+	ret0, _ := ret[0].([]entity.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpired indicates an expected call of GetExpired.
+func (mr *MockTrashRepositoryMockRecorder) GetExpired(ctx, kind, deletedBefore, top, skip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpired", reflect.TypeOf((*MockTrashRepository)(nil).GetExpired), ctx, kind, deletedBefore, top, skip)
+}
+
+// MoveToTrash mocks base method.
+func (m *MockTrashRepository) MoveToTrash(ctx context.Context, kind, id, tenantID, deletedAt string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveToTrash", ctx, kind, id, tenantID, deletedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveToTrash indicates an expected call of MoveToTrash.
+func (mr *MockTrashRepositoryMockRecorder) MoveToTrash(ctx, kind, id, tenantID, deletedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToTrash", reflect.TypeOf((*MockTrashRepository)(nil).MoveToTrash), ctx, kind, id, tenantID, deletedAt)
+}
+
+// Purge mocks base method.
+func (m *MockTrashRepository) Purge(ctx context.Context, kind, id, tenantID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, kind, id, tenantID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTrashRepositoryMockRecorder) Purge(ctx, kind, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTrashRepository)(nil).Purge), ctx, kind, id, tenantID)
+}
+
+// Restore mocks base method.
+func (m *MockTrashRepository) Restore(ctx context.Context, kind, id, tenantID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, kind, id, tenantID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTrashRepositoryMockRecorder) Restore(ctx, kind, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTrashRepository)(nil).Restore), ctx, kind, id, tenantID)
+}
+
+// MockTrashFeature is a mock of Feature interface.
+type MockTrashFeature struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashFeatureMockRecorder
+	isgomock struct{}
+}
+
+// MockTrashFeatureMockRecorder is the mock recorder for MockTrashFeature.
+type MockTrashFeatureMockRecorder struct {
+	mock *MockTrashFeature
+}
+
+// NewMockTrashFeature creates a new mock instance.
+func NewMockTrashFeature(ctrl *gomock.Controller) *MockTrashFeature {
+	mock := &MockTrashFeature{ctrl: ctrl}
+	mock.recorder = &MockTrashFeatureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrashFeature) EXPECT() *MockTrashFeatureMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockTrashFeature) Get(ctx context.Context, kind string, top, skip int, tenantID string) ([]dto.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, kind, top, skip, tenantID)
+	ret0, _ := ret[0].([]dto.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTrashFeatureMockRecorder) Get(ctx, kind, top, skip, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTrashFeature)(nil).Get), ctx, kind, top, skip, tenantID)
+}
+
+// GetCount mocks base method.
+func (m *MockTrashFeature) GetCount(ctx context.Context, kind, tenantID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCount", ctx, kind, tenantID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCount indicates an expected call of GetCount.
+func (mr *MockTrashFeatureMockRecorder) GetCount(ctx, kind, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCount", reflect.TypeOf((*MockTrashFeature)(nil).GetCount), ctx, kind, tenantID)
+}
+
+// Purge mocks base method.
+func (m *MockTrashFeature) Purge(ctx context.Context, kind, id, tenantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, kind, id, tenantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTrashFeatureMockRecorder) Purge(ctx, kind, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTrashFeature)(nil).Purge), ctx, kind, id, tenantID)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockTrashFeature) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", ctx, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockTrashFeatureMockRecorder) PurgeExpired(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockTrashFeature)(nil).PurgeExpired), ctx, now)
+}
+
+// Restore mocks base method.
+func (m *MockTrashFeature) Restore(ctx context.Context, kind, id, tenantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, kind, id, tenantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTrashFeatureMockRecorder) Restore(ctx, kind, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTrashFeature)(nil).Restore), ctx, kind, id, tenantID)
+}
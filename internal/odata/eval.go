@@ -0,0 +1,93 @@
+package odata
+
+import (
+	"cmp"
+	"strings"
+)
+
+// Record returns a value by field name, for evaluating a query in memory.
+// Values are strings, bools or integers, as the field's Type says.
+type Record func(field string) any
+
+// Match reports whether r satisfies e. A nil e matches every record.
+func (e *Expr) Match(r Record) bool {
+	if e == nil {
+		return true
+	}
+
+	switch e.Op {
+	case And:
+		return e.Left.Match(r) && e.Right.Match(r)
+	case Or:
+		return e.Left.Match(r) || e.Right.Match(r)
+	case Contains, StartsWith:
+		s, _ := r(e.Field).(string)
+		value, _ := e.Value.(string)
+
+		if e.Op == Contains {
+			return strings.Contains(strings.ToLower(s), strings.ToLower(value))
+		}
+
+		return strings.HasPrefix(strings.ToLower(s), strings.ToLower(value))
+	case Eq:
+		return compare(r(e.Field), e.Value) == 0
+	case Ne:
+		return compare(r(e.Field), e.Value) != 0
+	case Gt:
+		return compare(r(e.Field), e.Value) > 0
+	case Lt:
+		return compare(r(e.Field), e.Value) < 0
+	}
+
+	return false
+}
+
+// Compare orders a and b by q's $orderby terms.
+func (q *Query) Compare(a, b Record) int {
+	for _, o := range q.OrderBy {
+		c := compare(a(o.Field), b(o.Field))
+		if o.Desc {
+			c = -c
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// compare orders values of one field. false sorts before true.
+func compare(a, b any) int {
+	switch a := a.(type) {
+	case string:
+		b, _ := b.(string)
+
+		return strings.Compare(a, b)
+	case bool:
+		b, _ := b.(bool)
+
+		switch {
+		case a == b:
+			return 0
+		case a:
+			return 1
+		default:
+			return -1
+		}
+	default:
+		return cmp.Compare(toInt(a), toInt(b))
+	}
+}
+
+func toInt(v any) int64 {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	}
+
+	return 0
+}
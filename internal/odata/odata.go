@@ -0,0 +1,80 @@
+// Package odata parses the subset of OData list options the console accepts
+// ($filter, $orderby and $select) and carries the result through a request's
+// context, so repositories can apply it without each signature naming it.
+// Only fields on an entity's allowlist can be named.
+package odata
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidQuery is wrapped by every error Parse returns.
+var ErrInvalidQuery = errors.New("invalid OData query")
+
+// Type is the type of a field's values.
+type Type int
+
+const (
+	// Opaque fields can be selected but not filtered or ordered on.
+	Opaque Type = iota
+	String
+	Bool
+	Int
+)
+
+// Fields is an entity's allowlist, by the field names of its API (JSON)
+// representation.
+type Fields map[string]Type
+
+// Op is the operator of an Expr.
+type Op string
+
+const (
+	And        Op = "and"
+	Or         Op = "or"
+	Eq         Op = "eq"
+	Ne         Op = "ne"
+	Gt         Op = "gt"
+	Lt         Op = "lt"
+	Contains   Op = "contains"
+	StartsWith Op = "startswith"
+)
+
+// Expr is a $filter expression. And and Or combine Left and Right; the other
+// operators compare Field with Value, which is a string, bool or int64 as
+// the field's Type requires. Contains and StartsWith ignore case.
+type Expr struct {
+	Op          Op
+	Left, Right *Expr
+	Field       string
+	Value       any
+}
+
+// Order is one $orderby term.
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+// Query holds the parsed options. A nil Filter matches everything, and an
+// empty Select returns every field.
+type Query struct {
+	Filter  *Expr
+	OrderBy []Order
+	Select  []string
+}
+
+type contextKey struct{}
+
+// WithQuery returns a copy of ctx carrying q.
+func WithQuery(ctx context.Context, q *Query) context.Context {
+	return context.WithValue(ctx, contextKey{}, q)
+}
+
+// FromContext returns the query of ctx, or nil when it has none.
+func FromContext(ctx context.Context) *Query {
+	q, _ := ctx.Value(contextKey{}).(*Query)
+
+	return q
+}
@@ -0,0 +1,109 @@
+package odata
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testFields = Fields{
+	"hostname":         String,
+	"connectionStatus": Bool,
+	"tlsMode":          Int,
+	"tags":             Opaque,
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	q, err := Parse("connectionStatus eq false and (startswith(hostname,'lab-') or tlsMode gt 1)",
+		"hostname desc, tlsMode", "hostname,tags,hostname", testFields)
+	require.NoError(t, err)
+
+	require.Equal(t, &Expr{
+		Op:   And,
+		Left: &Expr{Op: Eq, Field: "connectionStatus", Value: false},
+		Right: &Expr{
+			Op:    Or,
+			Left:  &Expr{Op: StartsWith, Field: "hostname", Value: "lab-"},
+			Right: &Expr{Op: Gt, Field: "tlsMode", Value: int64(1)},
+		},
+	}, q.Filter)
+	require.Equal(t, []Order{{Field: "hostname", Desc: true}, {Field: "tlsMode"}}, q.OrderBy)
+	require.Equal(t, []string{"hostname", "tags"}, q.Select)
+
+	q, err = Parse("hostname eq 'O''Brien'", "", "", testFields)
+	require.NoError(t, err)
+	require.Equal(t, &Expr{Op: Eq, Field: "hostname", Value: "O'Brien"}, q.Filter)
+
+	q, err = Parse("", "", "", testFields)
+	require.NoError(t, err)
+	require.Equal(t, &Query{}, q)
+}
+
+func TestParseRejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name, filter, orderBy, selectFields string
+	}{
+		{name: "unknown field", filter: "password eq 'x'"},
+		{name: "opaque field", filter: "tags eq 'x'"},
+		{name: "wrong literal type", filter: "tlsMode eq '1'"},
+		{name: "ordering a bool", filter: "connectionStatus gt false"},
+		{name: "function on a number", filter: "contains(tlsMode,'1')"},
+		{name: "unknown operator", filter: "tlsMode ge 1"},
+		{name: "unterminated string", filter: "hostname eq 'lab"},
+		{name: "trailing input", filter: "hostname eq 'a' hostname"},
+		{name: "unbalanced parenthesis", filter: "(hostname eq 'a'"},
+		{name: "injection", filter: "hostname eq 'a'; drop table devices"},
+		{name: "too many conditions", filter: strings.Repeat("tlsMode eq 1 or ", maxTerms) + "tlsMode eq 1"},
+		{name: "too deep", filter: strings.Repeat("(", maxDepth+1) + "tlsMode eq 1" + strings.Repeat(")", maxDepth+1)},
+		{name: "order by opaque", orderBy: "tags"},
+		{name: "bad direction", orderBy: "hostname up"},
+		{name: "select unknown", selectFields: "hostname,password"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tc.filter, tc.orderBy, tc.selectFields, testFields)
+			require.ErrorIs(t, err, ErrInvalidQuery)
+		})
+	}
+}
+
+func TestMatchAndCompare(t *testing.T) {
+	t.Parallel()
+
+	q, err := Parse("connectionStatus eq false and (contains(hostname,'LAB') or tlsMode lt 2)", "tlsMode desc,hostname", "", testFields)
+	require.NoError(t, err)
+
+	record := func(hostname string, connected bool, tlsMode int) Record {
+		values := map[string]any{"hostname": hostname, "connectionStatus": connected, "tlsMode": tlsMode}
+
+		return func(field string) any { return values[field] }
+	}
+
+	require.True(t, q.Filter.Match(record("my-lab-1", false, 3)))
+	require.True(t, q.Filter.Match(record("office", false, 1)))
+	require.False(t, q.Filter.Match(record("office", false, 3)))
+	require.False(t, q.Filter.Match(record("lab-1", true, 1)))
+	require.True(t, (*Expr)(nil).Match(record("any", true, 0)))
+
+	require.Negative(t, q.Compare(record("b", false, 3), record("a", false, 1)))
+	require.Negative(t, q.Compare(record("a", false, 1), record("b", false, 1)))
+	require.Zero(t, q.Compare(record("a", true, 1), record("a", false, 1)))
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, FromContext(context.Background()))
+
+	q := &Query{Select: []string{"hostname"}}
+	require.Same(t, q, FromContext(WithQuery(context.Background(), q)))
+}
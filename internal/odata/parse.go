@@ -0,0 +1,417 @@
+package odata
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxFilterLength = 2048
+	maxTerms        = 32
+	maxDepth        = 8
+	maxOrderBy      = 4
+)
+
+// Parse parses the $filter, $orderby and $select options, any of which may
+// be empty, against the allowlist fields.
+func Parse(filter, orderBy, selectFields string, fields Fields) (*Query, error) {
+	q := &Query{}
+
+	if strings.TrimSpace(filter) != "" {
+		if len(filter) > maxFilterLength {
+			return nil, fmt.Errorf("%w: $filter is longer than %d characters", ErrInvalidQuery, maxFilterLength)
+		}
+
+		tokens, err := lex(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		p := &parser{tokens: tokens, fields: fields}
+
+		if q.Filter, err = p.parseOr(0); err != nil {
+			return nil, err
+		}
+
+		if t := p.peek(); t.kind != tokenEOF {
+			return nil, fmt.Errorf("%w: $filter: unexpected %q", ErrInvalidQuery, t.text)
+		}
+	}
+
+	var err error
+
+	if q.OrderBy, err = parseOrderBy(orderBy, fields); err != nil {
+		return nil, err
+	}
+
+	if q.Select, err = parseSelect(selectFields, fields); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+func parseOrderBy(orderBy string, fields Fields) ([]Order, error) {
+	if strings.TrimSpace(orderBy) == "" {
+		return nil, nil
+	}
+
+	terms := strings.Split(orderBy, ",")
+	if len(terms) > maxOrderBy {
+		return nil, fmt.Errorf("%w: $orderby takes at most %d fields", ErrInvalidQuery, maxOrderBy)
+	}
+
+	orders := make([]Order, 0, len(terms))
+
+	for _, term := range terms {
+		words := strings.Fields(term)
+		if len(words) == 0 || len(words) > 2 {
+			return nil, fmt.Errorf("%w: $orderby: %q is not a field with an optional asc or desc", ErrInvalidQuery, strings.TrimSpace(term))
+		}
+
+		if typ, ok := fields[words[0]]; !ok || typ == Opaque {
+			return nil, fmt.Errorf("%w: $orderby: cannot order by %q", ErrInvalidQuery, words[0])
+		}
+
+		order := Order{Field: words[0]}
+
+		if len(words) == 2 {
+			switch words[1] {
+			case "asc":
+			case "desc":
+				order.Desc = true
+			default:
+				return nil, fmt.Errorf("%w: $orderby: %q is neither asc nor desc", ErrInvalidQuery, words[1])
+			}
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+func parseSelect(selectFields string, fields Fields) ([]string, error) {
+	if strings.TrimSpace(selectFields) == "" {
+		return nil, nil
+	}
+
+	var selected []string
+
+	for _, name := range strings.Split(selectFields, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := fields[name]; !ok {
+			return nil, fmt.Errorf("%w: $select: unknown field %q", ErrInvalidQuery, name)
+		}
+
+		if !slices.Contains(selected, name) {
+			selected = append(selected, name)
+		}
+	}
+
+	return selected, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOpen
+	tokenClose
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ","})
+			i++
+		case c == '\'':
+			text, n, err := lexString(s[i:])
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: text})
+			i += n
+		case c == '-' || isDigit(c):
+			j := i + 1
+			for j < len(s) && isDigit(s[j]) {
+				j++
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, text: s[i:j]})
+			i = j
+		case isLetter(c):
+			j := i + 1
+			for j < len(s) && (isLetter(s[j]) || isDigit(s[j])) {
+				j++
+			}
+
+			tokens = append(tokens, token{kind: tokenIdent, text: s[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("%w: $filter: unexpected %q", ErrInvalidQuery, c)
+		}
+	}
+
+	return tokens, nil
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// lexString reads a quoted string at the start of s, in which a doubled
+// quote stands for one, and returns it with the number of bytes read.
+func lexString(s string) (value string, n int, err error) {
+	var b strings.Builder
+
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			b.WriteByte(s[i])
+
+			continue
+		}
+
+		if i+1 < len(s) && s[i+1] == '\'' {
+			b.WriteByte('\'')
+			i++
+
+			continue
+		}
+
+		return b.String(), i + 1, nil
+	}
+
+	return "", 0, fmt.Errorf("%w: $filter: unterminated string", ErrInvalidQuery)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	fields Fields
+	terms  int
+}
+
+func (p *parser) peek() token {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+
+	return token{kind: tokenEOF, text: "end of filter"}
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, fmt.Errorf("%w: $filter: expected %s, got %q", ErrInvalidQuery, what, t.text)
+	}
+
+	return t, nil
+}
+
+func (p *parser) parseOr(depth int) (*Expr, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for t := p.peek(); t.kind == tokenIdent && t.text == string(Or); t = p.peek() {
+		p.next()
+
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Expr{Op: Or, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (*Expr, error) {
+	left, err := p.parseTerm(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for t := p.peek(); t.kind == tokenIdent && t.text == string(And); t = p.peek() {
+		p.next()
+
+		right, err := p.parseTerm(depth)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Expr{Op: And, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseTerm(depth int) (*Expr, error) {
+	if p.peek().kind == tokenOpen {
+		if depth >= maxDepth {
+			return nil, fmt.Errorf("%w: $filter nests deeper than %d", ErrInvalidQuery, maxDepth)
+		}
+
+		p.next()
+
+		e, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := p.expect(tokenClose, ")"); err != nil {
+			return nil, err
+		}
+
+		return e, nil
+	}
+
+	p.terms++
+	if p.terms > maxTerms {
+		return nil, fmt.Errorf("%w: $filter has more than %d conditions", ErrInvalidQuery, maxTerms)
+	}
+
+	t, err := p.expect(tokenIdent, "a field or function")
+	if err != nil {
+		return nil, err
+	}
+
+	if (t.text == string(Contains) || t.text == string(StartsWith)) && p.peek().kind == tokenOpen {
+		return p.parseFunction(Op(t.text))
+	}
+
+	return p.parseComparison(t.text)
+}
+
+// parseFunction parses the arguments of contains(field,'text') or
+// startswith(field,'text').
+func (p *parser) parseFunction(op Op) (*Expr, error) {
+	p.next()
+
+	field, err := p.expect(tokenIdent, "a field")
+	if err != nil {
+		return nil, err
+	}
+
+	if typ, err := p.fieldType(field.text); err != nil {
+		return nil, err
+	} else if typ != String {
+		return nil, fmt.Errorf("%w: $filter: %s needs a string field, not %q", ErrInvalidQuery, op, field.text)
+	}
+
+	if _, err := p.expect(tokenComma, ","); err != nil {
+		return nil, err
+	}
+
+	value, err := p.expect(tokenString, "a quoted string")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.expect(tokenClose, ")"); err != nil {
+		return nil, err
+	}
+
+	return &Expr{Op: op, Field: field.text, Value: value.text}, nil
+}
+
+func (p *parser) parseComparison(field string) (*Expr, error) {
+	typ, err := p.fieldType(field)
+	if err != nil {
+		return nil, err
+	}
+
+	opToken, err := p.expect(tokenIdent, "eq, ne, gt or lt")
+	if err != nil {
+		return nil, err
+	}
+
+	op := Op(opToken.text)
+
+	switch op {
+	case Eq, Ne:
+	case Gt, Lt:
+		if typ == Bool {
+			return nil, fmt.Errorf("%w: $filter: %q can only be compared with eq or ne", ErrInvalidQuery, field)
+		}
+	default:
+		return nil, fmt.Errorf("%w: $filter: expected eq, ne, gt or lt, got %q", ErrInvalidQuery, opToken.text)
+	}
+
+	value, err := p.parseLiteral(field, typ)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expr{Op: op, Field: field, Value: value}, nil
+}
+
+func (p *parser) parseLiteral(field string, typ Type) (any, error) {
+	t := p.next()
+
+	switch {
+	case typ == String && t.kind == tokenString:
+		return t.text, nil
+	case typ == Bool && t.kind == tokenIdent && (t.text == "true" || t.text == "false"):
+		return t.text == "true", nil
+	case typ == Int && t.kind == tokenNumber:
+		n, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: $filter: %q is not an integer", ErrInvalidQuery, t.text)
+		}
+
+		return n, nil
+	}
+
+	return nil, fmt.Errorf("%w: $filter: %q is not a valid value for %q", ErrInvalidQuery, t.text, field)
+}
+
+func (p *parser) fieldType(field string) (Type, error) {
+	typ, ok := p.fields[field]
+	if !ok || typ == Opaque {
+		return typ, fmt.Errorf("%w: $filter: cannot filter on %q", ErrInvalidQuery, field)
+	}
+
+	return typ, nil
+}
@@ -0,0 +1,226 @@
+// Package rbac defines the console's roles, the permissions they grant and
+// the permission each API route requires. The table is shared by the HTTP
+// middleware and the OpenAPI spec so the two cannot drift apart.
+package rbac
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Role is a named set of permissions carried by an access token.
+type Role string
+
+const (
+	// RoleSuperAdmin is an admin of every tenant.
+	RoleSuperAdmin Role = "super-admin"
+	RoleAdmin      Role = "admin"
+	RoleOperator   Role = "operator"
+	RoleHelpdesk   Role = "helpdesk"
+	RoleReadOnly   Role = "read-only"
+)
+
+// Permission is the right to call a class of routes.
+type Permission string
+
+const (
+	// DevicesRead covers the inventory and reading device state, logs and settings.
+	DevicesRead Permission = "devices:read"
+	// DevicesWrite covers adding, editing and removing inventory entries.
+	DevicesWrite Permission = "devices:write"
+	// DevicesControl covers power and boot actions, user consent and KVM, SOL and IDER.
+	DevicesControl Permission = "devices:control"
+	// DevicesConfigure covers changing AMT settings on the device.
+	DevicesConfigure Permission = "devices:configure"
+	// DevicesErase covers remote platform erase, which destroys data on the device.
+	DevicesErase Permission = "devices:erase"
+	// ConfigRead covers reading domains, profiles and other provisioning configuration.
+	ConfigRead Permission = "config:read"
+	// ConfigWrite covers changing provisioning configuration.
+	ConfigWrite Permission = "config:write"
+	// ConfigBackup covers backing up and restoring all configuration, with
+	// its secrets and, optionally, devices and their credentials.
+	ConfigBackup Permission = "config:backup"
+	// SessionsRead covers listing active redirection sessions.
+	SessionsRead Permission = "sessions:read"
+	// SessionsManage covers terminating other users' redirection sessions.
+	SessionsManage Permission = "sessions:manage"
+	// UsersManage covers creating, changing and removing local user accounts.
+	UsersManage Permission = "users:manage"
+	// APIKeysManage covers issuing and revoking API keys.
+	APIKeysManage Permission = "apikeys:manage"
+	// LockoutsManage covers seeing and lifting login lockouts, so admins can
+	// let their users back in. Lockouts name usernames and client IPs of
+	// every tenant.
+	LockoutsManage Permission = "lockouts:manage"
+	// AuditRead covers querying and exporting the console's audit trail.
+	AuditRead Permission = "audit:read"
+	// AuditVerify covers checking the audit trail's hash chain, which spans
+	// every tenant.
+	AuditVerify Permission = "audit:verify"
+	// PoliciesManage covers creating, changing and removing the access
+	// policies that confine users and roles to tagged devices.
+	PoliciesManage Permission = "policies:manage"
+	// TenantsAll covers acting in any tenant rather than the caller's own. No
+	// route requires it; it is checked when a request names another tenant.
+	TenantsAll Permission = "tenants:all"
+)
+
+// DevicePermissions lists the permissions that act on individual devices.
+// Access policies narrow these to the devices a caller may reach.
+var DevicePermissions = []Permission{DevicesRead, DevicesWrite, DevicesControl, DevicesConfigure, DevicesErase}
+
+// Roles lists every role, most privileged first.
+var Roles = []Role{RoleSuperAdmin, RoleAdmin, RoleOperator, RoleHelpdesk, RoleReadOnly}
+
+var rolePermissions = map[Role][]Permission{
+	RoleSuperAdmin: {
+		DevicesRead, DevicesWrite, DevicesControl, DevicesConfigure, DevicesErase,
+		ConfigRead, ConfigWrite, ConfigBackup, SessionsRead, SessionsManage,
+		UsersManage, APIKeysManage, LockoutsManage, AuditRead, AuditVerify,
+		PoliciesManage, TenantsAll,
+	},
+	RoleAdmin: {
+		DevicesRead, DevicesWrite, DevicesControl, DevicesConfigure, DevicesErase,
+		ConfigRead, ConfigWrite, ConfigBackup, SessionsRead, SessionsManage,
+		UsersManage, APIKeysManage, LockoutsManage, AuditRead, PoliciesManage,
+	},
+	RoleOperator: {
+		DevicesRead, DevicesWrite, DevicesControl, DevicesConfigure,
+		ConfigRead, SessionsRead,
+	},
+	RoleHelpdesk: {DevicesRead, DevicesControl, SessionsRead},
+	RoleReadOnly: {DevicesRead, ConfigRead, SessionsRead},
+}
+
+// Rule maps routes to the permission they require. Method is empty for any
+// method. Path uses OpenAPI templates ({guid}); a trailing "*" matches any
+// suffix, including none.
+type Rule struct {
+	Method     string
+	Path       string
+	Permission Permission
+}
+
+// Rules is the permission table for every protected route. The first rule
+// that matches a route applies, so specific rules precede their group's
+// catch-all.
+var Rules = []Rule{
+	// Device inventory
+	{http.MethodGet, "/api/v1/devices*", DevicesRead},
+	{"", "/api/v1/devices*", DevicesWrite},
+	{http.MethodGet, "/api/v1/authorize/redirection/{id}", DevicesControl},
+
+	// AMT device management
+	{http.MethodPost, "/api/v1/amt/boot/remoteErase/{guid}", DevicesErase},
+	{"", "/api/v1/amt/power/action/{guid}", DevicesControl},
+	{"", "/api/v1/amt/power/bootOptions/{guid}", DevicesControl},
+	{"", "/api/v1/amt/power/bootoptions/{guid}", DevicesControl},
+	// Requesting a code starts the opt-in on the device's screen.
+	{"", "/api/v1/amt/userConsentCode/*", DevicesControl},
+	{"", "/api/v1/amt/kvm/screenshot/{guid}", DevicesControl},
+	{"", "/api/v1/amt/sol/*", DevicesControl},
+	// Runs arbitrary WS-MAN calls, including ones that change settings.
+	{"", "/api/v1/amt/explorer/{guid}/{call}", DevicesConfigure},
+	{http.MethodGet, "/api/v1/amt/*", DevicesRead},
+	{"", "/api/v1/amt/*", DevicesConfigure},
+	{http.MethodGet, "/api/v2/amt/*", DevicesRead},
+	{"", "/api/v2/amt/*", DevicesConfigure},
+
+	// Console
+	{http.MethodGet, "/api/v1/ciracert", ConfigRead},
+	{http.MethodGet, "/api/v1/server/*", DevicesRead},
+
+	// Administration
+	{http.MethodGet, "/api/v1/admin/redirection/sessions*", SessionsRead},
+	{"", "/api/v1/admin/redirection/sessions*", SessionsManage},
+	// Reading accounts is as sensitive as changing them.
+	{"", "/api/v1/admin/users*", UsersManage},
+	{"", "/api/v1/admin/apikeys*", APIKeysManage},
+	{"", "/api/v1/admin/lockouts*", LockoutsManage},
+	{http.MethodGet, "/api/v1/admin/audit/verify", AuditVerify},
+	{"", "/api/v1/admin/audit*", AuditRead},
+	{"", "/api/v1/admin/accesspolicies*", PoliciesManage},
+	// Listing a group's members reads devices, within the caller's scope.
+	{http.MethodGet, "/api/v1/admin/groups/{id}/members", DevicesRead},
+	// Retagging selected devices edits them, within the caller's scope.
+	{http.MethodPost, "/api/v1/admin/tags/devices", DevicesWrite},
+	{"", "/api/v1/admin/backup", ConfigBackup},
+	{"", "/api/v1/admin/restore", ConfigBackup},
+	{http.MethodGet, "/api/v1/admin/*", ConfigRead},
+	{"", "/api/v1/admin/*", ConfigWrite},
+}
+
+// Required returns the permission a route needs. ok is false for routes the
+// table does not cover, which callers must deny.
+func Required(method, path string) (permission Permission, ok bool) {
+	for _, rule := range Rules {
+		if rule.Method != "" && rule.Method != method {
+			continue
+		}
+
+		if Match(rule.Path, path) {
+			return rule.Permission, true
+		}
+	}
+
+	return "", false
+}
+
+// Match reports whether path matches pattern, an OpenAPI template whose
+// trailing "*" matches any suffix.
+func Match(pattern, path string) bool {
+	if prefix, wildcard := strings.CutSuffix(pattern, "*"); wildcard {
+		return strings.HasPrefix(path, prefix)
+	}
+
+	return pattern == path
+}
+
+// Allowed reports whether any of roles grants permission.
+func Allowed(roles []Role, permission Permission) bool {
+	for _, role := range roles {
+		for _, granted := range rolePermissions[role] {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// RolesWith lists the roles that grant permission.
+func RolesWith(permission Permission) []Role {
+	var roles []Role
+
+	for _, role := range Roles {
+		if Allowed([]Role{role}, permission) {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+// IsDevicePermission reports whether permission is one of DevicePermissions.
+func IsDevicePermission(permission Permission) bool {
+	for _, p := range DevicePermissions {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseRole returns the role named name, matched case-insensitively.
+func ParseRole(name string) (Role, bool) {
+	for _, role := range Roles {
+		if strings.EqualFold(string(role), strings.TrimSpace(name)) {
+			return role, true
+		}
+	}
+
+	return "", false
+}
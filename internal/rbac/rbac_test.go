@@ -0,0 +1,109 @@
+package rbac
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		method string
+		path   string
+		want   Permission
+	}{
+		{http.MethodGet, "/api/v1/devices", DevicesRead},
+		{http.MethodGet, "/api/v1/devices/{guid}", DevicesRead},
+		{http.MethodDelete, "/api/v1/devices/{guid}", DevicesWrite},
+		{http.MethodGet, "/api/v1/authorize/redirection/{id}", DevicesControl},
+		{http.MethodPost, "/api/v1/amt/boot/remoteErase/{guid}", DevicesErase},
+		{http.MethodGet, "/api/v1/amt/boot/remoteErase/{guid}", DevicesRead},
+		{http.MethodPost, "/api/v1/amt/power/action/{guid}", DevicesControl},
+		{http.MethodGet, "/api/v1/amt/userConsentCode/{guid}", DevicesControl},
+		{http.MethodGet, "/api/v1/amt/explorer/{guid}/{call}", DevicesConfigure},
+		{http.MethodPatch, "/api/v1/amt/networkSettings/wired/{guid}", DevicesConfigure},
+		{http.MethodPost, "/api/v2/amt/features/{guid}", DevicesConfigure},
+		{http.MethodGet, "/api/v1/admin/domains", ConfigRead},
+		{http.MethodPost, "/api/v1/admin/domains", ConfigWrite},
+		{http.MethodDelete, "/api/v1/admin/redirection/sessions/{id}", SessionsManage},
+		{http.MethodGet, "/api/v1/admin/users", UsersManage},
+		{http.MethodDelete, "/api/v1/admin/users/{username}", UsersManage},
+		{http.MethodPost, "/api/v1/admin/apikeys", APIKeysManage},
+		{http.MethodGet, "/api/v1/admin/lockouts", LockoutsManage},
+		{http.MethodGet, "/api/v1/admin/audit/export", AuditRead},
+		{http.MethodGet, "/api/v1/admin/audit/verify", AuditVerify},
+		{http.MethodGet, "/api/v1/admin/accesspolicies", PoliciesManage},
+		{http.MethodPut, "/api/v1/admin/accesspolicies/{id}", PoliciesManage},
+		{http.MethodGet, "/api/v1/admin/groups/{id}/members", DevicesRead},
+		{http.MethodPost, "/api/v1/admin/groups/{id}/members", ConfigWrite},
+		{http.MethodPost, "/api/v1/admin/tags/devices", DevicesWrite},
+		{http.MethodPost, "/api/v1/admin/tags/merge", ConfigWrite},
+		{http.MethodPost, "/api/v1/admin/backup", ConfigBackup},
+		{http.MethodPost, "/api/v1/admin/restore", ConfigBackup},
+	}
+
+	for _, tc := range tests {
+		got, ok := Required(tc.method, tc.path)
+		require.True(t, ok, tc.method+" "+tc.path)
+		require.Equal(t, tc.want, got, tc.method+" "+tc.path)
+	}
+
+	_, ok := Required(http.MethodGet, "/api/v1/unknown")
+	require.False(t, ok)
+}
+
+func TestAllowed(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, Allowed([]Role{RoleAdmin}, DevicesErase))
+	require.False(t, Allowed([]Role{RoleOperator}, DevicesErase))
+	require.True(t, Allowed([]Role{RoleOperator}, DevicesConfigure))
+	require.True(t, Allowed([]Role{RoleHelpdesk}, DevicesControl))
+	require.False(t, Allowed([]Role{RoleHelpdesk}, DevicesConfigure))
+	require.False(t, Allowed([]Role{RoleReadOnly}, DevicesControl))
+	require.True(t, Allowed([]Role{RoleReadOnly, RoleHelpdesk}, DevicesControl))
+	require.False(t, Allowed(nil, DevicesRead))
+
+	require.Equal(t, []Role{RoleSuperAdmin, RoleAdmin}, RolesWith(SessionsManage))
+	require.Equal(t, []Role{RoleSuperAdmin, RoleAdmin}, RolesWith(UsersManage))
+	require.Equal(t, []Role{RoleSuperAdmin, RoleAdmin}, RolesWith(APIKeysManage))
+	require.Equal(t, []Role{RoleSuperAdmin, RoleAdmin}, RolesWith(LockoutsManage))
+	require.Equal(t, []Role{RoleSuperAdmin, RoleAdmin}, RolesWith(AuditRead))
+	require.Equal(t, []Role{RoleSuperAdmin}, RolesWith(AuditVerify))
+	require.Equal(t, []Role{RoleSuperAdmin, RoleAdmin}, RolesWith(PoliciesManage))
+	require.Equal(t, []Role{RoleSuperAdmin}, RolesWith(TenantsAll))
+
+	require.True(t, IsDevicePermission(DevicesControl))
+	require.False(t, IsDevicePermission(ConfigRead))
+}
+
+func TestParseRole(t *testing.T) {
+	t.Parallel()
+
+	role, ok := ParseRole(" Read-Only ")
+	require.True(t, ok)
+	require.Equal(t, RoleReadOnly, role)
+
+	role, ok = ParseRole("Super-Admin")
+	require.True(t, ok)
+	require.Equal(t, RoleSuperAdmin, role)
+
+	_, ok = ParseRole("")
+	require.False(t, ok)
+
+	_, ok = ParseRole("superuser")
+	require.False(t, ok)
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, Match("/api/v1/devices", "/api/v1/devices"))
+	require.False(t, Match("/api/v1/devices", "/api/v1/devices/{guid}"))
+	require.True(t, Match("/api/v1/amt/*", "/api/v1/amt/power/state/{guid}"))
+	require.True(t, Match("/api/v1/devices*", "/api/v1/devices"))
+	require.False(t, Match("/api/v1/amt/*", "/api/v1/admin/domains"))
+}
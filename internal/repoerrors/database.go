@@ -23,3 +23,9 @@ func (e DatabaseError) Wrap(call, function string, err error) error {
 
 	return e
 }
+
+// Unwrap exposes the driver error, so callers can tell a canceled or timed
+// out query from a failed one.
+func (e DatabaseError) Unwrap() error {
+	return e.Console.OriginalError
+}
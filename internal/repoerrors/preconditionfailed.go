@@ -0,0 +1,19 @@
+package repoerrors
+
+import "github.com/device-management-toolkit/console/pkg/consoleerrors"
+
+// PreconditionFailedError tells that an entity was not updated or deleted
+// because it is no longer at the version the request expected.
+type PreconditionFailedError struct {
+	Console consoleerrors.InternalError
+}
+
+func (e PreconditionFailedError) Error() string {
+	return e.Console.Error()
+}
+
+func (e PreconditionFailedError) Wrap(details string) error {
+	e.Console.Message = "precondition failed: " + details
+
+	return e
+}
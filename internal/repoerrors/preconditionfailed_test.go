@@ -0,0 +1,21 @@
+package repoerrors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+)
+
+func TestPreconditionFailedError_Wrap(t *testing.T) {
+	t.Parallel()
+
+	err := PreconditionFailedError{Console: consoleerrors.InternalError{}}
+
+	wrappedErr := err.Wrap("profile acm has changed")
+
+	var pfErr PreconditionFailedError
+	require.ErrorAs(t, wrappedErr, &pfErr)
+	require.Equal(t, "precondition failed: profile acm has changed", pfErr.Console.FriendlyMessage())
+}
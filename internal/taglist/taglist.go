@@ -0,0 +1,48 @@
+// Package taglist reads and writes the comma-joined tags devices are stored
+// with, so that every backend agrees on what a device's tags are.
+package taglist
+
+import (
+	"slices"
+	"strings"
+)
+
+// Split returns the tags of a comma-joined list, trimmed, each once, in the
+// order they first appear. Empty tags are dropped.
+func Split(value string) []string {
+	tags := []string{}
+
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// Join returns the comma-joined list of tags.
+func Join(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
+// Apply returns tags without those of remove, then with those of add it
+// does not have yet, appended in order. A tag both added and removed is
+// kept.
+func Apply(tags, add, remove []string) []string {
+	out := make([]string, 0, len(tags)+len(add))
+
+	for _, tag := range tags {
+		if !slices.Contains(remove, tag) || slices.Contains(add, tag) {
+			out = append(out, tag)
+		}
+	}
+
+	for _, tag := range add {
+		if !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+
+	return out
+}
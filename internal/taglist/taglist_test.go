@@ -0,0 +1,22 @@
+package taglist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"lab", "site-a"}, Split(" lab,,site-a, lab "))
+	require.Equal(t, []string{}, Split(""))
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"lab", "site-b"}, Apply([]string{"site-a", "lab"}, []string{"site-b"}, []string{"site-a"}))
+	require.Equal(t, []string{"lab", "site-a"}, Apply([]string{"lab"}, []string{"site-a", "lab"}, nil))
+	require.Equal(t, []string{"lab"}, Apply([]string{"lab"}, []string{"lab"}, []string{"lab"}), "a merge target stays")
+}
@@ -0,0 +1,24 @@
+// Package tenancy carries the tenant a request acts in through its context,
+// so usecases below the HTTP layer scope their repository calls to it
+// without each signature naming the caller.
+package tenancy
+
+import "context"
+
+// Default is the tenant of callers whose identity names none, and of data
+// stored before tenancy was enforced.
+const Default = ""
+
+type contextKey struct{}
+
+// WithTenant returns a copy of ctx acting in tenantID.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenantID)
+}
+
+// FromContext returns the tenant ctx acts in, or Default when none was set.
+func FromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(contextKey{}).(string)
+
+	return tenantID
+}
@@ -0,0 +1,18 @@
+package tenancy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, Default, FromContext(context.Background()))
+
+	ctx := WithTenant(context.Background(), "tenant-a")
+	require.Equal(t, "tenant-a", FromContext(ctx))
+	require.Equal(t, "tenant-b", FromContext(WithTenant(ctx, "tenant-b")))
+}
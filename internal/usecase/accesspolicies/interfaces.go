@@ -0,0 +1,31 @@
+package accesspolicies
+
+import (
+	"context"
+
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/rbac"
+)
+
+type (
+	Repository interface {
+		GetCount(ctx context.Context, tenantID string) (int, error)
+		Get(ctx context.Context, top, skip int, tenantID string) ([]entity.AccessPolicy, error)
+		GetByID(ctx context.Context, id, tenantID string) (*entity.AccessPolicy, error)
+		GetBySubjects(ctx context.Context, subjects []string, tenantID string) ([]entity.AccessPolicy, error)
+		Delete(ctx context.Context, id, tenantID string) (bool, error)
+		Update(ctx context.Context, p *entity.AccessPolicy) (bool, error)
+		Insert(ctx context.Context, p *entity.AccessPolicy) error
+	}
+	Feature interface {
+		GetCount(ctx context.Context, tenantID string) (int, error)
+		Get(ctx context.Context, top, skip int, tenantID string) ([]dto.AccessPolicy, error)
+		GetByID(ctx context.Context, id, tenantID string) (*dto.AccessPolicy, error)
+		Delete(ctx context.Context, id, tenantID string) error
+		Update(ctx context.Context, p *dto.AccessPolicy) (*dto.AccessPolicy, error)
+		Insert(ctx context.Context, p *dto.AccessPolicy) (*dto.AccessPolicy, error)
+		Scope(ctx context.Context, username string, roles []rbac.Role, permission rbac.Permission, tenantID string) (*devicescope.Scope, error)
+	}
+)
@@ -0,0 +1,295 @@
+package accesspolicies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+const (
+	subjectUser = "user:"
+	subjectRole = "role:"
+
+	methodAny = "any"
+	methodAll = "all"
+
+	// pageSize is the page size of the full scans of policies.
+	pageSize = 100
+)
+
+// UseCase -.
+type UseCase struct {
+	repo Repository
+	log  logger.Interface
+}
+
+var (
+	ErrAccessPoliciesUseCase = consoleerrors.CreateConsoleError("AccessPoliciesUseCase")
+	ErrDatabase              = repoerrors.DatabaseError{Console: ErrAccessPoliciesUseCase}
+	ErrNotFound              = repoerrors.NotFoundError{Console: ErrAccessPoliciesUseCase}
+	ErrNotValid              = dto.NotValidError{Console: ErrAccessPoliciesUseCase}
+
+	errInvalidSubject    = errors.New("subject must be user:<username> or role:<role>")
+	errUnknownRole       = errors.New("unknown role")
+	errInvalidTag        = errors.New("tags must be non-empty and must not contain commas")
+	errInvalidPermission = errors.New("permissions must be devices:read, devices:write, devices:control, devices:configure or devices:erase")
+)
+
+// New -.
+func New(r Repository, log logger.Interface) *UseCase {
+	return &UseCase{
+		repo: r,
+		log:  log,
+	}
+}
+
+func (uc *UseCase) GetCount(ctx context.Context, tenantID string) (int, error) {
+	count, err := uc.repo.GetCount(ctx, tenantID)
+	if err != nil {
+		return 0, ErrDatabase.Wrap("GetCount", "uc.repo.GetCount", err)
+	}
+
+	return count, nil
+}
+
+func (uc *UseCase) Get(ctx context.Context, top, skip int, tenantID string) ([]dto.AccessPolicy, error) {
+	data, err := uc.repo.Get(ctx, top, skip, tenantID)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Get", "uc.repo.Get", err)
+	}
+
+	d1 := make([]dto.AccessPolicy, len(data))
+
+	for i := range data {
+		d1[i] = *entityToDTO(&data[i])
+	}
+
+	return d1, nil
+}
+
+func (uc *UseCase) GetByID(ctx context.Context, id, tenantID string) (*dto.AccessPolicy, error) {
+	data, err := uc.repo.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("GetByID", "uc.repo.GetByID", err)
+	}
+
+	if data == nil {
+		return nil, ErrNotFound
+	}
+
+	return entityToDTO(data), nil
+}
+
+func (uc *UseCase) Delete(ctx context.Context, id, tenantID string) error {
+	isSuccessful, err := uc.repo.Delete(ctx, id, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Delete", "uc.repo.Delete", err)
+	}
+
+	if !isSuccessful {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Update replaces a policy's name, subject, tags, method and permissions.
+func (uc *UseCase) Update(ctx context.Context, d *dto.AccessPolicy) (*dto.AccessPolicy, error) {
+	p, err := dtoToEntity(d)
+	if err != nil {
+		return nil, ErrNotValid.Wrap("Update", "validate", err)
+	}
+
+	updated, err := uc.repo.Update(ctx, p)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Update", "uc.repo.Update", err)
+	}
+
+	if !updated {
+		return nil, ErrNotFound
+	}
+
+	return uc.GetByID(ctx, d.ID, d.TenantID)
+}
+
+func (uc *UseCase) Insert(ctx context.Context, d *dto.AccessPolicy) (*dto.AccessPolicy, error) {
+	p, err := dtoToEntity(d)
+	if err != nil {
+		return nil, ErrNotValid.Wrap("Insert", "validate", err)
+	}
+
+	p.ID = uuid.NewString()
+	p.CreationDate = time.Now().UTC().Format(time.RFC3339)
+	p.CreatedBy = d.CreatedBy
+
+	if err := uc.repo.Insert(ctx, p); err != nil {
+		return nil, ErrDatabase.Wrap("Insert", "uc.repo.Insert", err)
+	}
+
+	return entityToDTO(p), nil
+}
+
+// Scope returns the devices a caller may reach with permission. It is nil,
+// meaning every device, unless a policy names the user or one of their
+// roles; then it is the union of those policies that grant permission, and
+// empty if none does.
+func (uc *UseCase) Scope(ctx context.Context, username string, roles []rbac.Role, permission rbac.Permission, tenantID string) (*devicescope.Scope, error) {
+	subjects := make([]string, 0, len(roles)+1)
+
+	if username != "" {
+		subjects = append(subjects, subjectUser+username)
+	}
+
+	for _, role := range roles {
+		subjects = append(subjects, subjectRole+string(role))
+	}
+
+	policies, err := uc.repo.GetBySubjects(ctx, subjects, tenantID)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Scope", "uc.repo.GetBySubjects", err)
+	}
+
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	scope := &devicescope.Scope{Expressions: []devicescope.Expression{}}
+
+	for i := range policies {
+		permissions := splitList(policies[i].Permissions)
+		if len(permissions) > 0 && !slices.Contains(permissions, string(permission)) {
+			continue
+		}
+
+		scope.Expressions = append(scope.Expressions, devicescope.Expression{
+			Tags: splitList(policies[i].Tags),
+			All:  policies[i].Method == methodAll,
+		})
+	}
+
+	return scope, nil
+}
+
+// TagReferences lists, as "access policy <name>", the policies of tenantID
+// whose tag expression names any of tags.
+func (uc *UseCase) TagReferences(ctx context.Context, tags []string, tenantID string) ([]string, error) {
+	var references []string
+
+	for skip := 0; ; skip += pageSize {
+		page, err := uc.repo.Get(ctx, pageSize, skip, tenantID)
+		if err != nil {
+			return nil, ErrDatabase.Wrap("TagReferences", "uc.repo.Get", err)
+		}
+
+		for i := range page {
+			if slices.ContainsFunc(splitList(page[i].Tags), func(tag string) bool { return slices.Contains(tags, tag) }) {
+				references = append(references, "access policy "+page[i].Name)
+			}
+		}
+
+		if len(page) < pageSize {
+			return references, nil
+		}
+	}
+}
+
+// dtoToEntity validates d and normalizes a role subject's spelling, so that
+// lookups by subject match it exactly.
+func dtoToEntity(d *dto.AccessPolicy) (*entity.AccessPolicy, error) {
+	subject, err := normalizeSubject(d.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.Tags) == 0 {
+		return nil, errInvalidTag
+	}
+
+	for _, tag := range d.Tags {
+		if tag == "" || strings.Contains(tag, ",") {
+			return nil, fmt.Errorf("%w: %q", errInvalidTag, tag)
+		}
+	}
+
+	for _, permission := range d.Permissions {
+		if !rbac.IsDevicePermission(rbac.Permission(permission)) {
+			return nil, fmt.Errorf("%w: %q", errInvalidPermission, permission)
+		}
+	}
+
+	method := d.Method
+	if method == "" {
+		method = methodAny
+	}
+
+	return &entity.AccessPolicy{
+		ID:          d.ID,
+		Name:        d.Name,
+		Subject:     subject,
+		Tags:        strings.Join(d.Tags, ","),
+		Method:      method,
+		Permissions: strings.Join(d.Permissions, ","),
+		TenantID:    d.TenantID,
+	}, nil
+}
+
+func normalizeSubject(subject string) (string, error) {
+	if name, ok := strings.CutPrefix(subject, subjectUser); ok && strings.TrimSpace(name) != "" {
+		return subjectUser + strings.TrimSpace(name), nil
+	}
+
+	if name, ok := strings.CutPrefix(subject, subjectRole); ok {
+		role, known := rbac.ParseRole(name)
+		if !known {
+			return "", fmt.Errorf("%w %q", errUnknownRole, name)
+		}
+
+		return subjectRole + string(role), nil
+	}
+
+	return "", fmt.Errorf("%w: %q", errInvalidSubject, subject)
+}
+
+func entityToDTO(p *entity.AccessPolicy) *dto.AccessPolicy {
+	return &dto.AccessPolicy{
+		ID:           p.ID,
+		Name:         p.Name,
+		Subject:      p.Subject,
+		Tags:         splitList(p.Tags),
+		Method:       p.Method,
+		Permissions:  splitList(p.Permissions),
+		CreationDate: parseTime(p.CreationDate),
+		CreatedBy:    p.CreatedBy,
+		TenantID:     p.TenantID,
+	}
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
+
+func parseTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+
+	return &t
+}
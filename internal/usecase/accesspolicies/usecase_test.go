@@ -0,0 +1,162 @@
+package accesspolicies_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/internal/usecase/accesspolicies"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func accessPoliciesTest(t *testing.T) (*accesspolicies.UseCase, *mocks.MockAccessPoliciesRepository) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	repo := mocks.NewMockAccessPoliciesRepository(mockCtl)
+
+	return accesspolicies.New(repo, logger.New("error")), repo
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := accessPoliciesTest(t)
+
+	var stored *entity.AccessPolicy
+
+	repo.EXPECT().
+		Insert(context.Background(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, p *entity.AccessPolicy) error {
+			stored = p
+
+			return nil
+		})
+
+	created, err := useCase.Insert(context.Background(), &dto.AccessPolicy{
+		Name:        "site-a-helpdesk",
+		Subject:     "role:Helpdesk",
+		Tags:        []string{"site-a", "rack-1"},
+		Permissions: []string{"devices:read", "devices:control"},
+		CreatedBy:   "admin",
+		TenantID:    "tenant-a",
+	})
+	require.NoError(t, err)
+
+	require.NotEmpty(t, stored.ID)
+	require.Equal(t, "role:helpdesk", stored.Subject)
+	require.Equal(t, "site-a,rack-1", stored.Tags)
+	require.Equal(t, "any", stored.Method)
+	require.Equal(t, "devices:read,devices:control", stored.Permissions)
+	require.Equal(t, "tenant-a", stored.TenantID)
+
+	require.Equal(t, stored.ID, created.ID)
+	require.Equal(t, []string{"site-a", "rack-1"}, created.Tags)
+	require.NotNil(t, created.CreationDate)
+}
+
+func TestInsertInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy dto.AccessPolicy
+	}{
+		{"unknown subject kind", dto.AccessPolicy{Subject: "group:lab", Tags: []string{"a"}}},
+		{"empty user", dto.AccessPolicy{Subject: "user:", Tags: []string{"a"}}},
+		{"unknown role", dto.AccessPolicy{Subject: "role:root", Tags: []string{"a"}}},
+		{"no tags", dto.AccessPolicy{Subject: "user:alice"}},
+		{"comma in tag", dto.AccessPolicy{Subject: "user:alice", Tags: []string{"a,b"}}},
+		{"non-device permission", dto.AccessPolicy{Subject: "user:alice", Tags: []string{"a"}, Permissions: []string{"users:manage"}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, _ := accessPoliciesTest(t)
+
+			_, err := useCase.Insert(context.Background(), &tc.policy)
+
+			var notValid dto.NotValidError
+			require.ErrorAs(t, err, &notValid)
+		})
+	}
+}
+
+func TestUpdateAndDeleteNotFound(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := accessPoliciesTest(t)
+
+	repo.EXPECT().Update(context.Background(), gomock.Any()).Return(false, nil)
+	repo.EXPECT().Delete(context.Background(), "missing", "").Return(false, nil)
+
+	_, err := useCase.Update(context.Background(), &dto.AccessPolicy{ID: "missing", Subject: "user:alice", Tags: []string{"a"}})
+	require.ErrorIs(t, err, accesspolicies.ErrNotFound)
+
+	err = useCase.Delete(context.Background(), "missing", "")
+	require.ErrorIs(t, err, accesspolicies.ErrNotFound)
+}
+
+func TestScope(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := accessPoliciesTest(t)
+	ctx := context.Background()
+	subjects := []string{"user:alice", "role:helpdesk"}
+
+	repo.EXPECT().GetBySubjects(ctx, subjects, "tenant-a").Return([]entity.AccessPolicy{
+		{Subject: "user:alice", Tags: "site-a", Method: "any"},
+		{Subject: "role:helpdesk", Tags: "site-b,lab", Method: "all", Permissions: "devices:read"},
+	}, nil).Times(2)
+
+	scope, err := useCase.Scope(ctx, "alice", []rbac.Role{rbac.RoleHelpdesk}, rbac.DevicesRead, "tenant-a")
+	require.NoError(t, err)
+	require.Equal(t, &devicescope.Scope{Expressions: []devicescope.Expression{
+		{Tags: []string{"site-a"}},
+		{Tags: []string{"site-b", "lab"}, All: true},
+	}}, scope)
+
+	scope, err = useCase.Scope(ctx, "alice", []rbac.Role{rbac.RoleHelpdesk}, rbac.DevicesControl, "tenant-a")
+	require.NoError(t, err)
+	require.Equal(t, &devicescope.Scope{Expressions: []devicescope.Expression{
+		{Tags: []string{"site-a"}},
+	}}, scope, "policies limited to other permissions do not widen the scope")
+}
+
+func TestScopeUnrestricted(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := accessPoliciesTest(t)
+
+	repo.EXPECT().GetBySubjects(context.Background(), []string{"user:bob", "role:admin"}, "").Return([]entity.AccessPolicy{}, nil)
+
+	scope, err := useCase.Scope(context.Background(), "bob", []rbac.Role{rbac.RoleAdmin}, rbac.DevicesErase, "")
+	require.NoError(t, err)
+	require.Nil(t, scope, "callers no policy names reach every device")
+}
+
+func TestTagReferences(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := accessPoliciesTest(t)
+	ctx := context.Background()
+
+	repo.EXPECT().Get(ctx, 100, 0, "tenant").Return([]entity.AccessPolicy{
+		{Name: "site-a", Tags: "site-a,lab"},
+		{Name: "site-b", Tags: "site-b"},
+	}, nil)
+
+	references, err := useCase.TagReferences(ctx, []string{"lab", "kiosk"}, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, []string{"access policy site-a"}, references)
+}
@@ -0,0 +1,59 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// Prefix marks a bearer credential as an API key rather than a JWT. A key is
+// Prefix + ID + "_" + secret.
+const Prefix = "dmt_"
+
+const (
+	idBytes     = 8
+	secretBytes = 32
+)
+
+// IsKey reports whether a bearer credential is an API key.
+func IsKey(credential string) bool {
+	return strings.HasPrefix(credential, Prefix)
+}
+
+// generate returns a new key ID and the full key to hand to the client.
+func generate() (id, key string, err error) {
+	raw := make([]byte, idBytes+secretBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+
+	id = hex.EncodeToString(raw[:idBytes])
+	key = Prefix + id + "_" + base64.RawURLEncoding.EncodeToString(raw[idBytes:])
+
+	return id, key, nil
+}
+
+// parse splits a key into its ID and secret.
+func parse(key string) (id, secret string, ok bool) {
+	rest, ok := strings.CutPrefix(key, Prefix)
+	if !ok {
+		return "", "", false
+	}
+
+	id, secret, ok = strings.Cut(rest, "_")
+	if !ok || len(id) != hex.EncodedLen(idBytes) || secret == "" {
+		return "", "", false
+	}
+
+	return id, secret, true
+}
+
+// hash digests a secret. Secrets are 256 random bits, so a fast hash is
+// enough; a password hash would only slow down every request.
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
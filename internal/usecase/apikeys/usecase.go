@@ -0,0 +1,260 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// lastUsedResolution limits last-used writes to one per key per minute, so a
+// busy pipeline does not write on every request.
+const lastUsedResolution = time.Minute
+
+// UseCase -.
+type UseCase struct {
+	repo    Repository
+	devices devices.Repository
+	log     logger.Interface
+}
+
+var (
+	ErrAPIKeysUseCase = consoleerrors.CreateConsoleError("APIKeysUseCase")
+	ErrDatabase       = repoerrors.DatabaseError{Console: ErrAPIKeysUseCase}
+	ErrNotFound       = repoerrors.NotFoundError{Console: ErrAPIKeysUseCase}
+	ErrNotValid       = dto.NotValidError{Console: ErrAPIKeysUseCase}
+
+	ErrInvalidKey = errors.New("invalid API key")
+	ErrKeyExpired = errors.New("API key has expired")
+
+	errUnknownRole  = errors.New("unknown role")
+	errInvalidRoute = errors.New("routes must be API paths starting with /api/")
+	errInvalidTag   = errors.New("tags must be non-empty and must not contain commas")
+	errExpiryPassed = errors.New("expiresAt must be in the future")
+)
+
+// New -.
+func New(r Repository, d devices.Repository, log logger.Interface) *UseCase {
+	return &UseCase{
+		repo:    r,
+		devices: d,
+		log:     log,
+	}
+}
+
+func (uc *UseCase) GetCount(ctx context.Context, tenantID string) (int, error) {
+	count, err := uc.repo.GetCount(ctx, tenantID)
+	if err != nil {
+		return 0, ErrDatabase.Wrap("GetCount", "uc.repo.GetCount", err)
+	}
+
+	return count, nil
+}
+
+func (uc *UseCase) Get(ctx context.Context, top, skip int, tenantID string) ([]dto.APIKey, error) {
+	data, err := uc.repo.Get(ctx, top, skip, tenantID)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Get", "uc.repo.Get", err)
+	}
+
+	d1 := make([]dto.APIKey, len(data))
+
+	for i := range data {
+		d1[i] = *entityToDTO(&data[i])
+	}
+
+	return d1, nil
+}
+
+// GetByID hides keys of other tenants as if they did not exist.
+func (uc *UseCase) GetByID(ctx context.Context, id, tenantID string) (*dto.APIKey, error) {
+	data, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("GetByID", "uc.repo.GetByID", err)
+	}
+
+	if data == nil || data.TenantID != tenantID {
+		return nil, ErrNotFound
+	}
+
+	return entityToDTO(data), nil
+}
+
+// Delete revokes a key. It stops working immediately.
+func (uc *UseCase) Delete(ctx context.Context, id, tenantID string) error {
+	isSuccessful, err := uc.repo.Delete(ctx, id, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Delete", "uc.repo.Delete", err)
+	}
+
+	if !isSuccessful {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Insert issues a key. The returned secret is not stored and cannot be
+// retrieved again.
+func (uc *UseCase) Insert(ctx context.Context, d *dto.APIKey) (*dto.APIKeyCreated, error) {
+	if err := validate(d); err != nil {
+		return nil, ErrNotValid.Wrap("Insert", "validate", err)
+	}
+
+	id, key, err := generate()
+	if err != nil {
+		return nil, fmt.Errorf("apikeys - Insert - generate: %w", err)
+	}
+
+	_, secret, _ := parse(key)
+	role, _ := rbac.ParseRole(d.Role)
+
+	k := &entity.APIKey{
+		ID:           id,
+		Name:         d.Name,
+		KeyHash:      hash(secret),
+		Role:         string(role),
+		Routes:       strings.Join(d.Routes, ","),
+		Tags:         strings.Join(d.Tags, ","),
+		CreationDate: time.Now().UTC().Format(time.RFC3339),
+		CreatedBy:    d.CreatedBy,
+		TenantID:     d.TenantID,
+	}
+
+	if d.ExpiresAt != nil {
+		k.ExpiresAt = d.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	if _, err := uc.repo.Insert(ctx, k); err != nil {
+		return nil, ErrDatabase.Wrap("Insert", "uc.repo.Insert", err)
+	}
+
+	return &dto.APIKeyCreated{APIKey: *entityToDTO(k), Key: key}, nil
+}
+
+// Authenticate resolves a presented key. It returns ErrInvalidKey for a
+// malformed, unknown or revoked key and ErrKeyExpired once it has expired.
+func (uc *UseCase) Authenticate(ctx context.Context, key string) (*dto.APIKey, error) {
+	id, secret, ok := parse(key)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+
+	k, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Authenticate", "uc.repo.GetByID", err)
+	}
+
+	if k == nil || subtle.ConstantTimeCompare([]byte(hash(secret)), []byte(k.KeyHash)) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	now := time.Now().UTC()
+
+	if expiresAt := parseTime(k.ExpiresAt); expiresAt != nil && !now.Before(*expiresAt) {
+		return nil, ErrKeyExpired
+	}
+
+	if lastUsed := parseTime(k.LastUsedAt); lastUsed == nil || now.Sub(*lastUsed) >= lastUsedResolution {
+		k.LastUsedAt = now.Format(time.RFC3339)
+
+		// Not fatal: a failed write only leaves the timestamp stale.
+		if _, err := uc.repo.UpdateLastUsed(ctx, k.ID, k.LastUsedAt); err != nil {
+			uc.log.Warn("apikeys - Authenticate - failed to record last use of key " + k.ID + ": " + err.Error())
+		}
+	}
+
+	return entityToDTO(k), nil
+}
+
+// DeviceInScope reports whether a key may reach a device. Keys without tags
+// reach every device; others only devices carrying one of their tags.
+func (uc *UseCase) DeviceInScope(ctx context.Context, k *dto.APIKey, guid string) (bool, error) {
+	if len(k.Tags) == 0 {
+		return true, nil
+	}
+
+	device, err := uc.devices.GetByID(ctx, guid, k.TenantID)
+	if err != nil {
+		return false, ErrDatabase.Wrap("DeviceInScope", "uc.devices.GetByID", err)
+	}
+
+	if device == nil {
+		return false, nil
+	}
+
+	for _, tag := range splitList(device.Tags) {
+		if slices.Contains(k.Tags, tag) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func validate(d *dto.APIKey) error {
+	if _, ok := rbac.ParseRole(d.Role); !ok {
+		return fmt.Errorf("%w %q (want super-admin, admin, operator, helpdesk or read-only)", errUnknownRole, d.Role)
+	}
+
+	for _, route := range d.Routes {
+		if !strings.HasPrefix(route, "/api/") || strings.Contains(route, ",") {
+			return fmt.Errorf("%w: %q", errInvalidRoute, route)
+		}
+	}
+
+	for _, tag := range d.Tags {
+		if tag == "" || strings.Contains(tag, ",") {
+			return fmt.Errorf("%w: %q", errInvalidTag, tag)
+		}
+	}
+
+	if d.ExpiresAt != nil && !d.ExpiresAt.After(time.Now()) {
+		return errExpiryPassed
+	}
+
+	return nil
+}
+
+func entityToDTO(k *entity.APIKey) *dto.APIKey {
+	return &dto.APIKey{
+		ID:           k.ID,
+		Name:         k.Name,
+		Role:         k.Role,
+		Routes:       splitList(k.Routes),
+		Tags:         splitList(k.Tags),
+		ExpiresAt:    parseTime(k.ExpiresAt),
+		LastUsedAt:   parseTime(k.LastUsedAt),
+		CreationDate: parseTime(k.CreationDate),
+		CreatedBy:    k.CreatedBy,
+		TenantID:     k.TenantID,
+	}
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
+
+func parseTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+
+	return &t
+}
@@ -0,0 +1,222 @@
+package apikeys_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/apikeys"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func apiKeysTest(t *testing.T) (*apikeys.UseCase, *mocks.MockAPIKeysRepository, *mocks.MockDeviceManagementRepository) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	repo := mocks.NewMockAPIKeysRepository(mockCtl)
+	devices := mocks.NewMockDeviceManagementRepository(mockCtl)
+
+	return apikeys.New(repo, devices, logger.New("error")), repo, devices
+}
+
+func sha256Hex(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _ := apiKeysTest(t)
+
+	var stored *entity.APIKey
+
+	repo.EXPECT().
+		Insert(context.Background(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, k *entity.APIKey) (string, error) {
+			stored = k
+
+			return "", nil
+		})
+
+	expires := time.Now().Add(24 * time.Hour)
+
+	created, err := useCase.Insert(context.Background(), &dto.APIKey{
+		Name:      "ci",
+		Role:      "Operator",
+		Routes:    []string{"/api/v1/amt/power/*"},
+		Tags:      []string{"lab", "ci"},
+		ExpiresAt: &expires,
+		CreatedBy: "admin",
+	})
+	require.NoError(t, err)
+
+	require.True(t, apikeys.IsKey(created.Key))
+	require.Contains(t, created.Key, created.ID)
+	require.Equal(t, "operator", created.Role)
+	require.Equal(t, []string{"lab", "ci"}, created.Tags)
+
+	// Only a digest of the secret is stored.
+	secret := created.Key[strings.LastIndex(created.Key, created.ID)+len(created.ID)+1:]
+	require.Equal(t, sha256Hex(secret), stored.KeyHash)
+	require.NotContains(t, stored.KeyHash, secret)
+	require.Equal(t, "lab,ci", stored.Tags)
+}
+
+func TestInsert_Rejected(t *testing.T) {
+	t.Parallel()
+
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		key  dto.APIKey
+	}{
+		{name: "unknown role", key: dto.APIKey{Name: "ci", Role: "root"}},
+		{name: "route outside the API", key: dto.APIKey{Name: "ci", Role: "admin", Routes: []string{"/metrics"}}},
+		{name: "tag with comma", key: dto.APIKey{Name: "ci", Role: "admin", Tags: []string{"a,b"}}},
+		{name: "expiry in the past", key: dto.APIKey{Name: "ci", Role: "admin", ExpiresAt: &past}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, _, _ := apiKeysTest(t)
+
+			_, err := useCase.Insert(context.Background(), &tc.key)
+
+			var notValid dto.NotValidError
+			require.ErrorAs(t, err, &notValid)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	const (
+		id     = "3f2a9c1b7d4e8a60"
+		secret = "c2VjcmV0"
+		key    = apikeys.Prefix + id + "_" + secret
+	)
+
+	recent := time.Now().UTC().Add(-10 * time.Second).Format(time.RFC3339)
+	stale := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+	past := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+
+	tests := []struct {
+		name       string
+		key        string
+		stored     *entity.APIKey
+		lookup     bool
+		recordsUse bool
+		err        error
+	}{
+		{
+			name:       "valid key records its use",
+			key:        key,
+			stored:     &entity.APIKey{ID: id, KeyHash: sha256Hex(secret), Role: "operator", LastUsedAt: stale},
+			lookup:     true,
+			recordsUse: true,
+		},
+		{
+			name:   "recent use is not rewritten",
+			key:    key,
+			stored: &entity.APIKey{ID: id, KeyHash: sha256Hex(secret), Role: "operator", LastUsedAt: recent},
+			lookup: true,
+		},
+		{
+			name: "malformed key",
+			key:  apikeys.Prefix + "short",
+			err:  apikeys.ErrInvalidKey,
+		},
+		{
+			name:   "revoked key",
+			key:    key,
+			lookup: true,
+			err:    apikeys.ErrInvalidKey,
+		},
+		{
+			name:   "wrong secret",
+			key:    key,
+			stored: &entity.APIKey{ID: id, KeyHash: sha256Hex("other"), Role: "operator"},
+			lookup: true,
+			err:    apikeys.ErrInvalidKey,
+		},
+		{
+			name:   "expired key",
+			key:    key,
+			stored: &entity.APIKey{ID: id, KeyHash: sha256Hex(secret), Role: "operator", ExpiresAt: past},
+			lookup: true,
+			err:    apikeys.ErrKeyExpired,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, repo, _ := apiKeysTest(t)
+
+			if tc.lookup {
+				repo.EXPECT().GetByID(context.Background(), id).Return(tc.stored, nil)
+			}
+
+			if tc.recordsUse {
+				repo.EXPECT().UpdateLastUsed(context.Background(), id, gomock.Any()).Return(true, nil)
+			}
+
+			got, err := useCase.Authenticate(context.Background(), tc.key)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "operator", got.Role)
+			require.NotNil(t, got.LastUsedAt)
+		})
+	}
+}
+
+func TestDeviceInScope(t *testing.T) {
+	t.Parallel()
+
+	useCase, _, devices := apiKeysTest(t)
+
+	devices.EXPECT().GetByID(context.Background(), "in", "").Return(&entity.Device{GUID: "in", Tags: "office,lab"}, nil)
+	devices.EXPECT().GetByID(context.Background(), "out", "").Return(&entity.Device{GUID: "out", Tags: "office"}, nil)
+	devices.EXPECT().GetByID(context.Background(), "missing", "").Return(nil, nil)
+
+	key := &dto.APIKey{Tags: []string{"lab"}}
+
+	ok, err := useCase.DeviceInScope(context.Background(), key, "in")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = useCase.DeviceInScope(context.Background(), key, "out")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = useCase.DeviceInScope(context.Background(), key, "missing")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// Keys without tags reach every device without a lookup.
+	ok, err = useCase.DeviceInScope(context.Background(), &dto.APIKey{}, "any")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
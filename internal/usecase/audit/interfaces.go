@@ -0,0 +1,24 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+type (
+	Repository interface {
+		GetCount(ctx context.Context, filter entity.AuditFilter) (int, error)
+		Get(ctx context.Context, top, skip int, filter entity.AuditFilter) ([]entity.AuditEvent, error)
+		GetAfter(ctx context.Context, seq int64, limit int) ([]entity.AuditEvent, error)
+		GetLast(ctx context.Context) (*entity.AuditEvent, error)
+		Insert(ctx context.Context, e *entity.AuditEvent) error
+	}
+	Feature interface {
+		Record(ctx context.Context, e *dto.AuditEvent) error
+		GetCount(ctx context.Context, filter dto.AuditFilter) (int, error)
+		Get(ctx context.Context, top, skip int, filter dto.AuditFilter) ([]dto.AuditEvent, error)
+		Verify(ctx context.Context) (*dto.AuditVerification, error)
+	}
+)
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	redacted = "[redacted]"
+
+	// maxValueLength elides long values such as certificates, which are
+	// rarely useful in the trail and bloat it.
+	maxValueLength = 256
+)
+
+// secretKeyParts mark a parameter as secret when its name contains one of
+// them, in any case.
+var secretKeyParts = []string{"password", "passphrase", "secret", "token", "psk", "privatekey", "credential"}
+
+// secretKeys are secret parameter names too generic to match by part.
+var secretKeys = map[string]bool{
+	"key":              true,
+	"derkey":           true,
+	"provisioningcert": true,
+	"authorization":    true,
+}
+
+// Redact returns a copy of params with the string values of secret-looking
+// keys replaced, at any depth, and long strings elided.
+func Redact(params map[string]interface{}) map[string]interface{} {
+	if params == nil {
+		return nil
+	}
+
+	out := make(map[string]interface{}, len(params))
+
+	for k, v := range params {
+		if s, ok := v.(string); ok && s != "" && isSecret(k) {
+			out[k] = redacted
+
+			continue
+		}
+
+		out[k] = redactValue(v)
+	}
+
+	return out
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return Redact(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i := range v {
+			out[i] = redactValue(v[i])
+		}
+
+		return out
+	case string:
+		if len(v) > maxValueLength {
+			return "[elided: " + strconv.Itoa(len(v)) + " bytes]"
+		}
+
+		return v
+	default:
+		return v
+	}
+}
+
+func isSecret(key string) bool {
+	k := strings.ToLower(key)
+	if secretKeys[k] {
+		return true
+	}
+
+	for _, part := range secretKeyParts {
+		if strings.Contains(k, part) {
+			return true
+		}
+	}
+
+	return false
+}
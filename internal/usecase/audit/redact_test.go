@@ -0,0 +1,48 @@
+package audit_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/usecase/audit"
+)
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	params := map[string]interface{}{
+		"path": map[string]interface{}{"guid": "guid-1"},
+		"body": map[string]interface{}{
+			"profileName":    "office",
+			"amtPassword":    "P@ssw0rd",
+			"pskValue":       "secret-psk",
+			"Key":            "abc",
+			"wifiConfigs":    []interface{}{map[string]interface{}{"passphrase": "wifi-pass", "priority": float64(1)}},
+			"activation":     "acmactivate",
+			"certificate":    strings.Repeat("A", 300),
+			"generateRandom": true,
+			"mebxPassword":   "",
+		},
+	}
+
+	got := audit.Redact(params)
+
+	require.Equal(t, map[string]interface{}{
+		"path": map[string]interface{}{"guid": "guid-1"},
+		"body": map[string]interface{}{
+			"profileName":    "office",
+			"amtPassword":    "[redacted]",
+			"pskValue":       "[redacted]",
+			"Key":            "[redacted]",
+			"wifiConfigs":    []interface{}{map[string]interface{}{"passphrase": "[redacted]", "priority": float64(1)}},
+			"activation":     "acmactivate",
+			"certificate":    "[elided: 300 bytes]",
+			"generateRandom": true,
+			"mebxPassword":   "",
+		},
+	}, got)
+	require.Equal(t, "P@ssw0rd", params["body"].(map[string]interface{})["amtPassword"], "the input is not modified")
+	require.Nil(t, audit.Redact(nil))
+}
@@ -0,0 +1,318 @@
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// Results of an audited operation.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	ResultDenied  = "denied"
+)
+
+const (
+	// timestampLayout has a fixed width, so stored timestamps sort as strings.
+	timestampLayout = "2006-01-02T15:04:05.000Z"
+
+	// insertAttempts bounds retries when another console instance appends
+	// the same sequence number first.
+	insertAttempts = 3
+
+	verifyPageSize = 500
+
+	// keyIDLength is how many hex digits of a key's SHA-256 name it in the
+	// hashes it signs.
+	keyIDLength = 8
+)
+
+var (
+	ErrAuditUseCase = consoleerrors.CreateConsoleError("AuditUseCase")
+	ErrDatabase     = repoerrors.DatabaseError{Console: ErrAuditUseCase}
+)
+
+// UseCase appends to and reads the audit trail. Hashes are HMACs keyed with
+// the audit key, so someone who can write to the database but not read the
+// secret store cannot forge them. Each hash names the key that signed it:
+// after a rotation, entries signed with a previous key still verify while
+// that key is kept.
+type UseCase struct {
+	repo Repository
+	log  logger.Interface
+
+	// keyID names key, which signs new entries; keys holds it and the
+	// previous keys by ID, for Verify.
+	key   []byte
+	keyID string
+	keys  map[string][]byte
+
+	// mu serialises appends, which each extend the chain from the last
+	// entry. lastSeq and lastHash cache it once loaded.
+	mu       sync.Mutex
+	loaded   bool
+	lastSeq  int64
+	lastHash string
+}
+
+// New -. Key signs new entries; previousKeys only verify older ones.
+func New(r Repository, log logger.Interface, key string, previousKeys []string) *UseCase {
+	if key == "" {
+		log.Warn("audit - New - no audit key: the audit trail can be rewritten without detection")
+	}
+
+	uc := &UseCase{
+		repo:  r,
+		log:   log,
+		key:   []byte(key),
+		keyID: keyID(key),
+		keys:  map[string][]byte{},
+	}
+
+	for _, k := range previousKeys {
+		uc.keys[keyID(k)] = []byte(k)
+	}
+
+	uc.keys[uc.keyID] = uc.key
+
+	return uc
+}
+
+// Record appends e to the audit trail, redacting secrets from its parameters.
+// Seq, Timestamp, PrevHash and Hash are set on e.
+func (uc *UseCase) Record(ctx context.Context, e *dto.AuditEvent) error {
+	e.Parameters = Redact(e.Parameters)
+
+	params := ""
+
+	if len(e.Parameters) > 0 {
+		b, err := json.Marshal(e.Parameters)
+		if err != nil {
+			return fmt.Errorf("audit - Record - json.Marshal: %w", err)
+		}
+
+		params = string(b)
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	var err error
+
+	for range insertAttempts {
+		if !uc.loaded {
+			if err = uc.load(ctx); err != nil {
+				return err
+			}
+		}
+
+		ev := &entity.AuditEvent{
+			Seq:        uc.lastSeq + 1,
+			Timestamp:  time.Now().UTC().Format(timestampLayout),
+			Actor:      e.Actor,
+			TenantID:   e.TenantID,
+			Action:     e.Action,
+			Target:     e.Target,
+			Parameters: params,
+			Result:     e.Result,
+			Status:     e.Status,
+			SourceIP:   e.SourceIP,
+			PrevHash:   uc.lastHash,
+		}
+		ev.Hash = sign(uc.keyID, uc.key, ev)
+
+		err = uc.repo.Insert(ctx, ev)
+		if err == nil {
+			uc.lastSeq, uc.lastHash = ev.Seq, ev.Hash
+			*e = *entityToDTO(ev)
+
+			return nil
+		}
+
+		// Another instance appended first: continue from its entry.
+		var notUnique repoerrors.NotUniqueError
+		if !errors.As(err, &notUnique) {
+			break
+		}
+
+		uc.loaded = false
+	}
+
+	uc.loaded = false
+
+	return ErrDatabase.Wrap("Record", "uc.repo.Insert", err)
+}
+
+func (uc *UseCase) load(ctx context.Context) error {
+	last, err := uc.repo.GetLast(ctx)
+	if err != nil {
+		return ErrDatabase.Wrap("Record", "uc.repo.GetLast", err)
+	}
+
+	uc.lastSeq, uc.lastHash = 0, ""
+
+	if last != nil {
+		uc.lastSeq, uc.lastHash = last.Seq, last.Hash
+	}
+
+	uc.loaded = true
+
+	return nil
+}
+
+func (uc *UseCase) GetCount(ctx context.Context, filter dto.AuditFilter) (int, error) {
+	count, err := uc.repo.GetCount(ctx, filterToEntity(filter))
+	if err != nil {
+		return 0, ErrDatabase.Wrap("GetCount", "uc.repo.GetCount", err)
+	}
+
+	return count, nil
+}
+
+// Get returns matching entries, oldest first.
+func (uc *UseCase) Get(ctx context.Context, top, skip int, filter dto.AuditFilter) ([]dto.AuditEvent, error) {
+	data, err := uc.repo.Get(ctx, top, skip, filterToEntity(filter))
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Get", "uc.repo.Get", err)
+	}
+
+	d1 := make([]dto.AuditEvent, len(data))
+
+	for i := range data {
+		d1[i] = *entityToDTO(&data[i])
+	}
+
+	return d1, nil
+}
+
+// Verify walks the whole trail, across tenants, checking that entries are
+// numbered without gaps, that each links to the one before, and that each
+// hash matches its contents under the key it names.
+func (uc *UseCase) Verify(ctx context.Context) (*dto.AuditVerification, error) {
+	v := &dto.AuditVerification{Valid: true}
+
+	for {
+		page, err := uc.repo.GetAfter(ctx, v.Count, verifyPageSize)
+		if err != nil {
+			return nil, ErrDatabase.Wrap("Verify", "uc.repo.GetAfter", err)
+		}
+
+		for i := range page {
+			e := &page[i]
+
+			switch {
+			case e.Seq != v.Count+1:
+				return broken(v, v.Count+1, "entry missing"), nil
+			case e.PrevHash != v.LastHash:
+				return broken(v, e.Seq, "previous hash mismatch"), nil
+			case !uc.verifies(e):
+				return broken(v, e.Seq, "hash mismatch"), nil
+			}
+
+			v.Count, v.LastHash = e.Seq, e.Hash
+		}
+
+		if len(page) < verifyPageSize {
+			return v, nil
+		}
+	}
+}
+
+func broken(v *dto.AuditVerification, seq int64, reason string) *dto.AuditVerification {
+	v.Valid = false
+	v.BrokenAt = seq
+	v.Reason = reason
+
+	return v
+}
+
+// verifies tells whether e's hash is right under the key it names. A key
+// that is not known any more fails like a wrong hash.
+func (uc *UseCase) verifies(e *entity.AuditEvent) bool {
+	id, _, _ := strings.Cut(e.Hash, ":")
+
+	key, ok := uc.keys[id]
+
+	return ok && hmac.Equal([]byte(sign(id, key, e)), []byte(e.Hash))
+}
+
+// sign returns the HMAC-SHA256 of every field of e but Hash itself, keyed
+// with key and prefixed with the key's ID.
+func sign(id string, key []byte, e *entity.AuditEvent) string {
+	// A JSON array keeps field boundaries unambiguous.
+	b, _ := json.Marshal([]interface{}{
+		e.Seq, e.Timestamp, e.Actor, e.TenantID, e.Action, e.Target,
+		e.Parameters, e.Result, e.Status, e.SourceIP, e.PrevHash,
+	})
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(b)
+
+	return id + ":" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// keyID names a key by the start of its SHA-256, which does not reveal it.
+func keyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])[:keyIDLength]
+}
+
+func filterToEntity(f dto.AuditFilter) entity.AuditFilter {
+	e := entity.AuditFilter{
+		TenantID: f.TenantID,
+		Actor:    f.Actor,
+		Action:   f.Action,
+		Target:   f.Target,
+		Result:   f.Result,
+	}
+
+	if !f.From.IsZero() {
+		e.From = f.From.UTC().Format(timestampLayout)
+	}
+
+	if !f.To.IsZero() {
+		e.To = f.To.UTC().Format(timestampLayout)
+	}
+
+	return e
+}
+
+func entityToDTO(e *entity.AuditEvent) *dto.AuditEvent {
+	d := &dto.AuditEvent{
+		Seq:      e.Seq,
+		Actor:    e.Actor,
+		TenantID: e.TenantID,
+		Action:   e.Action,
+		Target:   e.Target,
+		Result:   e.Result,
+		Status:   e.Status,
+		SourceIP: e.SourceIP,
+		PrevHash: e.PrevHash,
+		Hash:     e.Hash,
+	}
+
+	d.Timestamp, _ = time.Parse(timestampLayout, e.Timestamp)
+
+	if e.Parameters != "" {
+		if err := json.Unmarshal([]byte(e.Parameters), &d.Parameters); err != nil {
+			d.Parameters = map[string]interface{}{"raw": e.Parameters}
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,202 @@
+package audit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/audit"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// store is an in-memory audit table behind the mocked repository.
+type store struct{ events []entity.AuditEvent }
+
+func auditTest(t *testing.T) (*audit.UseCase, *store) {
+	t.Helper()
+
+	s := &store{}
+
+	return auditOn(t, s, "key", nil), s
+}
+
+// auditOn returns a use case on s, signing with key.
+func auditOn(t *testing.T, s *store, key string, previousKeys []string) *audit.UseCase {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	repo := mocks.NewMockAuditRepository(mockCtl)
+
+	repo.EXPECT().GetLast(gomock.Any()).DoAndReturn(func(context.Context) (*entity.AuditEvent, error) {
+		if len(s.events) == 0 {
+			return nil, nil
+		}
+
+		last := s.events[len(s.events)-1]
+
+		return &last, nil
+	}).AnyTimes()
+	repo.EXPECT().Insert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.AuditEvent) error {
+		for i := range s.events {
+			if s.events[i].Seq == e.Seq {
+				return repoerrors.NotUniqueError{}
+			}
+		}
+
+		s.events = append(s.events, *e)
+
+		return nil
+	}).AnyTimes()
+	repo.EXPECT().GetAfter(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, seq int64, limit int) ([]entity.AuditEvent, error) {
+		out := []entity.AuditEvent{}
+
+		for i := range s.events {
+			if s.events[i].Seq > seq && len(out) < limit {
+				out = append(out, s.events[i])
+			}
+		}
+
+		return out, nil
+	}).AnyTimes()
+
+	return audit.New(repo, logger.New("error"), key, previousKeys)
+}
+
+func record(t *testing.T, uc *audit.UseCase, actor string) *dto.AuditEvent {
+	t.Helper()
+
+	e := &dto.AuditEvent{
+		Actor:  actor,
+		Action: "POST /api/v1/amt/power/action/{guid}",
+		Target: "guid-1",
+		Parameters: map[string]interface{}{
+			"body": map[string]interface{}{"action": float64(10), "password": "P@ssw0rd"},
+		},
+		Result: audit.ResultSuccess,
+		Status: 200,
+	}
+
+	require.NoError(t, uc.Record(context.Background(), e))
+
+	return e
+}
+
+func TestRecordChainsEntries(t *testing.T) {
+	t.Parallel()
+
+	uc, s := auditTest(t)
+
+	first := record(t, uc, "jdoe")
+	second := record(t, uc, "admin")
+
+	require.Equal(t, int64(1), first.Seq)
+	require.Empty(t, first.PrevHash)
+	require.Equal(t, int64(2), second.Seq)
+	require.Equal(t, first.Hash, second.PrevHash)
+	require.NotContains(t, s.events[0].Parameters, "P@ssw0rd")
+	require.Equal(t, "[redacted]", first.Parameters["body"].(map[string]interface{})["password"])
+
+	v, err := uc.Verify(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &dto.AuditVerification{Valid: true, Count: 2, LastHash: second.Hash}, v)
+}
+
+func TestRecordContinuesAnotherInstancesChain(t *testing.T) {
+	t.Parallel()
+
+	uc, s := auditTest(t)
+
+	record(t, uc, "jdoe")
+
+	// Another console instance sharing the database appends next.
+	s.events = append(s.events, entity.AuditEvent{Seq: 2, Hash: "other"})
+
+	e := record(t, uc, "jdoe")
+	require.Equal(t, int64(3), e.Seq)
+	require.Equal(t, "other", e.PrevHash)
+}
+
+func TestVerifyAcrossKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	uc, s := auditTest(t)
+
+	record(t, uc, "jdoe")
+
+	rotated := auditOn(t, s, "new key", []string{"key"})
+	last := record(t, rotated, "jdoe")
+
+	v, err := rotated.Verify(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &dto.AuditVerification{Valid: true, Count: 2, LastHash: last.Hash}, v)
+
+	// Once the previous key is dropped, the entries it signed no longer verify.
+	v, err = auditOn(t, s, "new key", nil).Verify(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &dto.AuditVerification{BrokenAt: 1, Reason: "hash mismatch"}, v)
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, s *store)
+		want   *dto.AuditVerification
+	}{
+		{
+			name:   "edited entry",
+			tamper: func(_ *testing.T, s *store) { s.events[1].Actor = "someone-else" },
+			want:   &dto.AuditVerification{Count: 1, BrokenAt: 2, Reason: "hash mismatch"},
+		},
+		{
+			name:   "removed entry",
+			tamper: func(_ *testing.T, s *store) { s.events = append(s.events[:1], s.events[2:]...) },
+			want:   &dto.AuditVerification{Count: 1, BrokenAt: 2, Reason: "entry missing"},
+		},
+		{
+			name: "rewritten hash",
+			tamper: func(_ *testing.T, s *store) {
+				s.events[1].Actor = "someone-else"
+				s.events[1].Hash = "forged"
+			},
+			want: &dto.AuditVerification{Count: 1, BrokenAt: 2, Reason: "hash mismatch"},
+		},
+		{
+			name: "rewritten with a key of the attacker's",
+			tamper: func(t *testing.T, s *store) {
+				t.Helper()
+
+				forged := &store{events: s.events[:1:1]}
+				record(t, auditOn(t, forged, "guessed", nil), "someone-else")
+				s.events[1] = forged.events[1]
+			},
+			want: &dto.AuditVerification{Count: 1, BrokenAt: 2, Reason: "hash mismatch"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			uc, s := auditTest(t)
+
+			first := record(t, uc, "jdoe")
+			record(t, uc, "jdoe")
+			record(t, uc, "jdoe")
+
+			tc.tamper(t, s)
+			tc.want.LastHash = first.Hash
+
+			v, err := uc.Verify(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tc.want, v)
+		})
+	}
+}
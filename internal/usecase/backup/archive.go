@@ -0,0 +1,184 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+)
+
+const (
+	// Format names the file type, so that other JSON is refused up front.
+	Format = "device-management-toolkit/console-backup"
+
+	// Version is the layout of the contents this console writes. Restore
+	// takes this version and older ones.
+	Version = 1
+
+	kdfPBKDF2     = "pbkdf2-sha256"
+	kdfIterations = 600_000
+	// maxIterations bounds the work a crafted archive can ask for.
+	maxIterations = 10_000_000
+	saltSize      = 16
+	keySize       = 32
+
+	// maxContentsSize bounds what an archive may inflate to.
+	maxContentsSize = 256 << 20
+)
+
+var (
+	errUnknownFormat      = errors.New("not a console backup")
+	errUnsupportedVersion = errors.New("backup version not supported")
+	errUnsupportedKDF     = errors.New("backup key derivation not supported")
+	errDecrypt            = errors.New("wrong passphrase or damaged backup")
+	errTooLarge           = errors.New("backup contents too large")
+)
+
+// envelope is an archive as stored: a header in the clear, and the contents
+// as gzipped JSON sealed with AES-256-GCM under a key derived from the
+// passphrase. The header is authenticated along with the contents.
+type envelope struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"createdAt"`
+	KDF        string    `json:"kdf"`
+	Iterations int       `json:"iterations"`
+	Salt       []byte    `json:"salt"`
+	Nonce      []byte    `json:"nonce"`
+	Data       []byte    `json:"data"`
+}
+
+// contents is what an archive holds. Secrets are in the clear rather than
+// encrypted with the console's own key, so that another console can restore
+// them, and tenant IDs are left out: a restore writes to the caller's tenant.
+type contents struct {
+	CIRAConfigs        []entity.CIRAConfig         `json:"ciraConfigs"`
+	IEEE8021xConfigs   []entity.IEEE8021xConfig    `json:"ieee8021xConfigs"`
+	WirelessConfigs    []entity.WirelessConfig     `json:"wirelessConfigs"`
+	Domains            []entity.Domain             `json:"domains"`
+	Profiles           []entity.Profile            `json:"profiles"`
+	ProfileWiFiConfigs []entity.ProfileWiFiConfigs `json:"profileWiFiConfigs"`
+	Devices            []entity.Device             `json:"devices,omitempty"`
+}
+
+// seal encrypts c with passphrase into an archive.
+func seal(c *contents, passphrase string, now time.Time) ([]byte, error) {
+	var plain bytes.Buffer
+
+	zw := gzip.NewWriter(&plain)
+	if err := json.NewEncoder(zw).Encode(c); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	e := envelope{
+		Format:     Format,
+		Version:    Version,
+		CreatedAt:  now.UTC(),
+		KDF:        kdfPBKDF2,
+		Iterations: kdfIterations,
+		Salt:       make([]byte, saltSize),
+	}
+
+	if _, err := rand.Read(e.Salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := e.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	e.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(e.Nonce); err != nil {
+		return nil, err
+	}
+
+	e.Data = aead.Seal(nil, e.Nonce, plain.Bytes(), e.header())
+
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// open decrypts an archive made by seal.
+func open(archive []byte, passphrase string) (*contents, error) {
+	var e envelope
+
+	if err := json.Unmarshal(archive, &e); err != nil || e.Format != Format {
+		return nil, errUnknownFormat
+	}
+
+	if e.Version < 1 || e.Version > Version {
+		return nil, fmt.Errorf("%w: %d", errUnsupportedVersion, e.Version)
+	}
+
+	if e.KDF != kdfPBKDF2 || e.Iterations < 1 || e.Iterations > maxIterations {
+		return nil, errUnsupportedKDF
+	}
+
+	aead, err := e.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(e.Nonce) != aead.NonceSize() {
+		return nil, errDecrypt
+	}
+
+	plain, err := aead.Open(nil, e.Nonce, e.Data, e.header())
+	if err != nil {
+		return nil, errDecrypt
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(zr, maxContentsSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxContentsSize {
+		return nil, errTooLarge
+	}
+
+	var c contents
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (e *envelope) cipher(passphrase string) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, e.Salt, e.Iterations, keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// header is the additional data the contents are sealed with.
+func (e *envelope) header() []byte {
+	return fmt.Appendf(nil, "%s\n%d\n%s\n%s\n%d", e.Format, e.Version, e.CreatedAt.Format(time.RFC3339Nano), e.KDF, e.Iterations)
+}
@@ -0,0 +1,70 @@
+package backup
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+)
+
+func TestSealOpen(t *testing.T) {
+	t.Parallel()
+
+	c := &contents{
+		CIRAConfigs: []entity.CIRAConfig{{ConfigName: "cira", Password: "P@ssw0rd"}},
+		Profiles:    []entity.Profile{{ProfileName: "acm", AMTPassword: "secret"}},
+	}
+
+	archive, err := seal(c, "correct horse battery", time.Now())
+	require.NoError(t, err)
+	require.NotContains(t, string(archive), "P@ssw0rd")
+
+	opened, err := open(archive, "correct horse battery")
+	require.NoError(t, err)
+	require.Equal(t, c, opened)
+
+	_, err = open(archive, "wrong horse battery")
+	require.ErrorIs(t, err, errDecrypt)
+
+	// The header is authenticated with the contents.
+	var e envelope
+	require.NoError(t, json.Unmarshal(archive, &e))
+
+	e.CreatedAt = e.CreatedAt.Add(time.Hour)
+	tampered, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	_, err = open(tampered, "correct horse battery")
+	require.ErrorIs(t, err, errDecrypt)
+
+	e.Version = Version + 1
+	newer, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	_, err = open(newer, "correct horse battery")
+	require.ErrorIs(t, err, errUnsupportedVersion)
+
+	_, err = open([]byte(`{"profiles":[]}`), "correct horse battery")
+	require.ErrorIs(t, err, errUnknownFormat)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validate(&contents{
+		Profiles:           []entity.Profile{{ProfileName: "acm"}},
+		ProfileWiFiConfigs: []entity.ProfileWiFiConfigs{{ProfileName: "acm", WirelessProfileName: "wifi"}},
+	}))
+
+	err := validate(&contents{
+		Domains:            []entity.Domain{{ProfileName: "a", DomainSuffix: "x.com"}, {ProfileName: "b", DomainSuffix: "x.com"}},
+		Profiles:           []entity.Profile{{ProfileName: ""}},
+		ProfileWiFiConfigs: []entity.ProfileWiFiConfigs{{ProfileName: "ccm", WirelessProfileName: "wifi"}},
+	})
+	require.ErrorIs(t, err, errDuplicateName)
+	require.ErrorIs(t, err, errMissingName)
+	require.ErrorIs(t, err, errMissingRef)
+}
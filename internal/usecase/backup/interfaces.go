@@ -0,0 +1,42 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/usecase/ciraconfigs"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/internal/usecase/domains"
+	"github.com/device-management-toolkit/console/internal/usecase/ieee8021xconfigs"
+	"github.com/device-management-toolkit/console/internal/usecase/profiles"
+	"github.com/device-management-toolkit/console/internal/usecase/profilewificonfigs"
+	"github.com/device-management-toolkit/console/internal/usecase/wificonfigs"
+)
+
+type (
+	// Transactor runs fn in one database transaction, which repository
+	// calls made with the ctx fn is given join.
+	Transactor interface {
+		InTx(ctx context.Context, fn func(ctx context.Context) error) error
+	}
+	// DomainCerts reads a domain with its certificate, from the database or
+	// the certificate store, wherever it is kept.
+	DomainCerts interface {
+		GetByNameWithCert(ctx context.Context, name, tenantID string) (*entity.Domain, error)
+	}
+	Repositories struct {
+		CIRAConfigs        ciraconfigs.Repository
+		IEEE8021xConfigs   ieee8021xconfigs.Repository
+		WirelessConfigs    wificonfigs.Repository
+		Domains            domains.Repository
+		Profiles           profiles.Repository
+		ProfileWiFiConfigs profilewificonfigs.Repository
+		Devices            devices.Repository
+		Transactor         Transactor
+	}
+	Feature interface {
+		Backup(ctx context.Context, req dto.BackupRequest, tenantID string) ([]byte, error)
+		Restore(ctx context.Context, req dto.RestoreRequest, tenantID string) (*dto.RestoreResult, error)
+	}
+)
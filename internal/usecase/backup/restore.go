@@ -0,0 +1,673 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+// Kinds of entity, as reported in a dto.RestoreItem.
+const (
+	kindCIRAConfig      = "ciraConfig"
+	kindIEEE8021xConfig = "ieee8021xConfig"
+	kindWirelessConfig  = "wirelessConfig"
+	kindDomain          = "domain"
+	kindProfile         = "profile"
+	kindDevice          = "device"
+)
+
+const (
+	renameSuffix = "restored"
+	// maxRenames bounds the search for a free name.
+	maxRenames = 100
+	// maxIEEE8021xName is the longest 802.1x profile name the API takes.
+	maxIEEE8021xName = 32
+)
+
+var (
+	errMissingName   = errors.New("name is required")
+	errDuplicateName = errors.New("appears more than once")
+	errMissingRef    = errors.New("refers to a missing")
+	errNoFreeName    = errors.New("no free name to rename to")
+)
+
+// Restore writes the entities of an archive to the tenant, in one
+// transaction: either all of them are restored or none is. An entity that
+// already exists is skipped, overwritten or restored under a new name, as
+// req.OnConflict says, and references to a renamed entity follow it.
+func (uc *UseCase) Restore(ctx context.Context, req dto.RestoreRequest, tenantID string) (*dto.RestoreResult, error) {
+	c, err := open(req.Archive, req.Passphrase)
+	if err != nil {
+		return nil, ErrNotValid.Wrap("Restore", "open", err)
+	}
+
+	if err := validate(c); err != nil {
+		return nil, ErrNotValid.Wrap("Restore", "validate", err)
+	}
+
+	r := &restore{
+		uc:       uc,
+		c:        c,
+		tenantID: tenantID,
+		policy:   req.OnConflict,
+		dryRun:   req.DryRun,
+	}
+
+	if r.policy == "" {
+		r.policy = dto.ConflictSkip
+	}
+
+	if req.DryRun {
+		err = r.run(ctx)
+	} else {
+		err = uc.repos.Transactor.InTx(ctx, r.run)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.DryRun {
+		uc.log.Info("restore to tenant %q: %d entities", tenantID, len(r.items))
+	}
+
+	return &dto.RestoreResult{DryRun: req.DryRun, Items: r.items}, nil
+}
+
+// validate checks what an archive can be checked for without the database.
+func validate(c *contents) error {
+	var errs []error
+
+	check := func(kind string, names []string) {
+		seen := make(map[string]bool, len(names))
+
+		for _, name := range names {
+			switch {
+			case name == "":
+				errs = append(errs, fmt.Errorf("%s: %w", kind, errMissingName))
+			case seen[name]:
+				errs = append(errs, fmt.Errorf("%s %q %w", kind, name, errDuplicateName))
+			}
+
+			seen[name] = true
+		}
+	}
+
+	check(kindCIRAConfig, names(c.CIRAConfigs, func(e entity.CIRAConfig) string { return e.ConfigName }))
+	check(kindIEEE8021xConfig, names(c.IEEE8021xConfigs, func(e entity.IEEE8021xConfig) string { return e.ProfileName }))
+	check(kindWirelessConfig, names(c.WirelessConfigs, func(e entity.WirelessConfig) string { return e.ProfileName }))
+	check(kindDomain, names(c.Domains, func(e entity.Domain) string { return e.ProfileName }))
+	check("domain suffix", names(c.Domains, func(e entity.Domain) string { return e.DomainSuffix }))
+	check(kindProfile, names(c.Profiles, func(e entity.Profile) string { return e.ProfileName }))
+	check(kindDevice, names(c.Devices, func(e entity.Device) string { return e.GUID }))
+
+	profiles := set(names(c.Profiles, func(e entity.Profile) string { return e.ProfileName }))
+
+	for _, link := range c.ProfileWiFiConfigs {
+		if !profiles[link.ProfileName] {
+			errs = append(errs, fmt.Errorf("wireless link of %s %q %w profile", kindProfile, link.ProfileName, errMissingRef))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// restore is one run of a Restore.
+type restore struct {
+	uc       *UseCase
+	c        *contents
+	tenantID string
+	policy   string
+	dryRun   bool
+
+	// items and renamed are reset by run, which a Mongo transaction may
+	// retry.
+	items   []dto.RestoreItem
+	renamed map[string]map[string]string
+}
+
+func (r *restore) run(ctx context.Context) error {
+	r.items = make([]dto.RestoreItem, 0)
+	r.renamed = make(map[string]map[string]string)
+
+	if err := r.checkReferences(ctx); err != nil {
+		return err
+	}
+
+	// Referenced entities go first, so that references resolve.
+	for _, step := range []func(context.Context) error{
+		r.ieee8021xConfigs,
+		r.ciraConfigs,
+		r.wirelessConfigs,
+		r.domains,
+		r.profiles,
+		r.devices,
+	} {
+		if err := step(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkReferences makes sure that whatever the archive refers to without
+// having it exists in the tenant already.
+func (r *restore) checkReferences(ctx context.Context) error {
+	var errs []error
+
+	check := func(kind, name, from string, have map[string]bool, exists func(ctx context.Context, name string) (bool, error)) error {
+		if name == "" || have[name] {
+			return nil
+		}
+
+		found, err := exists(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			errs = append(errs, fmt.Errorf("%s %w %s %q", from, errMissingRef, kind, name))
+		}
+
+		return nil
+	}
+
+	ciraConfigs := set(names(r.c.CIRAConfigs, func(e entity.CIRAConfig) string { return e.ConfigName }))
+	ieee8021xConfigs := set(names(r.c.IEEE8021xConfigs, func(e entity.IEEE8021xConfig) string { return e.ProfileName }))
+	wirelessConfigs := set(names(r.c.WirelessConfigs, func(e entity.WirelessConfig) string { return e.ProfileName }))
+
+	for i := range r.c.WirelessConfigs {
+		w := &r.c.WirelessConfigs[i]
+
+		if err := check(kindIEEE8021xConfig, deref(w.IEEE8021xProfileName), kindWirelessConfig+" "+strconv.Quote(w.ProfileName), ieee8021xConfigs, r.ieee8021xConfigExists); err != nil {
+			return err
+		}
+	}
+
+	for i := range r.c.Profiles {
+		p := &r.c.Profiles[i]
+		from := kindProfile + " " + strconv.Quote(p.ProfileName)
+
+		if err := check(kindCIRAConfig, deref(p.CIRAConfigName), from, ciraConfigs, r.ciraConfigExists); err != nil {
+			return err
+		}
+
+		if err := check(kindIEEE8021xConfig, deref(p.IEEE8021xProfileName), from, ieee8021xConfigs, r.ieee8021xConfigExists); err != nil {
+			return err
+		}
+	}
+
+	for _, link := range r.c.ProfileWiFiConfigs {
+		from := kindProfile + " " + strconv.Quote(link.ProfileName)
+
+		if err := check(kindWirelessConfig, link.WirelessProfileName, from, wirelessConfigs, r.wirelessConfigExists); err != nil {
+			return err
+		}
+	}
+
+	if len(errs) > 0 {
+		return ErrNotValid.Wrap("Restore", "checkReferences", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func (r *restore) ciraConfigs(ctx context.Context) error {
+	repo := r.uc.repos.CIRAConfigs
+
+	for i := range r.c.CIRAConfigs {
+		cfg := r.c.CIRAConfigs[i]
+
+		err := r.put(ctx, kindCIRAConfig, cfg.ConfigName, r.ciraConfigExists, func(ctx context.Context, name string, overwrite bool) error {
+			cfg.ConfigName, cfg.TenantID = name, r.tenantID
+
+			var err error
+
+			if cfg.Password, err = r.uc.encrypt(cfg.Password); err != nil {
+				return ErrBackupUseCase.Wrap("Restore", "encrypt CIRA config password", err)
+			}
+
+			if overwrite {
+				_, err = repo.Update(ctx, &cfg)
+			} else {
+				_, err = repo.Insert(ctx, &cfg)
+			}
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *restore) ieee8021xConfigs(ctx context.Context) error {
+	repo := r.uc.repos.IEEE8021xConfigs
+
+	for i := range r.c.IEEE8021xConfigs {
+		cfg := r.c.IEEE8021xConfigs[i]
+
+		err := r.put(ctx, kindIEEE8021xConfig, cfg.ProfileName, r.ieee8021xConfigExists, func(ctx context.Context, name string, overwrite bool) error {
+			cfg.ProfileName, cfg.TenantID = name, r.tenantID
+
+			var err error
+
+			if overwrite {
+				_, err = repo.Update(ctx, &cfg)
+			} else {
+				_, err = repo.Insert(ctx, &cfg)
+			}
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *restore) wirelessConfigs(ctx context.Context) error {
+	repo := r.uc.repos.WirelessConfigs
+
+	for i := range r.c.WirelessConfigs {
+		cfg := r.c.WirelessConfigs[i]
+
+		err := r.put(ctx, kindWirelessConfig, cfg.ProfileName, r.wirelessConfigExists, func(ctx context.Context, name string, overwrite bool) error {
+			cfg.ProfileName, cfg.TenantID = name, r.tenantID
+			cfg.IEEE8021xProfileName = r.ref(kindIEEE8021xConfig, cfg.IEEE8021xProfileName)
+
+			var err error
+
+			if cfg.PSKPassphrase, err = r.uc.encrypt(cfg.PSKPassphrase); err != nil {
+				return ErrBackupUseCase.Wrap("Restore", "encrypt wireless passphrase", err)
+			}
+
+			if overwrite {
+				_, err = repo.Update(ctx, &cfg)
+			} else {
+				_, err = repo.Insert(ctx, &cfg)
+			}
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *restore) domains(ctx context.Context) error {
+	repo := r.uc.repos.Domains
+
+	for i := range r.c.Domains {
+		d := r.c.Domains[i]
+
+		// Suffixes are unique too, and do not change on a rename.
+		owner, err := repo.GetDomainByDomainSuffix(ctx, d.DomainSuffix, r.tenantID)
+		if err != nil {
+			return ErrDatabase.Wrap("Restore", "repo.GetDomainByDomainSuffix", err)
+		}
+
+		if owner != nil && (owner.ProfileName != d.ProfileName || r.policy == dto.ConflictRename) {
+			r.items = append(r.items, dto.RestoreItem{
+				Kind:   kindDomain,
+				Name:   d.ProfileName,
+				Action: dto.RestoreSkipped,
+				Note:   fmt.Sprintf("domain suffix %q is in use by domain %q", d.DomainSuffix, owner.ProfileName),
+			})
+
+			continue
+		}
+
+		err = r.put(ctx, kindDomain, d.ProfileName, r.domainExists, func(ctx context.Context, name string, overwrite bool) error {
+			// Restored certificates are kept in the database; the domains
+			// use case reads them from there as it does older domains.
+			d.ProfileName, d.TenantID = name, r.tenantID
+
+			var err error
+
+			if d.ProvisioningCertPassword, err = r.uc.encrypt(d.ProvisioningCertPassword); err != nil {
+				return ErrBackupUseCase.Wrap("Restore", "encrypt provisioning certificate password", err)
+			}
+
+			if overwrite {
+				_, err = repo.Update(ctx, &d)
+			} else {
+				_, err = repo.Insert(ctx, &d)
+			}
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *restore) profiles(ctx context.Context) error {
+	repo := r.uc.repos.Profiles
+
+	for i := range r.c.Profiles {
+		p := r.c.Profiles[i]
+		original := p.ProfileName
+
+		err := r.put(ctx, kindProfile, p.ProfileName, r.profileExists, func(ctx context.Context, name string, overwrite bool) error {
+			p.ProfileName, p.TenantID = name, r.tenantID
+			p.CIRAConfigName = r.ref(kindCIRAConfig, p.CIRAConfigName)
+			p.IEEE8021xProfileName = r.ref(kindIEEE8021xConfig, p.IEEE8021xProfileName)
+
+			var err error
+
+			if p.AMTPassword, err = r.uc.encrypt(p.AMTPassword); err != nil {
+				return ErrBackupUseCase.Wrap("Restore", "encrypt AMT password", err)
+			}
+
+			if p.MEBXPassword, err = r.uc.encrypt(p.MEBXPassword); err != nil {
+				return ErrBackupUseCase.Wrap("Restore", "encrypt MEBx password", err)
+			}
+
+			if overwrite {
+				if _, err = repo.Update(ctx, &p); err != nil {
+					return err
+				}
+
+				if _, err = r.uc.repos.ProfileWiFiConfigs.DeleteByProfileName(ctx, name, r.tenantID); err != nil {
+					return err
+				}
+			} else if _, err = repo.Insert(ctx, &p); err != nil {
+				return err
+			}
+
+			return r.profileWiFiConfigs(ctx, original, name)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// profileWiFiConfigs links a restored profile to its wireless configs.
+func (r *restore) profileWiFiConfigs(ctx context.Context, original, name string) error {
+	for _, link := range r.c.ProfileWiFiConfigs {
+		if link.ProfileName != original {
+			continue
+		}
+
+		link.ProfileName, link.TenantID = name, r.tenantID
+		link.WirelessProfileName = deref(r.ref(kindWirelessConfig, &link.WirelessProfileName))
+
+		if _, err := r.uc.repos.ProfileWiFiConfigs.Insert(ctx, &link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *restore) devices(ctx context.Context) error {
+	repo := r.uc.repos.Devices
+
+	for i := range r.c.Devices {
+		d := r.c.Devices[i]
+
+		// A device is its GUID, so it is never renamed.
+		if r.policy == dto.ConflictRename {
+			found, err := r.deviceExists(ctx, d.GUID)
+			if err != nil {
+				return ErrDatabase.Wrap("Restore", "repo.GetByID", err)
+			}
+
+			if found {
+				r.items = append(r.items, dto.RestoreItem{
+					Kind:   kindDevice,
+					Name:   d.GUID,
+					Action: dto.RestoreSkipped,
+					Note:   "devices are not renamed",
+				})
+
+				continue
+			}
+		}
+
+		err := r.put(ctx, kindDevice, d.GUID, r.deviceExists, func(ctx context.Context, _ string, overwrite bool) error {
+			d.TenantID = r.tenantID
+			// The device has yet to connect to this console.
+			d.ConnectionStatus, d.MPSInstance = false, ""
+
+			var err error
+
+			if d.Password, err = r.uc.encrypt(d.Password); err != nil {
+				return ErrBackupUseCase.Wrap("Restore", "encrypt device password", err)
+			}
+
+			if d.MPSPassword, err = r.encryptOptional(d.MPSPassword); err != nil {
+				return ErrBackupUseCase.Wrap("Restore", "encrypt MPS password", err)
+			}
+
+			if d.MEBXPassword, err = r.encryptOptional(d.MEBXPassword); err != nil {
+				return ErrBackupUseCase.Wrap("Restore", "encrypt MEBx password", err)
+			}
+
+			if overwrite {
+				_, err = repo.Update(ctx, &d)
+			} else {
+				_, err = repo.Insert(ctx, &d)
+			}
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// put restores one entity of kind by the conflict policy: write stores it
+// under name, overwriting the existing entity if overwrite. On a dry run
+// write is not called.
+func (r *restore) put(ctx context.Context, kind, name string, exists func(ctx context.Context, name string) (bool, error),
+	write func(ctx context.Context, name string, overwrite bool) error,
+) error {
+	found, err := exists(ctx, name)
+	if err != nil {
+		return ErrDatabase.Wrap("Restore", "exists", err)
+	}
+
+	item := dto.RestoreItem{Kind: kind, Name: name, Action: dto.RestoreCreated}
+
+	switch {
+	case !found:
+	case r.policy == dto.ConflictOverwrite:
+		item.Action = dto.RestoreOverwritten
+	case r.policy == dto.ConflictRename:
+		newName, err := r.freeName(ctx, kind, name, exists)
+		if err != nil {
+			return err
+		}
+
+		item.Action, item.RestoredAs = dto.RestoreRenamed, newName
+
+		if r.renamed[kind] == nil {
+			r.renamed[kind] = make(map[string]string)
+		}
+
+		r.renamed[kind][name] = newName
+	default:
+		item.Action = dto.RestoreSkipped
+	}
+
+	r.items = append(r.items, item)
+
+	if r.dryRun || item.Action == dto.RestoreSkipped {
+		return nil
+	}
+
+	target := name
+	if item.RestoredAs != "" {
+		target = item.RestoredAs
+	}
+
+	if err := write(ctx, target, item.Action == dto.RestoreOverwritten); err != nil {
+		return ErrDatabase.Wrap("Restore", "restore "+kind+" "+strconv.Quote(name), err)
+	}
+
+	return nil
+}
+
+// freeName finds a name for kind that is taken neither in the tenant nor
+// in the archive: name-restored, name-restored2 and so on. 802.1x profile
+// names are alphanumeric and short, so for them the hyphen is dropped and
+// name is cut to fit.
+func (r *restore) freeName(ctx context.Context, kind, name string, exists func(ctx context.Context, name string) (bool, error)) (string, error) {
+	inArchive := set(r.archiveNames(kind))
+
+	for n := 1; n <= maxRenames; n++ {
+		suffix := renameSuffix
+		if n > 1 {
+			suffix += strconv.Itoa(n)
+		}
+
+		candidate := name + "-" + suffix
+
+		if kind == kindIEEE8021xConfig {
+			candidate = name[:min(len(name), maxIEEE8021xName-len(suffix))] + suffix
+		}
+
+		if inArchive[candidate] {
+			continue
+		}
+
+		found, err := exists(ctx, candidate)
+		if err != nil {
+			return "", ErrDatabase.Wrap("Restore", "exists", err)
+		}
+
+		if !found {
+			return candidate, nil
+		}
+	}
+
+	return "", ErrNotValid.Wrap("Restore", "freeName", fmt.Errorf("%s %q: %w", kind, name, errNoFreeName))
+}
+
+func (r *restore) archiveNames(kind string) []string {
+	switch kind {
+	case kindCIRAConfig:
+		return names(r.c.CIRAConfigs, func(e entity.CIRAConfig) string { return e.ConfigName })
+	case kindIEEE8021xConfig:
+		return names(r.c.IEEE8021xConfigs, func(e entity.IEEE8021xConfig) string { return e.ProfileName })
+	case kindWirelessConfig:
+		return names(r.c.WirelessConfigs, func(e entity.WirelessConfig) string { return e.ProfileName })
+	case kindDomain:
+		return names(r.c.Domains, func(e entity.Domain) string { return e.ProfileName })
+	case kindProfile:
+		return names(r.c.Profiles, func(e entity.Profile) string { return e.ProfileName })
+	}
+
+	return nil
+}
+
+// ref follows a reference to an entity of kind that may have been renamed.
+func (r *restore) ref(kind string, name *string) *string {
+	if name == nil {
+		return nil
+	}
+
+	if newName, ok := r.renamed[kind][*name]; ok {
+		return &newName
+	}
+
+	return name
+}
+
+func (r *restore) encryptOptional(s *string) (*string, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	encrypted, err := r.uc.encrypt(*s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encrypted, nil
+}
+
+func (r *restore) ciraConfigExists(ctx context.Context, name string) (bool, error) {
+	e, err := r.uc.repos.CIRAConfigs.GetByName(ctx, name, r.tenantID)
+
+	return e != nil, err
+}
+
+func (r *restore) ieee8021xConfigExists(ctx context.Context, name string) (bool, error) {
+	e, err := r.uc.repos.IEEE8021xConfigs.GetByName(ctx, name, r.tenantID)
+
+	return e != nil, err
+}
+
+func (r *restore) wirelessConfigExists(ctx context.Context, name string) (bool, error) {
+	e, err := r.uc.repos.WirelessConfigs.GetByName(ctx, name, r.tenantID)
+
+	return e != nil, err
+}
+
+func (r *restore) domainExists(ctx context.Context, name string) (bool, error) {
+	e, err := r.uc.repos.Domains.GetByName(ctx, name, r.tenantID)
+
+	return e != nil, err
+}
+
+func (r *restore) profileExists(ctx context.Context, name string) (bool, error) {
+	e, err := r.uc.repos.Profiles.GetByName(ctx, name, r.tenantID)
+
+	return e != nil, err
+}
+
+func (r *restore) deviceExists(ctx context.Context, guid string) (bool, error) {
+	e, err := r.uc.repos.Devices.GetByID(ctx, guid, r.tenantID)
+
+	return e != nil, err
+}
+
+func names[T any](entities []T, name func(T) string) []string {
+	out := make([]string, len(entities))
+	for i := range entities {
+		out[i] = name(entities[i])
+	}
+
+	return out
+}
+
+func set(names []string) map[string]bool {
+	out := make(map[string]bool, len(names))
+	for _, name := range names {
+		out[name] = true
+	}
+
+	return out
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,321 @@
+// Package backup exports a tenant's configuration to an encrypted archive
+// and restores it, possibly on another console: profiles, domains with their
+// provisioning certificates, CIRA, wireless and 802.1x configs and, when
+// asked for, devices with their credentials.
+package backup
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+const (
+	// MinPassphraseLength is the shortest passphrase a backup takes.
+	MinPassphraseLength = 12
+
+	// pageSize is how many entities a backup reads per repository call.
+	pageSize = 100
+)
+
+// UseCase -.
+type UseCase struct {
+	repos            Repositories
+	domainCerts      DomainCerts
+	log              logger.Interface
+	safeRequirements security.Cryptor
+}
+
+var (
+	ErrBackupUseCase = consoleerrors.CreateConsoleError("BackupUseCase")
+	ErrDatabase      = repoerrors.DatabaseError{Console: ErrBackupUseCase}
+	ErrNotValid      = dto.NotValidError{Console: ErrBackupUseCase}
+
+	errShortPassphrase = errors.New("passphrase must be at least 12 characters")
+)
+
+// New -.
+func New(repos Repositories, domainCerts DomainCerts, log logger.Interface, safeRequirements security.Cryptor) *UseCase {
+	return &UseCase{
+		repos:            repos,
+		domainCerts:      domainCerts,
+		log:              log,
+		safeRequirements: safeRequirements,
+	}
+}
+
+// Backup returns an archive of the tenant's configuration, and of its
+// devices if req.IncludeDevices, encrypted with req.Passphrase.
+func (uc *UseCase) Backup(ctx context.Context, req dto.BackupRequest, tenantID string) ([]byte, error) {
+	if len(req.Passphrase) < MinPassphraseLength {
+		return nil, ErrNotValid.Wrap("Backup", "validate", errShortPassphrase)
+	}
+
+	c, err := uc.collect(ctx, tenantID, req.IncludeDevices)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := seal(c, req.Passphrase, time.Now())
+	if err != nil {
+		return nil, ErrBackupUseCase.Wrap("Backup", "seal", err)
+	}
+
+	uc.log.Info("backup of tenant %q: %d profiles, %d domains, %d CIRA configs, %d wireless configs, %d 802.1x configs, %d devices",
+		tenantID, len(c.Profiles), len(c.Domains), len(c.CIRAConfigs), len(c.WirelessConfigs), len(c.IEEE8021xConfigs), len(c.Devices))
+
+	return archive, nil
+}
+
+func (uc *UseCase) collect(ctx context.Context, tenantID string, includeDevices bool) (*contents, error) {
+	c := &contents{}
+
+	for _, step := range []func(context.Context, *contents, string) error{
+		uc.collectCIRAConfigs,
+		uc.collectIEEE8021xConfigs,
+		uc.collectWirelessConfigs,
+		uc.collectDomains,
+		uc.collectProfiles,
+	} {
+		if err := step(ctx, c, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	if includeDevices {
+		if err := uc.collectDevices(ctx, c, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func (uc *UseCase) collectCIRAConfigs(ctx context.Context, c *contents, tenantID string) error {
+	configs, err := all(ctx, uc.repos.CIRAConfigs.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Backup", "uc.repos.CIRAConfigs.Get", err)
+	}
+
+	for i := range configs {
+		configs[i].TenantID, configs[i].Version = "", ""
+
+		if configs[i].Password, err = uc.decrypt(configs[i].Password); err != nil {
+			return ErrBackupUseCase.Wrap("Backup", "decrypt CIRA config password", err)
+		}
+	}
+
+	c.CIRAConfigs = configs
+
+	return nil
+}
+
+func (uc *UseCase) collectIEEE8021xConfigs(ctx context.Context, c *contents, tenantID string) error {
+	configs, err := all(ctx, uc.repos.IEEE8021xConfigs.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Backup", "uc.repos.IEEE8021xConfigs.Get", err)
+	}
+
+	for i := range configs {
+		configs[i].TenantID, configs[i].Version = "", ""
+	}
+
+	c.IEEE8021xConfigs = configs
+
+	return nil
+}
+
+func (uc *UseCase) collectWirelessConfigs(ctx context.Context, c *contents, tenantID string) error {
+	configs, err := all(ctx, uc.repos.WirelessConfigs.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Backup", "uc.repos.WirelessConfigs.Get", err)
+	}
+
+	for i := range configs {
+		w := &configs[i]
+		w.TenantID, w.Version = "", ""
+		// Joined from the 802.1x config, which the archive has itself.
+		w.AuthenticationProtocol, w.PXETimeout, w.WiredInterface = nil, nil, nil
+
+		if w.PSKPassphrase, err = uc.decrypt(w.PSKPassphrase); err != nil {
+			return ErrBackupUseCase.Wrap("Backup", "decrypt wireless passphrase", err)
+		}
+	}
+
+	c.WirelessConfigs = configs
+
+	return nil
+}
+
+func (uc *UseCase) collectDomains(ctx context.Context, c *contents, tenantID string) error {
+	list, err := all(ctx, uc.repos.Domains.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Backup", "uc.repos.Domains.Get", err)
+	}
+
+	c.Domains = make([]entity.Domain, 0, len(list))
+
+	for i := range list {
+		// The list leaves out certificates kept in the certificate store.
+		d, err := uc.domainCerts.GetByNameWithCert(ctx, list[i].ProfileName, tenantID)
+		if err != nil {
+			return ErrDatabase.Wrap("Backup", "uc.domainCerts.GetByNameWithCert", err)
+		}
+
+		// The certificate store keeps the password in the clear, the
+		// database encrypted; only the latter decrypts.
+		if password, err := uc.decrypt(d.ProvisioningCertPassword); err == nil {
+			d.ProvisioningCertPassword = password
+		}
+
+		d.TenantID, d.Version = "", ""
+		c.Domains = append(c.Domains, *d)
+	}
+
+	return nil
+}
+
+func (uc *UseCase) collectProfiles(ctx context.Context, c *contents, tenantID string) error {
+	list, err := all(ctx, uc.repos.Profiles.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Backup", "uc.repos.Profiles.Get", err)
+	}
+
+	c.Profiles = make([]entity.Profile, 0, len(list))
+
+	for i := range list {
+		// The list leaves out the passwords.
+		p, err := uc.repos.Profiles.GetByName(ctx, list[i].ProfileName, tenantID)
+		if err != nil {
+			return ErrDatabase.Wrap("Backup", "uc.repos.Profiles.GetByName", err)
+		}
+
+		if p == nil {
+			continue // deleted since it was listed
+		}
+
+		if p.AMTPassword, err = uc.decrypt(p.AMTPassword); err != nil {
+			return ErrBackupUseCase.Wrap("Backup", "decrypt AMT password", err)
+		}
+
+		if p.MEBXPassword, err = uc.decrypt(p.MEBXPassword); err != nil {
+			return ErrBackupUseCase.Wrap("Backup", "decrypt MEBx password", err)
+		}
+
+		links, err := uc.repos.ProfileWiFiConfigs.GetByProfileName(ctx, p.ProfileName, tenantID)
+		if err != nil {
+			return ErrDatabase.Wrap("Backup", "uc.repos.ProfileWiFiConfigs.GetByProfileName", err)
+		}
+
+		for j := range links {
+			links[j].TenantID = ""
+		}
+
+		c.ProfileWiFiConfigs = append(c.ProfileWiFiConfigs, links...)
+		c.Profiles = append(c.Profiles, entity.Profile{
+			ProfileName:                p.ProfileName,
+			AMTPassword:                p.AMTPassword,
+			GenerateRandomPassword:     p.GenerateRandomPassword,
+			CIRAConfigName:             p.CIRAConfigName,
+			Activation:                 p.Activation,
+			MEBXPassword:               p.MEBXPassword,
+			GenerateRandomMEBxPassword: p.GenerateRandomMEBxPassword,
+			Tags:                       p.Tags,
+			DHCPEnabled:                p.DHCPEnabled,
+			IPSyncEnabled:              p.IPSyncEnabled,
+			LocalWiFiSyncEnabled:       p.LocalWiFiSyncEnabled,
+			TLSMode:                    p.TLSMode,
+			TLSSigningAuthority:        p.TLSSigningAuthority,
+			UserConsent:                p.UserConsent,
+			IDEREnabled:                p.IDEREnabled,
+			KVMEnabled:                 p.KVMEnabled,
+			SOLEnabled:                 p.SOLEnabled,
+			IEEE8021xProfileName:       p.IEEE8021xProfileName,
+			UEFIWiFiSyncEnabled:        p.UEFIWiFiSyncEnabled,
+		})
+	}
+
+	return nil
+}
+
+func (uc *UseCase) collectDevices(ctx context.Context, c *contents, tenantID string) error {
+	list, err := all(ctx, uc.repos.Devices.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Backup", "uc.repos.Devices.Get", err)
+	}
+
+	c.Devices = make([]entity.Device, 0, len(list))
+
+	for i := range list {
+		// The list leaves out the MPS and MEBx passwords.
+		d, err := uc.repos.Devices.GetByID(ctx, list[i].GUID, tenantID)
+		if err != nil {
+			return ErrDatabase.Wrap("Backup", "uc.repos.Devices.GetByID", err)
+		}
+
+		if d == nil {
+			continue // deleted since it was listed
+		}
+
+		if d.Password, err = uc.decrypt(d.Password); err != nil {
+			return ErrBackupUseCase.Wrap("Backup", "decrypt device password", err)
+		}
+
+		for _, secret := range []*string{d.MPSPassword, d.MEBXPassword} {
+			if secret == nil {
+				continue
+			}
+
+			if *secret, err = uc.decrypt(*secret); err != nil {
+				return ErrBackupUseCase.Wrap("Backup", "decrypt device password", err)
+			}
+		}
+
+		d.TenantID, d.Version = "", ""
+		c.Devices = append(c.Devices, *d)
+	}
+
+	return nil
+}
+
+func (uc *UseCase) decrypt(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	return uc.safeRequirements.Decrypt(s)
+}
+
+func (uc *UseCase) encrypt(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	return uc.safeRequirements.Encrypt(s)
+}
+
+// all pages through a repository list.
+func all[T any](ctx context.Context, get func(ctx context.Context, top, skip int, tenantID string) ([]T, error), tenantID string) ([]T, error) {
+	out := make([]T, 0)
+
+	for skip := 0; ; skip += pageSize {
+		page, err := get(ctx, pageSize, skip, tenantID)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, page...)
+
+		if len(page) < pageSize {
+			return out, nil
+		}
+	}
+}
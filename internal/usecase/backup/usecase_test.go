@@ -0,0 +1,189 @@
+package backup_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	mockcrypto "github.com/device-management-toolkit/console/internal/mocks/crypto"
+	"github.com/device-management-toolkit/console/internal/usecase/backup"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+const passphrase = "correct horse battery"
+
+type backupMocks struct {
+	cira      *mocks.MockCIRAConfigsRepository
+	ieee      *mocks.MockIEEE8021xConfigsRepository
+	wireless  *mocks.MockWiFiConfigsRepository
+	domains   *mocks.MockDomainsRepository
+	profiles  *mocks.MockProfilesRepository
+	links     *mocks.MockProfileWiFiConfigsRepository
+	devices   *mocks.MockDeviceManagementRepository
+	certs     *mocks.MockDomainCerts
+	transacts *mocks.MockTransactor
+}
+
+func backupTest(t *testing.T) (*backup.UseCase, *backupMocks) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	m := &backupMocks{
+		cira:      mocks.NewMockCIRAConfigsRepository(mockCtl),
+		ieee:      mocks.NewMockIEEE8021xConfigsRepository(mockCtl),
+		wireless:  mocks.NewMockWiFiConfigsRepository(mockCtl),
+		domains:   mocks.NewMockDomainsRepository(mockCtl),
+		profiles:  mocks.NewMockProfilesRepository(mockCtl),
+		links:     mocks.NewMockProfileWiFiConfigsRepository(mockCtl),
+		devices:   mocks.NewMockDeviceManagementRepository(mockCtl),
+		certs:     mocks.NewMockDomainCerts(mockCtl),
+		transacts: mocks.NewMockTransactor(mockCtl),
+	}
+
+	uc := backup.New(backup.Repositories{
+		CIRAConfigs:        m.cira,
+		IEEE8021xConfigs:   m.ieee,
+		WirelessConfigs:    m.wireless,
+		Domains:            m.domains,
+		Profiles:           m.profiles,
+		ProfileWiFiConfigs: m.links,
+		Devices:            m.devices,
+		Transactor:         m.transacts,
+	}, m.certs, logger.New("error"), mockcrypto.MockCrypto{})
+
+	return uc, m
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// archive backs up one entity of each kind from tenant t1.
+func archive(t *testing.T) []byte {
+	t.Helper()
+
+	uc, m := backupTest(t)
+	ctx := context.Background()
+
+	m.cira.EXPECT().Get(ctx, 100, 0, "t1").Return([]entity.CIRAConfig{{ConfigName: "cira", Password: "sealed", TenantID: "t1"}}, nil)
+	m.ieee.EXPECT().Get(ctx, 100, 0, "t1").Return([]entity.IEEE8021xConfig{{ProfileName: "eap", TenantID: "t1"}}, nil)
+	m.wireless.EXPECT().Get(ctx, 100, 0, "t1").Return([]entity.WirelessConfig{{ProfileName: "wifi", PSKPassphrase: "sealed", IEEE8021xProfileName: ptr("eap"), TenantID: "t1"}}, nil)
+	m.domains.EXPECT().Get(ctx, 100, 0, "t1").Return([]entity.Domain{{ProfileName: "dom"}}, nil)
+	m.certs.EXPECT().GetByNameWithCert(ctx, "dom", "t1").
+		Return(&entity.Domain{ProfileName: "dom", DomainSuffix: "example.com", ProvisioningCert: "pfx", ProvisioningCertPassword: "sealed", TenantID: "t1"}, nil)
+	m.profiles.EXPECT().Get(ctx, 100, 0, "t1").Return([]entity.Profile{{ProfileName: "acm"}}, nil)
+	m.profiles.EXPECT().GetByName(ctx, "acm", "t1").
+		Return(&entity.Profile{ProfileName: "acm", Activation: "acmactivate", AMTPassword: "sealed", CIRAConfigName: ptr("cira"), TenantID: "t1"}, nil)
+	m.links.EXPECT().GetByProfileName(ctx, "acm", "t1").
+		Return([]entity.ProfileWiFiConfigs{{Priority: 1, ProfileName: "acm", WirelessProfileName: "wifi", TenantID: "t1"}}, nil)
+	m.devices.EXPECT().Get(ctx, 100, 0, "t1").Return([]entity.Device{{GUID: "g1"}}, nil)
+	m.devices.EXPECT().GetByID(ctx, "g1", "t1").Return(&entity.Device{GUID: "g1", Password: "sealed", MPSPassword: ptr("sealed"), TenantID: "t1"}, nil)
+
+	data, err := uc.Backup(ctx, dto.BackupRequest{Passphrase: passphrase, IncludeDevices: true}, "t1")
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestBackup_ShortPassphrase(t *testing.T) {
+	t.Parallel()
+
+	uc, _ := backupTest(t)
+
+	_, err := uc.Backup(context.Background(), dto.BackupRequest{Passphrase: "short"}, "t1")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+}
+
+func TestRestore_Rename(t *testing.T) {
+	t.Parallel()
+
+	data := archive(t)
+	uc, m := backupTest(t)
+	ctx := context.Background()
+
+	m.transacts.EXPECT().InTx(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+
+	m.ieee.EXPECT().GetByName(ctx, "eap", "t2").Return(nil, nil)
+	m.ieee.EXPECT().Insert(ctx, &entity.IEEE8021xConfig{ProfileName: "eap", TenantID: "t2"}).Return("", nil)
+
+	// The CIRA config exists, so it is restored under a new name...
+	m.cira.EXPECT().GetByName(ctx, "cira", "t2").Return(&entity.CIRAConfig{ConfigName: "cira"}, nil)
+	m.cira.EXPECT().GetByName(ctx, "cira-restored", "t2").Return(nil, nil)
+	m.cira.EXPECT().Insert(ctx, &entity.CIRAConfig{ConfigName: "cira-restored", Password: "encrypted", TenantID: "t2"}).Return("", nil)
+
+	m.wireless.EXPECT().GetByName(ctx, "wifi", "t2").Return(nil, nil)
+	m.wireless.EXPECT().Insert(ctx, &entity.WirelessConfig{ProfileName: "wifi", PSKPassphrase: "encrypted", IEEE8021xProfileName: ptr("eap"), TenantID: "t2"}).Return("", nil)
+
+	m.domains.EXPECT().GetDomainByDomainSuffix(ctx, "example.com", "t2").Return(nil, nil)
+	m.domains.EXPECT().GetByName(ctx, "dom", "t2").Return(nil, nil)
+	m.domains.EXPECT().Insert(ctx, &entity.Domain{
+		ProfileName: "dom", DomainSuffix: "example.com", ProvisioningCert: "pfx", ProvisioningCertPassword: "encrypted", TenantID: "t2",
+	}).Return("", nil)
+
+	// ...and the profile that uses it follows.
+	m.profiles.EXPECT().GetByName(ctx, "acm", "t2").Return(nil, nil)
+	m.profiles.EXPECT().Insert(ctx, &entity.Profile{
+		ProfileName: "acm", Activation: "acmactivate", AMTPassword: "encrypted", CIRAConfigName: ptr("cira-restored"), TenantID: "t2",
+	}).Return("", nil)
+	m.links.EXPECT().Insert(ctx, &entity.ProfileWiFiConfigs{Priority: 1, ProfileName: "acm", WirelessProfileName: "wifi", TenantID: "t2"}).Return("", nil)
+
+	m.devices.EXPECT().GetByID(ctx, "g1", "t2").Return(&entity.Device{GUID: "g1"}, nil)
+
+	result, err := uc.Restore(ctx, dto.RestoreRequest{Passphrase: passphrase, OnConflict: dto.ConflictRename, Archive: data}, "t2")
+	require.NoError(t, err)
+	require.Equal(t, []dto.RestoreItem{
+		{Kind: "ieee8021xConfig", Name: "eap", Action: dto.RestoreCreated},
+		{Kind: "ciraConfig", Name: "cira", Action: dto.RestoreRenamed, RestoredAs: "cira-restored"},
+		{Kind: "wirelessConfig", Name: "wifi", Action: dto.RestoreCreated},
+		{Kind: "domain", Name: "dom", Action: dto.RestoreCreated},
+		{Kind: "profile", Name: "acm", Action: dto.RestoreCreated},
+		{Kind: "device", Name: "g1", Action: dto.RestoreSkipped, Note: "devices are not renamed"},
+	}, result.Items)
+}
+
+func TestRestore_DryRunOverwrite(t *testing.T) {
+	t.Parallel()
+
+	data := archive(t)
+	uc, m := backupTest(t)
+	ctx := context.Background()
+
+	// No transaction and no writes: only lookups.
+	m.ieee.EXPECT().GetByName(ctx, "eap", "t2").Return(&entity.IEEE8021xConfig{ProfileName: "eap"}, nil)
+	m.cira.EXPECT().GetByName(ctx, "cira", "t2").Return(nil, nil)
+	m.wireless.EXPECT().GetByName(ctx, "wifi", "t2").Return(nil, nil)
+	m.domains.EXPECT().GetDomainByDomainSuffix(ctx, "example.com", "t2").Return(&entity.Domain{ProfileName: "other"}, nil)
+	m.profiles.EXPECT().GetByName(ctx, "acm", "t2").Return(&entity.Profile{ProfileName: "acm"}, nil)
+	m.devices.EXPECT().GetByID(ctx, "g1", "t2").Return(nil, nil)
+
+	result, err := uc.Restore(ctx, dto.RestoreRequest{Passphrase: passphrase, OnConflict: dto.ConflictOverwrite, DryRun: true, Archive: data}, "t2")
+	require.NoError(t, err)
+	require.True(t, result.DryRun)
+	require.Equal(t, []dto.RestoreItem{
+		{Kind: "ieee8021xConfig", Name: "eap", Action: dto.RestoreOverwritten},
+		{Kind: "ciraConfig", Name: "cira", Action: dto.RestoreCreated},
+		{Kind: "wirelessConfig", Name: "wifi", Action: dto.RestoreCreated},
+		{Kind: "domain", Name: "dom", Action: dto.RestoreSkipped, Note: `domain suffix "example.com" is in use by domain "other"`},
+		{Kind: "profile", Name: "acm", Action: dto.RestoreOverwritten},
+		{Kind: "device", Name: "g1", Action: dto.RestoreCreated},
+	}, result.Items)
+}
+
+func TestRestore_Invalid(t *testing.T) {
+	t.Parallel()
+
+	data := archive(t)
+	uc, _ := backupTest(t)
+
+	_, err := uc.Restore(context.Background(), dto.RestoreRequest{Passphrase: "not the passphrase", Archive: data}, "t2")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+
+	_, err = uc.Restore(context.Background(), dto.RestoreRequest{Passphrase: passphrase, Archive: []byte(`{}`)}, "t2")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+}
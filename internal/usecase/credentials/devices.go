@@ -0,0 +1,175 @@
+package credentials
+
+import (
+	"context"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/internal/versions"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// deviceRepository keeps device passwords in the secret store.
+type deviceRepository struct {
+	devices.Repository
+	trash trash.Repository
+	vault vault
+}
+
+// NewDeviceRepository wraps r so that device passwords are kept in store.
+// A device whose credentials could not be stored is purged from trashRepo.
+func NewDeviceRepository(r devices.Repository, trashRepo trash.Repository, store ObjectStorager, crypto security.Cryptor, log logger.Interface) devices.Repository {
+	return deviceRepository{
+		Repository: r,
+		trash:      trashRepo,
+		vault:      vault{store: store, crypto: crypto, log: log},
+	}
+}
+
+func deviceSecrets(d *entity.Device) map[string]*string {
+	return map[string]*string{
+		"password":     &d.Password,
+		"mpspassword":  d.MPSPassword,
+		"mebxpassword": d.MEBXPassword,
+	}
+}
+
+// copyDevice copies d, down to its optional passwords, so that the caller's
+// entity keeps its secrets.
+func copyDevice(d *entity.Device) *entity.Device {
+	c := *d
+
+	for _, p := range []**string{&c.MPSPassword, &c.MEBXPassword} {
+		if *p != nil {
+			v := **p
+			*p = &v
+		}
+	}
+
+	return &c
+}
+
+func (r deviceRepository) open(call string, d *entity.Device) error {
+	return r.vault.open(call, &d.Password, d.MPSPassword, d.MEBXPassword)
+}
+
+func (r deviceRepository) openAll(call string, list []entity.Device) ([]entity.Device, error) {
+	for i := range list {
+		if err := r.open(call, &list[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+func (r deviceRepository) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.Device, error) {
+	list, err := r.Repository.Get(ctx, top, skip, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.openAll("Get", list)
+}
+
+func (r deviceRepository) GetByID(ctx context.Context, guid, tenantID string) (*entity.Device, error) {
+	d, err := r.Repository.GetByID(ctx, guid, tenantID)
+	if err != nil || d == nil {
+		return d, err
+	}
+
+	return d, r.open("GetByID", d)
+}
+
+func (r deviceRepository) GetByGUID(ctx context.Context, guid string) (*entity.Device, error) {
+	d, err := r.Repository.GetByGUID(ctx, guid)
+	if err != nil || d == nil {
+		return d, err
+	}
+
+	return d, r.open("GetByGUID", d)
+}
+
+func (r deviceRepository) GetByTags(ctx context.Context, tags []string, method string, limit, offset int, tenantID string) ([]entity.Device, error) {
+	list, err := r.Repository.GetByTags(ctx, tags, method, limit, offset, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.openAll("GetByTags", list)
+}
+
+func (r deviceRepository) GetByColumn(ctx context.Context, columnName, queryValue, tenantID string) ([]entity.Device, error) {
+	list, err := r.Repository.GetByColumn(ctx, columnName, queryValue, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.openAll("GetByColumn", list)
+}
+
+// Insert writes the row first, so that a duplicate leaves the credentials of
+// the device already stored alone.
+func (r deviceRepository) Insert(ctx context.Context, d *entity.Device) (string, error) {
+	stored := copyDevice(d)
+	key := deviceKey(d.TenantID, d.GUID)
+
+	plainTexts, err := r.vault.extract("Insert", key, deviceSecrets(stored))
+	if err != nil {
+		return "", err
+	}
+
+	id, err := r.Repository.Insert(ctx, stored)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := r.vault.put("Insert", key, plainTexts); err != nil {
+		if deleted, _ := r.Repository.Delete(ctx, d.GUID, d.TenantID); deleted {
+			_, _ = r.trash.Purge(ctx, entity.TrashDevices, d.GUID, d.TenantID)
+		}
+
+		return "", err
+	}
+
+	return id, nil
+}
+
+// Update writes the credentials first, so that the row never references
+// ones not stored, and puts them back if the row is then not updated.
+func (r deviceRepository) Update(ctx context.Context, d *entity.Device) (bool, error) {
+	stored := copyDevice(d)
+	key := deviceKey(d.TenantID, d.GUID)
+
+	plainTexts, err := r.vault.extract("Update", key, deviceSecrets(stored))
+	if err != nil {
+		return false, err
+	}
+
+	// An update refused for a stale version must leave the secrets alone too.
+	if _, ok := versions.IfMatch(ctx); ok {
+		current, err := r.Repository.GetByID(ctx, d.GUID, d.TenantID)
+		if err != nil {
+			return false, err
+		}
+
+		if current != nil && versions.Stale(ctx, current.Version) {
+			return false, ErrVersionMismatch.Wrap("device " + d.GUID + " has changed")
+		}
+	}
+
+	undo, err := r.vault.put("Update", key, plainTexts)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := r.Repository.Update(ctx, stored)
+	if err != nil || !updated {
+		undo()
+	}
+
+	return updated, err
+}
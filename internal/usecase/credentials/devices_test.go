@@ -0,0 +1,152 @@
+package credentials_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/credentials"
+	"github.com/device-management-toolkit/console/internal/versions"
+	"github.com/device-management-toolkit/console/pkg/logger"
+	secrets "github.com/device-management-toolkit/console/pkg/secrets/vault"
+)
+
+var errStore = errors.New("store unavailable")
+
+// objectStore is a credentials.ObjectStorager in memory. Its writes fail
+// with err, and its reads with getErr, if set.
+type objectStore struct {
+	objects map[string]map[string]string
+	err     error
+	getErr  error
+}
+
+func (s *objectStore) GetKeyValue(_ string) (string, error) { return "", nil }
+
+func (s *objectStore) SetKeyValue(_, _ string) error { return nil }
+
+func (s *objectStore) DeleteKeyValue(key string) error {
+	delete(s.objects, key)
+
+	return nil
+}
+
+func (s *objectStore) GetObject(key string) (map[string]string, error) {
+	if s.getErr != nil {
+		return nil, s.getErr
+	}
+
+	object, ok := s.objects[key]
+	if !ok {
+		return nil, secrets.ErrSecretNotFound
+	}
+
+	return object, nil
+}
+
+func (s *objectStore) SetObject(key string, data map[string]string) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.objects[key] = data
+
+	return nil
+}
+
+func TestDeviceRepository(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDeviceManagementRepository(ctrl)
+	trashRepo := mocks.NewMockTrashRepository(ctrl)
+	store := &objectStore{objects: map[string]map[string]string{}}
+	r := credentials.NewDeviceRepository(repo, trashRepo, store, mocks.MockCrypto{}, logger.New("error"))
+	trash := credentials.NewTrashRepository(trashRepo, store, logger.New("error"))
+
+	mps := "sealed mps"
+	d := &entity.Device{GUID: "guid", TenantID: "t1", Password: "sealed", MPSPassword: &mps}
+
+	var row entity.Device
+
+	repo.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, stored *entity.Device) (string, error) {
+		row = *stored
+
+		return "", nil
+	})
+
+	_, err := r.Insert(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, "sealed", d.Password, "the caller's entity keeps its secrets")
+	require.Equal(t, "sealed mps", mps)
+	require.True(t, credentials.IsReference(row.Password))
+	require.True(t, credentials.IsReference(*row.MPSPassword))
+	require.Nil(t, row.MEBXPassword)
+	require.Equal(t, map[string]string{"password": "decrypted", "mpspassword": "decrypted"}, store.objects["credentials/devices/t1/guid"])
+
+	repo.EXPECT().GetByID(ctx, "guid", "t1").Return(&row, nil)
+
+	got, err := r.GetByID(ctx, "guid", "t1")
+	require.NoError(t, err)
+	require.Equal(t, "encrypted", got.Password)
+	require.Equal(t, "encrypted", *got.MPSPassword)
+
+	// An update refused for a stale version leaves the stored secrets too.
+	stale := versions.WithIfMatch(ctx, []string{"old"})
+	row.Version = "new"
+
+	repo.EXPECT().GetByID(stale, "guid", "t1").Return(&row, nil)
+
+	_, err = r.Update(stale, &entity.Device{GUID: "guid", TenantID: "t1", Password: "changed"})
+	require.ErrorAs(t, err, &repoerrors.PreconditionFailedError{})
+	require.Equal(t, "decrypted", store.objects["credentials/devices/t1/guid"]["password"])
+
+	// An update that changes no row puts the stored secrets back.
+	repo.EXPECT().Update(ctx, gomock.Any()).Return(false, nil)
+
+	updated, err := r.Update(ctx, &entity.Device{GUID: "guid", TenantID: "t1", Password: "changed"})
+	require.NoError(t, err)
+	require.False(t, updated)
+	require.Equal(t, map[string]string{"password": "decrypted", "mpspassword": "decrypted"}, store.objects["credentials/devices/t1/guid"])
+
+	// A store failure leaves the row as it was.
+	store.getErr = errStore
+
+	_, err = r.Update(ctx, d)
+	require.ErrorAs(t, err, &credentials.StoreError{})
+	require.Equal(t, "decrypted", store.objects["credentials/devices/t1/guid"]["password"])
+
+	store.getErr = nil
+	store.err = errStore
+
+	_, err = r.Update(ctx, d)
+	require.ErrorAs(t, err, &credentials.StoreError{})
+
+	// A device that could not be stored is taken back, trash and all.
+	repo.EXPECT().Insert(ctx, gomock.Any()).Return("", nil)
+	repo.EXPECT().Delete(ctx, "other", "t1").Return(true, nil)
+	trashRepo.EXPECT().Purge(ctx, entity.TrashDevices, "other", "t1").Return(true, nil)
+
+	_, err = r.Insert(ctx, &entity.Device{GUID: "other", TenantID: "t1", Password: "sealed"})
+	require.ErrorAs(t, err, &credentials.StoreError{})
+
+	// The trash keeps the credentials of a deleted device until it is purged.
+	repo.EXPECT().Delete(ctx, "guid", "t1").Return(true, nil)
+
+	_, err = r.Delete(ctx, "guid", "t1")
+	require.NoError(t, err)
+	require.Len(t, store.objects, 1)
+
+	trashRepo.EXPECT().Purge(ctx, entity.TrashDevices, "guid", "t1").Return(true, nil)
+
+	_, err = trash.Purge(ctx, entity.TrashDevices, "guid", "t1")
+	require.NoError(t, err)
+	require.Empty(t, store.objects)
+}
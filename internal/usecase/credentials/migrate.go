@@ -0,0 +1,219 @@
+package credentials
+
+import (
+	"context"
+	"slices"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/audit"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/internal/usecase/profiles"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// Kinds of entity with credentials.
+const (
+	KindDevices  = "devices"
+	KindProfiles = "profiles"
+)
+
+const (
+	// defaultTenant is the tenant of single-tenant deployments, always migrated.
+	defaultTenant = ""
+	// DefaultBatchSize is how many entities are read per query by default.
+	DefaultBatchSize = 100
+)
+
+var ErrDatabase = repoerrors.DatabaseError{Console: ErrCredentialsUseCase}
+
+type (
+	// Repositories are the database repositories, not wrapped, and the audit
+	// trail that names the tenants.
+	Repositories struct {
+		Devices  devices.Repository
+		Profiles profiles.Repository
+		Trash    trash.Repository
+		Audit    audit.Repository
+	}
+
+	// Options of a migration. Tenants are migrated besides the default
+	// tenant and every tenant named in the audit trail.
+	Options struct {
+		Tenants   []string
+		BatchSize int
+	}
+
+	// Report tells what a migration did.
+	Report struct {
+		Tenants []string
+		Kinds   []KindReport
+	}
+
+	// KindReport counts the entities of one kind whose credentials were moved
+	// to the secret store, and those already there or without any.
+	KindReport struct {
+		Kind   string
+		Moved  int
+		Stored int
+	}
+)
+
+// Migration moves the credentials of existing devices and profiles from the
+// database to the secret store. It can be run again, say after it was
+// interrupted, and moves only what is left.
+type Migration struct {
+	repos    Repositories
+	devices  devices.Repository
+	profiles profiles.Repository
+	log      logger.Interface
+}
+
+// NewMigration -.
+func NewMigration(repos Repositories, store ObjectStorager, crypto security.Cryptor, log logger.Interface) *Migration {
+	return &Migration{
+		repos:    repos,
+		devices:  NewDeviceRepository(repos.Devices, repos.Trash, store, crypto, log),
+		profiles: NewProfileRepository(repos.Profiles, repos.Trash, store, crypto, log),
+		log:      log,
+	}
+}
+
+// Run moves the credentials of every tenant's devices and profiles.
+func (m *Migration) Run(ctx context.Context, opts Options) (*Report, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	tenants, err := m.tenants(ctx, opts.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Tenants: tenants}
+
+	devicesReport, err := migrateKind(ctx, KindDevices, tenants, opts.BatchSize,
+		m.repos.Devices.Get,
+		func(ctx context.Context, d *entity.Device) (*entity.Device, error) {
+			return m.repos.Devices.GetByID(ctx, d.GUID, d.TenantID)
+		},
+		func(d *entity.Device) []*string { return []*string{&d.Password, d.MPSPassword, d.MEBXPassword} },
+		m.devices.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	profilesReport, err := migrateKind(ctx, KindProfiles, tenants, opts.BatchSize,
+		m.repos.Profiles.Get,
+		func(ctx context.Context, p *entity.Profile) (*entity.Profile, error) {
+			return m.repos.Profiles.GetByName(ctx, p.ProfileName, p.TenantID)
+		},
+		func(p *entity.Profile) []*string { return []*string{&p.AMTPassword, &p.MEBXPassword} },
+		m.profiles.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Kinds = []KindReport{devicesReport, profilesReport}
+
+	m.log.Info("credentials migrated to the secret store: %d devices, %d profiles", devicesReport.Moved, profilesReport.Moved)
+
+	return report, nil
+}
+
+// tenants lists the default tenant, the given ones and those in the audit
+// trail.
+func (m *Migration) tenants(ctx context.Context, given []string) ([]string, error) {
+	tenants := append([]string{defaultTenant}, given...)
+
+	var seq int64
+
+	for {
+		page, err := m.repos.Audit.GetAfter(ctx, seq, DefaultBatchSize)
+		if err != nil {
+			return nil, ErrDatabase.Wrap("tenants", "m.repos.Audit.GetAfter", err)
+		}
+
+		for i := range page {
+			tenants = append(tenants, page[i].TenantID)
+		}
+
+		if len(page) < DefaultBatchSize {
+			break
+		}
+
+		seq = page[len(page)-1].Seq
+	}
+
+	slices.Sort(tenants)
+
+	return slices.Compact(tenants), nil
+}
+
+// migrateKind pages through the entities of one kind, loads each in full and
+// writes those with credentials still in the database through the wrapped
+// repository, which moves them.
+func migrateKind[T any](
+	ctx context.Context,
+	kind string,
+	tenants []string,
+	batchSize int,
+	get func(ctx context.Context, top, skip int, tenantID string) ([]T, error),
+	load func(ctx context.Context, listed *T) (*T, error),
+	secrets func(*T) []*string,
+	update func(ctx context.Context, e *T) (bool, error),
+) (KindReport, error) {
+	report := KindReport{Kind: kind}
+
+	for _, tenantID := range tenants {
+		for skip := 0; ; skip += batchSize {
+			page, err := get(ctx, batchSize, skip, tenantID)
+			if err != nil {
+				return report, ErrDatabase.Wrap("migrate", kind+" get", err)
+			}
+
+			for i := range page {
+				e, err := load(ctx, &page[i])
+				if err != nil {
+					return report, ErrDatabase.Wrap("migrate", kind+" load", err)
+				}
+
+				if e == nil {
+					continue // deleted since listed
+				}
+
+				if !inDatabase(secrets(e)) {
+					report.Stored++
+
+					continue
+				}
+
+				if _, err := update(ctx, e); err != nil {
+					return report, err
+				}
+
+				report.Moved++
+			}
+
+			if len(page) < batchSize {
+				break
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// inDatabase tells whether any of the secrets is kept in the database.
+func inDatabase(secrets []*string) bool {
+	for _, secret := range secrets {
+		if secret != nil && *secret != "" && !IsReference(*secret) {
+			return true
+		}
+	}
+
+	return false
+}
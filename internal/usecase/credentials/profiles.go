@@ -0,0 +1,125 @@
+package credentials
+
+import (
+	"context"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/profiles"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/internal/versions"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// profileRepository keeps the AMT and MEBx passwords of profiles in the
+// secret store.
+type profileRepository struct {
+	profiles.Repository
+	trash trash.Repository
+	vault vault
+}
+
+// NewProfileRepository wraps r so that profile passwords are kept in store.
+// A profile whose credentials could not be stored is purged from trashRepo.
+func NewProfileRepository(r profiles.Repository, trashRepo trash.Repository, store ObjectStorager, crypto security.Cryptor, log logger.Interface) profiles.Repository {
+	return profileRepository{
+		Repository: r,
+		trash:      trashRepo,
+		vault:      vault{store: store, crypto: crypto, log: log},
+	}
+}
+
+func profileSecrets(p *entity.Profile) map[string]*string {
+	return map[string]*string{
+		"amtpassword":  &p.AMTPassword,
+		"mebxpassword": &p.MEBXPassword,
+	}
+}
+
+func (r profileRepository) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.Profile, error) {
+	list, err := r.Repository.Get(ctx, top, skip, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		if err := r.vault.open("Get", &list[i].AMTPassword, &list[i].MEBXPassword); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+func (r profileRepository) GetByName(ctx context.Context, profileName, tenantID string) (*entity.Profile, error) {
+	p, err := r.Repository.GetByName(ctx, profileName, tenantID)
+	if err != nil || p == nil {
+		return p, err
+	}
+
+	return p, r.vault.open("GetByName", &p.AMTPassword, &p.MEBXPassword)
+}
+
+// Insert writes the row first, so that a duplicate leaves the credentials of
+// the profile already stored alone.
+func (r profileRepository) Insert(ctx context.Context, p *entity.Profile) (string, error) {
+	stored := *p
+	key := profileKey(p.TenantID, p.ProfileName)
+
+	plainTexts, err := r.vault.extract("Insert", key, profileSecrets(&stored))
+	if err != nil {
+		return "", err
+	}
+
+	id, err := r.Repository.Insert(ctx, &stored)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := r.vault.put("Insert", key, plainTexts); err != nil {
+		if deleted, _ := r.Repository.Delete(ctx, p.ProfileName, p.TenantID); deleted {
+			_, _ = r.trash.Purge(ctx, entity.TrashProfiles, p.ProfileName, p.TenantID)
+		}
+
+		return "", err
+	}
+
+	return id, nil
+}
+
+// Update writes the credentials first, so that the row never references
+// ones not stored, and puts them back if the row is then not updated.
+func (r profileRepository) Update(ctx context.Context, p *entity.Profile) (bool, error) {
+	stored := *p
+	key := profileKey(p.TenantID, p.ProfileName)
+
+	plainTexts, err := r.vault.extract("Update", key, profileSecrets(&stored))
+	if err != nil {
+		return false, err
+	}
+
+	// An update refused for a stale version must leave the secrets alone too.
+	if _, ok := versions.IfMatch(ctx); ok {
+		current, err := r.Repository.GetByName(ctx, p.ProfileName, p.TenantID)
+		if err != nil {
+			return false, err
+		}
+
+		if current != nil && versions.Stale(ctx, current.Version) {
+			return false, ErrVersionMismatch.Wrap("profile " + p.ProfileName + " has changed")
+		}
+	}
+
+	undo, err := r.vault.put("Update", key, plainTexts)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := r.Repository.Update(ctx, &stored)
+	if err != nil || !updated {
+		undo()
+	}
+
+	return updated, err
+}
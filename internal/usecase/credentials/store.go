@@ -0,0 +1,210 @@
+// Package credentials keeps the passwords of devices and profiles in the
+// configured secret store instead of the database, which then only holds
+// references to them.
+//
+// The repositories here wrap the database ones. On the way in they move each
+// encrypted secret, decrypted, into a secret store object per device or
+// profile, keyed by tenant and GUID or profile name, and write a reference in
+// its place; on the way out they resolve references into secrets encrypted
+// with the console's key again. The use cases above them see the same
+// entities either way, and rows written before the store was enabled keep
+// working until migrated.
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+	secrets "github.com/device-management-toolkit/console/pkg/secrets/vault"
+)
+
+// referencePrefix marks a column holding a reference, as in
+// "secretstore:credentials/devices/{tenantID}/{guid}#password".
+const referencePrefix = "secretstore:"
+
+var (
+	ErrCredentialsUseCase = consoleerrors.CreateConsoleError("CredentialsUseCase")
+	ErrStore              = StoreError{Console: ErrCredentialsUseCase}
+	// ErrVersionMismatch refuses to store the secrets of an update the
+	// repository would refuse, the entity having changed since.
+	ErrVersionMismatch = repoerrors.PreconditionFailedError{Console: ErrCredentialsUseCase}
+
+	errBadReference = errors.New("malformed secret store reference")
+)
+
+// ObjectStorager is a secret store that keeps several fields per key.
+type ObjectStorager interface {
+	security.Storager
+	GetObject(key string) (map[string]string, error)
+	SetObject(key string, data map[string]string) error
+}
+
+// StoreError -.
+type StoreError struct {
+	Console consoleerrors.InternalError
+}
+
+func (e StoreError) Error() string {
+	return e.Console.Error()
+}
+
+func (e StoreError) Wrap(call, function string, err error) error {
+	_ = e.Console.Wrap(call, function, err)
+	e.Console.Message = "credential store operation failed"
+
+	return e
+}
+
+// deviceKey is the secret store key of a device's credentials.
+// Format: credentials/devices/{tenantID}/{guid}.
+func deviceKey(tenantID, guid string) string {
+	return fmt.Sprintf("credentials/devices/%s/%s", tenantID, guid)
+}
+
+// profileKey is the secret store key of a profile's credentials.
+// Format: credentials/profiles/{tenantID}/{profileName}.
+func profileKey(tenantID, profileName string) string {
+	return fmt.Sprintf("credentials/profiles/%s/%s", tenantID, profileName)
+}
+
+// IsReference tells whether a stored secret is a reference to the secret
+// store rather than the secret itself.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, referencePrefix)
+}
+
+func reference(key, field string) string {
+	return referencePrefix + key + "#" + field
+}
+
+func parseReference(value string) (key, field string, err error) {
+	key, field, ok := strings.Cut(strings.TrimPrefix(value, referencePrefix), "#")
+	if !ok || key == "" || field == "" {
+		return "", "", errBadReference
+	}
+
+	return key, field, nil
+}
+
+// vault moves secrets between entities and the secret store.
+type vault struct {
+	store  ObjectStorager
+	crypto security.Cryptor
+	log    logger.Interface
+}
+
+// extract replaces the secrets, by field name, with references to the
+// object at key and returns them decrypted. Empty and missing secrets and
+// references are left alone.
+func (v vault) extract(call, key string, secrets map[string]*string) (map[string]string, error) {
+	plainTexts := map[string]string{}
+
+	for field, secret := range secrets {
+		if secret == nil || *secret == "" || IsReference(*secret) {
+			continue
+		}
+
+		plainText, err := v.crypto.Decrypt(*secret)
+		if err != nil {
+			return nil, ErrStore.Wrap(call, "v.crypto.Decrypt", err)
+		}
+
+		plainTexts[field] = plainText
+		*secret = reference(key, field)
+	}
+
+	return plainTexts, nil
+}
+
+// put writes the secrets into the object at key, which keeps its other
+// fields. The returned undo puts the object back as it was, for a write of
+// the entity that then fails.
+func (v vault) put(call, key string, plainTexts map[string]string) (undo func(), err error) {
+	if len(plainTexts) == 0 {
+		return func() {}, nil
+	}
+
+	previous, err := v.store.GetObject(key)
+	if err != nil && !errors.Is(err, secrets.ErrSecretNotFound) {
+		return nil, ErrStore.Wrap(call, "v.store.GetObject", err)
+	}
+
+	object := maps.Clone(previous)
+	if object == nil {
+		object = map[string]string{}
+	}
+
+	for field, plainText := range plainTexts {
+		object[field] = plainText
+	}
+
+	if err := v.store.SetObject(key, object); err != nil {
+		return nil, ErrStore.Wrap(call, "v.store.SetObject", err)
+	}
+
+	return func() { v.restore(key, previous) }, nil
+}
+
+// restore writes back the object at key as put found it, or deletes it if
+// there was none. The entity's error is the one reported, so failing to is
+// only logged.
+func (v vault) restore(key string, previous map[string]string) {
+	var err error
+
+	if previous == nil {
+		err = v.store.DeleteKeyValue(key)
+	} else {
+		err = v.store.SetObject(key, previous)
+	}
+
+	if err != nil {
+		v.log.Warn("Failed to restore credentials in the secret store: %s: %v", key, err)
+	}
+}
+
+// open resolves the references among secrets into secrets encrypted with the
+// console's key.
+func (v vault) open(call string, secrets ...*string) error {
+	objects := map[string]map[string]string{}
+
+	for _, secret := range secrets {
+		if secret == nil || !IsReference(*secret) {
+			continue
+		}
+
+		key, field, err := parseReference(*secret)
+		if err != nil {
+			return ErrStore.Wrap(call, "parseReference", err)
+		}
+
+		object, ok := objects[key]
+		if !ok {
+			if object, err = v.store.GetObject(key); err != nil {
+				return ErrStore.Wrap(call, "v.store.GetObject", err)
+			}
+
+			objects[key] = object
+		}
+
+		if *secret, err = v.crypto.Encrypt(object[field]); err != nil {
+			return ErrStore.Wrap(call, "v.crypto.Encrypt", err)
+		}
+	}
+
+	return nil
+}
+
+// forget deletes the object at key. The entity is gone already, so failing
+// to is only logged.
+func (v vault) forget(key string) {
+	if err := v.store.DeleteKeyValue(key); err != nil {
+		v.log.Warn("Failed to delete credentials from the secret store: %s: %v", key, err)
+	}
+}
@@ -0,0 +1,41 @@
+package credentials
+
+import (
+	"context"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// trashRepository forgets the credentials of devices and profiles once they
+// are purged from the trash. Until then they are kept, for a restore.
+type trashRepository struct {
+	trash.Repository
+	vault vault
+}
+
+// NewTrashRepository wraps r so that purging a device or profile deletes its
+// passwords from store.
+func NewTrashRepository(r trash.Repository, store ObjectStorager, log logger.Interface) trash.Repository {
+	return trashRepository{
+		Repository: r,
+		vault:      vault{store: store, log: log},
+	}
+}
+
+func (r trashRepository) Purge(ctx context.Context, kind, id, tenantID string) (bool, error) {
+	purged, err := r.Repository.Purge(ctx, kind, id, tenantID)
+	if err != nil || !purged {
+		return purged, err
+	}
+
+	switch kind {
+	case entity.TrashDevices:
+		r.vault.forget(deviceKey(tenantID, id))
+	case entity.TrashProfiles:
+		r.vault.forget(profileKey(tenantID, id))
+	}
+
+	return purged, nil
+}
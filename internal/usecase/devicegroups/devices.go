@@ -0,0 +1,160 @@
+package devicegroups
+
+import (
+	"context"
+	"slices"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/taglist"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/internal/usecase/tags"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+)
+
+// deviceRepository matches devices against the group rules as they are
+// added and change.
+type deviceRepository struct {
+	devices.Repository
+	uc *UseCase
+}
+
+// NewDeviceRepository wraps r so that the devices it writes are matched
+// against the rules of uc's groups. A failed match is logged: the device was
+// written, and the next change or a refresh of the group catches up.
+func NewDeviceRepository(r devices.Repository, uc *UseCase) devices.Repository {
+	return deviceRepository{Repository: r, uc: uc}
+}
+
+// matched matches device guid, logging a failure.
+func (uc *UseCase) matched(ctx context.Context, guid string) {
+	if err := uc.match(ctx, guid); err != nil {
+		uc.log.Warn("Failed to match device %s against the group rules: %v", guid, err)
+	}
+}
+
+func (r deviceRepository) Insert(ctx context.Context, d *entity.Device) (string, error) {
+	version, err := r.Repository.Insert(ctx, d)
+	if err != nil {
+		return version, err
+	}
+
+	r.uc.matched(ctx, d.GUID)
+
+	return version, nil
+}
+
+func (r deviceRepository) Update(ctx context.Context, d *entity.Device) (bool, error) {
+	updated, err := r.Repository.Update(ctx, d)
+	if err != nil || !updated {
+		return updated, err
+	}
+
+	r.uc.matched(ctx, d.GUID)
+
+	return true, nil
+}
+
+func (r deviceRepository) UpdateConnectionStatus(ctx context.Context, guid string, status bool) error {
+	if err := r.Repository.UpdateConnectionStatus(ctx, guid, status); err != nil {
+		return err
+	}
+
+	r.uc.matched(ctx, guid)
+
+	return nil
+}
+
+// trashRepository matches devices restored from the trash, which a group
+// refreshed meanwhile no longer counts.
+type trashRepository struct {
+	trash.Repository
+	uc *UseCase
+}
+
+// NewTrashRepository wraps r so that restored devices are matched against
+// the rules of uc's groups.
+func NewTrashRepository(r trash.Repository, uc *UseCase) trash.Repository {
+	return trashRepository{Repository: r, uc: uc}
+}
+
+func (r trashRepository) Restore(ctx context.Context, kind, id, tenantID string) (bool, error) {
+	restored, err := r.Repository.Restore(ctx, kind, id, tenantID)
+	if err != nil || !restored || kind != entity.TrashDevices {
+		return restored, err
+	}
+
+	r.uc.matched(ctx, id)
+
+	return true, nil
+}
+
+// tagRepository refreshes the groups with tag rules once tags change
+// fleet-wide: refreshing those few groups is cheaper than matching every
+// device changed.
+type tagRepository struct {
+	tags.Repository
+	uc *UseCase
+}
+
+// NewTagRepository wraps r so that the groups of uc whose rules look at tags
+// are refreshed after an update. A failed refresh is logged, as a failed
+// match is.
+func NewTagRepository(r tags.Repository, uc *UseCase) tags.Repository {
+	return tagRepository{Repository: r, uc: uc}
+}
+
+func (r tagRepository) Update(ctx context.Context, guids, add, remove []string, tenantID string) (int, error) {
+	changed, err := r.Repository.Update(ctx, guids, add, remove, tenantID)
+	if err != nil || changed == 0 {
+		return changed, err
+	}
+
+	if err := r.uc.refreshTagRules(ctx, tenantID); err != nil {
+		r.uc.log.Warn("Failed to refresh the groups with tag rules: %v", err)
+	}
+
+	return changed, nil
+}
+
+// refreshTagRules refreshes the groups of tenantID with a rule on tags.
+func (uc *UseCase) refreshTagRules(ctx context.Context, tenantID string) error {
+	groups, err := uc.all(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	for i := range groups {
+		if !slices.ContainsFunc(groups[i].Rules, func(r entity.DeviceGroupRule) bool { return r.Field == fieldTags }) {
+			continue
+		}
+
+		if err := uc.refresh(ctx, &groups[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TagReferences lists, as "group <name>", the groups of tenantID with a rule
+// on any of tags.
+func (uc *UseCase) TagReferences(ctx context.Context, tags []string, tenantID string) ([]string, error) {
+	groups, err := uc.all(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var references []string
+
+	for i := range groups {
+		if slices.ContainsFunc(groups[i].Rules, func(r entity.DeviceGroupRule) bool {
+			return r.Field == fieldTags && slices.ContainsFunc(taglist.Split(r.Value), func(tag string) bool {
+				return slices.Contains(tags, tag)
+			})
+		}) {
+			references = append(references, "group "+groups[i].Name)
+		}
+	}
+
+	return references, nil
+}
@@ -0,0 +1,49 @@
+package devicegroups
+
+import (
+	"context"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+type (
+	// Repository stores groups with their rules, and their members: those
+	// added by hand, and those matched by the rules, which the use case
+	// keeps current. Members are only ever devices of the group's tenant
+	// that are not in the trash; AddMembers and SetMatches skip the others.
+	Repository interface {
+		GetCount(ctx context.Context, tenantID string) (int, error)
+		Get(ctx context.Context, top, skip int, tenantID string) ([]entity.DeviceGroup, error)
+		GetByID(ctx context.Context, id, tenantID string) (*entity.DeviceGroup, error)
+		Delete(ctx context.Context, id, tenantID string) (bool, error)
+		Update(ctx context.Context, g *entity.DeviceGroup) (bool, error)
+		Insert(ctx context.Context, g *entity.DeviceGroup) error
+		// GetMemberCount, GetMembers and GetMemberGUIDs reach the devices
+		// that are members of any of the groups ids, each once.
+		GetMemberCount(ctx context.Context, ids []string, tenantID string) (int, error)
+		GetMembers(ctx context.Context, ids []string, top, skip int, tenantID string) ([]entity.Device, error)
+		GetMemberGUIDs(ctx context.Context, ids []string, tenantID string) ([]string, error)
+		GetMemberships(ctx context.Context, id, tenantID string) ([]entity.DeviceGroupMember, error)
+		AddMembers(ctx context.Context, id string, guids []string, tenantID string) (int, error)
+		RemoveMembers(ctx context.Context, id string, guids []string, tenantID string) (int, error)
+		// SetMatches replaces the devices the rules of group id match;
+		// SetDeviceMatches replaces the groups whose rules match device guid.
+		SetMatches(ctx context.Context, id string, guids []string, tenantID string) error
+		SetDeviceMatches(ctx context.Context, guid string, ids []string, tenantID string) error
+	}
+	Feature interface {
+		GetCount(ctx context.Context, tenantID string) (int, error)
+		Get(ctx context.Context, top, skip int, tenantID string) ([]dto.DeviceGroup, error)
+		GetByID(ctx context.Context, id, tenantID string) (*dto.DeviceGroup, error)
+		Delete(ctx context.Context, id, tenantID string) error
+		Update(ctx context.Context, g *dto.DeviceGroup) (*dto.DeviceGroup, error)
+		Insert(ctx context.Context, g *dto.DeviceGroup) (*dto.DeviceGroup, error)
+		Refresh(ctx context.Context, id, tenantID string) error
+		GetMemberCount(ctx context.Context, id, tenantID string) (int, error)
+		GetMembers(ctx context.Context, id string, top, skip int, tenantID string) ([]dto.Device, error)
+		AddMembers(ctx context.Context, id string, selection dto.DeviceSelection, tenantID string) (int, error)
+		RemoveMember(ctx context.Context, id, guid, tenantID string) error
+		Resolve(ctx context.Context, selection dto.DeviceSelection, tenantID string) ([]string, error)
+	}
+)
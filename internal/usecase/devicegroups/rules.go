@@ -0,0 +1,277 @@
+package devicegroups
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/taglist"
+)
+
+const (
+	fieldTags             = "tags"
+	fieldHostname         = "hostname"
+	fieldAMTVersion       = "amtVersion"
+	fieldConnectionStatus = "connectionStatus"
+	fieldDeviceInfo       = "deviceInfo."
+
+	opEq    = "eq"
+	opMatch = "match"
+	opAny   = "any"
+	opAll   = "all"
+	opNone  = "none"
+	opGte   = "gte"
+	opLte   = "lte"
+)
+
+var (
+	errUnknownField    = errors.New("unknown rule field")
+	errUnknownOperator = errors.New("operator does not apply to field")
+	errInvalidValue    = errors.New("invalid rule value")
+)
+
+// operators are those each field takes; deviceInfo fields take opEq and
+// opMatch.
+var operators = map[string][]string{
+	fieldTags:             {opAny, opAll, opNone},
+	fieldHostname:         {opEq, opMatch},
+	fieldAMTVersion:       {opEq, opGte, opLte},
+	fieldConnectionStatus: {opEq},
+}
+
+// deviceInfoFields are the deviceInfo fields rules may compare.
+var deviceInfoFields = map[string]func(*dto.DeviceInfo) string{
+	"fwVersion":   func(i *dto.DeviceInfo) string { return i.FWVersion },
+	"fwBuild":     func(i *dto.DeviceInfo) string { return i.FWBuild },
+	"fwSku":       func(i *dto.DeviceInfo) string { return i.FWSku },
+	"currentMode": func(i *dto.DeviceInfo) string { return i.CurrentMode },
+	"features":    func(i *dto.DeviceInfo) string { return i.Features },
+	"ipAddress":   func(i *dto.DeviceInfo) string { return i.IPAddress },
+	"lmsVersion":  func(i *dto.DeviceInfo) string { return i.LMSVersion },
+	"tlsMode":     func(i *dto.DeviceInfo) string { return i.TLSMode },
+}
+
+// validateRule checks that r names a known field, an operator of that field
+// and a value the operator can compare with.
+func validateRule(r *dto.DeviceGroupRule) error {
+	allowed, ok := operators[r.Field]
+
+	if name, isInfo := strings.CutPrefix(r.Field, fieldDeviceInfo); isInfo {
+		if _, ok = deviceInfoFields[name]; ok {
+			allowed = []string{opEq, opMatch}
+		}
+	}
+
+	if !ok {
+		return fmt.Errorf("%w %q", errUnknownField, r.Field)
+	}
+
+	if !slices.Contains(allowed, r.Operator) {
+		return fmt.Errorf("%w: %s %s", errUnknownOperator, r.Field, r.Operator)
+	}
+
+	var err error
+
+	switch {
+	case r.Field == fieldTags:
+		if len(taglist.Split(r.Value)) == 0 {
+			err = errInvalidValue
+		}
+	case r.Field == fieldAMTVersion:
+		if _, ok := parseVersion(r.Value); !ok {
+			err = errInvalidValue
+		}
+	case r.Field == fieldConnectionStatus:
+		_, err = strconv.ParseBool(r.Value)
+	case r.Operator == opMatch:
+		_, err = path.Match(strings.ToLower(r.Value), "")
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w for %s: %q", errInvalidValue, r.Field, r.Value)
+	}
+
+	return nil
+}
+
+// candidate is a device as rules see it.
+type candidate struct {
+	tags      []string
+	hostname  string
+	connected bool
+	info      *dto.DeviceInfo
+}
+
+func newCandidate(d *entity.Device) candidate {
+	return candidate{
+		tags:      taglist.Split(d.Tags),
+		hostname:  d.Hostname,
+		connected: d.ConnectionStatus,
+		info:      deviceInfo(d),
+	}
+}
+
+// matchesAll reports whether c matches every one of rules; no rules match
+// no device, so that a group without rules has no dynamic members.
+func matchesAll(rules []entity.DeviceGroupRule, c *candidate) bool {
+	if len(rules) == 0 {
+		return false
+	}
+
+	for i := range rules {
+		if !matches(&rules[i], c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matches(r *entity.DeviceGroupRule, c *candidate) bool {
+	switch r.Field {
+	case fieldTags:
+		return matchesTags(r.Operator, taglist.Split(r.Value), c.tags)
+	case fieldHostname:
+		return matchesText(r.Operator, r.Value, c.hostname)
+	case fieldAMTVersion:
+		if c.info == nil {
+			return false
+		}
+
+		return matchesVersion(r.Operator, r.Value, c.info.FWVersion)
+	case fieldConnectionStatus:
+		want, err := strconv.ParseBool(r.Value)
+
+		return err == nil && want == c.connected
+	}
+
+	name, _ := strings.CutPrefix(r.Field, fieldDeviceInfo)
+
+	value, ok := deviceInfoFields[name]
+	if !ok || c.info == nil {
+		return false
+	}
+
+	return matchesText(r.Operator, r.Value, value(c.info))
+}
+
+func matchesTags(operator string, want, tags []string) bool {
+	for _, tag := range want {
+		found := slices.Contains(tags, tag)
+
+		switch {
+		case found && operator == opAny:
+			return true
+		case found && operator == opNone:
+			return false
+		case !found && operator == opAll:
+			return false
+		}
+	}
+
+	return operator != opAny
+}
+
+// matchesText compares ignoring case; opMatch takes a pattern of * and ?.
+func matchesText(operator, want, value string) bool {
+	want, value = strings.ToLower(want), strings.ToLower(value)
+
+	if operator == opMatch {
+		matched, err := path.Match(want, value)
+
+		return err == nil && matched
+	}
+
+	return want == value
+}
+
+func matchesVersion(operator, want, value string) bool {
+	w, ok := parseVersion(want)
+	if !ok {
+		return false
+	}
+
+	v, ok := parseVersion(value)
+	if !ok {
+		return false
+	}
+
+	cmp := compareVersions(v, w)
+
+	switch operator {
+	case opGte:
+		return cmp >= 0
+	case opLte:
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// parseVersion parses a dotted version such as 16.1.25.
+func parseVersion(value string) ([]int, bool) {
+	if value == "" {
+		return nil, false
+	}
+
+	parts := strings.Split(value, ".")
+	version := make([]int, len(parts))
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+
+		version[i] = n
+	}
+
+	return version, true
+}
+
+// compareVersions compares part by part; missing parts count as 0, so 16.1
+// equals 16.1.0.
+func compareVersions(a, b []int) int {
+	for i := range max(len(a), len(b)) {
+		var x, y int
+
+		if i < len(a) {
+			x = a[i]
+		}
+
+		if i < len(b) {
+			y = b[i]
+		}
+
+		if x != y {
+			if x < y {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// deviceInfo decodes the deviceInfo of d, or returns nil if it has none or
+// it is not valid.
+func deviceInfo(d *entity.Device) *dto.DeviceInfo {
+	if d.DeviceInfo == "" {
+		return nil
+	}
+
+	var info dto.DeviceInfo
+	if err := json.Unmarshal([]byte(d.DeviceInfo), &info); err != nil {
+		return nil
+	}
+
+	return &info
+}
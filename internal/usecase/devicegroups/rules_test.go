@@ -0,0 +1,63 @@
+package devicegroups
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+func TestMatchesAll(t *testing.T) {
+	t.Parallel()
+
+	device := &entity.Device{
+		Hostname:         "Lab-01",
+		Tags:             "site-a, lab",
+		ConnectionStatus: true,
+		DeviceInfo:       `{"fwVersion":"16.1.25","currentMode":"ACM"}`,
+	}
+
+	tests := []struct {
+		name  string
+		rules []entity.DeviceGroupRule
+		want  bool
+	}{
+		{"no rules", nil, false},
+		{"hostname pattern", []entity.DeviceGroupRule{{Field: "hostname", Operator: "match", Value: "lab-*"}}, true},
+		{"hostname", []entity.DeviceGroupRule{{Field: "hostname", Operator: "eq", Value: "lab-02"}}, false},
+		{"any tag", []entity.DeviceGroupRule{{Field: "tags", Operator: "any", Value: "site-b,lab"}}, true},
+		{"all tags", []entity.DeviceGroupRule{{Field: "tags", Operator: "all", Value: "site-b,lab"}}, false},
+		{"no tag", []entity.DeviceGroupRule{{Field: "tags", Operator: "none", Value: "site-b"}}, true},
+		{"newer AMT", []entity.DeviceGroupRule{{Field: "amtVersion", Operator: "gte", Value: "16"}}, true},
+		{"older AMT", []entity.DeviceGroupRule{{Field: "amtVersion", Operator: "lte", Value: "15.0.50"}}, false},
+		{"connected", []entity.DeviceGroupRule{{Field: "connectionStatus", Operator: "eq", Value: "true"}}, true},
+		{"device info", []entity.DeviceGroupRule{{Field: "deviceInfo.currentMode", Operator: "eq", Value: "acm"}}, true},
+		{"every rule", []entity.DeviceGroupRule{
+			{Field: "hostname", Operator: "match", Value: "lab-*"},
+			{Field: "connectionStatus", Operator: "eq", Value: "false"},
+		}, false},
+	}
+
+	c := newCandidate(device)
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, matchesAll(tc.rules, &c))
+		})
+	}
+}
+
+func TestValidateRule(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateRule(&dto.DeviceGroupRule{Field: "deviceInfo.fwSku", Operator: "match", Value: "*"}))
+	require.ErrorIs(t, validateRule(&dto.DeviceGroupRule{Field: "serial", Operator: "eq", Value: "1"}), errUnknownField)
+	require.ErrorIs(t, validateRule(&dto.DeviceGroupRule{Field: "tags", Operator: "match", Value: "a"}), errUnknownOperator)
+	require.ErrorIs(t, validateRule(&dto.DeviceGroupRule{Field: "tags", Operator: "any", Value: " , "}), errInvalidValue)
+	require.ErrorIs(t, validateRule(&dto.DeviceGroupRule{Field: "amtVersion", Operator: "gte", Value: "sixteen"}), errInvalidValue)
+	require.ErrorIs(t, validateRule(&dto.DeviceGroupRule{Field: "hostname", Operator: "match", Value: "lab-["}), errInvalidValue)
+}
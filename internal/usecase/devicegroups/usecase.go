@@ -0,0 +1,585 @@
+package devicegroups
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/taglist"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// pageSize is the page size of the full scans of groups and devices.
+const pageSize = 100
+
+// UseCase -.
+type UseCase struct {
+	repo    Repository
+	devices devices.Repository
+	log     logger.Interface
+}
+
+var (
+	ErrDeviceGroupsUseCase = consoleerrors.CreateConsoleError("DeviceGroupsUseCase")
+	ErrDatabase            = repoerrors.DatabaseError{Console: ErrDeviceGroupsUseCase}
+	ErrNotFound            = repoerrors.NotFoundError{Console: ErrDeviceGroupsUseCase}
+	ErrNotValid            = dto.NotValidError{Console: ErrDeviceGroupsUseCase}
+
+	errUnknownParent  = errors.New("parent group does not exist")
+	errParentCycle    = errors.New("a group cannot be nested in itself or its subgroups")
+	errHasSubgroups   = errors.New("group has subgroups; move or delete them first")
+	errEmptySelection = errors.New("selection names no devices or groups")
+)
+
+// New -. devices is the repository the rules are matched against; it should
+// be the plain one, not a repository wrapped by NewDeviceRepository.
+func New(r Repository, d devices.Repository, log logger.Interface) *UseCase {
+	return &UseCase{
+		repo:    r,
+		devices: d,
+		log:     log,
+	}
+}
+
+func (uc *UseCase) GetCount(ctx context.Context, tenantID string) (int, error) {
+	count, err := uc.repo.GetCount(ctx, tenantID)
+	if err != nil {
+		return 0, ErrDatabase.Wrap("GetCount", "uc.repo.GetCount", err)
+	}
+
+	return count, nil
+}
+
+func (uc *UseCase) Get(ctx context.Context, top, skip int, tenantID string) ([]dto.DeviceGroup, error) {
+	data, err := uc.repo.Get(ctx, top, skip, tenantID)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Get", "uc.repo.Get", err)
+	}
+
+	d1 := make([]dto.DeviceGroup, len(data))
+
+	for i := range data {
+		d1[i] = *entityToDTO(&data[i])
+	}
+
+	return d1, nil
+}
+
+func (uc *UseCase) GetByID(ctx context.Context, id, tenantID string) (*dto.DeviceGroup, error) {
+	data, err := uc.repo.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("GetByID", "uc.repo.GetByID", err)
+	}
+
+	if data == nil {
+		return nil, ErrNotFound
+	}
+
+	return entityToDTO(data), nil
+}
+
+// Delete refuses to delete a group with subgroups.
+func (uc *UseCase) Delete(ctx context.Context, id, tenantID string) error {
+	groups, err := uc.all(ctx, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Delete", "uc.all", err)
+	}
+
+	for i := range groups {
+		if groups[i].ParentID == id {
+			return ErrNotValid.Wrap("Delete", "subgroups", errHasSubgroups)
+		}
+	}
+
+	isSuccessful, err := uc.repo.Delete(ctx, id, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Delete", "uc.repo.Delete", err)
+	}
+
+	if !isSuccessful {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Update replaces a group's name, description, parent and rules, then
+// matches the rules anew.
+func (uc *UseCase) Update(ctx context.Context, d *dto.DeviceGroup) (*dto.DeviceGroup, error) {
+	g, err := dtoToEntity(d)
+	if err != nil {
+		return nil, ErrNotValid.Wrap("Update", "validate", err)
+	}
+
+	if err := uc.checkParent(ctx, g); err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.repo.Update(ctx, g)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Update", "uc.repo.Update", err)
+	}
+
+	if !updated {
+		return nil, ErrNotFound
+	}
+
+	if err := uc.refresh(ctx, g); err != nil {
+		return nil, ErrDatabase.Wrap("Update", "uc.refresh", err)
+	}
+
+	return uc.GetByID(ctx, d.ID, d.TenantID)
+}
+
+func (uc *UseCase) Insert(ctx context.Context, d *dto.DeviceGroup) (*dto.DeviceGroup, error) {
+	g, err := dtoToEntity(d)
+	if err != nil {
+		return nil, ErrNotValid.Wrap("Insert", "validate", err)
+	}
+
+	g.ID = uuid.NewString()
+	g.CreationDate = time.Now().UTC().Format(time.RFC3339)
+	g.CreatedBy = d.CreatedBy
+
+	if err := uc.checkParent(ctx, g); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Insert(ctx, g); err != nil {
+		return nil, ErrDatabase.Wrap("Insert", "uc.repo.Insert", err)
+	}
+
+	if err := uc.refresh(ctx, g); err != nil {
+		return nil, ErrDatabase.Wrap("Insert", "uc.refresh", err)
+	}
+
+	return entityToDTO(g), nil
+}
+
+// Refresh matches the rules of a group against every device anew. Devices
+// are matched as they change, so this is only needed to catch up with
+// changes made behind the console's back, such as a database restore.
+func (uc *UseCase) Refresh(ctx context.Context, id, tenantID string) error {
+	g, err := uc.repo.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Refresh", "uc.repo.GetByID", err)
+	}
+
+	if g == nil {
+		return ErrNotFound
+	}
+
+	if err := uc.refresh(ctx, g); err != nil {
+		return ErrDatabase.Wrap("Refresh", "uc.refresh", err)
+	}
+
+	return nil
+}
+
+// GetMemberCount counts the members of a group and its subgroups.
+func (uc *UseCase) GetMemberCount(ctx context.Context, id, tenantID string) (int, error) {
+	ids, err := uc.subtree(ctx, id, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	if devicescope.FromContext(ctx) != nil {
+		members, err := uc.scopedMembers(ctx, ids, tenantID)
+		if err != nil {
+			return 0, ErrDatabase.Wrap("GetMemberCount", "uc.scopedMembers", err)
+		}
+
+		return len(members), nil
+	}
+
+	count, err := uc.repo.GetMemberCount(ctx, ids, tenantID)
+	if err != nil {
+		return 0, ErrDatabase.Wrap("GetMemberCount", "uc.repo.GetMemberCount", err)
+	}
+
+	return count, nil
+}
+
+// GetMembers lists the members of a group and its subgroups, in GUID order.
+// Their secrets are left out. With a device scope in ctx, members outside it
+// are left out too, as the device list leaves them out.
+func (uc *UseCase) GetMembers(ctx context.Context, id string, top, skip int, tenantID string) ([]dto.Device, error) {
+	ids, err := uc.subtree(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []entity.Device
+
+	if devicescope.FromContext(ctx) != nil {
+		data, err = uc.scopedMembers(ctx, ids, tenantID)
+		if err != nil {
+			return nil, ErrDatabase.Wrap("GetMembers", "uc.scopedMembers", err)
+		}
+
+		data = page(data, top, skip)
+	} else {
+		data, err = uc.repo.GetMembers(ctx, ids, top, skip, tenantID)
+		if err != nil {
+			return nil, ErrDatabase.Wrap("GetMembers", "uc.repo.GetMembers", err)
+		}
+	}
+
+	d1 := make([]dto.Device, len(data))
+
+	for i := range data {
+		d1[i] = deviceToDTO(&data[i])
+	}
+
+	return d1, nil
+}
+
+// AddMembers adds the selected devices to a group by hand, and returns how
+// many were not members by hand already.
+func (uc *UseCase) AddMembers(ctx context.Context, id string, selection dto.DeviceSelection, tenantID string) (int, error) {
+	g, err := uc.repo.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return 0, ErrDatabase.Wrap("AddMembers", "uc.repo.GetByID", err)
+	}
+
+	if g == nil {
+		return 0, ErrNotFound
+	}
+
+	guids, err := uc.Resolve(ctx, selection, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	added, err := uc.repo.AddMembers(ctx, id, guids, tenantID)
+	if err != nil {
+		return 0, ErrDatabase.Wrap("AddMembers", "uc.repo.AddMembers", err)
+	}
+
+	return added, nil
+}
+
+// RemoveMember removes a device added to a group by hand. A device its rules
+// match stays a member.
+func (uc *UseCase) RemoveMember(ctx context.Context, id, guid, tenantID string) error {
+	removed, err := uc.repo.RemoveMembers(ctx, id, []string{strings.ToLower(guid)}, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("RemoveMember", "uc.repo.RemoveMembers", err)
+	}
+
+	if removed == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Resolve returns the GUIDs of the devices a selection names, sorted and
+// each once. GUIDs named outright are returned whether or not such a device
+// exists; groups must exist.
+func (uc *UseCase) Resolve(ctx context.Context, selection dto.DeviceSelection, tenantID string) ([]string, error) {
+	if len(selection.GUIDs) == 0 && len(selection.Groups) == 0 {
+		return nil, ErrNotValid.Wrap("Resolve", "selection", errEmptySelection)
+	}
+
+	guids := make([]string, 0, len(selection.GUIDs))
+
+	for _, guid := range selection.GUIDs {
+		guids = append(guids, strings.ToLower(guid))
+	}
+
+	if len(selection.Groups) > 0 {
+		groups, err := uc.all(ctx, tenantID)
+		if err != nil {
+			return nil, ErrDatabase.Wrap("Resolve", "uc.all", err)
+		}
+
+		var ids []string
+
+		for _, id := range selection.Groups {
+			subtree, ok := subtreeOf(groups, id)
+			if !ok {
+				return nil, ErrNotFound.WrapWithMessage("Resolve", "selection", "group "+id+" not found")
+			}
+
+			ids = append(ids, subtree...)
+		}
+
+		members, err := uc.repo.GetMemberGUIDs(ctx, ids, tenantID)
+		if err != nil {
+			return nil, ErrDatabase.Wrap("Resolve", "uc.repo.GetMemberGUIDs", err)
+		}
+
+		guids = append(guids, members...)
+	}
+
+	slices.Sort(guids)
+
+	return slices.Compact(guids), nil
+}
+
+// checkParent checks that g's parent exists and is not g or one of its
+// subgroups.
+func (uc *UseCase) checkParent(ctx context.Context, g *entity.DeviceGroup) error {
+	if g.ParentID == "" {
+		return nil
+	}
+
+	groups, err := uc.all(ctx, g.TenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("checkParent", "uc.all", err)
+	}
+
+	parents := make(map[string]string, len(groups))
+
+	for i := range groups {
+		parents[groups[i].ID] = groups[i].ParentID
+	}
+
+	if _, ok := parents[g.ParentID]; !ok {
+		return ErrNotValid.Wrap("checkParent", "parent", errUnknownParent)
+	}
+
+	for id := g.ParentID; id != ""; id = parents[id] {
+		if id == g.ID {
+			return ErrNotValid.Wrap("checkParent", "parent", errParentCycle)
+		}
+	}
+
+	return nil
+}
+
+// subtree returns the IDs of group id and its subgroups.
+func (uc *UseCase) subtree(ctx context.Context, id, tenantID string) ([]string, error) {
+	groups, err := uc.all(ctx, tenantID)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("subtree", "uc.all", err)
+	}
+
+	ids, ok := subtreeOf(groups, id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return ids, nil
+}
+
+// scopedMembers returns every member of the groups ids within the device
+// scope of ctx, in GUID order.
+func (uc *UseCase) scopedMembers(ctx context.Context, ids []string, tenantID string) ([]entity.Device, error) {
+	scope := devicescope.FromContext(ctx)
+	members := []entity.Device{}
+
+	for skip := 0; ; skip += pageSize {
+		batch, err := uc.repo.GetMembers(ctx, ids, pageSize, skip, tenantID)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range batch {
+			if scope.Allows(taglist.Split(batch[i].Tags)) {
+				members = append(members, batch[i])
+			}
+		}
+
+		if len(batch) < pageSize {
+			return members, nil
+		}
+	}
+}
+
+// page returns top members after skip; top 0 returns pageSize of them, as
+// the repositories do.
+func page(members []entity.Device, top, skip int) []entity.Device {
+	if top <= 0 {
+		top = pageSize
+	}
+
+	skip = min(max(skip, 0), len(members))
+	members = members[skip:]
+
+	return members[:min(top, len(members))]
+}
+
+func subtreeOf(groups []entity.DeviceGroup, id string) ([]string, bool) {
+	if !slices.ContainsFunc(groups, func(g entity.DeviceGroup) bool { return g.ID == id }) {
+		return nil, false
+	}
+
+	ids := []string{id}
+
+	for next := 0; next < len(ids); next++ {
+		for i := range groups {
+			if groups[i].ParentID == ids[next] {
+				ids = append(ids, groups[i].ID)
+			}
+		}
+	}
+
+	return ids, true
+}
+
+// all returns every group of tenantID.
+func (uc *UseCase) all(ctx context.Context, tenantID string) ([]entity.DeviceGroup, error) {
+	var groups []entity.DeviceGroup
+
+	for skip := 0; ; skip += pageSize {
+		page, err := uc.repo.Get(ctx, pageSize, skip, tenantID)
+		if err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, page...)
+
+		if len(page) < pageSize {
+			return groups, nil
+		}
+	}
+}
+
+// refresh matches the rules of g against every device of its tenant.
+func (uc *UseCase) refresh(ctx context.Context, g *entity.DeviceGroup) error {
+	guids := []string{}
+
+	if len(g.Rules) > 0 {
+		for skip := 0; ; skip += pageSize {
+			page, err := uc.devices.Get(ctx, pageSize, skip, g.TenantID)
+			if err != nil {
+				return err
+			}
+
+			for i := range page {
+				c := newCandidate(&page[i])
+				if matchesAll(g.Rules, &c) {
+					guids = append(guids, page[i].GUID)
+				}
+			}
+
+			if len(page) < pageSize {
+				break
+			}
+		}
+	}
+
+	return uc.repo.SetMatches(ctx, g.ID, guids, g.TenantID)
+}
+
+// match matches device guid against the rules of every group of its tenant.
+func (uc *UseCase) match(ctx context.Context, guid string) error {
+	d, err := uc.devices.GetByGUID(ctx, guid)
+	if err != nil || d == nil {
+		return err
+	}
+
+	groups, err := uc.all(ctx, d.TenantID)
+	if err != nil {
+		return err
+	}
+
+	c := newCandidate(d)
+	ids := []string{}
+
+	for i := range groups {
+		if matchesAll(groups[i].Rules, &c) {
+			ids = append(ids, groups[i].ID)
+		}
+	}
+
+	return uc.repo.SetDeviceMatches(ctx, d.GUID, ids, d.TenantID)
+}
+
+func dtoToEntity(d *dto.DeviceGroup) (*entity.DeviceGroup, error) {
+	rules := make([]entity.DeviceGroupRule, len(d.Rules))
+
+	for i := range d.Rules {
+		if err := validateRule(&d.Rules[i]); err != nil {
+			return nil, err
+		}
+
+		value := d.Rules[i].Value
+		if d.Rules[i].Field == fieldTags {
+			value = taglist.Join(taglist.Split(value))
+		}
+
+		rules[i] = entity.DeviceGroupRule{
+			Field:    d.Rules[i].Field,
+			Operator: d.Rules[i].Operator,
+			Value:    value,
+		}
+	}
+
+	return &entity.DeviceGroup{
+		ID:          d.ID,
+		Name:        d.Name,
+		Description: d.Description,
+		ParentID:    d.ParentID,
+		Rules:       rules,
+		TenantID:    d.TenantID,
+	}, nil
+}
+
+func entityToDTO(g *entity.DeviceGroup) *dto.DeviceGroup {
+	rules := make([]dto.DeviceGroupRule, len(g.Rules))
+
+	for i := range g.Rules {
+		rules[i] = dto.DeviceGroupRule{
+			Field:    g.Rules[i].Field,
+			Operator: g.Rules[i].Operator,
+			Value:    g.Rules[i].Value,
+		}
+	}
+
+	return &dto.DeviceGroup{
+		ID:           g.ID,
+		Name:         g.Name,
+		Description:  g.Description,
+		ParentID:     g.ParentID,
+		Rules:        rules,
+		CreationDate: parseTime(g.CreationDate),
+		CreatedBy:    g.CreatedBy,
+		TenantID:     g.TenantID,
+	}
+}
+
+// deviceToDTO maps a member, leaving its secrets out.
+func deviceToDTO(d *entity.Device) dto.Device {
+	var tags []string
+	if d.Tags != "" {
+		tags = strings.Split(d.Tags, ",")
+	}
+
+	return dto.Device{
+		ConnectionStatus: d.ConnectionStatus,
+		MPSInstance:      d.MPSInstance,
+		Hostname:         d.Hostname,
+		GUID:             d.GUID,
+		MPSUsername:      d.MPSUsername,
+		Tags:             tags,
+		TenantID:         d.TenantID,
+		FriendlyName:     d.FriendlyName,
+		DNSSuffix:        d.DNSSuffix,
+		DeviceInfo:       deviceInfo(d),
+		UseTLS:           d.UseTLS,
+		AllowSelfSigned:  d.AllowSelfSigned,
+		Version:          d.Version,
+	}
+}
+
+func parseTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+
+	return &t
+}
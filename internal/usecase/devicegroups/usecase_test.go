@@ -0,0 +1,189 @@
+package devicegroups_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/devicegroups"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func groupsTest(t *testing.T) (*devicegroups.UseCase, *mocks.MockDeviceGroupsRepository, *mocks.MockDeviceManagementRepository) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockDeviceGroupsRepository(ctrl)
+	devices := mocks.NewMockDeviceManagementRepository(ctrl)
+
+	return devicegroups.New(repo, devices, logger.New("error")), repo, devices
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, devices := groupsTest(t)
+	ctx := context.Background()
+
+	repo.EXPECT().Insert(ctx, gomock.Any()).Return(nil)
+	devices.EXPECT().Get(ctx, 100, 0, "tenant").Return([]entity.Device{
+		{GUID: "a", Hostname: "lab-01", TenantID: "tenant"},
+		{GUID: "b", Hostname: "office-01", TenantID: "tenant"},
+	}, nil)
+	repo.EXPECT().SetMatches(ctx, gomock.Any(), []string{"a"}, "tenant").Return(nil)
+
+	created, err := useCase.Insert(ctx, &dto.DeviceGroup{
+		Name:      "lab",
+		Rules:     []dto.DeviceGroupRule{{Field: "hostname", Operator: "match", Value: "lab-*"}},
+		CreatedBy: "admin",
+		TenantID:  "tenant",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	require.NotNil(t, created.CreationDate)
+	require.Equal(t, "admin", created.CreatedBy)
+
+	_, err = useCase.Insert(ctx, &dto.DeviceGroup{Name: "lab", Rules: []dto.DeviceGroupRule{{Field: "serial", Operator: "eq"}}})
+	require.ErrorAs(t, err, &dto.NotValidError{})
+}
+
+func TestUpdate_RejectsCycle(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _ := groupsTest(t)
+	ctx := context.Background()
+
+	repo.EXPECT().Get(ctx, 100, 0, "").Return([]entity.DeviceGroup{
+		{ID: "site"},
+		{ID: "lab", ParentID: "site"},
+	}, nil).Times(2)
+
+	_, err := useCase.Update(ctx, &dto.DeviceGroup{ID: "site", Name: "site", ParentID: "lab"})
+	require.ErrorAs(t, err, &dto.NotValidError{})
+
+	_, err = useCase.Update(ctx, &dto.DeviceGroup{ID: "site", Name: "site", ParentID: "missing"})
+	require.ErrorAs(t, err, &dto.NotValidError{})
+}
+
+func TestDelete_RefusesSubgroups(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _ := groupsTest(t)
+	ctx := context.Background()
+
+	repo.EXPECT().Get(ctx, 100, 0, "").Return([]entity.DeviceGroup{{ID: "site"}, {ID: "lab", ParentID: "site"}}, nil).Times(2)
+
+	require.ErrorAs(t, useCase.Delete(ctx, "site", ""), &dto.NotValidError{})
+
+	repo.EXPECT().Delete(ctx, "lab", "").Return(true, nil)
+	require.NoError(t, useCase.Delete(ctx, "lab", ""))
+}
+
+func TestGetMembers_HonoursDeviceScope(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _ := groupsTest(t)
+	ctx := devicescope.WithScope(context.Background(), &devicescope.Scope{
+		Expressions: []devicescope.Expression{{Tags: []string{"site-a"}}},
+	})
+
+	repo.EXPECT().Get(ctx, 100, 0, "tenant").Return([]entity.DeviceGroup{{ID: "lab"}}, nil).Times(2)
+	repo.EXPECT().GetMembers(ctx, []string{"lab"}, 100, 0, "tenant").Return([]entity.Device{
+		{GUID: "a", Tags: "site-a"},
+		{GUID: "b", Tags: "site-b"},
+		{GUID: "c", Tags: "lab,site-a"},
+	}, nil).Times(2)
+
+	members, err := useCase.GetMembers(ctx, "lab", 10, 1, "tenant")
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	require.Equal(t, "c", members[0].GUID)
+
+	count, err := useCase.GetMemberCount(ctx, "lab", "tenant")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _ := groupsTest(t)
+	ctx := context.Background()
+
+	repo.EXPECT().Get(ctx, 100, 0, "").Return([]entity.DeviceGroup{{ID: "site"}, {ID: "lab", ParentID: "site"}}, nil).Times(2)
+	repo.EXPECT().GetMemberGUIDs(ctx, []string{"site", "lab"}, "").Return([]string{"a", "c"}, nil)
+
+	guids, err := useCase.Resolve(ctx, dto.DeviceSelection{GUIDs: []string{"B", "a"}, Groups: []string{"site"}}, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, guids)
+
+	_, err = useCase.Resolve(ctx, dto.DeviceSelection{Groups: []string{"missing"}}, "")
+	require.ErrorAs(t, err, &repoerrors.NotFoundError{})
+
+	_, err = useCase.Resolve(ctx, dto.DeviceSelection{}, "")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+}
+
+func TestDeviceRepository_MatchesWrittenDevices(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, devices := groupsTest(t)
+	ctx := context.Background()
+	device := &entity.Device{GUID: "a", Hostname: "lab-01", Tags: "lab", TenantID: "tenant"}
+
+	devices.EXPECT().Insert(ctx, device).Return("v1", nil)
+	devices.EXPECT().GetByGUID(ctx, "a").Return(device, nil)
+	repo.EXPECT().Get(ctx, 100, 0, "tenant").Return([]entity.DeviceGroup{
+		{ID: "lab", Rules: []entity.DeviceGroupRule{{Field: "tags", Operator: "any", Value: "lab"}}},
+		{ID: "office", Rules: []entity.DeviceGroupRule{{Field: "tags", Operator: "any", Value: "office"}}},
+		{ID: "static"},
+	}, nil)
+	repo.EXPECT().SetDeviceMatches(ctx, "a", []string{"lab"}, "tenant").Return(nil)
+
+	version, err := devicegroups.NewDeviceRepository(devices, useCase).Insert(ctx, device)
+	require.NoError(t, err)
+	require.Equal(t, "v1", version)
+}
+
+func TestTagRepository_RefreshesTagRuleGroups(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, devices := groupsTest(t)
+	ctx := context.Background()
+	tagRepo := mocks.NewMockTagsRepository(gomock.NewController(t))
+
+	tagRepo.EXPECT().Update(ctx, []string{"a"}, []string{"lab"}, nil, "tenant").Return(1, nil)
+	repo.EXPECT().Get(ctx, 100, 0, "tenant").Return([]entity.DeviceGroup{
+		{ID: "lab", Rules: []entity.DeviceGroupRule{{Field: "tags", Operator: "any", Value: "lab"}}, TenantID: "tenant"},
+		{ID: "office", Rules: []entity.DeviceGroupRule{{Field: "hostname", Operator: "match", Value: "office-*"}}, TenantID: "tenant"},
+	}, nil)
+	devices.EXPECT().Get(ctx, 100, 0, "tenant").Return([]entity.Device{{GUID: "a", Tags: "lab", TenantID: "tenant"}}, nil)
+	repo.EXPECT().SetMatches(ctx, "lab", []string{"a"}, "tenant").Return(nil)
+
+	changed, err := devicegroups.NewTagRepository(tagRepo, useCase).Update(ctx, []string{"a"}, []string{"lab"}, nil, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, 1, changed)
+}
+
+func TestTagReferences(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _ := groupsTest(t)
+	ctx := context.Background()
+
+	repo.EXPECT().Get(ctx, 100, 0, "tenant").Return([]entity.DeviceGroup{
+		{Name: "lab", Rules: []entity.DeviceGroupRule{{Field: "tags", Operator: "any", Value: "lab,bench"}}},
+		{Name: "lab-hosts", Rules: []entity.DeviceGroupRule{{Field: "hostname", Operator: "match", Value: "lab"}}},
+	}, nil)
+
+	references, err := useCase.TagReferences(ctx, []string{"lab"}, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, []string{"group lab"}, references)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman/ips/alarmclock"
 
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/tenancy"
 )
 
 const (
@@ -18,7 +19,7 @@ const (
 )
 
 func (uc *UseCase) GetAlarmOccurrences(c context.Context, guid string) ([]dto.AlarmClockOccurrence, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +54,7 @@ func (uc *UseCase) GetAlarmOccurrences(c context.Context, guid string) ([]dto.Al
 }
 
 func (uc *UseCase) CreateAlarmOccurrences(c context.Context, guid string, alarm dto.AlarmClockOccurrenceInput) (dto.AddAlarmOutput, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.AddAlarmOutput{}, err
 	}
@@ -80,7 +81,7 @@ func (uc *UseCase) CreateAlarmOccurrences(c context.Context, guid string, alarm
 }
 
 func (uc *UseCase) DeleteAlarmOccurrences(c context.Context, guid, instanceID string) error {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return err
 	}
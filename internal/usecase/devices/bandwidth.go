@@ -0,0 +1,81 @@
+package devices
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Bandwidth limit scopes, used as the "limit" label on throttling metrics.
+const (
+	bandwidthScopeSession = "session"
+	bandwidthScopeGlobal  = "global"
+
+	// minBandwidthBurst keeps very low caps from splitting every frame into
+	// tiny waits.
+	minBandwidthBurst = 16 * 1024
+)
+
+// newBandwidthLimiter returns a token bucket for bytesPerSecond allowing up to
+// one second of burst, or nil when the rate is unlimited.
+func newBandwidthLimiter(bytesPerSecond int) *rate.Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), max(bytesPerSecond, minBandwidthBurst))
+}
+
+func (uc *UseCase) globalBandwidthLimiter() *rate.Limiter {
+	uc.policyMutex.RLock()
+	defer uc.policyMutex.RUnlock()
+
+	return uc.bandwidth
+}
+
+// throttle blocks until n more bytes of conn's traffic fit within the
+// session's and the console-wide bandwidth caps.
+func (uc *UseCase) throttle(conn *DeviceConnection, n int) error {
+	if err := waitBandwidth(conn.ctx, conn.bandwidth, n, conn.Mode, bandwidthScopeSession); err != nil {
+		return err
+	}
+
+	return waitBandwidth(conn.ctx, uc.globalBandwidthLimiter(), n, conn.Mode, bandwidthScopeGlobal)
+}
+
+// waitBandwidth reserves n bytes from limiter, sleeping when the bucket is
+// empty. Bytes that had to wait are counted as throttled.
+func waitBandwidth(ctx context.Context, limiter *rate.Limiter, n int, mode, scope string) error {
+	if limiter == nil {
+		return nil
+	}
+
+	for n > 0 {
+		chunk := min(n, limiter.Burst())
+		n -= chunk
+
+		reservation := limiter.ReserveN(time.Now(), chunk)
+
+		delay := reservation.Delay()
+		if delay == 0 {
+			continue
+		}
+
+		kvmThrottledBytes.WithLabelValues(mode, scope).Add(float64(chunk))
+		kvmThrottleWaitSeconds.WithLabelValues(mode, scope).Observe(delay.Seconds())
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			reservation.Cancel()
+
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
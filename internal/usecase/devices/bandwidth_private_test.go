@@ -0,0 +1,78 @@
+package devices
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBandwidthLimiter(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newBandwidthLimiter(0))
+	require.Nil(t, newBandwidthLimiter(-1))
+
+	limiter := newBandwidthLimiter(1024)
+	require.NotNil(t, limiter)
+	require.Equal(t, minBandwidthBurst, limiter.Burst())
+	require.Equal(t, 1<<20, newBandwidthLimiter(1<<20).Burst())
+}
+
+func TestWaitBandwidth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unlimited", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, waitBandwidth(context.Background(), nil, 1<<30, "kvm", bandwidthScopeSession))
+	})
+
+	t.Run("delays once the burst is spent", func(t *testing.T) {
+		t.Parallel()
+
+		// 16 KiB burst refilled at 160 KiB/s: the next 16 KiB waits ~100ms.
+		limiter := newBandwidthLimiter(10 * minBandwidthBurst)
+		limiter.SetBurst(minBandwidthBurst)
+
+		require.NoError(t, waitBandwidth(context.Background(), limiter, minBandwidthBurst, "kvm", bandwidthScopeSession))
+
+		start := time.Now()
+		require.NoError(t, waitBandwidth(context.Background(), limiter, minBandwidthBurst, "kvm", bandwidthScopeSession))
+		require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := newBandwidthLimiter(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := waitBandwidth(ctx, limiter, 2*minBandwidthBurst, "sol", bandwidthScopeGlobal)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestSetSessionPoliciesBandwidth(t *testing.T) {
+	t.Parallel()
+
+	uc := newPolicyTestUseCase(SessionPolicy{BandwidthLimit: 2048})
+	require.Nil(t, uc.globalBandwidthLimiter())
+
+	uc.SetSessionPolicies(SessionPolicies{
+		Modes:          map[string]SessionPolicy{"kvm": {BandwidthLimit: 2048}},
+		BandwidthLimit: 4096,
+	})
+	require.NotNil(t, uc.globalBandwidthLimiter())
+
+	conn := newPolicyTestConnection(t)
+	uc.registerSession(conn.ctx, conn, func(string) {})
+	require.NotNil(t, conn.bandwidth)
+	require.Equal(t, 2048, int(conn.bandwidth.Limit()))
+
+	uc.SetSessionPolicies(SessionPolicies{})
+	require.Nil(t, uc.globalBandwidthLimiter())
+}
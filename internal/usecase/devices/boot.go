@@ -5,12 +5,13 @@ import (
 	"errors"
 
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/tenancy"
 	deviceManagement "github.com/device-management-toolkit/console/internal/usecase/devices/wsman"
 	"github.com/device-management-toolkit/console/pkg/consoleerrors"
 )
 
 func (uc *UseCase) GetRemoteEraseCapabilities(c context.Context, guid string) (dto.BootCapabilities, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.BootCapabilities{}, err
 	}
@@ -40,7 +41,7 @@ func (uc *UseCase) GetRemoteEraseCapabilities(c context.Context, guid string) (d
 }
 
 func (uc *UseCase) SetRemoteEraseOptions(c context.Context, guid string, req dto.RemoteEraseRequest) error {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return err
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman/cim/credential"
 
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/tenancy"
 	"github.com/device-management-toolkit/console/internal/usecase/devices/wsman"
 	"github.com/device-management-toolkit/console/pkg/consoleerrors"
 )
@@ -160,7 +161,7 @@ func processCertificates(contextItems []credential.CredentialContext, response w
 }
 
 func (uc *UseCase) GetCertificates(c context.Context, guid string) (dto.SecuritySettings, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.SecuritySettings{}, err
 	}
@@ -257,7 +258,7 @@ func KeysToDTO(r *publicprivate.RefinedPullResponse) dto.KeyPullResponse {
 }
 
 func (uc *UseCase) GetDeviceCertificate(c context.Context, guid string) (dto.Certificate, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.Certificate{}, err
 	}
@@ -330,7 +331,7 @@ func populateCertificateDTO(cert *x509.Certificate) dto.Certificate {
 func (uc *UseCase) AddCertificate(c context.Context, guid string, certInfo dto.CertInfo) (handle string, err error) {
 	var certData []byte
 
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return "", err
 	}
@@ -397,7 +398,7 @@ func (uc *UseCase) AddCertificate(c context.Context, guid string, certInfo dto.C
 }
 
 func (uc *UseCase) DeleteCertificate(c context.Context, guid, instanceID string) error {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return err
 	}
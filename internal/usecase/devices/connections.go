@@ -6,10 +6,11 @@ import (
 	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman/amt/tls"
 
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/tenancy"
 )
 
 func (uc *UseCase) GetTLSSettingData(c context.Context, guid string) ([]dto.SettingDataResponse, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return nil, err
 	}
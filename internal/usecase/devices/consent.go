@@ -5,10 +5,11 @@ import (
 	"strconv"
 
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/tenancy"
 )
 
 func (uc *UseCase) CancelUserConsent(c context.Context, guid string) (dto.UserConsentMessage, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.UserConsentMessage{}, err
 	}
@@ -48,7 +49,7 @@ func (uc *UseCase) CancelUserConsent(c context.Context, guid string) (dto.UserCo
 }
 
 func (uc *UseCase) GetUserConsentCode(c context.Context, guid string) (dto.UserConsentMessage, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.UserConsentMessage{}, err
 	}
@@ -88,7 +89,7 @@ func (uc *UseCase) GetUserConsentCode(c context.Context, guid string) (dto.UserC
 }
 
 func (uc *UseCase) SendConsentCode(c context.Context, userConsent dto.UserConsentCode, guid string) (dto.UserConsentMessage, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.UserConsentMessage{}, err
 	}
@@ -0,0 +1,323 @@
+package devices
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman/client"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/tenancy"
+)
+
+const (
+	// AuthenticateSessionReplyHeaderSize is the fixed part of an
+	// AuthenticateSessionReply before its variable-length auth data.
+	AuthenticateSessionReplyHeaderSize = 9
+	// maxAuthRounds bounds the query -> digest -> response exchange.
+	maxAuthRounds = 4
+)
+
+var (
+	ErrRedirectionBusy        = errors.New("AMT redirection session is busy (another session is already active)")
+	ErrRedirectionRejected    = errors.New("AMT rejected the redirection session")
+	ErrRedirectionAuthFailed  = errors.New("AMT redirection authentication failed")
+	ErrRedirectionUnsupported = errors.New("AMT redirection does not offer digest authentication")
+	ErrRedirectionProtocol    = errors.New("unexpected AMT redirection message")
+)
+
+// Start messages for each redirection mode, as sent by the browser client.
+var (
+	startRedirectionSOL = []byte{RedirectionCommandsStartRedirectionSession, 0x00, 0x00, 0x00, 'S', 'O', 'L', ' '}
+	startRedirectionKVM = []byte{RedirectionCommandsStartRedirectionSession, 0x01, 0x00, 0x00, 'K', 'V', 'M', 'R'}
+	endRedirection      = []byte{RedirectionCommandsEndRedirectionSession, 0x00, 0x00, 0x00}
+	authQuery           = []byte{RedirectionCommandsAuthenticateSession, 0x00, 0x00, 0x00, AuthenticationTypeQuery, 0x00, 0x00, 0x00, 0x00}
+)
+
+// headlessRedirection runs the AMT redirection handshake on the console side
+// for sessions with no browser attached (SSH gateway, capture, screenshots).
+// The browser path only rewrites authentication; here the console is the
+// redirection client end to end.
+type headlessRedirection struct {
+	uc      *UseCase
+	conn    *DeviceConnection
+	pending []byte
+
+	sendMu    sync.Mutex
+	sequence  uint32
+	closeOnce sync.Once
+}
+
+// openHeadlessRedirection connects to the device's redirection port and
+// completes StartRedirectionSession plus digest authentication for mode.
+func (uc *UseCase) openHeadlessRedirection(c context.Context, guid, mode string, start []byte) (*headlessRedirection, error) {
+	device, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
+	if err != nil {
+		return nil, err
+	}
+
+	if device == nil || device.GUID == "" {
+		return nil, ErrNotFound
+	}
+
+	return uc.connectHeadlessRedirection(c, *device, mode, start)
+}
+
+// connectHeadlessRedirection is openHeadlessRedirection for a device the
+// caller has already looked up.
+func (uc *UseCase) connectHeadlessRedirection(c context.Context, device entity.Device, mode string, start []byte) (*headlessRedirection, error) {
+	wsmanConnection, err := uc.redirection.SetupWsmanClient(c, device, true, true)
+	if err != nil {
+		return nil, err
+	}
+
+	decryptedPassword, err := uc.safeRequirements.Decrypt(device.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	device.Password = decryptedPassword
+
+	ctx, cancel := context.WithCancel(c)
+	now := time.Now()
+	deviceConnection := &DeviceConnection{
+		wsmanMessages: wsmanConnection,
+		Device:        device,
+		Mode:          mode,
+		Headless:      true,
+		Challenge: client.AuthChallenge{
+			Username: device.Username,
+			Password: device.Password,
+		},
+		ctx:          ctx,
+		cancel:       cancel,
+		lastActivity: now,
+		lastDataRecv: now,
+	}
+
+	connectStart := time.Now()
+	err = uc.redirection.RedirectConnect(ctx, deviceConnection)
+
+	RecordConnectionSetup(time.Since(connectStart), mode)
+
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	h := &headlessRedirection{uc: uc, conn: deviceConnection}
+
+	if err := h.handshake(start); err != nil {
+		h.close()
+
+		return nil, err
+	}
+
+	uc.registerSession(c, deviceConnection, func(string) { h.close() })
+
+	return h, nil
+}
+
+func (h *headlessRedirection) handshake(start []byte) error {
+	if err := h.send(start); err != nil {
+		return err
+	}
+
+	header, err := h.peek(RedirectionSessionReply)
+	if err != nil {
+		return err
+	}
+
+	if header[0] != RedirectionCommandsStartRedirectionSessionReply {
+		return fmt.Errorf("%w: 0x%02x waiting for StartRedirectionSessionReply", ErrRedirectionProtocol, header[0])
+	}
+
+	switch header[1] {
+	case StartRedirectionSessionReplyStatusSuccess:
+	case StartRedirectionSessionReplyStatusBusy:
+		return ErrRedirectionBusy
+	default:
+		return fmt.Errorf("%w: status %d", ErrRedirectionRejected, header[1])
+	}
+
+	fixed, err := h.peek(RedirectSessionLengthBytes)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.take(RedirectSessionLengthBytes + int(fixed[RedirectSessionLengthBytes-1])); err != nil {
+		return err
+	}
+
+	return h.authenticate()
+}
+
+// authenticate walks the same query -> digest challenge -> digest response
+// exchange the browser performs, reusing the interceptor's digest helpers.
+func (h *headlessRedirection) authenticate() error {
+	challenge := &h.conn.Challenge
+
+	if err := h.send(authQuery); err != nil {
+		return err
+	}
+
+	for range maxAuthRounds {
+		reply, err := h.readAuthenticateReply()
+		if err != nil {
+			return err
+		}
+
+		status, authType := reply[1], reply[4]
+
+		switch {
+		case authType == AuthenticationTypeQuery:
+			if !bytes.Contains(reply[AuthenticateSessionReplyHeaderSize:], []byte{AuthenticationTypeDigest}) {
+				return ErrRedirectionUnsupported
+			}
+
+			err = h.send(handleDigestAuthentication(challenge))
+		case status == AuthenticationStatusSuccess:
+			return nil
+		case authType == AuthenticationTypeDigest && status == AuthenticationStatusFail && challenge.Realm == "":
+			handleAuthenticateSessionReply(reply, challenge)
+
+			err = h.send(handleDigestAuthentication(challenge))
+		default:
+			return ErrRedirectionAuthFailed
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return ErrRedirectionAuthFailed
+}
+
+func (h *headlessRedirection) readAuthenticateReply() ([]byte, error) {
+	header, err := h.peek(AuthenticateSessionReplyHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if header[0] != RedirectionCommandsAuthenticateSessionReply {
+		return nil, fmt.Errorf("%w: 0x%02x waiting for AuthenticateSessionReply", ErrRedirectionProtocol, header[0])
+	}
+
+	length := binary.LittleEndian.Uint32(header[5:AuthenticateSessionReplyHeaderSize])
+
+	return h.take(AuthenticateSessionReplyHeaderSize + int(length))
+}
+
+// fill appends the next chunk received from the device to the pending buffer.
+func (h *headlessRedirection) fill() error {
+	select {
+	case <-h.conn.ctx.Done():
+		return io.EOF
+	default:
+	}
+
+	recvStart := time.Now()
+	data, err := h.uc.redirection.RedirectListen(h.conn.ctx, h.conn)
+	h.uc.observeDeviceReceive(h.conn, time.Since(recvStart))
+
+	if err != nil {
+		return err
+	}
+
+	if err := h.uc.throttle(h.conn, len(data)); err != nil {
+		return err
+	}
+
+	h.conn.mu.Lock()
+	h.conn.lastDataRecv = time.Now()
+	h.conn.mu.Unlock()
+
+	kvmDeviceToBrowserBytes.WithLabelValues(h.conn.Mode).Add(float64(len(data)))
+
+	h.pending = append(h.pending, data...)
+
+	return nil
+}
+
+// peek returns the first n pending bytes without consuming them.
+func (h *headlessRedirection) peek(n int) ([]byte, error) {
+	for len(h.pending) < n {
+		if err := h.fill(); err != nil {
+			return nil, err
+		}
+	}
+
+	return h.pending[:n], nil
+}
+
+// take consumes and returns the first n pending bytes.
+func (h *headlessRedirection) take(n int) ([]byte, error) {
+	if _, err := h.peek(n); err != nil {
+		return nil, err
+	}
+
+	msg := make([]byte, n)
+	copy(msg, h.pending[:n])
+	h.pending = h.pending[n:]
+
+	return msg, nil
+}
+
+// send writes a raw message to the device. Safe for concurrent use.
+func (h *headlessRedirection) send(data []byte) error {
+	h.sendMu.Lock()
+	defer h.sendMu.Unlock()
+
+	return h.sendLocked(data)
+}
+
+func (h *headlessRedirection) sendLocked(data []byte) error {
+	if len(data) == 0 {
+		return ErrRedirectionProtocol
+	}
+
+	if err := h.uc.throttle(h.conn, len(data)); err != nil {
+		return err
+	}
+
+	kvmBrowserToDeviceBytes.WithLabelValues(h.conn.Mode).Add(float64(len(data)))
+
+	return h.uc.redirection.RedirectSend(h.conn.ctx, h.conn, data)
+}
+
+// sendSequenced prefixes payload with command, padding and the next sequence
+// number, the framing shared by every post-authentication SOL message.
+func (h *headlessRedirection) sendSequenced(command byte, payload []byte) error {
+	h.sendMu.Lock()
+	defer h.sendMu.Unlock()
+
+	msg := make([]byte, 0, solMessageHeaderSize+len(payload))
+	msg = append(msg, command, 0x00, 0x00, 0x00)
+	msg = binary.LittleEndian.AppendUint32(msg, h.sequence)
+	msg = append(msg, payload...)
+
+	h.sequence++
+
+	return h.sendLocked(msg)
+}
+
+func (h *headlessRedirection) close() {
+	h.closeOnce.Do(func() {
+		_ = h.send(endRedirection)
+
+		h.conn.cancel()
+		h.uc.unregisterSession(h.conn)
+
+		if err := h.uc.redirection.RedirectClose(context.Background(), h.conn); err != nil {
+			h.uc.log.Debug("headless redirection close", "guid", h.conn.Device.GUID, "error", err)
+		}
+	})
+}
@@ -15,11 +15,13 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"golang.org/x/time/rate"
 
 	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman"
 	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman/client"
 
 	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/tenancy"
 )
 
 const (
@@ -47,12 +49,25 @@ type DeviceConnection struct {
 	lastDataRecv  time.Time // Track last data received from device
 	mu            sync.RWMutex
 	healthTicker  *time.Ticker
+
+	// Session bookkeeping for policies and the admin session API.
+	ID           string
+	StartedAt    time.Time
+	Origin       SessionOrigin
+	Headless     bool
+	lastInput    time.Time // Last operator input, drives the idle timeout
+	idleWarned   bool
+	expiryWarned bool
+	stopReason   string
+	writeMu      sync.Mutex // gorilla/websocket allows one concurrent writer
+	terminate    func(reason string)
+	bandwidth    *rate.Limiter // Per-session cap, nil when unlimited
 }
 
 func (uc *UseCase) Redirect(c context.Context, conn *websocket.Conn, guid, mode string) error {
 	// KVM_TIMING: Measure device lookup latency
 	lookupStart := time.Now()
-	device, err := uc.repo.GetByID(c, guid, "")
+	device, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 
 	RecordDeviceLookup(time.Since(lookupStart))
 	uc.log.Debug("KVM_TIMING: Device lookup", "duration_ms", time.Since(lookupStart).Milliseconds(), "guid", guid)
@@ -90,6 +105,7 @@ func (uc *UseCase) Redirect(c context.Context, conn *websocket.Conn, guid, mode
 	}
 
 	uc.updateConnectionActivity(deviceConnection)
+	uc.registerSession(c, deviceConnection, uc.terminateBrowserSession(deviceConnection))
 	uc.startConnectionGoroutines(c, deviceConnection, key)
 
 	return nil
@@ -208,6 +224,8 @@ func (uc *UseCase) startConnectionGoroutines(c context.Context, deviceConnection
 		uc.redirMutex.Lock()
 		delete(uc.redirConnections, key)
 		uc.redirMutex.Unlock()
+
+		uc.unregisterSession(deviceConnection)
 	}()
 }
 
@@ -215,10 +233,12 @@ func (uc *UseCase) closeDeviceWebSocket(conn WebSocketConn, deviceConnection *De
 	uc.log.Debug("KVM session closed by AMT", "guid", deviceConnection.Device.GUID)
 
 	if conn != nil {
+		deviceConnection.writeMu.Lock()
 		_ = conn.WriteMessage(
 			websocket.CloseMessage,
 			websocket.FormatCloseMessage(websocket.CloseNormalClosure, "AMT session ended"),
 		)
+		deviceConnection.writeMu.Unlock()
 		_ = conn.Close()
 	}
 
@@ -281,6 +301,10 @@ func (uc *UseCase) ListenToDevice(deviceConnection *DeviceConnection) {
 			toSend, deviceConnection.Direct = processDeviceData(toSend, &deviceConnection.Challenge)
 		}
 
+		if err := uc.throttle(deviceConnection, len(toSend)); err != nil {
+			return
+		}
+
 		// metrics: device -> browser
 		start := time.Now()
 
@@ -288,7 +312,9 @@ func (uc *UseCase) ListenToDevice(deviceConnection *DeviceConnection) {
 		kvmDeviceToBrowserBytes.WithLabelValues(deviceConnection.Mode).Add(float64(len(toSend)))
 		kvmDeviceToBrowserMessages.WithLabelValues(deviceConnection.Mode).Inc()
 
+		deviceConnection.writeMu.Lock()
 		err = conn.WriteMessage(websocket.BinaryMessage, toSend)
+		deviceConnection.writeMu.Unlock()
 		uc.observeDeviceToBrowserWrite(deviceConnection, time.Since(start), len(toSend))
 
 		if err != nil {
@@ -353,6 +379,14 @@ func (uc *UseCase) ListenToBrowser(deviceConnection *DeviceConnection) {
 			continue
 		}
 
+		if isOperatorInput(deviceConnection.Mode, deviceConnection.Direct, toSend) {
+			uc.markInput(deviceConnection)
+		}
+
+		if err := uc.throttle(deviceConnection, len(toSend)); err != nil {
+			return
+		}
+
 		// metrics: browser -> device
 		start := time.Now()
 
@@ -2,6 +2,7 @@ package devices
 
 import (
 	"context"
+	"io"
 
 	"github.com/gorilla/websocket"
 
@@ -40,6 +41,7 @@ type (
 		GetCount(context.Context, string) (int, error)
 		Get(ctx context.Context, top, skip int, tenantID string) ([]entity.Device, error)
 		GetByID(ctx context.Context, guid, tenantID string) (*entity.Device, error)
+		GetByGUID(ctx context.Context, guid string) (*entity.Device, error)
 		GetDistinctTags(ctx context.Context, tenantID string) ([]string, error)
 		GetByTags(ctx context.Context, tags []string, method string, limit, offset int, tenantID string) ([]entity.Device, error)
 		Delete(ctx context.Context, guid, tenantID string) (bool, error)
@@ -54,6 +56,7 @@ type (
 		GetCount(context.Context, string) (int, error)
 		Get(ctx context.Context, top, skip int, tenantID string) ([]dto.Device, error)
 		GetByID(ctx context.Context, guid, tenantID string, includeSecrets bool) (*dto.Device, error)
+		GetByGUID(ctx context.Context, guid string, includeSecrets bool) (*dto.Device, error)
 		UpdateConnectionStatus(ctx context.Context, guid string, status bool) error
 		UpdateLastSeen(ctx context.Context, guid string) error
 		GetDistinctTags(ctx context.Context, tenantID string) ([]string, error)
@@ -83,6 +86,12 @@ type (
 		GetAuditLog(ctx context.Context, startIndex int, guid string) (dto.AuditLog, error)
 		GetEventLog(ctx context.Context, startIndex, maxReadRecords int, guid string) (dto.EventLogs, error)
 		Redirect(ctx context.Context, conn *websocket.Conn, guid, mode string) error
+		OpenSOL(ctx context.Context, guid string) (io.ReadWriteCloser, error)
+		GetRedirectionSessions(ctx context.Context) ([]dto.RedirectionSession, error)
+		TerminateRedirectionSession(ctx context.Context, id string) error
+		StartSOLCapture(ctx context.Context, guid string, req dto.SOLCaptureRequest) (dto.SOLCapture, error)
+		GetSOLCaptures(ctx context.Context, guid string) ([]dto.SOLCapture, error)
+		GetSOLCapture(ctx context.Context, guid, id string) (dto.SOLCapture, error)
 		GetNetworkSettings(c context.Context, guid string) (dto.NetworkSettings, error)
 		GetWiredNetworkSettings(c context.Context, guid string) (dto.WiredNetworkInfo, error)
 		PatchWiredNetworkSettings(c context.Context, guid string, req dto.WiredNetworkConfigRequest) error
@@ -104,6 +113,7 @@ type (
 		// KVM Screen Settings (IPS_ScreenSettingData)
 		GetKVMScreenSettings(c context.Context, guid string) (dto.KVMScreenSettings, error)
 		SetKVMScreenSettings(c context.Context, guid string, req dto.KVMScreenSettingsRequest) (dto.KVMScreenSettings, error)
+		GetKVMScreenshot(c context.Context, guid string) ([]byte, error)
 		// Link Preference (AMT_EthernetPortSettings)
 		SetLinkPreference(c context.Context, guid string, req dto.LinkPreferenceRequest) (dto.LinkPreferenceResponse, error)
 	}
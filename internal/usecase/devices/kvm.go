@@ -6,6 +6,7 @@ import (
 	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman/ips/kvmredirection"
 
 	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/tenancy"
 	"github.com/device-management-toolkit/console/pkg/consoleerrors"
 )
 
@@ -13,7 +14,7 @@ var ErrNotSupportedUseCase = NotSupportedError{Console: consoleerrors.CreateCons
 
 // GetKVMScreenSettings returns IPS_ScreenSettingData for the device.
 func (uc *UseCase) GetKVMScreenSettings(c context.Context, guid string) (dto.KVMScreenSettings, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.KVMScreenSettings{}, err
 	}
@@ -74,7 +75,7 @@ func (uc *UseCase) GetKVMScreenSettings(c context.Context, guid string) (dto.KVM
 // SetKVMScreenSettings updates IPS_ScreenSettingData; currently not supported via wsman lib
 // We accept payload but return NotSupported to preserve API contract for future.
 func (uc *UseCase) SetKVMScreenSettings(c context.Context, guid string, reqData dto.KVMScreenSettingsRequest) (dto.KVMScreenSettings, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.KVMScreenSettings{}, err
 	}
@@ -4,11 +4,12 @@ import (
 	"context"
 
 	dto "github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/tenancy"
 )
 
 // SetLinkPreference sets the link preference (ME or Host) on a device's WiFi interface.
 func (uc *UseCase) SetLinkPreference(c context.Context, guid string, req dto.LinkPreferenceRequest) (dto.LinkPreferenceResponse, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.LinkPreferenceResponse{}, err
 	}
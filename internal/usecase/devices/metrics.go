@@ -8,7 +8,11 @@ import (
 )
 
 // metricLabelMode is the Prometheus label name shared by KVM metric vectors.
-const metricLabelMode = "mode"
+// metricLabelLimit tells session caps apart from the console-wide cap.
+const (
+	metricLabelMode  = "mode"
+	metricLabelLimit = "limit"
+)
 
 var (
 	kvmDeviceToBrowserBytes = promauto.NewCounterVec(
@@ -98,6 +102,24 @@ var (
 		[]string{metricLabelMode},
 	)
 
+	// Bandwidth shaping.
+	kvmThrottledBytes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kvm_throttled_bytes_total",
+			Help: "Bytes delayed by a redirection bandwidth cap (per mode and limit: session or global)",
+		},
+		[]string{metricLabelMode, metricLabelLimit},
+	)
+
+	kvmThrottleWaitSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kvm_throttle_wait_seconds",
+			Help:    "Time a redirection frame waited for a bandwidth cap (per mode and limit)",
+			Buckets: []float64{0.001, 0.005, 0.01, 0.02, 0.05, 0.1, 0.2, 0.5, 1, 2, 5},
+		},
+		[]string{metricLabelMode, metricLabelLimit},
+	)
+
 	// KVM Connection Performance Metrics.
 	kvmDeviceLookupSeconds = promauto.NewHistogram(
 		prometheus.HistogramOpts{
@@ -7,6 +7,7 @@ import (
 	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman/amt/ethernetport"
 
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/tenancy"
 	"github.com/device-management-toolkit/console/internal/usecase/devices/wsman"
 )
 
@@ -17,7 +18,7 @@ const (
 )
 
 func (uc *UseCase) GetNetworkSettings(c context.Context, guid string) (dto.NetworkSettings, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.NetworkSettings{}, err
 	}
@@ -188,7 +189,7 @@ func (uc *UseCase) PatchWiredNetworkSettings(c context.Context, guid string, req
 		return err
 	}
 
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return err
 	}
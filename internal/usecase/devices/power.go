@@ -15,6 +15,7 @@ import (
 	ipsPower "github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman/ips/power"
 
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/tenancy"
 	"github.com/device-management-toolkit/console/internal/usecase/devices/wsman"
 	"github.com/device-management-toolkit/console/pkg/consoleerrors"
 )
@@ -44,7 +45,7 @@ var (
 )
 
 func (uc *UseCase) SendPowerAction(c context.Context, guid string, action int) (power.PowerActionResponse, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return power.PowerActionResponse{}, err
 	}
@@ -122,7 +123,7 @@ func ensureFullPowerBeforeReset(device wsman.Management) (power.PowerActionRespo
 }
 
 func (uc *UseCase) GetPowerState(c context.Context, guid string) (dto.PowerState, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.PowerState{}, err
 	}
@@ -160,7 +161,7 @@ func (uc *UseCase) GetPowerState(c context.Context, guid string) (dto.PowerState
 }
 
 func (uc *UseCase) GetPowerCapabilities(c context.Context, guid string) (dto.PowerCapabilities, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return dto.PowerCapabilities{}, err
 	}
@@ -280,7 +281,7 @@ func buildBootSettingData(bootData boot.BootSettingDataResponse, bootSetting dto
 }
 
 func (uc *UseCase) SetBootOptions(c context.Context, guid string, bootSetting dto.BootSetting) (power.PowerActionResponse, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return power.PowerActionResponse{}, err
 	}
@@ -304,7 +305,7 @@ func (uc *UseCase) SetBootOptions(c context.Context, guid string, bootSetting dt
 	}
 
 	newData := buildBootSettingData(bootData, bootSetting)
-	bootSource := uc.getBootSource(guid, &bootSetting)
+	bootSource := uc.getBootSource(c, guid, &bootSetting)
 
 	err = determineBootDevice(bootSetting, &newData)
 	if err != nil {
@@ -504,7 +505,7 @@ func ValidatePBAWinReBootParams(file string) (buffer []byte, paramCount int, err
 
 // "Intel(r) AMT: Force PXE Boot".
 // "Intel(r) AMT: Force CD/DVD Boot".
-func (uc *UseCase) getBootSource(guid string, bootSetting *dto.BootSetting) string {
+func (uc *UseCase) getBootSource(ctx context.Context, guid string, bootSetting *dto.BootSetting) string {
 	switch bootSetting.Action {
 	case BootActionResetToPXE, BootActionPowerOnToPXE:
 		return string(cimBoot.PXE)
@@ -513,16 +514,16 @@ func (uc *UseCase) getBootSource(guid string, bootSetting *dto.BootSetting) stri
 	case BootActionHTTPSBoot, BootActionPowerOnHTTPSBoot:
 		return string(cimBoot.OCRUEFIHTTPS)
 	case BootActionPBA, BootActionPowerOnPBA:
-		return uc.getPbaBootSource(guid, bootSetting)
+		return uc.getPbaBootSource(ctx, guid, bootSetting)
 	case BootActionWinREBoot, BootActionPowerOnWinREBoot:
-		return uc.getWinReBootSource(guid, bootSetting)
+		return uc.getWinReBootSource(ctx, guid, bootSetting)
 	default:
 		return ""
 	}
 }
 
-func (uc *UseCase) getPbaBootSource(guid string, bootSetting *dto.BootSetting) string {
-	sources, err := uc.GetBootSourceSetting(context.Background(), guid)
+func (uc *UseCase) getPbaBootSource(ctx context.Context, guid string, bootSetting *dto.BootSetting) string {
+	sources, err := uc.GetBootSourceSetting(ctx, guid)
 	if err != nil {
 		return ""
 	}
@@ -536,8 +537,8 @@ func (uc *UseCase) getPbaBootSource(guid string, bootSetting *dto.BootSetting) s
 	return ""
 }
 
-func (uc *UseCase) getWinReBootSource(guid string, bootSetting *dto.BootSetting) string {
-	sources, err := uc.GetBootSourceSetting(context.Background(), guid)
+func (uc *UseCase) getWinReBootSource(ctx context.Context, guid string, bootSetting *dto.BootSetting) string {
+	sources, err := uc.GetBootSourceSetting(ctx, guid)
 	if err != nil {
 		return ""
 	}
@@ -593,7 +594,7 @@ func parseVersion(version []software.SoftwareIdentity) (int, error) {
 }
 
 func (uc *UseCase) GetBootSourceSetting(c context.Context, guid string) ([]dto.BootSources, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return nil, err
 	}
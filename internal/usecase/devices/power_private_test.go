@@ -1,6 +1,7 @@
 package devices
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -127,7 +128,7 @@ func TestGetBootSource(t *testing.T) {
 			t.Parallel()
 
 			uc := &UseCase{} // create a dummy UseCase
-			res := uc.getBootSource("test-guid", &tc.bootSettings)
+			res := uc.getBootSource(context.Background(), "test-guid", &tc.bootSettings)
 
 			require.Equal(t, tc.res, res)
 		})
@@ -82,6 +82,22 @@ func (uc *UseCase) GetByID(ctx context.Context, guid, tenantID string, includeSe
 		return nil, ErrDatabase.Wrap("GetByID", "uc.repo.GetByID", err)
 	}
 
+	return uc.found(data, includeSecrets)
+}
+
+// GetByGUID looks a device up in whichever tenant it belongs to. It serves
+// connections the device opens itself; requests made for a user must use
+// GetByID.
+func (uc *UseCase) GetByGUID(ctx context.Context, guid string, includeSecrets bool) (*dto.Device, error) {
+	data, err := uc.repo.GetByGUID(ctx, strings.ToLower(guid))
+	if err != nil {
+		return nil, ErrDatabase.Wrap("GetByGUID", "uc.repo.GetByGUID", err)
+	}
+
+	return uc.found(data, includeSecrets)
+}
+
+func (uc *UseCase) found(data *entity.Device, includeSecrets bool) (*dto.Device, error) {
 	if data == nil || data.GUID == "" {
 		return nil, ErrNotFound
 	}
@@ -216,6 +232,10 @@ func (uc *UseCase) Update(ctx context.Context, d *dto.Device, fields map[string]
 		return nil, err
 	}
 
+	if err := checkScope(ctx, "Update", d1.Tags); err != nil {
+		return nil, err
+	}
+
 	updated, err := uc.repo.Update(ctx, d1)
 	if err != nil {
 		return nil, ErrDatabase.Wrap("Update", "uc.repo.Update", err)
@@ -247,6 +267,10 @@ func (uc *UseCase) Insert(ctx context.Context, d *dto.Device) (*dto.Device, erro
 		return nil, err
 	}
 
+	if err := checkScope(ctx, "Insert", d1.Tags); err != nil {
+		return nil, err
+	}
+
 	if d1.GUID == "" {
 		d1.GUID = uuid.New().String()
 	}
@@ -0,0 +1,290 @@
+package devices
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/odata"
+)
+
+var (
+	ErrNotValid = dto.NotValidError{Console: ErrDeviceUseCase}
+
+	errTagsOutOfScope = errors.New("the device's tags must keep it within your device scope")
+)
+
+// scopedRepository hides devices outside the scope of the request's context
+// (see devicescope), as if they did not exist. Contexts without a scope,
+// such as those of the console's own background work, see every device.
+//
+// Scoped lists are built from one unpaged tag query per scope expression and
+// filtered, ordered and paged in memory. Scopes are meant to cover a site's
+// devices, not the fleet.
+type scopedRepository struct {
+	Repository
+}
+
+// NewScopedRepository wraps r so that every lookup honours the device scope
+// of its context. The devices use case wraps its repository itself.
+func NewScopedRepository(r Repository) Repository {
+	if _, ok := r.(scopedRepository); ok || r == nil {
+		return r
+	}
+
+	return scopedRepository{r}
+}
+
+// visible returns the devices of tenantID within scope, ordered by GUID like
+// the unscoped queries.
+func (r scopedRepository) visible(ctx context.Context, scope *devicescope.Scope, tenantID string) ([]entity.Device, error) {
+	seen := map[string]bool{}
+	devices := make([]entity.Device, 0)
+
+	for _, e := range scope.Expressions {
+		method := "OR"
+		if e.All {
+			method = "AND"
+		}
+
+		matched, err := r.Repository.GetByTags(ctx, e.Tags, method, 0, 0, tenantID)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range matched {
+			if !seen[matched[i].GUID] {
+				seen[matched[i].GUID] = true
+
+				devices = append(devices, matched[i])
+			}
+		}
+	}
+
+	sort.Slice(devices, func(i, j int) bool { return devices[i].GUID < devices[j].GUID })
+
+	return devices, nil
+}
+
+// query applies the $filter and $orderby of ctx's OData query, if any, to
+// devices, which stay in GUID order otherwise.
+func query(ctx context.Context, devices []entity.Device) []entity.Device {
+	q := odata.FromContext(ctx)
+	if q == nil {
+		return devices
+	}
+
+	matched := make([]entity.Device, 0, len(devices))
+
+	for i := range devices {
+		if q.Filter.Match(deviceRecord(&devices[i])) {
+			matched = append(matched, devices[i])
+		}
+	}
+
+	if len(q.OrderBy) > 0 {
+		sort.SliceStable(matched, func(i, j int) bool {
+			return q.Compare(deviceRecord(&matched[i]), deviceRecord(&matched[j])) < 0
+		})
+	}
+
+	return matched
+}
+
+// deviceRecord exposes d's filterable fields by their OData names.
+func deviceRecord(d *entity.Device) odata.Record {
+	return func(field string) any {
+		switch field {
+		case "guid":
+			return d.GUID
+		case "hostname":
+			return d.Hostname
+		case "friendlyName":
+			return d.FriendlyName
+		case "dnsSuffix":
+			return d.DNSSuffix
+		case "mpsInstance":
+			return d.MPSInstance
+		case "mpsusername":
+			return d.MPSUsername
+		case "connectionStatus":
+			return d.ConnectionStatus
+		case "useTLS":
+			return d.UseTLS
+		case "allowSelfSigned":
+			return d.AllowSelfSigned
+		}
+
+		return nil
+	}
+}
+
+func page(devices []entity.Device, limit, offset int) []entity.Device {
+	offset = min(max(offset, 0), len(devices))
+	devices = devices[offset:]
+
+	if limit > 0 && limit < len(devices) {
+		devices = devices[:limit]
+	}
+
+	return devices
+}
+
+func (r scopedRepository) filter(ctx context.Context, devices []entity.Device) []entity.Device {
+	scope := devicescope.FromContext(ctx)
+	if scope == nil {
+		return devices
+	}
+
+	allowed := make([]entity.Device, 0, len(devices))
+
+	for i := range devices {
+		if scope.Allows(splitTags(devices[i].Tags)) {
+			allowed = append(allowed, devices[i])
+		}
+	}
+
+	return allowed
+}
+
+func (r scopedRepository) GetCount(ctx context.Context, tenantID string) (int, error) {
+	scope := devicescope.FromContext(ctx)
+	if scope == nil {
+		return r.Repository.GetCount(ctx, tenantID)
+	}
+
+	devices, err := r.visible(ctx, scope, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(query(ctx, devices)), nil
+}
+
+func (r scopedRepository) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.Device, error) {
+	scope := devicescope.FromContext(ctx)
+	if scope == nil {
+		return r.Repository.Get(ctx, top, skip, tenantID)
+	}
+
+	devices, err := r.visible(ctx, scope, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return page(query(ctx, devices), top, skip), nil
+}
+
+func (r scopedRepository) GetByID(ctx context.Context, guid, tenantID string) (*entity.Device, error) {
+	d, err := r.Repository.GetByID(ctx, guid, tenantID)
+	if err != nil || d == nil {
+		return d, err
+	}
+
+	if !devicescope.FromContext(ctx).Allows(splitTags(d.Tags)) {
+		return nil, nil
+	}
+
+	return d, nil
+}
+
+func (r scopedRepository) GetDistinctTags(ctx context.Context, tenantID string) ([]string, error) {
+	scope := devicescope.FromContext(ctx)
+	if scope == nil {
+		return r.Repository.GetDistinctTags(ctx, tenantID)
+	}
+
+	devices, err := r.visible(ctx, scope, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	tags := make([]string, 0)
+
+	for i := range devices {
+		for _, tag := range splitTags(devices[i].Tags) {
+			if !seen[tag] {
+				seen[tag] = true
+
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	sort.Strings(tags)
+
+	return tags, nil
+}
+
+func (r scopedRepository) GetByTags(ctx context.Context, tags []string, method string, limit, offset int, tenantID string) ([]entity.Device, error) {
+	if devicescope.FromContext(ctx) == nil {
+		return r.Repository.GetByTags(ctx, tags, method, limit, offset, tenantID)
+	}
+
+	devices, err := r.Repository.GetByTags(ctx, tags, method, 0, 0, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return page(r.filter(ctx, devices), limit, offset), nil
+}
+
+func (r scopedRepository) GetByColumn(ctx context.Context, columnName, queryValue, tenantID string) ([]entity.Device, error) {
+	devices, err := r.Repository.GetByColumn(ctx, columnName, queryValue, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.filter(ctx, devices), nil
+}
+
+// Delete reports false, as for a missing device, when guid is out of scope.
+func (r scopedRepository) Delete(ctx context.Context, guid, tenantID string) (bool, error) {
+	if ok, err := r.inScope(ctx, guid, tenantID); !ok || err != nil {
+		return false, err
+	}
+
+	return r.Repository.Delete(ctx, guid, tenantID)
+}
+
+// Update reports false, as for a missing device, when d is out of scope as
+// stored. The use case checks the tags it is given.
+func (r scopedRepository) Update(ctx context.Context, d *entity.Device) (bool, error) {
+	if ok, err := r.inScope(ctx, d.GUID, d.TenantID); !ok || err != nil {
+		return false, err
+	}
+
+	return r.Repository.Update(ctx, d)
+}
+
+func (r scopedRepository) inScope(ctx context.Context, guid, tenantID string) (bool, error) {
+	if devicescope.FromContext(ctx) == nil {
+		return true, nil
+	}
+
+	d, err := r.GetByID(ctx, guid, tenantID)
+
+	return d != nil, err
+}
+
+// checkScope refuses tags that would place a device out of the caller's scope.
+func checkScope(ctx context.Context, call, tags string) error {
+	if !devicescope.FromContext(ctx).Allows(splitTags(tags)) {
+		return ErrNotValid.Wrap(call, "devicescope.Allows", errTagsOutOfScope)
+	}
+
+	return nil
+}
+
+func splitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+
+	return strings.Split(tags, ",")
+}
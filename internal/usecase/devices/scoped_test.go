@@ -0,0 +1,123 @@
+package devices_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/odata"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+)
+
+func siteAScope() context.Context {
+	return devicescope.WithScope(context.Background(), &devicescope.Scope{Expressions: []devicescope.Expression{
+		{Tags: []string{"site-a"}},
+		{Tags: []string{"lab", "kiosk"}, All: true},
+	}})
+}
+
+func TestScopedLists(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _ := devicesTest(t)
+	ctx := siteAScope()
+
+	repo.EXPECT().GetByTags(ctx, []string{"site-a"}, "OR", 0, 0, "").
+		Return([]entity.Device{{GUID: "guid-3", Tags: "site-a"}, {GUID: "guid-1", Tags: "site-a,lab,kiosk"}}, nil).Times(3)
+	repo.EXPECT().GetByTags(ctx, []string{"lab", "kiosk"}, "AND", 0, 0, "").
+		Return([]entity.Device{{GUID: "guid-1", Tags: "site-a,lab,kiosk"}, {GUID: "guid-2", Tags: "lab,kiosk"}}, nil).Times(3)
+
+	count, err := useCase.GetCount(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	items, err := useCase.Get(ctx, 2, 1, "")
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, "guid-2", items[0].GUID)
+	require.Equal(t, "guid-3", items[1].GUID)
+
+	tags, err := useCase.GetDistinctTags(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"kiosk", "lab", "site-a"}, tags)
+
+	repo.EXPECT().GetByColumn(ctx, "HostName", "host", "").
+		Return([]entity.Device{{GUID: "guid-4", Tags: "site-b"}, {GUID: "guid-3", Tags: "site-a"}}, nil)
+
+	items, err = useCase.GetByColumn(ctx, "HostName", "host", "")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "guid-3", items[0].GUID)
+}
+
+func TestScopedODataQuery(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _ := devicesTest(t)
+
+	q, err := odata.Parse("connectionStatus eq true or startswith(hostname,'KIOSK')", "hostname desc", "", odata.Fields{
+		"hostname":         odata.String,
+		"connectionStatus": odata.Bool,
+	})
+	require.NoError(t, err)
+
+	ctx := odata.WithQuery(siteAScope(), q)
+
+	repo.EXPECT().GetByTags(ctx, []string{"site-a"}, "OR", 0, 0, "").
+		Return([]entity.Device{
+			{GUID: "guid-3", Hostname: "kiosk-b", Tags: "site-a"},
+			{GUID: "guid-1", Hostname: "desk", Tags: "site-a", ConnectionStatus: true},
+			{GUID: "guid-4", Hostname: "printer", Tags: "site-a"},
+		}, nil).Times(2)
+	repo.EXPECT().GetByTags(ctx, []string{"lab", "kiosk"}, "AND", 0, 0, "").
+		Return([]entity.Device{{GUID: "guid-2", Hostname: "kiosk-a", Tags: "lab,kiosk"}}, nil).Times(2)
+
+	count, err := useCase.GetCount(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	items, err := useCase.Get(ctx, 2, 0, "")
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, "guid-3", items[0].GUID)
+	require.Equal(t, "guid-2", items[1].GUID)
+}
+
+func TestScopedLookups(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _ := devicesTest(t)
+	ctx := siteAScope()
+
+	repo.EXPECT().GetByID(ctx, "guid-4", "").Return(&entity.Device{GUID: "guid-4", Tags: "site-b"}, nil).Times(3)
+
+	var notFound repoerrors.NotFoundError
+
+	_, err := useCase.GetByID(ctx, "guid-4", "", false)
+	require.ErrorAs(t, err, &notFound, "out-of-scope devices are invisible")
+
+	_, err = useCase.GetPowerState(ctx, "guid-4")
+	require.ErrorAs(t, err, &notFound)
+
+	err = useCase.Delete(ctx, "guid-4", "")
+	require.ErrorAs(t, err, &notFound)
+
+	var notValid dto.NotValidError
+
+	_, err = useCase.Insert(ctx, &dto.Device{GUID: "guid-5", Tags: []string{"site-b"}})
+	require.ErrorAs(t, err, &notValid, "a device cannot be added outside the scope")
+
+	// Unscoped callers reach every device.
+	repo.EXPECT().GetByID(context.Background(), "guid-4", "").Return(&entity.Device{GUID: "guid-4", Tags: "site-b"}, nil)
+
+	d, err := useCase.GetByID(context.Background(), "guid-4", "", false)
+	require.NoError(t, err)
+	require.Equal(t, "guid-4", d.GUID)
+
+	require.Equal(t, devices.NewScopedRepository(repo), devices.NewScopedRepository(devices.NewScopedRepository(repo)), "wrapping twice is harmless")
+}
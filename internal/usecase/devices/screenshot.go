@@ -0,0 +1,308 @@
+package devices
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"time"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman/ips/optin"
+
+	"github.com/device-management-toolkit/console/internal/tenancy"
+	"github.com/device-management-toolkit/console/internal/usecase/devices/wsman"
+)
+
+// RFB (VNC) messages used to grab a single frame. After the KVM redirection
+// session is authenticated AMT speaks plain RFB 3.8 with the device as server.
+const (
+	rfbVersion                  = "RFB 003.008\n"
+	rfbVersionSize              = 12
+	rfbSecurityNone             = 1
+	rfbSecurityResultSize       = 4
+	rfbServerInitSize           = 24
+	rfbRectangleHeaderSize      = 12
+	rfbSetPixelFormat           = 0
+	rfbSetEncodings             = 2
+	rfbFramebufferUpdateRequest = 3
+	rfbEncodingRaw              = 0
+
+	rfbFramebufferUpdate = 0
+	rfbSetColourMap      = 1
+	rfbBell              = 2
+	rfbServerCutText     = 3
+
+	// AMT only serves 8 and 16 bit colour; RGB565 is its default.
+	rfbBytesPerPixel = 2
+
+	kvmModeName = "kvm"
+	// kvmMaxFramebufferPixels guards against a bogus ServerInit (8K UHD).
+	kvmMaxFramebufferPixels = 7680 * 4320
+	kvmScreenshotTimeout    = 30 * time.Second
+)
+
+var (
+	ErrUserConsentRequired = errors.New("user consent required: enter the code displayed on the device and retry")
+
+	// rfbPixelFormatRGB565 is 16 bpp, depth 16, little endian, true colour,
+	// max 31/63/31, shifts 11/5/0.
+	rfbPixelFormatRGB565 = []byte{16, 16, 0, 1, 0, 31, 0, 63, 0, 31, 11, 5, 0, 0, 0, 0}
+)
+
+// GetKVMScreenshot captures the device's screen as a PNG over a short headless
+// KVM session. When the device requires user consent for KVM and none has been
+// given, it starts the opt-in (AMT shows a code on screen) and returns
+// ErrUserConsentRequired; the caller submits the code and retries.
+func (uc *UseCase) GetKVMScreenshot(c context.Context, guid string) ([]byte, error) {
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
+	if err != nil {
+		return nil, err
+	}
+
+	if item == nil || item.GUID == "" {
+		return nil, ErrNotFound
+	}
+
+	device, err := uc.device.SetupWsmanClient(c, *item, false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireKVMConsent(device); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(c, kvmScreenshotTimeout)
+	defer cancel()
+
+	h, err := uc.connectHeadlessRedirection(ctx, *item, kvmModeName, startRedirectionKVM)
+	if err != nil {
+		return nil, err
+	}
+
+	defer h.close()
+
+	// Receives from AMT ignore ctx; closing the connection unblocks them.
+	stop := context.AfterFunc(ctx, h.close)
+	defer stop()
+
+	img, err := captureFramebuffer(h)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("kvm screenshot: %w", ctx.Err())
+		}
+
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// requireKVMConsent returns ErrUserConsentRequired until the code displayed on
+// the device has been entered, starting the opt-in when none is under way.
+func requireKVMConsent(device wsman.Management) error {
+	response, err := device.GetIPSOptInService()
+	if err != nil {
+		return err
+	}
+
+	service := response.Body.GetAndPutResponse
+
+	if optin.OptInRequired(service.OptInRequired) == optin.OptInRequiredNone {
+		return nil
+	}
+
+	switch optin.OptInState(service.OptInState) {
+	case optin.Received, optin.InSession:
+		return nil
+	case optin.NotStarted:
+		if _, err := device.GetUserConsentCode(); err != nil {
+			return err
+		}
+	case optin.Requested, optin.Displayed:
+	}
+
+	return ErrUserConsentRequired
+}
+
+// captureFramebuffer negotiates RFB on an authenticated KVM session, asks for
+// the whole screen and decodes the first framebuffer update.
+func captureFramebuffer(h *headlessRedirection) (*image.RGBA, error) {
+	width, height, err := rfbHandshake(h)
+	if err != nil {
+		return nil, err
+	}
+
+	request := []byte{rfbSetPixelFormat, 0, 0, 0}
+	request = append(request, rfbPixelFormatRGB565...)
+	request = append(request, rfbSetEncodings, 0)
+	request = binary.BigEndian.AppendUint16(request, 1)
+	request = binary.BigEndian.AppendUint32(request, rfbEncodingRaw)
+	request = append(request, rfbFramebufferUpdateRequest, 0, 0, 0, 0, 0)
+	request = binary.BigEndian.AppendUint16(request, uint16(width))  //nolint:gosec // read from a uint16
+	request = binary.BigEndian.AppendUint16(request, uint16(height)) //nolint:gosec // read from a uint16
+
+	if err := h.send(request); err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for {
+		header, err := h.take(1)
+		if err != nil {
+			return nil, err
+		}
+
+		switch header[0] {
+		case rfbFramebufferUpdate:
+			return img, readFramebufferUpdate(h, img)
+		case rfbSetColourMap:
+			fixed, err := h.take(5)
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = h.take(6 * int(binary.BigEndian.Uint16(fixed[3:5])))
+			if err != nil {
+				return nil, err
+			}
+		case rfbBell:
+		case rfbServerCutText:
+			fixed, err := h.take(7)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := h.take(int(binary.BigEndian.Uint32(fixed[3:7]))); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%w: RFB message type %d", ErrRedirectionProtocol, header[0])
+		}
+	}
+}
+
+// rfbHandshake performs version and security negotiation plus initialisation
+// and returns the framebuffer size. Security is "None": the redirection layer
+// has already authenticated the session.
+func rfbHandshake(h *headlessRedirection) (width, height int, err error) {
+	if _, err = h.take(rfbVersionSize); err != nil {
+		return 0, 0, err
+	}
+
+	if err = h.send([]byte(rfbVersion)); err != nil {
+		return 0, 0, err
+	}
+
+	count, err := h.take(1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if count[0] == 0 {
+		return 0, 0, fmt.Errorf("%w: RFB connection refused", ErrRedirectionRejected)
+	}
+
+	if _, err = h.take(int(count[0])); err != nil {
+		return 0, 0, err
+	}
+
+	if err = h.send([]byte{rfbSecurityNone}); err != nil {
+		return 0, 0, err
+	}
+
+	result, err := h.take(rfbSecurityResultSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if binary.BigEndian.Uint32(result) != 0 {
+		return 0, 0, ErrRedirectionAuthFailed
+	}
+
+	// ClientInit: shared, so an open browser session is not disconnected.
+	if err = h.send([]byte{1}); err != nil {
+		return 0, 0, err
+	}
+
+	serverInit, err := h.take(rfbServerInitSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	width = int(binary.BigEndian.Uint16(serverInit[0:2]))
+	height = int(binary.BigEndian.Uint16(serverInit[2:4]))
+
+	if _, err = h.take(int(binary.BigEndian.Uint32(serverInit[20:24]))); err != nil {
+		return 0, 0, err
+	}
+
+	if width == 0 || height == 0 || width*height > kvmMaxFramebufferPixels {
+		return 0, 0, fmt.Errorf("%w: framebuffer size %dx%d", ErrRedirectionProtocol, width, height)
+	}
+
+	return width, height, nil
+}
+
+// readFramebufferUpdate paints the raw RGB565 rectangles of one update into img.
+func readFramebufferUpdate(h *headlessRedirection, img *image.RGBA) error {
+	fixed, err := h.take(3)
+	if err != nil {
+		return err
+	}
+
+	rects := int(binary.BigEndian.Uint16(fixed[1:3]))
+
+	for range rects {
+		header, err := h.take(rfbRectangleHeaderSize)
+		if err != nil {
+			return err
+		}
+
+		x := int(binary.BigEndian.Uint16(header[0:2]))
+		y := int(binary.BigEndian.Uint16(header[2:4]))
+		w := int(binary.BigEndian.Uint16(header[4:6]))
+		rh := int(binary.BigEndian.Uint16(header[6:8]))
+		encoding := int32(binary.BigEndian.Uint32(header[8:12])) //nolint:gosec // RFB encodings are signed
+
+		if encoding != rfbEncodingRaw {
+			return fmt.Errorf("%w: RFB encoding %d", ErrRedirectionProtocol, encoding)
+		}
+
+		if !image.Rect(x, y, x+w, y+rh).In(img.Bounds()) {
+			return fmt.Errorf("%w: rectangle %dx%d+%d+%d outside framebuffer", ErrRedirectionProtocol, w, rh, x, y)
+		}
+
+		pixels, err := h.take(w * rh * rfbBytesPerPixel)
+		if err != nil {
+			return err
+		}
+
+		for i := range w * rh {
+			img.SetRGBA(x+i%w, y+i/w, rgb565(binary.LittleEndian.Uint16(pixels[i*rfbBytesPerPixel:])))
+		}
+	}
+
+	return nil
+}
+
+func rgb565(v uint16) color.RGBA {
+	r, g, b := v>>11&0x1F, v>>5&0x3F, v&0x1F
+
+	return color.RGBA{
+		R: uint8(r<<3 | r>>2), //nolint:gosec // 5 bits scaled to 8
+		G: uint8(g<<2 | g>>4), //nolint:gosec // 6 bits scaled to 8
+		B: uint8(b<<3 | b>>2), //nolint:gosec // 5 bits scaled to 8
+		A: 0xFF,
+	}
+}
@@ -0,0 +1,164 @@
+package devices_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman"
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman/ips/optin"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	devices "github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// fakeKVM extends fakeAMT with the RFB server AMT exposes once a KVM
+// redirection session is authenticated. The framebuffer is 2x1: red, blue.
+type fakeKVM struct {
+	*fakeAMT
+
+	securityDone bool
+}
+
+func (f *fakeKVM) send(ctx context.Context, conn *devices.DeviceConnection, data []byte) error {
+	switch {
+	case data[0] == devices.RedirectionCommandsStartRedirectionSession,
+		data[0] == devices.RedirectionCommandsEndRedirectionSession:
+		return f.fakeAMT.send(ctx, conn, data)
+	case data[0] == devices.RedirectionCommandsAuthenticateSession:
+		authenticated := data[4] == devices.AuthenticationTypeDigest && f.digestSeen > 0
+
+		f.authenticate(data[4])
+
+		if authenticated {
+			f.replies <- []byte("RFB 004.000\n")
+		}
+	case string(data) == "RFB 003.008\n":
+		f.replies <- []byte{1, 1}
+	case len(data) == 1 && !f.securityDone:
+		f.securityDone = true
+		f.replies <- []byte{0, 0, 0, 0}
+	case len(data) == 1:
+		serverInit := []byte{0, 2, 0, 1}
+		serverInit = append(serverInit, make([]byte, 16)...)
+		serverInit = binary.BigEndian.AppendUint32(serverInit, 3)
+		f.replies <- append(serverInit, "AMT"...)
+	case data[0] == 0: // SetPixelFormat, SetEncodings and FramebufferUpdateRequest
+		update := []byte{2, 0, 0, 0, 1, 0, 0, 0, 0, 0, 2, 0, 1, 0, 0, 0, 0}
+		update = binary.LittleEndian.AppendUint16(update, 0xF800)
+		update = binary.LittleEndian.AppendUint16(update, 0x001F)
+		f.replies <- update
+	}
+
+	return nil
+}
+
+// scriptedDevice plays the device side of a redirection session.
+type scriptedDevice interface {
+	send(ctx context.Context, conn *devices.DeviceConnection, data []byte) error
+	listen(ctx context.Context, conn *devices.DeviceConnection) ([]byte, error)
+}
+
+// initHeadlessTest wires a use case whose redirection traffic goes to amt and
+// whose WS-MAN calls go to the returned management mock.
+func initHeadlessTest(t *testing.T, amt scriptedDevice) (*devices.UseCase, *mocks.MockDeviceManagementRepository, *mocks.MockManagement) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	repo := mocks.NewMockDeviceManagementRepository(mockCtl)
+	management := mocks.NewMockManagement(mockCtl)
+	wsmanMock := mocks.NewMockWSMAN(mockCtl)
+	wsmanMock.EXPECT().Worker().Return().AnyTimes()
+	wsmanMock.EXPECT().SetupWsmanClient(gomock.Any(), gomock.Any(), false, true).Return(management, nil).AnyTimes()
+
+	redirection := mocks.NewMockRedirection(mockCtl)
+	redirection.EXPECT().SetupWsmanClient(gomock.Any(), gomock.Any(), true, true).Return(wsman.Messages{}, nil).AnyTimes()
+	redirection.EXPECT().RedirectConnect(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	redirection.EXPECT().RedirectClose(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	redirection.EXPECT().RedirectSend(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(amt.send).AnyTimes()
+	redirection.EXPECT().RedirectListen(gomock.Any(), gomock.Any()).DoAndReturn(amt.listen).AnyTimes()
+
+	u := devices.New(repo, wsmanMock, redirection, logger.New("error"), mocks.MockCrypto{})
+
+	return u, repo, management
+}
+
+func optInService(required optin.OptInRequired, state optin.OptInState) optin.Response {
+	var response optin.Response
+
+	response.Body.GetAndPutResponse.OptInRequired = uint32(required)
+	response.Body.GetAndPutResponse.OptInState = int(state)
+
+	return response
+}
+
+func TestGetKVMScreenshot(t *testing.T) {
+	t.Parallel()
+
+	device := &entity.Device{GUID: "kvm-guid", Username: "admin", Password: "encrypted"}
+
+	t.Run("decodes the framebuffer to png", func(t *testing.T) {
+		t.Parallel()
+
+		kvm := &fakeKVM{fakeAMT: newFakeAMT(devices.StartRedirectionSessionReplyStatusSuccess)}
+		useCase, repo, management := initHeadlessTest(t, kvm)
+		repo.EXPECT().GetByID(gomock.Any(), device.GUID, "").Return(device, nil)
+		management.EXPECT().GetIPSOptInService().Return(optInService(optin.OptInRequiredKVM, optin.Received), nil)
+
+		data, err := useCase.GetKVMScreenshot(context.Background(), device.GUID)
+		require.NoError(t, err)
+
+		img, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, 2, img.Bounds().Dx())
+		require.Equal(t, 1, img.Bounds().Dy())
+		require.Equal(t, color.RGBA{R: 0xFF, A: 0xFF}, color.RGBAModel.Convert(img.At(0, 0)))
+		require.Equal(t, color.RGBA{B: 0xFF, A: 0xFF}, color.RGBAModel.Convert(img.At(1, 0)))
+
+		sessions, err := useCase.GetRedirectionSessions(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+	})
+
+	t.Run("starts user consent when required", func(t *testing.T) {
+		t.Parallel()
+
+		useCase, repo, management := initHeadlessTest(t, &fakeKVM{fakeAMT: newFakeAMT(0)})
+		repo.EXPECT().GetByID(gomock.Any(), device.GUID, "").Return(device, nil)
+		management.EXPECT().GetIPSOptInService().Return(optInService(optin.OptInRequiredAll, optin.NotStarted), nil)
+		management.EXPECT().GetUserConsentCode().Return(optin.Response{}, nil)
+
+		_, err := useCase.GetKVMScreenshot(context.Background(), device.GUID)
+		require.ErrorIs(t, err, devices.ErrUserConsentRequired)
+	})
+
+	t.Run("waits for a displayed consent code", func(t *testing.T) {
+		t.Parallel()
+
+		useCase, repo, management := initHeadlessTest(t, &fakeKVM{fakeAMT: newFakeAMT(0)})
+		repo.EXPECT().GetByID(gomock.Any(), device.GUID, "").Return(device, nil)
+		management.EXPECT().GetIPSOptInService().Return(optInService(optin.OptInRequiredKVM, optin.Displayed), nil)
+
+		_, err := useCase.GetKVMScreenshot(context.Background(), device.GUID)
+		require.ErrorIs(t, err, devices.ErrUserConsentRequired)
+	})
+
+	t.Run("device not found", func(t *testing.T) {
+		t.Parallel()
+
+		useCase, repo, _ := initHeadlessTest(t, &fakeKVM{fakeAMT: newFakeAMT(0)})
+		repo.EXPECT().GetByID(gomock.Any(), "missing", "").Return(nil, nil)
+
+		_, err := useCase.GetKVMScreenshot(context.Background(), "missing")
+		require.ErrorIs(t, err, devices.ErrNotFound)
+	})
+}
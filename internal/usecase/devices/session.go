@@ -0,0 +1,421 @@
+package devices
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/tenancy"
+)
+
+// Reasons recorded when a redirection session ends.
+const (
+	SessionStopReasonClosed      = "closed"
+	SessionStopReasonIdle        = "idle timeout"
+	SessionStopReasonMaxDuration = "maximum session duration reached"
+	SessionStopReasonTerminated  = "terminated by administrator"
+
+	sessionWarningType  = "sessionWarning"
+	policyCheckInterval = time.Second
+)
+
+// SessionPolicy limits one redirection mode. Zero disables a limit.
+// BandwidthLimit is in bytes per second, both directions combined.
+type SessionPolicy struct {
+	IdleTimeout    time.Duration
+	MaxDuration    time.Duration
+	BandwidthLimit int
+}
+
+// SessionPolicies maps redirection modes ("kvm", "sol", "ider") to their
+// limits. WarningPeriod is how long before a cut-off the browser is warned.
+// BandwidthLimit caps all sessions together, in bytes per second.
+type SessionPolicies struct {
+	Modes          map[string]SessionPolicy
+	WarningPeriod  time.Duration
+	BandwidthLimit int
+}
+
+// SessionOrigin identifies who opened a redirection session.
+type SessionOrigin struct {
+	User       string
+	RemoteAddr string
+}
+
+type sessionOriginKey struct{}
+
+// WithSessionOrigin attaches the requesting operator to ctx so the session
+// can be attributed in the session list and audit entries.
+func WithSessionOrigin(ctx context.Context, origin SessionOrigin) context.Context {
+	return context.WithValue(ctx, sessionOriginKey{}, origin)
+}
+
+func sessionOriginFrom(ctx context.Context) SessionOrigin {
+	origin, _ := ctx.Value(sessionOriginKey{}).(SessionOrigin)
+
+	return origin
+}
+
+// AuditRecorder appends to the console's audit trail.
+type AuditRecorder interface {
+	Record(ctx context.Context, e *dto.AuditEvent) error
+}
+
+// SetAuditRecorder makes session starts and stops part of the audit trail;
+// without one they are only logged. Call it before serving requests.
+func (uc *UseCase) SetAuditRecorder(r AuditRecorder) {
+	uc.audit = r
+}
+
+// SetSessionPolicies replaces the session limits. Sessions already running
+// pick up new idle and duration limits on their next check and the new global
+// bandwidth cap immediately; their own bandwidth cap is fixed at start.
+func (uc *UseCase) SetSessionPolicies(policies SessionPolicies) {
+	uc.policyMutex.Lock()
+	defer uc.policyMutex.Unlock()
+
+	uc.policies = policies
+	uc.bandwidth = newBandwidthLimiter(policies.BandwidthLimit)
+}
+
+func (uc *UseCase) sessionPolicy(mode string) (SessionPolicy, time.Duration) {
+	uc.policyMutex.RLock()
+	defer uc.policyMutex.RUnlock()
+
+	return uc.policies.Modes[mode], uc.policies.WarningPeriod
+}
+
+// GetRedirectionSessions lists the active browser and headless sessions of
+// ctx's tenant, oldest first.
+func (uc *UseCase) GetRedirectionSessions(ctx context.Context) ([]dto.RedirectionSession, error) {
+	tenantID := tenancy.FromContext(ctx)
+
+	uc.redirMutex.RLock()
+
+	conns := make([]*DeviceConnection, 0, len(uc.sessions))
+	for _, conn := range uc.sessions {
+		if conn.Device.TenantID == tenantID {
+			conns = append(conns, conn)
+		}
+	}
+
+	uc.redirMutex.RUnlock()
+
+	sessions := make([]dto.RedirectionSession, 0, len(conns))
+
+	for _, conn := range conns {
+		policy, _ := uc.sessionPolicy(conn.Mode)
+
+		conn.mu.RLock()
+		session := dto.RedirectionSession{
+			ID:          conn.ID,
+			GUID:        conn.Device.GUID,
+			Mode:        conn.Mode,
+			Headless:    conn.Headless,
+			User:        conn.Origin.User,
+			RemoteAddr:  conn.Origin.RemoteAddr,
+			StartedAt:   conn.StartedAt,
+			LastInputAt: conn.lastInput,
+		}
+		conn.mu.RUnlock()
+
+		if policy.MaxDuration > 0 {
+			expiresAt := session.StartedAt.Add(policy.MaxDuration)
+			session.ExpiresAt = &expiresAt
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	slices.SortFunc(sessions, func(a, b dto.RedirectionSession) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+
+	return sessions, nil
+}
+
+// TerminateRedirectionSession ends an active session and frees the device's
+// redirection slot.
+func (uc *UseCase) TerminateRedirectionSession(ctx context.Context, id string) error {
+	uc.redirMutex.RLock()
+	conn, ok := uc.sessions[id]
+	uc.redirMutex.RUnlock()
+
+	if !ok || conn.Device.TenantID != tenancy.FromContext(ctx) {
+		return ErrNotFound.WrapWithMessage("TerminateRedirectionSession", "uc.sessions", "redirection session not found")
+	}
+
+	uc.stopSession(conn, SessionStopReasonTerminated)
+
+	return nil
+}
+
+// registerSession makes conn visible to the session API, records the start
+// and begins enforcing its mode's limits. A reused browser connection keeps
+// its original registration.
+func (uc *UseCase) registerSession(ctx context.Context, conn *DeviceConnection, terminate func(reason string)) {
+	now := time.Now()
+
+	conn.mu.Lock()
+	conn.terminate = terminate
+
+	if conn.ID != "" {
+		conn.mu.Unlock()
+
+		return
+	}
+
+	policy, _ := uc.sessionPolicy(conn.Mode)
+
+	conn.ID = uuid.NewString()
+	conn.bandwidth = newBandwidthLimiter(policy.BandwidthLimit)
+	conn.StartedAt = now
+	conn.lastInput = now
+	conn.Origin = sessionOriginFrom(ctx)
+	conn.mu.Unlock()
+
+	uc.redirMutex.Lock()
+	uc.sessions[conn.ID] = conn
+	uc.redirMutex.Unlock()
+
+	uc.auditSession("started", conn)
+
+	go uc.enforceSessionPolicy(conn)
+}
+
+// unregisterSession records the stop once, however the session ended.
+func (uc *UseCase) unregisterSession(conn *DeviceConnection) {
+	conn.mu.RLock()
+	id := conn.ID
+	conn.mu.RUnlock()
+
+	uc.redirMutex.Lock()
+	_, ok := uc.sessions[id]
+	delete(uc.sessions, id)
+	uc.redirMutex.Unlock()
+
+	if ok {
+		uc.auditSession("stopped", conn)
+	}
+}
+
+// stopSession ends conn for reason; the first reason recorded wins.
+func (uc *UseCase) stopSession(conn *DeviceConnection, reason string) {
+	conn.mu.Lock()
+	if conn.stopReason == "" {
+		conn.stopReason = reason
+	}
+
+	terminate := conn.terminate
+	conn.mu.Unlock()
+
+	if terminate != nil {
+		terminate(reason)
+	}
+}
+
+// markInput records operator activity, resetting the idle timer.
+func (uc *UseCase) markInput(conn *DeviceConnection) {
+	conn.mu.Lock()
+	conn.lastInput = time.Now()
+	conn.idleWarned = false
+	conn.mu.Unlock()
+}
+
+// isOperatorInput reports whether a browser message reflects someone at the
+// console. KVM viewers poll for framebuffer updates on their own, so only key,
+// pointer and clipboard events count; for SOL only keystrokes count. IDER
+// traffic is disk I/O and always counts.
+func isOperatorInput(mode string, direct bool, msg []byte) bool {
+	if !direct || len(msg) == 0 {
+		return false
+	}
+
+	switch mode {
+	case "kvm":
+		const rfbKeyEvent, rfbPointerEvent, rfbClientCutText = 4, 5, 6
+
+		return msg[0] == rfbKeyEvent || msg[0] == rfbPointerEvent || msg[0] == rfbClientCutText
+	case "sol":
+		return msg[0] == RedirectionCommandsSOLDataToHost
+	default:
+		return true
+	}
+}
+
+func (uc *UseCase) enforceSessionPolicy(conn *DeviceConnection) {
+	ticker := time.NewTicker(policyCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-conn.ctx.Done():
+			return
+		case now := <-ticker.C:
+			if reason := uc.checkSessionPolicy(conn, now); reason != "" {
+				uc.stopSession(conn, reason)
+
+				return
+			}
+		}
+	}
+}
+
+// checkSessionPolicy returns why conn must end now, if it must, and warns the
+// browser once per limit as the cut-off approaches.
+func (uc *UseCase) checkSessionPolicy(conn *DeviceConnection, now time.Time) string {
+	policy, warningPeriod := uc.sessionPolicy(conn.Mode)
+
+	var warnings []dto.RedirectionSessionWarning
+
+	conn.mu.Lock()
+
+	if policy.MaxDuration > 0 {
+		remaining := conn.StartedAt.Add(policy.MaxDuration).Sub(now)
+		if remaining <= 0 {
+			conn.mu.Unlock()
+
+			return SessionStopReasonMaxDuration
+		}
+
+		if remaining <= warningPeriod && !conn.expiryWarned {
+			conn.expiryWarned = true
+			warnings = append(warnings, newSessionWarning(SessionStopReasonMaxDuration, remaining))
+		}
+	}
+
+	if policy.IdleTimeout > 0 {
+		remaining := conn.lastInput.Add(policy.IdleTimeout).Sub(now)
+		if remaining <= 0 {
+			conn.mu.Unlock()
+
+			return SessionStopReasonIdle
+		}
+
+		if remaining <= warningPeriod && !conn.idleWarned {
+			conn.idleWarned = true
+			warnings = append(warnings, newSessionWarning(SessionStopReasonIdle, remaining))
+		}
+	}
+
+	conn.mu.Unlock()
+
+	for _, warning := range warnings {
+		uc.warnSession(conn, warning)
+	}
+
+	return ""
+}
+
+func newSessionWarning(reason string, remaining time.Duration) dto.RedirectionSessionWarning {
+	return dto.RedirectionSessionWarning{
+		Type:             sessionWarningType,
+		Reason:           reason,
+		SecondsRemaining: int(remaining.Round(time.Second).Seconds()),
+	}
+}
+
+func (uc *UseCase) warnSession(conn *DeviceConnection, warning dto.RedirectionSessionWarning) {
+	if conn.Headless || conn.Conn == nil {
+		uc.log.Debug("redirection session ending soon", "id", conn.ID, "reason", warning.Reason, "seconds", warning.SecondsRemaining)
+
+		return
+	}
+
+	data, err := json.Marshal(warning)
+	if err != nil {
+		return
+	}
+
+	_ = conn.writeMessage(websocket.TextMessage, data)
+}
+
+// writeMessage serializes writes to the browser websocket.
+func (conn *DeviceConnection) writeMessage(messageType int, data []byte) error {
+	conn.writeMu.Lock()
+	defer conn.writeMu.Unlock()
+
+	return conn.Conn.WriteMessage(messageType, data)
+}
+
+// terminateBrowserSession tells AMT and the browser the session is over and
+// closes both ends so the relay goroutines unblock.
+func (uc *UseCase) terminateBrowserSession(conn *DeviceConnection) func(reason string) {
+	return func(reason string) {
+		_ = uc.redirection.RedirectSend(conn.ctx, conn, endRedirection)
+
+		if conn.Conn != nil {
+			_ = conn.writeMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason))
+			_ = conn.Conn.Close()
+		}
+
+		conn.cancel()
+
+		if err := uc.redirection.RedirectClose(context.Background(), conn); err != nil {
+			uc.log.Debug("redirection session close", "id", conn.ID, "error", err)
+		}
+	}
+}
+
+// auditSession writes the audit entry for a session start or stop.
+func (uc *UseCase) auditSession(event string, conn *DeviceConnection) {
+	conn.mu.RLock()
+
+	message := fmt.Sprintf("audit: redirection session %s id=%s guid=%s mode=%s headless=%t user=%s remote=%s",
+		event, conn.ID, conn.Device.GUID, conn.Mode, conn.Headless, conn.Origin.User, conn.Origin.RemoteAddr)
+
+	e := &dto.AuditEvent{
+		Actor:    conn.Origin.User,
+		TenantID: conn.Device.TenantID,
+		Action:   "redirection." + event,
+		Target:   conn.Device.GUID,
+		Parameters: map[string]interface{}{
+			"sessionId": conn.ID,
+			"mode":      conn.Mode,
+			"headless":  conn.Headless,
+		},
+		Result:   "success",
+		SourceIP: remoteIP(conn.Origin.RemoteAddr),
+	}
+
+	if event == "stopped" {
+		reason := conn.stopReason
+		if reason == "" {
+			reason = SessionStopReasonClosed
+		}
+
+		duration := time.Since(conn.StartedAt).Round(time.Second)
+
+		message += fmt.Sprintf(" reason=%q duration=%s", reason, duration)
+		e.Parameters["reason"] = reason
+		e.Parameters["duration"] = duration.String()
+	}
+
+	conn.mu.RUnlock()
+
+	uc.log.Info(message)
+
+	if uc.audit == nil {
+		return
+	}
+
+	if err := uc.audit.Record(context.Background(), e); err != nil {
+		uc.log.Error("devices - auditSession - " + err.Error())
+	}
+}
+
+// remoteIP drops the port from a remote address, if it has one.
+func remoteIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
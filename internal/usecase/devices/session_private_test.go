@@ -0,0 +1,233 @@
+package devices
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/tenancy"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func newPolicyTestUseCase(policy SessionPolicy) *UseCase {
+	uc := &UseCase{
+		sessions: make(map[string]*DeviceConnection),
+		log:      logger.New("error"),
+	}
+
+	uc.SetSessionPolicies(SessionPolicies{
+		Modes:         map[string]SessionPolicy{"kvm": policy},
+		WarningPeriod: time.Minute,
+	})
+
+	return uc
+}
+
+func newPolicyTestConnection(t *testing.T) *DeviceConnection {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	return &DeviceConnection{
+		Device:   entity.Device{GUID: "device-guid"},
+		Mode:     "kvm",
+		Headless: true,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+func TestCheckSessionPolicy(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		policy       SessionPolicy
+		lastInput    time.Time
+		now          time.Time
+		reason       string
+		idleWarned   bool
+		expiryWarned bool
+	}{
+		{
+			name:      "no limits",
+			lastInput: start,
+			now:       start.Add(24 * time.Hour),
+		},
+		{
+			name:      "active session within limits",
+			policy:    SessionPolicy{IdleTimeout: 15 * time.Minute, MaxDuration: time.Hour},
+			lastInput: start.Add(10 * time.Minute),
+			now:       start.Add(12 * time.Minute),
+		},
+		{
+			name:       "idle warning",
+			policy:     SessionPolicy{IdleTimeout: 15 * time.Minute},
+			lastInput:  start,
+			now:        start.Add(14*time.Minute + 30*time.Second),
+			idleWarned: true,
+		},
+		{
+			name:      "idle timeout",
+			policy:    SessionPolicy{IdleTimeout: 15 * time.Minute},
+			lastInput: start,
+			now:       start.Add(15 * time.Minute),
+			reason:    SessionStopReasonIdle,
+		},
+		{
+			name:         "duration warning",
+			policy:       SessionPolicy{MaxDuration: time.Hour},
+			lastInput:    start.Add(59 * time.Minute),
+			now:          start.Add(59 * time.Minute),
+			expiryWarned: true,
+		},
+		{
+			name:      "maximum duration",
+			policy:    SessionPolicy{IdleTimeout: 15 * time.Minute, MaxDuration: time.Hour},
+			lastInput: start.Add(time.Hour),
+			now:       start.Add(time.Hour),
+			reason:    SessionStopReasonMaxDuration,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			uc := newPolicyTestUseCase(tc.policy)
+			conn := newPolicyTestConnection(t)
+			conn.StartedAt = start
+			conn.lastInput = tc.lastInput
+
+			require.Equal(t, tc.reason, uc.checkSessionPolicy(conn, tc.now))
+			require.Equal(t, tc.idleWarned, conn.idleWarned)
+			require.Equal(t, tc.expiryWarned, conn.expiryWarned)
+		})
+	}
+}
+
+func TestIsOperatorInput(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isOperatorInput("kvm", true, []byte{4, 1, 0, 0}))
+	assert.True(t, isOperatorInput("kvm", true, []byte{5, 0, 0, 10}))
+	assert.False(t, isOperatorInput("kvm", true, []byte{3, 1, 0, 0}))
+	assert.False(t, isOperatorInput("kvm", false, []byte{4, 1, 0, 0}))
+	assert.True(t, isOperatorInput("sol", true, []byte{RedirectionCommandsSOLDataToHost}))
+	assert.False(t, isOperatorInput("sol", true, []byte{RedirectionCommandsSOLHeartbeat}))
+	assert.True(t, isOperatorInput("ider", true, []byte{0x50}))
+	assert.False(t, isOperatorInput("ider", true, nil))
+}
+
+func TestTerminateRedirectionSession(t *testing.T) {
+	t.Parallel()
+
+	uc := newPolicyTestUseCase(SessionPolicy{MaxDuration: time.Hour})
+	conn := newPolicyTestConnection(t)
+
+	var reasons []string
+
+	ctx := WithSessionOrigin(context.Background(), SessionOrigin{User: "alice", RemoteAddr: "10.0.0.5"})
+	uc.registerSession(ctx, conn, func(reason string) {
+		reasons = append(reasons, reason)
+		conn.cancel()
+		uc.unregisterSession(conn)
+	})
+
+	sessions, err := uc.GetRedirectionSessions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, conn.ID, sessions[0].ID)
+	assert.Equal(t, "alice", sessions[0].User)
+	assert.Equal(t, "10.0.0.5", sessions[0].RemoteAddr)
+	require.NotNil(t, sessions[0].ExpiresAt)
+	assert.Equal(t, conn.StartedAt.Add(time.Hour), *sessions[0].ExpiresAt)
+
+	require.NoError(t, uc.TerminateRedirectionSession(context.Background(), conn.ID))
+	assert.Equal(t, []string{SessionStopReasonTerminated}, reasons)
+	assert.Equal(t, SessionStopReasonTerminated, conn.stopReason)
+
+	sessions, err = uc.GetRedirectionSessions(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	var notFound repoerrors.NotFoundError
+
+	err = uc.TerminateRedirectionSession(context.Background(), conn.ID)
+	require.ErrorAs(t, err, &notFound)
+}
+
+// auditLog records audit events in memory.
+type auditLog struct{ events []*dto.AuditEvent }
+
+func (a *auditLog) Record(_ context.Context, e *dto.AuditEvent) error {
+	a.events = append(a.events, e)
+
+	return nil
+}
+
+func TestRedirectionSessionsAreAudited(t *testing.T) {
+	t.Parallel()
+
+	uc := newPolicyTestUseCase(SessionPolicy{})
+	conn := newPolicyTestConnection(t)
+	conn.Device.TenantID = "acme"
+
+	audit := &auditLog{}
+	uc.SetAuditRecorder(audit)
+
+	ctx := WithSessionOrigin(context.Background(), SessionOrigin{User: "alice", RemoteAddr: "10.0.0.5:51234"})
+	uc.registerSession(ctx, conn, func(string) {})
+	uc.stopSession(conn, SessionStopReasonIdle)
+	uc.unregisterSession(conn)
+
+	require.Len(t, audit.events, 2)
+
+	started, stopped := audit.events[0], audit.events[1]
+	assert.Equal(t, "redirection.started", started.Action)
+	assert.Equal(t, "alice", started.Actor)
+	assert.Equal(t, "acme", started.TenantID)
+	assert.Equal(t, conn.Device.GUID, started.Target)
+	assert.Equal(t, "10.0.0.5", started.SourceIP)
+	assert.Equal(t, conn.ID, started.Parameters["sessionId"])
+	assert.Equal(t, "redirection.stopped", stopped.Action)
+	assert.Equal(t, SessionStopReasonIdle, stopped.Parameters["reason"])
+}
+
+func TestRedirectionSessionsAreTenantScoped(t *testing.T) {
+	t.Parallel()
+
+	uc := newPolicyTestUseCase(SessionPolicy{})
+	conn := newPolicyTestConnection(t)
+	conn.Device.TenantID = "acme"
+
+	uc.registerSession(context.Background(), conn, func(string) {
+		conn.cancel()
+		uc.unregisterSession(conn)
+	})
+
+	other := tenancy.WithTenant(context.Background(), "globex")
+
+	sessions, err := uc.GetRedirectionSessions(other)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	var notFound repoerrors.NotFoundError
+
+	err = uc.TerminateRedirectionSession(other, conn.ID)
+	require.ErrorAs(t, err, &notFound)
+
+	sessions, err = uc.GetRedirectionSessions(tenancy.WithTenant(context.Background(), "acme"))
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, conn.ID, sessions[0].ID)
+}
@@ -0,0 +1,201 @@
+package devices
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Serial-over-LAN message types exchanged after the redirection session is
+// authenticated.
+const (
+	RedirectionCommandsSOLSettings       = 0x20
+	RedirectionCommandsSOLSettingsReply  = 0x21
+	RedirectionCommandsSOLControl        = 0x27
+	RedirectionCommandsSOLDataToHost     = 0x28
+	RedirectionCommandsSOLSerialSettings = 0x29
+	RedirectionCommandsSOLDataFromHost   = 0x2A
+	RedirectionCommandsSOLHeartbeat      = 0x2B
+
+	solMessageHeaderSize     = 8 // command, 3 reserved bytes, 32-bit sequence
+	solDataHeaderSize        = 10
+	solSettingsReplySize     = 23
+	solSerialSettingsSize    = 10
+	solMaxTxBuffer           = 10000
+	solTxTimeout             = 100
+	solRxTimeout             = 10000
+	solRxFlushTimeout        = 100
+	solMaxPayload            = 1000
+	SOLKeepAliveInterval     = 2 * time.Second
+	solModeName              = "sol"
+	solControlRTSDTRFlags    = 0x1B
+	solSettingsPayloadLength = 16
+)
+
+// solSession is a console-side SOL client. Read returns the host's serial
+// output, Write sends keystrokes to the host.
+type solSession struct {
+	*headlessRedirection
+
+	unread []byte
+	done   chan struct{}
+	once   sync.Once
+}
+
+// OpenSOL starts a Serial-over-LAN session with the device and returns the
+// serial stream. The caller owns the session and must Close it.
+func (uc *UseCase) OpenSOL(c context.Context, guid string) (io.ReadWriteCloser, error) {
+	h, err := uc.openHeadlessRedirection(c, guid, solModeName, startRedirectionSOL)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &solSession{headlessRedirection: h, done: make(chan struct{})}
+
+	if err := s.start(); err != nil {
+		h.close()
+
+		return nil, err
+	}
+
+	go s.keepAlive()
+
+	return s, nil
+}
+
+func (s *solSession) start() error {
+	payload := make([]byte, 0, solSettingsPayloadLength)
+	payload = binary.LittleEndian.AppendUint16(payload, solMaxTxBuffer)
+	payload = binary.LittleEndian.AppendUint16(payload, solTxTimeout)
+	payload = binary.LittleEndian.AppendUint16(payload, 0) // TxOverflowTimeout
+	payload = binary.LittleEndian.AppendUint16(payload, solRxTimeout)
+	payload = binary.LittleEndian.AppendUint16(payload, solRxFlushTimeout)
+	payload = binary.LittleEndian.AppendUint16(payload, 0) // Heartbeat, driven by keepAlive instead
+	payload = binary.LittleEndian.AppendUint32(payload, 0)
+
+	if err := s.sendSequenced(RedirectionCommandsSOLSettings, payload); err != nil {
+		return err
+	}
+
+	header, err := s.peek(1)
+	if err != nil {
+		return err
+	}
+
+	if header[0] != RedirectionCommandsSOLSettingsReply {
+		return fmt.Errorf("%w: 0x%02x waiting for SOL settings reply", ErrRedirectionProtocol, header[0])
+	}
+
+	if _, err := s.take(solSettingsReplySize); err != nil {
+		return err
+	}
+
+	return s.sendSequenced(RedirectionCommandsSOLControl, []byte{0x00, 0x00, solControlRTSDTRFlags, 0x00, 0x00, 0x00})
+}
+
+// keepAlive sends heartbeats so AMT does not drop an idle console.
+func (s *solSession) keepAlive() {
+	ticker := time.NewTicker(SOLKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.conn.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.sendSequenced(RedirectionCommandsSOLHeartbeat, nil); err != nil {
+				s.uc.log.Debug("SOL heartbeat failed", "guid", s.conn.Device.GUID, "error", err)
+
+				return
+			}
+		}
+	}
+}
+
+// Read returns serial output from the host, skipping control traffic.
+func (s *solSession) Read(p []byte) (int, error) {
+	for len(s.unread) == 0 {
+		if err := s.nextMessage(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, s.unread)
+	s.unread = s.unread[n:]
+
+	return n, nil
+}
+
+func (s *solSession) nextMessage() error {
+	header, err := s.peek(1)
+	if err != nil {
+		return err
+	}
+
+	switch header[0] {
+	case RedirectionCommandsSOLDataFromHost:
+		fixed, err := s.peek(solDataHeaderSize)
+		if err != nil {
+			return err
+		}
+
+		length := int(binary.LittleEndian.Uint16(fixed[solMessageHeaderSize:solDataHeaderSize]))
+
+		msg, err := s.take(solDataHeaderSize + length)
+		if err != nil {
+			return err
+		}
+
+		s.unread = msg[solDataHeaderSize:]
+	case RedirectionCommandsSOLHeartbeat:
+		_, err = s.take(solMessageHeaderSize)
+	case RedirectionCommandsSOLSerialSettings:
+		_, err = s.take(solSerialSettingsSize)
+	case RedirectionCommandsSOLSettingsReply:
+		_, err = s.take(solSettingsReplySize)
+	case RedirectionCommandsEndRedirectionSession:
+		return io.EOF
+	default:
+		return fmt.Errorf("%w: 0x%02x in SOL stream", ErrRedirectionProtocol, header[0])
+	}
+
+	return err
+}
+
+// Write sends keystrokes to the host, split into AMT-sized frames.
+func (s *solSession) Write(p []byte) (int, error) {
+	s.uc.markInput(s.conn)
+
+	written := 0
+
+	for written < len(p) {
+		chunk := p[written:min(written+solMaxPayload, len(p))]
+
+		payload := make([]byte, 0, 2+len(chunk))
+		payload = binary.LittleEndian.AppendUint16(payload, uint16(len(chunk))) //nolint:gosec // bounded by solMaxPayload
+		payload = append(payload, chunk...)
+
+		if err := s.sendSequenced(RedirectionCommandsSOLDataToHost, payload); err != nil {
+			return written, err
+		}
+
+		written += len(chunk)
+	}
+
+	return written, nil
+}
+
+// Close ends the redirection session and releases the device connection.
+func (s *solSession) Close() error {
+	s.once.Do(func() {
+		close(s.done)
+		s.close()
+	})
+
+	return nil
+}
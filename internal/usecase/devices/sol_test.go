@@ -0,0 +1,165 @@
+package devices_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	devices "github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// fakeAMT answers redirection messages the way firmware does, one reply per
+// request, so the headless client can be driven end to end.
+type fakeAMT struct {
+	startStatus byte
+	replies     chan []byte
+	digestSeen  int
+
+	mu    sync.Mutex
+	typed []byte
+}
+
+func newFakeAMT(startStatus byte) *fakeAMT {
+	return &fakeAMT{startStatus: startStatus, replies: make(chan []byte, 16)}
+}
+
+func (f *fakeAMT) send(_ context.Context, _ *devices.DeviceConnection, data []byte) error {
+	switch data[0] {
+	case devices.RedirectionCommandsStartRedirectionSession:
+		f.replies <- []byte{devices.RedirectionCommandsStartRedirectionSessionReply, f.startStatus, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	case devices.RedirectionCommandsAuthenticateSession:
+		f.authenticate(data[4])
+	case devices.RedirectionCommandsSOLSettings:
+		f.replies <- append([]byte{devices.RedirectionCommandsSOLSettingsReply}, make([]byte, 22)...)
+	case devices.RedirectionCommandsSOLControl:
+		f.replies <- append([]byte{devices.RedirectionCommandsSOLHeartbeat, 0, 0, 0, 0, 0, 0, 0}, solFrame("login: ")...)
+	case devices.RedirectionCommandsSOLDataToHost:
+		length := binary.LittleEndian.Uint16(data[8:10])
+
+		f.mu.Lock()
+		f.typed = append(f.typed, data[10:10+length]...)
+		f.mu.Unlock()
+	}
+
+	return nil
+}
+
+func (f *fakeAMT) authenticate(authType byte) {
+	switch {
+	case authType == devices.AuthenticationTypeQuery:
+		f.replies <- []byte{devices.RedirectionCommandsAuthenticateSessionReply, 0, 0, 0, 0, 1, 0, 0, 0, devices.AuthenticationTypeDigest}
+	case f.digestSeen == 0:
+		f.digestSeen++
+
+		data := []byte{5, 'r', 'e', 'a', 'l', 'm', 5, 'n', 'o', 'n', 'c', 'e', 4, 'a', 'u', 't', 'h'}
+		reply := []byte{devices.RedirectionCommandsAuthenticateSessionReply, devices.AuthenticationStatusFail, 0, 0, devices.AuthenticationTypeDigest}
+		reply = binary.LittleEndian.AppendUint32(reply, uint32(len(data)))
+		f.replies <- append(reply, data...)
+	default:
+		f.replies <- []byte{devices.RedirectionCommandsAuthenticateSessionReply, devices.AuthenticationStatusSuccess, 0, 0, devices.AuthenticationTypeDigest, 0, 0, 0, 0}
+	}
+}
+
+func (f *fakeAMT) listen(ctx context.Context, _ *devices.DeviceConnection) ([]byte, error) {
+	select {
+	case reply := <-f.replies:
+		return reply, nil
+	case <-ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+func (f *fakeAMT) typedText() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return string(f.typed)
+}
+
+func solFrame(text string) []byte {
+	frame := []byte{devices.RedirectionCommandsSOLDataFromHost, 0, 0, 0, 0, 0, 0, 0}
+	frame = binary.LittleEndian.AppendUint16(frame, uint16(len(text)))
+
+	return append(frame, text...)
+}
+
+func initSOLTest(t *testing.T, amt *fakeAMT) (*devices.UseCase, *mocks.MockDeviceManagementRepository) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	repo := mocks.NewMockDeviceManagementRepository(mockCtl)
+	wsmanMock := mocks.NewMockWSMAN(mockCtl)
+	wsmanMock.EXPECT().Worker().Return().AnyTimes()
+
+	redirection := mocks.NewMockRedirection(mockCtl)
+	redirection.EXPECT().SetupWsmanClient(gomock.Any(), gomock.Any(), true, true).Return(wsman.Messages{}, nil).AnyTimes()
+	redirection.EXPECT().RedirectConnect(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	redirection.EXPECT().RedirectClose(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	redirection.EXPECT().RedirectSend(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(amt.send).AnyTimes()
+	redirection.EXPECT().RedirectListen(gomock.Any(), gomock.Any()).DoAndReturn(amt.listen).AnyTimes()
+
+	u := devices.New(repo, wsmanMock, redirection, logger.New("error"), mocks.MockCrypto{})
+
+	return u, repo
+}
+
+func TestOpenSOL(t *testing.T) {
+	t.Parallel()
+
+	device := &entity.Device{GUID: "sol-guid", Username: "admin", Password: "encrypted"}
+
+	t.Run("relays serial data after digest authentication", func(t *testing.T) {
+		t.Parallel()
+
+		amt := newFakeAMT(devices.StartRedirectionSessionReplyStatusSuccess)
+		useCase, repo := initSOLTest(t, amt)
+		repo.EXPECT().GetByID(gomock.Any(), device.GUID, "").Return(device, nil)
+
+		session, err := useCase.OpenSOL(context.Background(), device.GUID)
+		require.NoError(t, err)
+
+		buf := make([]byte, 32)
+		n, err := session.Read(buf)
+		require.NoError(t, err)
+		require.Equal(t, "login: ", string(buf[:n]))
+
+		_, err = session.Write([]byte("root\r"))
+		require.NoError(t, err)
+		require.Equal(t, "root\r", amt.typedText())
+
+		require.NoError(t, session.Close())
+		require.NoError(t, session.Close())
+	})
+
+	t.Run("busy redirection session", func(t *testing.T) {
+		t.Parallel()
+
+		amt := newFakeAMT(devices.StartRedirectionSessionReplyStatusBusy)
+		useCase, repo := initSOLTest(t, amt)
+		repo.EXPECT().GetByID(gomock.Any(), device.GUID, "").Return(device, nil)
+
+		_, err := useCase.OpenSOL(context.Background(), device.GUID)
+		require.ErrorIs(t, err, devices.ErrRedirectionBusy)
+	})
+
+	t.Run("device not found", func(t *testing.T) {
+		t.Parallel()
+
+		useCase, repo := initSOLTest(t, newFakeAMT(0))
+		repo.EXPECT().GetByID(gomock.Any(), "missing", "").Return(nil, nil)
+
+		_, err := useCase.OpenSOL(context.Background(), "missing")
+		require.ErrorIs(t, err, devices.ErrNotFound)
+	})
+}
@@ -0,0 +1,234 @@
+package devices
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/tenancy"
+)
+
+// SOL capture states.
+const (
+	SOLCaptureRunning   = "running"
+	SOLCaptureCompleted = "completed"
+	SOLCaptureFailed    = "failed"
+
+	// solCaptureMaxBytes bounds the text kept per capture; the oldest output
+	// is dropped first since the end of a failing boot is what matters.
+	solCaptureMaxBytes = 256 * 1024
+	// solCaptureRetention is how many captures are kept per device.
+	solCaptureRetention = 10
+	solCaptureReadSize  = 4096
+)
+
+// solCapture accumulates one capture's output. Captures live in memory only.
+type solCapture struct {
+	mu      sync.Mutex
+	result  dto.SOLCapture
+	text    []byte
+	pattern []byte
+}
+
+// StartSOLCapture opens a headless SOL session, optionally sends a power
+// action so the boot is captured from the start, and records serial output in
+// the background until the window elapses or the pattern appears.
+func (uc *UseCase) StartSOLCapture(c context.Context, guid string, req dto.SOLCaptureRequest) (dto.SOLCapture, error) {
+	// The capture outlives the request that started it.
+	session, err := uc.OpenSOL(context.WithoutCancel(c), guid)
+	if err != nil {
+		return dto.SOLCapture{}, err
+	}
+
+	if req.PowerAction != 0 {
+		if _, err := uc.SendPowerAction(c, guid, req.PowerAction); err != nil {
+			_ = session.Close()
+
+			return dto.SOLCapture{}, err
+		}
+	}
+
+	capture := &solCapture{
+		result: dto.SOLCapture{
+			ID:          uuid.NewString(),
+			GUID:        guid,
+			Status:      SOLCaptureRunning,
+			Duration:    req.Duration,
+			PowerAction: req.PowerAction,
+			Pattern:     req.Pattern,
+			StartedAt:   time.Now(),
+		},
+		pattern: []byte(req.Pattern),
+	}
+
+	uc.storeSOLCapture(capture)
+
+	go uc.runSOLCapture(session, capture, time.Duration(req.Duration)*time.Second)
+
+	return capture.snapshot(), nil
+}
+
+// GetSOLCaptures lists the captures kept for a device, newest first.
+func (uc *UseCase) GetSOLCaptures(c context.Context, guid string) ([]dto.SOLCapture, error) {
+	if err := uc.requireDevice(c, guid); err != nil {
+		return nil, err
+	}
+
+	uc.captureMutex.Lock()
+	captures := uc.captures[guid]
+	uc.captureMutex.Unlock()
+
+	results := make([]dto.SOLCapture, 0, len(captures))
+	for i := len(captures) - 1; i >= 0; i-- {
+		results = append(results, captures[i].snapshot())
+	}
+
+	return results, nil
+}
+
+// GetSOLCapture returns one capture, running or finished.
+func (uc *UseCase) GetSOLCapture(c context.Context, guid, id string) (dto.SOLCapture, error) {
+	if err := uc.requireDevice(c, guid); err != nil {
+		return dto.SOLCapture{}, err
+	}
+
+	uc.captureMutex.Lock()
+	defer uc.captureMutex.Unlock()
+
+	for _, capture := range uc.captures[guid] {
+		if capture.result.ID == id {
+			return capture.snapshot(), nil
+		}
+	}
+
+	return dto.SOLCapture{}, ErrNotFound.WrapWithMessage("GetSOLCapture", "uc.captures", "SOL capture not found")
+}
+
+func (uc *UseCase) requireDevice(c context.Context, guid string) error {
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
+	if err != nil {
+		return err
+	}
+
+	if item == nil || item.GUID == "" {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// storeSOLCapture adds capture, evicting the device's oldest finished
+// captures beyond the retention limit.
+func (uc *UseCase) storeSOLCapture(capture *solCapture) {
+	uc.captureMutex.Lock()
+	defer uc.captureMutex.Unlock()
+
+	guid := capture.result.GUID
+	captures := append(uc.captures[guid], capture)
+
+	for i := 0; len(captures) > solCaptureRetention && i < len(captures); {
+		if captures[i].snapshot().Status == SOLCaptureRunning {
+			i++
+
+			continue
+		}
+
+		captures = append(captures[:i], captures[i+1:]...)
+	}
+
+	uc.captures[guid] = captures
+}
+
+func (uc *UseCase) runSOLCapture(session io.ReadCloser, capture *solCapture, window time.Duration) {
+	var expired atomic.Bool
+
+	timer := time.AfterFunc(window, func() {
+		expired.Store(true)
+
+		_ = session.Close()
+	})
+	defer timer.Stop()
+
+	buf := make([]byte, solCaptureReadSize)
+
+	var err error
+
+	for {
+		var n int
+
+		n, err = session.Read(buf)
+		if n > 0 && capture.append(buf[:n]) {
+			err = nil
+
+			break
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	_ = session.Close()
+
+	if expired.Load() || errors.Is(err, io.EOF) {
+		err = nil
+	}
+
+	capture.finish(err)
+
+	uc.log.Info("SOL capture " + capture.result.ID + " for " + capture.result.GUID + " finished: " + capture.snapshot().Status)
+}
+
+// append records output and reports whether the pattern has now appeared.
+// Only the new bytes, plus enough overlap for a match split across reads,
+// are searched.
+func (c *solCapture) append(data []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	from := max(0, len(c.text)-len(c.pattern)+1)
+	c.text = append(c.text, data...)
+
+	matched := len(c.pattern) > 0 && bytes.Contains(c.text[from:], c.pattern)
+	if matched {
+		c.result.Matched = true
+	}
+
+	if over := len(c.text) - solCaptureMaxBytes; over > 0 {
+		c.text = c.text[over:]
+		c.result.Truncated = true
+	}
+
+	return matched
+}
+
+func (c *solCapture) finish(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.result.EndedAt = &now
+	c.result.Status = SOLCaptureCompleted
+
+	if err != nil {
+		c.result.Status = SOLCaptureFailed
+		c.result.Error = err.Error()
+	}
+}
+
+func (c *solCapture) snapshot() dto.SOLCapture {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := c.result
+	result.Text = string(c.text)
+
+	return result
+}
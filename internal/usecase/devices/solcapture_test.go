@@ -0,0 +1,121 @@
+package devices_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman/cim/power"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	devices "github.com/device-management-toolkit/console/internal/usecase/devices"
+)
+
+// masterBusReset is the CIM power management "Master Bus Reset" action.
+const masterBusReset = 10
+
+var errPowerAction = errors.New("power action failed")
+
+func waitForSOLCapture(t *testing.T, useCase *devices.UseCase, guid, id string) dto.SOLCapture {
+	t.Helper()
+
+	var capture dto.SOLCapture
+
+	require.Eventually(t, func() bool {
+		var err error
+
+		capture, err = useCase.GetSOLCapture(context.Background(), guid, id)
+		require.NoError(t, err)
+
+		return capture.Status != devices.SOLCaptureRunning
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return capture
+}
+
+func TestSOLCapture(t *testing.T) {
+	t.Parallel()
+
+	device := &entity.Device{GUID: "sol-guid", Username: "admin", Password: "encrypted"}
+
+	t.Run("stops when the pattern appears", func(t *testing.T) {
+		t.Parallel()
+
+		useCase, repo, management := initHeadlessTest(t, newFakeAMT(devices.StartRedirectionSessionReplyStatusSuccess))
+		repo.EXPECT().GetByID(gomock.Any(), device.GUID, "").Return(device, nil).AnyTimes()
+		management.EXPECT().SendPowerAction(masterBusReset).Return(power.PowerActionResponse{}, nil)
+
+		started, err := useCase.StartSOLCapture(context.Background(), device.GUID, dto.SOLCaptureRequest{
+			Duration:    60,
+			PowerAction: masterBusReset,
+			Pattern:     "login:",
+		})
+		require.NoError(t, err)
+		require.Equal(t, devices.SOLCaptureRunning, started.Status)
+
+		capture := waitForSOLCapture(t, useCase, device.GUID, started.ID)
+		require.Equal(t, devices.SOLCaptureCompleted, capture.Status)
+		require.True(t, capture.Matched)
+		require.Equal(t, "login: ", capture.Text)
+		require.NotNil(t, capture.EndedAt)
+
+		captures, err := useCase.GetSOLCaptures(context.Background(), device.GUID)
+		require.NoError(t, err)
+		require.Len(t, captures, 1)
+		require.Equal(t, started.ID, captures[0].ID)
+	})
+
+	t.Run("records until the window elapses", func(t *testing.T) {
+		t.Parallel()
+
+		useCase, repo, _ := initHeadlessTest(t, newFakeAMT(devices.StartRedirectionSessionReplyStatusSuccess))
+		repo.EXPECT().GetByID(gomock.Any(), device.GUID, "").Return(device, nil).AnyTimes()
+
+		started, err := useCase.StartSOLCapture(context.Background(), device.GUID, dto.SOLCaptureRequest{
+			Duration: 1,
+			Pattern:  "panic",
+		})
+		require.NoError(t, err)
+
+		capture := waitForSOLCapture(t, useCase, device.GUID, started.ID)
+		require.Equal(t, devices.SOLCaptureCompleted, capture.Status)
+		require.False(t, capture.Matched)
+		require.Equal(t, "login: ", capture.Text)
+	})
+
+	t.Run("power action failure ends the session", func(t *testing.T) {
+		t.Parallel()
+
+		useCase, repo, management := initHeadlessTest(t, newFakeAMT(devices.StartRedirectionSessionReplyStatusSuccess))
+		repo.EXPECT().GetByID(gomock.Any(), device.GUID, "").Return(device, nil).AnyTimes()
+		management.EXPECT().SendPowerAction(masterBusReset).Return(power.PowerActionResponse{}, errPowerAction)
+
+		_, err := useCase.StartSOLCapture(context.Background(), device.GUID, dto.SOLCaptureRequest{
+			Duration:    60,
+			PowerAction: masterBusReset,
+		})
+		require.ErrorIs(t, err, errPowerAction)
+
+		sessions, err := useCase.GetRedirectionSessions(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+	})
+
+	t.Run("unknown capture", func(t *testing.T) {
+		t.Parallel()
+
+		useCase, repo, _ := initHeadlessTest(t, newFakeAMT(0))
+		repo.EXPECT().GetByID(gomock.Any(), device.GUID, "").Return(device, nil)
+
+		var notFound repoerrors.NotFoundError
+
+		_, err := useCase.GetSOLCapture(context.Background(), device.GUID, "missing")
+		require.ErrorAs(t, err, &notFound)
+	})
+}
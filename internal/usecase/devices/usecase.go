@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+	"golang.org/x/time/rate"
 
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
@@ -46,7 +47,14 @@ type UseCase struct {
 	device           WSMAN
 	redirection      Redirection
 	redirConnections map[string]*DeviceConnection
-	redirMutex       sync.RWMutex // Protects redirConnections map
+	redirMutex       sync.RWMutex // Protects redirConnections and sessions maps
+	sessions         map[string]*DeviceConnection
+	policies         SessionPolicies
+	bandwidth        *rate.Limiter
+	policyMutex      sync.RWMutex // Protects policies and bandwidth
+	captures         map[string][]*solCapture
+	captureMutex     sync.Mutex
+	audit            AuditRecorder
 	log              logger.Interface
 	safeRequirements security.Cryptor
 }
@@ -56,10 +64,12 @@ var ErrAMT = AMTError{Console: consoleerrors.CreateConsoleError("DevicesUseCase"
 // New -.
 func New(r Repository, d WSMAN, redirection Redirection, log logger.Interface, safeRequirements security.Cryptor) *UseCase {
 	uc := &UseCase{
-		repo:             r,
+		repo:             NewScopedRepository(r),
 		device:           d,
 		redirection:      redirection,
 		redirConnections: make(map[string]*DeviceConnection),
+		sessions:         make(map[string]*DeviceConnection),
+		captures:         make(map[string][]*solCapture),
 		log:              log,
 		safeRequirements: safeRequirements,
 	}
@@ -303,6 +313,7 @@ func (uc *UseCase) entityToDTO(d *entity.Device) (*dto.Device, error) {
 		Username:         d.Username,
 		UseTLS:           d.UseTLS,
 		AllowSelfSigned:  d.AllowSelfSigned,
+		Version:          d.Version,
 	}
 
 	if d.CertHash != nil {
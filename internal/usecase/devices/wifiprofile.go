@@ -15,6 +15,7 @@ import (
 
 	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
 	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/tenancy"
 	"github.com/device-management-toolkit/console/internal/usecase/devices/wsman"
 )
 
@@ -182,7 +183,7 @@ func (uc *UseCase) UpdateWirelessProfile(c context.Context, guid string, profile
 }
 
 func (uc *UseCase) setupWirelessProfileManagement(c context.Context, guid string) (wsman.Management, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/wsman/cim/wifi"
+
+	"github.com/device-management-toolkit/console/internal/tenancy"
 )
 
 func (uc *UseCase) RequestWirelessStateChange(c context.Context, guid string, requestedState wifi.RequestedState) (wifi.RequestedState, error) {
@@ -11,7 +13,7 @@ func (uc *UseCase) RequestWirelessStateChange(c context.Context, guid string, re
 		return 0, ErrValidationUseCase.Wrap("RequestWirelessStateChange", "validate requested state", "state must be one of 3, 32768, 32769")
 	}
 
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return 0, err
 	}
@@ -51,7 +53,7 @@ func isWirelessRequestedStateSupported(requestedState wifi.RequestedState) bool
 }
 
 func (uc *UseCase) GetWirelessState(c context.Context, guid string) (wifi.EnabledState, error) {
-	item, err := uc.repo.GetByID(c, guid, "")
+	item, err := uc.repo.GetByID(c, guid, tenancy.FromContext(c))
 	if err != nil {
 		return 0, err
 	}
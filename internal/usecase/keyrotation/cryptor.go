@@ -0,0 +1,38 @@
+package keyrotation
+
+import "github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+
+// Cryptor encrypts with the current key and decrypts with it or, while a
+// rotation is in progress, with the previous key.
+type Cryptor struct {
+	security.Crypto
+	Previous security.Crypto
+}
+
+// NewCryptor returns the cryptor for the configured keys: a plain one unless
+// a previous key is set.
+func NewCryptor(key, previousKey string) security.Cryptor {
+	if previousKey == "" {
+		return security.Crypto{EncryptionKey: key}
+	}
+
+	return Cryptor{
+		Crypto:   security.Crypto{EncryptionKey: key},
+		Previous: security.Crypto{EncryptionKey: previousKey},
+	}
+}
+
+// Decrypt -.
+func (c Cryptor) Decrypt(cipherText string) (string, error) {
+	plainText, err := c.Crypto.Decrypt(cipherText)
+	if err == nil {
+		return plainText, nil
+	}
+
+	// AES-GCM authenticates, so the wrong key fails rather than garbles.
+	if plainText, prevErr := c.Previous.Decrypt(cipherText); prevErr == nil {
+		return plainText, nil
+	}
+
+	return "", err
+}
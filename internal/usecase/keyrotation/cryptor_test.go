@@ -0,0 +1,40 @@
+package keyrotation_test
+
+import (
+	"testing"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/usecase/keyrotation"
+)
+
+func TestCryptor(t *testing.T) {
+	t.Parallel()
+
+	previous, current := security.Crypto{}.GenerateKey(), security.Crypto{}.GenerateKey()
+
+	require.Equal(t, security.Crypto{EncryptionKey: current}, keyrotation.NewCryptor(current, ""))
+
+	c := keyrotation.NewCryptor(current, previous)
+
+	old, err := security.Crypto{EncryptionKey: previous}.Encrypt("old secret")
+	require.NoError(t, err)
+
+	plainText, err := c.Decrypt(old)
+	require.NoError(t, err)
+	require.Equal(t, "old secret", plainText)
+
+	sealed, err := c.Encrypt("new secret")
+	require.NoError(t, err)
+
+	plainText, err = security.Crypto{EncryptionKey: current}.Decrypt(sealed)
+	require.NoError(t, err, "encrypts with the current key")
+	require.Equal(t, "new secret", plainText)
+
+	other, err := security.Crypto{EncryptionKey: security.Crypto{}.GenerateKey()}.Encrypt("other")
+	require.NoError(t, err)
+
+	_, err = c.Decrypt(other)
+	require.Error(t, err)
+}
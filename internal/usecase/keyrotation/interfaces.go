@@ -0,0 +1,24 @@
+package keyrotation
+
+import (
+	"github.com/device-management-toolkit/console/internal/usecase/ciraconfigs"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/internal/usecase/domains"
+	"github.com/device-management-toolkit/console/internal/usecase/profiles"
+	"github.com/device-management-toolkit/console/internal/usecase/tenants"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/internal/usecase/wificonfigs"
+)
+
+// Repositories hold the entities with encrypted secrets, and name the
+// tenants that own them. Trash, if set, is searched for entities the
+// rotation cannot reach.
+type Repositories struct {
+	CIRAConfigs     ciraconfigs.Repository
+	WirelessConfigs wificonfigs.Repository
+	Domains         domains.Repository
+	Profiles        profiles.Repository
+	Devices         devices.Repository
+	Tenants         tenants.Repository
+	Trash           trash.Repository
+}
@@ -0,0 +1,379 @@
+// Package keyrotation re-encrypts the secrets the console stores, such as
+// device, profile and CIRA passwords, WiFi PSKs and domain certificate
+// passwords, when the encryption key changes.
+//
+// While a rotation runs the consoles decrypt with the new key or, failing
+// that, the previous one, so secrets not yet rotated stay readable. Each
+// secret that still decrypts only with the previous key is re-encrypted and
+// written back; one that already decrypts with the new key is left alone, so
+// an interrupted rotation resumes by running it again.
+package keyrotation
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/credentials"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// Kinds of entity with encrypted secrets.
+const (
+	KindCIRAConfigs     = "ciraconfigs"
+	KindWirelessConfigs = "wirelessconfigs"
+	KindDomains         = "domains"
+	KindProfiles        = "profiles"
+	KindDevices         = "devices"
+)
+
+const (
+	// defaultTenant is the tenant of single-tenant deployments, always rotated.
+	defaultTenant = ""
+	// DefaultBatchSize is how many entities are read per query by default.
+	DefaultBatchSize = 100
+)
+
+var (
+	ErrKeyRotationUseCase = consoleerrors.CreateConsoleError("KeyRotationUseCase")
+	ErrDatabase           = repoerrors.DatabaseError{Console: ErrKeyRotationUseCase}
+
+	errSameKey = errors.New("new key is the previous key")
+)
+
+type (
+	// Options of a rotation. Tenants are rotated besides the default tenant
+	// and every tenant that owns entities.
+	Options struct {
+		Tenants   []string
+		BatchSize int
+	}
+
+	// Report tells what a rotation did. It is Complete when a last pass found
+	// no secret left under the previous key, which can then be dropped.
+	// Trashed counts the entities in the trash, which are not rotated; while
+	// there are any the rotation is not complete either.
+	Report struct {
+		Tenants  []string
+		Kinds    []KindReport
+		Trashed  int
+		Complete bool
+	}
+
+	// KindReport counts the entities of one kind by what happened to their
+	// secrets. Unreadable ones decrypt with neither key and are left as they
+	// are.
+	KindReport struct {
+		Kind       string
+		Rotated    int
+		Current    int
+		Unreadable int
+	}
+)
+
+// outcome of rotating the secrets of one entity.
+type outcome int
+
+const (
+	outcomeCurrent outcome = iota
+	outcomeRotated
+	outcomeUnreadable
+)
+
+// UseCase rotates the secrets in the repositories from the previous key to
+// the new one.
+type UseCase struct {
+	repos    Repositories
+	previous security.Crypto
+	current  security.Crypto
+	log      logger.Interface
+}
+
+// New -.
+func New(repos Repositories, previousKey, key string, log logger.Interface) *UseCase {
+	return &UseCase{
+		repos:    repos,
+		previous: security.Crypto{EncryptionKey: previousKey},
+		current:  security.Crypto{EncryptionKey: key},
+		log:      log,
+	}
+}
+
+// Rotate re-encrypts every secret still under the previous key. A second
+// pass picks up secrets written with the previous key while the first one
+// ran, by consoles not yet restarted with the new key; if it finds any the
+// rotation is not complete and should be run again.
+func (uc *UseCase) Rotate(ctx context.Context, opts Options) (*Report, error) {
+	if uc.previous.EncryptionKey == uc.current.EncryptionKey {
+		return nil, ErrKeyRotationUseCase.Wrap("Rotate", "compare keys", errSameKey)
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	tenants, err := uc.tenants(ctx, opts.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Tenants: tenants}
+
+	first, err := uc.pass(ctx, tenants, opts.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := uc.pass(ctx, tenants, opts.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Complete = true
+
+	for i := range first {
+		if last[i].Rotated > 0 {
+			report.Complete = false
+		}
+
+		first[i].Rotated += last[i].Rotated
+	}
+
+	report.Kinds = first
+
+	if report.Trashed, err = uc.trashed(ctx, tenants); err != nil {
+		return nil, err
+	}
+
+	if report.Trashed > 0 {
+		report.Complete = false
+	}
+
+	uc.log.Info("key rotation: %d tenants, complete: %t", len(tenants), report.Complete)
+
+	return report, nil
+}
+
+// trashed counts the entities with secrets in the trash of the tenants.
+func (uc *UseCase) trashed(ctx context.Context, tenants []string) (int, error) {
+	if uc.repos.Trash == nil {
+		return 0, nil
+	}
+
+	count := 0
+
+	for _, kind := range []string{KindCIRAConfigs, KindWirelessConfigs, KindDomains, KindProfiles, KindDevices} {
+		for _, tenantID := range tenants {
+			n, err := uc.repos.Trash.GetCount(ctx, kind, tenantID)
+			if err != nil {
+				return 0, ErrDatabase.Wrap("trashed", "uc.repos.Trash.GetCount", err)
+			}
+
+			count += n
+		}
+	}
+
+	return count, nil
+}
+
+// tenants lists the default tenant, the given ones and those that own
+// entities, in the trash or not.
+func (uc *UseCase) tenants(ctx context.Context, given []string) ([]string, error) {
+	stored, err := uc.repos.Tenants.Get(ctx)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("tenants", "uc.repos.Tenants.Get", err)
+	}
+
+	tenants := append([]string{defaultTenant}, given...)
+	tenants = append(tenants, stored...)
+
+	slices.Sort(tenants)
+
+	return slices.Compact(tenants), nil
+}
+
+// pass rotates every kind of entity once.
+func (uc *UseCase) pass(ctx context.Context, tenants []string, batchSize int) ([]KindReport, error) {
+	r := uc.repos
+
+	cira := kind[entity.CIRAConfig]{
+		name: KindCIRAConfigs,
+		get:  r.CIRAConfigs.Get,
+		secrets: func(c *entity.CIRAConfig) []*string {
+			return []*string{&c.Password}
+		},
+		update: r.CIRAConfigs.Update,
+	}
+
+	wireless := kind[entity.WirelessConfig]{
+		name: KindWirelessConfigs,
+		get:  r.WirelessConfigs.Get,
+		secrets: func(w *entity.WirelessConfig) []*string {
+			return []*string{&w.PSKPassphrase}
+		},
+		update: r.WirelessConfigs.Update,
+	}
+
+	domains := kind[entity.Domain]{
+		name: KindDomains,
+		get:  r.Domains.Get,
+		secrets: func(d *entity.Domain) []*string {
+			return []*string{&d.ProvisioningCertPassword}
+		},
+		update: r.Domains.Update,
+	}
+
+	// Listed profiles and devices lack some of their secrets.
+	profiles := kind[entity.Profile]{
+		name: KindProfiles,
+		get:  r.Profiles.Get,
+		load: func(ctx context.Context, p *entity.Profile) (*entity.Profile, error) {
+			return r.Profiles.GetByName(ctx, p.ProfileName, p.TenantID)
+		},
+		secrets: func(p *entity.Profile) []*string {
+			return []*string{&p.AMTPassword, &p.MEBXPassword}
+		},
+		update: r.Profiles.Update,
+	}
+
+	devices := kind[entity.Device]{
+		name: KindDevices,
+		get:  r.Devices.Get,
+		load: func(ctx context.Context, d *entity.Device) (*entity.Device, error) {
+			return r.Devices.GetByID(ctx, d.GUID, d.TenantID)
+		},
+		secrets: func(d *entity.Device) []*string {
+			return []*string{&d.Password, d.MPSPassword, d.MEBXPassword}
+		},
+		update: r.Devices.Update,
+	}
+
+	reports := make([]KindReport, 0, 5)
+
+	for _, rotate := range []func() (KindReport, error){
+		func() (KindReport, error) { return rotateKind(ctx, uc, &cira, tenants, batchSize) },
+		func() (KindReport, error) { return rotateKind(ctx, uc, &wireless, tenants, batchSize) },
+		func() (KindReport, error) { return rotateKind(ctx, uc, &domains, tenants, batchSize) },
+		func() (KindReport, error) { return rotateKind(ctx, uc, &profiles, tenants, batchSize) },
+		func() (KindReport, error) { return rotateKind(ctx, uc, &devices, tenants, batchSize) },
+	} {
+		report, err := rotate()
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// kind tells how to list, load and update one kind of entity and where its
+// secrets are.
+type kind[T any] struct {
+	name    string
+	get     func(ctx context.Context, top, skip int, tenantID string) ([]T, error)
+	load    func(ctx context.Context, listed *T) (*T, error)
+	secrets func(*T) []*string
+	update  func(ctx context.Context, e *T) (bool, error)
+}
+
+// rotateKind rotates the entities of one kind, tenant by tenant, a batch at
+// a time. Rotating does not change the order they are listed in.
+func rotateKind[T any](ctx context.Context, uc *UseCase, k *kind[T], tenants []string, batchSize int) (KindReport, error) {
+	report := KindReport{Kind: k.name}
+
+	for _, tenantID := range tenants {
+		for skip := 0; ; skip += batchSize {
+			page, err := k.get(ctx, batchSize, skip, tenantID)
+			if err != nil {
+				return report, ErrDatabase.Wrap("rotate", k.name+" get", err)
+			}
+
+			for i := range page {
+				e := &page[i]
+
+				if k.load != nil {
+					if e, err = k.load(ctx, e); err != nil {
+						return report, ErrDatabase.Wrap("rotate", k.name+" load", err)
+					}
+
+					if e == nil {
+						continue // deleted since listed
+					}
+				}
+
+				result, err := uc.rotate(k.secrets(e))
+				if err != nil {
+					return report, err
+				}
+
+				switch result {
+				case outcomeRotated:
+					if _, err := k.update(ctx, e); err != nil {
+						return report, ErrDatabase.Wrap("rotate", k.name+" update", err)
+					}
+
+					report.Rotated++
+				case outcomeUnreadable:
+					report.Unreadable++
+				case outcomeCurrent:
+					report.Current++
+				}
+			}
+
+			if len(page) < batchSize {
+				break
+			}
+
+			uc.log.Debug("key rotation: %s in tenant %q: %d rotated so far", k.name, tenantID, report.Rotated)
+		}
+	}
+
+	return report, nil
+}
+
+// rotate re-encrypts in place the secrets under the previous key. Empty and
+// missing secrets and references to the secret store are skipped. If any
+// secret decrypts with neither key the entity is left as it is.
+func (uc *UseCase) rotate(secrets []*string) (outcome, error) {
+	rotated := make([]string, len(secrets))
+	result := outcomeCurrent
+
+	for i, secret := range secrets {
+		// References point to the secret store, which keeps secrets in plain.
+		if secret == nil || *secret == "" || credentials.IsReference(*secret) {
+			continue
+		}
+
+		rotated[i] = *secret
+
+		if _, err := uc.current.Decrypt(*secret); err == nil {
+			continue
+		}
+
+		plainText, err := uc.previous.Decrypt(*secret)
+		if err != nil {
+			return outcomeUnreadable, nil //nolint:nilerr // unreadable secrets are counted, not fatal
+		}
+
+		if rotated[i], err = uc.current.Encrypt(plainText); err != nil {
+			return result, ErrKeyRotationUseCase.Wrap("rotate", "uc.current.Encrypt", err)
+		}
+
+		result = outcomeRotated
+	}
+
+	for i, secret := range secrets {
+		if secret != nil && *secret != "" {
+			*secret = rotated[i]
+		}
+	}
+
+	return result, nil
+}
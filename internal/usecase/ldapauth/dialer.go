@@ -0,0 +1,82 @@
+package ldapauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/device-management-toolkit/console/config"
+)
+
+var errNoCACerts = errors.New("no certificates found")
+
+type urlDialer struct {
+	cfg config.LDAP
+}
+
+// NewDialer dials cfg.URL, upgrading the connection with StartTLS when set.
+func NewDialer(cfg config.LDAP) Dialer {
+	return urlDialer{cfg: cfg}
+}
+
+func (d urlDialer) Dial(_ context.Context) (Conn, error) {
+	tlsConfig, err := d.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := ldap.DialURL(d.cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: d.cfg.Timeout}),
+		ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	if d.cfg.Timeout > 0 {
+		conn.SetTimeout(d.cfg.Timeout)
+	}
+
+	if d.cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+func (d urlDialer) tlsConfig() (*tls.Config, error) {
+	u, err := url.Parse(d.cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: d.cfg.TLSSkipVerify, //nolint:gosec // operator opted in via auth.ldap.tlsSkipVerify to trust a self-signed directory
+	}
+
+	if d.cfg.CACertFile != "" {
+		pem, err := os.ReadFile(d.cfg.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w in %s", errNoCACerts, d.cfg.CACertFile)
+		}
+	}
+
+	return tlsConfig, nil
+}
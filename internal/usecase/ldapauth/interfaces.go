@@ -0,0 +1,26 @@
+package ldapauth
+
+import (
+	"context"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+type (
+	// Conn is the part of an LDAP connection the use case needs.
+	Conn interface {
+		Bind(username, password string) error
+		Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error)
+		Close() error
+	}
+	// Dialer opens a connection to the directory, secured as configured.
+	Dialer interface {
+		Dial(ctx context.Context) (Conn, error)
+	}
+	Feature interface {
+		Authenticate(ctx context.Context, username, password string) (*dto.User, error)
+		Lookup(ctx context.Context, username string) (*dto.User, error)
+	}
+)
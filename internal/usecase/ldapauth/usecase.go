@@ -0,0 +1,237 @@
+// Package ldapauth authenticates logins against an LDAP directory, such as
+// Active Directory, and maps the user's groups to a role.
+package ldapauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/internal/tenancy"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// UseCase -.
+type UseCase struct {
+	cfg    config.LDAP
+	dialer Dialer
+	log    logger.Interface
+}
+
+var (
+	// ErrInvalidCredentials is returned for an unknown user or a wrong
+	// password, which the directory does not tell apart.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrNoRole is returned when none of the user's groups maps to a role and
+	// there is no default role.
+	ErrNoRole = errors.New("none of the user's directory groups grants a console role")
+)
+
+// New -.
+func New(cfg config.LDAP, d Dialer, log logger.Interface) *UseCase {
+	return &UseCase{
+		cfg:    cfg,
+		dialer: d,
+		log:    log,
+	}
+}
+
+// Authenticate finds the user and binds as them with password. The user acts
+// in the default tenant.
+func (uc *UseCase) Authenticate(ctx context.Context, username, password string) (*dto.User, error) {
+	// An empty password would make an unauthenticated bind, which many
+	// directories accept for any DN.
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := uc.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	entry, err := uc.find(conn, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+
+		return nil, fmt.Errorf("ldapauth - Authenticate - conn.Bind: %w", err)
+	}
+
+	// Group searches run as the service account, which the user may not
+	// match in rights.
+	if uc.cfg.GroupBaseDN != "" && uc.cfg.BindDN != "" {
+		if err := conn.Bind(uc.cfg.BindDN, uc.cfg.BindPassword); err != nil {
+			return nil, fmt.Errorf("ldapauth - Authenticate - conn.Bind: %w", err)
+		}
+	}
+
+	return uc.user(conn, username, entry)
+}
+
+// Lookup returns a user's current role without their password, so a session
+// can be renewed. It fails with ErrInvalidCredentials once the user is gone.
+func (uc *UseCase) Lookup(ctx context.Context, username string) (*dto.User, error) {
+	conn, err := uc.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	entry, err := uc.find(conn, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.user(conn, username, entry)
+}
+
+// connect dials the directory and binds as the service account, if any.
+func (uc *UseCase) connect(ctx context.Context) (Conn, error) {
+	conn, err := uc.dialer.Dial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ldapauth - connect - uc.dialer.Dial: %w", err)
+	}
+
+	if uc.cfg.BindDN != "" {
+		if err := conn.Bind(uc.cfg.BindDN, uc.cfg.BindPassword); err != nil {
+			conn.Close()
+
+			return nil, fmt.Errorf("ldapauth - connect - conn.Bind: %w", err)
+		}
+	}
+
+	return conn, nil
+}
+
+// find returns the one entry userFilter matches for username.
+func (uc *UseCase) find(conn Conn, username string) (*ldap.Entry, error) {
+	attributes := []string{"dn"}
+	if uc.cfg.UsernameAttribute != "" {
+		attributes = append(attributes, uc.cfg.UsernameAttribute)
+	}
+
+	if uc.cfg.GroupAttribute != "" {
+		attributes = append(attributes, uc.cfg.GroupAttribute)
+	}
+
+	// A size limit of 2 tells one match from several without reading them all.
+	result, err := conn.Search(ldap.NewSearchRequest(
+		uc.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		fmt.Sprintf(uc.cfg.UserFilter, ldap.EscapeFilter(username)),
+		attributes, nil,
+	))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("ldapauth - find - conn.Search: %w", err)
+	}
+
+	// Several matches make the filter ambiguous; refuse rather than pick one.
+	if err != nil || len(result.Entries) != 1 {
+		if err != nil || len(result.Entries) > 1 {
+			uc.log.Warn("ldapauth - find - userFilter matches several entries for " + username)
+		}
+
+		return nil, ErrInvalidCredentials
+	}
+
+	return result.Entries[0], nil
+}
+
+func (uc *UseCase) user(conn Conn, username string, entry *ldap.Entry) (*dto.User, error) {
+	groups := entry.GetEqualFoldAttributeValues(uc.cfg.GroupAttribute)
+
+	if uc.cfg.GroupBaseDN != "" {
+		result, err := conn.Search(ldap.NewSearchRequest(
+			uc.cfg.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+			fmt.Sprintf(uc.cfg.GroupFilter, ldap.EscapeFilter(entry.DN)),
+			[]string{"dn"}, nil,
+		))
+		if err != nil {
+			return nil, fmt.Errorf("ldapauth - user - conn.Search: %w", err)
+		}
+
+		for _, group := range result.Entries {
+			groups = append(groups, group.DN)
+		}
+	}
+
+	role, ok := uc.role(groups)
+	if !ok {
+		return nil, ErrNoRole
+	}
+
+	return &dto.User{
+		Username: uc.canonical(username, entry),
+		Role:     string(role),
+		TenantID: tenancy.Default,
+	}, nil
+}
+
+// canonical returns the name the directory knows the user by, in lower case,
+// so that access policies and lockouts see one user whatever case or alias
+// they log in with. Without the username attribute, that is the name given.
+func (uc *UseCase) canonical(username string, entry *ldap.Entry) string {
+	if uc.cfg.UsernameAttribute != "" {
+		if name := entry.GetEqualFoldAttributeValue(uc.cfg.UsernameAttribute); name != "" {
+			username = name
+		}
+	}
+
+	return strings.ToLower(username)
+}
+
+// role returns the most privileged role the groups map to, or the default
+// role.
+func (uc *UseCase) role(groups []string) (rbac.Role, bool) {
+	granted := map[rbac.Role]bool{}
+
+	for _, group := range groups {
+		for key, name := range uc.cfg.GroupRoles {
+			if role, ok := rbac.ParseRole(name); ok && groupMatches(key, group) {
+				granted[role] = true
+			}
+		}
+	}
+
+	for _, role := range rbac.Roles {
+		if granted[role] {
+			return role, true
+		}
+	}
+
+	return rbac.ParseRole(uc.cfg.DefaultRole)
+}
+
+// groupMatches compares key, a group DN or CN, with a group's DN.
+func groupMatches(key, group string) bool {
+	dn, err := ldap.ParseDN(group)
+	if err != nil || len(dn.RDNs) == 0 {
+		return strings.EqualFold(key, group)
+	}
+
+	if strings.Contains(key, "=") {
+		keyDN, err := ldap.ParseDN(key)
+
+		return err == nil && keyDN.EqualFold(dn)
+	}
+
+	for _, attr := range dn.RDNs[0].Attributes {
+		if strings.EqualFold(attr.Type, "cn") && strings.EqualFold(attr.Value, key) {
+			return true
+		}
+	}
+
+	return false
+}
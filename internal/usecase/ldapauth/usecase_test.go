@@ -0,0 +1,231 @@
+package ldapauth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/ldapauth"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+const userDN = "uid=jdoe,ou=users,dc=example,dc=org"
+
+func testConfig() config.LDAP {
+	return config.LDAP{
+		URL:               "ldaps://ldap.example.org",
+		BindDN:            "cn=console,dc=example,dc=org",
+		BindPassword:      "service",
+		BaseDN:            "dc=example,dc=org",
+		UserFilter:        "(uid=%s)",
+		UsernameAttribute: "uid",
+		GroupAttribute:    "memberOf",
+		GroupRoles: map[string]string{
+			"cn=Console-Admins,ou=groups,dc=example,dc=org": "admin",
+			"it-helpdesk": "helpdesk",
+		},
+	}
+}
+
+func ldapTest(t *testing.T, cfg config.LDAP) (*ldapauth.UseCase, *mocks.MockLDAPConn) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	conn := mocks.NewMockLDAPConn(mockCtl)
+	dialer := mocks.NewMockLDAPDialer(mockCtl)
+	dialer.EXPECT().Dial(gomock.Any()).Return(conn, nil).AnyTimes()
+	conn.EXPECT().Close().Return(nil).AnyTimes()
+
+	return ldapauth.New(cfg, dialer, logger.New("error")), conn
+}
+
+func userEntry(groups ...string) *ldap.SearchResult {
+	return &ldap.SearchResult{Entries: []*ldap.Entry{
+		ldap.NewEntry(userDN, map[string][]string{"uid": {"jdoe"}, "memberOf": groups}),
+	}}
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		groups []string
+		role   string
+		err    error
+	}{
+		{
+			name:   "most privileged mapped group wins",
+			groups: []string{"cn=it-helpdesk,ou=groups,dc=example,dc=org", "CN=console-admins,OU=groups,DC=example,DC=org"},
+			role:   "admin",
+		},
+		{
+			name:   "group matched by CN",
+			groups: []string{"cn=IT-Helpdesk,ou=site-a,dc=example,dc=org"},
+			role:   "helpdesk",
+		},
+		{
+			name:   "no mapped group and no default role",
+			groups: []string{"cn=staff,ou=groups,dc=example,dc=org"},
+			err:    ldapauth.ErrNoRole,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, conn := ldapTest(t, testConfig())
+
+			gomock.InOrder(
+				conn.EXPECT().Bind("cn=console,dc=example,dc=org", "service").Return(nil),
+				conn.EXPECT().Search(gomock.Any()).DoAndReturn(func(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+					require.Equal(t, "dc=example,dc=org", req.BaseDN)
+					require.Equal(t, "(uid=jdoe)", req.Filter)
+
+					return userEntry(tc.groups...), nil
+				}),
+				conn.EXPECT().Bind(userDN, "secret").Return(nil),
+			)
+
+			user, err := useCase.Authenticate(context.Background(), "jdoe", "secret")
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, &dto.User{Username: "jdoe", Role: tc.role}, user)
+		})
+	}
+}
+
+func TestAuthenticateCanonicalUsername(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		attribute string
+		username  string
+		want      string
+	}{
+		{name: "from the username attribute", attribute: "uid", username: "JDoe@Example.org", want: "jdoe"},
+		{name: "lower case without it", username: "JDoe", want: "jdoe"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := testConfig()
+			cfg.UsernameAttribute = tc.attribute
+
+			useCase, conn := ldapTest(t, cfg)
+
+			conn.EXPECT().Bind("cn=console,dc=example,dc=org", "service").Return(nil)
+			conn.EXPECT().Search(gomock.Any()).Return(userEntry("cn=it-helpdesk,ou=groups,dc=example,dc=org"), nil)
+			conn.EXPECT().Bind(userDN, "secret").Return(nil)
+
+			user, err := useCase.Authenticate(context.Background(), tc.username, "secret")
+			require.NoError(t, err)
+			require.Equal(t, tc.want, user.Username)
+		})
+	}
+}
+
+func TestAuthenticateRejected(t *testing.T) {
+	t.Parallel()
+
+	useCase, conn := ldapTest(t, testConfig())
+
+	conn.EXPECT().Bind("cn=console,dc=example,dc=org", "service").Return(nil).Times(3)
+
+	// Wrong password.
+	conn.EXPECT().Search(gomock.Any()).Return(userEntry(), nil)
+	conn.EXPECT().Bind(userDN, "wrong").Return(ldap.NewError(ldap.LDAPResultInvalidCredentials, nil))
+
+	_, err := useCase.Authenticate(context.Background(), "jdoe", "wrong")
+	require.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+
+	// Unknown user.
+	conn.EXPECT().Search(gomock.Any()).Return(&ldap.SearchResult{}, nil)
+
+	_, err = useCase.Authenticate(context.Background(), "nobody", "secret")
+	require.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+
+	// A filter matching several entries is refused.
+	conn.EXPECT().Search(gomock.Any()).Return(&ldap.SearchResult{}, ldap.NewError(ldap.LDAPResultSizeLimitExceeded, nil))
+
+	_, err = useCase.Authenticate(context.Background(), "j*", "secret")
+	require.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+
+	// An empty password would be an unauthenticated bind; the directory is
+	// not asked.
+	_, err = useCase.Authenticate(context.Background(), "jdoe", "")
+	require.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+}
+
+func TestAuthenticateEscapesUsername(t *testing.T) {
+	t.Parallel()
+
+	useCase, conn := ldapTest(t, testConfig())
+
+	conn.EXPECT().Bind(gomock.Any(), gomock.Any()).Return(nil)
+	conn.EXPECT().Search(gomock.Any()).DoAndReturn(func(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+		require.Equal(t, `(uid=\2a\29\28uid=\2a)`, req.Filter)
+
+		return &ldap.SearchResult{}, nil
+	})
+
+	_, err := useCase.Authenticate(context.Background(), "*)(uid=*", "secret")
+	require.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+}
+
+func TestLookupGroupSearch(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig()
+	cfg.GroupAttribute = ""
+	cfg.GroupBaseDN = "ou=groups,dc=example,dc=org"
+	cfg.GroupFilter = "(member=%s)"
+	cfg.DefaultRole = "read-only"
+
+	useCase, conn := ldapTest(t, cfg)
+
+	serviceBind := func() *gomock.Call {
+		return conn.EXPECT().Bind("cn=console,dc=example,dc=org", "service").Return(nil)
+	}
+	userSearch := func() *gomock.Call {
+		return conn.EXPECT().Search(gomock.Any()).Return(userEntry(), nil)
+	}
+
+	gomock.InOrder(
+		serviceBind(),
+		userSearch(),
+		conn.EXPECT().Search(gomock.Any()).DoAndReturn(func(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			require.Equal(t, "ou=groups,dc=example,dc=org", req.BaseDN)
+			require.Equal(t, "(member="+userDN+")", req.Filter)
+
+			return &ldap.SearchResult{Entries: []*ldap.Entry{ldap.NewEntry("cn=it-helpdesk,ou=groups,dc=example,dc=org", nil)}}, nil
+		}),
+		serviceBind(),
+		userSearch(),
+		conn.EXPECT().Search(gomock.Any()).Return(&ldap.SearchResult{}, nil),
+	)
+
+	user, err := useCase.Lookup(context.Background(), "jdoe")
+	require.NoError(t, err)
+	require.Equal(t, "helpdesk", user.Role)
+
+	user, err = useCase.Lookup(context.Background(), "jdoe")
+	require.NoError(t, err)
+	require.Equal(t, "read-only", user.Role, "users in no mapped group get the default role")
+}
@@ -0,0 +1,18 @@
+package lockouts
+
+import (
+	"context"
+	"time"
+
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+type Feature interface {
+	// Check returns how long a login for username from ip must wait, zero
+	// when it may go ahead.
+	Check(ctx context.Context, username, ip string) time.Duration
+	Failed(ctx context.Context, username, ip string)
+	Succeeded(ctx context.Context, username string)
+	Get(ctx context.Context) ([]dto.Lockout, error)
+	Clear(ctx context.Context, kind, key string) error
+}
@@ -0,0 +1,34 @@
+package lockouts
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// metricLabelKind tells username records apart from client IP records.
+const metricLabelKind = "kind"
+
+var (
+	loginFailures = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "login_failures_total",
+			Help: "Logins refused for invalid credentials",
+		},
+	)
+
+	loginLockouts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "login_lockouts_total",
+			Help: "Usernames or client IPs locked out after too many failed logins (per kind)",
+		},
+		[]string{metricLabelKind},
+	)
+
+	loginThrottled = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "login_throttled_total",
+			Help: "Login attempts refused while a username or client IP had to wait (per kind)",
+		},
+		[]string{metricLabelKind},
+	)
+)
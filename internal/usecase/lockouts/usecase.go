@@ -0,0 +1,305 @@
+package lockouts
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// Kinds of failed-login records.
+const (
+	KindUsername = "username"
+	KindIP       = "ip"
+)
+
+const (
+	// pruneInterval limits clean-up of forgotten records to one pass a minute.
+	pruneInterval = time.Minute
+	// maxRecords bounds the records held, whatever the rate of distinct
+	// usernames and IPs failing.
+	maxRecords = 100_000
+)
+
+var (
+	ErrLockoutsUseCase = consoleerrors.CreateConsoleError("LockoutsUseCase")
+	ErrNotFound        = repoerrors.NotFoundError{Console: ErrLockoutsUseCase}
+)
+
+type key struct {
+	kind  string
+	value string
+}
+
+type record struct {
+	failures int
+	last     time.Time
+}
+
+// UseCase counts failed logins in memory. Records are lost on restart, which
+// is no worse than the lockout ending on its own after Duration.
+type UseCase struct {
+	log        logger.Interface
+	policy     config.LoginLockout
+	now        func() time.Time
+	maxRecords int
+
+	mu        sync.Mutex
+	records   map[key]*record
+	lastPrune time.Time
+}
+
+// New -.
+func New(log logger.Interface, policy config.LoginLockout) *UseCase {
+	return &UseCase{
+		log:        log,
+		policy:     policy,
+		now:        time.Now,
+		maxRecords: maxRecords,
+		records:    map[key]*record{},
+	}
+}
+
+// Check returns how long a login for username from ip must wait, zero when
+// it may go ahead.
+func (uc *UseCase) Check(_ context.Context, username, ip string) time.Duration {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	now := uc.now()
+
+	var wait time.Duration
+
+	for _, k := range uc.keys(username, ip) {
+		r := uc.current(k, now)
+		if r == nil {
+			continue
+		}
+
+		until, _ := uc.blockedUntil(k.kind, r)
+		if d := until.Sub(now); d > 0 {
+			loginThrottled.WithLabelValues(k.kind).Inc()
+
+			wait = max(wait, d)
+		}
+	}
+
+	return wait
+}
+
+// Failed records a login refused for invalid credentials.
+func (uc *UseCase) Failed(_ context.Context, username, ip string) {
+	loginFailures.Inc()
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	now := uc.now()
+
+	uc.prune(now)
+
+	for _, k := range uc.keys(username, ip) {
+		r := uc.current(k, now)
+		if r == nil {
+			if len(uc.records) >= uc.maxRecords {
+				uc.evict(now)
+			}
+
+			r = &record{}
+			uc.records[k] = r
+		}
+
+		r.failures++
+		r.last = now
+
+		if r.failures == uc.maxAttempts(k.kind) {
+			loginLockouts.WithLabelValues(k.kind).Inc()
+			uc.log.Warn("lockouts - Failed - " + k.kind + " " + k.value + " locked out after " + strconv.Itoa(r.failures) + " failed logins")
+		}
+	}
+}
+
+// Succeeded forgets the failures of username. Those of the client IP are
+// kept, so one valid account does not reset guessing at others.
+func (uc *UseCase) Succeeded(_ context.Context, username string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	delete(uc.records, key{KindUsername, strings.ToLower(username)})
+}
+
+// Get lists the usernames and client IPs with failures not yet forgotten.
+func (uc *UseCase) Get(_ context.Context) ([]dto.Lockout, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	now := uc.now()
+	lockouts := make([]dto.Lockout, 0, len(uc.records))
+
+	for k := range uc.records {
+		r := uc.current(k, now)
+		if r == nil {
+			continue
+		}
+
+		l := dto.Lockout{
+			Kind:          k.kind,
+			Key:           k.value,
+			Failures:      r.failures,
+			LastFailureAt: r.last,
+		}
+
+		until, locked := uc.blockedUntil(k.kind, r)
+		if until.After(now) {
+			l.BlockedUntil = &until
+			l.Locked = locked
+		}
+
+		lockouts = append(lockouts, l)
+	}
+
+	// Usernames first, then IPs.
+	sort.Slice(lockouts, func(i, j int) bool {
+		if lockouts[i].Kind != lockouts[j].Kind {
+			return lockouts[i].Kind > lockouts[j].Kind
+		}
+
+		return lockouts[i].Key < lockouts[j].Key
+	})
+
+	return lockouts, nil
+}
+
+// Clear forgets the failures of a username or client IP, lifting any lockout.
+func (uc *UseCase) Clear(_ context.Context, kind, value string) error {
+	k := key{kind, value}
+	if kind == KindUsername {
+		k.value = strings.ToLower(value)
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.current(k, uc.now()) == nil {
+		return ErrNotFound.WrapWithMessage("Clear", "uc.records", "no failed logins recorded for "+kind+" "+value)
+	}
+
+	delete(uc.records, k)
+
+	return nil
+}
+
+// keys returns the records a login counts against. Usernames match
+// case-insensitively, as accounts do.
+func (uc *UseCase) keys(username, ip string) []key {
+	keys := make([]key, 0, 2)
+
+	if uc.policy.MaxAttempts > 0 && username != "" {
+		keys = append(keys, key{KindUsername, strings.ToLower(username)})
+	}
+
+	if uc.policy.MaxAttemptsPerIP > 0 && ip != "" {
+		keys = append(keys, key{KindIP, ip})
+	}
+
+	return keys
+}
+
+// current returns the record of k, dropping it once forgotten. Callers hold
+// uc.mu.
+func (uc *UseCase) current(k key, now time.Time) *record {
+	r, ok := uc.records[k]
+	if !ok {
+		return nil
+	}
+
+	if !now.Before(r.last.Add(uc.policy.Duration)) {
+		delete(uc.records, k)
+
+		return nil
+	}
+
+	return r
+}
+
+func (uc *UseCase) maxAttempts(kind string) int {
+	if kind == KindIP {
+		return uc.policy.MaxAttemptsPerIP
+	}
+
+	return uc.policy.MaxAttempts
+}
+
+// blockedUntil returns when r lets the next attempt through. Only usernames
+// are delayed after each failure: an IP may be shared by many users.
+func (uc *UseCase) blockedUntil(kind string, r *record) (until time.Time, locked bool) {
+	if r.failures >= uc.maxAttempts(kind) {
+		return r.last.Add(uc.policy.Duration), true
+	}
+
+	if kind == KindIP {
+		return r.last, false
+	}
+
+	return r.last.Add(uc.delay(r.failures)), false
+}
+
+// delay doubles policy.Delay for each failure after the first, up to
+// policy.Duration.
+func (uc *UseCase) delay(failures int) time.Duration {
+	d := uc.policy.Delay
+
+	for i := 1; i < failures && d < uc.policy.Duration; i++ {
+		d *= 2
+	}
+
+	return min(d, uc.policy.Duration)
+}
+
+// prune drops forgotten records at most once per pruneInterval. Records live
+// for policy.Duration, so guessing many usernames still grows the map until
+// then; maxRecords is what bounds it. Callers hold uc.mu.
+func (uc *UseCase) prune(now time.Time) {
+	if now.Sub(uc.lastPrune) < pruneInterval {
+		return
+	}
+
+	uc.lastPrune = now
+
+	for k := range uc.records {
+		uc.current(k, now)
+	}
+}
+
+// evict makes room for a new record when maxRecords are held: it drops the
+// forgotten records and, if that frees none, the one whose last failure is
+// oldest. A spray of that many names can so cut an account's count short, but
+// its own IP is locked out long before. Callers hold uc.mu.
+func (uc *UseCase) evict(now time.Time) {
+	var (
+		oldest key
+		found  bool
+	)
+
+	for k, r := range uc.records {
+		if uc.current(k, now) == nil {
+			continue
+		}
+
+		if !found || r.last.Before(uc.records[oldest].last) {
+			oldest, found = k, true
+		}
+	}
+
+	if found && len(uc.records) >= uc.maxRecords {
+		delete(uc.records, oldest)
+	}
+}
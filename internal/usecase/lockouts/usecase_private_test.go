@@ -0,0 +1,144 @@
+package lockouts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// clock is a settable time source for the use case.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func lockoutsTest(t *testing.T) (*UseCase, *clock) {
+	t.Helper()
+
+	c := &clock{t: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+
+	uc := New(logger.New("error"), config.LoginLockout{
+		MaxAttempts:      3,
+		MaxAttemptsPerIP: 5,
+		Delay:            time.Second,
+		Duration:         15 * time.Minute,
+	})
+	uc.now = c.now
+
+	return uc, c
+}
+
+func TestProgressiveDelayAndLockout(t *testing.T) {
+	t.Parallel()
+
+	uc, c := lockoutsTest(t)
+	ctx := context.Background()
+
+	require.Zero(t, uc.Check(ctx, "jdoe", "10.0.0.1"))
+
+	uc.Failed(ctx, "jdoe", "10.0.0.1")
+	require.Equal(t, time.Second, uc.Check(ctx, "JDoe", "10.0.0.2"), "usernames match case-insensitively")
+
+	c.t = c.t.Add(time.Second)
+	require.Zero(t, uc.Check(ctx, "jdoe", "10.0.0.1"))
+
+	uc.Failed(ctx, "jdoe", "10.0.0.1")
+	require.Equal(t, 2*time.Second, uc.Check(ctx, "jdoe", "10.0.0.1"), "the delay doubles")
+
+	c.t = c.t.Add(2 * time.Second)
+	uc.Failed(ctx, "jdoe", "10.0.0.1")
+	require.Equal(t, 15*time.Minute, uc.Check(ctx, "jdoe", "10.0.0.1"), "locked out at maxAttempts")
+
+	lockouts, err := uc.Get(ctx)
+	require.NoError(t, err)
+	require.Len(t, lockouts, 2)
+	require.Equal(t, KindUsername, lockouts[0].Kind)
+	require.True(t, lockouts[0].Locked)
+	require.Equal(t, 3, lockouts[0].Failures)
+	require.Equal(t, KindIP, lockouts[1].Kind)
+	require.False(t, lockouts[1].Locked)
+
+	c.t = c.t.Add(15 * time.Minute)
+	require.Zero(t, uc.Check(ctx, "jdoe", "10.0.0.1"), "failures are forgotten after duration")
+
+	lockouts, err = uc.Get(ctx)
+	require.NoError(t, err)
+	require.Empty(t, lockouts)
+}
+
+func TestIPLockout(t *testing.T) {
+	t.Parallel()
+
+	uc, _ := lockoutsTest(t)
+	ctx := context.Background()
+
+	// Guessing one password across many usernames is caught by the IP count.
+	for _, username := range []string{"a", "b", "c", "d"} {
+		uc.Failed(ctx, username, "10.0.0.1")
+	}
+
+	require.Zero(t, uc.Check(ctx, "e", "10.0.0.1"), "an IP is not delayed before its lockout")
+
+	uc.Failed(ctx, "e", "10.0.0.1")
+	require.Equal(t, 15*time.Minute, uc.Check(ctx, "f", "10.0.0.1"))
+	require.Zero(t, uc.Check(ctx, "f", "10.0.0.2"))
+}
+
+func TestSucceededAndClear(t *testing.T) {
+	t.Parallel()
+
+	uc, _ := lockoutsTest(t)
+	ctx := context.Background()
+
+	uc.Failed(ctx, "jdoe", "10.0.0.1")
+	uc.Succeeded(ctx, "JDOE")
+	require.Zero(t, uc.Check(ctx, "jdoe", ""))
+
+	lockouts, err := uc.Get(ctx)
+	require.NoError(t, err)
+	require.Len(t, lockouts, 1, "the IP keeps its failures")
+
+	require.NoError(t, uc.Clear(ctx, KindIP, "10.0.0.1"))
+	require.Zero(t, uc.Check(ctx, "", "10.0.0.1"))
+
+	var notFound repoerrors.NotFoundError
+	require.ErrorAs(t, uc.Clear(ctx, KindIP, "10.0.0.1"), &notFound)
+	require.ErrorAs(t, uc.Clear(ctx, "host", "x"), &notFound)
+}
+
+func TestDisabled(t *testing.T) {
+	t.Parallel()
+
+	uc := New(logger.New("error"), config.LoginLockout{})
+	ctx := context.Background()
+
+	for range 10 {
+		uc.Failed(ctx, "jdoe", "10.0.0.1")
+	}
+
+	require.Zero(t, uc.Check(ctx, "jdoe", "10.0.0.1"))
+}
+
+func TestRecordsAreCapped(t *testing.T) {
+	t.Parallel()
+
+	uc, c := lockoutsTest(t)
+	uc.maxRecords = 3
+	ctx := context.Background()
+
+	uc.Failed(ctx, "first", "")
+
+	for _, username := range []string{"second", "third", "fourth"} {
+		c.t = c.t.Add(time.Second)
+		uc.Failed(ctx, username, "")
+	}
+
+	require.Len(t, uc.records, 3)
+	require.Zero(t, uc.Check(ctx, "first", ""), "the oldest record makes room")
+	require.NotZero(t, uc.Check(ctx, "fourth", ""))
+}
@@ -0,0 +1,39 @@
+package migrate
+
+import (
+	"github.com/device-management-toolkit/console/internal/usecase/accesspolicies"
+	"github.com/device-management-toolkit/console/internal/usecase/apikeys"
+	"github.com/device-management-toolkit/console/internal/usecase/audit"
+	"github.com/device-management-toolkit/console/internal/usecase/ciraconfigs"
+	"github.com/device-management-toolkit/console/internal/usecase/devicegroups"
+	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/internal/usecase/domains"
+	"github.com/device-management-toolkit/console/internal/usecase/ieee8021xconfigs"
+	"github.com/device-management-toolkit/console/internal/usecase/profiles"
+	"github.com/device-management-toolkit/console/internal/usecase/profilewificonfigs"
+	"github.com/device-management-toolkit/console/internal/usecase/tenants"
+	"github.com/device-management-toolkit/console/internal/usecase/tokens"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/internal/usecase/users"
+	"github.com/device-management-toolkit/console/internal/usecase/wificonfigs"
+)
+
+// Repositories are the repositories of one database, the source or the
+// target of a migration.
+type Repositories struct {
+	Devices            devices.Repository
+	Domains            domains.Repository
+	Profiles           profiles.Repository
+	ProfileWiFiConfigs profilewificonfigs.Repository
+	IEEE8021xConfigs   ieee8021xconfigs.Repository
+	CIRAConfigs        ciraconfigs.Repository
+	WirelessConfigs    wificonfigs.Repository
+	Users              users.Repository
+	APIKeys            apikeys.Repository
+	Tokens             tokens.Repository
+	Audit              audit.Repository
+	AccessPolicies     accesspolicies.Repository
+	DeviceGroups       devicegroups.Repository
+	Tenants            tenants.Repository
+	Trash              trash.Repository
+}
@@ -0,0 +1,214 @@
+// Package migrate copies a console deployment from one database provider to
+// another, say from the embedded SQLite to Postgres or Mongo as it grows.
+//
+// Entities are read through the source repositories and inserted through
+// the target ones as they are stored, so encrypted fields stay encrypted
+// with the console's key and the audit trail keeps its hash chain. The
+// source can keep serving while it is read; changes made after that are not
+// migrated, so switch the console over once the migration succeeds.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// defaultTenant is the tenant of single-tenant deployments, always migrated.
+const defaultTenant = ""
+
+var (
+	ErrMigrateUseCase = consoleerrors.CreateConsoleError("MigrateUseCase")
+	ErrDatabase       = repoerrors.DatabaseError{Console: ErrMigrateUseCase}
+
+	errTargetNotEmpty = errors.New("target database is not empty")
+	errMismatch       = errors.New("target does not match source")
+	errOrphanGroups   = errors.New("device groups with a missing parent")
+	errTrashMissing   = errors.New("entity to move to the trash is missing")
+)
+
+type (
+	// Options of a migration. Tenants are migrated besides the default
+	// tenant and every tenant that owns entities, in the trash or not. A dry
+	// run reads the source and checks the target without writing to it.
+	Options struct {
+		Tenants []string
+		DryRun  bool
+	}
+
+	// Report lists what was migrated, or would be on a dry run.
+	Report struct {
+		Tenants []string
+		DryRun  bool
+		Kinds   []KindReport
+	}
+
+	// KindReport counts and checksums one kind of entity. The target matched
+	// the source in both after the migration.
+	KindReport struct {
+		Kind     string
+		Count    int
+		Checksum string
+	}
+)
+
+// UseCase migrates from the source repositories to the target ones.
+type UseCase struct {
+	source Repositories
+	target Repositories
+	log    logger.Interface
+}
+
+// New -.
+func New(source, target Repositories, log logger.Interface) *UseCase {
+	return &UseCase{
+		source: source,
+		target: target,
+		log:    log,
+	}
+}
+
+// Run migrates everything, then reads the target back and checks that every
+// kind of entity has the count and checksum it had on the source. The target
+// must be empty.
+func (uc *UseCase) Run(ctx context.Context, opts Options) (*Report, error) {
+	s, err := readShared(ctx, &uc.source)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := uc.source.Tenants.Get(ctx)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Run", "uc.source.Tenants.Get", err)
+	}
+
+	tenants := tenantsOf(opts.Tenants, stored)
+
+	if err = s.readTenants(ctx, &uc.source, tenants); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Tenants: tenants, DryRun: opts.DryRun, Kinds: s.summary()}
+
+	if err = uc.checkEmpty(ctx, tenants); err != nil {
+		return nil, err
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	if err = write(ctx, &uc.target, s); err != nil {
+		return nil, err
+	}
+
+	written, err := readShared(ctx, &uc.target)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = written.readTenants(ctx, &uc.target, tenants); err != nil {
+		return nil, err
+	}
+
+	if err = verify(report.Kinds, written.summary()); err != nil {
+		return nil, ErrMigrateUseCase.Wrap("Run", "verify", err)
+	}
+
+	total := 0
+	for _, k := range report.Kinds {
+		total += k.Count
+	}
+
+	uc.log.Info("migrated %d entities of %d tenants", total, len(tenants))
+
+	return report, nil
+}
+
+// checkEmpty refuses a target that holds data already, which the migration
+// would clash with or mix into.
+func (uc *UseCase) checkEmpty(ctx context.Context, tenants []string) error {
+	counters := []struct {
+		kind  string
+		count func(ctx context.Context, tenantID string) (int, error)
+	}{
+		{KindIEEE8021xConfigs, uc.target.IEEE8021xConfigs.GetCount},
+		{KindCIRAConfigs, uc.target.CIRAConfigs.GetCount},
+		{KindWirelessConfigs, uc.target.WirelessConfigs.GetCount},
+		{KindDomains, uc.target.Domains.GetCount},
+		{KindProfiles, uc.target.Profiles.GetCount},
+		{KindDevices, uc.target.Devices.GetCount},
+		{KindUsers, uc.target.Users.GetCount},
+		{KindAPIKeys, uc.target.APIKeys.GetCount},
+		{KindAccessPolicies, uc.target.AccessPolicies.GetCount},
+		{KindDeviceGroups, uc.target.DeviceGroups.GetCount},
+	}
+
+	for _, tenantID := range tenants {
+		for _, c := range counters {
+			n, err := c.count(ctx, tenantID)
+			if err != nil {
+				return ErrDatabase.Wrap("checkEmpty", "GetCount "+c.kind, err)
+			}
+
+			if n > 0 {
+				return ErrMigrateUseCase.Wrap("checkEmpty", c.kind,
+					fmt.Errorf("%w: tenant %q has %d %s", errTargetNotEmpty, tenantID, n, c.kind))
+			}
+		}
+
+		for _, kind := range entity.TrashKinds {
+			n, err := uc.target.Trash.GetCount(ctx, kind, tenantID)
+			if err != nil {
+				return ErrDatabase.Wrap("checkEmpty", "GetCount "+KindTrash, err)
+			}
+
+			if n > 0 {
+				return ErrMigrateUseCase.Wrap("checkEmpty", KindTrash,
+					fmt.Errorf("%w: tenant %q has %d %s in the trash", errTargetNotEmpty, tenantID, n, kind))
+			}
+		}
+	}
+
+	last, err := uc.target.Audit.GetLast(ctx)
+	if err != nil {
+		return ErrDatabase.Wrap("checkEmpty", "uc.target.Audit.GetLast", err)
+	}
+
+	if last != nil {
+		return ErrMigrateUseCase.Wrap("checkEmpty", KindAuditEvents, fmt.Errorf("%w: it has an audit trail", errTargetNotEmpty))
+	}
+
+	return nil
+}
+
+// verify compares the summary of the target with that of the source.
+func verify(want, got []KindReport) error {
+	var errs []error
+
+	for i := range want {
+		if want[i] != got[i] {
+			errs = append(errs, fmt.Errorf("%w: %s: %d with checksum %s, want %d with checksum %s",
+				errMismatch, want[i].Kind, got[i].Count, got[i].Checksum, want[i].Count, want[i].Checksum))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// tenantsOf returns the default tenant, the given ones and those stored,
+// sorted and without duplicates.
+func tenantsOf(given, stored []string) []string {
+	tenants := append([]string{defaultTenant}, given...)
+	tenants = append(tenants, stored...)
+
+	slices.Sort(tenants)
+
+	return slices.Compact(tenants)
+}
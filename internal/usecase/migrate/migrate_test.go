@@ -0,0 +1,34 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+)
+
+func TestTenantsOf(t *testing.T) {
+	t.Parallel()
+
+	tenants := tenantsOf([]string{"b", "a"}, []string{"", "a", "c"})
+	require.Equal(t, []string{"", "a", "b", "c"}, tenants)
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	source := &snapshot{Profiles: []entity.Profile{{ProfileName: "b"}, {ProfileName: "a"}}}
+	// The order a database lists entities in does not matter.
+	target := &snapshot{Profiles: []entity.Profile{{ProfileName: "a"}, {ProfileName: "b"}}}
+
+	require.NoError(t, verify(source.summary(), target.summary()))
+
+	target.Profiles[0].AMTPassword = "changed"
+	target.Devices = []entity.Device{{GUID: "extra"}}
+
+	err := verify(source.summary(), target.summary())
+	require.ErrorIs(t, err, errMismatch)
+	require.ErrorContains(t, err, KindProfiles+": 2 with checksum")
+	require.ErrorContains(t, err, KindDevices+": 1 with checksum")
+}
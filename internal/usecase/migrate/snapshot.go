@@ -0,0 +1,694 @@
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+)
+
+// pageSize is how many entities are read per repository call.
+const pageSize = 100
+
+// snapshot holds every entity of the migrated tenants as stored: secrets stay
+// encrypted with the console's key. Fields a repository fills in itself on
+// insert, such as versions, and the live state of devices are left out, so
+// that a snapshot of the source and one of the target compare equal. Entities
+// in the trash are held with the live ones, and listed in Trash with the time
+// they were deleted at.
+type snapshot struct {
+	IEEE8021xConfigs   []entity.IEEE8021xConfig
+	CIRAConfigs        []entity.CIRAConfig
+	WirelessConfigs    []entity.WirelessConfig
+	Domains            []entity.Domain
+	Profiles           []entity.Profile
+	ProfileWiFiConfigs []entity.ProfileWiFiConfigs
+	Devices            []entity.Device
+	Users              []entity.User
+	APIKeys            []entity.APIKey
+	AccessPolicies     []entity.AccessPolicy
+	DeviceGroups       []entity.DeviceGroup
+	DeviceGroupMembers []entity.DeviceGroupMember
+	RefreshTokens      []entity.RefreshToken
+	RevokedTokens      []entity.RevokedToken
+	AuditEvents        []entity.AuditEvent
+	Trash              []entity.TrashItem
+}
+
+// Kinds of entities, in the order they are written.
+const (
+	KindIEEE8021xConfigs   = "ieee8021xConfigs"
+	KindCIRAConfigs        = "ciraConfigs"
+	KindWirelessConfigs    = "wirelessConfigs"
+	KindDomains            = "domains"
+	KindProfiles           = "profiles"
+	KindProfileWiFiConfigs = "profileWiFiConfigs"
+	KindDevices            = "devices"
+	KindUsers              = "users"
+	KindAPIKeys            = "apiKeys"
+	KindAccessPolicies     = "accessPolicies"
+	KindDeviceGroups       = "deviceGroups"
+	KindDeviceGroupMembers = "deviceGroupMembers"
+	KindRefreshTokens      = "refreshTokens"
+	KindRevokedTokens      = "revokedTokens"
+	KindAuditEvents        = "auditEvents"
+	KindTrash              = "trash"
+)
+
+// summary counts and checksums each kind of the snapshot. The checksum is
+// the SHA-256 of the entities in key order, so it does not depend on the
+// order a database lists them in.
+func (s *snapshot) summary() []KindReport {
+	return []KindReport{
+		summarize(KindIEEE8021xConfigs, s.IEEE8021xConfigs, func(e entity.IEEE8021xConfig) string { return key(e.TenantID, e.ProfileName) }),
+		summarize(KindCIRAConfigs, s.CIRAConfigs, func(e entity.CIRAConfig) string { return key(e.TenantID, e.ConfigName) }),
+		summarize(KindWirelessConfigs, s.WirelessConfigs, func(e entity.WirelessConfig) string { return key(e.TenantID, e.ProfileName) }),
+		summarize(KindDomains, s.Domains, func(e entity.Domain) string { return key(e.TenantID, e.ProfileName) }),
+		summarize(KindProfiles, s.Profiles, func(e entity.Profile) string { return key(e.TenantID, e.ProfileName) }),
+		summarize(KindProfileWiFiConfigs, s.ProfileWiFiConfigs, func(e entity.ProfileWiFiConfigs) string {
+			return key(e.TenantID, e.ProfileName, e.WirelessProfileName)
+		}),
+		summarize(KindDevices, s.Devices, func(e entity.Device) string { return key(e.TenantID, e.GUID) }),
+		summarize(KindUsers, s.Users, func(e entity.User) string { return key(e.TenantID, e.Username) }),
+		summarize(KindAPIKeys, s.APIKeys, func(e entity.APIKey) string { return key(e.TenantID, e.ID) }),
+		summarize(KindAccessPolicies, s.AccessPolicies, func(e entity.AccessPolicy) string { return key(e.TenantID, e.ID) }),
+		summarize(KindDeviceGroups, s.DeviceGroups, func(e entity.DeviceGroup) string { return key(e.TenantID, e.ID) }),
+		summarize(KindDeviceGroupMembers, s.DeviceGroupMembers, func(e entity.DeviceGroupMember) string {
+			return key(e.TenantID, e.GroupID, e.GUID, strconv.FormatBool(e.Dynamic))
+		}),
+		summarize(KindRefreshTokens, s.RefreshTokens, func(e entity.RefreshToken) string { return e.TokenHash }),
+		summarize(KindRevokedTokens, s.RevokedTokens, func(e entity.RevokedToken) string { return e.ID }),
+		summarize(KindAuditEvents, s.AuditEvents, func(e entity.AuditEvent) string { return fmt.Sprintf("%020d", e.Seq) }),
+		summarize(KindTrash, s.Trash, func(e entity.TrashItem) string { return key(e.Kind, e.TenantID, e.ID) }),
+	}
+}
+
+func summarize[T any](kind string, entities []T, keyOf func(T) string) KindReport {
+	sorted := slices.SortedFunc(slices.Values(entities), func(a, b T) int { return cmp.Compare(keyOf(a), keyOf(b)) })
+
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+
+	for i := range sorted {
+		// Entities are plain structs, which always encode.
+		_ = enc.Encode(sorted[i])
+	}
+
+	return KindReport{Kind: kind, Count: len(entities), Checksum: hex.EncodeToString(h.Sum(nil))}
+}
+
+// key joins the parts of an entity key.
+func key(parts ...string) string {
+	return strings.Join(parts, "\x00")
+}
+
+// readShared starts a snapshot with the entities that belong to no tenant
+// or to all of them.
+func readShared(ctx context.Context, repos *Repositories) (*snapshot, error) {
+	s := &snapshot{}
+
+	if err := readRevokedTokens(ctx, repos, s); err != nil {
+		return nil, err
+	}
+
+	if err := readAuditEvents(ctx, repos, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// readTenants adds the entities of the tenants to the snapshot.
+func (s *snapshot) readTenants(ctx context.Context, repos *Repositories, tenants []string) error {
+	for _, tenantID := range tenants {
+		for _, step := range []func(context.Context, *Repositories, *snapshot, string) error{
+			readIEEE8021xConfigs,
+			readCIRAConfigs,
+			readWirelessConfigs,
+			readDomains,
+			readProfiles,
+			readDevices,
+			readTrash,
+			readUsers,
+			readAPIKeys,
+			readAccessPolicies,
+			readDeviceGroups,
+		} {
+			if err := step(ctx, repos, s, tenantID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func readIEEE8021xConfigs(ctx context.Context, repos *Repositories, s *snapshot, tenantID string) error {
+	configs, err := all(ctx, repos.IEEE8021xConfigs.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("read", "repos.IEEE8021xConfigs.Get", err)
+	}
+
+	for i := range configs {
+		configs[i].Version = ""
+	}
+
+	s.IEEE8021xConfigs = append(s.IEEE8021xConfigs, configs...)
+
+	return nil
+}
+
+func readCIRAConfigs(ctx context.Context, repos *Repositories, s *snapshot, tenantID string) error {
+	configs, err := all(ctx, repos.CIRAConfigs.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("read", "repos.CIRAConfigs.Get", err)
+	}
+
+	for i := range configs {
+		configs[i].Version = ""
+	}
+
+	s.CIRAConfigs = append(s.CIRAConfigs, configs...)
+
+	return nil
+}
+
+func readWirelessConfigs(ctx context.Context, repos *Repositories, s *snapshot, tenantID string) error {
+	configs, err := all(ctx, repos.WirelessConfigs.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("read", "repos.WirelessConfigs.Get", err)
+	}
+
+	for i := range configs {
+		storedWirelessConfig(&configs[i])
+	}
+
+	s.WirelessConfigs = append(s.WirelessConfigs, configs...)
+
+	return nil
+}
+
+func readDomains(ctx context.Context, repos *Repositories, s *snapshot, tenantID string) error {
+	domains, err := all(ctx, repos.Domains.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("read", "repos.Domains.Get", err)
+	}
+
+	for i := range domains {
+		domains[i].Version = ""
+	}
+
+	s.Domains = append(s.Domains, domains...)
+
+	return nil
+}
+
+func readProfiles(ctx context.Context, repos *Repositories, s *snapshot, tenantID string) error {
+	list, err := all(ctx, repos.Profiles.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("read", "repos.Profiles.Get", err)
+	}
+
+	for i := range list {
+		// The list leaves out the passwords.
+		p, err := repos.Profiles.GetByName(ctx, list[i].ProfileName, tenantID)
+		if err != nil {
+			return ErrDatabase.Wrap("read", "repos.Profiles.GetByName", err)
+		}
+
+		if p == nil {
+			continue // deleted since it was listed
+		}
+
+		if err := addProfile(ctx, repos, s, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addProfile adds a profile to the snapshot with its wireless links.
+func addProfile(ctx context.Context, repos *Repositories, s *snapshot, p *entity.Profile) error {
+	links, err := repos.ProfileWiFiConfigs.GetByProfileName(ctx, p.ProfileName, p.TenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("read", "repos.ProfileWiFiConfigs.GetByProfileName", err)
+	}
+
+	s.ProfileWiFiConfigs = append(s.ProfileWiFiConfigs, links...)
+	// Only the stored columns: the rest is joined from other entities, and
+	// the creation date is set on insert.
+	s.Profiles = append(s.Profiles, entity.Profile{
+		ProfileName:                p.ProfileName,
+		AMTPassword:                p.AMTPassword,
+		GenerateRandomPassword:     p.GenerateRandomPassword,
+		CIRAConfigName:             p.CIRAConfigName,
+		Activation:                 p.Activation,
+		MEBXPassword:               p.MEBXPassword,
+		GenerateRandomMEBxPassword: p.GenerateRandomMEBxPassword,
+		Tags:                       p.Tags,
+		DHCPEnabled:                p.DHCPEnabled,
+		IPSyncEnabled:              p.IPSyncEnabled,
+		LocalWiFiSyncEnabled:       p.LocalWiFiSyncEnabled,
+		TenantID:                   p.TenantID,
+		TLSMode:                    p.TLSMode,
+		TLSSigningAuthority:        p.TLSSigningAuthority,
+		UserConsent:                p.UserConsent,
+		IDEREnabled:                p.IDEREnabled,
+		KVMEnabled:                 p.KVMEnabled,
+		SOLEnabled:                 p.SOLEnabled,
+		IEEE8021xProfileName:       p.IEEE8021xProfileName,
+		UEFIWiFiSyncEnabled:        p.UEFIWiFiSyncEnabled,
+	})
+
+	return nil
+}
+
+func readDevices(ctx context.Context, repos *Repositories, s *snapshot, tenantID string) error {
+	list, err := all(ctx, repos.Devices.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("read", "repos.Devices.Get", err)
+	}
+
+	for i := range list {
+		// The list leaves out the MPS and MEBx passwords.
+		d, err := repos.Devices.GetByID(ctx, list[i].GUID, tenantID)
+		if err != nil {
+			return ErrDatabase.Wrap("read", "repos.Devices.GetByID", err)
+		}
+
+		if d == nil {
+			continue // deleted since it was listed
+		}
+
+		storedDevice(d)
+		s.Devices = append(s.Devices, *d)
+	}
+
+	return nil
+}
+
+// storedWirelessConfig leaves the fields of a wireless config it stores.
+func storedWirelessConfig(w *entity.WirelessConfig) {
+	w.Version = ""
+	// Joined from the 802.1x config, which is migrated itself.
+	w.AuthenticationProtocol, w.PXETimeout, w.WiredInterface = nil, nil, nil
+}
+
+// storedDevice leaves the fields of a device it stores.
+func storedDevice(d *entity.Device) {
+	// Connection state is live: devices report it again once they connect to
+	// the console on the new database.
+	d.ConnectionStatus, d.MPSInstance = false, ""
+	d.LastConnected, d.LastSeen, d.LastDisconnected = nil, nil, nil
+	d.Version = ""
+}
+
+// readTrash reads the entities the tenant has in the trash, as the live ones
+// are read, and notes when each was deleted.
+func readTrash(ctx context.Context, repos *Repositories, s *snapshot, tenantID string) error {
+	trashed := trash.WithTrashed(ctx)
+
+	for _, kind := range entity.TrashKinds {
+		items, err := all(ctx, func(ctx context.Context, top, skip int, tenantID string) ([]entity.TrashItem, error) {
+			return repos.Trash.Get(ctx, kind, top, skip, tenantID)
+		}, tenantID)
+		if err != nil {
+			return ErrDatabase.Wrap("read", "repos.Trash.Get", err)
+		}
+
+		for i := range items {
+			found, err := readTrashed(trashed, repos, s, &items[i])
+			if err != nil {
+				return err
+			}
+
+			if found { // not purged or restored since it was listed
+				s.Trash = append(s.Trash, items[i])
+			}
+		}
+	}
+
+	return nil
+}
+
+// readTrashed adds one entity in the trash to the snapshot.
+func readTrashed(ctx context.Context, repos *Repositories, s *snapshot, item *entity.TrashItem) (bool, error) {
+	switch item.Kind {
+	case entity.TrashDevices:
+		d, err := repos.Devices.GetByID(ctx, item.ID, item.TenantID)
+		if err != nil || d == nil {
+			return false, wrapTrashed(err, "repos.Devices.GetByID")
+		}
+
+		storedDevice(d)
+		s.Devices = append(s.Devices, *d)
+	case entity.TrashProfiles:
+		p, err := repos.Profiles.GetByName(ctx, item.ID, item.TenantID)
+		if err != nil || p == nil {
+			return false, wrapTrashed(err, "repos.Profiles.GetByName")
+		}
+
+		if err := addProfile(ctx, repos, s, p); err != nil {
+			return false, err
+		}
+	case entity.TrashDomains:
+		d, err := repos.Domains.GetByName(ctx, item.ID, item.TenantID)
+		if err != nil || d == nil {
+			return false, wrapTrashed(err, "repos.Domains.GetByName")
+		}
+
+		d.Version = ""
+		s.Domains = append(s.Domains, *d)
+	case entity.TrashWirelessConfigs:
+		w, err := repos.WirelessConfigs.GetByName(ctx, item.ID, item.TenantID)
+		if err != nil || w == nil {
+			return false, wrapTrashed(err, "repos.WirelessConfigs.GetByName")
+		}
+
+		storedWirelessConfig(w)
+		s.WirelessConfigs = append(s.WirelessConfigs, *w)
+	case entity.TrashCIRAConfigs:
+		c, err := repos.CIRAConfigs.GetByName(ctx, item.ID, item.TenantID)
+		if err != nil || c == nil {
+			return false, wrapTrashed(err, "repos.CIRAConfigs.GetByName")
+		}
+
+		c.Version = ""
+		s.CIRAConfigs = append(s.CIRAConfigs, *c)
+	case entity.TrashIEEE8021xConfigs:
+		c, err := repos.IEEE8021xConfigs.GetByName(ctx, item.ID, item.TenantID)
+		if err != nil || c == nil {
+			return false, wrapTrashed(err, "repos.IEEE8021xConfigs.GetByName")
+		}
+
+		c.Version = ""
+		s.IEEE8021xConfigs = append(s.IEEE8021xConfigs, *c)
+	}
+
+	return true, nil
+}
+
+// wrapTrashed wraps an error reading an entity in the trash, if any.
+func wrapTrashed(err error, call string) error {
+	if err == nil {
+		return nil
+	}
+
+	return ErrDatabase.Wrap("read", call, err)
+}
+
+func readUsers(ctx context.Context, repos *Repositories, s *snapshot, tenantID string) error {
+	list, err := all(ctx, repos.Users.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("read", "repos.Users.Get", err)
+	}
+
+	s.Users = append(s.Users, list...)
+
+	for i := range list {
+		refreshTokens, err := repos.Tokens.GetRefreshTokensByUser(ctx, list[i].Username, tenantID)
+		if err != nil {
+			return ErrDatabase.Wrap("read", "repos.Tokens.GetRefreshTokensByUser", err)
+		}
+
+		s.RefreshTokens = append(s.RefreshTokens, refreshTokens...)
+	}
+
+	return nil
+}
+
+func readAPIKeys(ctx context.Context, repos *Repositories, s *snapshot, tenantID string) error {
+	list, err := all(ctx, repos.APIKeys.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("read", "repos.APIKeys.Get", err)
+	}
+
+	s.APIKeys = append(s.APIKeys, list...)
+
+	return nil
+}
+
+func readAccessPolicies(ctx context.Context, repos *Repositories, s *snapshot, tenantID string) error {
+	list, err := all(ctx, repos.AccessPolicies.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("read", "repos.AccessPolicies.Get", err)
+	}
+
+	s.AccessPolicies = append(s.AccessPolicies, list...)
+
+	return nil
+}
+
+// readDeviceGroups reads the groups of the tenant, and their members among
+// the devices read before, in the trash or not.
+func readDeviceGroups(ctx context.Context, repos *Repositories, s *snapshot, tenantID string) error {
+	list, err := all(ctx, repos.DeviceGroups.Get, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("read", "repos.DeviceGroups.Get", err)
+	}
+
+	migrated := map[string]bool{}
+
+	for i := range s.Devices {
+		if s.Devices[i].TenantID == tenantID {
+			migrated[s.Devices[i].GUID] = true
+		}
+	}
+
+	s.DeviceGroups = append(s.DeviceGroups, list...)
+
+	for i := range list {
+		members, err := repos.DeviceGroups.GetMemberships(ctx, list[i].ID, tenantID)
+		if err != nil {
+			return ErrDatabase.Wrap("read", "repos.DeviceGroups.GetMemberships", err)
+		}
+
+		for _, m := range members {
+			if migrated[m.GUID] {
+				s.DeviceGroupMembers = append(s.DeviceGroupMembers, m)
+			}
+		}
+	}
+
+	return nil
+}
+
+func readRevokedTokens(ctx context.Context, repos *Repositories, s *snapshot) error {
+	for skip := 0; ; skip += pageSize {
+		page, err := repos.Tokens.GetRevoked(ctx, pageSize, skip)
+		if err != nil {
+			return ErrDatabase.Wrap("read", "repos.Tokens.GetRevoked", err)
+		}
+
+		s.RevokedTokens = append(s.RevokedTokens, page...)
+
+		if len(page) < pageSize {
+			return nil
+		}
+	}
+}
+
+// readAuditEvents reads the whole trail, of every tenant: entries are copied
+// as they are, so the hash chain still verifies on the target.
+func readAuditEvents(ctx context.Context, repos *Repositories, s *snapshot) error {
+	var seq int64
+
+	for {
+		page, err := repos.Audit.GetAfter(ctx, seq, pageSize)
+		if err != nil {
+			return ErrDatabase.Wrap("read", "repos.Audit.GetAfter", err)
+		}
+
+		s.AuditEvents = append(s.AuditEvents, page...)
+
+		if len(page) < pageSize {
+			return nil
+		}
+
+		seq = page[len(page)-1].Seq
+	}
+}
+
+// write inserts the snapshot into empty repositories, referenced entities
+// first.
+func write(ctx context.Context, repos *Repositories, s *snapshot) error {
+	for i := range s.IEEE8021xConfigs {
+		if _, err := repos.IEEE8021xConfigs.Insert(ctx, &s.IEEE8021xConfigs[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.IEEE8021xConfigs.Insert", err)
+		}
+	}
+
+	for i := range s.CIRAConfigs {
+		if _, err := repos.CIRAConfigs.Insert(ctx, &s.CIRAConfigs[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.CIRAConfigs.Insert", err)
+		}
+	}
+
+	for i := range s.WirelessConfigs {
+		if _, err := repos.WirelessConfigs.Insert(ctx, &s.WirelessConfigs[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.WirelessConfigs.Insert", err)
+		}
+	}
+
+	for i := range s.Domains {
+		if _, err := repos.Domains.Insert(ctx, &s.Domains[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.Domains.Insert", err)
+		}
+	}
+
+	for i := range s.Profiles {
+		if _, err := repos.Profiles.Insert(ctx, &s.Profiles[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.Profiles.Insert", err)
+		}
+	}
+
+	for i := range s.ProfileWiFiConfigs {
+		if _, err := repos.ProfileWiFiConfigs.Insert(ctx, &s.ProfileWiFiConfigs[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.ProfileWiFiConfigs.Insert", err)
+		}
+	}
+
+	for i := range s.Devices {
+		if _, err := repos.Devices.Insert(ctx, &s.Devices[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.Devices.Insert", err)
+		}
+	}
+
+	for i := range s.Users {
+		if _, err := repos.Users.Insert(ctx, &s.Users[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.Users.Insert", err)
+		}
+	}
+
+	for i := range s.APIKeys {
+		if _, err := repos.APIKeys.Insert(ctx, &s.APIKeys[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.APIKeys.Insert", err)
+		}
+	}
+
+	for i := range s.AccessPolicies {
+		if err := repos.AccessPolicies.Insert(ctx, &s.AccessPolicies[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.AccessPolicies.Insert", err)
+		}
+	}
+
+	if err := writeDeviceGroups(ctx, repos, s); err != nil {
+		return err
+	}
+
+	for i := range s.RefreshTokens {
+		if err := repos.Tokens.InsertRefreshToken(ctx, &s.RefreshTokens[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.Tokens.InsertRefreshToken", err)
+		}
+	}
+
+	for i := range s.RevokedTokens {
+		if err := repos.Tokens.Revoke(ctx, &s.RevokedTokens[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.Tokens.Revoke", err)
+		}
+	}
+
+	for i := range s.AuditEvents {
+		if err := repos.Audit.Insert(ctx, &s.AuditEvents[i]); err != nil {
+			return ErrDatabase.Wrap("write", "repos.Audit.Insert", err)
+		}
+	}
+
+	// Entities in the trash were inserted live, for what refers to them to
+	// be written too, and go back to the trash last.
+	for _, item := range s.Trash {
+		moved, err := repos.Trash.MoveToTrash(ctx, item.Kind, item.ID, item.TenantID, item.DeletedAt)
+		if err != nil {
+			return ErrDatabase.Wrap("write", "repos.Trash.MoveToTrash", err)
+		}
+
+		if !moved {
+			return ErrDatabase.Wrap("write", "repos.Trash.MoveToTrash",
+				fmt.Errorf("%w: %s %q of tenant %q", errTrashMissing, item.Kind, item.ID, item.TenantID))
+		}
+	}
+
+	return nil
+}
+
+// writeDeviceGroups inserts the groups, parents before their subgroups, then
+// their members as they were: the rules are not matched again.
+func writeDeviceGroups(ctx context.Context, repos *Repositories, s *snapshot) error {
+	written := map[string]bool{}
+
+	for len(written) < len(s.DeviceGroups) {
+		progressed := false
+
+		for i := range s.DeviceGroups {
+			g := &s.DeviceGroups[i]
+			if written[key(g.TenantID, g.ID)] || (g.ParentID != "" && !written[key(g.TenantID, g.ParentID)]) {
+				continue
+			}
+
+			if err := repos.DeviceGroups.Insert(ctx, g); err != nil {
+				return ErrDatabase.Wrap("write", "repos.DeviceGroups.Insert", err)
+			}
+
+			written[key(g.TenantID, g.ID)] = true
+			progressed = true
+		}
+
+		if !progressed {
+			return ErrDatabase.Wrap("write", "repos.DeviceGroups.Insert", errOrphanGroups)
+		}
+	}
+
+	type target struct{ tenantID, groupID string }
+
+	static, dynamic := map[target][]string{}, map[target][]string{}
+
+	for _, m := range s.DeviceGroupMembers {
+		t := target{m.TenantID, m.GroupID}
+		if m.Dynamic {
+			dynamic[t] = append(dynamic[t], m.GUID)
+		} else {
+			static[t] = append(static[t], m.GUID)
+		}
+	}
+
+	for t, guids := range static {
+		if _, err := repos.DeviceGroups.AddMembers(ctx, t.groupID, guids, t.tenantID); err != nil {
+			return ErrDatabase.Wrap("write", "repos.DeviceGroups.AddMembers", err)
+		}
+	}
+
+	for t, guids := range dynamic {
+		if err := repos.DeviceGroups.SetMatches(ctx, t.groupID, guids, t.tenantID); err != nil {
+			return ErrDatabase.Wrap("write", "repos.DeviceGroups.SetMatches", err)
+		}
+	}
+
+	return nil
+}
+
+// all pages through a repository list.
+func all[T any](ctx context.Context, get func(ctx context.Context, top, skip int, tenantID string) ([]T, error), tenantID string) ([]T, error) {
+	out := make([]T, 0)
+
+	for skip := 0; ; skip += pageSize {
+		page, err := get(ctx, pageSize, skip, tenantID)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, page...)
+
+		if len(page) < pageSize {
+			return out, nil
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/accesspolicies"
+)
+
+type AccessPolicyRepo struct {
+	col *mongo.Collection
+}
+
+var _ accesspolicies.Repository = (*AccessPolicyRepo)(nil)
+
+func NewAccessPolicyRepo(db *mongo.Database) *AccessPolicyRepo {
+	return &AccessPolicyRepo{col: db.Collection(CollectionAccessPolicies)}
+}
+
+func (r *AccessPolicyRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	n, err := r.col.CountDocuments(ctx, bson.M{fieldTenantID: tenantID})
+	if err != nil {
+		return 0, errAccessPolicyDatabase.Wrap("GetCount", "CountDocuments", err)
+	}
+
+	return int(n), nil
+}
+
+func (r *AccessPolicyRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.AccessPolicy, error) {
+	limit := int64(DefaultTop)
+	if top > 0 {
+		limit = int64(top)
+	}
+
+	offset := int64(0)
+	if skip > 0 {
+		offset = int64(skip)
+	}
+
+	return r.find(ctx, "Get", bson.M{fieldTenantID: tenantID},
+		options.Find().
+			SetSort(bson.D{{Key: fieldName, Value: 1}}).
+			SetLimit(limit).
+			SetSkip(offset))
+}
+
+func (r *AccessPolicyRepo) GetByID(ctx context.Context, id, tenantID string) (*entity.AccessPolicy, error) {
+	if !identifierRegex.MatchString(id) {
+		return nil, nil
+	}
+
+	p := entity.AccessPolicy{}
+
+	err := r.col.FindOne(ctx, bson.M{fieldID: id, fieldTenantID: tenantID}).Decode(&p)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		return nil, errAccessPolicyDatabase.Wrap("GetByID", "FindOne", err)
+	}
+
+	return &p, nil
+}
+
+// GetBySubjects matches subjects with $in, so each one is compared as a
+// literal string rather than parsed as an operator.
+func (r *AccessPolicyRepo) GetBySubjects(ctx context.Context, subjects []string, tenantID string) ([]entity.AccessPolicy, error) {
+	if len(subjects) == 0 {
+		return []entity.AccessPolicy{}, nil
+	}
+
+	return r.find(ctx, "GetBySubjects",
+		bson.M{fieldTenantID: tenantID, fieldSubject: bson.M{"$in": subjects}},
+		options.Find().SetSort(bson.D{{Key: fieldName, Value: 1}}))
+}
+
+func (r *AccessPolicyRepo) Delete(ctx context.Context, id, tenantID string) (bool, error) {
+	if !identifierRegex.MatchString(id) {
+		return false, nil
+	}
+
+	res, err := r.col.DeleteOne(ctx, bson.M{fieldID: id, fieldTenantID: tenantID})
+	if err != nil {
+		return false, errAccessPolicyDatabase.Wrap("Delete", "DeleteOne", err)
+	}
+
+	return res.DeletedCount > 0, nil
+}
+
+func (r *AccessPolicyRepo) Update(ctx context.Context, p *entity.AccessPolicy) (bool, error) {
+	if !identifierRegex.MatchString(p.ID) {
+		return false, nil
+	}
+
+	res, err := r.col.UpdateOne(ctx,
+		bson.M{fieldID: p.ID, fieldTenantID: p.TenantID},
+		bson.M{opSet: bson.M{
+			fieldName:     p.Name,
+			fieldSubject:  p.Subject,
+			fieldTags:     p.Tags,
+			"method":      p.Method,
+			"permissions": p.Permissions,
+		}},
+	)
+	if err != nil {
+		if isDuplicateKey(err) {
+			return false, errAccessPolicyNotUnique.Wrap(err.Error())
+		}
+
+		return false, errAccessPolicyDatabase.Wrap("Update", "UpdateOne", err)
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+func (r *AccessPolicyRepo) Insert(ctx context.Context, p *entity.AccessPolicy) error {
+	if !identifierRegex.MatchString(p.ID) {
+		return errAccessPolicyDatabase.Wrap("Insert", "validate", nil)
+	}
+
+	if _, err := r.col.InsertOne(ctx, p); err != nil {
+		if isDuplicateKey(err) {
+			return errAccessPolicyNotUnique.Wrap(err.Error())
+		}
+
+		return errAccessPolicyDatabase.Wrap("Insert", "InsertOne", err)
+	}
+
+	return nil
+}
+
+func (r *AccessPolicyRepo) find(ctx context.Context, op string, filter bson.M, opts *options.FindOptionsBuilder) ([]entity.AccessPolicy, error) {
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errAccessPolicyDatabase.Wrap(op, "Find", err)
+	}
+	defer cur.Close(ctx)
+
+	out := make([]entity.AccessPolicy, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, errAccessPolicyDatabase.Wrap(op, "Cursor.All", err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,107 @@
+package mongo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	mongo "github.com/device-management-toolkit/console/internal/usecase/nosqldb/mongo"
+)
+
+func TestAccessPolicyRepo_GetByID_Found(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse(
+		"testdb."+mongo.CollectionAccessPolicies,
+		bson.D{
+			{Key: "id", Value: "3f2a9c1b-7d4e"},
+			{Key: "name", Value: "site-a"},
+			{Key: "subject", Value: "role:operator"},
+			{Key: "tags", Value: "site-a"},
+			{Key: "method", Value: "any"},
+		},
+	))
+
+	repo := mongo.NewAccessPolicyRepo(db)
+
+	got, err := repo.GetByID(context.Background(), "3f2a9c1b-7d4e", "")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "role:operator", got.Subject)
+	require.Equal(t, "site-a", got.Tags)
+}
+
+func TestAccessPolicyRepo_GetByID_RejectsOperator(t *testing.T) {
+	t.Parallel()
+
+	db, _ := newMockedDB(t)
+
+	repo := mongo.NewAccessPolicyRepo(db)
+
+	got, err := repo.GetByID(context.Background(), `{"$ne":""}`, "")
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestAccessPolicyRepo_GetBySubjects(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse(
+		"testdb."+mongo.CollectionAccessPolicies,
+		bson.D{{Key: "id", Value: "a"}, {Key: "subject", Value: "user:alice"}},
+		bson.D{{Key: "id", Value: "b"}, {Key: "subject", Value: "role:operator"}},
+	))
+
+	repo := mongo.NewAccessPolicyRepo(db)
+
+	rows, err := repo.GetBySubjects(context.Background(), []string{"user:alice", "role:operator"}, "")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	rows, err = repo.GetBySubjects(context.Background(), nil, "")
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
+
+func TestAccessPolicyRepo_Insert_DuplicateReturnsNotUniqueError(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(duplicateKeyResponse())
+
+	repo := mongo.NewAccessPolicyRepo(db)
+
+	err := repo.Insert(context.Background(), &entity.AccessPolicy{ID: "a", Name: "site-a"})
+	require.Error(t, err)
+
+	var nu repoerrors.NotUniqueError
+	require.True(t, errors.As(err, &nu))
+}
+
+func TestAccessPolicyRepo_UpdateAndDelete(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(updateResponse(1), deleteResponse(1))
+
+	repo := mongo.NewAccessPolicyRepo(db)
+
+	ok, err := repo.Update(context.Background(), &entity.AccessPolicy{ID: "a", Name: "site-a"})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = repo.Delete(context.Background(), "a", "")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
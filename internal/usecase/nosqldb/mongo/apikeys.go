@@ -0,0 +1,127 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/apikeys"
+)
+
+type APIKeyRepo struct {
+	col *mongo.Collection
+}
+
+var _ apikeys.Repository = (*APIKeyRepo)(nil)
+
+func NewAPIKeyRepo(db *mongo.Database) *APIKeyRepo {
+	return &APIKeyRepo{col: db.Collection(CollectionAPIKeys)}
+}
+
+func (r *APIKeyRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	n, err := r.col.CountDocuments(ctx, bson.M{fieldTenantID: tenantID})
+	if err != nil {
+		return 0, errAPIKeyDatabase.Wrap("GetCount", "CountDocuments", err)
+	}
+
+	return int(n), nil
+}
+
+func (r *APIKeyRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.APIKey, error) {
+	limit := int64(DefaultTop)
+	if top > 0 {
+		limit = int64(top)
+	}
+
+	offset := int64(0)
+	if skip > 0 {
+		offset = int64(skip)
+	}
+
+	cur, err := r.col.Find(ctx, bson.M{fieldTenantID: tenantID},
+		options.Find().
+			SetSort(bson.D{{Key: fieldCreationDate, Value: 1}, {Key: fieldID, Value: 1}}).
+			SetLimit(limit).
+			SetSkip(offset))
+	if err != nil {
+		return nil, errAPIKeyDatabase.Wrap("Get", "Find", err)
+	}
+	defer cur.Close(ctx)
+
+	out := make([]entity.APIKey, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, errAPIKeyDatabase.Wrap("Get", "Cursor.All", err)
+	}
+
+	return out, nil
+}
+
+// GetByID searches every tenant, as a presented key names no tenant until it
+// is found.
+func (r *APIKeyRepo) GetByID(ctx context.Context, id string) (*entity.APIKey, error) {
+	if !identifierRegex.MatchString(id) {
+		return nil, nil
+	}
+
+	k := entity.APIKey{}
+
+	err := r.col.FindOne(ctx, bson.M{fieldID: id}).Decode(&k)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		return nil, errAPIKeyDatabase.Wrap("GetByID", "FindOne", err)
+	}
+
+	return &k, nil
+}
+
+func (r *APIKeyRepo) Delete(ctx context.Context, id, tenantID string) (bool, error) {
+	if !identifierRegex.MatchString(id) {
+		return false, nil
+	}
+
+	res, err := r.col.DeleteOne(ctx, bson.M{fieldID: id, fieldTenantID: tenantID})
+	if err != nil {
+		return false, errAPIKeyDatabase.Wrap("Delete", "DeleteOne", err)
+	}
+
+	return res.DeletedCount > 0, nil
+}
+
+func (r *APIKeyRepo) UpdateLastUsed(ctx context.Context, id, lastUsedAt string) (bool, error) {
+	if !identifierRegex.MatchString(id) {
+		return false, errAPIKeyDatabase.Wrap("UpdateLastUsed", "validate", nil)
+	}
+
+	res, err := r.col.UpdateOne(ctx,
+		bson.M{fieldID: id},
+		bson.M{opSet: bson.M{"lastusedat": lastUsedAt}},
+	)
+	if err != nil {
+		return false, errAPIKeyDatabase.Wrap("UpdateLastUsed", "UpdateOne", err)
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+func (r *APIKeyRepo) Insert(ctx context.Context, k *entity.APIKey) (string, error) {
+	if !identifierRegex.MatchString(k.ID) {
+		return "", errAPIKeyDatabase.Wrap("Insert", "validate", nil)
+	}
+
+	if _, err := r.col.InsertOne(ctx, k); err != nil {
+		if isDuplicateKey(err) {
+			return "", errAPIKeyNotUnique.Wrap(err.Error())
+		}
+
+		return "", errAPIKeyDatabase.Wrap("Insert", "InsertOne", err)
+	}
+
+	return "", nil
+}
@@ -0,0 +1,114 @@
+package mongo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	mongo "github.com/device-management-toolkit/console/internal/usecase/nosqldb/mongo"
+)
+
+func TestAPIKeyRepo_GetByID_Found(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse(
+		"testdb."+mongo.CollectionAPIKeys,
+		bson.D{
+			{Key: "id", Value: "3f2a9c1b7d4e8a60"},
+			{Key: "name", Value: "ci"},
+			{Key: "keyhash", Value: "hash"},
+			{Key: "role", Value: "operator"},
+			{Key: "tags", Value: "lab"},
+		},
+	))
+
+	repo := mongo.NewAPIKeyRepo(db)
+
+	got, err := repo.GetByID(context.Background(), "3f2a9c1b7d4e8a60")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "ci", got.Name)
+	require.Equal(t, "hash", got.KeyHash)
+	require.Equal(t, "lab", got.Tags)
+}
+
+func TestAPIKeyRepo_GetByID_RejectsOperator(t *testing.T) {
+	t.Parallel()
+
+	db, _ := newMockedDB(t)
+
+	repo := mongo.NewAPIKeyRepo(db)
+
+	got, err := repo.GetByID(context.Background(), `{"$ne":""}`)
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestAPIKeyRepo_Get_ReturnsRows(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse(
+		"testdb."+mongo.CollectionAPIKeys,
+		bson.D{{Key: "id", Value: "a"}},
+		bson.D{{Key: "id", Value: "b"}},
+	))
+
+	repo := mongo.NewAPIKeyRepo(db)
+
+	rows, err := repo.Get(context.Background(), 10, 0, "")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+}
+
+func TestAPIKeyRepo_Insert_DuplicateReturnsNotUniqueError(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(duplicateKeyResponse())
+
+	repo := mongo.NewAPIKeyRepo(db)
+
+	_, err := repo.Insert(context.Background(), &entity.APIKey{ID: "a"})
+	require.Error(t, err)
+
+	var nu repoerrors.NotUniqueError
+	require.True(t, errors.As(err, &nu))
+}
+
+func TestAPIKeyRepo_UpdateLastUsed(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(updateResponse(1))
+
+	repo := mongo.NewAPIKeyRepo(db)
+
+	ok, err := repo.UpdateLastUsed(context.Background(), "a", "2026-10-19T00:00:00Z")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestAPIKeyRepo_Delete(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(deleteResponse(1))
+
+	repo := mongo.NewAPIKeyRepo(db)
+
+	ok, err := repo.Delete(context.Background(), "a", "")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -0,0 +1,136 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/audit"
+)
+
+type AuditRepo struct {
+	col *mongo.Collection
+}
+
+var _ audit.Repository = (*AuditRepo)(nil)
+
+func NewAuditRepo(db *mongo.Database) *AuditRepo {
+	return &AuditRepo{col: db.Collection(CollectionAuditEvents)}
+}
+
+func auditFilter(filter entity.AuditFilter) bson.M {
+	f := bson.M{fieldTenantID: filter.TenantID}
+
+	for _, field := range []struct{ key, value string }{
+		{fieldActor, filter.Actor},
+		{fieldAction, filter.Action},
+		{fieldTarget, filter.Target},
+		{fieldResult, filter.Result},
+	} {
+		if field.value != "" {
+			f[field.key] = field.value
+		}
+	}
+
+	between := bson.M{}
+
+	if filter.From != "" {
+		between["$gte"] = filter.From
+	}
+
+	if filter.To != "" {
+		between["$lte"] = filter.To
+	}
+
+	if len(between) > 0 {
+		f[fieldTimestamp] = between
+	}
+
+	return f
+}
+
+func (r *AuditRepo) GetCount(ctx context.Context, filter entity.AuditFilter) (int, error) {
+	n, err := r.col.CountDocuments(ctx, auditFilter(filter))
+	if err != nil {
+		return 0, errAuditDatabase.Wrap("GetCount", "CountDocuments", err)
+	}
+
+	return int(n), nil
+}
+
+// Get returns entries of filter.TenantID, oldest first.
+func (r *AuditRepo) Get(ctx context.Context, top, skip int, filter entity.AuditFilter) ([]entity.AuditEvent, error) {
+	limit := int64(DefaultTop)
+	if top > 0 {
+		limit = int64(top)
+	}
+
+	offset := int64(0)
+	if skip > 0 {
+		offset = int64(skip)
+	}
+
+	return r.find(ctx, "Get", auditFilter(filter),
+		options.Find().
+			SetSort(bson.D{{Key: fieldSeq, Value: 1}}).
+			SetLimit(limit).
+			SetSkip(offset))
+}
+
+// GetAfter returns up to limit entries following seq, of every tenant, for
+// verifying the chain.
+func (r *AuditRepo) GetAfter(ctx context.Context, seq int64, limit int) ([]entity.AuditEvent, error) {
+	return r.find(ctx, "GetAfter", bson.M{fieldSeq: bson.M{"$gt": seq}},
+		options.Find().
+			SetSort(bson.D{{Key: fieldSeq, Value: 1}}).
+			SetLimit(int64(max(limit, 1))))
+}
+
+// GetLast returns the newest entry, nil when there is none.
+func (r *AuditRepo) GetLast(ctx context.Context) (*entity.AuditEvent, error) {
+	e := entity.AuditEvent{}
+
+	err := r.col.FindOne(ctx, bson.M{},
+		options.FindOne().SetSort(bson.D{{Key: fieldSeq, Value: -1}})).Decode(&e)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		return nil, errAuditDatabase.Wrap("GetLast", "FindOne", err)
+	}
+
+	return &e, nil
+}
+
+// Insert appends e. A taken sequence number returns a NotUniqueError.
+func (r *AuditRepo) Insert(ctx context.Context, e *entity.AuditEvent) error {
+	if _, err := r.col.InsertOne(ctx, e); err != nil {
+		if isDuplicateKey(err) {
+			return errAuditNotUnique.Wrap(err.Error())
+		}
+
+		return errAuditDatabase.Wrap("Insert", "InsertOne", err)
+	}
+
+	return nil
+}
+
+func (r *AuditRepo) find(ctx context.Context, op string, filter bson.M, opts *options.FindOptionsBuilder) ([]entity.AuditEvent, error) {
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errAuditDatabase.Wrap(op, "Find", err)
+	}
+	defer cur.Close(ctx)
+
+	out := make([]entity.AuditEvent, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, errAuditDatabase.Wrap(op, "Cursor.All", err)
+	}
+
+	return out, nil
+}
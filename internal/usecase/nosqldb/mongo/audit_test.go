@@ -0,0 +1,72 @@
+package mongo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	mongo "github.com/device-management-toolkit/console/internal/usecase/nosqldb/mongo"
+)
+
+func TestAuditRepo_Get(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse(
+		"testdb."+mongo.CollectionAuditEvents,
+		bson.D{{Key: "seq", Value: int64(1)}, {Key: "actor", Value: "jdoe"}},
+		bson.D{{Key: "seq", Value: int64(2)}, {Key: "actor", Value: "jdoe"}},
+	))
+
+	repo := mongo.NewAuditRepo(db)
+
+	rows, err := repo.Get(context.Background(), 10, 0, entity.AuditFilter{Actor: "jdoe", From: "2026-10-19T00:00:00.000Z"})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, int64(2), rows[1].Seq)
+}
+
+func TestAuditRepo_GetLast(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(
+		findResponse("testdb."+mongo.CollectionAuditEvents, bson.D{{Key: "seq", Value: int64(7)}, {Key: "hash", Value: "h7"}}),
+		findResponse("testdb."+mongo.CollectionAuditEvents),
+	)
+
+	repo := mongo.NewAuditRepo(db)
+
+	last, err := repo.GetLast(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &entity.AuditEvent{Seq: 7, Hash: "h7"}, last)
+
+	last, err = repo.GetLast(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, last)
+}
+
+func TestAuditRepo_Insert_DuplicateReturnsNotUniqueError(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(insertResponse(), duplicateKeyResponse())
+
+	repo := mongo.NewAuditRepo(db)
+
+	require.NoError(t, repo.Insert(context.Background(), &entity.AuditEvent{Seq: 1}))
+
+	err := repo.Insert(context.Background(), &entity.AuditEvent{Seq: 1})
+	require.Error(t, err)
+
+	var nu repoerrors.NotUniqueError
+	require.True(t, errors.As(err, &nu))
+}
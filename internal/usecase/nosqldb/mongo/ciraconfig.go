@@ -10,6 +10,7 @@ import (
 
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/usecase/ciraconfigs"
+	"github.com/device-management-toolkit/console/internal/versions"
 )
 
 type CIRARepo struct {
@@ -27,7 +28,12 @@ func (r *CIRARepo) GetCount(ctx context.Context, tenantID string) (int, error) {
 		return 0, nil
 	}
 
-	n, err := r.col.CountDocuments(ctx, bson.M{fieldTenantID: tenantID})
+	filter, err := odataFilter(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}, ciraConfigODataFields)
+	if err != nil {
+		return 0, errCIRADatabase.Wrap("GetCount", "odataFilter", err)
+	}
+
+	n, err := r.col.CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, errCIRADatabase.Wrap("GetCount", "CountDocuments", err)
 	}
@@ -50,8 +56,17 @@ func (r *CIRARepo) Get(ctx context.Context, top, skip int, tenantID string) ([]e
 		offset = int64(skip)
 	}
 
-	cur, err := r.col.Find(ctx, bson.M{fieldTenantID: tenantID},
-		options.Find().SetSort(bson.D{{Key: fieldConfigName, Value: 1}}).SetLimit(limit).SetSkip(offset))
+	filter, err := odataFilter(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}, ciraConfigODataFields)
+	if err != nil {
+		return nil, errCIRADatabase.Wrap("Get", "odataFilter", err)
+	}
+
+	sort, err := odataSort(ctx, ciraConfigODataFields, bson.D{{Key: fieldConfigName, Value: 1}})
+	if err != nil {
+		return nil, errCIRADatabase.Wrap("Get", "odataSort", err)
+	}
+
+	cur, err := r.col.Find(ctx, filter, options.Find().SetSort(sort).SetLimit(limit).SetSkip(offset))
 	if err != nil {
 		return nil, errCIRADatabase.Wrap("Get", "Find", err)
 	}
@@ -76,7 +91,7 @@ func (r *CIRARepo) GetByName(ctx context.Context, configName, tenantID string) (
 
 	c := entity.CIRAConfig{}
 
-	err := r.col.FindOne(ctx, bson.M{fieldConfigName: configName, fieldTenantID: tenantID}).Decode(&c)
+	err := r.col.FindOne(ctx, byKey(ctx, bson.M{fieldConfigName: configName, fieldTenantID: tenantID})).Decode(&c)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, nil
@@ -97,12 +112,12 @@ func (r *CIRARepo) Delete(ctx context.Context, configName, tenantID string) (boo
 		return false, nil
 	}
 
-	res, err := r.col.DeleteOne(ctx, bson.M{fieldConfigName: configName, fieldTenantID: tenantID})
+	deleted, err := softDelete(ctx, r.col, "CIRA config "+configName, bson.M{fieldConfigName: configName, fieldTenantID: tenantID})
 	if err != nil {
-		return false, errCIRADatabase.Wrap("Delete", "DeleteOne", err)
+		return false, errCIRADatabase.Wrap("Delete", "softDelete", err)
 	}
 
-	return res.DeletedCount > 0, nil
+	return deleted, nil
 }
 
 func (r *CIRARepo) Update(ctx context.Context, c *entity.CIRAConfig) (bool, error) {
@@ -114,8 +129,10 @@ func (r *CIRARepo) Update(ctx context.Context, c *entity.CIRAConfig) (bool, erro
 		return false, errCIRADatabase.Wrap("Update", "validate", nil)
 	}
 
+	filter := bson.M{fieldConfigName: c.ConfigName, fieldTenantID: c.TenantID, fieldDeletedAt: nil}
+
 	res, err := r.col.UpdateOne(ctx,
-		bson.M{fieldConfigName: c.ConfigName, fieldTenantID: c.TenantID},
+		withIfMatch(ctx, filter),
 		bson.M{opSet: bson.M{
 			"mpsaddress":             c.MPSAddress,
 			"mpsport":                c.MPSPort,
@@ -127,12 +144,19 @@ func (r *CIRARepo) Update(ctx context.Context, c *entity.CIRAConfig) (bool, erro
 			"mpsrootcertificate":     c.MPSRootCertificate,
 			"proxydetails":           c.ProxyDetails,
 			"generaterandompassword": c.GenerateRandomPassword,
+			fieldVersion:             versions.New(),
 		}},
 	)
 	if err != nil {
 		return false, errCIRADatabase.Wrap("Update", "UpdateOne", err)
 	}
 
+	if res.MatchedCount == 0 {
+		if err := checkVersion(ctx, r.col, "CIRA config "+c.ConfigName, filter); err != nil {
+			return false, err
+		}
+	}
+
 	return res.MatchedCount > 0, nil
 }
 
@@ -145,7 +169,14 @@ func (r *CIRARepo) Insert(ctx context.Context, c *entity.CIRAConfig) (string, er
 		return "", errCIRADatabase.Wrap("Insert", "validate", nil)
 	}
 
-	if _, err := r.col.InsertOne(ctx, c); err != nil {
+	toInsert := *c
+	toInsert.Version = versions.New()
+
+	if err := purgeTrashed(ctx, r.col, entity.TrashCIRAConfigs, c.ConfigName, c.TenantID); err != nil {
+		return "", errCIRADatabase.Wrap("Insert", "purgeTrashed", err)
+	}
+
+	if _, err := r.col.InsertOne(ctx, toInsert); err != nil {
 		if isDuplicateKey(err) {
 			return "", errCIRANotUnique.Wrap(err.Error())
 		}
@@ -153,5 +184,5 @@ func (r *CIRARepo) Insert(ctx context.Context, c *entity.CIRAConfig) (string, er
 		return "", errCIRADatabase.Wrap("Insert", "InsertOne", err)
 	}
 
-	return "", nil
+	return toInsert.Version, nil
 }
@@ -75,7 +75,7 @@ func TestCIRARepo_Insert(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(insertResponse())
+	md.AddResponses(deleteResponse(0), insertResponse())
 
 	repo := mongo.NewCIRARepo(db)
 
@@ -91,7 +91,7 @@ func TestCIRARepo_Insert_DuplicateReturnsNotUniqueError(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(duplicateKeyResponse())
+	md.AddResponses(deleteResponse(0), duplicateKeyResponse())
 
 	repo := mongo.NewCIRARepo(db)
 
@@ -144,7 +144,7 @@ func TestCIRARepo_Delete_Matched(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(deleteResponse(1))
+	md.AddResponses(updateResponse(1))
 
 	repo := mongo.NewCIRARepo(db)
 
@@ -158,7 +158,7 @@ func TestCIRARepo_Delete_NoMatch(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(deleteResponse(0))
+	md.AddResponses(updateResponse(0))
 
 	repo := mongo.NewCIRARepo(db)
 
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -31,16 +32,31 @@ const (
 	CollectionIEEE8021xConfigs   = "ieee8021xconfigs"
 	CollectionWirelessConfigs    = "wirelessconfigs"
 	CollectionProfileWiFiConfigs = "profiles_wirelessconfigs"
+	CollectionUsers              = "users"
+	CollectionAPIKeys            = "api_keys"
+	CollectionRevokedTokens      = "revoked_tokens"
+	CollectionRefreshTokens      = "refresh_tokens"
+	CollectionAuditEvents        = "audit_events"
+	CollectionAccessPolicies     = "access_policies"
+	CollectionDeviceGroups       = "device_groups"
+	CollectionDeviceGroupMembers = "device_group_members"
 )
 
-// Connect dials Mongo, pings, and creates the unique indexes that stand in
-// for the SQL UNIQUE constraints. Caller disconnects the returned client.
-func Connect(ctx context.Context, uri string, log logger.Interface) (*mongo.Client, *mongo.Database, error) {
+// Connect dials Mongo, pings, creates the indexes that stand in for the SQL
+// ones and versions documents that predate versioning. Every operation is
+// bounded by queryTimeout as well as its context, unless queryTimeout is
+// zero. Caller disconnects the returned client.
+func Connect(ctx context.Context, uri string, queryTimeout time.Duration, log logger.Interface) (*mongo.Client, *mongo.Database, error) {
 	if uri == "" {
 		return nil, nil, fmt.Errorf("mongo.Connect: %w", errEmptyConnectionURI)
 	}
 
-	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	opts := options.Client().ApplyURI(uri).SetMonitor(canceledMonitor)
+	if queryTimeout > 0 {
+		opts.SetTimeout(queryTimeout)
+	}
+
+	client, err := mongo.Connect(opts)
 	if err != nil {
 		return nil, nil, fmt.Errorf("mongo.Connect: %w", err)
 	}
@@ -59,12 +75,25 @@ func Connect(ctx context.Context, uri string, log logger.Interface) (*mongo.Clie
 		return nil, nil, fmt.Errorf("mongo.Connect: ensureIndexes: %w", err)
 	}
 
+	if err := backfillVersions(ctx, db); err != nil {
+		_ = client.Disconnect(ctx)
+
+		return nil, nil, fmt.Errorf("mongo.Connect: backfillVersions: %w", err)
+	}
+
+	if err := backfillTagLists(ctx, db); err != nil {
+		_ = client.Disconnect(ctx)
+
+		return nil, nil, fmt.Errorf("mongo.Connect: backfillTagLists: %w", err)
+	}
+
 	log.Info("mongo connected: db=%s", DatabaseName)
 
 	return client, db, nil
 }
 
-// ensureIndexes creates the unique indexes the SQL schema relies on.
+// ensureIndexes creates the unique indexes the SQL schema relies on, and the
+// plain ones it uses for lookups.
 // Idempotent — safe to call on every startup.
 func ensureIndexes(ctx context.Context, db *mongo.Database, log logger.Interface) error {
 	type idx struct {
@@ -81,6 +110,17 @@ func ensureIndexes(ctx context.Context, db *mongo.Database, log logger.Interface
 		{CollectionDomains, bson.D{{Key: fieldDomainSuffix, Value: 1}, {Key: fieldTenantID, Value: 1}}},
 		{CollectionIEEE8021xConfigs, bson.D{{Key: fieldProfileName, Value: 1}, {Key: fieldTenantID, Value: 1}}},
 		{CollectionWirelessConfigs, bson.D{{Key: fieldProfileName, Value: 1}, {Key: fieldTenantID, Value: 1}}},
+		// Key IDs are random and global, like SQL's PRIMARY KEY (id).
+		{CollectionAPIKeys, bson.D{{Key: fieldID, Value: 1}}},
+		{CollectionRevokedTokens, bson.D{{Key: fieldID, Value: 1}}},
+		{CollectionRefreshTokens, bson.D{{Key: fieldTokenHash, Value: 1}}},
+		// A taken sequence number tells an appender the chain moved on.
+		{CollectionAuditEvents, bson.D{{Key: fieldSeq, Value: 1}}},
+		{CollectionAccessPolicies, bson.D{{Key: fieldID, Value: 1}}},
+		{CollectionAccessPolicies, bson.D{{Key: fieldName, Value: 1}, {Key: fieldTenantID, Value: 1}}},
+		{CollectionDeviceGroups, bson.D{{Key: fieldID, Value: 1}}},
+		{CollectionDeviceGroups, bson.D{{Key: fieldName, Value: 1}, {Key: fieldTenantID, Value: 1}}},
+		{CollectionDeviceGroupMembers, bson.D{{Key: fieldGroupID, Value: 1}, {Key: fieldGUID, Value: 1}, {Key: fieldDynamic, Value: 1}}},
 		// SQL PK includes priority — multiple link rows per (profile, wifi, tenant) at different priorities are valid.
 		{CollectionProfileWiFiConfigs, bson.D{
 			{Key: fieldProfileName, Value: 1},
@@ -112,7 +152,32 @@ func ensureIndexes(ctx context.Context, db *mongo.Database, log logger.Interface
 		return fmt.Errorf("create case-insensitive unique index on %s: %w", CollectionDomains, err)
 	}
 
-	log.Info("mongo unique indexes ensured (%d total)", len(tenantScoped)+1)
+	// Mirrors SQL's lower_username_idx; usernames are global, not per tenant.
+	if _, err := db.Collection(CollectionUsers).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: fieldUsername, Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetCollation(&options.Collation{Locale: "en", Strength: 2}).
+			SetName("lower_username_idx"),
+	}); err != nil {
+		return fmt.Errorf("create case-insensitive unique index on %s: %w", CollectionUsers, err)
+	}
+
+	// Plain indexes, for the lookups SQL indexes too.
+	lookups := []idx{
+		// Multikey, like SQL's device_tags_tag_idx.
+		{CollectionDevices, bson.D{{Key: fieldTenantID, Value: 1}, {Key: fieldTagList, Value: 1}}},
+		{CollectionDeviceGroups, bson.D{{Key: fieldTenantID, Value: 1}, {Key: fieldParentID, Value: 1}}},
+		{CollectionDeviceGroupMembers, bson.D{{Key: fieldGUID, Value: 1}, {Key: fieldTenantID, Value: 1}}},
+	}
+
+	for _, i := range lookups {
+		if _, err := db.Collection(i.coll).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: i.keys}); err != nil {
+			return fmt.Errorf("create index on %s: %w", i.coll, err)
+		}
+	}
+
+	log.Info("mongo indexes ensured (%d unique, %d plain)", len(tenantScoped)+2, len(lookups))
 
 	return nil
 }
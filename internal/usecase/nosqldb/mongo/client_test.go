@@ -17,7 +17,7 @@ import (
 func TestConnect_RejectsEmptyURI(t *testing.T) {
 	t.Parallel()
 
-	client, db, err := mongo.Connect(context.Background(), "", logger.New("error"))
+	client, db, err := mongo.Connect(context.Background(), "", 0, logger.New("error"))
 	require.Error(t, err)
 	require.Nil(t, client)
 	require.Nil(t, db)
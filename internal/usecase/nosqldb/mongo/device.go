@@ -3,7 +3,7 @@ package mongo
 import (
 	"context"
 	"errors"
-	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,7 +12,9 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 
 	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/taglist"
 	"github.com/device-management-toolkit/console/internal/usecase/devices"
+	"github.com/device-management-toolkit/console/internal/versions"
 )
 
 type DeviceRepo struct {
@@ -20,28 +22,40 @@ type DeviceRepo struct {
 }
 
 type deviceFilter struct {
-	GUID     string `bson:"guid"`
-	TenantID string `bson:"tenantid"`
+	GUID      string  `bson:"guid"`
+	TenantID  string  `bson:"tenantid"`
+	DeletedAt *string `bson:"deletedat"`
+	Version   bson.M  `bson:"version,omitempty"`
 }
 
 type deviceUpdateFields struct {
-	GUID             string  `bson:"guid"`
-	Hostname         string  `bson:"hostname"`
-	Tags             string  `bson:"tags"`
-	MPSInstance      string  `bson:"mpsinstance"`
-	ConnectionStatus bool    `bson:"connectionstatus"`
-	MPSUsername      string  `bson:"mpsusername"`
-	TenantID         string  `bson:"tenantid"`
-	FriendlyName     string  `bson:"friendlyname"`
-	DNSSuffix        string  `bson:"dnssuffix"`
-	DeviceInfo       string  `bson:"deviceinfo"`
-	Username         string  `bson:"username"`
-	Password         string  `bson:"password"`
-	MPSPassword      *string `bson:"mpspassword"`
-	MEBXPassword     *string `bson:"mebxpassword"`
-	UseTLS           bool    `bson:"usetls"`
-	AllowSelfSigned  bool    `bson:"allowselfsigned"`
-	CertHash         *string `bson:"certhash"`
+	GUID             string   `bson:"guid"`
+	Hostname         string   `bson:"hostname"`
+	Tags             string   `bson:"tags"`
+	MPSInstance      string   `bson:"mpsinstance"`
+	ConnectionStatus bool     `bson:"connectionstatus"`
+	MPSUsername      string   `bson:"mpsusername"`
+	TenantID         string   `bson:"tenantid"`
+	FriendlyName     string   `bson:"friendlyname"`
+	DNSSuffix        string   `bson:"dnssuffix"`
+	DeviceInfo       string   `bson:"deviceinfo"`
+	Username         string   `bson:"username"`
+	Password         string   `bson:"password"`
+	MPSPassword      *string  `bson:"mpspassword"`
+	MEBXPassword     *string  `bson:"mebxpassword"`
+	UseTLS           bool     `bson:"usetls"`
+	AllowSelfSigned  bool     `bson:"allowselfsigned"`
+	CertHash         *string  `bson:"certhash"`
+	Version          string   `bson:"version"`
+	TagList          []string `bson:"taglist"`
+}
+
+// deviceDocument is a device as stored. Its tags are kept twice: as the
+// comma-joined Tags the API returns, and as TagList, which tag queries use
+// and the (tenantid, taglist) index covers.
+type deviceDocument struct {
+	entity.Device `bson:",inline"`
+	TagList       []string `bson:"taglist"`
 }
 
 type deviceUpdateDocument struct {
@@ -59,7 +73,12 @@ func (r *DeviceRepo) GetCount(ctx context.Context, tenantID string) (int, error)
 		return 0, nil
 	}
 
-	n, err := r.col.CountDocuments(ctx, bson.M{fieldTenantID: tenantID})
+	filter, err := odataFilter(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}, deviceODataFields)
+	if err != nil {
+		return 0, errDeviceDatabase.Wrap("GetCount", "odataFilter", err)
+	}
+
+	n, err := r.col.CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, errDeviceDatabase.Wrap("GetCount", "CountDocuments", err)
 	}
@@ -82,8 +101,17 @@ func (r *DeviceRepo) Get(ctx context.Context, top, skip int, tenantID string) ([
 		offset = int64(skip)
 	}
 
-	cur, err := r.col.Find(ctx, bson.M{fieldTenantID: tenantID},
-		options.Find().SetSort(bson.D{{Key: fieldGUID, Value: 1}}).SetLimit(limit).SetSkip(offset))
+	filter, err := odataFilter(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}, deviceODataFields)
+	if err != nil {
+		return nil, errDeviceDatabase.Wrap("Get", "odataFilter", err)
+	}
+
+	sort, err := odataSort(ctx, deviceODataFields, bson.D{{Key: fieldGUID, Value: 1}})
+	if err != nil {
+		return nil, errDeviceDatabase.Wrap("Get", "odataSort", err)
+	}
+
+	cur, err := r.col.Find(ctx, filter, options.Find().SetSort(sort).SetLimit(limit).SetSkip(offset))
 	if err != nil {
 		return nil, errDeviceDatabase.Wrap("Get", "Find", err)
 	}
@@ -108,7 +136,7 @@ func (r *DeviceRepo) GetByID(ctx context.Context, guid, tenantID string) (*entit
 
 	d := entity.Device{}
 
-	err := r.col.FindOne(ctx, bson.M{fieldGUID: guid, fieldTenantID: tenantID}).Decode(&d)
+	err := r.col.FindOne(ctx, byKey(ctx, bson.M{fieldGUID: guid, fieldTenantID: tenantID})).Decode(&d)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, nil
@@ -120,60 +148,62 @@ func (r *DeviceRepo) GetByID(ctx context.Context, guid, tenantID string) (*entit
 	return &d, nil
 }
 
+// GetByGUID searches every tenant, for connections the device opens itself,
+// which name no tenant. GUIDs are unique across tenants.
+func (r *DeviceRepo) GetByGUID(ctx context.Context, guid string) (*entity.Device, error) {
+	if !identifierRegex.MatchString(guid) {
+		return nil, nil
+	}
+
+	d := entity.Device{}
+
+	err := r.col.FindOne(ctx, byKey(ctx, bson.M{fieldGUID: guid})).Decode(&d)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		return nil, errDeviceDatabase.Wrap("GetByGUID", "FindOne", err)
+	}
+
+	return &d, nil
+}
+
+// GetDistinctTags lists the tags of the live devices, in order.
 func (r *DeviceRepo) GetDistinctTags(ctx context.Context, tenantID string) ([]string, error) {
 	if tenantID != "" && !identifierRegex.MatchString(tenantID) {
 		return []string{}, nil
 	}
 
-	// Tags are a comma-joined string (SQL-compat); split and dedupe in Go.
-	var rawStrings []string
-	if err := r.col.Distinct(ctx, fieldTags, bson.M{fieldTenantID: tenantID}).Decode(&rawStrings); err != nil {
+	tags := []string{}
+	if err := r.col.Distinct(ctx, fieldTagList, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}).Decode(&tags); err != nil {
 		return []string{}, errDeviceDatabase.Wrap("GetDistinctTags", "Distinct", err)
 	}
 
-	seen := map[string]struct{}{}
-	out := make([]string, 0, len(rawStrings))
-
-	for _, s := range rawStrings {
-		for _, t := range strings.Split(s, ",") {
-			t = strings.TrimSpace(t)
-			if t == "" {
-				continue
-			}
-
-			if _, ok := seen[t]; ok {
-				continue
-			}
-
-			seen[t] = struct{}{}
-			out = append(out, t)
-		}
-	}
+	slices.Sort(tags)
 
-	return out, nil
+	return tags, nil
 }
 
+// GetByTags lists the live devices carrying every one of tags when method is
+// "AND", and any of them otherwise.
 func (r *DeviceRepo) GetByTags(ctx context.Context, tags []string, method string, limit, offset int, tenantID string) ([]entity.Device, error) {
 	if tenantID != "" && !identifierRegex.MatchString(tenantID) {
 		return []entity.Device{}, nil
 	}
 
-	// Tags are a comma-joined string (SQL-compat); regex against it.
-	regexes := make([]bson.M, 0, len(tags))
-	for _, t := range tags {
-		regexes = append(regexes, bson.M{fieldTags: bson.M{opRegex: "(^|,)" + regexp.QuoteMeta(t) + "(,|$)"}})
+	tags = taglist.Split(taglist.Join(tags))
+	if len(tags) == 0 {
+		return []entity.Device{}, nil
 	}
 
-	filter := bson.M{fieldTenantID: tenantID}
-
-	if len(regexes) > 0 {
-		if method == "AND" {
-			filter["$and"] = regexes
-		} else {
-			filter["$or"] = regexes
-		}
+	operator := "$in"
+	if method == "AND" {
+		operator = "$all"
 	}
 
+	filter := bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil, fieldTagList: bson.M{operator: tags}}
+
 	// No DefaultTop here — limit<=0 means unbounded (matches sqldb GetByTags).
 	lim := int64(0)
 	if limit > 0 {
@@ -209,12 +239,12 @@ func (r *DeviceRepo) Delete(ctx context.Context, guid, tenantID string) (bool, e
 		return false, nil
 	}
 
-	res, err := r.col.DeleteOne(ctx, bson.M{fieldGUID: guid, fieldTenantID: tenantID})
+	deleted, err := softDelete(ctx, r.col, "device "+guid, bson.M{fieldGUID: guid, fieldTenantID: tenantID})
 	if err != nil {
-		return false, errDeviceDatabase.Wrap("Delete", "DeleteOne", err)
+		return false, errDeviceDatabase.Wrap("Delete", "softDelete", err)
 	}
 
-	return res.DeletedCount > 0, nil
+	return deleted, nil
 }
 
 func (r *DeviceRepo) Update(ctx context.Context, d *entity.Device) (bool, error) {
@@ -228,7 +258,7 @@ func (r *DeviceRepo) Update(ctx context.Context, d *entity.Device) (bool, error)
 
 	// Explicit field list mirrors sqldb/device.go:Update so a new field must be wired in intentionally.
 	res, err := r.col.UpdateOne(ctx,
-		deviceFilter{GUID: d.GUID, TenantID: d.TenantID},
+		deviceFilter{GUID: d.GUID, TenantID: d.TenantID, Version: versionCondition(ctx)},
 		deviceUpdateDocument{Set: deviceUpdateFields{
 			GUID:             d.GUID,
 			Hostname:         d.Hostname,
@@ -247,12 +277,20 @@ func (r *DeviceRepo) Update(ctx context.Context, d *entity.Device) (bool, error)
 			UseTLS:           d.UseTLS,
 			AllowSelfSigned:  d.AllowSelfSigned,
 			CertHash:         d.CertHash,
+			Version:          versions.New(),
+			TagList:          taglist.Split(d.Tags),
 		}},
 	)
 	if err != nil {
 		return false, errDeviceDatabase.Wrap("Update", "UpdateOne", err)
 	}
 
+	if res.MatchedCount == 0 {
+		if err := checkVersion(ctx, r.col, "device "+d.GUID, deviceFilter{GUID: d.GUID, TenantID: d.TenantID}); err != nil {
+			return false, err
+		}
+	}
+
 	return res.MatchedCount > 0, nil
 }
 
@@ -303,7 +341,14 @@ func (r *DeviceRepo) Insert(ctx context.Context, d *entity.Device) (string, erro
 		return "", errDeviceDatabase.Wrap("Insert", "validate", nil)
 	}
 
-	_, err := r.col.InsertOne(ctx, d)
+	toInsert := deviceDocument{Device: *d, TagList: taglist.Split(d.Tags)}
+	toInsert.Version = versions.New()
+
+	if err := purgeTrashed(ctx, r.col, entity.TrashDevices, d.GUID, d.TenantID); err != nil {
+		return "", errDeviceDatabase.Wrap("Insert", "purgeTrashed", err)
+	}
+
+	_, err := r.col.InsertOne(ctx, toInsert)
 	if err != nil {
 		if isDuplicateKey(err) {
 			return "", errDeviceNotUnique.Wrap(err.Error())
@@ -312,7 +357,7 @@ func (r *DeviceRepo) Insert(ctx context.Context, d *entity.Device) (string, erro
 		return "", errDeviceDatabase.Wrap("Insert", "InsertOne", err)
 	}
 
-	return "", nil // matches embedded SQL ("" return)
+	return toInsert.Version, nil
 }
 
 func (r *DeviceRepo) GetByColumn(ctx context.Context, columnName, queryValue, tenantID string) ([]entity.Device, error) {
@@ -330,7 +375,7 @@ func (r *DeviceRepo) GetByColumn(ctx context.Context, columnName, queryValue, te
 		return []entity.Device{}, nil
 	}
 
-	cur, err := r.col.Find(ctx, bson.M{field: queryValue, fieldTenantID: tenantID})
+	cur, err := r.col.Find(ctx, bson.M{field: queryValue, fieldTenantID: tenantID, fieldDeletedAt: nil})
 	if err != nil {
 		return nil, errDeviceDatabase.Wrap("GetByColumn", "Find", err)
 	}
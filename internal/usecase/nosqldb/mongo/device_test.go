@@ -11,6 +11,7 @@ import (
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/repoerrors"
 	mongo "github.com/device-management-toolkit/console/internal/usecase/nosqldb/mongo"
+	"github.com/device-management-toolkit/console/internal/versions"
 )
 
 func TestDeviceRepo_GetCount(t *testing.T) {
@@ -85,20 +86,20 @@ func TestDeviceRepo_Get(t *testing.T) {
 	require.Len(t, rows, 2)
 }
 
-// GetDistinctTags issues the `distinct` command, then de-duplicates and
-// trims tags in Go. The test asserts the post-driver in-process logic.
-func TestDeviceRepo_GetDistinctTags_DeduplicatesAcrossRows(t *testing.T) {
+// GetDistinctTags issues the `distinct` command on the taglist array, whose
+// values are single tags already; it only sorts them.
+func TestDeviceRepo_GetDistinctTags_SortsTags(t *testing.T) {
 	t.Parallel()
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(distinctResponse("lab,gpu", "lab,cpu", "  gpu , cpu  ", ""))
+	md.AddResponses(distinctResponse("lab", "cpu", "gpu"))
 
 	repo := mongo.NewDeviceRepo(db)
 
 	tags, err := repo.GetDistinctTags(context.Background(), "t1")
 	require.NoError(t, err)
-	require.ElementsMatch(t, []string{"lab", "gpu", "cpu"}, tags)
+	require.Equal(t, []string{"cpu", "gpu", "lab"}, tags)
 }
 
 // HTTP layer passes column names like "HostName", "FriendlyName" matching the
@@ -170,15 +171,16 @@ func TestDeviceRepo_Insert(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(insertResponse())
+	md.AddResponses(deleteResponse(0), insertResponse())
 
 	repo := mongo.NewDeviceRepo(db)
 
-	_, err := repo.Insert(context.Background(), &entity.Device{
+	version, err := repo.Insert(context.Background(), &entity.Device{
 		GUID:     "g1",
 		TenantID: "t1",
 	})
 	require.NoError(t, err)
+	require.NotEmpty(t, version)
 }
 
 func TestDeviceRepo_Insert_DuplicateReturnsNotUniqueError(t *testing.T) {
@@ -186,7 +188,7 @@ func TestDeviceRepo_Insert_DuplicateReturnsNotUniqueError(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(duplicateKeyResponse())
+	md.AddResponses(deleteResponse(0), duplicateKeyResponse())
 
 	repo := mongo.NewDeviceRepo(db)
 
@@ -234,12 +236,79 @@ func TestDeviceRepo_Update_NoMatch(t *testing.T) {
 	require.False(t, ok)
 }
 
+func TestDeviceRepo_Update_StaleVersion(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(
+		updateResponse(0),
+		findResponse("testdb."+mongo.CollectionDevices, bson.D{{Key: "n", Value: int64(1)}}),
+	)
+
+	repo := mongo.NewDeviceRepo(db)
+
+	ctx := versions.WithIfMatch(context.Background(), []string{"v1"})
+
+	ok, err := repo.Update(ctx, &entity.Device{
+		GUID:     "g1",
+		TenantID: "t1",
+	})
+	require.False(t, ok)
+
+	var pf repoerrors.PreconditionFailedError
+	require.ErrorAs(t, err, &pf)
+}
+
+func TestDeviceRepo_Update_IfMatchNoDevice(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(
+		updateResponse(0),
+		findResponse("testdb."+mongo.CollectionDevices),
+	)
+
+	repo := mongo.NewDeviceRepo(db)
+
+	ctx := versions.WithIfMatch(context.Background(), []string{"v1"})
+
+	ok, err := repo.Update(ctx, &entity.Device{
+		GUID:     "ghost",
+		TenantID: "t1",
+	})
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestDeviceRepo_Delete_StaleVersion(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(
+		updateResponse(0),
+		findResponse("testdb."+mongo.CollectionDevices, bson.D{{Key: "n", Value: int64(1)}}),
+	)
+
+	repo := mongo.NewDeviceRepo(db)
+
+	ctx := versions.WithIfMatch(context.Background(), []string{"v1"})
+
+	ok, err := repo.Delete(ctx, "g1", "t1")
+	require.False(t, ok)
+
+	var pf repoerrors.PreconditionFailedError
+	require.ErrorAs(t, err, &pf)
+}
+
 func TestDeviceRepo_Delete_Matched(t *testing.T) {
 	t.Parallel()
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(deleteResponse(1))
+	md.AddResponses(updateResponse(1))
 
 	repo := mongo.NewDeviceRepo(db)
 
@@ -253,7 +322,7 @@ func TestDeviceRepo_Delete_NoMatch(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(deleteResponse(0))
+	md.AddResponses(updateResponse(0))
 
 	repo := mongo.NewDeviceRepo(db)
 
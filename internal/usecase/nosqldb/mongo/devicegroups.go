@@ -0,0 +1,376 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/devicegroups"
+)
+
+// DeviceGroupRepo keeps groups, their rules embedded, in one collection and
+// their members in another, one document per group, device and kind of
+// membership, as SQL keeps device_group_members.
+type DeviceGroupRepo struct {
+	col     *mongo.Collection
+	members *mongo.Collection
+	devices *mongo.Collection
+}
+
+var _ devicegroups.Repository = (*DeviceGroupRepo)(nil)
+
+func NewDeviceGroupRepo(db *mongo.Database) *DeviceGroupRepo {
+	return &DeviceGroupRepo{
+		col:     db.Collection(CollectionDeviceGroups),
+		members: db.Collection(CollectionDeviceGroupMembers),
+		devices: db.Collection(CollectionDevices),
+	}
+}
+
+func (r *DeviceGroupRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	n, err := r.col.CountDocuments(ctx, bson.M{fieldTenantID: tenantID})
+	if err != nil {
+		return 0, errDeviceGroupDatabase.Wrap("GetCount", "CountDocuments", err)
+	}
+
+	return int(n), nil
+}
+
+func (r *DeviceGroupRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.DeviceGroup, error) {
+	limit := int64(DefaultTop)
+	if top > 0 {
+		limit = int64(top)
+	}
+
+	offset := int64(0)
+	if skip > 0 {
+		offset = int64(skip)
+	}
+
+	cur, err := r.col.Find(ctx, bson.M{fieldTenantID: tenantID},
+		options.Find().
+			SetSort(bson.D{{Key: fieldName, Value: 1}}).
+			SetLimit(limit).
+			SetSkip(offset))
+	if err != nil {
+		return nil, errDeviceGroupDatabase.Wrap("Get", "Find", err)
+	}
+	defer cur.Close(ctx)
+
+	out := make([]entity.DeviceGroup, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, errDeviceGroupDatabase.Wrap("Get", "Cursor.All", err)
+	}
+
+	for i := range out {
+		withRules(&out[i])
+	}
+
+	return out, nil
+}
+
+func (r *DeviceGroupRepo) GetByID(ctx context.Context, id, tenantID string) (*entity.DeviceGroup, error) {
+	if !identifierRegex.MatchString(id) {
+		return nil, nil
+	}
+
+	g := entity.DeviceGroup{}
+
+	err := r.col.FindOne(ctx, bson.M{fieldID: id, fieldTenantID: tenantID}).Decode(&g)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		return nil, errDeviceGroupDatabase.Wrap("GetByID", "FindOne", err)
+	}
+
+	withRules(&g)
+
+	return &g, nil
+}
+
+// Delete deletes the group, then its members.
+func (r *DeviceGroupRepo) Delete(ctx context.Context, id, tenantID string) (bool, error) {
+	if !identifierRegex.MatchString(id) {
+		return false, nil
+	}
+
+	res, err := r.col.DeleteOne(ctx, bson.M{fieldID: id, fieldTenantID: tenantID})
+	if err != nil {
+		return false, errDeviceGroupDatabase.Wrap("Delete", "DeleteOne", err)
+	}
+
+	if res.DeletedCount == 0 {
+		return false, nil
+	}
+
+	if _, err := r.members.DeleteMany(ctx, bson.M{fieldGroupID: id, fieldTenantID: tenantID}); err != nil {
+		return true, errDeviceGroupDatabase.Wrap("Delete", "DeleteMany", err)
+	}
+
+	return true, nil
+}
+
+func (r *DeviceGroupRepo) Update(ctx context.Context, g *entity.DeviceGroup) (bool, error) {
+	if !identifierRegex.MatchString(g.ID) {
+		return false, nil
+	}
+
+	res, err := r.col.UpdateOne(ctx,
+		bson.M{fieldID: g.ID, fieldTenantID: g.TenantID},
+		bson.M{opSet: bson.M{
+			fieldName:     g.Name,
+			"description": g.Description,
+			fieldParentID: g.ParentID,
+			"rules":       g.Rules,
+		}},
+	)
+	if err != nil {
+		if isDuplicateKey(err) {
+			return false, errDeviceGroupNotUnique.Wrap(err.Error())
+		}
+
+		return false, errDeviceGroupDatabase.Wrap("Update", "UpdateOne", err)
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+func (r *DeviceGroupRepo) Insert(ctx context.Context, g *entity.DeviceGroup) error {
+	if !identifierRegex.MatchString(g.ID) {
+		return errDeviceGroupDatabase.Wrap("Insert", "validate", nil)
+	}
+
+	if _, err := r.col.InsertOne(ctx, g); err != nil {
+		if isDuplicateKey(err) {
+			return errDeviceGroupNotUnique.Wrap(err.Error())
+		}
+
+		return errDeviceGroupDatabase.Wrap("Insert", "InsertOne", err)
+	}
+
+	return nil
+}
+
+func (r *DeviceGroupRepo) GetMemberCount(ctx context.Context, ids []string, tenantID string) (int, error) {
+	filter, err := r.memberFilter(ctx, ids, tenantID)
+	if err != nil {
+		return 0, errDeviceGroupDatabase.Wrap("GetMemberCount", "Distinct", err)
+	}
+
+	n, err := r.devices.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, errDeviceGroupDatabase.Wrap("GetMemberCount", "CountDocuments", err)
+	}
+
+	return int(n), nil
+}
+
+func (r *DeviceGroupRepo) GetMembers(ctx context.Context, ids []string, top, skip int, tenantID string) ([]entity.Device, error) {
+	filter, err := r.memberFilter(ctx, ids, tenantID)
+	if err != nil {
+		return nil, errDeviceGroupDatabase.Wrap("GetMembers", "Distinct", err)
+	}
+
+	limit := int64(DefaultTop)
+	if top > 0 {
+		limit = int64(top)
+	}
+
+	offset := int64(0)
+	if skip > 0 {
+		offset = int64(skip)
+	}
+
+	cur, err := r.devices.Find(ctx, filter,
+		options.Find().
+			SetSort(bson.D{{Key: fieldGUID, Value: 1}}).
+			SetLimit(limit).
+			SetSkip(offset))
+	if err != nil {
+		return nil, errDeviceGroupDatabase.Wrap("GetMembers", "Find", err)
+	}
+	defer cur.Close(ctx)
+
+	out := make([]entity.Device, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, errDeviceGroupDatabase.Wrap("GetMembers", "Cursor.All", err)
+	}
+
+	return out, nil
+}
+
+func (r *DeviceGroupRepo) GetMemberGUIDs(ctx context.Context, ids []string, tenantID string) ([]string, error) {
+	filter, err := r.memberFilter(ctx, ids, tenantID)
+	if err != nil {
+		return nil, errDeviceGroupDatabase.Wrap("GetMemberGUIDs", "Distinct", err)
+	}
+
+	guids, err := r.liveGUIDs(ctx, filter)
+	if err != nil {
+		return nil, errDeviceGroupDatabase.Wrap("GetMemberGUIDs", "Distinct", err)
+	}
+
+	return guids, nil
+}
+
+func (r *DeviceGroupRepo) GetMemberships(ctx context.Context, id, tenantID string) ([]entity.DeviceGroupMember, error) {
+	cur, err := r.members.Find(ctx, bson.M{fieldGroupID: id, fieldTenantID: tenantID},
+		options.Find().SetSort(bson.D{{Key: fieldGUID, Value: 1}, {Key: fieldDynamic, Value: 1}}))
+	if err != nil {
+		return nil, errDeviceGroupDatabase.Wrap("GetMemberships", "Find", err)
+	}
+	defer cur.Close(ctx)
+
+	out := make([]entity.DeviceGroupMember, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, errDeviceGroupDatabase.Wrap("GetMemberships", "Cursor.All", err)
+	}
+
+	return out, nil
+}
+
+func (r *DeviceGroupRepo) AddMembers(ctx context.Context, id string, guids []string, tenantID string) (int, error) {
+	added, err := r.insertMembers(ctx, id, guids, false, tenantID)
+	if err != nil {
+		return 0, errDeviceGroupDatabase.Wrap("AddMembers", "insertMembers", err)
+	}
+
+	return added, nil
+}
+
+// RemoveMembers removes members added by hand.
+func (r *DeviceGroupRepo) RemoveMembers(ctx context.Context, id string, guids []string, tenantID string) (int, error) {
+	if len(guids) == 0 {
+		return 0, nil
+	}
+
+	res, err := r.members.DeleteMany(ctx, bson.M{
+		fieldGroupID:  id,
+		fieldGUID:     bson.M{"$in": guids},
+		fieldDynamic:  false,
+		fieldTenantID: tenantID,
+	})
+	if err != nil {
+		return 0, errDeviceGroupDatabase.Wrap("RemoveMembers", "DeleteMany", err)
+	}
+
+	return int(res.DeletedCount), nil
+}
+
+func (r *DeviceGroupRepo) SetMatches(ctx context.Context, id string, guids []string, tenantID string) error {
+	_, err := r.members.DeleteMany(ctx, bson.M{fieldGroupID: id, fieldDynamic: true, fieldTenantID: tenantID})
+	if err != nil {
+		return errDeviceGroupDatabase.Wrap("SetMatches", "DeleteMany", err)
+	}
+
+	if _, err := r.insertMembers(ctx, id, guids, true, tenantID); err != nil {
+		return errDeviceGroupDatabase.Wrap("SetMatches", "insertMembers", err)
+	}
+
+	return nil
+}
+
+func (r *DeviceGroupRepo) SetDeviceMatches(ctx context.Context, guid string, ids []string, tenantID string) error {
+	_, err := r.members.DeleteMany(ctx, bson.M{fieldGUID: guid, fieldDynamic: true, fieldTenantID: tenantID})
+	if err != nil {
+		return errDeviceGroupDatabase.Wrap("SetDeviceMatches", "DeleteMany", err)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Groups deleted meanwhile are skipped.
+	var live []string
+	if err := r.col.Distinct(ctx, fieldID, bson.M{fieldID: bson.M{"$in": ids}, fieldTenantID: tenantID}).Decode(&live); err != nil {
+		return errDeviceGroupDatabase.Wrap("SetDeviceMatches", "Distinct", err)
+	}
+
+	if len(live) == 0 {
+		return nil
+	}
+
+	docs := make([]entity.DeviceGroupMember, len(live))
+	for i, id := range live {
+		docs[i] = entity.DeviceGroupMember{GroupID: id, GUID: guid, Dynamic: true, TenantID: tenantID}
+	}
+
+	if _, err := r.members.InsertMany(ctx, docs); err != nil {
+		return errDeviceGroupDatabase.Wrap("SetDeviceMatches", "InsertMany", err)
+	}
+
+	return nil
+}
+
+// memberFilter matches the live devices that are members of any of the
+// groups ids.
+func (r *DeviceGroupRepo) memberFilter(ctx context.Context, ids []string, tenantID string) (bson.M, error) {
+	guids := []string{}
+
+	if len(ids) > 0 {
+		err := r.members.Distinct(ctx, fieldGUID, bson.M{fieldGroupID: bson.M{"$in": ids}, fieldTenantID: tenantID}).Decode(&guids)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bson.M{fieldGUID: bson.M{"$in": guids}, fieldTenantID: tenantID, fieldDeletedAt: nil}, nil
+}
+
+// liveGUIDs returns the GUIDs of the devices filter matches, sorted.
+func (r *DeviceGroupRepo) liveGUIDs(ctx context.Context, filter bson.M) ([]string, error) {
+	guids := []string{}
+
+	if err := r.devices.Distinct(ctx, fieldGUID, filter).Decode(&guids); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(guids)
+
+	return guids, nil
+}
+
+// insertMembers makes the live devices of guids members of group id, and
+// returns how many were not already.
+func (r *DeviceGroupRepo) insertMembers(ctx context.Context, id string, guids []string, dynamic bool, tenantID string) (int, error) {
+	if len(guids) == 0 {
+		return 0, nil
+	}
+
+	live, err := r.liveGUIDs(ctx, bson.M{fieldGUID: bson.M{"$in": guids}, fieldTenantID: tenantID, fieldDeletedAt: nil})
+	if err != nil || len(live) == 0 {
+		return 0, err
+	}
+
+	models := make([]mongo.WriteModel, len(live))
+
+	for i, guid := range live {
+		member := entity.DeviceGroupMember{GroupID: id, GUID: guid, Dynamic: dynamic, TenantID: tenantID}
+
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{fieldGroupID: id, fieldGUID: guid, fieldDynamic: dynamic}).
+			SetUpdate(bson.M{"$setOnInsert": member}).
+			SetUpsert(true)
+	}
+
+	res, err := r.members.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.UpsertedCount), nil
+}
+
+// withRules gives g an empty rather than a nil list of rules, as SQL does.
+func withRules(g *entity.DeviceGroup) {
+	if g.Rules == nil {
+		g.Rules = []entity.DeviceGroupRule{}
+	}
+}
@@ -0,0 +1,115 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	mongo "github.com/device-management-toolkit/console/internal/usecase/nosqldb/mongo"
+)
+
+func TestDeviceGroupRepo_GetByID(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(
+		findResponse(
+			"testdb."+mongo.CollectionDeviceGroups,
+			bson.D{
+				{Key: "id", Value: "lab"},
+				{Key: "name", Value: "lab"},
+				{Key: "parentid", Value: "site"},
+				{Key: "rules", Value: bson.A{
+					bson.D{{Key: "field", Value: "hostname"}, {Key: "operator", Value: "match"}, {Key: "value", Value: "lab-*"}},
+				}},
+			},
+		),
+		findResponse("testdb."+mongo.CollectionDeviceGroups, bson.D{{Key: "id", Value: "site"}, {Key: "name", Value: "site"}}),
+	)
+
+	repo := mongo.NewDeviceGroupRepo(db)
+
+	got, err := repo.GetByID(context.Background(), "lab", "")
+	require.NoError(t, err)
+	require.Equal(t, "site", got.ParentID)
+	require.Equal(t, []entity.DeviceGroupRule{{Field: "hostname", Operator: "match", Value: "lab-*"}}, got.Rules)
+
+	got, err = repo.GetByID(context.Background(), "site", "")
+	require.NoError(t, err)
+	require.Equal(t, []entity.DeviceGroupRule{}, got.Rules)
+
+	got, err = repo.GetByID(context.Background(), `{"$ne":""}`, "")
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestDeviceGroupRepo_Insert_DuplicateReturnsNotUniqueError(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(duplicateKeyResponse())
+
+	err := mongo.NewDeviceGroupRepo(db).Insert(context.Background(), &entity.DeviceGroup{ID: "lab", Name: "lab"})
+	require.ErrorAs(t, err, &repoerrors.NotUniqueError{})
+}
+
+func TestDeviceGroupRepo_Delete(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	// The group, then its members; a missing group stops at the first.
+	md.AddResponses(deleteResponse(1), deleteResponse(3), deleteResponse(0))
+
+	repo := mongo.NewDeviceGroupRepo(db)
+
+	deleted, err := repo.Delete(context.Background(), "lab", "")
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	deleted, err = repo.Delete(context.Background(), "lab", "")
+	require.NoError(t, err)
+	require.False(t, deleted)
+}
+
+func TestDeviceGroupRepo_GetMemberGUIDs(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	// The members of the groups, then those of them that are live devices.
+	md.AddResponses(distinctResponse("b", "a", "gone"), distinctResponse("b", "a"))
+
+	guids, err := mongo.NewDeviceGroupRepo(db).GetMemberGUIDs(context.Background(), []string{"lab", "bench"}, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, guids)
+}
+
+func TestDeviceGroupRepo_AddMembers(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	// The live devices, then one upsert per device, one of which existed.
+	md.AddResponses(
+		distinctResponse("a", "b"),
+		bson.D{
+			{Key: "n", Value: int32(2)},
+			{Key: "nModified", Value: int32(0)},
+			{Key: "upserted", Value: bson.A{
+				bson.D{{Key: "index", Value: int32(0)}, {Key: "_id", Value: bson.NewObjectID()}},
+			}},
+			{Key: "ok", Value: 1},
+		},
+	)
+
+	added, err := mongo.NewDeviceGroupRepo(db).AddMembers(context.Background(), "lab", []string{"a", "b", "ghost"}, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, added)
+}
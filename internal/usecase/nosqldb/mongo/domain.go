@@ -11,6 +11,7 @@ import (
 
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/usecase/domains"
+	"github.com/device-management-toolkit/console/internal/versions"
 )
 
 type DomainRepo struct {
@@ -28,7 +29,12 @@ func (r *DomainRepo) GetCount(ctx context.Context, tenantID string) (int, error)
 		return 0, nil
 	}
 
-	n, err := r.col.CountDocuments(ctx, bson.M{fieldTenantID: tenantID})
+	filter, err := odataFilter(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}, domainODataFields)
+	if err != nil {
+		return 0, errDomainDatabase.Wrap("GetCount", "odataFilter", err)
+	}
+
+	n, err := r.col.CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, errDomainDatabase.Wrap("GetCount", "CountDocuments", err)
 	}
@@ -51,8 +57,17 @@ func (r *DomainRepo) Get(ctx context.Context, top, skip int, tenantID string) ([
 		offset = int64(skip)
 	}
 
-	cur, err := r.col.Find(ctx, bson.M{fieldTenantID: tenantID},
-		options.Find().SetSort(bson.D{{Key: fieldProfileName, Value: 1}}).SetLimit(limit).SetSkip(offset))
+	filter, err := odataFilter(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}, domainODataFields)
+	if err != nil {
+		return nil, errDomainDatabase.Wrap("Get", "odataFilter", err)
+	}
+
+	sort, err := odataSort(ctx, domainODataFields, bson.D{{Key: fieldProfileName, Value: 1}})
+	if err != nil {
+		return nil, errDomainDatabase.Wrap("Get", "odataSort", err)
+	}
+
+	cur, err := r.col.Find(ctx, filter, options.Find().SetSort(sort).SetLimit(limit).SetSkip(offset))
 	if err != nil {
 		return nil, errDomainDatabase.Wrap("Get", "Find", err)
 	}
@@ -77,7 +92,7 @@ func (r *DomainRepo) GetDomainByDomainSuffix(ctx context.Context, domainSuffix,
 
 	d := entity.Domain{}
 
-	err := r.col.FindOne(ctx, bson.M{fieldDomainSuffix: domainSuffix, fieldTenantID: tenantID}).Decode(&d)
+	err := r.col.FindOne(ctx, bson.M{fieldDomainSuffix: domainSuffix, fieldTenantID: tenantID, fieldDeletedAt: nil}).Decode(&d)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, nil
@@ -99,10 +114,10 @@ func (r *DomainRepo) GetByName(ctx context.Context, name, tenantID string) (*ent
 	}
 
 	// Case-insensitive match (mirrors SQL LOWER(name) = LOWER(?)).
-	filter := bson.M{
+	filter := byKey(ctx, bson.M{
 		fieldProfileName: bson.M{opRegex: "^" + regexp.QuoteMeta(name) + "$", "$options": "i"},
 		fieldTenantID:    tenantID,
-	}
+	})
 
 	d := entity.Domain{}
 
@@ -127,15 +142,15 @@ func (r *DomainRepo) Delete(ctx context.Context, name, tenantID string) (bool, e
 		return false, nil
 	}
 
-	res, err := r.col.DeleteOne(ctx, bson.M{
+	deleted, err := softDelete(ctx, r.col, "domain "+name, bson.M{
 		fieldProfileName: bson.M{opRegex: "^" + regexp.QuoteMeta(name) + "$", "$options": "i"},
 		fieldTenantID:    tenantID,
 	})
 	if err != nil {
-		return false, errDomainDatabase.Wrap("Delete", "DeleteOne", err)
+		return false, errDomainDatabase.Wrap("Delete", "softDelete", err)
 	}
 
-	return res.DeletedCount > 0, nil
+	return deleted, nil
 }
 
 func (r *DomainRepo) Update(ctx context.Context, d *entity.Domain) (bool, error) {
@@ -148,20 +163,29 @@ func (r *DomainRepo) Update(ctx context.Context, d *entity.Domain) (bool, error)
 	}
 
 	// profilename intentionally not in $set — it's the filter key, immutable per SQL semantics.
+	filter := bson.M{fieldProfileName: d.ProfileName, fieldTenantID: d.TenantID, fieldDeletedAt: nil}
+
 	res, err := r.col.UpdateOne(ctx,
-		bson.M{fieldProfileName: d.ProfileName, fieldTenantID: d.TenantID},
+		withIfMatch(ctx, filter),
 		bson.M{opSet: bson.M{
 			fieldDomainSuffix:               d.DomainSuffix,
 			"provisioningcert":              d.ProvisioningCert,
 			"provisioningcertstorageformat": d.ProvisioningCertStorageFormat,
 			"provisioningcertpassword":      d.ProvisioningCertPassword,
 			"expirationdate":                d.ExpirationDate,
+			fieldVersion:                    versions.New(),
 		}},
 	)
 	if err != nil {
 		return false, errDomainDatabase.Wrap("Update", "UpdateOne", err)
 	}
 
+	if res.MatchedCount == 0 {
+		if err := checkVersion(ctx, r.col, "domain "+d.ProfileName, filter); err != nil {
+			return false, err
+		}
+	}
+
 	return res.MatchedCount > 0, nil
 }
 
@@ -174,7 +198,14 @@ func (r *DomainRepo) Insert(ctx context.Context, d *entity.Domain) (string, erro
 		return "", errDomainDatabase.Wrap("Insert", "validate", nil)
 	}
 
-	if _, err := r.col.InsertOne(ctx, d); err != nil {
+	toInsert := *d
+	toInsert.Version = versions.New()
+
+	if err := r.purgeTrashed(ctx, d); err != nil {
+		return "", errDomainDatabase.Wrap("Insert", "purgeTrashed", err)
+	}
+
+	if _, err := r.col.InsertOne(ctx, toInsert); err != nil {
 		if isDuplicateKey(err) {
 			return "", errDomainNotUnique.Wrap(err.Error())
 		}
@@ -182,5 +213,24 @@ func (r *DomainRepo) Insert(ctx context.Context, d *entity.Domain) (string, erro
 		return "", errDomainDatabase.Wrap("Insert", "InsertOne", err)
 	}
 
-	return "", nil
+	return toInsert.Version, nil
+}
+
+// purgeTrashed purges the trashed domains whose name or suffix d would clash
+// with: in its tenant, or anywhere for both ignoring case.
+func (r *DomainRepo) purgeTrashed(ctx context.Context, d *entity.Domain) error {
+	_, err := r.col.DeleteMany(ctx, bson.M{
+		fieldTenantID:  d.TenantID,
+		fieldDeletedAt: inTrash,
+		"$or":          bson.A{bson.M{fieldProfileName: d.ProfileName}, bson.M{fieldDomainSuffix: d.DomainSuffix}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.col.DeleteMany(ctx,
+		bson.M{fieldProfileName: d.ProfileName, fieldDomainSuffix: d.DomainSuffix, fieldDeletedAt: inTrash},
+		options.DeleteMany().SetCollation(caseInsensitive))
+
+	return err
 }
@@ -115,7 +115,7 @@ func TestDomainRepo_Insert(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(insertResponse())
+	md.AddResponses(deleteResponse(0), deleteResponse(0), insertResponse())
 
 	repo := mongo.NewDomainRepo(db)
 
@@ -131,7 +131,7 @@ func TestDomainRepo_Insert_DuplicateReturnsNotUniqueError(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(duplicateKeyResponse())
+	md.AddResponses(deleteResponse(0), deleteResponse(0), duplicateKeyResponse())
 
 	repo := mongo.NewDomainRepo(db)
 
@@ -184,7 +184,7 @@ func TestDomainRepo_Delete_Matched(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(deleteResponse(1))
+	md.AddResponses(updateResponse(1))
 
 	repo := mongo.NewDomainRepo(db)
 
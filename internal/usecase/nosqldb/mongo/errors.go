@@ -23,6 +23,21 @@ var (
 	errWiFiNotUnique               = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("MongoWirelessRepo")}
 	errProfileWiFiConfigsDatabase  = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("MongoProfileWiFiConfigsRepo")}
 	errProfileWiFiConfigsNotUnique = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("MongoProfileWiFiConfigsRepo")}
+	errUserDatabase                = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("MongoUserRepo")}
+	errUserNotUnique               = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("MongoUserRepo")}
+	errAPIKeyDatabase              = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("MongoAPIKeyRepo")}
+	errAPIKeyNotUnique             = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("MongoAPIKeyRepo")}
+	errTokenDatabase               = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("MongoTokenRepo")}
+	errAuditDatabase               = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("MongoAuditRepo")}
+	errAuditNotUnique              = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("MongoAuditRepo")}
+	errAccessPolicyDatabase        = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("MongoAccessPolicyRepo")}
+	errAccessPolicyNotUnique       = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("MongoAccessPolicyRepo")}
+	errDeviceGroupDatabase         = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("MongoDeviceGroupRepo")}
+	errDeviceGroupNotUnique        = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("MongoDeviceGroupRepo")}
+	errTagDatabase                 = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("MongoTagRepo")}
+	errTrashDatabase               = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("MongoTrashRepo")}
+	errTenantDatabase              = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("MongoTenantRepo")}
+	errVersionMismatch             = repoerrors.PreconditionFailedError{Console: consoleerrors.CreateConsoleError("MongoVersionCheck")}
 )
 
 // isDuplicateKey matches Mongo E11000 errors (mapped to NotUniqueError, mirroring SQL).
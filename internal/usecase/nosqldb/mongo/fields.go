@@ -10,10 +10,30 @@ const (
 	fieldConfigName           = "configname"
 	fieldDomainSuffix         = "domainsuffix"
 	fieldTags                 = "tags"
+	fieldTagList              = "taglist"
 	fieldIEEE8021xProfileName = "ieee8021xprofilename"
 	fieldWirelessProfileName  = "wirelessprofilename"
 	fieldPriority             = "priority"
 	fieldWiredInterface       = "wiredinterface"
+	fieldUsername             = "username"
+	fieldID                   = "id"
+	fieldCreationDate         = "creationdate"
+	fieldTokenHash            = "tokenhash"
+	fieldSessionID            = "sessionid"
+	fieldExpiresAt            = "expiresat"
+	fieldSeq                  = "seq"
+	fieldTimestamp            = "timestamp"
+	fieldActor                = "actor"
+	fieldAction               = "action"
+	fieldTarget               = "target"
+	fieldResult               = "result"
+	fieldName                 = "name"
+	fieldSubject              = "subject"
+	fieldDeletedAt            = "deletedat"
+	fieldVersion              = "version"
+	fieldGroupID              = "groupid"
+	fieldParentID             = "parentid"
+	fieldDynamic              = "dynamic"
 )
 
 const (
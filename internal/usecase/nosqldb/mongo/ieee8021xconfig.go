@@ -10,6 +10,7 @@ import (
 
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/usecase/ieee8021xconfigs"
+	"github.com/device-management-toolkit/console/internal/versions"
 )
 
 type IEEE8021xRepo struct {
@@ -31,7 +32,7 @@ func (r *IEEE8021xRepo) CheckProfileExists(ctx context.Context, profileName, ten
 		return false, nil
 	}
 
-	n, err := r.col.CountDocuments(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID},
+	n, err := r.col.CountDocuments(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID, fieldDeletedAt: nil},
 		options.Count().SetLimit(1))
 	if err != nil {
 		return false, errIEEEDatabase.Wrap("CheckProfileExists", "CountDocuments", err)
@@ -45,7 +46,12 @@ func (r *IEEE8021xRepo) GetCount(ctx context.Context, tenantID string) (int, err
 		return 0, nil
 	}
 
-	n, err := r.col.CountDocuments(ctx, bson.M{fieldTenantID: tenantID})
+	filter, err := odataFilter(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}, ieee8021xConfigODataFields)
+	if err != nil {
+		return 0, errIEEEDatabase.Wrap("GetCount", "odataFilter", err)
+	}
+
+	n, err := r.col.CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, errIEEEDatabase.Wrap("GetCount", "CountDocuments", err)
 	}
@@ -68,8 +74,17 @@ func (r *IEEE8021xRepo) Get(ctx context.Context, top, skip int, tenantID string)
 		offset = int64(skip)
 	}
 
-	cur, err := r.col.Find(ctx, bson.M{fieldTenantID: tenantID},
-		options.Find().SetSort(bson.D{{Key: fieldProfileName, Value: 1}}).SetLimit(limit).SetSkip(offset))
+	filter, err := odataFilter(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}, ieee8021xConfigODataFields)
+	if err != nil {
+		return nil, errIEEEDatabase.Wrap("Get", "odataFilter", err)
+	}
+
+	sort, err := odataSort(ctx, ieee8021xConfigODataFields, bson.D{{Key: fieldProfileName, Value: 1}})
+	if err != nil {
+		return nil, errIEEEDatabase.Wrap("Get", "odataSort", err)
+	}
+
+	cur, err := r.col.Find(ctx, filter, options.Find().SetSort(sort).SetLimit(limit).SetSkip(offset))
 	if err != nil {
 		return nil, errIEEEDatabase.Wrap("Get", "Find", err)
 	}
@@ -94,7 +109,7 @@ func (r *IEEE8021xRepo) GetByName(ctx context.Context, profileName, tenantID str
 
 	c := entity.IEEE8021xConfig{}
 
-	err := r.col.FindOne(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID}).Decode(&c)
+	err := r.col.FindOne(ctx, byKey(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID})).Decode(&c)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, nil
@@ -115,12 +130,12 @@ func (r *IEEE8021xRepo) Delete(ctx context.Context, profileName, tenantID string
 		return false, nil
 	}
 
-	res, err := r.col.DeleteOne(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID})
+	deleted, err := softDelete(ctx, r.col, "IEEE 802.1x config "+profileName, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID})
 	if err != nil {
-		return false, errIEEEDatabase.Wrap("Delete", "DeleteOne", err)
+		return false, errIEEEDatabase.Wrap("Delete", "softDelete", err)
 	}
 
-	return res.DeletedCount > 0, nil
+	return deleted, nil
 }
 
 func (r *IEEE8021xRepo) Update(ctx context.Context, c *entity.IEEE8021xConfig) (bool, error) {
@@ -132,18 +147,27 @@ func (r *IEEE8021xRepo) Update(ctx context.Context, c *entity.IEEE8021xConfig) (
 		return false, errIEEEDatabase.Wrap("Update", "validate", nil)
 	}
 
+	filter := bson.M{fieldProfileName: c.ProfileName, fieldTenantID: c.TenantID, fieldDeletedAt: nil}
+
 	res, err := r.col.UpdateOne(ctx,
-		bson.M{fieldProfileName: c.ProfileName, fieldTenantID: c.TenantID},
+		withIfMatch(ctx, filter),
 		bson.M{opSet: bson.M{
 			"authenticationprotocol": c.AuthenticationProtocol,
 			"pxetimeout":             c.PXETimeout,
 			"wiredinterface":         c.WiredInterface,
+			fieldVersion:             versions.New(),
 		}},
 	)
 	if err != nil {
 		return false, errIEEEDatabase.Wrap("Update", "UpdateOne", err)
 	}
 
+	if res.MatchedCount == 0 {
+		if err := checkVersion(ctx, r.col, "IEEE 802.1x config "+c.ProfileName, filter); err != nil {
+			return false, err
+		}
+	}
+
 	return res.MatchedCount > 0, nil
 }
 
@@ -156,7 +180,14 @@ func (r *IEEE8021xRepo) Insert(ctx context.Context, c *entity.IEEE8021xConfig) (
 		return "", errIEEEDatabase.Wrap("Insert", "validate", nil)
 	}
 
-	if _, err := r.col.InsertOne(ctx, c); err != nil {
+	toInsert := *c
+	toInsert.Version = versions.New()
+
+	if err := purgeTrashed(ctx, r.col, entity.TrashIEEE8021xConfigs, c.ProfileName, c.TenantID); err != nil {
+		return "", errIEEEDatabase.Wrap("Insert", "purgeTrashed", err)
+	}
+
+	if _, err := r.col.InsertOne(ctx, toInsert); err != nil {
 		if isDuplicateKey(err) {
 			return "", errIEEENotUnique.Wrap(err.Error())
 		}
@@ -164,5 +195,5 @@ func (r *IEEE8021xRepo) Insert(ctx context.Context, c *entity.IEEE8021xConfig) (
 		return "", errIEEEDatabase.Wrap("Insert", "InsertOne", err)
 	}
 
-	return "", nil
+	return toInsert.Version, nil
 }
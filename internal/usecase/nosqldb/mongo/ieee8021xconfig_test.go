@@ -126,7 +126,7 @@ func TestIEEE8021xRepo_Insert(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(insertResponse())
+	md.AddResponses(deleteResponse(0), insertResponse())
 
 	repo := mongo.NewIEEE8021xRepo(db)
 
@@ -142,7 +142,7 @@ func TestIEEE8021xRepo_Insert_DuplicateReturnsNotUniqueError(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(duplicateKeyResponse())
+	md.AddResponses(deleteResponse(0), duplicateKeyResponse())
 
 	repo := mongo.NewIEEE8021xRepo(db)
 
@@ -178,7 +178,7 @@ func TestIEEE8021xRepo_Delete(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(deleteResponse(1))
+	md.AddResponses(updateResponse(1))
 
 	repo := mongo.NewIEEE8021xRepo(db)
 
@@ -0,0 +1,23 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"github.com/device-management-toolkit/console/pkg/db"
+)
+
+// canceledMonitor counts commands cut short by their context, or by the
+// client's own timeout, in db.QueriesCanceled.
+var canceledMonitor = &event.CommandMonitor{
+	Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+		err := ctx.Err()
+		if err == nil && mongo.IsTimeout(e.Failure) {
+			err = context.DeadlineExceeded
+		}
+
+		db.ObserveCanceled(db.ProviderMongo, err)
+	},
+}
@@ -0,0 +1,174 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/device-management-toolkit/console/internal/odata"
+)
+
+// odataFields maps the OData field names of an entity to its bson fields.
+type odataFields map[string]string
+
+var (
+	deviceODataFields = odataFields{
+		"guid":             fieldGUID,
+		"hostname":         "hostname",
+		"friendlyName":     "friendlyname",
+		"dnsSuffix":        "dnssuffix",
+		"mpsInstance":      "mpsinstance",
+		"mpsusername":      "mpsusername",
+		"connectionStatus": "connectionstatus",
+		"useTLS":           "usetls",
+		"allowSelfSigned":  "allowselfsigned",
+	}
+
+	profileODataFields = odataFields{
+		"profileName":            fieldProfileName,
+		"activation":             "activation",
+		"ciraConfigName":         "ciraconfigname",
+		"ieee8021xProfileName":   fieldIEEE8021xProfileName,
+		"generateRandomPassword": "generaterandompassword",
+		"dhcpEnabled":            "dhcpenabled",
+		"tlsMode":                "tlsmode",
+		"tlsSigningAuthority":    "tlssigningauthority",
+		"userConsent":            "userconsent",
+		"iderEnabled":            "iderenabled",
+		"kvmEnabled":             "kvmenabled",
+		"solEnabled":             "solenabled",
+	}
+
+	domainODataFields = odataFields{
+		"profileName":                   fieldProfileName,
+		"domainSuffix":                  fieldDomainSuffix,
+		"provisioningCertStorageFormat": "provisioningcertstorageformat",
+	}
+
+	ciraConfigODataFields = odataFields{
+		"configName":             fieldConfigName,
+		"mpsServerAddress":       "mpsaddress",
+		"mpsPort":                "mpsport",
+		"username":               fieldUsername,
+		"commonName":             "commonname",
+		"serverAddressFormat":    "serveraddressformat",
+		"authMethod":             "authmethod",
+		"generateRandomPassword": "generaterandompassword",
+	}
+
+	wirelessConfigODataFields = odataFields{
+		"profileName":          fieldProfileName,
+		"authenticationMethod": "authenticationmethod",
+		"encryptionMethod":     "encryptionmethod",
+		"ssid":                 "ssid",
+		"ieee8021xProfileName": fieldIEEE8021xProfileName,
+	}
+
+	ieee8021xConfigODataFields = odataFields{
+		"profileName":            fieldProfileName,
+		"authenticationProtocol": "authenticationprotocol",
+		"pxeTimeout":             "pxetimeout",
+		"wiredInterface":         fieldWiredInterface,
+	}
+)
+
+// odataFilter narrows base by the $filter of the query in ctx, if any.
+func odataFilter(ctx context.Context, base bson.M, fields odataFields) (bson.M, error) {
+	q := odata.FromContext(ctx)
+	if q == nil || q.Filter == nil {
+		return base, nil
+	}
+
+	f, err := odataCondition(q.Filter, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.M{"$and": bson.A{base, f}}, nil
+}
+
+// odataSort orders by the $orderby of the query in ctx, then by defaults,
+// which keep pages stable.
+func odataSort(ctx context.Context, fields odataFields, defaults bson.D) (bson.D, error) {
+	q := odata.FromContext(ctx)
+	if q == nil || len(q.OrderBy) == 0 {
+		return defaults, nil
+	}
+
+	sort := make(bson.D, 0, len(q.OrderBy)+len(defaults))
+
+	for _, o := range q.OrderBy {
+		field, ok := fields[o.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot order by %q", odata.ErrInvalidQuery, o.Field)
+		}
+
+		direction := 1
+		if o.Desc {
+			direction = -1
+		}
+
+		sort = append(sort, bson.E{Key: field, Value: direction})
+	}
+
+	for _, d := range defaults {
+		if !containsKey(sort, d.Key) {
+			sort = append(sort, d)
+		}
+	}
+
+	return sort, nil
+}
+
+// containsKey reports whether d already sorts by key; Mongo rejects a sort
+// naming a field twice.
+func containsKey(d bson.D, key string) bool {
+	for _, e := range d {
+		if e.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func odataCondition(e *odata.Expr, fields odataFields) (bson.M, error) {
+	if e.Op == odata.And || e.Op == odata.Or {
+		left, err := odataCondition(e.Left, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := odataCondition(e.Right, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		return bson.M{"$" + string(e.Op): bson.A{left, right}}, nil
+	}
+
+	field, ok := fields[e.Field]
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot filter on %q", odata.ErrInvalidQuery, e.Field)
+	}
+
+	switch e.Op {
+	case odata.Eq, odata.Ne, odata.Gt, odata.Lt:
+		// An explicit $eq keeps a value from ever being read as an operator.
+		return bson.M{field: bson.M{"$" + string(e.Op): e.Value}}, nil
+	case odata.Contains, odata.StartsWith:
+		value, _ := e.Value.(string)
+
+		pattern := regexp.QuoteMeta(value)
+		if e.Op == odata.StartsWith {
+			pattern = "^" + pattern
+		}
+
+		return bson.M{field: bson.M{opRegex: pattern, "$options": "i"}}, nil
+	case odata.And, odata.Or:
+	}
+
+	return nil, fmt.Errorf("%w: unsupported operator %q", odata.ErrInvalidQuery, e.Op)
+}
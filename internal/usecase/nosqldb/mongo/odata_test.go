@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/device-management-toolkit/console/internal/odata"
+)
+
+func TestODataFilterAndSort(t *testing.T) {
+	t.Parallel()
+
+	q, err := odata.Parse("connectionStatus eq false and (startswith(hostname,'lab.') or friendlyName ne '$gt')",
+		"hostname desc,guid", "", odata.Fields{
+			"guid":             odata.String,
+			"hostname":         odata.String,
+			"friendlyName":     odata.String,
+			"connectionStatus": odata.Bool,
+		})
+	require.NoError(t, err)
+
+	ctx := odata.WithQuery(context.Background(), q)
+	base := bson.M{fieldTenantID: "t1"}
+
+	filter, err := odataFilter(ctx, base, deviceODataFields)
+	require.NoError(t, err)
+	require.Equal(t, bson.M{"$and": bson.A{base, bson.M{"$and": bson.A{
+		bson.M{"connectionstatus": bson.M{"$eq": false}},
+		bson.M{"$or": bson.A{
+			bson.M{"hostname": bson.M{opRegex: `^lab\.`, "$options": "i"}},
+			bson.M{"friendlyname": bson.M{"$ne": "$gt"}},
+		}},
+	}}}}, filter)
+
+	sort, err := odataSort(ctx, deviceODataFields, bson.D{{Key: fieldGUID, Value: 1}})
+	require.NoError(t, err)
+	require.Equal(t, bson.D{{Key: "hostname", Value: -1}, {Key: fieldGUID, Value: 1}}, sort)
+
+	// Without a query the filter and sort are left as they were.
+	filter, err = odataFilter(context.Background(), base, deviceODataFields)
+	require.NoError(t, err)
+	require.Equal(t, base, filter)
+
+	// Fields a repository does not map are refused.
+	q, err = odata.Parse("ssid eq 'x'", "", "", odata.Fields{"ssid": odata.String})
+	require.NoError(t, err)
+
+	_, err = odataFilter(odata.WithQuery(context.Background(), q), base, deviceODataFields)
+	require.ErrorIs(t, err, odata.ErrInvalidQuery)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/usecase/profiles"
+	"github.com/device-management-toolkit/console/internal/versions"
 	"github.com/device-management-toolkit/console/pkg/logger"
 )
 
@@ -34,7 +35,12 @@ func (r *ProfileRepo) GetCount(ctx context.Context, tenantID string) (int, error
 		return 0, nil
 	}
 
-	n, err := r.col.CountDocuments(ctx, bson.M{fieldTenantID: tenantID})
+	filter, err := odataFilter(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}, profileODataFields)
+	if err != nil {
+		return 0, errProfileDatabase.Wrap("GetCount", "odataFilter", err)
+	}
+
+	n, err := r.col.CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, errProfileDatabase.Wrap("GetCount", "CountDocuments", err)
 	}
@@ -57,8 +63,17 @@ func (r *ProfileRepo) Get(ctx context.Context, top, skip int, tenantID string) (
 		offset = int64(skip)
 	}
 
-	cur, err := r.col.Find(ctx, bson.M{fieldTenantID: tenantID},
-		options.Find().SetSort(bson.D{{Key: fieldProfileName, Value: 1}}).SetLimit(limit).SetSkip(offset))
+	filter, err := odataFilter(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}, profileODataFields)
+	if err != nil {
+		return nil, errProfileDatabase.Wrap("Get", "odataFilter", err)
+	}
+
+	sort, err := odataSort(ctx, profileODataFields, bson.D{{Key: fieldProfileName, Value: 1}})
+	if err != nil {
+		return nil, errProfileDatabase.Wrap("Get", "odataSort", err)
+	}
+
+	cur, err := r.col.Find(ctx, filter, options.Find().SetSort(sort).SetLimit(limit).SetSkip(offset))
 	if err != nil {
 		return nil, errProfileDatabase.Wrap("Get", "Find", err)
 	}
@@ -90,7 +105,7 @@ func (r *ProfileRepo) GetByName(ctx context.Context, profileName, tenantID strin
 
 	p := entity.Profile{}
 
-	err := r.col.FindOne(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID}).Decode(&p)
+	err := r.col.FindOne(ctx, byKey(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID})).Decode(&p)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, nil
@@ -143,12 +158,12 @@ func (r *ProfileRepo) Delete(ctx context.Context, profileName, tenantID string)
 		return false, nil
 	}
 
-	res, err := r.col.DeleteOne(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID})
+	deleted, err := softDelete(ctx, r.col, "profile "+profileName, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID})
 	if err != nil {
-		return false, errProfileDatabase.Wrap("Delete", "DeleteOne", err)
+		return false, errProfileDatabase.Wrap("Delete", "softDelete", err)
 	}
 
-	return res.DeletedCount > 0, nil
+	return deleted, nil
 }
 
 func (r *ProfileRepo) Update(ctx context.Context, p *entity.Profile) (bool, error) {
@@ -180,16 +195,25 @@ func (r *ProfileRepo) Update(ctx context.Context, p *entity.Profile) (bool, erro
 		"ipsyncenabled":              p.IPSyncEnabled,
 		"localwifisyncenabled":       p.LocalWiFiSyncEnabled,
 		"uefiwifisyncenabled":        p.UEFIWiFiSyncEnabled,
+		fieldVersion:                 versions.New(),
 	}
 
+	filter := bson.M{fieldProfileName: p.ProfileName, fieldTenantID: p.TenantID, fieldDeletedAt: nil}
+
 	res, err := r.col.UpdateOne(ctx,
-		bson.M{fieldProfileName: p.ProfileName, fieldTenantID: p.TenantID},
+		withIfMatch(ctx, filter),
 		bson.M{opSet: set},
 	)
 	if err != nil {
 		return false, errProfileDatabase.Wrap("Update", "UpdateOne", err)
 	}
 
+	if res.MatchedCount == 0 {
+		if err := checkVersion(ctx, r.col, "profile "+p.ProfileName, filter); err != nil {
+			return false, err
+		}
+	}
+
 	return res.MatchedCount > 0, nil
 }
 
@@ -206,6 +230,11 @@ func (r *ProfileRepo) Insert(ctx context.Context, p *entity.Profile) (string, er
 	toInsert := *p
 	toInsert.CIRAConfigName = nullIfEmptyPtr(p.CIRAConfigName)
 	toInsert.IEEE8021xProfileName = nullIfEmptyPtr(p.IEEE8021xProfileName)
+	toInsert.Version = versions.New()
+
+	if err := purgeTrashed(ctx, r.col, entity.TrashProfiles, p.ProfileName, p.TenantID); err != nil {
+		return "", errProfileDatabase.Wrap("Insert", "purgeTrashed", err)
+	}
 
 	if _, err := r.col.InsertOne(ctx, toInsert); err != nil {
 		if isDuplicateKey(err) {
@@ -215,7 +244,7 @@ func (r *ProfileRepo) Insert(ctx context.Context, p *entity.Profile) (string, er
 		return "", errProfileDatabase.Wrap("Insert", "InsertOne", err)
 	}
 
-	return "", nil
+	return toInsert.Version, nil
 }
 
 // nullIfEmptyPtr returns nil for (nil || pointer to "") so BSON stores null.
@@ -11,6 +11,7 @@ import (
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/repoerrors"
 	mongo "github.com/device-management-toolkit/console/internal/usecase/nosqldb/mongo"
+	"github.com/device-management-toolkit/console/internal/versions"
 	"github.com/device-management-toolkit/console/pkg/logger"
 )
 
@@ -116,7 +117,7 @@ func TestProfileRepo_Insert(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(insertResponse())
+	md.AddResponses(deleteResponse(0), insertResponse())
 
 	repo := mongo.NewProfileRepo(db, logger.New("error"))
 
@@ -132,7 +133,7 @@ func TestProfileRepo_Insert_DuplicateReturnsNotUniqueError(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(duplicateKeyResponse())
+	md.AddResponses(deleteResponse(0), duplicateKeyResponse())
 
 	repo := mongo.NewProfileRepo(db, logger.New("error"))
 
@@ -163,12 +164,36 @@ func TestProfileRepo_Update(t *testing.T) {
 	require.True(t, ok)
 }
 
+func TestProfileRepo_Update_StaleVersion(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(
+		updateResponse(0),
+		findResponse("testdb."+mongo.CollectionProfiles, bson.D{{Key: "n", Value: int64(1)}}),
+	)
+
+	repo := mongo.NewProfileRepo(db, logger.New("error"))
+
+	ctx := versions.WithIfMatch(context.Background(), []string{"v1"})
+
+	ok, err := repo.Update(ctx, &entity.Profile{
+		ProfileName: "p1",
+		TenantID:    "t1",
+	})
+	require.False(t, ok)
+
+	var pf repoerrors.PreconditionFailedError
+	require.ErrorAs(t, err, &pf)
+}
+
 func TestProfileRepo_Delete(t *testing.T) {
 	t.Parallel()
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(deleteResponse(1))
+	md.AddResponses(updateResponse(1))
 
 	repo := mongo.NewProfileRepo(db, logger.New("error"))
 
@@ -0,0 +1,71 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+)
+
+// Deleting an entity moves it to the trash by setting its deletedat field,
+// which live documents lack; a nil deletedat in a filter matches only those.
+
+// byKey completes a filter that looks one entity up by its key: live
+// documents only, unless ctx looks in the trash too (see trash.WithTrashed).
+func byKey(ctx context.Context, filter bson.M) bson.M {
+	if !trash.Trashed(ctx) {
+		filter[fieldDeletedAt] = nil
+	}
+
+	return filter
+}
+
+// deletedNow is the deletedat of a document deleted now, in RFC 3339 and UTC
+// like sqldb's deleted_at, so it orders as text.
+func deletedNow() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// softDelete moves the live document matching filter, at a version ctx
+// expects if any, to the trash and tells whether there was one. name says
+// what the document is in a version mismatch error.
+func softDelete(ctx context.Context, col *mongo.Collection, name string, filter bson.M) (bool, error) {
+	filter[fieldDeletedAt] = nil
+
+	res, err := col.UpdateOne(ctx, withIfMatch(ctx, filter), bson.M{opSet: bson.M{fieldDeletedAt: deletedNow()}})
+	if err != nil {
+		return false, err
+	}
+
+	if res.MatchedCount == 0 {
+		return false, checkVersion(ctx, col, name, filter)
+	}
+
+	return true, nil
+}
+
+// purgeTrashed purges the trashed entity of kind with id in tenantID, and its
+// links, so a new one can take its key: the unique indexes cover trashed
+// documents too.
+func purgeTrashed(ctx context.Context, col *mongo.Collection, kind, id, tenantID string) error {
+	c, ok := trashCollections[kind]
+	if !ok {
+		return errUnknownTrashKind
+	}
+
+	res, err := col.DeleteOne(ctx, bson.M{c.id: id, fieldTenantID: tenantID, fieldDeletedAt: inTrash})
+	if err != nil || res.DeletedCount == 0 {
+		return err
+	}
+
+	for _, link := range c.links {
+		if _, err := col.Database().Collection(link).DeleteMany(ctx, bson.M{c.id: id, fieldTenantID: tenantID}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
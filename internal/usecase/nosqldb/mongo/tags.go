@@ -0,0 +1,195 @@
+package mongo
+
+import (
+	"context"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/taglist"
+	"github.com/device-management-toolkit/console/internal/versions"
+)
+
+// tagChunk bounds the devices read and written in one round trip.
+const tagChunk = 500
+
+// TagRepo manages the tags of devices fleet-wide, through the taglist array
+// device documents carry.
+type TagRepo struct {
+	col *mongo.Collection
+}
+
+func NewTagRepo(db *mongo.Database) *TagRepo {
+	return &TagRepo{col: db.Collection(CollectionDevices)}
+}
+
+// GetCounts lists the tags of the live devices, with how many carry each.
+func (r *TagRepo) GetCounts(ctx context.Context, tenantID string) ([]entity.Tag, error) {
+	if tenantID != "" && !identifierRegex.MatchString(tenantID) {
+		return []entity.Tag{}, nil
+	}
+
+	cur, err := r.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}}},
+		{{Key: "$unwind", Value: "$" + fieldTagList}},
+		{{Key: "$group", Value: bson.M{"_id": "$" + fieldTagList, "devices": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	})
+	if err != nil {
+		return nil, errTagDatabase.Wrap("GetCounts", "Aggregate", err)
+	}
+	defer cur.Close(ctx)
+
+	tags := make([]entity.Tag, 0)
+	if err := cur.All(ctx, &tags); err != nil {
+		return nil, errTagDatabase.Wrap("GetCounts", "Cursor.All", err)
+	}
+
+	return tags, nil
+}
+
+// GetGUIDs returns the GUIDs of the devices carrying any of tags, sorted.
+// Devices in the trash are included, so that a fleet-wide change reaches
+// them too.
+func (r *TagRepo) GetGUIDs(ctx context.Context, tags []string, tenantID string) ([]string, error) {
+	if len(tags) == 0 || tenantID != "" && !identifierRegex.MatchString(tenantID) {
+		return []string{}, nil
+	}
+
+	guids := []string{}
+
+	err := r.col.Distinct(ctx, fieldGUID, bson.M{fieldTenantID: tenantID, fieldTagList: bson.M{"$in": tags}}).Decode(&guids)
+	if err != nil {
+		return nil, errTagDatabase.Wrap("GetGUIDs", "Distinct", err)
+	}
+
+	slices.Sort(guids)
+
+	return guids, nil
+}
+
+// GetTags returns the GUID and tags of each device of guids. Unknown GUIDs
+// are skipped.
+func (r *TagRepo) GetTags(ctx context.Context, guids []string, tenantID string) ([]entity.Device, error) {
+	devices := []entity.Device{}
+
+	if tenantID != "" && !identifierRegex.MatchString(tenantID) {
+		return devices, nil
+	}
+
+	for chunk := range slices.Chunk(guids, tagChunk) {
+		current, err := r.tagsOf(ctx, chunk, tenantID)
+		if err != nil {
+			return nil, errTagDatabase.Wrap("GetTags", "tagsOf", err)
+		}
+
+		devices = append(devices, current...)
+	}
+
+	return devices, nil
+}
+
+// Update removes the tags of remove from the devices of guids and adds those
+// of add, and returns how many devices changed. Unknown GUIDs are skipped. A
+// changed device gets a new version.
+func (r *TagRepo) Update(ctx context.Context, guids, add, remove []string, tenantID string) (int, error) {
+	if tenantID != "" && !identifierRegex.MatchString(tenantID) {
+		return 0, nil
+	}
+
+	changed := 0
+
+	for chunk := range slices.Chunk(guids, tagChunk) {
+		n, err := r.update(ctx, chunk, add, remove, tenantID)
+		if err != nil {
+			return changed, errTagDatabase.Wrap("Update", "update", err)
+		}
+
+		changed += n
+	}
+
+	return changed, nil
+}
+
+// update applies add and remove to one chunk of devices.
+func (r *TagRepo) update(ctx context.Context, guids, add, remove []string, tenantID string) (int, error) {
+	current, err := r.tagsOf(ctx, guids, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	models := []mongo.WriteModel{}
+
+	for _, d := range current {
+		tags := taglist.Split(d.Tags)
+
+		updated := taglist.Apply(tags, add, remove)
+		if slices.Equal(tags, updated) {
+			continue
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{fieldGUID: d.GUID, fieldTenantID: tenantID}).
+			SetUpdate(bson.M{opSet: bson.M{
+				fieldTags:    taglist.Join(updated),
+				fieldTagList: updated,
+				fieldVersion: versions.New(),
+			}}))
+	}
+
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	res, err := r.col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.MatchedCount), nil
+}
+
+// tagsOf reads the tags of one chunk of devices.
+func (r *TagRepo) tagsOf(ctx context.Context, guids []string, tenantID string) ([]entity.Device, error) {
+	cur, err := r.col.Find(ctx,
+		bson.M{fieldGUID: bson.M{"$in": guids}, fieldTenantID: tenantID},
+		options.Find().SetProjection(bson.M{fieldGUID: 1, fieldTags: 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	current := []entity.Device{}
+	if err := cur.All(ctx, &current); err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+// backfillTagLists gives devices written before tags were kept as an array
+// the taglist their tags split into, as sqldb's migration fills device_tags.
+func backfillTagLists(ctx context.Context, db *mongo.Database) error {
+	// Split, trim, and drop empty and repeated tags, as taglist.Split does;
+	// the order of taglist does not matter to queries.
+	split := bson.M{"$setUnion": bson.A{bson.M{"$filter": bson.M{
+		"input": bson.M{"$map": bson.M{
+			"input": bson.M{"$split": bson.A{bson.M{"$ifNull": bson.A{"$" + fieldTags, ""}}, ","}},
+			"as":    "tag",
+			"in":    bson.M{"$trim": bson.M{"input": "$$tag"}},
+		}},
+		"as":   "tag",
+		"cond": bson.M{"$ne": bson.A{"$$tag", ""}},
+	}}}}
+
+	_, err := db.Collection(CollectionDevices).UpdateMany(ctx,
+		bson.M{fieldTagList: bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: opSet, Value: bson.M{fieldTagList: split}}}},
+	)
+
+	return err
+}
@@ -0,0 +1,68 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	mongo "github.com/device-management-toolkit/console/internal/usecase/nosqldb/mongo"
+)
+
+func TestTagRepo_GetCounts(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse(
+		"testdb."+mongo.CollectionDevices,
+		bson.D{{Key: "_id", Value: "lab"}, {Key: "devices", Value: int32(2)}},
+		bson.D{{Key: "_id", Value: "site-a"}, {Key: "devices", Value: int32(1)}},
+	))
+
+	tags, err := mongo.NewTagRepo(db).GetCounts(context.Background(), "t1")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Tag{{Name: "lab", Devices: 2}, {Name: "site-a", Devices: 1}}, tags)
+}
+
+func TestTagRepo_GetGUIDs(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(distinctResponse("g2", "g1"))
+
+	repo := mongo.NewTagRepo(db)
+
+	guids, err := repo.GetGUIDs(context.Background(), []string{"lab"}, "t1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"g1", "g2"}, guids)
+
+	// No tags, no query.
+	guids, err = repo.GetGUIDs(context.Background(), nil, "t1")
+	require.NoError(t, err)
+	require.Empty(t, guids)
+}
+
+// Update writes only the devices whose tags change: g2 already carries the
+// tag added and none of those removed.
+func TestTagRepo_Update(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(
+		findResponse(
+			"testdb."+mongo.CollectionDevices,
+			bson.D{{Key: "guid", Value: "g1"}, {Key: "tags", Value: "lab,site-a"}},
+			bson.D{{Key: "guid", Value: "g2"}, {Key: "tags", Value: "bench"}},
+		),
+		updateResponse(1),
+	)
+
+	changed, err := mongo.NewTagRepo(db).Update(context.Background(), []string{"g1", "g2"}, []string{"bench"}, []string{"lab"}, "t1")
+	require.NoError(t, err)
+	require.Equal(t, 1, changed)
+}
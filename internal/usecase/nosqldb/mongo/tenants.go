@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"context"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// tenantCollections are the collections of tenant entities. Those of
+// documents that belong to one of them add no tenant of their own.
+var tenantCollections = []string{
+	CollectionDevices,
+	CollectionProfiles,
+	CollectionCIRAConfigs,
+	CollectionWirelessConfigs,
+	CollectionIEEE8021xConfigs,
+	CollectionDomains,
+	CollectionUsers,
+	CollectionAPIKeys,
+	CollectionAccessPolicies,
+	CollectionDeviceGroups,
+}
+
+// TenantRepo lists the tenants that own documents.
+type TenantRepo struct {
+	db *mongo.Database
+}
+
+func NewTenantRepo(db *mongo.Database) *TenantRepo {
+	return &TenantRepo{db: db}
+}
+
+// Get lists the tenants of the documents of every collection, those in the
+// trash included.
+func (r *TenantRepo) Get(ctx context.Context) ([]string, error) {
+	tenants := []string{}
+
+	for _, collection := range tenantCollections {
+		var found []string
+
+		if err := r.db.Collection(collection).Distinct(ctx, fieldTenantID, bson.M{}).Decode(&found); err != nil {
+			return nil, errTenantDatabase.Wrap("Get", "Distinct "+collection, err)
+		}
+
+		tenants = append(tenants, found...)
+	}
+
+	slices.Sort(tenants)
+
+	return slices.Compact(tenants), nil
+}
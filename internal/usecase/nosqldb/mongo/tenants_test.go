@@ -0,0 +1,34 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mongo "github.com/device-management-toolkit/console/internal/usecase/nosqldb/mongo"
+)
+
+func TestTenantRepo_Get(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	// One distinct query per collection, in order.
+	md.AddResponses(
+		distinctResponse("t2", ""),
+		distinctResponse("t1"),
+		distinctResponse(),
+		distinctResponse(),
+		distinctResponse(),
+		distinctResponse("t1"),
+		distinctResponse("", "t3"),
+		distinctResponse(),
+		distinctResponse(),
+		distinctResponse(),
+	)
+
+	tenants, err := mongo.NewTenantRepo(db).Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "t1", "t2", "t3"}, tenants)
+}
@@ -0,0 +1,146 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/tokens"
+)
+
+type TokenRepo struct {
+	revoked *mongo.Collection
+	refresh *mongo.Collection
+}
+
+var _ tokens.Repository = (*TokenRepo)(nil)
+
+func NewTokenRepo(db *mongo.Database) *TokenRepo {
+	return &TokenRepo{
+		revoked: db.Collection(CollectionRevokedTokens),
+		refresh: db.Collection(CollectionRefreshTokens),
+	}
+}
+
+// Revoke upserts, so listing an ID again keeps the first entry, like SQL's
+// ON CONFLICT DO NOTHING.
+func (r *TokenRepo) Revoke(ctx context.Context, t *entity.RevokedToken) error {
+	_, err := r.revoked.UpdateOne(ctx,
+		bson.M{fieldID: t.ID},
+		bson.M{"$setOnInsert": t},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil && !isDuplicateKey(err) {
+		return errTokenDatabase.Wrap("Revoke", "UpdateOne", err)
+	}
+
+	return nil
+}
+
+func (r *TokenRepo) IsRevoked(ctx context.Context, ids []string) (bool, error) {
+	n, err := r.revoked.CountDocuments(ctx, bson.M{fieldID: bson.M{"$in": ids}}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, errTokenDatabase.Wrap("IsRevoked", "CountDocuments", err)
+	}
+
+	return n > 0, nil
+}
+
+func (r *TokenRepo) GetRevoked(ctx context.Context, top, skip int) ([]entity.RevokedToken, error) {
+	limit := int64(DefaultTop)
+	if top > 0 {
+		limit = int64(top)
+	}
+
+	offset := int64(0)
+	if skip > 0 {
+		offset = int64(skip)
+	}
+
+	cur, err := r.revoked.Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: fieldID, Value: 1}}).SetLimit(limit).SetSkip(offset))
+	if err != nil {
+		return nil, errTokenDatabase.Wrap("GetRevoked", "Find", err)
+	}
+	defer cur.Close(ctx)
+
+	out := make([]entity.RevokedToken, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, errTokenDatabase.Wrap("GetRevoked", "Cursor.All", err)
+	}
+
+	return out, nil
+}
+
+func (r *TokenRepo) InsertRefreshToken(ctx context.Context, t *entity.RefreshToken) error {
+	if _, err := r.refresh.InsertOne(ctx, t); err != nil {
+		return errTokenDatabase.Wrap("InsertRefreshToken", "InsertOne", err)
+	}
+
+	return nil
+}
+
+func (r *TokenRepo) GetRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	t := entity.RefreshToken{}
+
+	err := r.refresh.FindOne(ctx, bson.M{fieldTokenHash: tokenHash}).Decode(&t)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		return nil, errTokenDatabase.Wrap("GetRefreshToken", "FindOne", err)
+	}
+
+	return &t, nil
+}
+
+func (r *TokenRepo) MarkRefreshTokenUsed(ctx context.Context, tokenHash string) (bool, error) {
+	res, err := r.refresh.UpdateOne(ctx,
+		bson.M{fieldTokenHash: tokenHash, "used": false},
+		bson.M{opSet: bson.M{"used": true}},
+	)
+	if err != nil {
+		return false, errTokenDatabase.Wrap("MarkRefreshTokenUsed", "UpdateOne", err)
+	}
+
+	return res.ModifiedCount > 0, nil
+}
+
+func (r *TokenRepo) GetRefreshTokensByUser(ctx context.Context, username, tenantID string) ([]entity.RefreshToken, error) {
+	cur, err := r.refresh.Find(ctx, bson.M{fieldUsername: username, fieldTenantID: tenantID},
+		options.Find().SetSort(bson.D{{Key: fieldCreationDate, Value: 1}}).SetCollation(caseInsensitive))
+	if err != nil {
+		return nil, errTokenDatabase.Wrap("GetRefreshTokensByUser", "Find", err)
+	}
+	defer cur.Close(ctx)
+
+	out := make([]entity.RefreshToken, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, errTokenDatabase.Wrap("GetRefreshTokensByUser", "Cursor.All", err)
+	}
+
+	return out, nil
+}
+
+func (r *TokenRepo) DeleteSession(ctx context.Context, sessionID string) error {
+	if _, err := r.refresh.DeleteMany(ctx, bson.M{fieldSessionID: sessionID}); err != nil {
+		return errTokenDatabase.Wrap("DeleteSession", "DeleteMany", err)
+	}
+
+	return nil
+}
+
+func (r *TokenRepo) Prune(ctx context.Context, now string) error {
+	for _, col := range []*mongo.Collection{r.revoked, r.refresh} {
+		if _, err := col.DeleteMany(ctx, bson.M{fieldExpiresAt: bson.M{"$lt": now}}); err != nil {
+			return errTokenDatabase.Wrap("Prune", "DeleteMany", err)
+		}
+	}
+
+	return nil
+}
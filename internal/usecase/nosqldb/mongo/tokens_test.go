@@ -0,0 +1,121 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	mongo "github.com/device-management-toolkit/console/internal/usecase/nosqldb/mongo"
+)
+
+func TestTokenRepo_GetRefreshToken_Found(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse(
+		"testdb."+mongo.CollectionRefreshTokens,
+		bson.D{
+			{Key: "tokenhash", Value: "hash-1"},
+			{Key: "sessionid", Value: "session-1"},
+			{Key: "username", Value: "jdoe"},
+			{Key: "used", Value: true},
+		},
+	))
+
+	repo := mongo.NewTokenRepo(db)
+
+	got, err := repo.GetRefreshToken(context.Background(), "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "session-1", got.SessionID)
+	require.True(t, got.Used)
+}
+
+func TestTokenRepo_GetRefreshToken_Missing(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse("testdb." + mongo.CollectionRefreshTokens))
+
+	repo := mongo.NewTokenRepo(db)
+
+	got, err := repo.GetRefreshToken(context.Background(), "hash-1")
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestTokenRepo_InsertRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(insertResponse())
+
+	repo := mongo.NewTokenRepo(db)
+
+	require.NoError(t, repo.InsertRefreshToken(context.Background(), &entity.RefreshToken{TokenHash: "hash-1"}))
+}
+
+func TestTokenRepo_Revoke(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(updateResponse(0))
+
+	repo := mongo.NewTokenRepo(db)
+
+	require.NoError(t, repo.Revoke(context.Background(), &entity.RevokedToken{ID: "jti-1"}))
+}
+
+func TestTokenRepo_GetRevoked(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse(
+		"testdb."+mongo.CollectionRevokedTokens,
+		bson.D{{Key: "id", Value: "jti-1"}, {Key: "subject", Value: "jdoe"}},
+	))
+
+	repo := mongo.NewTokenRepo(db)
+
+	got, err := repo.GetRevoked(context.Background(), 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []entity.RevokedToken{{ID: "jti-1", Subject: "jdoe"}}, got)
+}
+
+func TestTokenRepo_MarkRefreshTokenUsed(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(updateResponse(1), updateResponse(0))
+
+	repo := mongo.NewTokenRepo(db)
+
+	marked, err := repo.MarkRefreshTokenUsed(context.Background(), "hash-1")
+	require.NoError(t, err)
+	require.True(t, marked)
+
+	marked, err = repo.MarkRefreshTokenUsed(context.Background(), "hash-1")
+	require.NoError(t, err)
+	require.False(t, marked)
+}
+
+func TestTokenRepo_DeleteSession(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(deleteResponse(2))
+
+	repo := mongo.NewTokenRepo(db)
+
+	require.NoError(t, repo.DeleteSession(context.Background(), "session-1"))
+}
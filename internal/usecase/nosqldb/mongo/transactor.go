@@ -0,0 +1,34 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Transactor runs repository calls in one multi-document transaction, the
+// counterpart of db.SQL.InTx. Mongo only has transactions on replica sets
+// and sharded clusters; on a standalone server InTx fails without writing.
+type Transactor struct {
+	client *mongo.Client
+}
+
+func NewTransactor(db *mongo.Database) *Transactor {
+	return &Transactor{client: db.Client()}
+}
+
+// InTx runs fn in a transaction. The driver carries the session in the ctx
+// fn is given, so repository calls made with it join the transaction.
+func (t *Transactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	return t.client.UseSession(ctx, func(ctx context.Context) error {
+		_, err := mongo.SessionFromContext(ctx).WithTransaction(ctx, func(ctx context.Context) (any, error) {
+			return nil, fn(ctx)
+		})
+
+		return err
+	})
+}
@@ -0,0 +1,223 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+)
+
+var errUnknownTrashKind = errors.New("unknown kind of entity")
+
+// trashCollection tells where the entities of one kind live. Name is the
+// field listed beside the ID; links are collections of documents keyed like
+// the entity that are purged with it.
+type trashCollection struct {
+	collection, id, name string
+	links                []string
+}
+
+var trashCollections = map[string]trashCollection{
+	entity.TrashDevices:          {collection: CollectionDevices, id: fieldGUID, name: "hostname", links: []string{CollectionDeviceGroupMembers}},
+	entity.TrashProfiles:         {collection: CollectionProfiles, id: fieldProfileName, name: fieldProfileName, links: []string{CollectionProfileWiFiConfigs}},
+	entity.TrashCIRAConfigs:      {collection: CollectionCIRAConfigs, id: fieldConfigName, name: fieldConfigName},
+	entity.TrashWirelessConfigs:  {collection: CollectionWirelessConfigs, id: fieldProfileName, name: fieldProfileName},
+	entity.TrashIEEE8021xConfigs: {collection: CollectionIEEE8021xConfigs, id: fieldProfileName, name: fieldProfileName},
+	entity.TrashDomains:          {collection: CollectionDomains, id: fieldProfileName, name: fieldDomainSuffix},
+}
+
+// inTrash matches the documents with a deletedat.
+var inTrash = bson.M{"$ne": nil}
+
+type TrashRepo struct {
+	db *mongo.Database
+}
+
+var _ trash.Repository = (*TrashRepo)(nil)
+
+func NewTrashRepo(db *mongo.Database) *TrashRepo {
+	return &TrashRepo{db: db}
+}
+
+func (r *TrashRepo) GetCount(ctx context.Context, kind, tenantID string) (int, error) {
+	c, ok := trashCollections[kind]
+	if !ok {
+		return 0, errTrashDatabase.Wrap("GetCount", "trashCollections", errUnknownTrashKind)
+	}
+
+	if tenantID != "" && !identifierRegex.MatchString(tenantID) {
+		return 0, nil
+	}
+
+	n, err := r.db.Collection(c.collection).CountDocuments(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: inTrash})
+	if err != nil {
+		return 0, errTrashDatabase.Wrap("GetCount", "CountDocuments", err)
+	}
+
+	return int(n), nil
+}
+
+// Get lists the entities of a kind in the trash, latest deleted first.
+func (r *TrashRepo) Get(ctx context.Context, kind string, top, skip int, tenantID string) ([]entity.TrashItem, error) {
+	c, ok := trashCollections[kind]
+	if !ok {
+		return nil, errTrashDatabase.Wrap("Get", "trashCollections", errUnknownTrashKind)
+	}
+
+	if tenantID != "" && !identifierRegex.MatchString(tenantID) {
+		return []entity.TrashItem{}, nil
+	}
+
+	limit := int64(DefaultTop)
+	if top > 0 {
+		limit = int64(top)
+	}
+
+	offset := int64(0)
+	if skip > 0 {
+		offset = int64(skip)
+	}
+
+	items, err := r.find(ctx, kind, c, bson.M{fieldTenantID: tenantID, fieldDeletedAt: inTrash},
+		options.Find().
+			SetSort(bson.D{{Key: fieldDeletedAt, Value: -1}, {Key: c.id, Value: 1}}).
+			SetLimit(limit).
+			SetSkip(offset))
+	if err != nil {
+		return nil, errTrashDatabase.Wrap("Get", "Find", err)
+	}
+
+	return items, nil
+}
+
+// GetExpired lists the entities of a kind deleted before deletedBefore, in
+// every tenant, oldest first.
+func (r *TrashRepo) GetExpired(ctx context.Context, kind, deletedBefore string, top, skip int) ([]entity.TrashItem, error) {
+	c, ok := trashCollections[kind]
+	if !ok {
+		return nil, errTrashDatabase.Wrap("GetExpired", "trashCollections", errUnknownTrashKind)
+	}
+
+	offset := int64(0)
+	if skip > 0 {
+		offset = int64(skip)
+	}
+
+	items, err := r.find(ctx, kind, c, bson.M{fieldDeletedAt: bson.M{"$ne": nil, "$lt": deletedBefore}},
+		options.Find().
+			SetSort(bson.D{{Key: fieldDeletedAt, Value: 1}, {Key: c.id, Value: 1}}).
+			SetLimit(int64(max(top, 1))).
+			SetSkip(offset))
+	if err != nil {
+		return nil, errTrashDatabase.Wrap("GetExpired", "Find", err)
+	}
+
+	return items, nil
+}
+
+func (r *TrashRepo) find(ctx context.Context, kind string, c trashCollection, filter bson.M, opts *options.FindOptionsBuilder) ([]entity.TrashItem, error) {
+	opts.SetProjection(bson.M{c.id: 1, c.name: 1, fieldTenantID: 1, fieldDeletedAt: 1})
+
+	cur, err := r.db.Collection(c.collection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var docs []bson.M
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	items := make([]entity.TrashItem, 0, len(docs))
+
+	for _, doc := range docs {
+		item := entity.TrashItem{Kind: kind}
+		item.ID, _ = doc[c.id].(string)
+		item.Name, _ = doc[c.name].(string)
+		item.TenantID, _ = doc[fieldTenantID].(string)
+		item.DeletedAt, _ = doc[fieldDeletedAt].(string)
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// Restore takes the entity out of the trash. Mongo keeps no references, so
+// unlike sqldb it does not check those of the entity are live.
+func (r *TrashRepo) Restore(ctx context.Context, kind, id, tenantID string) (bool, error) {
+	c, ok := trashCollections[kind]
+	if !ok {
+		return false, errTrashDatabase.Wrap("Restore", "trashCollections", errUnknownTrashKind)
+	}
+
+	if !identifierRegex.MatchString(id) || (tenantID != "" && !identifierRegex.MatchString(tenantID)) {
+		return false, nil
+	}
+
+	res, err := r.db.Collection(c.collection).UpdateOne(ctx,
+		bson.M{c.id: id, fieldTenantID: tenantID, fieldDeletedAt: inTrash},
+		bson.M{"$unset": bson.M{fieldDeletedAt: ""}},
+	)
+	if err != nil {
+		return false, errTrashDatabase.Wrap("Restore", "UpdateOne", err)
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+// MoveToTrash moves a live entity to the trash as deleted at deletedAt.
+func (r *TrashRepo) MoveToTrash(ctx context.Context, kind, id, tenantID, deletedAt string) (bool, error) {
+	c, ok := trashCollections[kind]
+	if !ok {
+		return false, errTrashDatabase.Wrap("MoveToTrash", "trashCollections", errUnknownTrashKind)
+	}
+
+	if !identifierRegex.MatchString(id) || (tenantID != "" && !identifierRegex.MatchString(tenantID)) {
+		return false, nil
+	}
+
+	res, err := r.db.Collection(c.collection).UpdateOne(ctx,
+		bson.M{c.id: id, fieldTenantID: tenantID, fieldDeletedAt: nil},
+		bson.M{opSet: bson.M{fieldDeletedAt: deletedAt}},
+	)
+	if err != nil {
+		return false, errTrashDatabase.Wrap("MoveToTrash", "UpdateOne", err)
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+// Purge deletes the entity in the trash for good, then its links.
+func (r *TrashRepo) Purge(ctx context.Context, kind, id, tenantID string) (bool, error) {
+	c, ok := trashCollections[kind]
+	if !ok {
+		return false, errTrashDatabase.Wrap("Purge", "trashCollections", errUnknownTrashKind)
+	}
+
+	if !identifierRegex.MatchString(id) || (tenantID != "" && !identifierRegex.MatchString(tenantID)) {
+		return false, nil
+	}
+
+	res, err := r.db.Collection(c.collection).DeleteOne(ctx, bson.M{c.id: id, fieldTenantID: tenantID, fieldDeletedAt: inTrash})
+	if err != nil {
+		return false, errTrashDatabase.Wrap("Purge", "DeleteOne", err)
+	}
+
+	if res.DeletedCount == 0 {
+		return false, nil
+	}
+
+	for _, link := range c.links {
+		if _, err := r.db.Collection(link).DeleteMany(ctx, bson.M{c.id: id, fieldTenantID: tenantID}); err != nil {
+			return true, errTrashDatabase.Wrap("Purge", "DeleteMany", err)
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,103 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	mongo "github.com/device-management-toolkit/console/internal/usecase/nosqldb/mongo"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func TestTrashRepo_Get(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse(
+		"testdb."+mongo.CollectionDevices,
+		bson.D{
+			{Key: "guid", Value: "guid"},
+			{Key: "hostname", Value: "host"},
+			{Key: "tenantid", Value: "tenant"},
+			{Key: "deletedat", Value: "2026-10-19T00:00:00Z"},
+		},
+	))
+
+	repo := mongo.NewTrashRepo(db)
+
+	items, err := repo.Get(context.Background(), entity.TrashDevices, 10, 0, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, []entity.TrashItem{{Kind: entity.TrashDevices, ID: "guid", Name: "host", TenantID: "tenant", DeletedAt: "2026-10-19T00:00:00Z"}}, items)
+
+	_, err = repo.Get(context.Background(), "users", 10, 0, "tenant")
+	require.Error(t, err)
+}
+
+func TestTrashRepo_GetCount(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse("testdb."+mongo.CollectionProfiles, bson.D{{Key: "n", Value: int32(2)}}))
+
+	count, err := mongo.NewTrashRepo(db).GetCount(context.Background(), entity.TrashProfiles, "")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func TestTrashRepo_Restore(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(updateResponse(1), updateResponse(0))
+
+	repo := mongo.NewTrashRepo(db)
+
+	restored, err := repo.Restore(context.Background(), entity.TrashCIRAConfigs, "cira", "")
+	require.NoError(t, err)
+	require.True(t, restored)
+
+	restored, err = repo.Restore(context.Background(), entity.TrashCIRAConfigs, "cira", "")
+	require.NoError(t, err)
+	require.False(t, restored, "not in the trash")
+
+	restored, err = repo.Restore(context.Background(), entity.TrashCIRAConfigs, `{"$ne":""}`, "")
+	require.NoError(t, err)
+	require.False(t, restored)
+}
+
+func TestTrashRepo_Purge(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	// The profile, then its wifi config links.
+	md.AddResponses(deleteResponse(1), deleteResponse(2), deleteResponse(0))
+
+	repo := mongo.NewTrashRepo(db)
+
+	purged, err := repo.Purge(context.Background(), entity.TrashProfiles, "acm", "")
+	require.NoError(t, err)
+	require.True(t, purged)
+
+	purged, err = repo.Purge(context.Background(), entity.TrashProfiles, "acm", "")
+	require.NoError(t, err)
+	require.False(t, purged)
+}
+
+func TestInsertPurgesTrashedEntityWithSameKey(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	// The trashed profile, its wireless links, then the new profile.
+	md.AddResponses(deleteResponse(1), deleteResponse(2), insertResponse())
+
+	_, err := mongo.NewProfileRepo(db, logger.New("error")).Insert(context.Background(), &entity.Profile{ProfileName: "acm", TenantID: "tenant"})
+	require.NoError(t, err)
+}
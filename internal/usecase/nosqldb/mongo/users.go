@@ -0,0 +1,137 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/usecase/users"
+)
+
+type UserRepo struct {
+	col *mongo.Collection
+}
+
+var _ users.Repository = (*UserRepo)(nil)
+
+// caseInsensitive matches usernames the way SQL's LOWER(username) = LOWER(?)
+// does, and lets lookups use lower_username_idx.
+var caseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
+func NewUserRepo(db *mongo.Database) *UserRepo {
+	return &UserRepo{col: db.Collection(CollectionUsers)}
+}
+
+func (r *UserRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	n, err := r.col.CountDocuments(ctx, bson.M{fieldTenantID: tenantID})
+	if err != nil {
+		return 0, errUserDatabase.Wrap("GetCount", "CountDocuments", err)
+	}
+
+	return int(n), nil
+}
+
+func (r *UserRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.User, error) {
+	limit := int64(DefaultTop)
+	if top > 0 {
+		limit = int64(top)
+	}
+
+	offset := int64(0)
+	if skip > 0 {
+		offset = int64(skip)
+	}
+
+	cur, err := r.col.Find(ctx, bson.M{fieldTenantID: tenantID},
+		options.Find().SetSort(bson.D{{Key: fieldUsername, Value: 1}}).SetLimit(limit).SetSkip(offset))
+	if err != nil {
+		return nil, errUserDatabase.Wrap("Get", "Find", err)
+	}
+	defer cur.Close(ctx)
+
+	out := make([]entity.User, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, errUserDatabase.Wrap("Get", "Cursor.All", err)
+	}
+
+	return out, nil
+}
+
+// GetByUsername searches every tenant, as usernames are unique across them
+// and login names no tenant.
+func (r *UserRepo) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	if !usernameRegex.MatchString(username) {
+		return nil, nil
+	}
+
+	u := entity.User{}
+
+	err := r.col.FindOne(ctx, bson.M{fieldUsername: username},
+		options.FindOne().SetCollation(caseInsensitive)).Decode(&u)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		return nil, errUserDatabase.Wrap("GetByUsername", "FindOne", err)
+	}
+
+	return &u, nil
+}
+
+func (r *UserRepo) Delete(ctx context.Context, username, tenantID string) (bool, error) {
+	if !usernameRegex.MatchString(username) {
+		return false, nil
+	}
+
+	res, err := r.col.DeleteOne(ctx, bson.M{fieldUsername: username, fieldTenantID: tenantID},
+		options.DeleteOne().SetCollation(caseInsensitive))
+	if err != nil {
+		return false, errUserDatabase.Wrap("Delete", "DeleteOne", err)
+	}
+
+	return res.DeletedCount > 0, nil
+}
+
+func (r *UserRepo) Update(ctx context.Context, u *entity.User) (bool, error) {
+	if !usernameRegex.MatchString(u.Username) {
+		return false, errUserDatabase.Wrap("Update", "validate", nil)
+	}
+
+	// username, creation and tenant fields are immutable, as in SQL.
+	res, err := r.col.UpdateOne(ctx,
+		bson.M{fieldUsername: u.Username, fieldTenantID: u.TenantID},
+		bson.M{opSet: bson.M{
+			"passwordhash":       u.PasswordHash,
+			"role":               u.Role,
+			"mustchangepassword": u.MustChangePassword,
+			"passwordchangedat":  u.PasswordChangedAt,
+		}},
+		options.UpdateOne().SetCollation(caseInsensitive),
+	)
+	if err != nil {
+		return false, errUserDatabase.Wrap("Update", "UpdateOne", err)
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+func (r *UserRepo) Insert(ctx context.Context, u *entity.User) (string, error) {
+	if !usernameRegex.MatchString(u.Username) {
+		return "", errUserDatabase.Wrap("Insert", "validate", nil)
+	}
+
+	if _, err := r.col.InsertOne(ctx, u); err != nil {
+		if isDuplicateKey(err) {
+			return "", errUserNotUnique.Wrap(err.Error())
+		}
+
+		return "", errUserDatabase.Wrap("Insert", "InsertOne", err)
+	}
+
+	return "", nil
+}
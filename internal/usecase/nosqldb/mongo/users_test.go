@@ -0,0 +1,129 @@
+package mongo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	mongo "github.com/device-management-toolkit/console/internal/usecase/nosqldb/mongo"
+)
+
+func TestUserRepo_GetByUsername_Found(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse(
+		"testdb."+mongo.CollectionUsers,
+		bson.D{
+			{Key: "username", Value: "Alice"},
+			{Key: "passwordhash", Value: "$2a$10$hash"},
+			{Key: "role", Value: "operator"},
+			{Key: "mustchangepassword", Value: true},
+		},
+	))
+
+	repo := mongo.NewUserRepo(db)
+
+	got, err := repo.GetByUsername(context.Background(), "alice")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "Alice", got.Username)
+	require.Equal(t, "operator", got.Role)
+	require.True(t, got.MustChangePassword)
+}
+
+func TestUserRepo_GetByUsername_NotFound(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse("testdb." + mongo.CollectionUsers))
+
+	repo := mongo.NewUserRepo(db)
+
+	got, err := repo.GetByUsername(context.Background(), "ghost")
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestUserRepo_GetByUsername_RejectsOperator(t *testing.T) {
+	t.Parallel()
+
+	db, _ := newMockedDB(t)
+
+	repo := mongo.NewUserRepo(db)
+
+	got, err := repo.GetByUsername(context.Background(), `{"$ne":""}`)
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestUserRepo_Get_ReturnsRows(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(findResponse(
+		"testdb."+mongo.CollectionUsers,
+		bson.D{{Key: "username", Value: "alice"}},
+		bson.D{{Key: "username", Value: "bob"}},
+	))
+
+	repo := mongo.NewUserRepo(db)
+
+	rows, err := repo.Get(context.Background(), 10, 0, "")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, "alice", rows[0].Username)
+	require.Equal(t, "bob", rows[1].Username)
+}
+
+func TestUserRepo_Insert_DuplicateReturnsNotUniqueError(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(duplicateKeyResponse())
+
+	repo := mongo.NewUserRepo(db)
+
+	_, err := repo.Insert(context.Background(), &entity.User{Username: "alice"})
+	require.Error(t, err)
+
+	var nu repoerrors.NotUniqueError
+	require.True(t, errors.As(err, &nu))
+}
+
+func TestUserRepo_Update(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(updateResponse(1))
+
+	repo := mongo.NewUserRepo(db)
+
+	ok, err := repo.Update(context.Background(), &entity.User{Username: "alice", Role: "admin"})
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestUserRepo_Delete_NoMatch(t *testing.T) {
+	t.Parallel()
+
+	db, md := newMockedDB(t)
+
+	md.AddResponses(deleteResponse(0))
+
+	repo := mongo.NewUserRepo(db)
+
+	ok, err := repo.Delete(context.Background(), "ghost", "")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -10,3 +10,7 @@ var identifierRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 
 // domainSuffixRegex permits the dot for DNS suffixes; same inline rule.
 var domainSuffixRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// usernameRegex also permits the dot and @ for e-mail style usernames; same
+// inline rule.
+var usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9._@-]+$`)
@@ -0,0 +1,83 @@
+package mongo
+
+import (
+	"context"
+	"maps"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/device-management-toolkit/console/internal/versions"
+)
+
+// versionCondition is the filter on fieldVersion for the versions ctx
+// expects, or nil if it expects none.
+func versionCondition(ctx context.Context) bson.M {
+	expected, ok := versions.IfMatch(ctx)
+	if !ok {
+		return nil
+	}
+
+	return bson.M{"$in": expected}
+}
+
+// withIfMatch returns filter narrowed to the versions ctx expects, if any.
+// filter itself is left alone, so checkVersion can reuse it.
+func withIfMatch(ctx context.Context, filter bson.M) bson.M {
+	condition := versionCondition(ctx)
+	if condition == nil {
+		return filter
+	}
+
+	narrowed := bson.M{fieldVersion: condition}
+	maps.Copy(narrowed, filter)
+
+	return narrowed
+}
+
+// checkVersion tells why an update or delete narrowed by the If-Match
+// versions matched nothing: errVersionMismatch if a live document matches
+// filter at another version, nil if there is none.
+func checkVersion(ctx context.Context, col *mongo.Collection, name string, filter any) error {
+	if _, ok := versions.IfMatch(ctx); !ok {
+		return nil
+	}
+
+	n, err := col.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return err
+	}
+
+	if n > 0 {
+		return errVersionMismatch.Wrap(name + " has changed")
+	}
+
+	return nil
+}
+
+// versionedCollections hold entities updated under If-Match.
+var versionedCollections = []string{
+	CollectionDevices,
+	CollectionProfiles,
+	CollectionCIRAConfigs,
+	CollectionWirelessConfigs,
+	CollectionIEEE8021xConfigs,
+	CollectionDomains,
+}
+
+// backfillVersions gives documents written before versioning the same
+// initial version sqldb's migration gives existing rows.
+func backfillVersions(ctx context.Context, db *mongo.Database) error {
+	for _, name := range versionedCollections {
+		_, err := db.Collection(name).UpdateMany(ctx,
+			bson.M{fieldVersion: bson.M{"$in": bson.A{nil, ""}}},
+			bson.M{opSet: bson.M{fieldVersion: "1"}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/usecase/wificonfigs"
+	"github.com/device-management-toolkit/console/internal/versions"
 	"github.com/device-management-toolkit/console/pkg/logger"
 )
 
@@ -39,7 +40,7 @@ func (r *WirelessRepo) CheckProfileExists(ctx context.Context, profileName, tena
 		return false, nil
 	}
 
-	n, err := r.col.CountDocuments(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID},
+	n, err := r.col.CountDocuments(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID, fieldDeletedAt: nil},
 		options.Count().SetLimit(1))
 	if err != nil {
 		return false, errWiFiDatabase.Wrap("CheckProfileExists", "CountDocuments", err)
@@ -53,7 +54,12 @@ func (r *WirelessRepo) GetCount(ctx context.Context, tenantID string) (int, erro
 		return 0, nil
 	}
 
-	n, err := r.col.CountDocuments(ctx, bson.M{fieldTenantID: tenantID})
+	filter, err := odataFilter(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}, wirelessConfigODataFields)
+	if err != nil {
+		return 0, errWiFiDatabase.Wrap("GetCount", "odataFilter", err)
+	}
+
+	n, err := r.col.CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, errWiFiDatabase.Wrap("GetCount", "CountDocuments", err)
 	}
@@ -76,8 +82,17 @@ func (r *WirelessRepo) Get(ctx context.Context, top, skip int, tenantID string)
 		offset = int64(skip)
 	}
 
-	cur, err := r.col.Find(ctx, bson.M{fieldTenantID: tenantID},
-		options.Find().SetSort(bson.D{{Key: fieldProfileName, Value: 1}}).SetLimit(limit).SetSkip(offset))
+	filter, err := odataFilter(ctx, bson.M{fieldTenantID: tenantID, fieldDeletedAt: nil}, wirelessConfigODataFields)
+	if err != nil {
+		return nil, errWiFiDatabase.Wrap("Get", "odataFilter", err)
+	}
+
+	sort, err := odataSort(ctx, wirelessConfigODataFields, bson.D{{Key: fieldProfileName, Value: 1}})
+	if err != nil {
+		return nil, errWiFiDatabase.Wrap("Get", "odataSort", err)
+	}
+
+	cur, err := r.col.Find(ctx, filter, options.Find().SetSort(sort).SetLimit(limit).SetSkip(offset))
 	if err != nil {
 		return nil, errWiFiDatabase.Wrap("Get", "Find", err)
 	}
@@ -108,7 +123,7 @@ func (r *WirelessRepo) GetByName(ctx context.Context, profileName, tenantID stri
 
 	w := entity.WirelessConfig{}
 
-	err := r.col.FindOne(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID}).Decode(&w)
+	err := r.col.FindOne(ctx, byKey(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID})).Decode(&w)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, nil
@@ -162,12 +177,12 @@ func (r *WirelessRepo) Delete(ctx context.Context, profileName, tenantID string)
 		return false, nil
 	}
 
-	res, err := r.col.DeleteOne(ctx, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID})
+	deleted, err := softDelete(ctx, r.col, "wireless config "+profileName, bson.M{fieldProfileName: profileName, fieldTenantID: tenantID})
 	if err != nil {
-		return false, errWiFiDatabase.Wrap("Delete", "DeleteOne", err)
+		return false, errWiFiDatabase.Wrap("Delete", "softDelete", err)
 	}
 
-	return res.DeletedCount > 0, nil
+	return deleted, nil
 }
 
 func (r *WirelessRepo) Update(ctx context.Context, w *entity.WirelessConfig) (bool, error) {
@@ -179,8 +194,10 @@ func (r *WirelessRepo) Update(ctx context.Context, w *entity.WirelessConfig) (bo
 		return false, errWiFiDatabase.Wrap("Update", "validate", nil)
 	}
 
+	filter := bson.M{fieldProfileName: w.ProfileName, fieldTenantID: w.TenantID, fieldDeletedAt: nil}
+
 	res, err := r.col.UpdateOne(ctx,
-		bson.M{fieldProfileName: w.ProfileName, fieldTenantID: w.TenantID},
+		withIfMatch(ctx, filter),
 		bson.M{opSet: bson.M{
 			"authenticationmethod":    w.AuthenticationMethod,
 			"encryptionmethod":        w.EncryptionMethod,
@@ -189,12 +206,19 @@ func (r *WirelessRepo) Update(ctx context.Context, w *entity.WirelessConfig) (bo
 			"pskpassphrase":           w.PSKPassphrase,
 			"linkpolicy":              w.LinkPolicy,
 			fieldIEEE8021xProfileName: nullIfEmptyPtr(w.IEEE8021xProfileName),
+			fieldVersion:              versions.New(),
 		}},
 	)
 	if err != nil {
 		return false, errWiFiDatabase.Wrap("Update", "UpdateOne", err)
 	}
 
+	if res.MatchedCount == 0 {
+		if err := checkVersion(ctx, r.col, "wireless config "+w.ProfileName, filter); err != nil {
+			return false, err
+		}
+	}
+
 	return res.MatchedCount > 0, nil
 }
 
@@ -208,6 +232,7 @@ func (r *WirelessRepo) Insert(ctx context.Context, w *entity.WirelessConfig) (st
 	}
 
 	ieee := nullIfEmptyPtr(w.IEEE8021xProfileName)
+	version := versions.New()
 
 	doc := bson.M{
 		fieldProfileName:       w.ProfileName,
@@ -221,6 +246,11 @@ func (r *WirelessRepo) Insert(ctx context.Context, w *entity.WirelessConfig) (st
 		"creationdate":            time.Now().Format("2006-01-02 15:04:05"),
 		fieldTenantID:             w.TenantID,
 		fieldIEEE8021xProfileName: ieee,
+		fieldVersion:              version,
+	}
+
+	if err := purgeTrashed(ctx, r.col, entity.TrashWirelessConfigs, w.ProfileName, w.TenantID); err != nil {
+		return "", errWiFiDatabase.Wrap("Insert", "purgeTrashed", err)
 	}
 
 	if _, err := r.col.InsertOne(ctx, doc); err != nil {
@@ -231,5 +261,5 @@ func (r *WirelessRepo) Insert(ctx context.Context, w *entity.WirelessConfig) (st
 		return "", errWiFiDatabase.Wrap("Insert", "InsertOne", err)
 	}
 
-	return "", nil
+	return version, nil
 }
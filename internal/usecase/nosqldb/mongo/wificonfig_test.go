@@ -134,7 +134,7 @@ func TestWirelessRepo_Insert(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(insertResponse())
+	md.AddResponses(deleteResponse(0), insertResponse())
 
 	repo := mongo.NewWirelessRepo(db, logger.New("error"))
 
@@ -150,7 +150,7 @@ func TestWirelessRepo_Insert_DuplicateReturnsNotUniqueError(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(duplicateKeyResponse())
+	md.AddResponses(deleteResponse(0), duplicateKeyResponse())
 
 	repo := mongo.NewWirelessRepo(db, logger.New("error"))
 
@@ -186,7 +186,7 @@ func TestWirelessRepo_Delete(t *testing.T) {
 
 	db, md := newMockedDB(t)
 
-	md.AddResponses(deleteResponse(1))
+	md.AddResponses(updateResponse(1))
 
 	repo := mongo.NewWirelessRepo(db, logger.New("error"))
 
@@ -469,13 +469,9 @@ func (uc *UseCase) Export(ctx context.Context, profileName, domainName, tenantID
 	return encryptedYAML, encryptionKey, nil
 }
 
+// Delete moves the profile to the trash. Its wifi configs stay associated
+// with it, so that a restore brings them back too.
 func (uc *UseCase) Delete(ctx context.Context, profileName, tenantID string) error {
-	// remove all wifi configs associated with the profile
-	err := uc.profileWifiConfig.DeleteByProfileName(ctx, profileName, tenantID)
-	if err != nil {
-		return ErrDatabase.Wrap("Delete", "uc.repo.Delete", err)
-	}
-
 	isSuccessful, err := uc.repo.Delete(ctx, profileName, tenantID)
 	if err != nil {
 		return ErrDatabase.Wrap("Delete", "uc.repo.Delete", err)
@@ -651,6 +647,7 @@ func (uc *UseCase) insertProfileWifiConfigs(ctx context.Context, d *dto.Profile)
 	if len(d.WiFiConfigs) > 0 {
 		for _, wifiConfig := range d.WiFiConfigs {
 			wifiConfig.ProfileName = d.ProfileName
+			wifiConfig.TenantID = d.TenantID
 			tmpWifiConfig := wifiConfig // create a new variable to avoid memory aliasing
 
 			err := uc.profileWifiConfig.Insert(ctx, &tmpWifiConfig)
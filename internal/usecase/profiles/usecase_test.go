@@ -283,10 +283,7 @@ func TestDelete(t *testing.T) {
 			name:        "successful deletion",
 			profileName: "example-profile",
 			tenantID:    "tenant-id-456",
-			mock: func(repo *mocks.MockProfilesRepository, _ *mocks.MockWiFiConfigsRepository, profilewificonfigfeat *mocks.MockProfileWiFiConfigsFeature) {
-				profilewificonfigfeat.EXPECT().
-					DeleteByProfileName(context.Background(), "example-profile", "tenant-id-456").
-					Return(nil)
+			mock: func(repo *mocks.MockProfilesRepository, _ *mocks.MockWiFiConfigsRepository, _ *mocks.MockProfileWiFiConfigsFeature) {
 				repo.EXPECT().
 					Delete(context.Background(), "example-profile", "tenant-id-456").
 					Return(true, nil)
@@ -297,10 +294,7 @@ func TestDelete(t *testing.T) {
 			name:        "deletion fails - profile not found",
 			profileName: "nonexistent-profile",
 			tenantID:    "tenant-id-456",
-			mock: func(repo *mocks.MockProfilesRepository, _ *mocks.MockWiFiConfigsRepository, profilewificonfigfeat *mocks.MockProfileWiFiConfigsFeature) {
-				profilewificonfigfeat.EXPECT().
-					DeleteByProfileName(context.Background(), "nonexistent-profile", "tenant-id-456").
-					Return(nil)
+			mock: func(repo *mocks.MockProfilesRepository, _ *mocks.MockWiFiConfigsRepository, _ *mocks.MockProfileWiFiConfigsFeature) {
 				repo.EXPECT().
 					Delete(context.Background(), "nonexistent-profile", "tenant-id-456").
 					Return(false, nil)
@@ -552,7 +546,11 @@ func TestInsert(t *testing.T) {
 					Insert(context.Background(), profile).
 					Return("unique-profile-id", nil)
 				profilewificonfigfeat.EXPECT().
-					Insert(context.Background(), &profileDTO.WiFiConfigs[0]).
+					Insert(context.Background(), &dto.ProfileWiFiConfigs{
+						ProfileName:         "new-profile",
+						WirelessProfileName: "wireless-profile-1",
+						TenantID:            profileDTO.TenantID,
+					}).
 					Return(nil)
 				repo.EXPECT().
 					GetByName(context.Background(), profile.ProfileName, profile.TenantID).
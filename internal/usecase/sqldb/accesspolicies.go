@@ -0,0 +1,280 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/db"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// AccessPolicyRepo -.
+type AccessPolicyRepo struct {
+	*db.SQL
+	log logger.Interface
+}
+
+var (
+	ErrAccessPolicyDatabase  = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("AccessPolicyRepo")}
+	ErrAccessPolicyNotUnique = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("AccessPolicyRepo")}
+)
+
+var accessPolicyColumns = []string{
+	"id",
+	"name",
+	"subject",
+	"tags",
+	"method",
+	"permissions",
+	"creation_date",
+	"created_by",
+	"tenant_id",
+}
+
+// NewAccessPolicyRepo -.
+func NewAccessPolicyRepo(database *db.SQL, log logger.Interface) *AccessPolicyRepo {
+	return &AccessPolicyRepo{database, log}
+}
+
+// GetCount -.
+func (r *AccessPolicyRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select("COUNT(*)").
+		From("access_policies").
+		Where("tenant_id = ?", tenantID).
+		ToSql()
+	if err != nil {
+		return 0, ErrAccessPolicyDatabase.Wrap("GetCount", "r.Builder: ", err)
+	}
+
+	var count int
+
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
+	if err != nil {
+		return 0, ErrAccessPolicyDatabase.Wrap("GetCount", "r.Pool.QueryRow", err)
+	}
+
+	return count, nil
+}
+
+// Get -.
+func (r *AccessPolicyRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.AccessPolicy, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	const defaultTop = 100
+
+	if top == 0 {
+		top = defaultTop
+	}
+
+	limitedTop := uint64(defaultTop)
+	if top > 0 {
+		limitedTop = uint64(top)
+	}
+
+	limitedSkip := uint64(0)
+	if skip > 0 {
+		limitedSkip = uint64(skip)
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Select(accessPolicyColumns...).
+		From("access_policies").
+		Where("tenant_id = ?", tenantID).
+		OrderBy("name").
+		Limit(limitedTop).
+		Offset(limitedSkip).
+		ToSql()
+	if err != nil {
+		return nil, ErrAccessPolicyDatabase.Wrap("Get", "r.Builder: ", err)
+	}
+
+	return r.query(ctx, "Get", sqlQuery, args)
+}
+
+// GetByID -.
+func (r *AccessPolicyRepo) GetByID(ctx context.Context, id, tenantID string) (*entity.AccessPolicy, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select(accessPolicyColumns...).
+		From("access_policies").
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		ToSql()
+	if err != nil {
+		return nil, ErrAccessPolicyDatabase.Wrap("GetByID", "r.Builder: ", err)
+	}
+
+	p := entity.AccessPolicy{}
+
+	err = scanAccessPolicy(r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...), &p)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, ErrAccessPolicyDatabase.Wrap("GetByID", "row.Scan: ", err)
+	}
+
+	return &p, nil
+}
+
+// GetBySubjects returns the policies naming any of subjects.
+func (r *AccessPolicyRepo) GetBySubjects(ctx context.Context, subjects []string, tenantID string) ([]entity.AccessPolicy, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	if len(subjects) == 0 {
+		return []entity.AccessPolicy{}, nil
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Select(accessPolicyColumns...).
+		From("access_policies").
+		Where(squirrel.Eq{"tenant_id": tenantID, "subject": subjects}).
+		OrderBy("name").
+		ToSql()
+	if err != nil {
+		return nil, ErrAccessPolicyDatabase.Wrap("GetBySubjects", "r.Builder: ", err)
+	}
+
+	return r.query(ctx, "GetBySubjects", sqlQuery, args)
+}
+
+// Delete -.
+func (r *AccessPolicyRepo) Delete(ctx context.Context, id, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Delete("access_policies").
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		ToSql()
+	if err != nil {
+		return false, ErrAccessPolicyDatabase.Wrap("Delete", "r.Builder: ", err)
+	}
+
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return false, ErrAccessPolicyDatabase.Wrap("Delete", "r.Pool.Exec", err)
+	}
+
+	result, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("AccessPolicyRepo - Delete - r.Pool.Exec: %w", err)
+	}
+
+	return result > 0, nil
+}
+
+// Update replaces everything but the ID, creation date and creator.
+func (r *AccessPolicyRepo) Update(ctx context.Context, p *entity.AccessPolicy) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Update("access_policies").
+		Set("name", p.Name).
+		Set("subject", p.Subject).
+		Set("tags", p.Tags).
+		Set("method", p.Method).
+		Set("permissions", p.Permissions).
+		Where("id = ? AND tenant_id = ?", p.ID, p.TenantID).
+		ToSql()
+	if err != nil {
+		return false, ErrAccessPolicyDatabase.Wrap("Update", "r.Builder: ", err)
+	}
+
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		if db.CheckNotUnique(err) {
+			return false, ErrAccessPolicyNotUnique.Wrap(err.Error())
+		}
+
+		return false, ErrAccessPolicyDatabase.Wrap("Update", "r.Pool.Exec", err)
+	}
+
+	result, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("AccessPolicyRepo - Update - r.Pool.Exec: %w", err)
+	}
+
+	return result > 0, nil
+}
+
+// Insert -.
+func (r *AccessPolicyRepo) Insert(ctx context.Context, p *entity.AccessPolicy) error {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Insert("access_policies").
+		Columns(accessPolicyColumns...).
+		Values(p.ID, p.Name, p.Subject, p.Tags, p.Method, p.Permissions, p.CreationDate, p.CreatedBy, p.TenantID).
+		ToSql()
+	if err != nil {
+		return ErrAccessPolicyDatabase.Wrap("Insert", "r.Builder: ", err)
+	}
+
+	_, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		if db.CheckNotUnique(err) {
+			return ErrAccessPolicyNotUnique.Wrap(err.Error())
+		}
+
+		return ErrAccessPolicyDatabase.Wrap("Insert", "r.Pool.Exec", err)
+	}
+
+	return nil
+}
+
+func (r *AccessPolicyRepo) query(ctx context.Context, op, sqlQuery string, args []interface{}) ([]entity.AccessPolicy, error) {
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, ErrAccessPolicyDatabase.Wrap(op, "r.Pool.Query", err)
+	}
+
+	defer rows.Close()
+
+	if rows.Err() != nil {
+		return nil, ErrAccessPolicyDatabase.Wrap(op, "rows.Err", rows.Err())
+	}
+
+	policies := make([]entity.AccessPolicy, 0)
+
+	for rows.Next() {
+		p := entity.AccessPolicy{}
+
+		if err := scanAccessPolicy(rows, &p); err != nil {
+			return nil, ErrAccessPolicyDatabase.Wrap(op, "rows.Scan: ", err)
+		}
+
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
+
+func scanAccessPolicy(row interface{ Scan(dest ...any) error }, p *entity.AccessPolicy) error {
+	var permissions, creationDate, createdBy sql.NullString
+
+	if err := row.Scan(&p.ID, &p.Name, &p.Subject, &p.Tags, &p.Method, &permissions, &creationDate, &createdBy, &p.TenantID); err != nil {
+		return err
+	}
+
+	p.Permissions, p.CreationDate, p.CreatedBy = permissions.String, creationDate.String, createdBy.String
+
+	return nil
+}
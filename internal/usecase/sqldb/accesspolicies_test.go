@@ -0,0 +1,123 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/sqldb"
+	"github.com/device-management-toolkit/console/pkg/db"
+)
+
+func setupAccessPolicyRepo(t *testing.T) *sqldb.AccessPolicyRepo {
+	t.Helper()
+
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { dbConn.Close() })
+
+	_, err = dbConn.ExecContext(context.Background(), schema)
+	require.NoError(t, err)
+
+	return sqldb.NewAccessPolicyRepo(&db.SQL{
+		Builder:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		Pool:       dbConn,
+		IsEmbedded: true,
+	}, mocks.NewMockLogger(nil))
+}
+
+func testAccessPolicy(id, name, subject string) *entity.AccessPolicy {
+	return &entity.AccessPolicy{
+		ID:           id,
+		Name:         name,
+		Subject:      subject,
+		Tags:         "site-a",
+		Method:       "any",
+		CreationDate: "2026-10-19T00:00:00Z",
+		CreatedBy:    "admin",
+	}
+}
+
+func TestAccessPolicyRepo_InsertAndGet(t *testing.T) {
+	t.Parallel()
+
+	repo := setupAccessPolicyRepo(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Insert(ctx, testAccessPolicy("1", "site-b", "role:operator")))
+	require.NoError(t, repo.Insert(ctx, testAccessPolicy("2", "site-a", "user:alice")))
+	require.NoError(t, repo.Insert(ctx, testAccessPolicy("3", "other", "user:bob")))
+
+	got, err := repo.GetByID(ctx, "2", "")
+	require.NoError(t, err)
+	require.Equal(t, testAccessPolicy("2", "site-a", "user:alice"), got)
+
+	missing, err := repo.GetByID(ctx, "2", "tenant-b")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	count, err := repo.GetCount(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	policies, err := repo.Get(ctx, 2, 0, "")
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	require.Equal(t, "other", policies[0].Name, "policies are listed by name")
+
+	policies, err = repo.GetBySubjects(ctx, []string{"user:alice", "role:operator"}, "")
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+
+	policies, err = repo.GetBySubjects(ctx, nil, "")
+	require.NoError(t, err)
+	require.Empty(t, policies)
+}
+
+func TestAccessPolicyRepo_InsertDuplicate(t *testing.T) {
+	t.Parallel()
+
+	repo := setupAccessPolicyRepo(t)
+
+	require.NoError(t, repo.Insert(context.Background(), testAccessPolicy("1", "site-a", "user:alice")))
+
+	err := repo.Insert(context.Background(), testAccessPolicy("2", "site-a", "user:bob"))
+
+	var notUnique repoerrors.NotUniqueError
+	require.ErrorAs(t, err, &notUnique)
+}
+
+func TestAccessPolicyRepo_UpdateAndDelete(t *testing.T) {
+	t.Parallel()
+
+	repo := setupAccessPolicyRepo(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Insert(ctx, testAccessPolicy("1", "site-a", "user:alice")))
+
+	p := testAccessPolicy("1", "site-a", "role:operator")
+	p.Tags, p.Method, p.Permissions = "site-a,rack-1", "all", "devices:read"
+
+	updated, err := repo.Update(ctx, p)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	got, err := repo.GetByID(ctx, "1", "")
+	require.NoError(t, err)
+	require.Equal(t, p, got)
+
+	deleted, err := repo.Delete(ctx, "1", "tenant-b")
+	require.NoError(t, err)
+	require.False(t, deleted, "another tenant cannot delete the policy")
+
+	deleted, err = repo.Delete(ctx, "1", "")
+	require.NoError(t, err)
+	require.True(t, deleted)
+}
@@ -0,0 +1,254 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/db"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// APIKeyRepo -.
+type APIKeyRepo struct {
+	*db.SQL
+	log logger.Interface
+}
+
+var (
+	ErrAPIKeyDatabase  = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("APIKeyRepo")}
+	ErrAPIKeyNotUnique = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("APIKeyRepo")}
+)
+
+var apiKeyColumns = []string{
+	"id",
+	"name",
+	"key_hash",
+	"role",
+	"routes",
+	"tags",
+	"expires_at",
+	"last_used_at",
+	"creation_date",
+	"created_by",
+	"tenant_id",
+}
+
+// NewAPIKeyRepo -.
+func NewAPIKeyRepo(database *db.SQL, log logger.Interface) *APIKeyRepo {
+	return &APIKeyRepo{database, log}
+}
+
+// GetCount -.
+func (r *APIKeyRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select("COUNT(*) OVER() AS total_count").
+		From("api_keys").
+		Where("tenant_id = ?", tenantID).
+		ToSql()
+	if err != nil {
+		return 0, ErrAPIKeyDatabase.Wrap("GetCount", "r.Builder: ", err)
+	}
+
+	var count int
+
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+
+		return 0, ErrAPIKeyDatabase.Wrap("GetCount", "r.Pool.QueryRow", err)
+	}
+
+	return count, nil
+}
+
+// Get -.
+func (r *APIKeyRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.APIKey, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	const defaultTop = 100
+
+	if top == 0 {
+		top = defaultTop
+	}
+
+	limitedTop := uint64(defaultTop)
+	if top > 0 {
+		limitedTop = uint64(top)
+	}
+
+	limitedSkip := uint64(0)
+	if skip > 0 {
+		limitedSkip = uint64(skip)
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Select(apiKeyColumns...).
+		From("api_keys").
+		Where("tenant_id = ?", tenantID).
+		OrderBy("creation_date", "id").
+		Limit(limitedTop).
+		Offset(limitedSkip).
+		ToSql()
+	if err != nil {
+		return nil, ErrAPIKeyDatabase.Wrap("Get", "r.Builder: ", err)
+	}
+
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, ErrAPIKeyDatabase.Wrap("Get", "r.Pool.Query", err)
+	}
+
+	defer rows.Close()
+
+	if rows.Err() != nil {
+		return nil, ErrAPIKeyDatabase.Wrap("Get", "rows.Err", rows.Err())
+	}
+
+	keys := make([]entity.APIKey, 0)
+
+	for rows.Next() {
+		k := entity.APIKey{}
+
+		err = rows.Scan(&k.ID, &k.Name, &k.KeyHash, &k.Role, &k.Routes, &k.Tags, &k.ExpiresAt, &k.LastUsedAt, &k.CreationDate, &k.CreatedBy, &k.TenantID)
+		if err != nil {
+			return nil, ErrAPIKeyDatabase.Wrap("Get", "rows.Scan: ", err)
+		}
+
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+// GetByID searches every tenant, as a presented key names no tenant until it
+// is found.
+func (r *APIKeyRepo) GetByID(ctx context.Context, id string) (*entity.APIKey, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select(apiKeyColumns...).
+		From("api_keys").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return nil, ErrAPIKeyDatabase.Wrap("GetByID", "r.Builder: ", err)
+	}
+
+	row := r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...)
+
+	k := entity.APIKey{}
+
+	err = row.Scan(&k.ID, &k.Name, &k.KeyHash, &k.Role, &k.Routes, &k.Tags, &k.ExpiresAt, &k.LastUsedAt, &k.CreationDate, &k.CreatedBy, &k.TenantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, ErrAPIKeyDatabase.Wrap("GetByID", "row.Scan: ", err)
+	}
+
+	return &k, nil
+}
+
+// Delete -.
+func (r *APIKeyRepo) Delete(ctx context.Context, id, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Delete("api_keys").
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		ToSql()
+	if err != nil {
+		return false, ErrAPIKeyDatabase.Wrap("Delete", "r.Builder: ", err)
+	}
+
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return false, ErrAPIKeyDatabase.Wrap("Delete", "r.Pool.Exec", err)
+	}
+
+	result, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("APIKeyRepo - Delete - r.Pool.Exec: %w", err)
+	}
+
+	return result > 0, nil
+}
+
+// UpdateLastUsed -.
+func (r *APIKeyRepo) UpdateLastUsed(ctx context.Context, id, lastUsedAt string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Update("api_keys").
+		Set("last_used_at", lastUsedAt).
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return false, ErrAPIKeyDatabase.Wrap("UpdateLastUsed", "r.Builder: ", err)
+	}
+
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return false, ErrAPIKeyDatabase.Wrap("UpdateLastUsed", "r.Pool.Exec", err)
+	}
+
+	result, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("APIKeyRepo - UpdateLastUsed - r.Pool.Exec: %w", err)
+	}
+
+	return result > 0, nil
+}
+
+// Insert -.
+func (r *APIKeyRepo) Insert(ctx context.Context, k *entity.APIKey) (string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	insertBuilder := r.Builder.
+		Insert("api_keys").
+		Columns(apiKeyColumns...).
+		Values(k.ID, k.Name, k.KeyHash, k.Role, k.Routes, k.Tags, k.ExpiresAt, k.LastUsedAt, k.CreationDate, k.CreatedBy, k.TenantID)
+
+	if !r.IsEmbedded {
+		insertBuilder = insertBuilder.Suffix("RETURNING xmin::text")
+	}
+
+	sqlQuery, args, err := insertBuilder.ToSql()
+	if err != nil {
+		return "", ErrAPIKeyDatabase.Wrap("Insert", "r.Builder: ", err)
+	}
+
+	version := ""
+
+	if r.IsEmbedded {
+		_, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+	} else {
+		err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&version)
+	}
+
+	if err != nil {
+		if db.CheckNotUnique(err) {
+			return "", ErrAPIKeyNotUnique.Wrap(err.Error())
+		}
+
+		return "", ErrAPIKeyDatabase.Wrap("Insert", "r.Pool.QueryRow", err)
+	}
+
+	return version, nil
+}
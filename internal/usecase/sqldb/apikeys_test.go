@@ -0,0 +1,126 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/sqldb"
+	"github.com/device-management-toolkit/console/pkg/db"
+)
+
+func setupAPIKeyRepo(t *testing.T) *sqldb.APIKeyRepo {
+	t.Helper()
+
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { dbConn.Close() })
+
+	_, err = dbConn.ExecContext(context.Background(), schema)
+	require.NoError(t, err)
+
+	return sqldb.NewAPIKeyRepo(&db.SQL{
+		Builder:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		Pool:       dbConn,
+		IsEmbedded: true,
+	}, mocks.NewMockLogger(nil))
+}
+
+func testAPIKey(id, created string) *entity.APIKey {
+	return &entity.APIKey{
+		ID:           id,
+		Name:         "ci",
+		KeyHash:      "hash-" + id,
+		Role:         "operator",
+		Routes:       "/api/v1/amt/power/*",
+		Tags:         "lab,ci",
+		ExpiresAt:    "2027-01-01T00:00:00Z",
+		CreationDate: created,
+		CreatedBy:    "admin",
+	}
+}
+
+func TestAPIKeyRepo_InsertAndGet(t *testing.T) {
+	t.Parallel()
+
+	repo := setupAPIKeyRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.Insert(ctx, testAPIKey("b", "2026-10-19T00:00:02Z"))
+	require.NoError(t, err)
+
+	_, err = repo.Insert(ctx, testAPIKey("a", "2026-10-19T00:00:01Z"))
+	require.NoError(t, err)
+
+	got, err := repo.GetByID(ctx, "b")
+	require.NoError(t, err)
+	require.Equal(t, testAPIKey("b", "2026-10-19T00:00:02Z"), got)
+
+	count, err := repo.GetCount(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	count, err = repo.GetCount(ctx, "tenant-b")
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	keys, err := repo.Get(ctx, 10, 0, "")
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, "a", keys[0].ID, "keys are listed oldest first")
+
+	missing, err := repo.GetByID(ctx, "missing")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+}
+
+func TestAPIKeyRepo_InsertDuplicate(t *testing.T) {
+	t.Parallel()
+
+	repo := setupAPIKeyRepo(t)
+
+	_, err := repo.Insert(context.Background(), testAPIKey("a", ""))
+	require.NoError(t, err)
+
+	_, err = repo.Insert(context.Background(), testAPIKey("a", ""))
+
+	var notUnique repoerrors.NotUniqueError
+	require.ErrorAs(t, err, &notUnique)
+}
+
+func TestAPIKeyRepo_UpdateLastUsedAndDelete(t *testing.T) {
+	t.Parallel()
+
+	repo := setupAPIKeyRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.Insert(ctx, testAPIKey("a", ""))
+	require.NoError(t, err)
+
+	updated, err := repo.UpdateLastUsed(ctx, "a", "2026-10-19T12:00:00Z")
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	got, err := repo.GetByID(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, "2026-10-19T12:00:00Z", got.LastUsedAt)
+
+	deleted, err := repo.Delete(ctx, "a", "tenant-b")
+	require.NoError(t, err)
+	require.False(t, deleted, "another tenant cannot revoke the key")
+
+	deleted, err = repo.Delete(ctx, "a", "")
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	updated, err = repo.UpdateLastUsed(ctx, "a", "2026-10-19T12:01:00Z")
+	require.NoError(t, err)
+	require.False(t, updated)
+}
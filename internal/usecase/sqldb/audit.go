@@ -0,0 +1,237 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/db"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// AuditRepo -.
+type AuditRepo struct {
+	*db.SQL
+	log logger.Interface
+}
+
+var (
+	ErrAuditDatabase  = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("AuditRepo")}
+	ErrAuditNotUnique = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("AuditRepo")}
+)
+
+var auditColumns = []string{
+	"seq",
+	"occurred_at",
+	"actor",
+	"tenant_id",
+	"action",
+	"target",
+	"parameters",
+	"result",
+	"status",
+	"source_ip",
+	"prev_hash",
+	"hash",
+}
+
+// NewAuditRepo -.
+func NewAuditRepo(database *db.SQL, log logger.Interface) *AuditRepo {
+	return &AuditRepo{database, log}
+}
+
+func auditWhere(filter entity.AuditFilter) squirrel.And {
+	where := squirrel.And{squirrel.Eq{"tenant_id": filter.TenantID}}
+
+	for _, field := range []struct{ column, value string }{
+		{"actor", filter.Actor},
+		{"action", filter.Action},
+		{"target", filter.Target},
+		{"result", filter.Result},
+	} {
+		if field.value != "" {
+			where = append(where, squirrel.Eq{field.column: field.value})
+		}
+	}
+
+	if filter.From != "" {
+		where = append(where, squirrel.GtOrEq{"occurred_at": filter.From})
+	}
+
+	if filter.To != "" {
+		where = append(where, squirrel.LtOrEq{"occurred_at": filter.To})
+	}
+
+	return where
+}
+
+// GetCount -.
+func (r *AuditRepo) GetCount(ctx context.Context, filter entity.AuditFilter) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select("COUNT(*)").
+		From("audit_events").
+		Where(auditWhere(filter)).
+		ToSql()
+	if err != nil {
+		return 0, ErrAuditDatabase.Wrap("GetCount", "r.Builder: ", err)
+	}
+
+	var count int
+
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
+	if err != nil {
+		return 0, ErrAuditDatabase.Wrap("GetCount", "r.Pool.QueryRow", err)
+	}
+
+	return count, nil
+}
+
+// Get returns entries of filter.TenantID, oldest first.
+func (r *AuditRepo) Get(ctx context.Context, top, skip int, filter entity.AuditFilter) ([]entity.AuditEvent, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	const defaultTop = 100
+
+	if top == 0 {
+		top = defaultTop
+	}
+
+	limitedTop := uint64(defaultTop)
+	if top > 0 {
+		limitedTop = uint64(top)
+	}
+
+	limitedSkip := uint64(0)
+	if skip > 0 {
+		limitedSkip = uint64(skip)
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Select(auditColumns...).
+		From("audit_events").
+		Where(auditWhere(filter)).
+		OrderBy("seq").
+		Limit(limitedTop).
+		Offset(limitedSkip).
+		ToSql()
+	if err != nil {
+		return nil, ErrAuditDatabase.Wrap("Get", "r.Builder: ", err)
+	}
+
+	return r.query(ctx, "Get", sqlQuery, args)
+}
+
+// GetAfter returns up to limit entries following seq, of every tenant, for
+// verifying the chain.
+func (r *AuditRepo) GetAfter(ctx context.Context, seq int64, limit int) ([]entity.AuditEvent, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select(auditColumns...).
+		From("audit_events").
+		Where("seq > ?", seq).
+		OrderBy("seq").
+		Limit(uint64(max(limit, 1))).
+		ToSql()
+	if err != nil {
+		return nil, ErrAuditDatabase.Wrap("GetAfter", "r.Builder: ", err)
+	}
+
+	return r.query(ctx, "GetAfter", sqlQuery, args)
+}
+
+// GetLast returns the newest entry, nil when there is none.
+func (r *AuditRepo) GetLast(ctx context.Context) (*entity.AuditEvent, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select(auditColumns...).
+		From("audit_events").
+		OrderBy("seq DESC").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, ErrAuditDatabase.Wrap("GetLast", "r.Builder: ", err)
+	}
+
+	e := entity.AuditEvent{}
+
+	err = scanAuditEvent(r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...), &e)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, ErrAuditDatabase.Wrap("GetLast", "row.Scan: ", err)
+	}
+
+	return &e, nil
+}
+
+// Insert appends e. A taken sequence number returns ErrAuditNotUnique.
+func (r *AuditRepo) Insert(ctx context.Context, e *entity.AuditEvent) error {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Insert("audit_events").
+		Columns(auditColumns...).
+		Values(e.Seq, e.Timestamp, e.Actor, e.TenantID, e.Action, e.Target, e.Parameters, e.Result, e.Status, e.SourceIP, e.PrevHash, e.Hash).
+		ToSql()
+	if err != nil {
+		return ErrAuditDatabase.Wrap("Insert", "r.Builder: ", err)
+	}
+
+	_, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		if db.CheckNotUnique(err) {
+			return ErrAuditNotUnique.Wrap(err.Error())
+		}
+
+		return ErrAuditDatabase.Wrap("Insert", "r.Pool.Exec", err)
+	}
+
+	return nil
+}
+
+func (r *AuditRepo) query(ctx context.Context, op, sqlQuery string, args []interface{}) ([]entity.AuditEvent, error) {
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, ErrAuditDatabase.Wrap(op, "r.Pool.Query", err)
+	}
+
+	defer rows.Close()
+
+	if rows.Err() != nil {
+		return nil, ErrAuditDatabase.Wrap(op, "rows.Err", rows.Err())
+	}
+
+	events := make([]entity.AuditEvent, 0)
+
+	for rows.Next() {
+		e := entity.AuditEvent{}
+
+		if err := scanAuditEvent(rows, &e); err != nil {
+			return nil, ErrAuditDatabase.Wrap(op, "rows.Scan: ", err)
+		}
+
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+func scanAuditEvent(row interface{ Scan(dest ...any) error }, e *entity.AuditEvent) error {
+	return row.Scan(&e.Seq, &e.Timestamp, &e.Actor, &e.TenantID, &e.Action, &e.Target, &e.Parameters, &e.Result, &e.Status, &e.SourceIP, &e.PrevHash, &e.Hash)
+}
@@ -0,0 +1,93 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/sqldb"
+	"github.com/device-management-toolkit/console/pkg/db"
+)
+
+func setupAuditRepo(t *testing.T) *sqldb.AuditRepo {
+	t.Helper()
+
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { dbConn.Close() })
+
+	_, err = dbConn.ExecContext(context.Background(), schema)
+	require.NoError(t, err)
+
+	return sqldb.NewAuditRepo(&db.SQL{
+		Builder:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		Pool:       dbConn,
+		IsEmbedded: true,
+	}, mocks.NewMockLogger(nil))
+}
+
+func testAuditEvent(seq int64, timestamp, actor, tenantID string) *entity.AuditEvent {
+	return &entity.AuditEvent{
+		Seq:        seq,
+		Timestamp:  timestamp,
+		Actor:      actor,
+		TenantID:   tenantID,
+		Action:     "POST /api/v1/amt/power/action/{guid}",
+		Target:     "guid-1",
+		Parameters: `{"body":{"action":10}}`,
+		Result:     "success",
+		Status:     200,
+		SourceIP:   "10.0.0.1",
+		PrevHash:   "prev",
+		Hash:       "hash",
+	}
+}
+
+func TestAuditRepo(t *testing.T) {
+	t.Parallel()
+
+	repo := setupAuditRepo(t)
+	ctx := context.Background()
+
+	last, err := repo.GetLast(ctx)
+	require.NoError(t, err)
+	require.Nil(t, last)
+
+	require.NoError(t, repo.Insert(ctx, testAuditEvent(1, "2026-10-19T10:00:00.000Z", "jdoe", "")))
+	require.NoError(t, repo.Insert(ctx, testAuditEvent(2, "2026-10-19T11:00:00.000Z", "admin", "")))
+	require.NoError(t, repo.Insert(ctx, testAuditEvent(3, "2026-10-19T12:00:00.000Z", "jdoe", "tenant-b")))
+
+	var notUnique repoerrors.NotUniqueError
+	require.ErrorAs(t, repo.Insert(ctx, testAuditEvent(3, "2026-10-19T12:00:00.000Z", "jdoe", "")), &notUnique)
+
+	last, err = repo.GetLast(ctx)
+	require.NoError(t, err)
+	require.Equal(t, testAuditEvent(3, "2026-10-19T12:00:00.000Z", "jdoe", "tenant-b"), last)
+
+	count, err := repo.GetCount(ctx, entity.AuditFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 2, count, "other tenants are not counted")
+
+	events, err := repo.Get(ctx, 0, 0, entity.AuditFilter{Actor: "jdoe"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, int64(1), events[0].Seq)
+
+	events, err = repo.Get(ctx, 0, 0, entity.AuditFilter{From: "2026-10-19T10:30:00.000Z", To: "2026-10-19T11:00:00.000Z"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, int64(2), events[0].Seq)
+
+	events, err = repo.GetAfter(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2, "verification reads every tenant")
+	require.Equal(t, int64(2), events[0].Seq)
+	require.Equal(t, int64(3), events[1].Seq)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/versions"
 	"github.com/device-management-toolkit/console/pkg/consoleerrors"
 	"github.com/device-management-toolkit/console/pkg/db"
 	"github.com/device-management-toolkit/console/pkg/logger"
@@ -28,22 +29,43 @@ var (
 	ErrCIRARepo          = consoleerrors.CreateConsoleError("CIRARepo")
 	ErrCIRARepoDatabase  = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("CIRARepo")}
 	ErrCIRARepoNotUnique = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("CIRARepo")}
+	ErrCIRAForeignKey    = ForeignKeyViolationError{Console: consoleerrors.CreateConsoleError("CIRARepo")}
 )
 
+// ciraConfigColumns are the columns of the CIRA configs' OData fields.
+var ciraConfigColumns = odataColumns{
+	"configName":             "cira_config_name",
+	"mpsServerAddress":       "mps_server_address",
+	"mpsPort":                "mps_port",
+	"username":               "user_name",
+	"commonName":             "common_name",
+	"serverAddressFormat":    "server_address_format",
+	"authMethod":             "auth_method",
+	"generateRandomPassword": "generate_random_password",
+}
+
 // GetCount -.
-func (r *CIRARepo) GetCount(_ context.Context, tenantID string) (int, error) {
-	sqlQuery, _, err := r.Builder.
+func (r *CIRARepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	builder, err := odataWhere(ctx, r.Builder.
 		Select("COUNT(*) OVER() AS total_count").
 		From("ciraconfigs").
 		Where("tenant_id = ?", tenantID).
-		ToSql()
+		Where(notDeleted), ciraConfigColumns)
+	if err != nil {
+		return 0, ErrCIRARepoDatabase.Wrap("GetCount", "odataWhere", err)
+	}
+
+	sqlQuery, args, err := builder.ToSql()
 	if err != nil {
 		return 0, ErrCIRARepoDatabase.Wrap("GetCount", "r.Builder", err)
 	}
 
 	var count int
 
-	err = r.Pool.QueryRowContext(context.Background(), sqlQuery, tenantID).Scan(&count)
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return 0, nil
@@ -56,7 +78,10 @@ func (r *CIRARepo) GetCount(_ context.Context, tenantID string) (int, error) {
 }
 
 // Get -.
-func (r *CIRARepo) Get(_ context.Context, top, skip int, tenantID string) ([]entity.CIRAConfig, error) {
+func (r *CIRARepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.CIRAConfig, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	const defaultTop = 100
 
 	if top == 0 {
@@ -73,7 +98,7 @@ func (r *CIRARepo) Get(_ context.Context, top, skip int, tenantID string) ([]ent
 		limitedSkip = uint64(skip)
 	}
 
-	sqlQuery, _, err := r.Builder.
+	builder, err := odataWhere(ctx, r.Builder.
 		Select("cira_config_name",
 			"mps_server_address",
 			"mps_port",
@@ -85,10 +110,21 @@ func (r *CIRARepo) Get(_ context.Context, top, skip int, tenantID string) ([]ent
 			"mps_root_certificate",
 			"proxydetails",
 			"tenant_id",
-			"generate_random_password").
+			"generate_random_password",
+			"version").
 		From("ciraconfigs").
 		Where("tenant_id = ?", tenantID).
-		OrderBy("cira_config_name").
+		Where(notDeleted), ciraConfigColumns)
+	if err != nil {
+		return nil, ErrCIRARepoDatabase.Wrap("Get", "odataWhere", err)
+	}
+
+	builder, err = odataOrderBy(ctx, builder, ciraConfigColumns, "cira_config_name")
+	if err != nil {
+		return nil, ErrCIRARepoDatabase.Wrap("Get", "odataOrderBy", err)
+	}
+
+	sqlQuery, args, err := builder.
 		Limit(limitedTop).
 		Offset(limitedSkip).
 		ToSql()
@@ -96,7 +132,7 @@ func (r *CIRARepo) Get(_ context.Context, top, skip int, tenantID string) ([]ent
 		return nil, ErrCIRARepoDatabase.Wrap("Get", "r.Builder", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, tenantID)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, ErrCIRARepoDatabase.Wrap("Get", "r.Pool.Query", err)
 	}
@@ -114,7 +150,7 @@ func (r *CIRARepo) Get(_ context.Context, top, skip int, tenantID string) ([]ent
 
 		var generateRandomPassword sql.NullString
 
-		err = rows.Scan(&p.ConfigName, &p.MPSAddress, &p.MPSPort, &p.Username, &p.Password, &p.CommonName, &p.ServerAddressFormat, &p.AuthMethod, &p.MPSRootCertificate, &p.ProxyDetails, &p.TenantID, &generateRandomPassword)
+		err = rows.Scan(&p.ConfigName, &p.MPSAddress, &p.MPSPort, &p.Username, &p.Password, &p.CommonName, &p.ServerAddressFormat, &p.AuthMethod, &p.MPSRootCertificate, &p.ProxyDetails, &p.TenantID, &generateRandomPassword, &p.Version)
 		if err != nil {
 			return nil, ErrCIRARepoDatabase.Wrap("Get", "rows.Scan", err)
 		}
@@ -130,7 +166,10 @@ func (r *CIRARepo) Get(_ context.Context, top, skip int, tenantID string) ([]ent
 }
 
 // GetByName -.
-func (r *CIRARepo) GetByName(_ context.Context, configName, tenantID string) (*entity.CIRAConfig, error) {
+func (r *CIRARepo) GetByName(ctx context.Context, configName, tenantID string) (*entity.CIRAConfig, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, _, err := r.Builder.
 		Select("cira_config_name",
 			"mps_server_address",
@@ -143,15 +182,17 @@ func (r *CIRARepo) GetByName(_ context.Context, configName, tenantID string) (*e
 			"mps_root_certificate",
 			"proxydetails",
 			"tenant_id",
-			"generate_random_password").
+			"generate_random_password",
+			"version").
 		From("ciraconfigs").
 		Where("cira_config_name = ? and tenant_id = ?", configName, tenantID).
+		Where(byKey(ctx, "")).
 		ToSql()
 	if err != nil {
 		return nil, ErrCIRARepoDatabase.Wrap("GetByName", "r.Builder", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, configName, tenantID)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, configName, tenantID)
 	if err != nil {
 		return nil, ErrCIRARepoDatabase.Wrap("GetByName", "r.Pool.Query", err)
 	}
@@ -169,7 +210,7 @@ func (r *CIRARepo) GetByName(_ context.Context, configName, tenantID string) (*e
 
 		var generateRandomPassword sql.NullString
 
-		err = rows.Scan(&p.ConfigName, &p.MPSAddress, &p.MPSPort, &p.Username, &p.Password, &p.CommonName, &p.ServerAddressFormat, &p.AuthMethod, &p.MPSRootCertificate, &p.ProxyDetails, &p.TenantID, &generateRandomPassword)
+		err = rows.Scan(&p.ConfigName, &p.MPSAddress, &p.MPSPort, &p.Username, &p.Password, &p.CommonName, &p.ServerAddressFormat, &p.AuthMethod, &p.MPSRootCertificate, &p.ProxyDetails, &p.TenantID, &generateRandomPassword, &p.Version)
 		if err != nil {
 			return p, ErrCIRARepoDatabase.Wrap("GetByName", "rows.Scan", err)
 		}
@@ -188,31 +229,39 @@ func (r *CIRARepo) GetByName(_ context.Context, configName, tenantID string) (*e
 	return configs[0], nil
 }
 
-// Delete -.
-func (r *CIRARepo) Delete(_ context.Context, configName, tenantID string) (bool, error) {
-	sqlQuery, args, err := r.Builder.
-		Delete("ciraconfigs").
-		Where("cira_config_name = ? AND tenant_id = ?", configName, tenantID).
-		ToSql()
-	if err != nil {
-		return false, ErrCIRARepoDatabase.Wrap("Delete", "r.Builder", err)
-	}
+// Delete moves the config to the trash, unless a live profile uses it.
+func (r *CIRARepo) Delete(ctx context.Context, configName, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
 
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, args...)
-	if err != nil {
-		return false, ErrCIRARepoDatabase.Wrap("Delete", "r.Pool.Exec", err)
-	}
+	var deleted bool
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return false, ErrCIRARepoDatabase.Wrap("Delete", "res.RowsAffected", err)
-	}
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		inUse, err := referenced(ctx, r.SQL, "profiles", "cira_config_name = ? AND tenant_id = ?", configName, tenantID)
+		if err != nil {
+			return ErrCIRARepoDatabase.Wrap("Delete", "referenced", err)
+		}
 
-	return rowsAffected > 0, nil
+		if inUse {
+			return ErrCIRAForeignKey.Wrap("CIRA config " + configName + " is used by a profile")
+		}
+
+		deleted, err = softDelete(ctx, r.SQL, "ciraconfigs", "CIRA config "+configName, "cira_config_name = ? AND tenant_id = ?", configName, tenantID)
+		if err != nil {
+			return ErrCIRARepoDatabase.Wrap("Delete", "softDelete", err)
+		}
+
+		return nil
+	})
+
+	return deleted, err
 }
 
 // Update -.
-func (r *CIRARepo) Update(_ context.Context, p *entity.CIRAConfig) (bool, error) {
+func (r *CIRARepo) Update(ctx context.Context, p *entity.CIRAConfig) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, args, err := r.Builder.
 		Update("ciraconfigs").
 		Set("mps_server_address", p.MPSAddress).
@@ -225,55 +274,66 @@ func (r *CIRARepo) Update(_ context.Context, p *entity.CIRAConfig) (bool, error)
 		Set("mps_root_certificate", p.MPSRootCertificate).
 		Set("proxydetails", p.ProxyDetails).
 		Set("generate_random_password", strconv.FormatBool(p.GenerateRandomPassword)).
+		Set("version", versions.New()).
 		Where("cira_config_name = ? AND tenant_id = ?", p.ConfigName, p.TenantID).
+		Where(notDeleted).
+		Where(ifMatch(ctx)).
 		ToSql()
 	if err != nil {
 		return false, ErrCIRARepoDatabase.Wrap("Update", "r.Builder", err)
 	}
 
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, args...)
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return false, ErrCIRARepoDatabase.Wrap("Update", "r.Pool.Exec", err)
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
-		return false, ErrCIRARepoDatabase.Wrap("Delete", "res.RowsAffected", err)
+		return false, ErrCIRARepoDatabase.Wrap("Update", "res.RowsAffected", err)
+	}
+
+	if rowsAffected == 0 {
+		if err := checkVersion(ctx, r.SQL, "ciraconfigs", "CIRA config "+p.ConfigName, "cira_config_name = ? AND tenant_id = ?", p.ConfigName, p.TenantID); err != nil {
+			return false, err
+		}
 	}
 
 	return rowsAffected > 0, nil
 }
 
 // Insert -.
-func (r *CIRARepo) Insert(_ context.Context, p *entity.CIRAConfig) (string, error) {
+func (r *CIRARepo) Insert(ctx context.Context, p *entity.CIRAConfig) (string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	version := versions.New()
+
 	insertBuilder := r.Builder.
 		Insert("ciraconfigs").
-		Columns("cira_config_name", "mps_server_address", "mps_port", "user_name", "password", "common_name", "server_address_format", "auth_method", "mps_root_certificate", "proxydetails", "tenant_id", "generate_random_password").
-		Values(p.ConfigName, p.MPSAddress, p.MPSPort, p.Username, p.Password, p.CommonName, p.ServerAddressFormat, p.AuthMethod, p.MPSRootCertificate, p.ProxyDetails, p.TenantID, strconv.FormatBool(p.GenerateRandomPassword))
-
-	if !r.IsEmbedded {
-		insertBuilder = insertBuilder.Suffix("RETURNING xmin::text")
-	}
+		Columns("cira_config_name", "mps_server_address", "mps_port", "user_name", "password", "common_name", "server_address_format", "auth_method", "mps_root_certificate", "proxydetails", "tenant_id", "generate_random_password", "version").
+		Values(p.ConfigName, p.MPSAddress, p.MPSPort, p.Username, p.Password, p.CommonName, p.ServerAddressFormat, p.AuthMethod, p.MPSRootCertificate, p.ProxyDetails, p.TenantID, strconv.FormatBool(p.GenerateRandomPassword), version)
 
 	sqlQuery, args, err := insertBuilder.ToSql()
 	if err != nil {
 		return "", ErrCIRARepoDatabase.Wrap("Insert", "r.Builder", err)
 	}
 
-	version := ""
+	err = r.InTx(ctx, func(ctx context.Context) error {
+		if err := purgeTrashed(ctx, r.SQL, entity.TrashCIRAConfigs, "cira_config_name = ? AND tenant_id = ?", p.ConfigName, p.TenantID); err != nil {
+			return err
+		}
 
-	if r.IsEmbedded {
-		_, err = r.Pool.ExecContext(context.Background(), sqlQuery, args...)
-	} else {
-		err = r.Pool.QueryRowContext(context.Background(), sqlQuery, args...).Scan(&version)
-	}
+		_, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 
+		return err
+	})
 	if err != nil {
-		if db.CheckNotUnique(err) {
+		if db.CheckNotUnique(err) || errors.Is(err, errTrashedInUse) {
 			return "", ErrCIRARepoNotUnique.Wrap(err.Error())
 		}
 
-		return "", ErrCIRARepoDatabase.Wrap("Insert", "r.Pool.QueryRow", err)
+		return "", ErrCIRARepoDatabase.Wrap("Insert", "r.InTx", err)
 	}
 
 	return version, nil
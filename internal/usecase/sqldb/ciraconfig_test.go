@@ -443,11 +443,10 @@ func TestCIRARepo_Insert(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name     string
-		setup    func(dbConn *sql.DB)
-		config   *entity.CIRAConfig
-		expected string
-		err      error
+		name   string
+		setup  func(dbConn *sql.DB)
+		config *entity.CIRAConfig
+		err    error
 	}{
 		{
 			name:  "Successful insert",
@@ -463,8 +462,7 @@ func TestCIRARepo_Insert(t *testing.T) {
 				ProxyDetails:       "proxy_details1",
 				TenantID:           "tenant1",
 			},
-			expected: "",
-			err:      nil,
+			err: nil,
 		},
 		{
 			name: "Insert with not unique error",
@@ -484,8 +482,7 @@ func TestCIRARepo_Insert(t *testing.T) {
 				ProxyDetails:       "proxy_details1",
 				TenantID:           "tenant1",
 			},
-			expected: "",
-			err:      repoerrors.NotUniqueError{},
+			err: repoerrors.NotUniqueError{},
 		},
 		{
 			name:  QueryExecutionErrorTestName,
@@ -501,8 +498,7 @@ func TestCIRARepo_Insert(t *testing.T) {
 				ProxyDetails:       "proxy_details1",
 				TenantID:           "tenant1",
 			},
-			expected: "",
-			err:      &repoerrors.DatabaseError{},
+			err: &repoerrors.DatabaseError{},
 		},
 	}
 
@@ -525,8 +521,8 @@ func TestCIRARepo_Insert(t *testing.T) {
 
 			assertTestResult(t, nil, nil, tc.err, err)
 
-			if version != tc.expected {
-				t.Errorf("Expected version %v, got %v", tc.expected, version)
+			if (err == nil) != (version != "") {
+				t.Errorf("Expected a version only on success, got %q", version)
 			}
 		})
 	}
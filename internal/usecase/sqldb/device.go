@@ -4,11 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"strings"
 	"time"
 
+	"github.com/Masterminds/squirrel"
+
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/taglist"
+	"github.com/device-management-toolkit/console/internal/versions"
 	"github.com/device-management-toolkit/console/pkg/consoleerrors"
 	"github.com/device-management-toolkit/console/pkg/db"
 	"github.com/device-management-toolkit/console/pkg/logger"
@@ -25,25 +28,46 @@ var (
 	ErrDeviceNotUnique = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("DeviceRepo")}
 )
 
+// deviceColumns are the columns of the devices' OData fields.
+var deviceColumns = odataColumns{
+	"guid":             "guid",
+	"hostname":         "hostname",
+	"friendlyName":     "friendlyname",
+	"dnsSuffix":        "dnssuffix",
+	"mpsInstance":      "mpsinstance",
+	"mpsusername":      "mpsusername",
+	"connectionStatus": "connectionstatus",
+	"useTLS":           "usetls",
+	"allowSelfSigned":  "allowselfsigned",
+}
+
 // New -.
 func NewDeviceRepo(database *db.SQL, log logger.Interface) *DeviceRepo {
 	return &DeviceRepo{database, log}
 }
 
 // GetCount -.
-func (r *DeviceRepo) GetCount(_ context.Context, tenantID string) (int, error) {
-	sqlQuery, _, err := r.Builder.
+func (r *DeviceRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	builder, err := odataWhere(ctx, r.Builder.
 		Select("COUNT(*) OVER() AS total_count").
 		From("devices").
 		Where("tenantid = ?", tenantID).
-		ToSql()
+		Where(notDeleted), deviceColumns)
+	if err != nil {
+		return 0, ErrDeviceDatabase.Wrap("GetCount", "odataWhere", err)
+	}
+
+	sqlQuery, args, err := builder.ToSql()
 	if err != nil {
 		return 0, ErrDeviceDatabase.Wrap("GetCount", "r.Builder: ", err)
 	}
 
 	var count int
 
-	err = r.Pool.QueryRowContext(context.Background(), sqlQuery, tenantID).Scan(&count)
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return 0, nil
@@ -56,7 +80,10 @@ func (r *DeviceRepo) GetCount(_ context.Context, tenantID string) (int, error) {
 }
 
 // Get -.
-func (r *DeviceRepo) Get(_ context.Context, top, skip int, tenantID string) ([]entity.Device, error) {
+func (r *DeviceRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.Device, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	const defaultTop = 100
 
 	if top == 0 {
@@ -73,7 +100,7 @@ func (r *DeviceRepo) Get(_ context.Context, top, skip int, tenantID string) ([]e
 		limitedSkip = uint64(skip)
 	}
 
-	sqlQuery, _, err := r.Builder.
+	builder, err := odataWhere(ctx, r.Builder.
 		Select("guid",
 			"hostname",
 			"tags",
@@ -88,10 +115,21 @@ func (r *DeviceRepo) Get(_ context.Context, top, skip int, tenantID string) ([]e
 			"password",
 			"usetls",
 			"allowselfsigned",
-			"certhash").
+			"certhash",
+			"version").
 		From("devices").
 		Where("tenantid = ?", tenantID).
-		OrderBy("guid").
+		Where(notDeleted), deviceColumns)
+	if err != nil {
+		return nil, ErrDeviceDatabase.Wrap("Get", "odataWhere", err)
+	}
+
+	builder, err = odataOrderBy(ctx, builder, deviceColumns, "guid")
+	if err != nil {
+		return nil, ErrDeviceDatabase.Wrap("Get", "odataOrderBy", err)
+	}
+
+	sqlQuery, args, err := builder.
 		Limit(limitedTop).
 		Offset(limitedSkip).
 		ToSql()
@@ -99,7 +137,7 @@ func (r *DeviceRepo) Get(_ context.Context, top, skip int, tenantID string) ([]e
 		return nil, ErrDeviceDatabase.Wrap("Get", "r.Builder: ", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, tenantID)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, ErrDeviceDatabase.Wrap("Get", "r.Pool.Query", err)
 	}
@@ -115,7 +153,7 @@ func (r *DeviceRepo) Get(_ context.Context, top, skip int, tenantID string) ([]e
 	for rows.Next() {
 		d := entity.Device{}
 
-		err = rows.Scan(&d.GUID, &d.Hostname, &d.Tags, &d.MPSInstance, &d.ConnectionStatus, &d.MPSUsername, &d.TenantID, &d.FriendlyName, &d.DNSSuffix, &d.DeviceInfo, &d.Username, &d.Password, &d.UseTLS, &d.AllowSelfSigned, &d.CertHash)
+		err = rows.Scan(&d.GUID, &d.Hostname, &d.Tags, &d.MPSInstance, &d.ConnectionStatus, &d.MPSUsername, &d.TenantID, &d.FriendlyName, &d.DNSSuffix, &d.DeviceInfo, &d.Username, &d.Password, &d.UseTLS, &d.AllowSelfSigned, &d.CertHash, &d.Version)
 		if err != nil {
 			return nil, ErrDeviceDatabase.Wrap("Get", "rows.Scan: ", err)
 		}
@@ -127,7 +165,23 @@ func (r *DeviceRepo) Get(_ context.Context, top, skip int, tenantID string) ([]e
 }
 
 // GetByID -.
-func (r *DeviceRepo) GetByID(_ context.Context, guid, tenantID string) (*entity.Device, error) {
+func (r *DeviceRepo) GetByID(ctx context.Context, guid, tenantID string) (*entity.Device, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	return r.getOne(ctx, "GetByID", "guid = ? and tenantid = ?", guid, tenantID)
+}
+
+// GetByGUID searches every tenant, for connections the device opens itself,
+// which name no tenant. GUIDs are unique across tenants.
+func (r *DeviceRepo) GetByGUID(ctx context.Context, guid string) (*entity.Device, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	return r.getOne(ctx, "GetByGUID", "guid = ?", guid)
+}
+
+func (r *DeviceRepo) getOne(ctx context.Context, op, where string, args ...interface{}) (*entity.Device, error) {
 	sqlQuery, _, err := r.Builder.
 		Select(
 			"guid",
@@ -147,23 +201,25 @@ func (r *DeviceRepo) GetByID(_ context.Context, guid, tenantID string) (*entity.
 			"usetls",
 			"allowselfsigned",
 			"certhash",
+			"version",
 		).
 		From("devices").
-		Where("guid = ? and tenantid = ?").
+		Where(where).
+		Where(byKey(ctx, "")).
 		ToSql()
 	if err != nil {
-		return nil, ErrDeviceDatabase.Wrap("Get", "r.Builder: ", err)
+		return nil, ErrDeviceDatabase.Wrap(op, "r.Builder: ", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, guid, tenantID)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return nil, ErrDeviceDatabase.Wrap("Get", "r.Pool.Query", err)
+		return nil, ErrDeviceDatabase.Wrap(op, "r.Pool.Query", err)
 	}
 
 	defer rows.Close()
 
 	if rows.Err() != nil {
-		return nil, ErrDeviceDatabase.Wrap("Get", "rows.Err", rows.Err())
+		return nil, ErrDeviceDatabase.Wrap(op, "rows.Err", rows.Err())
 	}
 
 	devices := make([]*entity.Device, 0)
@@ -171,9 +227,9 @@ func (r *DeviceRepo) GetByID(_ context.Context, guid, tenantID string) (*entity.
 	for rows.Next() {
 		d := &entity.Device{}
 
-		err = rows.Scan(&d.GUID, &d.Hostname, &d.Tags, &d.MPSInstance, &d.ConnectionStatus, &d.MPSUsername, &d.TenantID, &d.FriendlyName, &d.DNSSuffix, &d.DeviceInfo, &d.Username, &d.Password, &d.MPSPassword, &d.MEBXPassword, &d.UseTLS, &d.AllowSelfSigned, &d.CertHash)
+		err = rows.Scan(&d.GUID, &d.Hostname, &d.Tags, &d.MPSInstance, &d.ConnectionStatus, &d.MPSUsername, &d.TenantID, &d.FriendlyName, &d.DNSSuffix, &d.DeviceInfo, &d.Username, &d.Password, &d.MPSPassword, &d.MEBXPassword, &d.UseTLS, &d.AllowSelfSigned, &d.CertHash, &d.Version)
 		if err != nil {
-			return d, ErrDeviceDatabase.Wrap("Get", "rows.Scan: ", err)
+			return d, ErrDeviceDatabase.Wrap(op, "rows.Scan: ", err)
 		}
 
 		devices = append(devices, d)
@@ -186,48 +242,50 @@ func (r *DeviceRepo) GetByID(_ context.Context, guid, tenantID string) (*entity.
 	return devices[0], nil
 }
 
-func (r *DeviceRepo) GetDistinctTags(_ context.Context, tenantID string) ([]string, error) {
-	sqlQuery, _, err := r.Builder.
-		Select("DISTINCT tags as tag").
-		From("devices").
-		Where("tenantid = ?", tenantID).
+// GetDistinctTags lists the tags of the live devices, in order.
+func (r *DeviceRepo) GetDistinctTags(ctx context.Context, tenantID string) ([]string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select("DISTINCT tag").
+		From("device_tags").
+		Where("tenant_id = ?", tenantID).
+		Where(squirrel.Expr("guid IN (?)", liveDevices(r.SQL, tenantID))).
+		OrderBy("tag").
 		ToSql()
 	if err != nil {
 		return []string{}, ErrDeviceDatabase.Wrap("GetDistinctTags", "r.Builder: ", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, tenantID)
+	tags, err := queryStrings(ctx, r.SQL, sqlQuery, args...)
 	if err != nil {
-		return []string{}, ErrDeviceDatabase.Wrap("GetDistinctTags", "r.Pool.Query", err)
+		return []string{}, ErrDeviceDatabase.Wrap("GetDistinctTags", "queryStrings", err)
 	}
 
-	defer rows.Close()
-
-	if rows.Err() != nil {
-		return nil, ErrDeviceDatabase.Wrap("Get", "rows.Err", rows.Err())
-	}
-
-	tags := make([]string, 0)
-
-	for rows.Next() {
-		var tag string
-
-		err = rows.Scan(&tag)
-		if err != nil {
-			return []string{tag}, ErrDeviceDatabase.Wrap("GetDistinctTags", "rows.Scan: ", err)
-		}
+	return tags, nil
+}
 
-		tags = append(tags, tag)
-	}
+// GetByTags lists the live devices carrying every one of tags when method is
+// "AND", and any of them otherwise.
+func (r *DeviceRepo) GetByTags(ctx context.Context, tags []string, method string, limit, offset int, tenantID string) ([]entity.Device, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
 
+	tags = taglist.Split(taglist.Join(tags))
 	if len(tags) == 0 {
-		return []string{}, nil
+		return []entity.Device{}, nil
 	}
 
-	return tags, nil
-}
+	tagged := r.Builder.
+		Select("guid").
+		From("device_tags").
+		Where(squirrel.Eq{"tenant_id": tenantID, "tag": tags})
+
+	if method == "AND" {
+		tagged = tagged.GroupBy("guid").Having("COUNT(*) = ?", len(tags))
+	}
 
-func (r *DeviceRepo) GetByTags(_ context.Context, tags []string, method string, limit, offset int, tenantID string) ([]entity.Device, error) {
 	builder := r.Builder.
 		Select("guid",
 			"hostname",
@@ -238,29 +296,12 @@ func (r *DeviceRepo) GetByTags(_ context.Context, tags []string, method string,
 			"tenantid",
 			"friendlyname",
 			"dnssuffix",
-			"deviceinfo").
-		From("devices")
-
-	var params []interface{}
-
-	if method == "AND" {
-		// All tags must be present (simulating an 'AND' operation)
-		for _, tag := range tags {
-			builder = builder.Where("(',' || tags || ',') LIKE ? AND tenantId = ?", "%,"+tag+",%", tenantID)
-			params = append(params, "%,"+tag+",%", tenantID) //nolint:staticcheck // intentionally retained; the AND branch passes its args to Where inline
-		}
-	} else {
-		// Any tag is present (simulating an 'OR' operation)
-		var conditions []string
-		for _, tag := range tags {
-			conditions = append(conditions, "(',' || tags || ',') LIKE ?")
-			params = append(params, "%,"+tag+",%")
-		}
-
-		tagsCondition := strings.Join(conditions, " OR ")
-
-		builder = builder.Where("("+tagsCondition+") AND tenantId = ?", append(params, tenantID)...)
-	}
+			"deviceinfo",
+			"version").
+		From("devices").
+		Where("tenantid = ?", tenantID).
+		Where(notDeleted).
+		Where(squirrel.Expr("guid IN (?)", tagged))
 
 	limitedLimit := uint64(0)
 	if limit > 0 {
@@ -280,7 +321,7 @@ func (r *DeviceRepo) GetByTags(_ context.Context, tags []string, method string,
 		return nil, ErrDeviceDatabase.Wrap("GetByTags", "r.Builder: ", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, args...)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, ErrDeviceDatabase.Wrap("GetByTags", "r.Pool.QueryContext", err)
 	}
@@ -294,7 +335,7 @@ func (r *DeviceRepo) GetByTags(_ context.Context, tags []string, method string,
 
 	for rows.Next() {
 		var d entity.Device
-		if err := rows.Scan(&d.GUID, &d.Hostname, &d.Tags, &d.MPSInstance, &d.ConnectionStatus, &d.MPSUsername, &d.TenantID, &d.FriendlyName, &d.DNSSuffix, &d.DeviceInfo); err != nil {
+		if err := rows.Scan(&d.GUID, &d.Hostname, &d.Tags, &d.MPSInstance, &d.ConnectionStatus, &d.MPSUsername, &d.TenantID, &d.FriendlyName, &d.DNSSuffix, &d.DeviceInfo, &d.Version); err != nil {
 			return nil, ErrDeviceDatabase.Wrap("GetByTags", "rows.Scan", err)
 		}
 
@@ -304,31 +345,24 @@ func (r *DeviceRepo) GetByTags(_ context.Context, tags []string, method string,
 	return devices, nil
 }
 
-// Delete -.
-func (r *DeviceRepo) Delete(_ context.Context, guid, tenantID string) (bool, error) {
-	sqlQuery, _, err := r.Builder.
-		Delete("devices").
-		Where("guid = ? AND tenantid = ?", guid, tenantID).
-		ToSql()
-	if err != nil {
-		return false, ErrDeviceDatabase.Wrap("Delete", "r.Builder", err)
-	}
-
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, guid, tenantID)
-	if err != nil {
-		return false, ErrDeviceDatabase.Wrap("Delete", "r.Pool.Exec", err)
-	}
+// Delete moves the device to the trash.
+func (r *DeviceRepo) Delete(ctx context.Context, guid, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
 
-	rowsAffected, err := res.RowsAffected()
+	deleted, err := softDelete(ctx, r.SQL, "devices", "device "+guid, "guid = ? AND tenantid = ?", guid, tenantID)
 	if err != nil {
-		return false, ErrDeviceDatabase.Wrap("Delete", "res.RowsAffected", err)
+		return false, ErrDeviceDatabase.Wrap("Delete", "softDelete", err)
 	}
 
-	return rowsAffected > 0, nil
+	return deleted, nil
 }
 
 // Update -.
-func (r *DeviceRepo) Update(_ context.Context, d *entity.Device) (bool, error) {
+func (r *DeviceRepo) Update(ctx context.Context, d *entity.Device) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, args, err := r.Builder.
 		Update("devices").
 		Set("guid", d.GUID).
@@ -348,27 +382,50 @@ func (r *DeviceRepo) Update(_ context.Context, d *entity.Device) (bool, error) {
 		Set("useTLS", d.UseTLS).
 		Set("allowSelfSigned", d.AllowSelfSigned).
 		Set("certhash", d.CertHash).
+		Set("version", versions.New()).
 		Where("guid = ? AND tenantid = ?", d.GUID, d.TenantID).
+		Where(notDeleted).
+		Where(ifMatch(ctx)).
 		ToSql()
 	if err != nil {
 		return false, ErrDeviceDatabase.Wrap("Update", "r.Builder", err)
 	}
 
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, args...)
+	var updated bool
+
+	err = r.InTx(ctx, func(ctx context.Context) error {
+		res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil || n == 0 {
+			return err
+		}
+
+		updated = true
+
+		return writeDeviceTags(ctx, r.SQL, d.GUID, d.TenantID, taglist.Split(d.Tags))
+	})
 	if err != nil {
-		return false, ErrDeviceDatabase.Wrap("Update", "r.Pool.Exec", err)
+		return false, ErrDeviceDatabase.Wrap("Update", "r.InTx", err)
 	}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return false, ErrDeviceDatabase.Wrap("Delete", "res.RowsAffected", err)
+	if !updated {
+		if err := checkVersion(ctx, r.SQL, "devices", "device "+d.GUID, "guid = ? AND tenantid = ?", d.GUID, d.TenantID); err != nil {
+			return false, err
+		}
 	}
 
-	return rowsAffected > 0, nil
+	return updated, nil
 }
 
 // UpdateConnectionStatus updates only the connection status and timestamps for a device.
-func (r *DeviceRepo) UpdateConnectionStatus(_ context.Context, guid string, status bool) error {
+func (r *DeviceRepo) UpdateConnectionStatus(ctx context.Context, guid string, status bool) error {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	now := time.Now().Format("2006-01-02 15:04:05")
 
 	builder := r.Builder.
@@ -387,7 +444,7 @@ func (r *DeviceRepo) UpdateConnectionStatus(_ context.Context, guid string, stat
 		return ErrDeviceDatabase.Wrap("UpdateConnectionStatus", "r.Builder", err)
 	}
 
-	_, err = r.Pool.ExecContext(context.Background(), sqlQuery, args...)
+	_, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return ErrDeviceDatabase.Wrap("UpdateConnectionStatus", "r.Pool.Exec", err)
 	}
@@ -396,7 +453,10 @@ func (r *DeviceRepo) UpdateConnectionStatus(_ context.Context, guid string, stat
 }
 
 // UpdateLastSeen updates the lastseen timestamp for a device.
-func (r *DeviceRepo) UpdateLastSeen(_ context.Context, guid string) error {
+func (r *DeviceRepo) UpdateLastSeen(ctx context.Context, guid string) error {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	now := time.Now().Format("2006-01-02 15:04:05")
 
 	sqlQuery, args, err := r.Builder.
@@ -408,7 +468,7 @@ func (r *DeviceRepo) UpdateLastSeen(_ context.Context, guid string) error {
 		return ErrDeviceDatabase.Wrap("UpdateLastSeen", "r.Builder", err)
 	}
 
-	_, err = r.Pool.ExecContext(context.Background(), sqlQuery, args...)
+	_, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return ErrDeviceDatabase.Wrap("UpdateLastSeen", "r.Pool.Exec", err)
 	}
@@ -417,41 +477,48 @@ func (r *DeviceRepo) UpdateLastSeen(_ context.Context, guid string) error {
 }
 
 // Insert -.
-func (r *DeviceRepo) Insert(_ context.Context, d *entity.Device) (string, error) {
+func (r *DeviceRepo) Insert(ctx context.Context, d *entity.Device) (string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	version := versions.New()
+
 	insertBuilder := r.Builder.
 		Insert("devices").
-		Columns("guid", "hostname", "tags", "mpsinstance", "connectionstatus", "mpsusername", "tenantid", "friendlyname", "dnssuffix", "deviceinfo", "username", "password", "mpspassword", "mebxpassword", "usetls", "allowselfsigned", "certhash").
-		Values(d.GUID, d.Hostname, d.Tags, d.MPSInstance, d.ConnectionStatus, d.MPSUsername, d.TenantID, d.FriendlyName, d.DNSSuffix, d.DeviceInfo, d.Username, d.Password, d.MPSPassword, d.MEBXPassword, d.UseTLS, d.AllowSelfSigned, d.CertHash)
-
-	if !r.IsEmbedded {
-		insertBuilder = insertBuilder.Suffix("RETURNING xmin::text")
-	}
+		Columns("guid", "hostname", "tags", "mpsinstance", "connectionstatus", "mpsusername", "tenantid", "friendlyname", "dnssuffix", "deviceinfo", "username", "password", "mpspassword", "mebxpassword", "usetls", "allowselfsigned", "certhash", "version").
+		Values(d.GUID, d.Hostname, d.Tags, d.MPSInstance, d.ConnectionStatus, d.MPSUsername, d.TenantID, d.FriendlyName, d.DNSSuffix, d.DeviceInfo, d.Username, d.Password, d.MPSPassword, d.MEBXPassword, d.UseTLS, d.AllowSelfSigned, d.CertHash, version)
 
 	sqlQuery, args, err := insertBuilder.ToSql()
 	if err != nil {
 		return "", ErrDeviceDatabase.Wrap("Insert", "r.Builder", err)
 	}
 
-	version := ""
+	err = r.InTx(ctx, func(ctx context.Context) error {
+		if err := purgeTrashed(ctx, r.SQL, entity.TrashDevices, "guid = ?", d.GUID); err != nil {
+			return err
+		}
 
-	if r.IsEmbedded {
-		_, err = r.Pool.ExecContext(context.Background(), sqlQuery, args...)
-	} else {
-		err = r.Pool.QueryRowContext(context.Background(), sqlQuery, args...).Scan(&version)
-	}
+		if _, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+			return err
+		}
 
+		return writeDeviceTags(ctx, r.SQL, d.GUID, d.TenantID, taglist.Split(d.Tags))
+	})
 	if err != nil {
 		if db.CheckNotUnique(err) {
 			return "", ErrDeviceNotUnique
 		}
 
-		return "", ErrDeviceDatabase.Wrap("Insert", "r.Pool.QueryRow", err)
+		return "", ErrDeviceDatabase.Wrap("Insert", "r.InTx", err)
 	}
 
 	return version, nil
 }
 
-func (r *DeviceRepo) GetByColumn(_ context.Context, columnName, queryValue, tenantID string) ([]entity.Device, error) {
+func (r *DeviceRepo) GetByColumn(ctx context.Context, columnName, queryValue, tenantID string) ([]entity.Device, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, _, err := r.Builder.
 		Select(
 			"guid",
@@ -469,15 +536,17 @@ func (r *DeviceRepo) GetByColumn(_ context.Context, columnName, queryValue, tena
 			"usetls",
 			"allowselfsigned",
 			"certhash",
+			"version",
 		).
 		From("devices").
 		Where(columnName+" = ? AND tenantid = ?", queryValue, tenantID).
+		Where(notDeleted).
 		ToSql()
 	if err != nil {
 		return nil, ErrDeviceDatabase.Wrap("Get", "r.Builder: ", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, queryValue, tenantID)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, queryValue, tenantID)
 	if err != nil {
 		return nil, ErrDeviceDatabase.Wrap("Get", "r.Pool.Query", err)
 	}
@@ -493,7 +562,7 @@ func (r *DeviceRepo) GetByColumn(_ context.Context, columnName, queryValue, tena
 	for rows.Next() {
 		d := entity.Device{}
 
-		err = rows.Scan(&d.GUID, &d.Hostname, &d.Tags, &d.MPSInstance, &d.ConnectionStatus, &d.MPSUsername, &d.TenantID, &d.FriendlyName, &d.DNSSuffix, &d.DeviceInfo, &d.Username, &d.Password, &d.UseTLS, &d.AllowSelfSigned, &d.CertHash)
+		err = rows.Scan(&d.GUID, &d.Hostname, &d.Tags, &d.MPSInstance, &d.ConnectionStatus, &d.MPSUsername, &d.TenantID, &d.FriendlyName, &d.DNSSuffix, &d.DeviceInfo, &d.Username, &d.Password, &d.UseTLS, &d.AllowSelfSigned, &d.CertHash, &d.Version)
 		if err != nil {
 			return nil, ErrDeviceDatabase.Wrap("Get", "rows.Scan: ", err)
 		}
@@ -51,14 +51,44 @@ func setupDeviceTable(t *testing.T) *sql.DB {
 			certhash TEXT NOT NULL DEFAULT '',
 			lastconnected TEXT,
 			lastdisconnected TEXT,
-			lastseen TEXT
+			lastseen TEXT,
+			deleted_at TEXT,
+			version TEXT NOT NULL DEFAULT '1'
 		);
-	`)
+	`+deviceTagsSchema)
 	require.NoError(t, err)
 
 	return dbConn
 }
 
+// deviceTagsSchema creates the device_tags table that tag queries read, and
+// the device_group_members table an insert purges a trashed device's rows of.
+const deviceTagsSchema = `
+	CREATE TABLE device_tags (
+		guid TEXT NOT NULL,
+		tenant_id TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (guid, tenant_id, tag)
+	);
+	CREATE TABLE device_group_members (
+		group_id TEXT NOT NULL,
+		guid TEXT NOT NULL,
+		dynamic BOOLEAN NOT NULL,
+		tenant_id TEXT NOT NULL,
+		PRIMARY KEY (group_id, guid, dynamic)
+	);
+`
+
+// insertDeviceTags tags a device the way the repositories do.
+func insertDeviceTags(t *testing.T, dbConn *sql.DB, guid, tenantID string, tags ...string) {
+	t.Helper()
+
+	for _, tag := range tags {
+		_, err := dbConn.ExecContext(context.Background(), `INSERT INTO device_tags (guid, tenant_id, tag) VALUES (?, ?, ?)`, guid, tenantID, tag)
+		require.NoError(t, err)
+	}
+}
+
 // assertDeviceResults does a shallow check on device slice equality (len + type).
 func assertDeviceResults(t *testing.T, expected, actual []entity.Device) {
 	t.Helper()
@@ -385,6 +415,9 @@ func TestDeviceRepo_GetDistinctTags(t *testing.T) {
 				require.NoError(t, err)
 				_, err = dbConn.ExecContext(context.Background(), `INSERT INTO devices (guid, tags, tenantid) VALUES (?, ?, ?)`, "guid3", "tag1", "tenant1")
 				require.NoError(t, err)
+				insertDeviceTags(t, dbConn, "guid1", "tenant1", "tag1")
+				insertDeviceTags(t, dbConn, "guid2", "tenant1", "tag2")
+				insertDeviceTags(t, dbConn, "guid3", "tenant1", "tag1")
 			},
 			tenantID: "tenant1",
 			expected: []string{"tag1", "tag2"},
@@ -457,6 +490,7 @@ func TestDeviceRepo_GetByTags(t *testing.T) {
 				_, err := dbConn.ExecContext(context.Background(), `INSERT INTO devices (guid, hostname, tags, mpsinstance, connectionstatus, mpsusername, tenantid, friendlyname, dnssuffix, deviceinfo) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 					"guid1", "hostname1", ",tag1,tag2,", "mpsinstance1", true, "mpsusername1", "tenant1", "friendlyname1", "dnssuffix1", "deviceinfo1")
 				require.NoError(t, err)
+				insertDeviceTags(t, dbConn, "guid1", "tenant1", "tag1", "tag2")
 			},
 			tags:     []string{"tag1", "tag2"},
 			method:   "AND",
@@ -485,6 +519,7 @@ func TestDeviceRepo_GetByTags(t *testing.T) {
 				_, err := dbConn.ExecContext(context.Background(), `INSERT INTO devices (guid, hostname, tags, mpsinstance, connectionstatus, mpsusername, tenantid, friendlyname, dnssuffix, deviceinfo) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 					"guid1", "hostname1", ",tag1,", "mpsinstance1", true, "mpsusername1", "tenant1", "friendlyname1", "dnssuffix1", "deviceinfo1")
 				require.NoError(t, err)
+				insertDeviceTags(t, dbConn, "guid1", "tenant1", "tag1")
 			},
 			tags:     []string{"tag1", "tag2"},
 			method:   "OR",
@@ -534,6 +569,7 @@ func TestDeviceRepo_GetByTags(t *testing.T) {
 				_, err := dbConn.ExecContext(context.Background(), `INSERT INTO devices (guid, hostname, tags, mpsinstance, connectionstatus, mpsusername, tenantid, friendlyname, dnssuffix, deviceinfo) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 					"guid1", "hostname1", ",tag1,", "mpsinstance1", true, "mpsusername1", "tenant1", "friendlyname1", "dnssuffix1", "deviceinfo1")
 				require.NoError(t, err)
+				insertDeviceTags(t, dbConn, "guid1", "tenant1", "tag1")
 			},
 			tags:     []string{"tag1"},
 			method:   "AND",
@@ -565,9 +601,11 @@ func TestDeviceRepo_GetByTags(t *testing.T) {
                     tenantid TEXT NOT NULL,
                     friendlyname TEXT NOT NULL DEFAULT '',
                     dnssuffix TEXT NOT NULL DEFAULT '',
-                    deviceinfo TEXT NOT NULL DEFAULT ''
+                    deviceinfo TEXT NOT NULL DEFAULT '',
+                    deleted_at TEXT,
+                    version TEXT NOT NULL DEFAULT '1'
                 );
-            `)
+            `+deviceTagsSchema)
 			require.NoError(t, err)
 
 			tc.setup(dbConn)
@@ -664,7 +702,9 @@ func TestDeviceRepo_Delete(t *testing.T) {
 					mebxpassword TEXT,
 					usetls BOOLEAN NOT NULL DEFAULT FALSE,
 					allowselfsigned BOOLEAN NOT NULL DEFAULT FALSE,
-					certhash TEXT NOT NULL DEFAULT ''
+					certhash TEXT NOT NULL DEFAULT '',
+					deleted_at TEXT,
+					version TEXT NOT NULL DEFAULT '1'
 				);
 			`)
 			require.NoError(t, err)
@@ -815,9 +855,11 @@ func TestDeviceRepo_Update(t *testing.T) {
 					mebxpassword TEXT,
 					usetls BOOLEAN NOT NULL DEFAULT FALSE,
 					allowselfsigned BOOLEAN NOT NULL DEFAULT FALSE,
-					certhash TEXT NOT NULL DEFAULT ''
+					certhash TEXT NOT NULL DEFAULT '',
+					deleted_at TEXT,
+					version TEXT NOT NULL DEFAULT '1'
 				);
-			`)
+			`+deviceTagsSchema)
 			require.NoError(t, err)
 
 			tc.setup(dbConn)
@@ -857,11 +899,10 @@ func TestDeviceRepo_Insert(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name     string
-		setup    func(dbConn *sql.DB)
-		device   *entity.Device
-		expected string
-		err      error
+		name   string
+		setup  func(dbConn *sql.DB)
+		device *entity.Device
+		err    error
 	}{
 		{
 			name:  "Successful insert",
@@ -883,8 +924,7 @@ func TestDeviceRepo_Insert(t *testing.T) {
 				AllowSelfSigned:  false,
 				CertHash:         Certhash,
 			},
-			expected: "",
-			err:      nil,
+			err: nil,
 		},
 		{
 			name: "Insert with not unique error",
@@ -910,8 +950,7 @@ func TestDeviceRepo_Insert(t *testing.T) {
 				AllowSelfSigned:  false,
 				CertHash:         Certhash,
 			},
-			expected: "",
-			err:      repoerrors.NotUniqueError{},
+			err: repoerrors.NotUniqueError{},
 		},
 		{
 			name:  QueryExecutionErrorTestName,
@@ -933,8 +972,7 @@ func TestDeviceRepo_Insert(t *testing.T) {
 				AllowSelfSigned:  false,
 				CertHash:         Certhash,
 			},
-			expected: "",
-			err:      repoerrors.DatabaseError{},
+			err: repoerrors.DatabaseError{},
 		},
 	}
 
@@ -963,8 +1001,8 @@ func TestDeviceRepo_Insert(t *testing.T) {
 
 			checkDeviceError(t, err, tc.err)
 
-			if version != tc.expected {
-				t.Errorf("Expected version %v, got %v", tc.expected, version)
+			if (err == nil) != (version != "") {
+				t.Errorf("Expected a version only on success, got %q", version)
 			}
 		})
 	}
@@ -1070,7 +1108,9 @@ func TestDeviceRepo_GetByColumn(t *testing.T) {
                     password TEXT NOT NULL DEFAULT '',
                     usetls BOOLEAN NOT NULL DEFAULT FALSE,
                     allowselfsigned BOOLEAN NOT NULL DEFAULT FALSE,
-					certhash TEXT NOT NULL DEFAULT ''
+					certhash TEXT NOT NULL DEFAULT '',
+					deleted_at TEXT,
+					version TEXT NOT NULL DEFAULT '1'
                 );
             `)
 			require.NoError(t, err)
@@ -1292,3 +1332,21 @@ func TestDeviceRepo_UpdateLastSeen(t *testing.T) {
 		})
 	}
 }
+
+func TestDeviceRepo_HonoursContext(t *testing.T) {
+	t.Parallel()
+
+	dbConn := setupDeviceTable(t)
+	defer dbConn.Close()
+
+	repo := sqldb.NewDeviceRepo(CreateSQLConfig(dbConn, false), mocks.NewMockLogger(nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.GetByTags(ctx, []string{"lab"}, "OR", 0, 0, "tenant1")
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = repo.Get(ctx, 10, 0, "tenant1")
+	require.ErrorIs(t, err, context.Canceled)
+}
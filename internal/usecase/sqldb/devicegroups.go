@@ -0,0 +1,781 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/db"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// DeviceGroupRepo -.
+type DeviceGroupRepo struct {
+	*db.SQL
+	log logger.Interface
+}
+
+var (
+	ErrDeviceGroupDatabase  = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("DeviceGroupRepo")}
+	ErrDeviceGroupNotUnique = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("DeviceGroupRepo")}
+)
+
+var deviceGroupColumns = []string{
+	"id",
+	"name",
+	"description",
+	"parent_id",
+	"creation_date",
+	"created_by",
+	"tenant_id",
+}
+
+// memberColumns are the device columns of a group's members; their secrets
+// are left out.
+var memberColumns = []string{
+	"guid",
+	"hostname",
+	"tags",
+	"mpsinstance",
+	"connectionstatus",
+	"mpsusername",
+	"tenantid",
+	"friendlyname",
+	"dnssuffix",
+	"deviceinfo",
+	"usetls",
+	"allowselfsigned",
+	"version",
+}
+
+// memberChunk bounds the GUIDs bound to one statement, well below the
+// parameter limits of SQLite and Postgres.
+const memberChunk = 500
+
+// NewDeviceGroupRepo -.
+func NewDeviceGroupRepo(database *db.SQL, log logger.Interface) *DeviceGroupRepo {
+	return &DeviceGroupRepo{database, log}
+}
+
+// GetCount -.
+func (r *DeviceGroupRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select("COUNT(*)").
+		From("device_groups").
+		Where("tenant_id = ?", tenantID).
+		ToSql()
+	if err != nil {
+		return 0, ErrDeviceGroupDatabase.Wrap("GetCount", "r.Builder: ", err)
+	}
+
+	var count int
+
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
+	if err != nil {
+		return 0, ErrDeviceGroupDatabase.Wrap("GetCount", "r.Pool.QueryRow", err)
+	}
+
+	return count, nil
+}
+
+// Get -.
+func (r *DeviceGroupRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.DeviceGroup, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	const defaultTop = 100
+
+	if top == 0 {
+		top = defaultTop
+	}
+
+	limitedTop := uint64(defaultTop)
+	if top > 0 {
+		limitedTop = uint64(top)
+	}
+
+	limitedSkip := uint64(0)
+	if skip > 0 {
+		limitedSkip = uint64(skip)
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Select(deviceGroupColumns...).
+		From("device_groups").
+		Where("tenant_id = ?", tenantID).
+		OrderBy("name").
+		Limit(limitedTop).
+		Offset(limitedSkip).
+		ToSql()
+	if err != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("Get", "r.Builder: ", err)
+	}
+
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("Get", "r.Pool.Query", err)
+	}
+
+	defer rows.Close()
+
+	if rows.Err() != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("Get", "rows.Err", rows.Err())
+	}
+
+	groups := make([]entity.DeviceGroup, 0)
+
+	for rows.Next() {
+		g := entity.DeviceGroup{}
+
+		if err := scanDeviceGroup(rows, &g); err != nil {
+			return nil, ErrDeviceGroupDatabase.Wrap("Get", "rows.Scan: ", err)
+		}
+
+		groups = append(groups, g)
+	}
+
+	if err := r.withRules(ctx, groups); err != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("Get", "r.withRules", err)
+	}
+
+	return groups, nil
+}
+
+// GetByID -.
+func (r *DeviceGroupRepo) GetByID(ctx context.Context, id, tenantID string) (*entity.DeviceGroup, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select(deviceGroupColumns...).
+		From("device_groups").
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		ToSql()
+	if err != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("GetByID", "r.Builder: ", err)
+	}
+
+	g := entity.DeviceGroup{}
+
+	err = scanDeviceGroup(r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...), &g)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, ErrDeviceGroupDatabase.Wrap("GetByID", "row.Scan: ", err)
+	}
+
+	groups := []entity.DeviceGroup{g}
+
+	if err := r.withRules(ctx, groups); err != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("GetByID", "r.withRules", err)
+	}
+
+	return &groups[0], nil
+}
+
+// Delete deletes the group with its rules and members.
+func (r *DeviceGroupRepo) Delete(ctx context.Context, id, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	var deleted bool
+
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		if err := r.deleteChildren(ctx, id); err != nil {
+			return err
+		}
+
+		sqlQuery, args, err := r.Builder.
+			Delete("device_groups").
+			Where("id = ? AND tenant_id = ?", id, tenantID).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		deleted = n > 0
+
+		return err
+	})
+	if err != nil {
+		return false, ErrDeviceGroupDatabase.Wrap("Delete", "r.InTx", err)
+	}
+
+	return deleted, nil
+}
+
+// Update replaces the name, description, parent and rules.
+func (r *DeviceGroupRepo) Update(ctx context.Context, g *entity.DeviceGroup) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	var updated bool
+
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		sqlQuery, args, err := r.Builder.
+			Update("device_groups").
+			Set("name", g.Name).
+			Set("description", g.Description).
+			Set("parent_id", nullIfEmpty(g.ParentID)).
+			Where("id = ? AND tenant_id = ?", g.ID, g.TenantID).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil || n == 0 {
+			return err
+		}
+
+		updated = true
+
+		sqlQuery, args, err = r.Builder.
+			Delete("device_group_rules").
+			Where("group_id = ?", g.ID).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+			return err
+		}
+
+		return r.insertRules(ctx, g)
+	})
+	if err != nil {
+		if db.CheckNotUnique(err) {
+			return false, ErrDeviceGroupNotUnique.Wrap(err.Error())
+		}
+
+		return false, ErrDeviceGroupDatabase.Wrap("Update", "r.InTx", err)
+	}
+
+	return updated, nil
+}
+
+// Insert -.
+func (r *DeviceGroupRepo) Insert(ctx context.Context, g *entity.DeviceGroup) error {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		sqlQuery, args, err := r.Builder.
+			Insert("device_groups").
+			Columns(deviceGroupColumns...).
+			Values(g.ID, g.Name, g.Description, nullIfEmpty(g.ParentID), g.CreationDate, g.CreatedBy, g.TenantID).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+			return err
+		}
+
+		return r.insertRules(ctx, g)
+	})
+	if err != nil {
+		if db.CheckNotUnique(err) {
+			return ErrDeviceGroupNotUnique.Wrap(err.Error())
+		}
+
+		return ErrDeviceGroupDatabase.Wrap("Insert", "r.InTx", err)
+	}
+
+	return nil
+}
+
+// GetMemberCount -.
+func (r *DeviceGroupRepo) GetMemberCount(ctx context.Context, ids []string, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	sqlQuery, args, err := r.members(ids, tenantID, "COUNT(*)").ToSql()
+	if err != nil {
+		return 0, ErrDeviceGroupDatabase.Wrap("GetMemberCount", "r.Builder: ", err)
+	}
+
+	var count int
+
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
+	if err != nil {
+		return 0, ErrDeviceGroupDatabase.Wrap("GetMemberCount", "r.Pool.QueryRow", err)
+	}
+
+	return count, nil
+}
+
+// GetMembers -.
+func (r *DeviceGroupRepo) GetMembers(ctx context.Context, ids []string, top, skip int, tenantID string) ([]entity.Device, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	if len(ids) == 0 {
+		return []entity.Device{}, nil
+	}
+
+	const defaultTop = 100
+
+	if top == 0 {
+		top = defaultTop
+	}
+
+	limitedTop := uint64(defaultTop)
+	if top > 0 {
+		limitedTop = uint64(top)
+	}
+
+	limitedSkip := uint64(0)
+	if skip > 0 {
+		limitedSkip = uint64(skip)
+	}
+
+	sqlQuery, args, err := r.members(ids, tenantID, memberColumns...).
+		OrderBy("guid").
+		Limit(limitedTop).
+		Offset(limitedSkip).
+		ToSql()
+	if err != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("GetMembers", "r.Builder: ", err)
+	}
+
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("GetMembers", "r.Pool.Query", err)
+	}
+
+	defer rows.Close()
+
+	if rows.Err() != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("GetMembers", "rows.Err", rows.Err())
+	}
+
+	devices := make([]entity.Device, 0)
+
+	for rows.Next() {
+		var d entity.Device
+
+		err := rows.Scan(&d.GUID, &d.Hostname, &d.Tags, &d.MPSInstance, &d.ConnectionStatus, &d.MPSUsername, &d.TenantID, &d.FriendlyName, &d.DNSSuffix, &d.DeviceInfo, &d.UseTLS, &d.AllowSelfSigned, &d.Version)
+		if err != nil {
+			return nil, ErrDeviceGroupDatabase.Wrap("GetMembers", "rows.Scan", err)
+		}
+
+		devices = append(devices, d)
+	}
+
+	return devices, nil
+}
+
+// GetMemberGUIDs -.
+func (r *DeviceGroupRepo) GetMemberGUIDs(ctx context.Context, ids []string, tenantID string) ([]string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	if len(ids) == 0 {
+		return []string{}, nil
+	}
+
+	sqlQuery, args, err := r.members(ids, tenantID, "guid").OrderBy("guid").ToSql()
+	if err != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("GetMemberGUIDs", "r.Builder: ", err)
+	}
+
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("GetMemberGUIDs", "r.Pool.Query", err)
+	}
+
+	defer rows.Close()
+
+	if rows.Err() != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("GetMemberGUIDs", "rows.Err", rows.Err())
+	}
+
+	guids := make([]string, 0)
+
+	for rows.Next() {
+		var guid string
+		if err := rows.Scan(&guid); err != nil {
+			return nil, ErrDeviceGroupDatabase.Wrap("GetMemberGUIDs", "rows.Scan", err)
+		}
+
+		guids = append(guids, guid)
+	}
+
+	return guids, nil
+}
+
+// GetMemberships -.
+func (r *DeviceGroupRepo) GetMemberships(ctx context.Context, id, tenantID string) ([]entity.DeviceGroupMember, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select("group_id", "guid", "dynamic", "tenant_id").
+		From("device_group_members").
+		Where("group_id = ? AND tenant_id = ?", id, tenantID).
+		OrderBy("guid", "dynamic").
+		ToSql()
+	if err != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("GetMemberships", "r.Builder: ", err)
+	}
+
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("GetMemberships", "r.Pool.Query", err)
+	}
+
+	defer rows.Close()
+
+	if rows.Err() != nil {
+		return nil, ErrDeviceGroupDatabase.Wrap("GetMemberships", "rows.Err", rows.Err())
+	}
+
+	members := make([]entity.DeviceGroupMember, 0)
+
+	for rows.Next() {
+		var m entity.DeviceGroupMember
+		if err := rows.Scan(&m.GroupID, &m.GUID, &m.Dynamic, &m.TenantID); err != nil {
+			return nil, ErrDeviceGroupDatabase.Wrap("GetMemberships", "rows.Scan", err)
+		}
+
+		members = append(members, m)
+	}
+
+	return members, nil
+}
+
+// AddMembers -.
+func (r *DeviceGroupRepo) AddMembers(ctx context.Context, id string, guids []string, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	var added int
+
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		n, err := r.insertMembers(ctx, id, guids, false, tenantID)
+		added = n
+
+		return err
+	})
+	if err != nil {
+		return 0, ErrDeviceGroupDatabase.Wrap("AddMembers", "r.InTx", err)
+	}
+
+	return added, nil
+}
+
+// RemoveMembers removes members added by hand.
+func (r *DeviceGroupRepo) RemoveMembers(ctx context.Context, id string, guids []string, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	var removed int
+
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		for chunk := range slices.Chunk(guids, memberChunk) {
+			sqlQuery, args, err := r.Builder.
+				Delete("device_group_members").
+				Where(squirrel.Eq{"group_id": id, "tenant_id": tenantID, "dynamic": false, "guid": chunk}).
+				ToSql()
+			if err != nil {
+				return err
+			}
+
+			res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+			if err != nil {
+				return err
+			}
+
+			n, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+
+			removed += int(n)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, ErrDeviceGroupDatabase.Wrap("RemoveMembers", "r.InTx", err)
+	}
+
+	return removed, nil
+}
+
+// SetMatches -.
+func (r *DeviceGroupRepo) SetMatches(ctx context.Context, id string, guids []string, tenantID string) error {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		sqlQuery, args, err := r.Builder.
+			Delete("device_group_members").
+			Where(squirrel.Eq{"group_id": id, "tenant_id": tenantID, "dynamic": true}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+			return err
+		}
+
+		_, err = r.insertMembers(ctx, id, guids, true, tenantID)
+
+		return err
+	})
+	if err != nil {
+		return ErrDeviceGroupDatabase.Wrap("SetMatches", "r.InTx", err)
+	}
+
+	return nil
+}
+
+// SetDeviceMatches -.
+func (r *DeviceGroupRepo) SetDeviceMatches(ctx context.Context, guid string, ids []string, tenantID string) error {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		sqlQuery, args, err := r.Builder.
+			Delete("device_group_members").
+			Where(squirrel.Eq{"guid": guid, "tenant_id": tenantID, "dynamic": true}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		// Groups deleted meanwhile are skipped.
+		sqlQuery, args, err = r.Builder.
+			Insert("device_group_members").
+			Columns("group_id", "guid", "dynamic", "tenant_id").
+			Select(squirrel.Select("id").
+				Column("?", guid).
+				Column("TRUE").
+				Column("tenant_id").
+				From("device_groups").
+				Where(squirrel.Eq{"tenant_id": tenantID, "id": ids})).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+
+		return err
+	})
+	if err != nil {
+		return ErrDeviceGroupDatabase.Wrap("SetDeviceMatches", "r.InTx", err)
+	}
+
+	return nil
+}
+
+// members selects columns of the live devices that are members of any of
+// the groups ids.
+func (r *DeviceGroupRepo) members(ids []string, tenantID string, columns ...string) squirrel.SelectBuilder {
+	return r.Builder.
+		Select(columns...).
+		From("devices").
+		Where("tenantid = ?", tenantID).
+		Where(notDeleted).
+		Where(squirrel.Expr("guid IN (?)", squirrel.Select("guid").
+			From("device_group_members").
+			Where(squirrel.Eq{"tenant_id": tenantID, "group_id": ids})))
+}
+
+// insertMembers makes the live devices of guids members of group id, and
+// returns how many were not already.
+func (r *DeviceGroupRepo) insertMembers(ctx context.Context, id string, guids []string, dynamic bool, tenantID string) (int, error) {
+	// Literal, so that Postgres needn't infer the type of a selected
+	// parameter.
+	flag := "FALSE"
+	if dynamic {
+		flag = "TRUE"
+	}
+
+	inserted := 0
+
+	for chunk := range slices.Chunk(guids, memberChunk) {
+		sqlQuery, args, err := r.Builder.
+			Insert("device_group_members").
+			Columns("group_id", "guid", "dynamic", "tenant_id").
+			Select(squirrel.Select().
+				Column("?", id).
+				Column("guid").
+				Column(flag).
+				Column("tenantid").
+				From("devices").
+				Where(squirrel.Eq{"tenantid": tenantID, "guid": chunk}).
+				Where(notDeleted)).
+			Suffix("ON CONFLICT DO NOTHING").
+			ToSql()
+		if err != nil {
+			return inserted, err
+		}
+
+		res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+		if err != nil {
+			return inserted, err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return inserted, err
+		}
+
+		inserted += int(n)
+	}
+
+	return inserted, nil
+}
+
+func (r *DeviceGroupRepo) insertRules(ctx context.Context, g *entity.DeviceGroup) error {
+	if len(g.Rules) == 0 {
+		return nil
+	}
+
+	builder := r.Builder.
+		Insert("device_group_rules").
+		Columns("group_id", "position", "field", "operator", "value")
+
+	for i, rule := range g.Rules {
+		builder = builder.Values(g.ID, i, rule.Field, rule.Operator, rule.Value)
+	}
+
+	sqlQuery, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+
+	return err
+}
+
+// deleteChildren deletes the rules and members of group id, which the
+// schema cascades to only where foreign keys are enforced.
+func (r *DeviceGroupRepo) deleteChildren(ctx context.Context, id string) error {
+	for _, table := range []string{"device_group_rules", "device_group_members"} {
+		sqlQuery, args, err := r.Builder.Delete(table).Where("group_id = ?", id).ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withRules loads the rules of groups, in order.
+func (r *DeviceGroupRepo) withRules(ctx context.Context, groups []entity.DeviceGroup) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(groups))
+	ids := make([]string, len(groups))
+
+	for i := range groups {
+		index[groups[i].ID] = i
+		ids[i] = groups[i].ID
+		groups[i].Rules = []entity.DeviceGroupRule{}
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Select("group_id", "field", "operator", "value").
+		From("device_group_rules").
+		Where(squirrel.Eq{"group_id": ids}).
+		OrderBy("group_id", "position").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			groupID string
+			rule    entity.DeviceGroupRule
+		)
+
+		if err := rows.Scan(&groupID, &rule.Field, &rule.Operator, &rule.Value); err != nil {
+			return err
+		}
+
+		if i, ok := index[groupID]; ok {
+			groups[i].Rules = append(groups[i].Rules, rule)
+		}
+	}
+
+	return rows.Err()
+}
+
+func scanDeviceGroup(row interface{ Scan(dest ...any) error }, g *entity.DeviceGroup) error {
+	var description, parentID, creationDate, createdBy sql.NullString
+
+	if err := row.Scan(&g.ID, &g.Name, &description, &parentID, &creationDate, &createdBy, &g.TenantID); err != nil {
+		return err
+	}
+
+	g.Description, g.ParentID = description.String, parentID.String
+	g.CreationDate, g.CreatedBy = creationDate.String, createdBy.String
+
+	return nil
+}
+
+// nullIfEmpty stores an empty reference as NULL, which foreign keys allow.
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+
+	return value
+}
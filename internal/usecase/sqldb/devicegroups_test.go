@@ -0,0 +1,171 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/sqldb"
+	"github.com/device-management-toolkit/console/pkg/db"
+)
+
+func setupDeviceGroupRepo(t *testing.T) (*sqldb.DeviceGroupRepo, *sql.DB) {
+	t.Helper()
+
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	// One connection, so that every statement sees the same in-memory
+	// database.
+	dbConn.SetMaxOpenConns(1)
+	t.Cleanup(func() { dbConn.Close() })
+
+	_, err = dbConn.ExecContext(context.Background(), schema)
+	require.NoError(t, err)
+
+	return sqldb.NewDeviceGroupRepo(&db.SQL{
+		Builder:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		Pool:       dbConn,
+		IsEmbedded: true,
+	}, mocks.NewMockLogger(nil)), dbConn
+}
+
+func TestDeviceGroupRepo_InsertAndGet(t *testing.T) {
+	t.Parallel()
+
+	repo, _ := setupDeviceGroupRepo(t)
+	ctx := context.Background()
+
+	lab := &entity.DeviceGroup{
+		ID:   "1",
+		Name: "lab",
+		Rules: []entity.DeviceGroupRule{
+			{Field: "hostname", Operator: "match", Value: "lab-*"},
+			{Field: "connectionStatus", Operator: "eq", Value: "true"},
+		},
+		CreationDate: "2026-10-19T00:00:00Z",
+		CreatedBy:    "admin",
+	}
+
+	require.NoError(t, repo.Insert(ctx, lab))
+	require.NoError(t, repo.Insert(ctx, &entity.DeviceGroup{ID: "2", Name: "bench", ParentID: "1", Rules: []entity.DeviceGroupRule{}}))
+
+	got, err := repo.GetByID(ctx, "1", "")
+	require.NoError(t, err)
+	require.Equal(t, lab, got)
+
+	missing, err := repo.GetByID(ctx, "1", "tenant-b")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	groups, err := repo.Get(ctx, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	require.Equal(t, "bench", groups[0].Name, "groups are listed by name")
+	require.Equal(t, "1", groups[0].ParentID)
+	require.Len(t, groups[1].Rules, 2)
+
+	count, err := repo.GetCount(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	err = repo.Insert(ctx, &entity.DeviceGroup{ID: "3", Name: "lab"})
+	require.ErrorAs(t, err, &repoerrors.NotUniqueError{})
+
+	lab.Rules = lab.Rules[:1]
+	lab.Description = "lab machines"
+
+	updated, err := repo.Update(ctx, lab)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	got, err = repo.GetByID(ctx, "1", "")
+	require.NoError(t, err)
+	require.Equal(t, lab, got)
+
+	updated, err = repo.Update(ctx, &entity.DeviceGroup{ID: "ghost", Name: "ghost"})
+	require.NoError(t, err)
+	require.False(t, updated)
+}
+
+func TestDeviceGroupRepo_Members(t *testing.T) {
+	t.Parallel()
+
+	repo, dbConn := setupDeviceGroupRepo(t)
+	ctx := context.Background()
+
+	for _, guid := range []string{"a", "b", "c"} {
+		_, err := dbConn.ExecContext(ctx, `INSERT INTO devices (guid, hostname, tags, mpsinstance, connectionstatus, mpsusername, tenantid, friendlyname, dnssuffix, deviceinfo, usetls, allowselfsigned)
+			VALUES (?, ?, '', '', false, '', '', '', '', '', false, false)`, guid, "host-"+guid)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, repo.Insert(ctx, &entity.DeviceGroup{ID: "1", Name: "parent"}))
+	require.NoError(t, repo.Insert(ctx, &entity.DeviceGroup{ID: "2", Name: "child", ParentID: "1"}))
+
+	// Unknown devices are skipped, and so are members added already.
+	added, err := repo.AddMembers(ctx, "1", []string{"a", "b", "ghost"}, "")
+	require.NoError(t, err)
+	require.Equal(t, 2, added)
+
+	added, err = repo.AddMembers(ctx, "1", []string{"a"}, "")
+	require.NoError(t, err)
+	require.Equal(t, 0, added)
+
+	require.NoError(t, repo.SetMatches(ctx, "2", []string{"b", "c"}, ""))
+
+	members, err := repo.GetMembers(ctx, []string{"1", "2"}, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, members, 3, "a device in both groups is listed once")
+	require.Equal(t, "host-a", members[0].Hostname)
+
+	count, err := repo.GetMemberCount(ctx, []string{"1", "2"}, "")
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	guids, err := repo.GetMemberGUIDs(ctx, []string{"2"}, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "c"}, guids)
+
+	// A device's matches replace only its own.
+	require.NoError(t, repo.SetDeviceMatches(ctx, "c", []string{"1", "ghost"}, ""))
+
+	memberships, err := repo.GetMemberships(ctx, "1", "")
+	require.NoError(t, err)
+	require.Equal(t, []entity.DeviceGroupMember{
+		{GroupID: "1", GUID: "a"},
+		{GroupID: "1", GUID: "b"},
+		{GroupID: "1", GUID: "c", Dynamic: true},
+	}, memberships)
+
+	guids, err = repo.GetMemberGUIDs(ctx, []string{"2"}, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, guids)
+
+	// Removing by hand leaves the matches.
+	removed, err := repo.RemoveMembers(ctx, "1", []string{"a", "c"}, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	// Devices in the trash are no members.
+	_, err = dbConn.ExecContext(ctx, "UPDATE devices SET deleted_at = '2026-10-19T00:00:00Z' WHERE guid = 'b'")
+	require.NoError(t, err)
+
+	guids, err = repo.GetMemberGUIDs(ctx, []string{"1", "2"}, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"c"}, guids)
+
+	deleted, err := repo.Delete(ctx, "2", "")
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	memberships, err = repo.GetMemberships(ctx, "2", "")
+	require.NoError(t, err)
+	require.Empty(t, memberships)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/versions"
 	"github.com/device-management-toolkit/console/pkg/consoleerrors"
 	"github.com/device-management-toolkit/console/pkg/db"
 	"github.com/device-management-toolkit/console/pkg/logger"
@@ -29,20 +30,35 @@ func NewDomainRepo(database *db.SQL, log logger.Interface) *DomainRepo {
 	return &DomainRepo{database, log}
 }
 
+// domainColumns are the columns of the domains' OData fields.
+var domainColumns = odataColumns{
+	"profileName":                   "name",
+	"domainSuffix":                  "domain_suffix",
+	"provisioningCertStorageFormat": "provisioning_cert_storage_format",
+}
+
 // GetCount -.
-func (r *DomainRepo) GetCount(_ context.Context, tenantID string) (int, error) {
-	sqlQuery, _, err := r.Builder.
+func (r *DomainRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	builder, err := odataWhere(ctx, r.Builder.
 		Select("COUNT(*) OVER() AS total_count").
 		From("domains").
 		Where("tenant_id = ?", tenantID).
-		ToSql()
+		Where(notDeleted), domainColumns)
+	if err != nil {
+		return 0, ErrDomainDatabase.Wrap("GetCount", "odataWhere", err)
+	}
+
+	sqlQuery, args, err := builder.ToSql()
 	if err != nil {
 		return 0, ErrDomainDatabase.Wrap("GetCount", "r.Builder: ", err)
 	}
 
 	var count int
 
-	err = r.Pool.QueryRowContext(context.Background(), sqlQuery, tenantID).Scan(&count)
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return 0, nil
@@ -55,7 +71,10 @@ func (r *DomainRepo) GetCount(_ context.Context, tenantID string) (int, error) {
 }
 
 // Get -.
-func (r *DomainRepo) Get(_ context.Context, top, skip int, tenantID string) ([]entity.Domain, error) {
+func (r *DomainRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.Domain, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	const defaultTop = 100
 
 	if top == 0 {
@@ -72,17 +91,28 @@ func (r *DomainRepo) Get(_ context.Context, top, skip int, tenantID string) ([]e
 		limitedSkip = uint64(skip)
 	}
 
-	sqlQuery, _, err := r.Builder.
+	builder, err := odataWhere(ctx, r.Builder.
 		Select("name",
 			"domain_suffix",
 			"provisioning_cert",
 			"provisioning_cert_storage_format",
 			"provisioning_cert_key",
 			"expiration_date",
-			"tenant_id").
+			"tenant_id",
+			"version").
 		From("domains").
 		Where("tenant_id = ?", tenantID).
-		OrderBy("name").
+		Where(notDeleted), domainColumns)
+	if err != nil {
+		return nil, ErrDomainDatabase.Wrap("Get", "odataWhere", err)
+	}
+
+	builder, err = odataOrderBy(ctx, builder, domainColumns, "name")
+	if err != nil {
+		return nil, ErrDomainDatabase.Wrap("Get", "odataOrderBy", err)
+	}
+
+	sqlQuery, args, err := builder.
 		Limit(limitedTop).
 		Offset(limitedSkip).
 		ToSql()
@@ -90,7 +120,7 @@ func (r *DomainRepo) Get(_ context.Context, top, skip int, tenantID string) ([]e
 		return nil, ErrDomainDatabase.Wrap("Get", "r.Builder: ", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, tenantID)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, ErrDomainDatabase.Wrap("Get", "r.Pool.Query", err)
 	}
@@ -106,7 +136,7 @@ func (r *DomainRepo) Get(_ context.Context, top, skip int, tenantID string) ([]e
 	for rows.Next() {
 		d := entity.Domain{}
 
-		err = rows.Scan(&d.ProfileName, &d.DomainSuffix, &d.ProvisioningCert, &d.ProvisioningCertStorageFormat, &d.ProvisioningCertPassword, &d.ExpirationDate, &d.TenantID)
+		err = rows.Scan(&d.ProfileName, &d.DomainSuffix, &d.ProvisioningCert, &d.ProvisioningCertStorageFormat, &d.ProvisioningCertPassword, &d.ExpirationDate, &d.TenantID, &d.Version)
 		if err != nil {
 			return nil, ErrDomainDatabase.Wrap("Get", "rows.Scan: ", err)
 		}
@@ -118,7 +148,10 @@ func (r *DomainRepo) Get(_ context.Context, top, skip int, tenantID string) ([]e
 }
 
 // GetDomainByDomainSuffix -.
-func (r *DomainRepo) GetDomainByDomainSuffix(_ context.Context, domainSuffix, tenantID string) (*entity.Domain, error) {
+func (r *DomainRepo) GetDomainByDomainSuffix(ctx context.Context, domainSuffix, tenantID string) (*entity.Domain, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, _, err := r.Builder.
 		Select("name",
 			"domain_suffix",
@@ -127,19 +160,21 @@ func (r *DomainRepo) GetDomainByDomainSuffix(_ context.Context, domainSuffix, te
 			"provisioning_cert_key",
 			"expiration_date",
 			"tenant_id",
+			"version",
 		).
 		From("domains").
 		Where("domain_suffix = ? AND tenant_id = ?", domainSuffix, tenantID).
+		Where(notDeleted).
 		ToSql()
 	if err != nil {
 		return nil, ErrDomainDatabase.Wrap("GetDomainByDomainSuffix", "r.Builder: ", err)
 	}
 
-	row := r.Pool.QueryRowContext(context.Background(), sqlQuery)
+	row := r.Conn(ctx).QueryRowContext(ctx, sqlQuery)
 
 	d := entity.Domain{}
 
-	err = row.Scan(&d.ProfileName, &d.DomainSuffix, &d.ProvisioningCert, &d.ProvisioningCertStorageFormat, &d.ProvisioningCertPassword, &d.ExpirationDate, &d.TenantID)
+	err = row.Scan(&d.ProfileName, &d.DomainSuffix, &d.ProvisioningCert, &d.ProvisioningCertStorageFormat, &d.ProvisioningCertPassword, &d.ExpirationDate, &d.TenantID, &d.Version)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -152,7 +187,10 @@ func (r *DomainRepo) GetDomainByDomainSuffix(_ context.Context, domainSuffix, te
 }
 
 // GetByName -.
-func (r *DomainRepo) GetByName(_ context.Context, domainName, tenantID string) (*entity.Domain, error) {
+func (r *DomainRepo) GetByName(ctx context.Context, domainName, tenantID string) (*entity.Domain, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, args, err := r.Builder.
 		Select(
 			"name",
@@ -162,19 +200,21 @@ func (r *DomainRepo) GetByName(_ context.Context, domainName, tenantID string) (
 			"provisioning_cert_key",
 			"expiration_date",
 			"tenant_id",
+			"version",
 		).
 		From("domains").
 		Where("LOWER(name) = LOWER(?) AND tenant_id = ?", domainName, tenantID).
+		Where(byKey(ctx, "")).
 		ToSql()
 	if err != nil {
 		return nil, ErrDomainDatabase.Wrap("GetByName", "r.Builder: ", err)
 	}
 
-	row := r.Pool.QueryRowContext(context.Background(), sqlQuery, args...)
+	row := r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...)
 
 	d := entity.Domain{}
 
-	err = row.Scan(&d.ProfileName, &d.DomainSuffix, &d.ProvisioningCert, &d.ProvisioningCertStorageFormat, &d.ProvisioningCertPassword, &d.ExpirationDate, &d.TenantID)
+	err = row.Scan(&d.ProfileName, &d.DomainSuffix, &d.ProvisioningCert, &d.ProvisioningCertStorageFormat, &d.ProvisioningCertPassword, &d.ExpirationDate, &d.TenantID, &d.Version)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -186,31 +226,24 @@ func (r *DomainRepo) GetByName(_ context.Context, domainName, tenantID string) (
 	return &d, nil
 }
 
-// Delete -.
-func (r *DomainRepo) Delete(_ context.Context, domainName, tenantID string) (bool, error) {
-	sqlQuery, args, err := r.Builder.
-		Delete("domains").
-		Where("LOWER(name) = LOWER(?) AND tenant_id = ?", domainName, tenantID).
-		ToSql()
-	if err != nil {
-		return false, ErrDomainDatabase.Wrap("Delete", "r.Builder: ", err)
-	}
-
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, args...)
-	if err != nil {
-		return false, ErrDomainDatabase.Wrap("Delete", "r.Pool.Exec", err)
-	}
+// Delete moves the domain to the trash.
+func (r *DomainRepo) Delete(ctx context.Context, domainName, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
 
-	result, err := res.RowsAffected()
+	deleted, err := softDelete(ctx, r.SQL, "domains", "domain "+domainName, "LOWER(name) = LOWER(?) AND tenant_id = ?", domainName, tenantID)
 	if err != nil {
-		return false, fmt.Errorf("DomainRepo - Delete - r.Pool.Exec: %w", err)
+		return false, ErrDomainDatabase.Wrap("Delete", "softDelete", err)
 	}
 
-	return result > 0, nil
+	return deleted, nil
 }
 
 // Update -.
-func (r *DomainRepo) Update(_ context.Context, d *entity.Domain) (bool, error) {
+func (r *DomainRepo) Update(ctx context.Context, d *entity.Domain) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, args, err := r.Builder.
 		Update("domains").
 		Set("name", d.ProfileName).
@@ -219,13 +252,16 @@ func (r *DomainRepo) Update(_ context.Context, d *entity.Domain) (bool, error) {
 		Set("provisioning_cert_storage_format", d.ProvisioningCertStorageFormat).
 		Set("provisioning_cert_key", d.ProvisioningCertPassword).
 		Set("expiration_date", d.ExpirationDate).
+		Set("version", versions.New()).
 		Where("name = ? AND tenant_id = ?", d.ProfileName, d.TenantID).
+		Where(notDeleted).
+		Where(ifMatch(ctx)).
 		ToSql()
 	if err != nil {
 		return false, ErrDomainDatabase.Wrap("Update", "r.Builder: ", err)
 	}
 
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, args...)
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
 		if db.CheckNotUnique(err) {
 			return false, ErrDomainNotUnique.Wrap(err.Error())
@@ -239,39 +275,47 @@ func (r *DomainRepo) Update(_ context.Context, d *entity.Domain) (bool, error) {
 		return false, fmt.Errorf("DomainRepo - Update - r.Pool.Exec: %w", err)
 	}
 
+	if result == 0 {
+		if err := checkVersion(ctx, r.SQL, "domains", "domain "+d.ProfileName, "name = ? AND tenant_id = ?", d.ProfileName, d.TenantID); err != nil {
+			return false, err
+		}
+	}
+
 	return result > 0, nil
 }
 
 // Insert -.
-func (r *DomainRepo) Insert(_ context.Context, d *entity.Domain) (string, error) {
+func (r *DomainRepo) Insert(ctx context.Context, d *entity.Domain) (string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	version := versions.New()
+
 	insertBuilder := r.Builder.
 		Insert("domains").
-		Columns("name", "domain_suffix", "provisioning_cert", "provisioning_cert_storage_format", "provisioning_cert_key", "expiration_date", "tenant_id").
-		Values(d.ProfileName, d.DomainSuffix, d.ProvisioningCert, d.ProvisioningCertStorageFormat, d.ProvisioningCertPassword, d.ExpirationDate, d.TenantID)
-
-	if !r.IsEmbedded {
-		insertBuilder = insertBuilder.Suffix("RETURNING xmin::text")
-	}
+		Columns("name", "domain_suffix", "provisioning_cert", "provisioning_cert_storage_format", "provisioning_cert_key", "expiration_date", "tenant_id", "version").
+		Values(d.ProfileName, d.DomainSuffix, d.ProvisioningCert, d.ProvisioningCertStorageFormat, d.ProvisioningCertPassword, d.ExpirationDate, d.TenantID, version)
 
 	sqlQuery, args, err := insertBuilder.ToSql()
 	if err != nil {
 		return "", ErrDomainDatabase.Wrap("Insert", "r.Builder: ", err)
 	}
 
-	version := ""
+	err = r.InTx(ctx, func(ctx context.Context) error {
+		if err := purgeTrashed(ctx, r.SQL, entity.TrashDomains, "(tenant_id = ? AND (name = ? OR domain_suffix = ?)) OR (LOWER(name) = LOWER(?) AND LOWER(domain_suffix) = LOWER(?))", d.TenantID, d.ProfileName, d.DomainSuffix, d.ProfileName, d.DomainSuffix); err != nil {
+			return err
+		}
 
-	if r.IsEmbedded {
-		_, err = r.Pool.ExecContext(context.Background(), sqlQuery, args...)
-	} else {
-		err = r.Pool.QueryRowContext(context.Background(), sqlQuery, args...).Scan(&version)
-	}
+		_, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 
+		return err
+	})
 	if err != nil {
-		if db.CheckNotUnique(err) {
+		if db.CheckNotUnique(err) || errors.Is(err, errTrashedInUse) {
 			return "", ErrDomainNotUnique.Wrap(err.Error())
 		}
 
-		return "", ErrDomainDatabase.Wrap("Insert", "r.Pool.QueryRow", err)
+		return "", ErrDomainDatabase.Wrap("Insert", "r.InTx", err)
 	}
 
 	return version, nil
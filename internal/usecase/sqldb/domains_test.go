@@ -333,6 +333,7 @@ func TestDomainRepo_GetByName(t *testing.T) {
 				ProvisioningCertPassword:      "password",
 				ExpirationDate:                "2024-12-31",
 				TenantID:                      "tenant1",
+				Version:                       "1",
 			},
 			expectError: false,
 		},
@@ -574,11 +575,10 @@ func TestDomainRepo_Update(t *testing.T) {
 }
 
 type DomainInsertTestCase struct {
-	name     string
-	setup    func(dbConn *sql.DB)
-	domain   *entity.Domain
-	expected string
-	err      error
+	name   string
+	setup  func(dbConn *sql.DB)
+	domain *entity.Domain
+	err    error
 }
 
 func DomainInsertHelper(t *testing.T, tc DomainInsertTestCase, version string, err error) {
@@ -596,8 +596,8 @@ func DomainInsertHelper(t *testing.T, tc DomainInsertTestCase, version string, e
 		}
 	}
 
-	if version != tc.expected {
-		t.Errorf("Expected version %v, got %v", tc.expected, version)
+	if (err == nil) != (version != "") {
+		t.Errorf("Expected a version only on success, got %q", version)
 	}
 }
 
@@ -616,8 +616,7 @@ func TestDomainRepo_Insert(t *testing.T) {
 				ProvisioningCertPassword:      "password1",
 				TenantID:                      "tenant1",
 			},
-			expected: "",
-			err:      nil,
+			err: nil,
 		},
 		{
 			name: "Insert with not unique error",
@@ -634,8 +633,7 @@ func TestDomainRepo_Insert(t *testing.T) {
 				ProvisioningCertPassword:      "password1",
 				TenantID:                      "tenant1",
 			},
-			expected: "",
-			err:      repoerrors.NotUniqueError{},
+			err: repoerrors.NotUniqueError{},
 		},
 		{
 			name:  "Query execution error",
@@ -648,8 +646,7 @@ func TestDomainRepo_Insert(t *testing.T) {
 				ProvisioningCertPassword:      "password1",
 				TenantID:                      "tenant1",
 			},
-			expected: "",
-			err:      &repoerrors.DatabaseError{},
+			err: &repoerrors.DatabaseError{},
 		},
 	}
 
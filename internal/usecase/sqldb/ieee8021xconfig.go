@@ -7,6 +7,7 @@ import (
 
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/versions"
 	"github.com/device-management-toolkit/console/pkg/consoleerrors"
 	"github.com/device-management-toolkit/console/pkg/db"
 	"github.com/device-management-toolkit/console/pkg/logger"
@@ -19,8 +20,9 @@ type IEEE8021xRepo struct {
 }
 
 var (
-	ErrIEEE8021xDatabase  = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("IEEE8021xRepo")}
-	ErrIEEE8021xNotUnique = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("IEEE8021xRepo")}
+	ErrIEEE8021xDatabase   = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("IEEE8021xRepo")}
+	ErrIEEE8021xNotUnique  = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("IEEE8021xRepo")}
+	ErrIEEE8021xForeignKey = ForeignKeyViolationError{Console: consoleerrors.CreateConsoleError("IEEE8021xRepo")}
 )
 
 // New -.
@@ -29,11 +31,15 @@ func NewIEEE8021xRepo(database *db.SQL, log logger.Interface) *IEEE8021xRepo {
 }
 
 // CheckProfileExits -.
-func (r *IEEE8021xRepo) CheckProfileExists(_ context.Context, profileName, tenantID string) (bool, error) {
+func (r *IEEE8021xRepo) CheckProfileExists(ctx context.Context, profileName, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, _, err := r.Builder.
 		Select("COUNT(*)").
 		From("ieee8021xconfigs").
 		Where("profile_name = ? AND tenant_id = ?", profileName, tenantID).
+		Where(notDeleted).
 		ToSql()
 	if err != nil {
 		return false, ErrIEEE8021xDatabase.Wrap("CheckProfileExists", "r.Builder: ", err)
@@ -41,7 +47,7 @@ func (r *IEEE8021xRepo) CheckProfileExists(_ context.Context, profileName, tenan
 
 	var count int
 
-	err = r.Pool.QueryRowContext(context.Background(), sqlQuery, profileName, tenantID).Scan(&count)
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, profileName, tenantID).Scan(&count)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, nil
@@ -53,20 +59,37 @@ func (r *IEEE8021xRepo) CheckProfileExists(_ context.Context, profileName, tenan
 	return count > 0, nil
 }
 
+// ieee8021xConfigColumns are the columns of the IEEE 802.1x configs' OData
+// fields.
+var ieee8021xConfigColumns = odataColumns{
+	"profileName":            "profile_name",
+	"authenticationProtocol": "auth_protocol",
+	"pxeTimeout":             "pxe_timeout",
+	"wiredInterface":         "wired_interface",
+}
+
 // GetCount -.
-func (r *IEEE8021xRepo) GetCount(_ context.Context, tenantID string) (int, error) {
-	sqlQuery, _, err := r.Builder.
+func (r *IEEE8021xRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	builder, err := odataWhere(ctx, r.Builder.
 		Select("COUNT(*) OVER() AS total_count").
 		From("ieee8021xconfigs").
 		Where("tenant_id = ?", tenantID).
-		ToSql()
+		Where(notDeleted), ieee8021xConfigColumns)
+	if err != nil {
+		return 0, ErrIEEE8021xDatabase.Wrap("GetCount", "odataWhere", err)
+	}
+
+	sqlQuery, args, err := builder.ToSql()
 	if err != nil {
 		return 0, ErrIEEE8021xDatabase.Wrap("GetCount", "r.Builder: ", err)
 	}
 
 	var count int
 
-	err = r.Pool.QueryRowContext(context.Background(), sqlQuery, tenantID).Scan(&count)
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return 0, nil
@@ -79,7 +102,10 @@ func (r *IEEE8021xRepo) GetCount(_ context.Context, tenantID string) (int, error
 }
 
 // Get -.
-func (r *IEEE8021xRepo) Get(_ context.Context, top, skip int, tenantID string) ([]entity.IEEE8021xConfig, error) {
+func (r *IEEE8021xRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.IEEE8021xConfig, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	const defaultTop = 100
 
 	if top == 0 {
@@ -96,15 +122,27 @@ func (r *IEEE8021xRepo) Get(_ context.Context, top, skip int, tenantID string) (
 		limitedSkip = uint64(skip)
 	}
 
-	sqlQuery, _, err := r.Builder.
+	builder, err := odataWhere(ctx, r.Builder.
 		Select("profile_name",
 			"auth_Protocol",
 			"pxe_timeout",
 			"wired_interface",
 			"tenant_id",
+			"version",
 		).
 		From("ieee8021xconfigs").
 		Where("tenant_id = ?", tenantID).
+		Where(notDeleted), ieee8021xConfigColumns)
+	if err != nil {
+		return nil, ErrIEEE8021xDatabase.Wrap("Get", "odataWhere", err)
+	}
+
+	builder, err = odataOrderBy(ctx, builder, ieee8021xConfigColumns, "profile_name")
+	if err != nil {
+		return nil, ErrIEEE8021xDatabase.Wrap("Get", "odataOrderBy", err)
+	}
+
+	sqlQuery, args, err := builder.
 		Limit(limitedTop).
 		Offset(limitedSkip).
 		ToSql()
@@ -112,7 +150,7 @@ func (r *IEEE8021xRepo) Get(_ context.Context, top, skip int, tenantID string) (
 		return nil, ErrIEEE8021xDatabase.Wrap("Get", "r.Builder: ", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, tenantID)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, ErrIEEE8021xDatabase.Wrap("Get", "r.Pool.Query", err)
 	}
@@ -128,7 +166,7 @@ func (r *IEEE8021xRepo) Get(_ context.Context, top, skip int, tenantID string) (
 	for rows.Next() {
 		p := entity.IEEE8021xConfig{}
 
-		err = rows.Scan(&p.ProfileName, &p.AuthenticationProtocol, &p.PXETimeout, &p.WiredInterface, &p.TenantID)
+		err = rows.Scan(&p.ProfileName, &p.AuthenticationProtocol, &p.PXETimeout, &p.WiredInterface, &p.TenantID, &p.Version)
 		if err != nil {
 			return nil, ErrIEEE8021xDatabase.Wrap("Get", "rows.Scan: ", err)
 		}
@@ -140,22 +178,27 @@ func (r *IEEE8021xRepo) Get(_ context.Context, top, skip int, tenantID string) (
 }
 
 // GetByName -.
-func (r *IEEE8021xRepo) GetByName(_ context.Context, profileName, tenantID string) (*entity.IEEE8021xConfig, error) {
+func (r *IEEE8021xRepo) GetByName(ctx context.Context, profileName, tenantID string) (*entity.IEEE8021xConfig, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, _, err := r.Builder.
 		Select("profile_name",
 			"auth_Protocol",
 			"pxe_timeout",
 			"wired_interface",
 			"tenant_id",
+			"version",
 		).
 		From("ieee8021xconfigs").
 		Where("profile_name = ? and tenant_id = ?", profileName, tenantID).
+		Where(byKey(ctx, "")).
 		ToSql()
 	if err != nil {
 		return nil, ErrIEEE8021xDatabase.Wrap("Get", "r.Builder: ", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, profileName, tenantID)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, profileName, tenantID)
 	if err != nil {
 		return nil, ErrIEEE8021xDatabase.Wrap("Get", "r.Pool.Query", err)
 	}
@@ -171,7 +214,7 @@ func (r *IEEE8021xRepo) GetByName(_ context.Context, profileName, tenantID strin
 	for rows.Next() {
 		p := &entity.IEEE8021xConfig{}
 
-		err = rows.Scan(&p.ProfileName, &p.AuthenticationProtocol, &p.PXETimeout, &p.WiredInterface, &p.TenantID)
+		err = rows.Scan(&p.ProfileName, &p.AuthenticationProtocol, &p.PXETimeout, &p.WiredInterface, &p.TenantID, &p.Version)
 		if err != nil {
 			return p, ErrIEEE8021xDatabase.Wrap("Get", "rows.Scan: ", err)
 		}
@@ -186,43 +229,59 @@ func (r *IEEE8021xRepo) GetByName(_ context.Context, profileName, tenantID strin
 	return ieee8021xConfigs[0], nil
 }
 
-// Delete -.
-func (r *IEEE8021xRepo) Delete(_ context.Context, profileName, tenantID string) (bool, error) {
-	sqlQuery, args, err := r.Builder.
-		Delete("ieee8021xconfigs").
-		Where("profile_name = ? AND tenant_id = ?", profileName, tenantID).
-		ToSql()
-	if err != nil {
-		return false, ErrIEEE8021xDatabase.Wrap("Delete", "r.Builder: ", err)
-	}
+// Delete moves the config to the trash, unless a live wireless config or
+// profile uses it.
+func (r *IEEE8021xRepo) Delete(ctx context.Context, profileName, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
 
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, args...)
-	if err != nil {
-		return false, ErrIEEE8021xDatabase.Wrap("Delete", "r.Pool.Exec", err)
-	}
+	var deleted bool
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return false, ErrIEEE8021xDatabase.Wrap("Delete", "res.RowsAffected", err)
-	}
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		for _, from := range []string{"wirelessconfigs", "profiles"} {
+			inUse, err := referenced(ctx, r.SQL, from, "ieee8021x_profile_name = ? AND tenant_id = ?", profileName, tenantID)
+			if err != nil {
+				return ErrIEEE8021xDatabase.Wrap("Delete", "referenced", err)
+			}
 
-	return rowsAffected > 0, nil
+			if inUse {
+				return ErrIEEE8021xForeignKey.Wrap("IEEE 802.1x config " + profileName + " is used by " + from)
+			}
+		}
+
+		var err error
+
+		deleted, err = softDelete(ctx, r.SQL, "ieee8021xconfigs", "IEEE 802.1x config "+profileName, "profile_name = ? AND tenant_id = ?", profileName, tenantID)
+		if err != nil {
+			return ErrIEEE8021xDatabase.Wrap("Delete", "softDelete", err)
+		}
+
+		return nil
+	})
+
+	return deleted, err
 }
 
 // Update -.
-func (r *IEEE8021xRepo) Update(_ context.Context, p *entity.IEEE8021xConfig) (bool, error) {
+func (r *IEEE8021xRepo) Update(ctx context.Context, p *entity.IEEE8021xConfig) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, args, err := r.Builder.
 		Update("ieee8021xconfigs").
 		Set("auth_protocol", p.AuthenticationProtocol).
 		Set("pxe_timeout", p.PXETimeout).
 		Set("wired_interface", p.WiredInterface).
+		Set("version", versions.New()).
 		Where("profile_name = ? AND tenant_id = ?", p.ProfileName, p.TenantID).
+		Where(notDeleted).
+		Where(ifMatch(ctx)).
 		ToSql()
 	if err != nil {
 		return false, ErrIEEE8021xDatabase.Wrap("Update", "r.Builder: ", err)
 	}
 
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, args...)
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return false, ErrIEEE8021xDatabase.Wrap("Update", "r.Pool.Exec", err)
 	}
@@ -232,39 +291,47 @@ func (r *IEEE8021xRepo) Update(_ context.Context, p *entity.IEEE8021xConfig) (bo
 		return false, ErrIEEE8021xDatabase.Wrap("Update", "res.RowsAffected", err)
 	}
 
+	if rowsAffected == 0 {
+		if err := checkVersion(ctx, r.SQL, "ieee8021xconfigs", "IEEE 802.1x config "+p.ProfileName, "profile_name = ? AND tenant_id = ?", p.ProfileName, p.TenantID); err != nil {
+			return false, err
+		}
+	}
+
 	return rowsAffected > 0, nil
 }
 
 // Insert -.
-func (r *IEEE8021xRepo) Insert(_ context.Context, p *entity.IEEE8021xConfig) (string, error) {
+func (r *IEEE8021xRepo) Insert(ctx context.Context, p *entity.IEEE8021xConfig) (string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	version := versions.New()
+
 	insertBuilder := r.Builder.
 		Insert("ieee8021xconfigs").
-		Columns("profile_name", "auth_protocol", "pxe_timeout", "wired_interface", "tenant_id").
-		Values(p.ProfileName, p.AuthenticationProtocol, p.PXETimeout, p.WiredInterface, p.TenantID)
-
-	if !r.IsEmbedded {
-		insertBuilder = insertBuilder.Suffix("RETURNING xmin::text")
-	}
+		Columns("profile_name", "auth_protocol", "pxe_timeout", "wired_interface", "tenant_id", "version").
+		Values(p.ProfileName, p.AuthenticationProtocol, p.PXETimeout, p.WiredInterface, p.TenantID, version)
 
 	sqlQuery, args, err := insertBuilder.ToSql()
 	if err != nil {
 		return "", ErrIEEE8021xDatabase.Wrap("Insert", "r.Builder: ", err)
 	}
 
-	version := ""
+	err = r.InTx(ctx, func(ctx context.Context) error {
+		if err := purgeTrashed(ctx, r.SQL, entity.TrashIEEE8021xConfigs, "profile_name = ? AND tenant_id = ?", p.ProfileName, p.TenantID); err != nil {
+			return err
+		}
 
-	if r.IsEmbedded {
-		_, err = r.Pool.ExecContext(context.Background(), sqlQuery, args...)
-	} else {
-		err = r.Pool.QueryRowContext(context.Background(), sqlQuery, args...).Scan(&version)
-	}
+		_, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 
+		return err
+	})
 	if err != nil {
-		if db.CheckNotUnique(err) {
+		if db.CheckNotUnique(err) || errors.Is(err, errTrashedInUse) {
 			return "", ErrIEEE8021xNotUnique.Wrap(err.Error())
 		}
 
-		return "", ErrIEEE8021xDatabase.Wrap("Insert", "r.Pool.QueryRow", err)
+		return "", ErrIEEE8021xDatabase.Wrap("Insert", "r.InTx", err)
 	}
 
 	return version, nil
@@ -619,17 +619,16 @@ func InsertIEEEHelper(t *testing.T, tc InsertIEEETestCase, version string, err e
 		}
 	}
 
-	if version != tc.expected {
-		t.Errorf("Expected version %v, got %v", tc.expected, version)
+	if (err == nil) != (version != "") {
+		t.Errorf("Expected a version only on success, got %q", version)
 	}
 }
 
 type InsertIEEETestCase struct {
-	name     string
-	setup    func(dbConn *sql.DB)
-	config   *entity.IEEE8021xConfig
-	expected string
-	err      error
+	name   string
+	setup  func(dbConn *sql.DB)
+	config *entity.IEEE8021xConfig
+	err    error
 }
 
 func TestIEEE8021xRepo_Insert(t *testing.T) {
@@ -645,8 +644,7 @@ func TestIEEE8021xRepo_Insert(t *testing.T) {
 				WiredInterface:         true,
 				TenantID:               "tenant1",
 			},
-			expected: "",
-			err:      nil,
+			err: nil,
 		},
 		{
 			name: "Insert with not unique error",
@@ -661,8 +659,7 @@ func TestIEEE8021xRepo_Insert(t *testing.T) {
 				WiredInterface:         true,
 				TenantID:               "tenant1",
 			},
-			expected: "",
-			err:      repoerrors.NotUniqueError{},
+			err: repoerrors.NotUniqueError{},
 		},
 		{
 			name:  "Query execution error",
@@ -673,8 +670,7 @@ func TestIEEE8021xRepo_Insert(t *testing.T) {
 				WiredInterface:         true,
 				TenantID:               "tenant1",
 			},
-			expected: "",
-			err:      &repoerrors.DatabaseError{},
+			err: &repoerrors.DatabaseError{},
 		},
 	}
 
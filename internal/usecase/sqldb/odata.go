@@ -0,0 +1,103 @@
+package sqldb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/device-management-toolkit/console/internal/odata"
+)
+
+// odataColumns maps the OData field names of an entity to its columns.
+type odataColumns map[string]string
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// odataWhere narrows b by the $filter of the query in ctx, if any.
+func odataWhere(ctx context.Context, b squirrel.SelectBuilder, columns odataColumns) (squirrel.SelectBuilder, error) {
+	q := odata.FromContext(ctx)
+	if q == nil || q.Filter == nil {
+		return b, nil
+	}
+
+	cond, err := odataCondition(q.Filter, columns)
+	if err != nil {
+		return b, err
+	}
+
+	return b.Where(cond), nil
+}
+
+// odataOrderBy orders b by the $orderby of the query in ctx, then by
+// defaults, which keep pages stable.
+func odataOrderBy(ctx context.Context, b squirrel.SelectBuilder, columns odataColumns, defaults ...string) (squirrel.SelectBuilder, error) {
+	var orderBy []string
+
+	if q := odata.FromContext(ctx); q != nil {
+		for _, o := range q.OrderBy {
+			column, ok := columns[o.Field]
+			if !ok {
+				return b, fmt.Errorf("%w: cannot order by %q", odata.ErrInvalidQuery, o.Field)
+			}
+
+			if o.Desc {
+				column += " DESC"
+			}
+
+			orderBy = append(orderBy, column)
+		}
+	}
+
+	return b.OrderBy(append(orderBy, defaults...)...), nil
+}
+
+func odataCondition(e *odata.Expr, columns odataColumns) (squirrel.Sqlizer, error) {
+	if e.Op == odata.And || e.Op == odata.Or {
+		left, err := odataCondition(e.Left, columns)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := odataCondition(e.Right, columns)
+		if err != nil {
+			return nil, err
+		}
+
+		if e.Op == odata.And {
+			return squirrel.And{left, right}, nil
+		}
+
+		return squirrel.Or{left, right}, nil
+	}
+
+	column, ok := columns[e.Field]
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot filter on %q", odata.ErrInvalidQuery, e.Field)
+	}
+
+	switch e.Op {
+	case odata.Eq:
+		return squirrel.Eq{column: e.Value}, nil
+	case odata.Ne:
+		// As in the other stores, a missing value differs from any value.
+		return squirrel.Or{squirrel.NotEq{column: e.Value}, squirrel.Eq{column: nil}}, nil
+	case odata.Gt:
+		return squirrel.Gt{column: e.Value}, nil
+	case odata.Lt:
+		return squirrel.Lt{column: e.Value}, nil
+	case odata.Contains, odata.StartsWith:
+		value, _ := e.Value.(string)
+
+		pattern := likeEscaper.Replace(strings.ToLower(value)) + "%"
+		if e.Op == odata.Contains {
+			pattern = "%" + pattern
+		}
+
+		return squirrel.Expr("LOWER("+column+`) LIKE ? ESCAPE '\'`, pattern), nil
+	case odata.And, odata.Or:
+	}
+
+	return nil, fmt.Errorf("%w: unsupported operator %q", odata.ErrInvalidQuery, e.Op)
+}
@@ -0,0 +1,137 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/odata"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/sqldb"
+)
+
+var deviceODataFields = odata.Fields{
+	"guid":             odata.String,
+	"hostname":         odata.String,
+	"connectionStatus": odata.Bool,
+	"tags":             odata.Opaque,
+}
+
+func odataContext(t *testing.T, filter, orderBy string, fields odata.Fields) context.Context {
+	t.Helper()
+
+	q, err := odata.Parse(filter, orderBy, "", fields)
+	require.NoError(t, err)
+
+	return odata.WithQuery(context.Background(), q)
+}
+
+func TestDeviceRepo_OData(t *testing.T) {
+	t.Parallel()
+
+	// The subtests share one connection, and so one in-memory database.
+	dbConn := setupDeviceTable(t)
+	dbConn.SetMaxOpenConns(1)
+	t.Cleanup(func() { dbConn.Close() })
+
+	for _, d := range []struct {
+		guid, hostname string
+		connected      bool
+		tenantID       string
+	}{
+		{"guid1", "lab-100", true, "tenant1"},
+		{"guid2", "LAB-200", false, "tenant1"},
+		{"guid3", "office_1", false, "tenant1"},
+		{"guid4", "lab-300", false, "tenant2"},
+	} {
+		_, err := dbConn.ExecContext(context.Background(), `INSERT INTO devices (guid, hostname, connectionstatus, tenantid) VALUES (?, ?, ?, ?)`,
+			d.guid, d.hostname, d.connected, d.tenantID)
+		require.NoError(t, err)
+	}
+
+	repo := sqldb.NewDeviceRepo(CreateSQLConfig(dbConn, false), mocks.NewMockLogger(nil))
+
+	tests := []struct {
+		name, filter, orderBy string
+		expected              []string
+	}{
+		{name: "no query", expected: []string{"guid1", "guid2", "guid3"}},
+		{name: "startswith ignores case", filter: "startswith(hostname,'lab')", expected: []string{"guid1", "guid2"}},
+		{name: "bool and contains", filter: "connectionStatus eq false and contains(hostname,'-2')", expected: []string{"guid2"}},
+		{name: "like wildcards are literal", filter: "contains(hostname,'_')", expected: []string{"guid3"}},
+		{name: "or with order", filter: "guid eq 'guid1' or guid eq 'guid3'", orderBy: "hostname desc", expected: []string{"guid3", "guid1"}},
+		{name: "ne", filter: "hostname ne 'lab-100'", expected: []string{"guid2", "guid3"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := odataContext(t, tc.filter, tc.orderBy, deviceODataFields)
+
+			devices, err := repo.Get(ctx, 10, 0, "tenant1")
+			require.NoError(t, err)
+
+			guids := make([]string, 0, len(devices))
+			for i := range devices {
+				guids = append(guids, devices[i].GUID)
+			}
+
+			require.Equal(t, tc.expected, guids)
+
+			count, err := repo.GetCount(ctx, "tenant1")
+			require.NoError(t, err)
+
+			require.Equal(t, len(tc.expected), count)
+		})
+	}
+}
+
+func TestProfileRepo_OData(t *testing.T) {
+	t.Parallel()
+
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	defer dbConn.Close()
+
+	_, err = dbConn.ExecContext(context.Background(), schema)
+	require.NoError(t, err)
+
+	for _, name := range []string{"acm-1", "ccm-1", "acm-2"} {
+		_, err = dbConn.ExecContext(context.Background(), `INSERT INTO profiles (
+			profile_name, activation, generate_random_password, generate_random_mebx_password, tags,
+			dhcp_enabled, ip_sync_enabled, local_wifi_sync_enabled, tenant_id, tls_mode,
+			tls_signing_authority, user_consent, ider_enabled, kvm_enabled, sol_enabled,
+			uefi_wifi_sync_enabled
+		) VALUES (?, ?, false, false, '', true, false, false, ?, 1, '', 'All', true, true, true, false)`,
+			name, name[:3]+"activate", "tenant1")
+		require.NoError(t, err)
+	}
+
+	repo := sqldb.NewProfileRepo(CreateSQLConfig(dbConn, false), mocks.NewMockLogger(nil))
+
+	ctx := odataContext(t, "activation eq 'acmactivate'", "profileName desc", odata.Fields{
+		"profileName": odata.String,
+		"activation":  odata.String,
+	})
+
+	profiles, err := repo.Get(ctx, 10, 0, "tenant1")
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	require.Equal(t, "acm-2", profiles[0].ProfileName)
+	require.Equal(t, "acm-1", profiles[1].ProfileName)
+
+	count, err := repo.GetCount(ctx, "tenant1")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	// A field the repository has no column for is refused, not ignored.
+	ctx = odataContext(t, "hostname eq 'x'", "", odata.Fields{"hostname": odata.String})
+
+	_, err = repo.Get(ctx, 10, 0, "tenant1")
+	require.ErrorAs(t, err, &repoerrors.DatabaseError{})
+}
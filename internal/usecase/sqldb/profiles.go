@@ -7,6 +7,7 @@ import (
 
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/versions"
 	"github.com/device-management-toolkit/console/pkg/consoleerrors"
 	"github.com/device-management-toolkit/console/pkg/db"
 	"github.com/device-management-toolkit/console/pkg/logger"
@@ -28,20 +29,43 @@ func NewProfileRepo(database *db.SQL, log logger.Interface) *ProfileRepo {
 	return &ProfileRepo{database, log}
 }
 
+// profileColumns are the columns of the profiles' OData fields.
+var profileColumns = odataColumns{
+	"profileName":            "p.profile_name",
+	"activation":             "p.activation",
+	"ciraConfigName":         "p.cira_config_name",
+	"ieee8021xProfileName":   "p.ieee8021x_profile_name",
+	"generateRandomPassword": "p.generate_random_password",
+	"dhcpEnabled":            "p.dhcp_enabled",
+	"tlsMode":                "p.tls_mode",
+	"tlsSigningAuthority":    "p.tls_signing_authority",
+	"userConsent":            "p.user_consent",
+	"iderEnabled":            "p.ider_enabled",
+	"kvmEnabled":             "p.kvm_enabled",
+	"solEnabled":             "p.sol_enabled",
+}
+
 // GetCount -.
-func (r *ProfileRepo) GetCount(_ context.Context, tenantID string) (int, error) {
-	sqlQuery, _, err := r.Builder.
+func (r *ProfileRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	builder, err := odataWhere(ctx, r.Builder.
 		Select("COUNT(*) OVER() AS total_count").
-		From("profiles").
-		Where("tenant_id = ?", tenantID).
-		ToSql()
+		From("profiles p").
+		Where("p.tenant_id = ? AND p.deleted_at IS NULL", tenantID), profileColumns)
+	if err != nil {
+		return 0, ErrProfileDatabase.Wrap("GetCount", "odataWhere", err)
+	}
+
+	sqlQuery, args, err := builder.ToSql()
 	if err != nil {
 		return 0, ErrProfileDatabase.Wrap("GetCount", "r.Builder", err)
 	}
 
 	var count int
 
-	err = r.Pool.QueryRowContext(context.Background(), sqlQuery, tenantID).Scan(&count)
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return 0, nil
@@ -56,7 +80,10 @@ func (r *ProfileRepo) GetCount(_ context.Context, tenantID string) (int, error)
 // Get -.
 //
 //nolint:funlen // 2 lines ain't enough
-func (r *ProfileRepo) Get(_ context.Context, top, skip int, tenantID string) ([]entity.Profile, error) {
+func (r *ProfileRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.Profile, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	const defaultTop = 100
 
 	if top == 0 {
@@ -73,7 +100,7 @@ func (r *ProfileRepo) Get(_ context.Context, top, skip int, tenantID string) ([]
 		limitedSkip = uint64(skip)
 	}
 
-	sqlQuery, _, err := r.Builder.
+	builder, err := odataWhere(ctx, r.Builder.
 		Select(
 			"p.profile_name",
 			"p.activation",
@@ -93,6 +120,7 @@ func (r *ProfileRepo) Get(_ context.Context, top, skip int, tenantID string) ([]
 			"p.local_wifi_sync_enabled",
 			"p.ieee8021x_profile_name",
 			"p.uefi_wifi_sync_enabled",
+			"p.version",
 			"e.auth_protocol",
 			"e.pxe_timeout",
 			"e.wired_interface",
@@ -100,7 +128,12 @@ func (r *ProfileRepo) Get(_ context.Context, top, skip int, tenantID string) ([]
 		From("profiles p").
 		LeftJoin("profiles_wirelessconfigs pw ON pw.profile_name = p.profile_name AND pw.tenant_id = p.tenant_id").
 		LeftJoin("ieee8021xconfigs e ON p.ieee8021x_profile_name = e.profile_name AND p.tenant_id = e.tenant_id").
-		Where("p.tenant_id = ?", tenantID).
+		Where("p.tenant_id = ? AND p.deleted_at IS NULL", tenantID), profileColumns)
+	if err != nil {
+		return nil, ErrProfileDatabase.Wrap("Get", "odataWhere", err)
+	}
+
+	builder, err = odataOrderBy(ctx, builder.
 		GroupBy(
 			"p.profile_name",
 			"p.activation",
@@ -120,18 +153,23 @@ func (r *ProfileRepo) Get(_ context.Context, top, skip int, tenantID string) ([]
 			"p.local_wifi_sync_enabled",
 			"p.ieee8021x_profile_name",
 			"p.uefi_wifi_sync_enabled",
+			"p.version",
 			"e.auth_protocol",
 			"e.pxe_timeout",
 			"e.wired_interface",
-		).
-		OrderBy("p.profile_name").
+		), profileColumns, "p.profile_name")
+	if err != nil {
+		return nil, ErrProfileDatabase.Wrap("Get", "odataOrderBy", err)
+	}
+
+	sqlQuery, args, err := builder.
 		Limit(limitedTop).Offset(limitedSkip).
 		ToSql()
 	if err != nil {
 		return nil, ErrProfileDatabase.Wrap("Get", "r.Builder", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, tenantID)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, ErrProfileDatabase.Wrap("Get", "r.Pool.Query", err)
 	}
@@ -150,7 +188,7 @@ func (r *ProfileRepo) Get(_ context.Context, top, skip int, tenantID string) ([]
 		err = rows.Scan(&p.ProfileName, &p.Activation, &p.GenerateRandomPassword, &p.CIRAConfigName,
 			&p.GenerateRandomMEBxPassword, &p.Tags, &p.DHCPEnabled, &p.TenantID, &p.TLSMode,
 			&p.UserConsent, &p.IDEREnabled, &p.KVMEnabled, &p.SOLEnabled, &p.TLSSigningAuthority,
-			&p.IPSyncEnabled, &p.LocalWiFiSyncEnabled, &p.IEEE8021xProfileName, &p.UEFIWiFiSyncEnabled, &p.AuthenticationProtocol, &p.PXETimeout, &p.WiredInterface)
+			&p.IPSyncEnabled, &p.LocalWiFiSyncEnabled, &p.IEEE8021xProfileName, &p.UEFIWiFiSyncEnabled, &p.Version, &p.AuthenticationProtocol, &p.PXETimeout, &p.WiredInterface)
 		if err != nil {
 			return nil, ErrProfileDatabase.Wrap("Get", "rows.Scan", err)
 		}
@@ -163,7 +201,10 @@ func (r *ProfileRepo) Get(_ context.Context, top, skip int, tenantID string) ([]
 
 // GetByName -.
 
-func (r *ProfileRepo) GetByName(_ context.Context, profileName, tenantID string) (*entity.Profile, error) {
+func (r *ProfileRepo) GetByName(ctx context.Context, profileName, tenantID string) (*entity.Profile, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, _, err := r.Builder.
 		Select(
 			"p.profile_name",
@@ -186,6 +227,7 @@ func (r *ProfileRepo) GetByName(_ context.Context, profileName, tenantID string)
 			"p.local_wifi_sync_enabled",
 			"p.ieee8021x_profile_name",
 			"p.uefi_wifi_sync_enabled",
+			"p.version",
 			"e.auth_protocol",
 			"e.pxe_timeout",
 			"e.wired_interface",
@@ -193,12 +235,13 @@ func (r *ProfileRepo) GetByName(_ context.Context, profileName, tenantID string)
 		From("profiles p").
 		LeftJoin("ieee8021xconfigs e ON p.ieee8021x_profile_name = e.profile_name AND p.tenant_id = e.tenant_id").
 		Where("p.profile_name = ? and p.tenant_id = ?", profileName, tenantID).
+		Where(byKey(ctx, "p.")).
 		ToSql()
 	if err != nil {
 		return nil, ErrProfileDatabase.Wrap("GetByName", "r.Builder", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, profileName, tenantID)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, profileName, tenantID)
 	if err != nil {
 		return nil, ErrProfileDatabase.Wrap("GetByName", "r.Pool.Query", err)
 	}
@@ -218,7 +261,7 @@ func (r *ProfileRepo) GetByName(_ context.Context, profileName, tenantID string)
 			&p.CIRAConfigName,
 			&p.GenerateRandomMEBxPassword, &p.Tags, &p.DHCPEnabled, &p.TenantID, &p.TLSMode,
 			&p.UserConsent, &p.IDEREnabled, &p.KVMEnabled, &p.SOLEnabled, &p.TLSSigningAuthority,
-			&p.IPSyncEnabled, &p.LocalWiFiSyncEnabled, &p.IEEE8021xProfileName, &p.UEFIWiFiSyncEnabled, &p.AuthenticationProtocol, &p.PXETimeout, &p.WiredInterface)
+			&p.IPSyncEnabled, &p.LocalWiFiSyncEnabled, &p.IEEE8021xProfileName, &p.UEFIWiFiSyncEnabled, &p.Version, &p.AuthenticationProtocol, &p.PXETimeout, &p.WiredInterface)
 		if err != nil {
 			return p, ErrProfileDatabase.Wrap("GetByName", "rows.Scan", err)
 		}
@@ -233,33 +276,27 @@ func (r *ProfileRepo) GetByName(_ context.Context, profileName, tenantID string)
 	return profiles[0], nil
 }
 
-// Delete -.
+// Delete moves the profile to the trash. Its links to wireless configs are
+// kept, to be restored with it.
 
-func (r *ProfileRepo) Delete(_ context.Context, profileName, tenantID string) (bool, error) {
-	sqlQuery, args, err := r.Builder.
-		Delete("profiles").
-		Where("profile_name = ? AND tenant_id = ?", profileName, tenantID).
-		ToSql()
-	if err != nil {
-		return false, ErrProfileDatabase.Wrap("Delete", "r.Builder", err)
-	}
-
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, args...)
-	if err != nil {
-		return false, ErrProfileDatabase.Wrap("Delete", "r.Pool.Exec", err)
-	}
+func (r *ProfileRepo) Delete(ctx context.Context, profileName, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
 
-	rowsAffected, err := res.RowsAffected()
+	deleted, err := softDelete(ctx, r.SQL, "profiles", "profile "+profileName, "profile_name = ? AND tenant_id = ?", profileName, tenantID)
 	if err != nil {
-		return false, ErrProfileDatabase.Wrap("Delete", "res.RowsAffected", err)
+		return false, ErrProfileDatabase.Wrap("Delete", "softDelete", err)
 	}
 
-	return rowsAffected > 0, nil
+	return deleted, nil
 }
 
 // Update -.
 
-func (r *ProfileRepo) Update(_ context.Context, p *entity.Profile) (bool, error) {
+func (r *ProfileRepo) Update(ctx context.Context, p *entity.Profile) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, args, err := r.Builder.
 		Update("profiles").
 		Set("activation", p.Activation).
@@ -280,13 +317,16 @@ func (r *ProfileRepo) Update(_ context.Context, p *entity.Profile) (bool, error)
 		Set("ip_sync_enabled", p.IPSyncEnabled).
 		Set("local_wifi_sync_enabled", p.LocalWiFiSyncEnabled).
 		Set("uefi_wifi_sync_enabled", p.UEFIWiFiSyncEnabled).
+		Set("version", versions.New()).
 		Where("profile_name = ? AND tenant_id = ?", p.ProfileName, p.TenantID).
+		Where(notDeleted).
+		Where(ifMatch(ctx)).
 		ToSql()
 	if err != nil {
 		return false, ErrProfileDatabase.Wrap("Update", "r.Builder", err)
 	}
 
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, args...)
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return false, ErrProfileDatabase.Wrap("Update", "r.Pool.Exec", err)
 	}
@@ -296,11 +336,20 @@ func (r *ProfileRepo) Update(_ context.Context, p *entity.Profile) (bool, error)
 		return false, ErrProfileDatabase.Wrap("Update", "res.RowsAffected", err)
 	}
 
+	if rowsAffected == 0 {
+		if err := checkVersion(ctx, r.SQL, "profiles", "profile "+p.ProfileName, "profile_name = ? AND tenant_id = ?", p.ProfileName, p.TenantID); err != nil {
+			return false, err
+		}
+	}
+
 	return rowsAffected > 0, nil
 }
 
 // Insert -.
-func (r *ProfileRepo) Insert(_ context.Context, p *entity.Profile) (string, error) {
+func (r *ProfileRepo) Insert(ctx context.Context, p *entity.Profile) (string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	ciraConfigName := p.CIRAConfigName
 
 	ieee8021xProfileName := p.IEEE8021xProfileName
@@ -317,34 +366,33 @@ func (r *ProfileRepo) Insert(_ context.Context, p *entity.Profile) (string, erro
 		}
 	}
 
+	version := versions.New()
+
 	insertBuilder := r.Builder.
 		Insert("profiles").
-		Columns("profile_name", "activation", "amt_password", "generate_random_password", "cira_config_name", "mebx_password", "generate_random_mebx_password", "tags", "dhcp_enabled", "tls_mode", "user_consent", "ider_enabled", "kvm_enabled", "sol_enabled", "tls_signing_authority", "ieee8021x_profile_name", "ip_sync_enabled", "local_wifi_sync_enabled", "tenant_id", "uefi_wifi_sync_enabled").
-		Values(p.ProfileName, p.Activation, p.AMTPassword, p.GenerateRandomPassword, ciraConfigName, p.MEBXPassword, p.GenerateRandomMEBxPassword, p.Tags, p.DHCPEnabled, p.TLSMode, p.UserConsent, p.IDEREnabled, p.KVMEnabled, p.SOLEnabled, p.TLSSigningAuthority, ieee8021xProfileName, p.IPSyncEnabled, p.LocalWiFiSyncEnabled, p.TenantID, p.UEFIWiFiSyncEnabled)
-
-	if !r.IsEmbedded {
-		insertBuilder = insertBuilder.Suffix("RETURNING xmin::text")
-	}
+		Columns("profile_name", "activation", "amt_password", "generate_random_password", "cira_config_name", "mebx_password", "generate_random_mebx_password", "tags", "dhcp_enabled", "tls_mode", "user_consent", "ider_enabled", "kvm_enabled", "sol_enabled", "tls_signing_authority", "ieee8021x_profile_name", "ip_sync_enabled", "local_wifi_sync_enabled", "tenant_id", "uefi_wifi_sync_enabled", "version").
+		Values(p.ProfileName, p.Activation, p.AMTPassword, p.GenerateRandomPassword, ciraConfigName, p.MEBXPassword, p.GenerateRandomMEBxPassword, p.Tags, p.DHCPEnabled, p.TLSMode, p.UserConsent, p.IDEREnabled, p.KVMEnabled, p.SOLEnabled, p.TLSSigningAuthority, ieee8021xProfileName, p.IPSyncEnabled, p.LocalWiFiSyncEnabled, p.TenantID, p.UEFIWiFiSyncEnabled, version)
 
 	sqlQuery, args, err := insertBuilder.ToSql()
 	if err != nil {
 		return "", ErrProfileDatabase.Wrap("Insert", "r.Builder", err)
 	}
 
-	version := ""
+	err = r.InTx(ctx, func(ctx context.Context) error {
+		if err := purgeTrashed(ctx, r.SQL, entity.TrashProfiles, "profile_name = ? AND tenant_id = ?", p.ProfileName, p.TenantID); err != nil {
+			return err
+		}
 
-	if r.IsEmbedded {
-		_, err = r.Pool.ExecContext(context.Background(), sqlQuery, args...)
-	} else {
-		err = r.Pool.QueryRowContext(context.Background(), sqlQuery, args...).Scan(&version)
-	}
+		_, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 
+		return err
+	})
 	if err != nil {
-		if db.CheckNotUnique(err) {
+		if db.CheckNotUnique(err) || errors.Is(err, errTrashedInUse) {
 			return "", ErrProfileNotUnique.Wrap(err.Error())
 		}
 
-		return "", ErrProfileDatabase.Wrap("Insert", "r.Pool.QueryRow", err)
+		return "", ErrProfileDatabase.Wrap("Insert", "r.InTx", err)
 	}
 
 	return version, nil
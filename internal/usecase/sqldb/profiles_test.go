@@ -35,9 +35,13 @@ CREATE TABLE IF NOT EXISTS devices
     deviceinfo TEXT,
     username TEXT,
     password TEXT,
+    mpspassword TEXT,
+    mebxpassword TEXT,
     usetls BOOLEAN NOT NULL,
     allowselfsigned BOOLEAN NOT NULL,
     certhash TEXT,
+    deleted_at TEXT,
+    version TEXT NOT NULL DEFAULT '1',
     PRIMARY KEY (guid, tenantid),
     UNIQUE (guid)
 );
@@ -54,6 +58,8 @@ CREATE TABLE IF NOT EXISTS ciraconfigs(
   proxydetails TEXT,
   tenant_id TEXT NOT NULL,
   generate_random_password BOOLEAN,
+  deleted_at TEXT,
+  version TEXT NOT NULL DEFAULT '1',
   PRIMARY KEY (cira_config_name, tenant_id)
 );
 
@@ -63,6 +69,8 @@ CREATE TABLE IF NOT EXISTS ieee8021xconfigs(
   pxe_timeout INTEGER,
   wired_interface BOOLEAN NOT NULL,
   tenant_id TEXT NOT NULL,
+  deleted_at TEXT,
+  version TEXT NOT NULL DEFAULT '1',
   PRIMARY KEY (profile_name, tenant_id)
 );
 
@@ -78,6 +86,8 @@ CREATE TABLE IF NOT EXISTS wirelessconfigs(
   created_by TEXT,
   tenant_id TEXT NOT NULL,
   ieee8021x_profile_name TEXT,
+  deleted_at TEXT,
+  version TEXT NOT NULL DEFAULT '1',
   FOREIGN KEY (ieee8021x_profile_name, tenant_id) REFERENCES ieee8021xconfigs(profile_name, tenant_id),
   PRIMARY KEY (wireless_profile_name, tenant_id)
 );
@@ -105,6 +115,8 @@ CREATE TABLE IF NOT EXISTS profiles(
   local_wifi_sync_enabled BOOLEAN NOT NULL, 
   ieee8021x_profile_name TEXT,
   uefi_wifi_sync_enabled BOOLEAN NOT NULL,
+  deleted_at TEXT,
+  version TEXT NOT NULL DEFAULT '1',
   FOREIGN KEY (ieee8021x_profile_name, tenant_id) REFERENCES ieee8021xconfigs(profile_name, tenant_id),
   FOREIGN KEY (cira_config_name, tenant_id) REFERENCES ciraconfigs(cira_config_name, tenant_id),
   PRIMARY KEY (profile_name, tenant_id)
@@ -132,12 +144,132 @@ CREATE TABLE IF NOT EXISTS domains(
   creation_date TEXT, -- TIMESTAMP as TEXT
   created_by TEXT,
   tenant_id TEXT NOT NULL,
+  deleted_at TEXT,
+  version TEXT NOT NULL DEFAULT '1',
   CONSTRAINT domainsuffix UNIQUE (domain_suffix, tenant_id),
   PRIMARY KEY (name, tenant_id)
 );
 
 CREATE UNIQUE INDEX lower_name_suffix_idx ON domains (LOWER(name), LOWER(domain_suffix));
 
+CREATE TABLE IF NOT EXISTS users(
+  username TEXT NOT NULL,
+  password_hash TEXT NOT NULL,
+  role TEXT NOT NULL,
+  must_change_password BOOLEAN NOT NULL,
+  password_changed_at TEXT,
+  creation_date TEXT,
+  created_by TEXT,
+  tenant_id TEXT NOT NULL,
+  PRIMARY KEY (username)
+);
+
+CREATE UNIQUE INDEX lower_username_idx ON users (LOWER(username));
+
+CREATE TABLE IF NOT EXISTS api_keys(
+  id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  key_hash TEXT NOT NULL,
+  role TEXT NOT NULL,
+  routes TEXT,
+  tags TEXT,
+  expires_at TEXT,
+  last_used_at TEXT,
+  creation_date TEXT,
+  created_by TEXT,
+  tenant_id TEXT NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens(
+  id TEXT NOT NULL,
+  subject TEXT,
+  revoked_at TEXT NOT NULL,
+  expires_at TEXT NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens(
+  token_hash TEXT NOT NULL,
+  session_id TEXT NOT NULL,
+  username TEXT NOT NULL,
+  used BOOLEAN NOT NULL,
+  expires_at TEXT NOT NULL,
+  creation_date TEXT,
+  tenant_id TEXT NOT NULL,
+  PRIMARY KEY (token_hash)
+);
+
+CREATE TABLE IF NOT EXISTS audit_events(
+  seq BIGINT NOT NULL,
+  occurred_at TEXT NOT NULL,
+  actor TEXT,
+  tenant_id TEXT NOT NULL,
+  action TEXT NOT NULL,
+  target TEXT,
+  parameters TEXT,
+  result TEXT NOT NULL,
+  status INTEGER,
+  source_ip TEXT,
+  prev_hash TEXT NOT NULL,
+  hash TEXT NOT NULL,
+  PRIMARY KEY (seq)
+);
+CREATE TABLE IF NOT EXISTS access_policies(
+  id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  subject TEXT NOT NULL,
+  tags TEXT NOT NULL,
+  method TEXT NOT NULL,
+  permissions TEXT,
+  creation_date TEXT,
+  created_by TEXT,
+  tenant_id TEXT NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE (name, tenant_id)
+);
+
+CREATE TABLE IF NOT EXISTS device_groups(
+  id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  description TEXT,
+  parent_id TEXT,
+  creation_date TEXT,
+  created_by TEXT,
+  tenant_id TEXT NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE (name, tenant_id),
+  FOREIGN KEY (parent_id) REFERENCES device_groups(id)
+);
+
+CREATE TABLE IF NOT EXISTS device_group_rules(
+  group_id TEXT NOT NULL,
+  position INTEGER NOT NULL,
+  field TEXT NOT NULL,
+  operator TEXT NOT NULL,
+  value TEXT NOT NULL,
+  PRIMARY KEY (group_id, position),
+  FOREIGN KEY (group_id) REFERENCES device_groups(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS device_group_members(
+  group_id TEXT NOT NULL,
+  guid TEXT NOT NULL,
+  dynamic BOOLEAN NOT NULL,
+  tenant_id TEXT NOT NULL,
+  PRIMARY KEY (group_id, guid, dynamic),
+  FOREIGN KEY (group_id) REFERENCES device_groups(id) ON DELETE CASCADE,
+  FOREIGN KEY (guid, tenant_id) REFERENCES devices(guid, tenantid) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS device_tags(
+  guid TEXT NOT NULL,
+  tenant_id TEXT NOT NULL,
+  tag TEXT NOT NULL,
+  PRIMARY KEY (guid, tenant_id, tag),
+  FOREIGN KEY (guid, tenant_id) REFERENCES devices(guid, tenantid) ON DELETE CASCADE
+);
+
 PRAGMA foreign_keys = ON;
 `
 
@@ -30,7 +30,10 @@ func NewProfileWiFiConfigsRepo(database *db.SQL, log logger.Interface) *ProfileW
 }
 
 // Get by profile name -.
-func (r *ProfileWiFiConfigsRepo) GetByProfileName(_ context.Context, profileName, tenantID string) ([]entity.ProfileWiFiConfigs, error) {
+func (r *ProfileWiFiConfigsRepo) GetByProfileName(ctx context.Context, profileName, tenantID string) ([]entity.ProfileWiFiConfigs, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, args, err := r.Builder.
 		Select("wireless_profile_name", "profile_name", "priority", "tenant_id").
 		From("profiles_wirelessconfigs").
@@ -41,7 +44,7 @@ func (r *ProfileWiFiConfigsRepo) GetByProfileName(_ context.Context, profileName
 		return nil, ErrProfileWiFiConfigsDatabase.Wrap("GetByProfileName", "r.Builder", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, args...)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, ErrProfileWiFiConfigsDatabase.Wrap("GetByProfileName", "r.Pool.Query", err)
 	}
@@ -68,7 +71,10 @@ func (r *ProfileWiFiConfigsRepo) GetByProfileName(_ context.Context, profileName
 }
 
 // Delete -.
-func (r *ProfileWiFiConfigsRepo) DeleteByProfileName(_ context.Context, profileName, tenantID string) (bool, error) {
+func (r *ProfileWiFiConfigsRepo) DeleteByProfileName(ctx context.Context, profileName, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, args, err := r.Builder.
 		Delete("profiles_wirelessconfigs").
 		Where("profile_name = ? AND tenant_id = ?", profileName, tenantID).
@@ -77,7 +83,7 @@ func (r *ProfileWiFiConfigsRepo) DeleteByProfileName(_ context.Context, profileN
 		return false, ErrProfileWiFiConfigsDatabase.Wrap("Delete", "r.Builder", err)
 	}
 
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, args...)
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return false, ErrProfileWiFiConfigsDatabase.Wrap("Delete", "r.Pool.Exec", err)
 	}
@@ -91,7 +97,10 @@ func (r *ProfileWiFiConfigsRepo) DeleteByProfileName(_ context.Context, profileN
 }
 
 // Insert -.
-func (r *ProfileWiFiConfigsRepo) Insert(_ context.Context, p *entity.ProfileWiFiConfigs) (string, error) {
+func (r *ProfileWiFiConfigsRepo) Insert(ctx context.Context, p *entity.ProfileWiFiConfigs) (string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	insertBuilder := r.Builder.
 		Insert("profiles_wirelessconfigs").
 		Columns("wireless_profile_name", "profile_name", "priority", "tenant_id").
@@ -109,9 +118,9 @@ func (r *ProfileWiFiConfigsRepo) Insert(_ context.Context, p *entity.ProfileWiFi
 	version := ""
 
 	if r.IsEmbedded {
-		_, err = r.Pool.ExecContext(context.Background(), sqlQuery, args...)
+		_, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 	} else {
-		err = r.Pool.QueryRowContext(context.Background(), sqlQuery, args...).Scan(&version)
+		err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&version)
 	}
 
 	if err != nil {
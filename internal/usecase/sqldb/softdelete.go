@@ -0,0 +1,145 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/pkg/db"
+)
+
+// Deleting an entity moves it to the trash by setting its deleted_at column,
+// which stays NULL for live rows; the trash repository restores and purges
+// them. Queries for live entities filter on notDeleted.
+const notDeleted = "deleted_at IS NULL"
+
+// byKey filters a lookup of one entity by its key: live rows only, unless ctx
+// looks in the trash too (see trash.WithTrashed). Prefix qualifies deleted_at
+// in a join.
+func byKey(ctx context.Context, prefix string) squirrel.Sqlizer {
+	if trash.Trashed(ctx) {
+		return squirrel.And{}
+	}
+
+	return squirrel.Expr(prefix + notDeleted)
+}
+
+// errTrashedInUse tells that a trashed row in the way of a new one is still
+// referred to by another row in the trash.
+var errTrashedInUse = errors.New("an entity of that name is in the trash and used by another entity there; purge or restore that first")
+
+// deletedNow is the deleted_at marker of a row deleted now, in RFC 3339 and
+// UTC so it orders as text.
+func deletedNow() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// referenced tells whether a live row of from matches where. Trashed rows
+// keep their foreign keys, so moving a row to the trash, or out of it, checks
+// its references here instead.
+func referenced(ctx context.Context, database *db.SQL, from, where string, args ...interface{}) (bool, error) {
+	sqlQuery, args, err := database.Builder.
+		Select("1").
+		From(from).
+		Where(where, args...).
+		Where(notDeleted).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	var one int
+
+	err = database.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// softDelete moves the live rows of table that match where, at a version
+// ctx expects, to the trash and tells whether there were any. Name is the
+// entity in ErrVersionMismatch.
+func softDelete(ctx context.Context, database *db.SQL, table, name, where string, args ...interface{}) (bool, error) {
+	sqlQuery, queryArgs, err := database.Builder.
+		Update(table).
+		Set("deleted_at", deletedNow()).
+		Where(where, args...).
+		Where(notDeleted).
+		Where(ifMatch(ctx)).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := database.Conn(ctx).ExecContext(ctx, sqlQuery, queryArgs...)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rowsAffected == 0 {
+		return false, checkVersion(ctx, database, table, name, where, args...)
+	}
+
+	return true, nil
+}
+
+// purgeTrashed purges the trashed entities of kind that match where, along
+// with their links, so a new entity can take their keys. Trashed rows keep
+// their unique keys, so an insert purges those in its way first, in the same
+// transaction.
+func purgeTrashed(ctx context.Context, database *db.SQL, kind, where string, args ...interface{}) error {
+	t, ok := trashTables[kind]
+	if !ok {
+		return errUnknownTrashKind
+	}
+
+	for _, link := range t.links {
+		sqlQuery, linkArgs, err := database.Builder.
+			Delete(link).
+			Where("EXISTS (SELECT 1 FROM "+t.table+" p WHERE p."+t.id+" = "+link+"."+t.id+" AND p."+t.tenant+" = "+link+"."+t.linkTenantColumn()+
+				" AND p.deleted_at IS NOT NULL AND ("+where+"))", args...).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := database.Conn(ctx).ExecContext(ctx, sqlQuery, linkArgs...); err != nil {
+			return err
+		}
+	}
+
+	sqlQuery, queryArgs, err := database.Builder.
+		Delete(t.table).
+		Where("("+where+")", args...).
+		Where("deleted_at IS NOT NULL").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := database.Conn(ctx).ExecContext(ctx, sqlQuery, queryArgs...); err != nil {
+		if db.CheckForeignKeyViolation(err) {
+			return errTrashedInUse
+		}
+
+		return err
+	}
+
+	return nil
+}
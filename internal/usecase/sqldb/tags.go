@@ -0,0 +1,280 @@
+package sqldb
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/taglist"
+	"github.com/device-management-toolkit/console/internal/versions"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/db"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// TagRepo manages the tags of devices fleet-wide. A device's tags are kept
+// twice: as the comma-joined devices.tags the API returns, and as rows of
+// device_tags, which tag queries use. Both are written together.
+type TagRepo struct {
+	*db.SQL
+	log logger.Interface
+}
+
+var ErrTagDatabase = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("TagRepo")}
+
+// NewTagRepo -.
+func NewTagRepo(database *db.SQL, log logger.Interface) *TagRepo {
+	return &TagRepo{database, log}
+}
+
+// GetCounts lists the tags of the live devices, with how many carry each.
+func (r *TagRepo) GetCounts(ctx context.Context, tenantID string) ([]entity.Tag, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select("tag", "COUNT(*)").
+		From("device_tags").
+		Where("tenant_id = ?", tenantID).
+		Where(squirrel.Expr("guid IN (?)", liveDevices(r.SQL, tenantID))).
+		GroupBy("tag").
+		OrderBy("tag").
+		ToSql()
+	if err != nil {
+		return nil, ErrTagDatabase.Wrap("GetCounts", "r.Builder", err)
+	}
+
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, ErrTagDatabase.Wrap("GetCounts", "r.Pool.Query", err)
+	}
+	defer rows.Close()
+
+	tags := make([]entity.Tag, 0)
+
+	for rows.Next() {
+		var t entity.Tag
+		if err := rows.Scan(&t.Name, &t.Devices); err != nil {
+			return nil, ErrTagDatabase.Wrap("GetCounts", "rows.Scan", err)
+		}
+
+		tags = append(tags, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, ErrTagDatabase.Wrap("GetCounts", "rows.Err", err)
+	}
+
+	return tags, nil
+}
+
+// GetGUIDs returns the GUIDs of the devices carrying any of tags, in order.
+// Devices in the trash are included, so that a fleet-wide change reaches
+// them too.
+func (r *TagRepo) GetGUIDs(ctx context.Context, tags []string, tenantID string) ([]string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	if len(tags) == 0 {
+		return []string{}, nil
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Select("DISTINCT guid").
+		From("device_tags").
+		Where(squirrel.Eq{"tenant_id": tenantID, "tag": tags}).
+		OrderBy("guid").
+		ToSql()
+	if err != nil {
+		return nil, ErrTagDatabase.Wrap("GetGUIDs", "r.Builder", err)
+	}
+
+	guids, err := queryStrings(ctx, r.SQL, sqlQuery, args...)
+	if err != nil {
+		return nil, ErrTagDatabase.Wrap("GetGUIDs", "queryStrings", err)
+	}
+
+	return guids, nil
+}
+
+// GetTags returns the GUID and tags of each device of guids. Unknown GUIDs
+// are skipped.
+func (r *TagRepo) GetTags(ctx context.Context, guids []string, tenantID string) ([]entity.Device, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	devices := make([]entity.Device, 0, len(guids))
+
+	for chunk := range slices.Chunk(guids, memberChunk) {
+		current, err := r.tagsOf(ctx, chunk, tenantID)
+		if err != nil {
+			return nil, ErrTagDatabase.Wrap("GetTags", "r.tagsOf", err)
+		}
+
+		devices = append(devices, current...)
+	}
+
+	return devices, nil
+}
+
+// Update removes the tags of remove from the devices of guids and adds those
+// of add, in one transaction, and returns how many devices changed. Unknown
+// GUIDs are skipped. A changed device gets a new version.
+func (r *TagRepo) Update(ctx context.Context, guids, add, remove []string, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	changed := 0
+
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		for chunk := range slices.Chunk(guids, memberChunk) {
+			current, err := r.tagsOf(ctx, chunk, tenantID)
+			if err != nil {
+				return err
+			}
+
+			for _, d := range current {
+				tags := taglist.Split(d.Tags)
+
+				updated := taglist.Apply(tags, add, remove)
+				if slices.Equal(tags, updated) {
+					continue
+				}
+
+				if err := r.setTags(ctx, d.GUID, tenantID, updated); err != nil {
+					return err
+				}
+
+				changed++
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, ErrTagDatabase.Wrap("Update", "r.InTx", err)
+	}
+
+	return changed, nil
+}
+
+// tagsOf reads the tags of the devices of guids.
+func (r *TagRepo) tagsOf(ctx context.Context, guids []string, tenantID string) ([]entity.Device, error) {
+	sqlQuery, args, err := r.Builder.
+		Select("guid", "COALESCE(tags, '')").
+		From("devices").
+		Where(squirrel.Eq{"tenantid": tenantID, "guid": guids}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := make([]entity.Device, 0, len(guids))
+
+	for rows.Next() {
+		var d entity.Device
+		if err := rows.Scan(&d.GUID, &d.Tags); err != nil {
+			return nil, err
+		}
+
+		devices = append(devices, d)
+	}
+
+	return devices, rows.Err()
+}
+
+// setTags writes the tags of a device, and gives it a new version.
+func (r *TagRepo) setTags(ctx context.Context, guid, tenantID string, tags []string) error {
+	sqlQuery, args, err := r.Builder.
+		Update("devices").
+		Set("tags", taglist.Join(tags)).
+		Set("version", versions.New()).
+		Where("guid = ? AND tenantid = ?", guid, tenantID).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+		return err
+	}
+
+	return writeDeviceTags(ctx, r.SQL, guid, tenantID, tags)
+}
+
+// writeDeviceTags replaces the device_tags rows of a device. It is called in
+// the transaction that writes devices.tags.
+func writeDeviceTags(ctx context.Context, s *db.SQL, guid, tenantID string, tags []string) error {
+	sqlQuery, args, err := s.Builder.
+		Delete("device_tags").
+		Where("guid = ? AND tenant_id = ?", guid, tenantID).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	builder := s.Builder.
+		Insert("device_tags").
+		Columns("guid", "tenant_id", "tag")
+
+	for _, tag := range tags {
+		builder = builder.Values(guid, tenantID, tag)
+	}
+
+	sqlQuery, args, err = builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+
+	return err
+}
+
+// liveDevices selects the GUIDs of the devices of tenantID not in the trash.
+func liveDevices(s *db.SQL, tenantID string) squirrel.SelectBuilder {
+	return s.Builder.
+		Select("guid").
+		From("devices").
+		Where("tenantid = ?", tenantID).
+		Where(notDeleted)
+}
+
+// queryStrings runs a query of one text column.
+func queryStrings(ctx context.Context, s *db.SQL, sqlQuery string, args ...any) ([]string, error) {
+	rows, err := s.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]string, 0)
+
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+
+		out = append(out, value)
+	}
+
+	return out, rows.Err()
+}
@@ -0,0 +1,106 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/sqldb"
+	"github.com/device-management-toolkit/console/pkg/db"
+)
+
+func setupTagRepo(t *testing.T) (*sqldb.TagRepo, *sqldb.DeviceRepo) {
+	t.Helper()
+
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	dbConn.SetMaxOpenConns(1)
+	t.Cleanup(func() { dbConn.Close() })
+
+	_, err = dbConn.ExecContext(context.Background(), schema)
+	require.NoError(t, err)
+
+	database := &db.SQL{
+		Builder:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		Pool:       dbConn,
+		IsEmbedded: true,
+	}
+
+	return sqldb.NewTagRepo(database, mocks.NewMockLogger(nil)), sqldb.NewDeviceRepo(database, mocks.NewMockLogger(nil))
+}
+
+func TestTagRepo(t *testing.T) {
+	t.Parallel()
+
+	tags, devices := setupTagRepo(t)
+	ctx := context.Background()
+
+	for _, d := range []entity.Device{
+		{GUID: "a", Tags: "lab, site-a", TenantID: "tenant"},
+		{GUID: "b", Tags: "lab", TenantID: "tenant"},
+		{GUID: "c", Tags: "site-a,lab,", TenantID: "tenant"},
+		{GUID: "d", Tags: "lab", TenantID: "other"},
+	} {
+		_, err := devices.Insert(ctx, &d)
+		require.NoError(t, err)
+	}
+
+	deleted, err := devices.Delete(ctx, "c", "tenant")
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	counts, err := tags.GetCounts(ctx, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Tag{{Name: "lab", Devices: 2}, {Name: "site-a", Devices: 1}}, counts, "devices in the trash are not counted")
+
+	distinct, err := devices.GetDistinctTags(ctx, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, []string{"lab", "site-a"}, distinct)
+
+	guids, err := tags.GetGUIDs(ctx, []string{"site-a"}, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "c"}, guids, "devices in the trash are included")
+
+	both, err := devices.GetByTags(ctx, []string{"lab", "site-a"}, "AND", 10, 0, "tenant")
+	require.NoError(t, err)
+	require.Len(t, both, 1)
+	require.Equal(t, "a", both[0].GUID)
+
+	either, err := devices.GetByTags(ctx, []string{"site-a", "missing"}, "OR", 10, 0, "tenant")
+	require.NoError(t, err)
+	require.Len(t, either, 1)
+
+	before, err := devices.GetByGUID(ctx, "b")
+	require.NoError(t, err)
+
+	changed, err := tags.Update(ctx, []string{"a", "b", "missing"}, []string{"bench"}, []string{"lab"}, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, 2, changed)
+
+	after, err := devices.GetByGUID(ctx, "b")
+	require.NoError(t, err)
+	require.Equal(t, "bench", after.Tags)
+	require.NotEqual(t, before.Version, after.Version)
+
+	changed, err = tags.Update(ctx, []string{"b"}, []string{"bench"}, nil, "tenant")
+	require.NoError(t, err)
+	require.Zero(t, changed, "a device that already carries the tag is unchanged")
+
+	counts, err = tags.GetCounts(ctx, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, []entity.Tag{{Name: "bench", Devices: 2}, {Name: "site-a", Devices: 1}}, counts)
+
+	d := &entity.Device{GUID: "b", Tags: "office", TenantID: "tenant"}
+	_, err = devices.Update(ctx, d)
+	require.NoError(t, err)
+
+	guids, err = tags.GetGUIDs(ctx, []string{"bench", "office"}, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, guids, "updating a device rewrites its tags")
+}
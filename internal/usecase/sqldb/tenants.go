@@ -0,0 +1,63 @@
+package sqldb
+
+import (
+	"context"
+	"strings"
+
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/db"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// TenantRepo -.
+type TenantRepo struct {
+	*db.SQL
+	log logger.Interface
+}
+
+var ErrTenantDatabase = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("TenantRepo")}
+
+// tenantColumns are the tenant columns of the tables of tenant entities.
+// Tables of rows that belong to one of those, such as the links of profiles
+// and the members of groups, add no tenant of their own.
+var tenantColumns = []struct{ table, tenant string }{
+	{"devices", "tenantid"},
+	{"profiles", "tenant_id"},
+	{"ciraconfigs", "tenant_id"},
+	{"wirelessconfigs", "tenant_id"},
+	{"ieee8021xconfigs", "tenant_id"},
+	{"domains", "tenant_id"},
+	{"users", "tenant_id"},
+	{"api_keys", "tenant_id"},
+	{"access_policies", "tenant_id"},
+	{"device_groups", "tenant_id"},
+}
+
+// NewTenantRepo -.
+func NewTenantRepo(database *db.SQL, log logger.Interface) *TenantRepo {
+	return &TenantRepo{database, log}
+}
+
+// Get lists the tenants of the rows of every table, those in the trash
+// included.
+func (r *TenantRepo) Get(ctx context.Context) ([]string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	selects := make([]string, 0, len(tenantColumns))
+
+	for _, c := range tenantColumns {
+		selects = append(selects, "SELECT "+c.tenant+" AS tenant FROM "+c.table)
+	}
+
+	// UNION drops repeats.
+	sqlQuery := strings.Join(selects, " UNION ") + " ORDER BY tenant"
+
+	tenants, err := queryStrings(ctx, r.SQL, sqlQuery)
+	if err != nil {
+		return nil, ErrTenantDatabase.Wrap("Get", "queryStrings", err)
+	}
+
+	return tenants, nil
+}
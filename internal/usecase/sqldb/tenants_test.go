@@ -0,0 +1,46 @@
+package sqldb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/sqldb"
+)
+
+func TestTenantRepo_Get(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := setupTrashDatabase(t)
+	log := mocks.NewMockLogger(nil)
+
+	repo := sqldb.NewTenantRepo(database, log)
+
+	tenants, err := repo.Get(ctx)
+	require.NoError(t, err)
+	require.Empty(t, tenants)
+
+	_, err = sqldb.NewDeviceRepo(database, log).Insert(ctx, &entity.Device{GUID: "guid", TenantID: "t2"})
+	require.NoError(t, err)
+
+	_, err = sqldb.NewDomainRepo(database, log).Insert(ctx, &entity.Domain{ProfileName: "domain", DomainSuffix: "example.com", TenantID: "t1"})
+	require.NoError(t, err)
+
+	_, err = sqldb.NewCIRARepo(database, log).Insert(ctx, &entity.CIRAConfig{ConfigName: "cira", TenantID: "t1"})
+	require.NoError(t, err)
+
+	// Tenants whose only entities are in the trash are listed too.
+	_, err = sqldb.NewCIRARepo(database, log).Insert(ctx, &entity.CIRAConfig{ConfigName: "cira", TenantID: "t3"})
+	require.NoError(t, err)
+
+	_, err = sqldb.NewCIRARepo(database, log).Delete(ctx, "cira", "t3")
+	require.NoError(t, err)
+
+	tenants, err = repo.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"t1", "t2", "t3"}, tenants)
+}
@@ -0,0 +1,300 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/db"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// TokenRepo keeps the token revocation list and refresh tokens.
+type TokenRepo struct {
+	*db.SQL
+	log logger.Interface
+}
+
+var ErrTokenDatabase = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("TokenRepo")}
+
+var refreshTokenColumns = []string{
+	"token_hash",
+	"session_id",
+	"username",
+	"used",
+	"expires_at",
+	"creation_date",
+	"tenant_id",
+}
+
+// NewTokenRepo -.
+func NewTokenRepo(database *db.SQL, log logger.Interface) *TokenRepo {
+	return &TokenRepo{database, log}
+}
+
+// Revoke -.
+func (r *TokenRepo) Revoke(ctx context.Context, t *entity.RevokedToken) error {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Insert("revoked_tokens").
+		Columns("id", "subject", "revoked_at", "expires_at").
+		Values(t.ID, t.Subject, t.RevokedAt, t.ExpiresAt).
+		Suffix("ON CONFLICT (id) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return ErrTokenDatabase.Wrap("Revoke", "r.Builder: ", err)
+	}
+
+	if _, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+		return ErrTokenDatabase.Wrap("Revoke", "r.Pool.Exec", err)
+	}
+
+	return nil
+}
+
+// IsRevoked -.
+func (r *TokenRepo) IsRevoked(ctx context.Context, ids []string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select("COUNT(*)").
+		From("revoked_tokens").
+		Where(squirrel.Eq{"id": ids}).
+		ToSql()
+	if err != nil {
+		return false, ErrTokenDatabase.Wrap("IsRevoked", "r.Builder: ", err)
+	}
+
+	var count int
+
+	if err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count); err != nil {
+		return false, ErrTokenDatabase.Wrap("IsRevoked", "r.Pool.QueryRow", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetRevoked -.
+func (r *TokenRepo) GetRevoked(ctx context.Context, top, skip int) ([]entity.RevokedToken, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	const defaultTop = 100
+
+	limitedTop := uint64(defaultTop)
+	if top > 0 {
+		limitedTop = uint64(top)
+	}
+
+	limitedSkip := uint64(0)
+	if skip > 0 {
+		limitedSkip = uint64(skip)
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Select("id", "subject", "revoked_at", "expires_at").
+		From("revoked_tokens").
+		OrderBy("id").
+		Limit(limitedTop).
+		Offset(limitedSkip).
+		ToSql()
+	if err != nil {
+		return nil, ErrTokenDatabase.Wrap("GetRevoked", "r.Builder: ", err)
+	}
+
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, ErrTokenDatabase.Wrap("GetRevoked", "r.Pool.Query", err)
+	}
+
+	defer rows.Close()
+
+	if rows.Err() != nil {
+		return nil, ErrTokenDatabase.Wrap("GetRevoked", "rows.Err", rows.Err())
+	}
+
+	revoked := make([]entity.RevokedToken, 0)
+
+	for rows.Next() {
+		t := entity.RevokedToken{}
+
+		if err = rows.Scan(&t.ID, &t.Subject, &t.RevokedAt, &t.ExpiresAt); err != nil {
+			return nil, ErrTokenDatabase.Wrap("GetRevoked", "rows.Scan: ", err)
+		}
+
+		revoked = append(revoked, t)
+	}
+
+	return revoked, nil
+}
+
+// InsertRefreshToken -.
+func (r *TokenRepo) InsertRefreshToken(ctx context.Context, t *entity.RefreshToken) error {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Insert("refresh_tokens").
+		Columns(refreshTokenColumns...).
+		Values(t.TokenHash, t.SessionID, t.Username, t.Used, t.ExpiresAt, t.CreationDate, t.TenantID).
+		ToSql()
+	if err != nil {
+		return ErrTokenDatabase.Wrap("InsertRefreshToken", "r.Builder: ", err)
+	}
+
+	if _, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+		return ErrTokenDatabase.Wrap("InsertRefreshToken", "r.Pool.Exec", err)
+	}
+
+	return nil
+}
+
+// GetRefreshToken -.
+func (r *TokenRepo) GetRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select(refreshTokenColumns...).
+		From("refresh_tokens").
+		Where("token_hash = ?", tokenHash).
+		ToSql()
+	if err != nil {
+		return nil, ErrTokenDatabase.Wrap("GetRefreshToken", "r.Builder: ", err)
+	}
+
+	row := r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...)
+
+	t := entity.RefreshToken{}
+
+	err = row.Scan(&t.TokenHash, &t.SessionID, &t.Username, &t.Used, &t.ExpiresAt, &t.CreationDate, &t.TenantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, ErrTokenDatabase.Wrap("GetRefreshToken", "row.Scan: ", err)
+	}
+
+	return &t, nil
+}
+
+// MarkRefreshTokenUsed -.
+func (r *TokenRepo) MarkRefreshTokenUsed(ctx context.Context, tokenHash string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Update("refresh_tokens").
+		Set("used", true).
+		Where("token_hash = ? AND used = ?", tokenHash, false).
+		ToSql()
+	if err != nil {
+		return false, ErrTokenDatabase.Wrap("MarkRefreshTokenUsed", "r.Builder: ", err)
+	}
+
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return false, ErrTokenDatabase.Wrap("MarkRefreshTokenUsed", "r.Pool.Exec", err)
+	}
+
+	result, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("TokenRepo - MarkRefreshTokenUsed - r.Pool.Exec: %w", err)
+	}
+
+	return result > 0, nil
+}
+
+// GetRefreshTokensByUser -.
+func (r *TokenRepo) GetRefreshTokensByUser(ctx context.Context, username, tenantID string) ([]entity.RefreshToken, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select(refreshTokenColumns...).
+		From("refresh_tokens").
+		Where("LOWER(username) = LOWER(?) AND tenant_id = ?", username, tenantID).
+		OrderBy("creation_date").
+		ToSql()
+	if err != nil {
+		return nil, ErrTokenDatabase.Wrap("GetRefreshTokensByUser", "r.Builder: ", err)
+	}
+
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, ErrTokenDatabase.Wrap("GetRefreshTokensByUser", "r.Pool.Query", err)
+	}
+
+	defer rows.Close()
+
+	if rows.Err() != nil {
+		return nil, ErrTokenDatabase.Wrap("GetRefreshTokensByUser", "rows.Err", rows.Err())
+	}
+
+	tokens := make([]entity.RefreshToken, 0)
+
+	for rows.Next() {
+		t := entity.RefreshToken{}
+
+		err = rows.Scan(&t.TokenHash, &t.SessionID, &t.Username, &t.Used, &t.ExpiresAt, &t.CreationDate, &t.TenantID)
+		if err != nil {
+			return nil, ErrTokenDatabase.Wrap("GetRefreshTokensByUser", "rows.Scan: ", err)
+		}
+
+		tokens = append(tokens, t)
+	}
+
+	return tokens, nil
+}
+
+// DeleteSession -.
+func (r *TokenRepo) DeleteSession(ctx context.Context, sessionID string) error {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Delete("refresh_tokens").
+		Where("session_id = ?", sessionID).
+		ToSql()
+	if err != nil {
+		return ErrTokenDatabase.Wrap("DeleteSession", "r.Builder: ", err)
+	}
+
+	if _, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+		return ErrTokenDatabase.Wrap("DeleteSession", "r.Pool.Exec", err)
+	}
+
+	return nil
+}
+
+// Prune -.
+func (r *TokenRepo) Prune(ctx context.Context, now string) error {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	for _, table := range []string{"revoked_tokens", "refresh_tokens"} {
+		sqlQuery, args, err := r.Builder.
+			Delete(table).
+			Where("expires_at < ?", now).
+			ToSql()
+		if err != nil {
+			return ErrTokenDatabase.Wrap("Prune", "r.Builder: ", err)
+		}
+
+		if _, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+			return ErrTokenDatabase.Wrap("Prune", "r.Pool.Exec", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/sqldb"
+	"github.com/device-management-toolkit/console/pkg/db"
+)
+
+func setupTokenRepo(t *testing.T) *sqldb.TokenRepo {
+	t.Helper()
+
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { dbConn.Close() })
+
+	_, err = dbConn.ExecContext(context.Background(), schema)
+	require.NoError(t, err)
+
+	return sqldb.NewTokenRepo(&db.SQL{
+		Builder:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		Pool:       dbConn,
+		IsEmbedded: true,
+	}, mocks.NewMockLogger(nil))
+}
+
+func testRefreshToken(hash, session, expires string) *entity.RefreshToken {
+	return &entity.RefreshToken{
+		TokenHash:    hash,
+		SessionID:    session,
+		Username:     "jdoe",
+		ExpiresAt:    expires,
+		CreationDate: "2026-10-19T00:00:00Z",
+		TenantID:     "",
+	}
+}
+
+func TestTokenRepo_Revoke(t *testing.T) {
+	t.Parallel()
+
+	repo := setupTokenRepo(t)
+	ctx := context.Background()
+
+	revoked := &entity.RevokedToken{ID: "jti-1", Subject: "jdoe", RevokedAt: "2026-10-19T00:00:00Z", ExpiresAt: "2026-10-19T01:00:00Z"}
+
+	require.NoError(t, repo.Revoke(ctx, revoked))
+	require.NoError(t, repo.Revoke(ctx, revoked), "revoking twice is harmless")
+
+	listed, err := repo.IsRevoked(ctx, []string{"jti-1", "sid-1"})
+	require.NoError(t, err)
+	require.True(t, listed)
+
+	listed, err = repo.IsRevoked(ctx, []string{"jti-2"})
+	require.NoError(t, err)
+	require.False(t, listed)
+
+	all, err := repo.GetRevoked(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []entity.RevokedToken{*revoked}, all)
+
+	require.NoError(t, repo.Prune(ctx, "2026-10-19T02:00:00Z"))
+
+	listed, err = repo.IsRevoked(ctx, []string{"jti-1"})
+	require.NoError(t, err)
+	require.False(t, listed, "expired entries are pruned")
+}
+
+func TestTokenRepo_RefreshTokens(t *testing.T) {
+	t.Parallel()
+
+	repo := setupTokenRepo(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.InsertRefreshToken(ctx, testRefreshToken("hash-1", "session-1", "2026-10-20T00:00:00Z")))
+	require.NoError(t, repo.InsertRefreshToken(ctx, testRefreshToken("hash-2", "session-2", "2026-10-20T00:00:00Z")))
+
+	got, err := repo.GetRefreshToken(ctx, "hash-1")
+	require.NoError(t, err)
+	require.Equal(t, testRefreshToken("hash-1", "session-1", "2026-10-20T00:00:00Z"), got)
+
+	marked, err := repo.MarkRefreshTokenUsed(ctx, "hash-1")
+	require.NoError(t, err)
+	require.True(t, marked)
+
+	marked, err = repo.MarkRefreshTokenUsed(ctx, "hash-1")
+	require.NoError(t, err)
+	require.False(t, marked, "a token is marked used once")
+
+	byUser, err := repo.GetRefreshTokensByUser(ctx, "JDOE", "")
+	require.NoError(t, err)
+	require.Len(t, byUser, 2)
+
+	byUser, err = repo.GetRefreshTokensByUser(ctx, "jdoe", "tenant-b")
+	require.NoError(t, err)
+	require.Empty(t, byUser)
+
+	require.NoError(t, repo.DeleteSession(ctx, "session-1"))
+
+	missing, err := repo.GetRefreshToken(ctx, "hash-1")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+}
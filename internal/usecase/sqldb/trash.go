@@ -0,0 +1,367 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/db"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// TrashRepo -.
+type TrashRepo struct {
+	*db.SQL
+	log logger.Interface
+}
+
+var (
+	ErrTrashDatabase   = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("TrashRepo")}
+	ErrTrashForeignKey = ForeignKeyViolationError{Console: consoleerrors.CreateConsoleError("TrashRepo")}
+
+	errUnknownTrashKind = errors.New("unknown kind of entity")
+)
+
+// trashTable tells where the entities of one kind live. Name is the column
+// listed beside the ID. Refs join, as r, the rows the entity, as p, refers
+// to; they must be live for it to be restored. Links are tables of rows
+// keyed like the entity that are purged with it, naming its tenant in
+// linkTenant if set. Links are deleted outright rather than left to ON
+// DELETE CASCADE, which SQLite only applies on connections that enabled
+// foreign keys.
+type trashTable struct {
+	table, id, tenant, name string
+	refs                    []string
+	links                   []string
+	linkTenant              string
+}
+
+// linkTenantColumn is the tenant column of t's links.
+func (t trashTable) linkTenantColumn() string {
+	if t.linkTenant != "" {
+		return t.linkTenant
+	}
+
+	return t.tenant
+}
+
+var trashTables = map[string]trashTable{
+	entity.TrashDevices: {
+		table: "devices", id: "guid", tenant: "tenantid", name: "hostname",
+		links: []string{"device_tags", "device_group_members"}, linkTenant: "tenant_id",
+	},
+	entity.TrashProfiles: {
+		table: "profiles", id: "profile_name", tenant: "tenant_id", name: "profile_name",
+		refs: []string{
+			"ciraconfigs r ON r.cira_config_name = p.cira_config_name AND r.tenant_id = p.tenant_id",
+			"ieee8021xconfigs r ON r.profile_name = p.ieee8021x_profile_name AND r.tenant_id = p.tenant_id",
+			"profiles_wirelessconfigs pw ON pw.profile_name = p.profile_name AND pw.tenant_id = p.tenant_id " +
+				"JOIN wirelessconfigs r ON r.wireless_profile_name = pw.wireless_profile_name AND r.tenant_id = pw.tenant_id",
+		},
+		links: []string{"profiles_wirelessconfigs"},
+	},
+	entity.TrashCIRAConfigs: {table: "ciraconfigs", id: "cira_config_name", tenant: "tenant_id", name: "cira_config_name"},
+	entity.TrashWirelessConfigs: {
+		table: "wirelessconfigs", id: "wireless_profile_name", tenant: "tenant_id", name: "wireless_profile_name",
+		refs: []string{"ieee8021xconfigs r ON r.profile_name = p.ieee8021x_profile_name AND r.tenant_id = p.tenant_id"},
+	},
+	entity.TrashIEEE8021xConfigs: {table: "ieee8021xconfigs", id: "profile_name", tenant: "tenant_id", name: "profile_name"},
+	entity.TrashDomains:          {table: "domains", id: "name", tenant: "tenant_id", name: "domain_suffix"},
+}
+
+// NewTrashRepo -.
+func NewTrashRepo(database *db.SQL, log logger.Interface) *TrashRepo {
+	return &TrashRepo{database, log}
+}
+
+// GetCount -.
+func (r *TrashRepo) GetCount(ctx context.Context, kind, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	t, ok := trashTables[kind]
+	if !ok {
+		return 0, ErrTrashDatabase.Wrap("GetCount", "trashTables", errUnknownTrashKind)
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Select("COUNT(*)").
+		From(t.table).
+		Where(t.tenant+" = ? AND deleted_at IS NOT NULL", tenantID).
+		ToSql()
+	if err != nil {
+		return 0, ErrTrashDatabase.Wrap("GetCount", "r.Builder", err)
+	}
+
+	var count int
+
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
+	if err != nil {
+		return 0, ErrTrashDatabase.Wrap("GetCount", "r.Pool.QueryRow", err)
+	}
+
+	return count, nil
+}
+
+// Get lists the entities of a kind in the trash, latest deleted first.
+func (r *TrashRepo) Get(ctx context.Context, kind string, top, skip int, tenantID string) ([]entity.TrashItem, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	t, ok := trashTables[kind]
+	if !ok {
+		return nil, ErrTrashDatabase.Wrap("Get", "trashTables", errUnknownTrashKind)
+	}
+
+	const defaultTop = 100
+
+	limitedTop := uint64(defaultTop)
+	if top > 0 {
+		limitedTop = uint64(top)
+	}
+
+	limitedSkip := uint64(0)
+	if skip > 0 {
+		limitedSkip = uint64(skip)
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Select(t.id, t.name, t.tenant, "deleted_at").
+		From(t.table).
+		Where(t.tenant+" = ? AND deleted_at IS NOT NULL", tenantID).
+		OrderBy("deleted_at DESC", t.id).
+		Limit(limitedTop).
+		Offset(limitedSkip).
+		ToSql()
+	if err != nil {
+		return nil, ErrTrashDatabase.Wrap("Get", "r.Builder", err)
+	}
+
+	items, err := r.query(ctx, kind, sqlQuery, args)
+	if err != nil {
+		return nil, ErrTrashDatabase.Wrap("Get", "r.query", err)
+	}
+
+	return items, nil
+}
+
+// GetExpired lists the entities of a kind deleted before deletedBefore, in
+// every tenant, oldest first.
+func (r *TrashRepo) GetExpired(ctx context.Context, kind, deletedBefore string, top, skip int) ([]entity.TrashItem, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	t, ok := trashTables[kind]
+	if !ok {
+		return nil, ErrTrashDatabase.Wrap("GetExpired", "trashTables", errUnknownTrashKind)
+	}
+
+	limitedSkip := uint64(0)
+	if skip > 0 {
+		limitedSkip = uint64(skip)
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Select(t.id, t.name, t.tenant, "deleted_at").
+		From(t.table).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		OrderBy("deleted_at", t.id).
+		Limit(uint64(max(top, 1))).
+		Offset(limitedSkip).
+		ToSql()
+	if err != nil {
+		return nil, ErrTrashDatabase.Wrap("GetExpired", "r.Builder", err)
+	}
+
+	items, err := r.query(ctx, kind, sqlQuery, args)
+	if err != nil {
+		return nil, ErrTrashDatabase.Wrap("GetExpired", "r.query", err)
+	}
+
+	return items, nil
+}
+
+func (r *TrashRepo) query(ctx context.Context, kind, sqlQuery string, args []interface{}) ([]entity.TrashItem, error) {
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	items := make([]entity.TrashItem, 0)
+
+	for rows.Next() {
+		item := entity.TrashItem{Kind: kind}
+
+		var name sql.NullString
+
+		if err := rows.Scan(&item.ID, &name, &item.TenantID, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+
+		item.Name = name.String
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// Restore takes the entity out of the trash, unless it refers to another
+// entity still in there.
+func (r *TrashRepo) Restore(ctx context.Context, kind, id, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	t, ok := trashTables[kind]
+	if !ok {
+		return false, ErrTrashDatabase.Wrap("Restore", "trashTables", errUnknownTrashKind)
+	}
+
+	var restored bool
+
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		for _, ref := range t.refs {
+			sqlQuery, args, err := r.Builder.
+				Select("1").
+				From(t.table+" p").
+				Join(ref).
+				Where("p."+t.id+" = ? AND p."+t.tenant+" = ? AND r.deleted_at IS NOT NULL", id, tenantID).
+				Limit(1).
+				ToSql()
+			if err != nil {
+				return ErrTrashDatabase.Wrap("Restore", "r.Builder", err)
+			}
+
+			var one int
+
+			err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&one)
+			if err == nil {
+				return ErrTrashForeignKey.Wrap(kind + " " + id + " uses an entity in the trash; restore that first")
+			}
+
+			if !errors.Is(err, sql.ErrNoRows) {
+				return ErrTrashDatabase.Wrap("Restore", "r.Pool.QueryRow", err)
+			}
+		}
+
+		sqlQuery, args, err := r.Builder.
+			Update(t.table).
+			Set("deleted_at", nil).
+			Where(t.id+" = ? AND "+t.tenant+" = ? AND deleted_at IS NOT NULL", id, tenantID).
+			ToSql()
+		if err != nil {
+			return ErrTrashDatabase.Wrap("Restore", "r.Builder", err)
+		}
+
+		res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+		if err != nil {
+			return ErrTrashDatabase.Wrap("Restore", "r.Pool.Exec", err)
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return ErrTrashDatabase.Wrap("Restore", "res.RowsAffected", err)
+		}
+
+		restored = rowsAffected > 0
+
+		return nil
+	})
+
+	return restored, err
+}
+
+// MoveToTrash moves a live entity to the trash as deleted at deletedAt.
+func (r *TrashRepo) MoveToTrash(ctx context.Context, kind, id, tenantID, deletedAt string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	t, ok := trashTables[kind]
+	if !ok {
+		return false, ErrTrashDatabase.Wrap("MoveToTrash", "trashTables", errUnknownTrashKind)
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Update(t.table).
+		Set("deleted_at", deletedAt).
+		Where(t.id+" = ? AND "+t.tenant+" = ?", id, tenantID).
+		Where(notDeleted).
+		ToSql()
+	if err != nil {
+		return false, ErrTrashDatabase.Wrap("MoveToTrash", "r.Builder", err)
+	}
+
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return false, ErrTrashDatabase.Wrap("MoveToTrash", "r.Pool.Exec", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, ErrTrashDatabase.Wrap("MoveToTrash", "res.RowsAffected", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Purge deletes the entity in the trash for good, with its links.
+func (r *TrashRepo) Purge(ctx context.Context, kind, id, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	t, ok := trashTables[kind]
+	if !ok {
+		return false, ErrTrashDatabase.Wrap("Purge", "trashTables", errUnknownTrashKind)
+	}
+
+	var purged bool
+
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		for _, link := range t.links {
+			sqlQuery, args, err := r.Builder.
+				Delete(link).
+				Where(t.id+" = ? AND "+t.linkTenantColumn()+" = ?", id, tenantID).
+				Where("EXISTS (SELECT 1 FROM "+t.table+" p WHERE p."+t.id+" = ? AND p."+t.tenant+" = ? AND p.deleted_at IS NOT NULL)", id, tenantID).
+				ToSql()
+			if err != nil {
+				return ErrTrashDatabase.Wrap("Purge", "r.Builder", err)
+			}
+
+			if _, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+				return ErrTrashDatabase.Wrap("Purge", "r.Pool.Exec", err)
+			}
+		}
+
+		sqlQuery, args, err := r.Builder.
+			Delete(t.table).
+			Where(t.id+" = ? AND "+t.tenant+" = ? AND deleted_at IS NOT NULL", id, tenantID).
+			ToSql()
+		if err != nil {
+			return ErrTrashDatabase.Wrap("Purge", "r.Builder", err)
+		}
+
+		res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+		if err != nil {
+			if db.CheckForeignKeyViolation(err) {
+				return ErrTrashForeignKey.Wrap(kind + " " + id + " is used by an entity in the trash; purge that first")
+			}
+
+			return ErrTrashDatabase.Wrap("Purge", "r.Pool.Exec", err)
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return ErrTrashDatabase.Wrap("Purge", "res.RowsAffected", err)
+		}
+
+		purged = rowsAffected > 0
+
+		return nil
+	})
+
+	return purged, err
+}
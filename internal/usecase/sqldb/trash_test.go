@@ -0,0 +1,147 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/sqldb"
+	"github.com/device-management-toolkit/console/pkg/db"
+)
+
+func setupTrashDatabase(t *testing.T) *db.SQL {
+	t.Helper()
+
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	dbConn.SetMaxOpenConns(1)
+	t.Cleanup(func() { dbConn.Close() })
+
+	_, err = dbConn.ExecContext(context.Background(), schema)
+	require.NoError(t, err)
+
+	return &db.SQL{
+		Builder:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		Pool:       dbConn,
+		IsEmbedded: true,
+	}
+}
+
+func TestTrashedKeysCanBeReused(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := setupTrashDatabase(t)
+	log := mocks.NewMockLogger(nil)
+
+	devices := sqldb.NewDeviceRepo(database, log)
+	profiles := sqldb.NewProfileRepo(database, log)
+	wireless := sqldb.NewWirelessRepo(database, log)
+	links := sqldb.NewProfileWiFiConfigsRepo(database, log)
+	ciraConfigs := sqldb.NewCIRARepo(database, log)
+	domains := sqldb.NewDomainRepo(database, log)
+	groups := sqldb.NewDeviceGroupRepo(database, log)
+	tags := sqldb.NewTagRepo(database, log)
+	trash := sqldb.NewTrashRepo(database, log)
+
+	// A device re-added under the GUID of a trashed one replaces it, and the
+	// trashed device's tags and group memberships go with it.
+	_, err := devices.Insert(ctx, &entity.Device{GUID: "guid", Hostname: "old", Tags: "lab", TenantID: "tenant1"})
+	require.NoError(t, err)
+
+	err = groups.Insert(ctx, &entity.DeviceGroup{ID: "group", Name: "lab", TenantID: "tenant1"})
+	require.NoError(t, err)
+
+	_, err = groups.AddMembers(ctx, "group", []string{"guid"}, "tenant1")
+	require.NoError(t, err)
+
+	_, err = devices.Delete(ctx, "guid", "tenant1")
+	require.NoError(t, err)
+
+	_, err = devices.Insert(ctx, &entity.Device{GUID: "guid", Hostname: "new", TenantID: "tenant1"})
+	require.NoError(t, err)
+
+	device, err := devices.GetByID(ctx, "guid", "tenant1")
+	require.NoError(t, err)
+	require.Equal(t, "new", device.Hostname)
+
+	count, err := trash.GetCount(ctx, entity.TrashDevices, "tenant1")
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	members, err := groups.GetMemberGUIDs(ctx, []string{"group"}, "tenant1")
+	require.NoError(t, err)
+	require.Empty(t, members)
+
+	tagged, err := tags.GetGUIDs(ctx, []string{"lab"}, "tenant1")
+	require.NoError(t, err)
+	require.Empty(t, tagged)
+
+	// A trashed profile goes with its wireless links.
+	_, err = wireless.Insert(ctx, &entity.WirelessConfig{ProfileName: "wifi", TenantID: "tenant1"})
+	require.NoError(t, err)
+
+	_, err = profiles.Insert(ctx, &entity.Profile{ProfileName: "acm", Activation: "acmactivate", TenantID: "tenant1"})
+	require.NoError(t, err)
+
+	_, err = links.Insert(ctx, &entity.ProfileWiFiConfigs{Priority: 1, ProfileName: "acm", WirelessProfileName: "wifi", TenantID: "tenant1"})
+	require.NoError(t, err)
+
+	_, err = profiles.Delete(ctx, "acm", "tenant1")
+	require.NoError(t, err)
+
+	_, err = profiles.Insert(ctx, &entity.Profile{ProfileName: "acm", Activation: "ccmactivate", TenantID: "tenant1"})
+	require.NoError(t, err)
+
+	profile, err := profiles.GetByName(ctx, "acm", "tenant1")
+	require.NoError(t, err)
+	require.Equal(t, "ccmactivate", profile.Activation)
+
+	wifiLinks, err := links.GetByProfileName(ctx, "acm", "tenant1")
+	require.NoError(t, err)
+	require.Empty(t, wifiLinks)
+
+	// A domain that differs from a trashed one only in case still clashes
+	// with it, and replaces it.
+	_, err = domains.Insert(ctx, &entity.Domain{ProfileName: "domain", DomainSuffix: "example.com", TenantID: "tenant1"})
+	require.NoError(t, err)
+
+	_, err = domains.Delete(ctx, "domain", "tenant1")
+	require.NoError(t, err)
+
+	_, err = domains.Insert(ctx, &entity.Domain{ProfileName: "Domain", DomainSuffix: "Example.com", TenantID: "tenant2"})
+	require.NoError(t, err)
+
+	count, err = trash.GetCount(ctx, entity.TrashDomains, "tenant1")
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	// A trashed CIRA config that a trashed profile uses keeps its name.
+	_, err = ciraConfigs.Insert(ctx, &entity.CIRAConfig{ConfigName: "cira", TenantID: "tenant1"})
+	require.NoError(t, err)
+
+	ciraName := "cira"
+
+	_, err = profiles.Insert(ctx, &entity.Profile{ProfileName: "cira-profile", Activation: "ccmactivate", CIRAConfigName: &ciraName, TenantID: "tenant1"})
+	require.NoError(t, err)
+
+	_, err = profiles.Delete(ctx, "cira-profile", "tenant1")
+	require.NoError(t, err)
+
+	_, err = ciraConfigs.Delete(ctx, "cira", "tenant1")
+	require.NoError(t, err)
+
+	_, err = ciraConfigs.Insert(ctx, &entity.CIRAConfig{ConfigName: "cira", TenantID: "tenant1"})
+	require.ErrorAs(t, err, &repoerrors.NotUniqueError{})
+
+	count, err = trash.GetCount(ctx, entity.TrashCIRAConfigs, "tenant1")
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
@@ -0,0 +1,254 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/db"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// UserRepo -.
+type UserRepo struct {
+	*db.SQL
+	log logger.Interface
+}
+
+var (
+	ErrUserDatabase  = repoerrors.DatabaseError{Console: consoleerrors.CreateConsoleError("UserRepo")}
+	ErrUserNotUnique = repoerrors.NotUniqueError{Console: consoleerrors.CreateConsoleError("UserRepo")}
+)
+
+var userColumns = []string{
+	"username",
+	"password_hash",
+	"role",
+	"must_change_password",
+	"password_changed_at",
+	"creation_date",
+	"created_by",
+	"tenant_id",
+}
+
+// NewUserRepo -.
+func NewUserRepo(database *db.SQL, log logger.Interface) *UserRepo {
+	return &UserRepo{database, log}
+}
+
+// GetCount -.
+func (r *UserRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select("COUNT(*) OVER() AS total_count").
+		From("users").
+		Where("tenant_id = ?", tenantID).
+		ToSql()
+	if err != nil {
+		return 0, ErrUserDatabase.Wrap("GetCount", "r.Builder: ", err)
+	}
+
+	var count int
+
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+
+		return 0, ErrUserDatabase.Wrap("GetCount", "r.Pool.QueryRow", err)
+	}
+
+	return count, nil
+}
+
+// Get -.
+func (r *UserRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.User, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	const defaultTop = 100
+
+	if top == 0 {
+		top = defaultTop
+	}
+
+	limitedTop := uint64(defaultTop)
+	if top > 0 {
+		limitedTop = uint64(top)
+	}
+
+	limitedSkip := uint64(0)
+	if skip > 0 {
+		limitedSkip = uint64(skip)
+	}
+
+	sqlQuery, args, err := r.Builder.
+		Select(userColumns...).
+		From("users").
+		Where("tenant_id = ?", tenantID).
+		OrderBy("username").
+		Limit(limitedTop).
+		Offset(limitedSkip).
+		ToSql()
+	if err != nil {
+		return nil, ErrUserDatabase.Wrap("Get", "r.Builder: ", err)
+	}
+
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, ErrUserDatabase.Wrap("Get", "r.Pool.Query", err)
+	}
+
+	defer rows.Close()
+
+	if rows.Err() != nil {
+		return nil, ErrUserDatabase.Wrap("Get", "rows.Err", rows.Err())
+	}
+
+	users := make([]entity.User, 0)
+
+	for rows.Next() {
+		u := entity.User{}
+
+		err = rows.Scan(&u.Username, &u.PasswordHash, &u.Role, &u.MustChangePassword, &u.PasswordChangedAt, &u.CreationDate, &u.CreatedBy, &u.TenantID)
+		if err != nil {
+			return nil, ErrUserDatabase.Wrap("Get", "rows.Scan: ", err)
+		}
+
+		users = append(users, u)
+	}
+
+	return users, nil
+}
+
+// GetByUsername matches the username case-insensitively. It searches every
+// tenant, as usernames are unique across them and login names no tenant.
+func (r *UserRepo) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Select(userColumns...).
+		From("users").
+		Where("LOWER(username) = LOWER(?)", username).
+		ToSql()
+	if err != nil {
+		return nil, ErrUserDatabase.Wrap("GetByUsername", "r.Builder: ", err)
+	}
+
+	row := r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...)
+
+	u := entity.User{}
+
+	err = row.Scan(&u.Username, &u.PasswordHash, &u.Role, &u.MustChangePassword, &u.PasswordChangedAt, &u.CreationDate, &u.CreatedBy, &u.TenantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, ErrUserDatabase.Wrap("GetByUsername", "row.Scan: ", err)
+	}
+
+	return &u, nil
+}
+
+// Delete -.
+func (r *UserRepo) Delete(ctx context.Context, username, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Delete("users").
+		Where("LOWER(username) = LOWER(?) AND tenant_id = ?", username, tenantID).
+		ToSql()
+	if err != nil {
+		return false, ErrUserDatabase.Wrap("Delete", "r.Builder: ", err)
+	}
+
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return false, ErrUserDatabase.Wrap("Delete", "r.Pool.Exec", err)
+	}
+
+	result, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("UserRepo - Delete - r.Pool.Exec: %w", err)
+	}
+
+	return result > 0, nil
+}
+
+// Update -.
+func (r *UserRepo) Update(ctx context.Context, u *entity.User) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	sqlQuery, args, err := r.Builder.
+		Update("users").
+		Set("password_hash", u.PasswordHash).
+		Set("role", u.Role).
+		Set("must_change_password", u.MustChangePassword).
+		Set("password_changed_at", u.PasswordChangedAt).
+		Where("LOWER(username) = LOWER(?) AND tenant_id = ?", u.Username, u.TenantID).
+		ToSql()
+	if err != nil {
+		return false, ErrUserDatabase.Wrap("Update", "r.Builder: ", err)
+	}
+
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return false, ErrUserDatabase.Wrap("Update", "r.Pool.Exec", err)
+	}
+
+	result, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("UserRepo - Update - r.Pool.Exec: %w", err)
+	}
+
+	return result > 0, nil
+}
+
+// Insert -.
+func (r *UserRepo) Insert(ctx context.Context, u *entity.User) (string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	insertBuilder := r.Builder.
+		Insert("users").
+		Columns(userColumns...).
+		Values(u.Username, u.PasswordHash, u.Role, u.MustChangePassword, u.PasswordChangedAt, u.CreationDate, u.CreatedBy, u.TenantID)
+
+	if !r.IsEmbedded {
+		insertBuilder = insertBuilder.Suffix("RETURNING xmin::text")
+	}
+
+	sqlQuery, args, err := insertBuilder.ToSql()
+	if err != nil {
+		return "", ErrUserDatabase.Wrap("Insert", "r.Builder: ", err)
+	}
+
+	version := ""
+
+	if r.IsEmbedded {
+		_, err = r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
+	} else {
+		err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&version)
+	}
+
+	if err != nil {
+		if db.CheckNotUnique(err) {
+			return "", ErrUserNotUnique.Wrap(err.Error())
+		}
+
+		return "", ErrUserDatabase.Wrap("Insert", "r.Pool.QueryRow", err)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,203 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/sqldb"
+	"github.com/device-management-toolkit/console/pkg/db"
+)
+
+func setupUserRepo(t *testing.T) (*sqldb.UserRepo, *sql.DB) {
+	t.Helper()
+
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { dbConn.Close() })
+
+	_, err = dbConn.ExecContext(context.Background(), schema)
+	require.NoError(t, err)
+
+	sqlConfig := &db.SQL{
+		Builder:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		Pool:       dbConn,
+		IsEmbedded: true,
+	}
+
+	return sqldb.NewUserRepo(sqlConfig, mocks.NewMockLogger(nil)), dbConn
+}
+
+func testUser(username string) *entity.User {
+	return &entity.User{
+		Username:           username,
+		PasswordHash:       "$2a$10$hash",
+		Role:               "operator",
+		MustChangePassword: true,
+		PasswordChangedAt:  "2026-10-19T00:00:00Z",
+		CreationDate:       "2026-10-19T00:00:00Z",
+		CreatedBy:          "admin",
+		TenantID:           "",
+	}
+}
+
+func TestUserRepo_InsertAndGet(t *testing.T) {
+	t.Parallel()
+
+	repo, _ := setupUserRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.Insert(ctx, testUser("alice"))
+	require.NoError(t, err)
+
+	_, err = repo.Insert(ctx, testUser("bob"))
+	require.NoError(t, err)
+
+	got, err := repo.GetByUsername(ctx, "ALICE")
+	require.NoError(t, err)
+	require.Equal(t, testUser("alice"), got)
+
+	count, err := repo.GetCount(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	users, err := repo.Get(ctx, 1, 1, "")
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, "bob", users[0].Username)
+}
+
+func TestUserRepo_TenantScope(t *testing.T) {
+	t.Parallel()
+
+	repo, _ := setupUserRepo(t)
+	ctx := context.Background()
+
+	other := testUser("carol")
+	other.TenantID = "tenant-b"
+
+	_, err := repo.Insert(ctx, testUser("alice"))
+	require.NoError(t, err)
+
+	_, err = repo.Insert(ctx, other)
+	require.NoError(t, err)
+
+	count, err := repo.GetCount(ctx, "tenant-b")
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	users, err := repo.Get(ctx, 0, 0, "tenant-b")
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, "carol", users[0].Username)
+
+	// Another tenant's user is neither changed nor removed.
+	deleted, err := repo.Delete(ctx, "carol", "")
+	require.NoError(t, err)
+	require.False(t, deleted)
+
+	other.TenantID = ""
+
+	updated, err := repo.Update(ctx, other)
+	require.NoError(t, err)
+	require.False(t, updated)
+}
+
+func TestUserRepo_InsertDuplicate(t *testing.T) {
+	t.Parallel()
+
+	repo, _ := setupUserRepo(t)
+
+	_, err := repo.Insert(context.Background(), testUser("alice"))
+	require.NoError(t, err)
+
+	// Usernames are unique regardless of case.
+	_, err = repo.Insert(context.Background(), testUser("Alice"))
+
+	var notUnique repoerrors.NotUniqueError
+	require.ErrorAs(t, err, &notUnique)
+}
+
+func TestUserRepo_GetByUsernameNotFound(t *testing.T) {
+	t.Parallel()
+
+	repo, _ := setupUserRepo(t)
+
+	got, err := repo.GetByUsername(context.Background(), "nobody")
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestUserRepo_Update(t *testing.T) {
+	t.Parallel()
+
+	repo, _ := setupUserRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.Insert(ctx, testUser("alice"))
+	require.NoError(t, err)
+
+	u := testUser("Alice")
+	u.Role = "admin"
+	u.MustChangePassword = false
+	u.PasswordHash = "$2a$10$other"
+
+	updated, err := repo.Update(ctx, u)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	got, err := repo.GetByUsername(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, "admin", got.Role)
+	require.False(t, got.MustChangePassword)
+	require.Equal(t, "$2a$10$other", got.PasswordHash)
+
+	updated, err = repo.Update(ctx, testUser("nobody"))
+	require.NoError(t, err)
+	require.False(t, updated)
+}
+
+func TestUserRepo_Delete(t *testing.T) {
+	t.Parallel()
+
+	repo, _ := setupUserRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.Insert(ctx, testUser("alice"))
+	require.NoError(t, err)
+
+	deleted, err := repo.Delete(ctx, "ALICE", "")
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	deleted, err = repo.Delete(ctx, "alice", "")
+	require.NoError(t, err)
+	require.False(t, deleted)
+}
+
+func TestUserRepo_QueryError(t *testing.T) {
+	t.Parallel()
+
+	_, dbConn := setupUserRepo(t)
+
+	repo := sqldb.NewUserRepo(&db.SQL{
+		Builder:    squirrel.StatementBuilder.PlaceholderFormat(squirrel.AtP),
+		Pool:       dbConn,
+		IsEmbedded: true,
+	}, mocks.NewMockLogger(nil))
+
+	var dbError repoerrors.DatabaseError
+
+	_, err := repo.GetByUsername(context.Background(), "alice")
+	require.ErrorAs(t, err, &dbError)
+
+	_, err = repo.Delete(context.Background(), "alice", "")
+	require.ErrorAs(t, err, &dbError)
+}
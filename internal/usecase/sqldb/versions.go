@@ -0,0 +1,47 @@
+package sqldb
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/versions"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/db"
+)
+
+// ErrVersionMismatch is returned by an update or delete of an entity that is
+// no longer at the version the request expected.
+var ErrVersionMismatch = repoerrors.PreconditionFailedError{Console: consoleerrors.CreateConsoleError("VersionCheck")}
+
+// ifMatch narrows an update or delete to the versions ctx expects, if any;
+// squirrel ignores the nil predicate otherwise.
+func ifMatch(ctx context.Context) interface{} {
+	expected, ok := versions.IfMatch(ctx)
+	if !ok {
+		return nil
+	}
+
+	return squirrel.Eq{"version": expected}
+}
+
+// checkVersion tells why an update or delete narrowed by ifMatch touched no
+// row: ErrVersionMismatch if a live row of table matches where at another
+// version, nil if there is none.
+func checkVersion(ctx context.Context, database *db.SQL, table, name, where string, args ...interface{}) error {
+	if _, ok := versions.IfMatch(ctx); !ok {
+		return nil
+	}
+
+	exists, err := referenced(ctx, database, table, where, args...)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return ErrVersionMismatch.Wrap(name + " has changed")
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/device-management-toolkit/console/internal/entity"
 	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/versions"
 	"github.com/device-management-toolkit/console/pkg/consoleerrors"
 	"github.com/device-management-toolkit/console/pkg/db"
 	"github.com/device-management-toolkit/console/pkg/logger"
@@ -31,11 +32,15 @@ func NewWirelessRepo(database *db.SQL, log logger.Interface) *WirelessRepo {
 }
 
 // CheckProfileExits -.
-func (r *WirelessRepo) CheckProfileExists(_ context.Context, profileName, tenantID string) (bool, error) {
+func (r *WirelessRepo) CheckProfileExists(ctx context.Context, profileName, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, _, err := r.Builder.
 		Select("COUNT(*) OVER() AS total_count").
 		From("wirelessconfigs").
 		Where("wireless_profile_name = ? AND tenant_id = ?", profileName, tenantID).
+		Where(notDeleted).
 		ToSql()
 	if err != nil {
 		return false, ErrWiFiDatabase.Wrap("CheckProfileExists", "r.Builder", err)
@@ -43,7 +48,7 @@ func (r *WirelessRepo) CheckProfileExists(_ context.Context, profileName, tenant
 
 	var count int
 
-	err = r.Pool.QueryRowContext(context.Background(), sqlQuery, profileName, tenantID).Scan(&count)
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, profileName, tenantID).Scan(&count)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, nil
@@ -55,20 +60,37 @@ func (r *WirelessRepo) CheckProfileExists(_ context.Context, profileName, tenant
 	return true, nil
 }
 
+// wirelessConfigColumns are the columns of the wireless configs' OData
+// fields, qualified for the join in Get.
+var wirelessConfigColumns = odataColumns{
+	"profileName":          "w.wireless_profile_name",
+	"authenticationMethod": "w.authentication_method",
+	"encryptionMethod":     "w.encryption_method",
+	"ssid":                 "w.ssid",
+	"ieee8021xProfileName": "w.ieee8021x_profile_name",
+}
+
 // GetCount -.
-func (r *WirelessRepo) GetCount(_ context.Context, tenantID string) (int, error) {
-	sqlQuery, _, err := r.Builder.
+func (r *WirelessRepo) GetCount(ctx context.Context, tenantID string) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
+	builder, err := odataWhere(ctx, r.Builder.
 		Select("COUNT(*) OVER() AS total_count").
-		From("wirelessconfigs").
-		Where("tenant_id = ?", tenantID).
-		ToSql()
+		From("wirelessconfigs w").
+		Where("w.tenant_id = ? AND w.deleted_at IS NULL", tenantID), wirelessConfigColumns)
+	if err != nil {
+		return 0, ErrWiFiDatabase.Wrap("GetCount", "odataWhere", err)
+	}
+
+	sqlQuery, args, err := builder.ToSql()
 	if err != nil {
 		return 0, ErrWiFiDatabase.Wrap("GetCount", "r.Builder", err)
 	}
 
 	var count int
 
-	err = r.Pool.QueryRowContext(context.Background(), sqlQuery, tenantID).Scan(&count)
+	err = r.Conn(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return 0, nil
@@ -81,7 +103,10 @@ func (r *WirelessRepo) GetCount(_ context.Context, tenantID string) (int, error)
 }
 
 // Get -.
-func (r *WirelessRepo) Get(_ context.Context, top, skip int, tenantID string) ([]entity.WirelessConfig, error) {
+func (r *WirelessRepo) Get(ctx context.Context, top, skip int, tenantID string) ([]entity.WirelessConfig, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	const defaultTop = 100
 
 	if top == 0 {
@@ -98,7 +123,7 @@ func (r *WirelessRepo) Get(_ context.Context, top, skip int, tenantID string) ([
 		limitedSkip = uint64(skip)
 	}
 
-	sqlQuery, _, err := r.Builder.
+	builder, err := odataWhere(ctx, r.Builder.
 		Select(
 			"wireless_profile_name",
 			"authentication_method",
@@ -109,14 +134,24 @@ func (r *WirelessRepo) Get(_ context.Context, top, skip int, tenantID string) ([
 			"link_policy",
 			"w.tenant_id",
 			"ieee8021x_profile_name",
+			"w.version",
 			"auth_protocol",
 			"pxe_timeout",
 			"wired_interface",
 		).
 		From("wirelessconfigs w").
 		LeftJoin("ieee8021xconfigs e ON e.profile_name = w.ieee8021x_profile_name AND e.tenant_id = w.tenant_id AND e.wired_interface = false").
-		Where("w.tenant_id = ?", tenantID).
-		OrderBy("wireless_profile_name").
+		Where("w.tenant_id = ? AND w.deleted_at IS NULL", tenantID), wirelessConfigColumns)
+	if err != nil {
+		return nil, ErrWiFiDatabase.Wrap("Get", "odataWhere", err)
+	}
+
+	builder, err = odataOrderBy(ctx, builder, wirelessConfigColumns, "w.wireless_profile_name")
+	if err != nil {
+		return nil, ErrWiFiDatabase.Wrap("Get", "odataOrderBy", err)
+	}
+
+	sqlQuery, args, err := builder.
 		Limit(limitedTop).
 		Offset(limitedSkip).
 		ToSql()
@@ -124,7 +159,7 @@ func (r *WirelessRepo) Get(_ context.Context, top, skip int, tenantID string) ([
 		return nil, ErrWiFiDatabase.Wrap("Get", "r.Builder", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, tenantID)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, ErrWiFiDatabase.Wrap("Get", "r.Pool.Query", err)
 	}
@@ -140,7 +175,7 @@ func (r *WirelessRepo) Get(_ context.Context, top, skip int, tenantID string) ([
 	for rows.Next() {
 		p := entity.WirelessConfig{}
 
-		err = rows.Scan(&p.ProfileName, &p.AuthenticationMethod, &p.EncryptionMethod, &p.SSID, &p.PSKValue, &p.PSKPassphrase, &p.LinkPolicy, &p.TenantID, &p.IEEE8021xProfileName,
+		err = rows.Scan(&p.ProfileName, &p.AuthenticationMethod, &p.EncryptionMethod, &p.SSID, &p.PSKValue, &p.PSKPassphrase, &p.LinkPolicy, &p.TenantID, &p.IEEE8021xProfileName, &p.Version,
 			&p.AuthenticationProtocol, &p.PXETimeout, &p.WiredInterface)
 		if err != nil {
 			return nil, ErrWiFiDatabase.Wrap("Get", "rows.Scan", err)
@@ -153,7 +188,10 @@ func (r *WirelessRepo) Get(_ context.Context, top, skip int, tenantID string) ([
 }
 
 // GetByName -.
-func (r *WirelessRepo) GetByName(_ context.Context, profileName, tenantID string) (*entity.WirelessConfig, error) {
+func (r *WirelessRepo) GetByName(ctx context.Context, profileName, tenantID string) (*entity.WirelessConfig, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, _, err := r.Builder.
 		Select(
 			"wireless_profile_name",
@@ -165,6 +203,7 @@ func (r *WirelessRepo) GetByName(_ context.Context, profileName, tenantID string
 			"link_policy",
 			"w.tenant_id",
 			"ieee8021x_profile_name",
+			"w.version",
 			"auth_protocol",
 			"pxe_timeout",
 			"wired_interface",
@@ -172,12 +211,13 @@ func (r *WirelessRepo) GetByName(_ context.Context, profileName, tenantID string
 		From("wirelessconfigs w").
 		LeftJoin("ieee8021xconfigs e ON e.profile_name = w.ieee8021x_profile_name AND e.tenant_id = w.tenant_id AND e.wired_interface = false").
 		Where("w.wireless_profile_name = ? and w.tenant_id = ?", profileName, tenantID).
+		Where(byKey(ctx, "w.")).
 		ToSql()
 	if err != nil {
 		return nil, ErrWiFiDatabase.Wrap("GetByName", "r.Builder", err)
 	}
 
-	rows, err := r.Pool.QueryContext(context.Background(), sqlQuery, profileName, tenantID)
+	rows, err := r.Conn(ctx).QueryContext(ctx, sqlQuery, profileName, tenantID)
 	if err != nil {
 		return nil, ErrWiFiDatabase.Wrap("GetByName", "r.Pool.Query", err)
 	}
@@ -193,7 +233,7 @@ func (r *WirelessRepo) GetByName(_ context.Context, profileName, tenantID string
 	for rows.Next() {
 		p := &entity.WirelessConfig{}
 
-		err = rows.Scan(&p.ProfileName, &p.AuthenticationMethod, &p.EncryptionMethod, &p.SSID, &p.PSKValue, &p.PSKPassphrase, &p.LinkPolicy, &p.TenantID, &p.IEEE8021xProfileName,
+		err = rows.Scan(&p.ProfileName, &p.AuthenticationMethod, &p.EncryptionMethod, &p.SSID, &p.PSKValue, &p.PSKPassphrase, &p.LinkPolicy, &p.TenantID, &p.IEEE8021xProfileName, &p.Version,
 			&p.AuthenticationProtocol, &p.PXETimeout, &p.WiredInterface)
 		if err != nil {
 			return p, ErrWiFiDatabase.Wrap("GetByName", "rows.Scan", err)
@@ -209,36 +249,41 @@ func (r *WirelessRepo) GetByName(_ context.Context, profileName, tenantID string
 	return wirelessConfigs[0], nil
 }
 
-// Delete -.
-func (r *WirelessRepo) Delete(_ context.Context, profileName, tenantID string) (bool, error) {
-	sqlQuery, args, err := r.Builder.
-		Delete("wirelessconfigs").
-		Where("wireless_profile_name = ? AND tenant_id = ?", profileName, tenantID).
-		ToSql()
-	if err != nil {
-		return false, ErrWiFiDatabase.Wrap("Delete", "r.Builder", err)
-	}
+// Delete moves the config to the trash, unless a live profile uses it.
+func (r *WirelessRepo) Delete(ctx context.Context, profileName, tenantID string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
 
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, args...)
-	if err != nil {
-		// Check for PostgreSQL and SQLite foreign key violation errors
-		if db.CheckForeignKeyViolation(err) {
-			return false, ErrProfileWiFiConfigsForeignKeyViolation.Wrap(err.Error())
+	var deleted bool
+
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		inUse, err := referenced(ctx, r.SQL,
+			"profiles_wirelessconfigs pw JOIN profiles p ON p.profile_name = pw.profile_name AND p.tenant_id = pw.tenant_id",
+			"pw.wireless_profile_name = ? AND pw.tenant_id = ?", profileName, tenantID)
+		if err != nil {
+			return ErrWiFiDatabase.Wrap("Delete", "referenced", err)
 		}
 
-		return false, ErrWiFiDatabase.Wrap("Delete", "r.Pool.Exec", err)
-	}
+		if inUse {
+			return ErrProfileWiFiConfigsForeignKeyViolation.Wrap("wireless config " + profileName + " is used by a profile")
+		}
 
-	result, err := res.RowsAffected()
-	if err != nil {
-		return false, ErrDomainDatabase.Wrap("Delete", "res.RowsAffected", err)
-	}
+		deleted, err = softDelete(ctx, r.SQL, "wirelessconfigs", "wireless config "+profileName, "wireless_profile_name = ? AND tenant_id = ?", profileName, tenantID)
+		if err != nil {
+			return ErrWiFiDatabase.Wrap("Delete", "softDelete", err)
+		}
 
-	return result > 0, nil
+		return nil
+	})
+
+	return deleted, err
 }
 
 // Update -.
-func (r *WirelessRepo) Update(_ context.Context, p *entity.WirelessConfig) (bool, error) {
+func (r *WirelessRepo) Update(ctx context.Context, p *entity.WirelessConfig) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	sqlQuery, args, err := r.Builder.
 		Update("wirelessconfigs").
 		Set("authentication_method", p.AuthenticationMethod).
@@ -248,13 +293,16 @@ func (r *WirelessRepo) Update(_ context.Context, p *entity.WirelessConfig) (bool
 		Set("psk_passphrase", p.PSKPassphrase).
 		Set("link_policy", p.LinkPolicy).
 		Set("ieee8021x_profile_name", p.IEEE8021xProfileName).
+		Set("version", versions.New()).
 		Where("wireless_profile_name = ? AND tenant_id = ?", p.ProfileName, p.TenantID).
+		Where(notDeleted).
+		Where(ifMatch(ctx)).
 		ToSql()
 	if err != nil {
 		return false, ErrWiFiDatabase.Wrap("Update", "r.Builder", err)
 	}
 
-	res, err := r.Pool.ExecContext(context.Background(), sqlQuery, args...)
+	res, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return false, ErrWiFiDatabase.Wrap("Update", "r.Pool.Exec", err)
 	}
@@ -264,11 +312,20 @@ func (r *WirelessRepo) Update(_ context.Context, p *entity.WirelessConfig) (bool
 		return false, ErrDomainDatabase.Wrap("Update", "res.RowsAffected", err)
 	}
 
+	if result == 0 {
+		if err := checkVersion(ctx, r.SQL, "wirelessconfigs", "wireless config "+p.ProfileName, "wireless_profile_name = ? AND tenant_id = ?", p.ProfileName, p.TenantID); err != nil {
+			return false, err
+		}
+	}
+
 	return result > 0, nil
 }
 
 // Insert -.
-func (r *WirelessRepo) Insert(_ context.Context, p *entity.WirelessConfig) (string, error) {
+func (r *WirelessRepo) Insert(ctx context.Context, p *entity.WirelessConfig) (string, error) {
+	ctx, cancel := r.WithTimeout(ctx)
+	defer cancel()
+
 	date := time.Now().Format("2006-01-02 15:04:05")
 
 	ieeeProfileName := p.IEEE8021xProfileName
@@ -279,30 +336,29 @@ func (r *WirelessRepo) Insert(_ context.Context, p *entity.WirelessConfig) (stri
 		}
 	}
 
+	version := versions.New()
+
 	insertBuilder := r.Builder.
 		Insert("wirelessconfigs").
-		Columns("wireless_profile_name", "authentication_method", "encryption_method", "ssid", "psk_value", "psk_passphrase", "link_policy", "creation_date", "tenant_id", "ieee8021x_profile_name").
-		Values(p.ProfileName, p.AuthenticationMethod, p.EncryptionMethod, p.SSID, p.PSKValue, p.PSKPassphrase, p.LinkPolicy, date, p.TenantID, ieeeProfileName)
-
-	if !r.IsEmbedded {
-		insertBuilder = insertBuilder.Suffix("RETURNING xmin::text")
-	}
+		Columns("wireless_profile_name", "authentication_method", "encryption_method", "ssid", "psk_value", "psk_passphrase", "link_policy", "creation_date", "tenant_id", "ieee8021x_profile_name", "version").
+		Values(p.ProfileName, p.AuthenticationMethod, p.EncryptionMethod, p.SSID, p.PSKValue, p.PSKPassphrase, p.LinkPolicy, date, p.TenantID, ieeeProfileName, version)
 
 	sqlQuery, args, err := insertBuilder.ToSql()
 	if err != nil {
 		return "", ErrWiFiDatabase.Wrap("Insert", "r.Builder", err)
 	}
 
-	version := ""
+	err = r.InTx(ctx, func(ctx context.Context) error {
+		if err := purgeTrashed(ctx, r.SQL, entity.TrashWirelessConfigs, "wireless_profile_name = ? AND tenant_id = ?", p.ProfileName, p.TenantID); err != nil {
+			return err
+		}
 
-	if r.IsEmbedded {
-		_, err = r.Pool.ExecContext(context.Background(), sqlQuery, args...)
-	} else {
-		err = r.Pool.QueryRowContext(context.Background(), sqlQuery, args...).Scan(&version)
-	}
+		_, err := r.Conn(ctx).ExecContext(ctx, sqlQuery, args...)
 
+		return err
+	})
 	if err != nil {
-		if db.CheckNotUnique(err) {
+		if db.CheckNotUnique(err) || errors.Is(err, errTrashedInUse) {
 			return "", ErrWiFiNotUnique.Wrap(err.Error())
 		}
 
@@ -310,7 +366,7 @@ func (r *WirelessRepo) Insert(_ context.Context, p *entity.WirelessConfig) (stri
 			return "", ErrWiFiIEEEForeignKeyViolation.Wrap(err.Error())
 		}
 
-		return "", ErrWiFiDatabase.Wrap("Insert", "r.Pool.QueryRow", err)
+		return "", ErrWiFiDatabase.Wrap("Insert", "r.InTx", err)
 	}
 
 	return version, nil
@@ -494,6 +494,8 @@ func TestWirelessRepo_Update(t *testing.T) {
 						link_policy TEXT,
 						ieee8021x_profile_name TEXT,
 						tenant_id TEXT NOT NULL,
+						deleted_at TEXT,
+						version TEXT NOT NULL DEFAULT '1',
 						PRIMARY KEY (wireless_profile_name, tenant_id)
 					);
 				`)
@@ -531,6 +533,8 @@ func TestWirelessRepo_Update(t *testing.T) {
 						link_policy TEXT,
 						ieee8021x_profile_name TEXT,
 						tenant_id TEXT NOT NULL,
+						deleted_at TEXT,
+						version TEXT NOT NULL DEFAULT '1',
 						PRIMARY KEY (wireless_profile_name, tenant_id)
 					);
 				`)
@@ -619,7 +623,9 @@ func TestWirelessRepo_Insert(t *testing.T) {
 						link_policy TEXT,
 						creation_date TEXT,
 						tenant_id TEXT NOT NULL,
-						ieee8021x_profile_name TEXT
+						ieee8021x_profile_name TEXT,
+						deleted_at TEXT,
+						version TEXT NOT NULL DEFAULT '1'
 					);
 				`)
 				require.NoError(t, err)
@@ -647,7 +653,9 @@ func TestWirelessRepo_Insert(t *testing.T) {
 						link_policy TEXT,
 						creation_date TEXT,
 						tenant_id TEXT NOT NULL,
-						ieee8021x_profile_name TEXT
+						ieee8021x_profile_name TEXT,
+						deleted_at TEXT,
+						version TEXT NOT NULL DEFAULT '1'
 					);
 				`)
 				require.NoError(t, err)
@@ -0,0 +1,40 @@
+package tags
+
+import (
+	"context"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+type (
+	// Repository reads and rewrites the tags of the devices of a tenant.
+	// Update changes the comma-joined tags of each device and the index tag
+	// queries use together, and gives changed devices a new version.
+	Repository interface {
+		GetCounts(ctx context.Context, tenantID string) ([]entity.Tag, error)
+		// GetGUIDs includes devices in the trash, so that they carry a
+		// renamed tag when restored.
+		GetGUIDs(ctx context.Context, tags []string, tenantID string) ([]string, error)
+		GetTags(ctx context.Context, guids []string, tenantID string) ([]entity.Device, error)
+		Update(ctx context.Context, guids, add, remove []string, tenantID string) (int, error)
+	}
+	// Selector resolves a device selection to GUIDs; the device groups use
+	// case is one.
+	Selector interface {
+		Resolve(ctx context.Context, selection dto.DeviceSelection, tenantID string) ([]string, error)
+	}
+	// Referrer lists its entities that name any of tags in a tag
+	// expression, which a rename, merge or delete would silently change;
+	// the access policies and device groups use cases are ones.
+	Referrer interface {
+		TagReferences(ctx context.Context, tags []string, tenantID string) ([]string, error)
+	}
+	Feature interface {
+		Get(ctx context.Context, tenantID string) ([]dto.Tag, error)
+		Rename(ctx context.Context, tag, name, tenantID string) (int, error)
+		Merge(ctx context.Context, merge dto.TagMerge, tenantID string) (int, error)
+		Delete(ctx context.Context, tag, tenantID string) error
+		Update(ctx context.Context, update dto.TagUpdate, tenantID string) (int, error)
+	}
+)
@@ -0,0 +1,285 @@
+package tags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/taglist"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// UseCase -.
+type UseCase struct {
+	repo      Repository
+	selector  Selector
+	referrers []Referrer
+	log       logger.Interface
+}
+
+var (
+	ErrTagsUseCase = consoleerrors.CreateConsoleError("TagsUseCase")
+	ErrDatabase    = repoerrors.DatabaseError{Console: ErrTagsUseCase}
+	ErrNotFound    = repoerrors.NotFoundError{Console: ErrTagsUseCase}
+	ErrNotValid    = dto.NotValidError{Console: ErrTagsUseCase}
+
+	errEmptyTag    = errors.New("tag is empty")
+	errCommaInTag  = errors.New("tag cannot contain a comma")
+	errTagInUse    = errors.New("tag is in use already; merge the tags instead")
+	errReferenced  = errors.New("tag is named by access policies or group rules; change them first")
+	errNothingToDo = errors.New("no tags to add or remove")
+	errOutOfScope  = errors.New("the devices' tags must keep them within your device scope")
+)
+
+// New -. Rename, Merge and Delete refuse tags that any of referrers name, as
+// Update does the tags it removes.
+func New(r Repository, s Selector, referrers []Referrer, log logger.Interface) *UseCase {
+	return &UseCase{
+		repo:      r,
+		selector:  s,
+		referrers: referrers,
+		log:       log,
+	}
+}
+
+// Get lists the tags in use, by name, with how many devices carry each.
+func (uc *UseCase) Get(ctx context.Context, tenantID string) ([]dto.Tag, error) {
+	data, err := uc.repo.GetCounts(ctx, tenantID)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Get", "uc.repo.GetCounts", err)
+	}
+
+	d1 := make([]dto.Tag, len(data))
+
+	for i := range data {
+		d1[i] = dto.Tag{Name: data[i].Name, Devices: data[i].Devices}
+	}
+
+	return d1, nil
+}
+
+// Rename renames tag to name on every device carrying it, and returns how
+// many devices changed.
+func (uc *UseCase) Rename(ctx context.Context, tag, name, tenantID string) (int, error) {
+	name, err := validateTag(name)
+	if err != nil {
+		return 0, ErrNotValid.Wrap("Rename", "validateTag", err)
+	}
+
+	if err := uc.unreferenced(ctx, "Rename", []string{tag}, tenantID); err != nil {
+		return 0, err
+	}
+
+	guids, err := uc.tagged(ctx, "Rename", []string{tag}, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	inUse, err := uc.repo.GetGUIDs(ctx, []string{name}, tenantID)
+	if err != nil {
+		return 0, ErrDatabase.Wrap("Rename", "uc.repo.GetGUIDs", err)
+	}
+
+	if len(inUse) > 0 {
+		return 0, ErrNotValid.Wrap("Rename", "name", errTagInUse)
+	}
+
+	return uc.update(ctx, "Rename", guids, []string{name}, []string{tag}, tenantID)
+}
+
+// Merge replaces each of the tags of merge with merge.Into on every device
+// carrying it, and returns how many devices changed.
+func (uc *UseCase) Merge(ctx context.Context, merge dto.TagMerge, tenantID string) (int, error) {
+	into, err := validateTag(merge.Into)
+	if err != nil {
+		return 0, ErrNotValid.Wrap("Merge", "validateTag", err)
+	}
+
+	sources := make([]string, 0, len(merge.Tags))
+
+	for _, tag := range taglist.Split(strings.Join(merge.Tags, ",")) {
+		if tag != into {
+			sources = append(sources, tag)
+		}
+	}
+
+	if len(sources) == 0 {
+		return 0, ErrNotValid.Wrap("Merge", "tags", errNothingToDo)
+	}
+
+	if err := uc.unreferenced(ctx, "Merge", sources, tenantID); err != nil {
+		return 0, err
+	}
+
+	guids, err := uc.tagged(ctx, "Merge", sources, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	return uc.update(ctx, "Merge", guids, []string{into}, sources, tenantID)
+}
+
+// Delete removes tag from every device carrying it.
+func (uc *UseCase) Delete(ctx context.Context, tag, tenantID string) error {
+	if err := uc.unreferenced(ctx, "Delete", []string{tag}, tenantID); err != nil {
+		return err
+	}
+
+	guids, err := uc.tagged(ctx, "Delete", []string{tag}, tenantID)
+	if err != nil {
+		return err
+	}
+
+	_, err = uc.update(ctx, "Delete", guids, nil, []string{tag}, tenantID)
+
+	return err
+}
+
+// Update adds and removes tags on the selected devices, and returns how many
+// changed. Unknown GUIDs, and those outside the caller's device scope, are
+// skipped.
+func (uc *UseCase) Update(ctx context.Context, update dto.TagUpdate, tenantID string) (int, error) {
+	add, err := validateTags(update.Add)
+	if err != nil {
+		return 0, ErrNotValid.Wrap("Update", "validateTags", err)
+	}
+
+	remove, err := validateTags(update.Remove)
+	if err != nil {
+		return 0, ErrNotValid.Wrap("Update", "validateTags", err)
+	}
+
+	if len(add) == 0 && len(remove) == 0 {
+		return 0, ErrNotValid.Wrap("Update", "tags", errNothingToDo)
+	}
+
+	if len(remove) > 0 {
+		if err := uc.unreferenced(ctx, "Update", remove, tenantID); err != nil {
+			return 0, err
+		}
+	}
+
+	guids, err := uc.selector.Resolve(ctx, update.Devices, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	guids, err = uc.inScope(ctx, guids, add, remove, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	return uc.update(ctx, "Update", guids, add, remove, tenantID)
+}
+
+// inScope drops the devices of guids outside the device scope of ctx, as if
+// they did not exist, and refuses a change that would move a device out of
+// it.
+func (uc *UseCase) inScope(ctx context.Context, guids, add, remove []string, tenantID string) ([]string, error) {
+	scope := devicescope.FromContext(ctx)
+	if scope == nil {
+		return guids, nil
+	}
+
+	devices, err := uc.repo.GetTags(ctx, guids, tenantID)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Update", "uc.repo.GetTags", err)
+	}
+
+	visible := make([]string, 0, len(devices))
+
+	for i := range devices {
+		tags := taglist.Split(devices[i].Tags)
+		if !scope.Allows(tags) {
+			continue
+		}
+
+		if !scope.Allows(taglist.Apply(tags, add, remove)) {
+			return nil, ErrNotValid.Wrap("Update", "devicescope.Allows", errOutOfScope)
+		}
+
+		visible = append(visible, devices[i].GUID)
+	}
+
+	return visible, nil
+}
+
+// unreferenced refuses tags that an access policy or a group rule names:
+// changing them on the devices would change what those match.
+func (uc *UseCase) unreferenced(ctx context.Context, op string, tags []string, tenantID string) error {
+	var references []string
+
+	for _, r := range uc.referrers {
+		found, err := r.TagReferences(ctx, tags, tenantID)
+		if err != nil {
+			return ErrDatabase.Wrap(op, "TagReferences", err)
+		}
+
+		references = append(references, found...)
+	}
+
+	if len(references) > 0 {
+		return ErrNotValid.Wrap(op, "tags", fmt.Errorf("%w: %s", errReferenced, strings.Join(references, ", ")))
+	}
+
+	return nil
+}
+
+// tagged returns the GUIDs of the devices carrying any of tags, or
+// ErrNotFound if there are none.
+func (uc *UseCase) tagged(ctx context.Context, op string, tags []string, tenantID string) ([]string, error) {
+	guids, err := uc.repo.GetGUIDs(ctx, tags, tenantID)
+	if err != nil {
+		return nil, ErrDatabase.Wrap(op, "uc.repo.GetGUIDs", err)
+	}
+
+	if len(guids) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return guids, nil
+}
+
+func (uc *UseCase) update(ctx context.Context, op string, guids, add, remove []string, tenantID string) (int, error) {
+	changed, err := uc.repo.Update(ctx, guids, add, remove, tenantID)
+	if err != nil {
+		return 0, ErrDatabase.Wrap(op, "uc.repo.Update", err)
+	}
+
+	return changed, nil
+}
+
+// validateTag returns tag trimmed, if it can be stored in a comma-joined list.
+func validateTag(tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+
+	switch {
+	case tag == "":
+		return "", errEmptyTag
+	case strings.Contains(tag, ","):
+		return "", errCommaInTag
+	}
+
+	return tag, nil
+}
+
+// validateTags validates each of tags, and drops repeats.
+func validateTags(tags []string) ([]string, error) {
+	valid := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag, err := validateTag(tag)
+		if err != nil {
+			return nil, err
+		}
+
+		valid = append(valid, tag)
+	}
+
+	return taglist.Split(taglist.Join(valid)), nil
+}
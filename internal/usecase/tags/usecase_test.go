@@ -0,0 +1,177 @@
+package tags_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/devicescope"
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/internal/usecase/tags"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func tagsTest(t *testing.T) (*tags.UseCase, *mocks.MockTagsRepository, *mocks.MockTagsSelector, *mocks.MockTagsReferrer) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockTagsRepository(ctrl)
+	selector := mocks.NewMockTagsSelector(ctrl)
+	referrer := mocks.NewMockTagsReferrer(ctrl)
+
+	return tags.New(repo, selector, []tags.Referrer{referrer}, logger.New("error")), repo, selector, referrer
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _, _ := tagsTest(t)
+	ctx := context.Background()
+
+	repo.EXPECT().GetCounts(ctx, "tenant").Return([]entity.Tag{{Name: "lab", Devices: 3}}, nil)
+
+	got, err := useCase.Get(ctx, "tenant")
+	require.NoError(t, err)
+	require.Equal(t, []dto.Tag{{Name: "lab", Devices: 3}}, got)
+}
+
+func TestRename(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _, referrer := tagsTest(t)
+	ctx := context.Background()
+
+	referrer.EXPECT().TagReferences(ctx, []string{"lab"}, "").Return(nil, nil).Times(2)
+	referrer.EXPECT().TagReferences(ctx, []string{"missing"}, "").Return(nil, nil)
+	repo.EXPECT().GetGUIDs(ctx, []string{"lab"}, "").Return([]string{"a", "b"}, nil)
+	repo.EXPECT().GetGUIDs(ctx, []string{"bench"}, "").Return([]string{}, nil)
+	repo.EXPECT().Update(ctx, []string{"a", "b"}, []string{"bench"}, []string{"lab"}, "").Return(2, nil)
+
+	changed, err := useCase.Rename(ctx, "lab", " bench ", "")
+	require.NoError(t, err)
+	require.Equal(t, 2, changed)
+
+	// A name in use asks for a merge instead.
+	repo.EXPECT().GetGUIDs(ctx, []string{"lab"}, "").Return([]string{"a"}, nil)
+	repo.EXPECT().GetGUIDs(ctx, []string{"site-a"}, "").Return([]string{"c"}, nil)
+
+	_, err = useCase.Rename(ctx, "lab", "site-a", "")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+
+	repo.EXPECT().GetGUIDs(ctx, []string{"missing"}, "").Return([]string{}, nil)
+
+	_, err = useCase.Rename(ctx, "missing", "bench", "")
+	require.ErrorAs(t, err, &repoerrors.NotFoundError{})
+
+	_, err = useCase.Rename(ctx, "lab", "a,b", "")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _, referrer := tagsTest(t)
+	ctx := context.Background()
+
+	referrer.EXPECT().TagReferences(ctx, []string{"site-a-old"}, "").Return(nil, nil)
+	repo.EXPECT().GetGUIDs(ctx, []string{"site-a-old"}, "").Return([]string{"a"}, nil)
+	repo.EXPECT().Update(ctx, []string{"a"}, []string{"site-a"}, []string{"site-a-old"}, "").Return(1, nil)
+
+	changed, err := useCase.Merge(ctx, dto.TagMerge{Tags: []string{"site-a-old", "site-a "}, Into: "site-a"}, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, changed)
+
+	_, err = useCase.Merge(ctx, dto.TagMerge{Tags: []string{"site-a"}, Into: "site-a"}, "")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _, referrer := tagsTest(t)
+	ctx := context.Background()
+
+	referrer.EXPECT().TagReferences(ctx, []string{"lab"}, "").Return(nil, nil).Times(2)
+	repo.EXPECT().GetGUIDs(ctx, []string{"lab"}, "").Return([]string{"a"}, nil)
+	repo.EXPECT().Update(ctx, []string{"a"}, nil, []string{"lab"}, "").Return(1, nil)
+
+	require.NoError(t, useCase.Delete(ctx, "lab", ""))
+
+	repo.EXPECT().GetGUIDs(ctx, []string{"lab"}, "").Return([]string{}, nil)
+
+	require.ErrorAs(t, useCase.Delete(ctx, "lab", ""), &repoerrors.NotFoundError{})
+}
+
+func TestRefusesReferencedTags(t *testing.T) {
+	t.Parallel()
+
+	useCase, _, _, referrer := tagsTest(t)
+	ctx := context.Background()
+
+	referrer.EXPECT().TagReferences(ctx, []string{"site-a"}, "").Return([]string{"access policy site-a", "group site-a"}, nil).Times(4)
+
+	_, err := useCase.Rename(ctx, "site-a", "site-b", "")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+	require.ErrorContains(t, err, "access policy site-a, group site-a")
+
+	_, err = useCase.Merge(ctx, dto.TagMerge{Tags: []string{"site-a"}, Into: "site-b"}, "")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+
+	require.ErrorAs(t, useCase.Delete(ctx, "site-a", ""), &dto.NotValidError{})
+
+	_, err = useCase.Update(ctx, dto.TagUpdate{Devices: dto.DeviceSelection{GUIDs: []string{"a"}}, Remove: []string{"site-a"}}, "")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, selector, referrer := tagsTest(t)
+	ctx := context.Background()
+	selection := dto.DeviceSelection{Groups: []string{"lab"}}
+
+	referrer.EXPECT().TagReferences(ctx, []string{"staging"}, "").Return(nil, nil)
+	selector.EXPECT().Resolve(ctx, selection, "").Return([]string{"a", "b"}, nil)
+	repo.EXPECT().Update(ctx, []string{"a", "b"}, []string{"bench"}, []string{"staging"}, "").Return(1, nil)
+
+	changed, err := useCase.Update(ctx, dto.TagUpdate{Devices: selection, Add: []string{"bench", "bench"}, Remove: []string{"staging"}}, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, changed)
+
+	_, err = useCase.Update(ctx, dto.TagUpdate{Devices: selection}, "")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+
+	_, err = useCase.Update(ctx, dto.TagUpdate{Devices: selection, Add: []string{" "}}, "")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+}
+
+func TestUpdateWithinScope(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, selector, referrer := tagsTest(t)
+	ctx := devicescope.WithScope(context.Background(), &devicescope.Scope{
+		Expressions: []devicescope.Expression{{Tags: []string{"site-a"}}},
+	})
+	selection := dto.DeviceSelection{GUIDs: []string{"a", "b"}}
+	devices := []entity.Device{{GUID: "a", Tags: "site-a"}, {GUID: "b", Tags: "site-b"}}
+
+	// Devices outside the scope are skipped.
+	selector.EXPECT().Resolve(ctx, selection, "").Return([]string{"a", "b"}, nil).Times(2)
+	repo.EXPECT().GetTags(ctx, []string{"a", "b"}, "").Return(devices, nil).Times(2)
+	repo.EXPECT().Update(ctx, []string{"a"}, []string{"bench"}, []string{}, "").Return(1, nil)
+
+	changed, err := useCase.Update(ctx, dto.TagUpdate{Devices: selection, Add: []string{"bench"}}, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, changed)
+
+	// A change that would move a device out of the scope is refused.
+	referrer.EXPECT().TagReferences(ctx, []string{"site-a"}, "").Return(nil, nil)
+
+	_, err = useCase.Update(ctx, dto.TagUpdate{Devices: selection, Remove: []string{"site-a"}}, "")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+}
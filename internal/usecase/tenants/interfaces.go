@@ -0,0 +1,12 @@
+// Package tenants names the tenants of a deployment. There is no table of
+// tenants: one exists while it holds data.
+package tenants
+
+import "context"
+
+// Repository lists the tenants that own entities, in the trash or not, in
+// order. Commands that work through every tenant, such as key rotation and
+// database migration, start from it.
+type Repository interface {
+	Get(ctx context.Context) ([]string, error)
+}
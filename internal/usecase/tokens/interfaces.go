@@ -0,0 +1,36 @@
+package tokens
+
+import (
+	"context"
+	"time"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+type (
+	Repository interface {
+		// Revoke lists a token ID. Listing one again is not an error.
+		Revoke(ctx context.Context, t *entity.RevokedToken) error
+		IsRevoked(ctx context.Context, ids []string) (bool, error)
+		// GetRevoked pages through the revocation list by ID.
+		GetRevoked(ctx context.Context, top, skip int) ([]entity.RevokedToken, error)
+		InsertRefreshToken(ctx context.Context, t *entity.RefreshToken) error
+		GetRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+		// MarkRefreshTokenUsed reports false when the token was already used,
+		// so only one of two concurrent refreshes succeeds.
+		MarkRefreshTokenUsed(ctx context.Context, tokenHash string) (bool, error)
+		GetRefreshTokensByUser(ctx context.Context, username, tenantID string) ([]entity.RefreshToken, error)
+		DeleteSession(ctx context.Context, sessionID string) error
+		// Prune drops revocation entries and refresh tokens expired by now.
+		Prune(ctx context.Context, now string) error
+	}
+	Feature interface {
+		Start(ctx context.Context, username, tenantID string) (*dto.TokenSession, error)
+		Refresh(ctx context.Context, refreshToken string) (*dto.TokenSession, error)
+		End(ctx context.Context, sessionID, username string) error
+		EndAll(ctx context.Context, username, tenantID string) (int, error)
+		Revoke(ctx context.Context, jti, subject string, expiresAt time.Time) error
+		IsRevoked(ctx context.Context, ids ...string) (bool, error)
+	}
+)
@@ -0,0 +1,286 @@
+package tokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+const (
+	refreshTokenBytes = 32
+
+	// pruneInterval limits clean-up of expired entries to one pass a minute.
+	pruneInterval = time.Minute
+)
+
+// UseCase -.
+type UseCase struct {
+	repo       Repository
+	log        logger.Interface
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+
+	pruneMutex sync.Mutex
+	lastPrune  time.Time
+}
+
+var (
+	ErrTokensUseCase = consoleerrors.CreateConsoleError("TokensUseCase")
+	ErrDatabase      = repoerrors.DatabaseError{Console: ErrTokensUseCase}
+
+	// ErrInvalidRefreshToken is returned for an unknown, expired or already
+	// used refresh token.
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+)
+
+// New takes the lifetime of access tokens and of refresh tokens. A zero
+// refreshTTL turns refresh tokens off; sessions are still recorded, so they
+// can be ended.
+func New(r Repository, log logger.Interface, accessTTL, refreshTTL time.Duration) *UseCase {
+	return &UseCase{
+		repo:       r,
+		log:        log,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// Start records a new login session and issues its first refresh token.
+func (uc *UseCase) Start(ctx context.Context, username, tenantID string) (*dto.TokenSession, error) {
+	uc.prune(ctx)
+
+	s := &dto.TokenSession{
+		ID:       uuid.NewString(),
+		Username: username,
+		TenantID: tenantID,
+	}
+
+	if err := uc.issueRefreshToken(ctx, s); err != nil {
+		return nil, err
+	}
+
+	// Not disclosed, so it cannot be used, but it still records the session.
+	if uc.refreshTTL == 0 {
+		s.RefreshToken = ""
+	}
+
+	return s, nil
+}
+
+// Refresh exchanges a refresh token for the session's next one. A token can
+// be exchanged once: presenting a used token means it was copied, so the
+// whole session is ended.
+func (uc *UseCase) Refresh(ctx context.Context, refreshToken string) (*dto.TokenSession, error) {
+	if uc.refreshTTL == 0 || refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	tokenHash := hash(refreshToken)
+
+	t, err := uc.repo.GetRefreshToken(ctx, tokenHash)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Refresh", "uc.repo.GetRefreshToken", err)
+	}
+
+	if t == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if expiresAt, err := time.Parse(time.RFC3339, t.ExpiresAt); err != nil || !time.Now().Before(expiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	marked := false
+	if !t.Used {
+		marked, err = uc.repo.MarkRefreshTokenUsed(ctx, tokenHash)
+		if err != nil {
+			return nil, ErrDatabase.Wrap("Refresh", "uc.repo.MarkRefreshTokenUsed", err)
+		}
+	}
+
+	if !marked {
+		uc.log.Warn("tokens - Refresh - used refresh token presented again, ending session " + t.SessionID + " of " + t.Username)
+
+		if err := uc.End(ctx, t.SessionID, t.Username); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrInvalidRefreshToken
+	}
+
+	s := &dto.TokenSession{
+		ID:       t.SessionID,
+		Username: t.Username,
+		TenantID: t.TenantID,
+	}
+
+	if err := uc.issueRefreshToken(ctx, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// End revokes every token of a session, including access tokens already
+// issued, and forgets its refresh tokens.
+func (uc *UseCase) End(ctx context.Context, sessionID, username string) error {
+	// No token of the session outlives the longer of the two lifetimes.
+	expiresAt := time.Now().Add(max(uc.accessTTL, uc.refreshTTL))
+
+	if err := uc.Revoke(ctx, sessionID, username, expiresAt); err != nil {
+		return err
+	}
+
+	if err := uc.repo.DeleteSession(ctx, sessionID); err != nil {
+		return ErrDatabase.Wrap("End", "uc.repo.DeleteSession", err)
+	}
+
+	return nil
+}
+
+// EndAll ends every session of a user and returns how many there were.
+func (uc *UseCase) EndAll(ctx context.Context, username, tenantID string) (int, error) {
+	refreshTokens, err := uc.repo.GetRefreshTokensByUser(ctx, username, tenantID)
+	if err != nil {
+		return 0, ErrDatabase.Wrap("EndAll", "uc.repo.GetRefreshTokensByUser", err)
+	}
+
+	ended := map[string]bool{}
+
+	for i := range refreshTokens {
+		sessionID := refreshTokens[i].SessionID
+		if ended[sessionID] {
+			continue
+		}
+
+		if err := uc.End(ctx, sessionID, username); err != nil {
+			return len(ended), err
+		}
+
+		ended[sessionID] = true
+	}
+
+	return len(ended), nil
+}
+
+// Revoke lists a token ID until expiresAt, when the token expires anyway.
+func (uc *UseCase) Revoke(ctx context.Context, jti, subject string, expiresAt time.Time) error {
+	t := &entity.RevokedToken{
+		ID:        jti,
+		Subject:   subject,
+		RevokedAt: time.Now().UTC().Format(time.RFC3339),
+		ExpiresAt: expiresAt.UTC().Format(time.RFC3339),
+	}
+
+	if err := uc.repo.Revoke(ctx, t); err != nil {
+		return ErrDatabase.Wrap("Revoke", "uc.repo.Revoke", err)
+	}
+
+	return nil
+}
+
+// IsRevoked reports whether any of ids, a token's jti and sid, is listed.
+// Empty IDs are ignored.
+func (uc *UseCase) IsRevoked(ctx context.Context, ids ...string) (bool, error) {
+	listed := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		if id != "" {
+			listed = append(listed, id)
+		}
+	}
+
+	if len(listed) == 0 {
+		return false, nil
+	}
+
+	revoked, err := uc.repo.IsRevoked(ctx, listed)
+	if err != nil {
+		return false, ErrDatabase.Wrap("IsRevoked", "uc.repo.IsRevoked", err)
+	}
+
+	return revoked, nil
+}
+
+// issueRefreshToken sets s.RefreshToken to a new token for the session.
+func (uc *UseCase) issueRefreshToken(ctx context.Context, s *dto.TokenSession) error {
+	token, err := generate()
+	if err != nil {
+		return fmt.Errorf("tokens - issueRefreshToken - generate: %w", err)
+	}
+
+	now := time.Now().UTC()
+
+	t := &entity.RefreshToken{
+		TokenHash: hash(token),
+		SessionID: s.ID,
+		Username:  s.Username,
+		// Kept as long as any token of the session may be in use, so
+		// EndAll still finds the session when refresh tokens are off.
+		ExpiresAt:    now.Add(max(uc.accessTTL, uc.refreshTTL)).Format(time.RFC3339),
+		CreationDate: now.Format(time.RFC3339),
+		TenantID:     s.TenantID,
+	}
+
+	if err := uc.repo.InsertRefreshToken(ctx, t); err != nil {
+		return ErrDatabase.Wrap("issueRefreshToken", "uc.repo.InsertRefreshToken", err)
+	}
+
+	s.RefreshToken = token
+
+	return nil
+}
+
+// prune drops expired entries at most once per pruneInterval. Failure only
+// leaves them for the next pass.
+func (uc *UseCase) prune(ctx context.Context) {
+	uc.pruneMutex.Lock()
+
+	now := time.Now()
+	due := now.Sub(uc.lastPrune) >= pruneInterval
+
+	if due {
+		uc.lastPrune = now
+	}
+
+	uc.pruneMutex.Unlock()
+
+	if !due {
+		return
+	}
+
+	if err := uc.repo.Prune(ctx, now.UTC().Format(time.RFC3339)); err != nil {
+		uc.log.Warn("tokens - prune - failed to drop expired tokens: " + err.Error())
+	}
+}
+
+func generate() (string, error) {
+	raw := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// hash digests a refresh token. Tokens are 256 random bits, so a fast hash
+// is enough.
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
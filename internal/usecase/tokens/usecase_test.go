@@ -0,0 +1,224 @@
+package tokens_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/tokens"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+func tokensTest(t *testing.T, refreshTTL time.Duration) (*tokens.UseCase, *mocks.MockTokensRepository) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	repo := mocks.NewMockTokensRepository(mockCtl)
+
+	return tokens.New(repo, logger.New("error"), time.Hour, refreshTTL), repo
+}
+
+func sha256Hex(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func future() string {
+	return time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+}
+
+func TestStart(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := tokensTest(t, 24*time.Hour)
+
+	var stored *entity.RefreshToken
+
+	repo.EXPECT().Prune(context.Background(), gomock.Any()).Return(nil)
+	repo.EXPECT().
+		InsertRefreshToken(context.Background(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, t *entity.RefreshToken) error {
+			stored = t
+
+			return nil
+		})
+
+	session, err := useCase.Start(context.Background(), "jdoe", "tenant-a")
+	require.NoError(t, err)
+
+	require.NotEmpty(t, session.ID)
+	require.NotEmpty(t, session.RefreshToken)
+	require.Equal(t, session.ID, stored.SessionID)
+	require.Equal(t, "tenant-a", stored.TenantID)
+	// Only a digest of the token is stored.
+	require.Equal(t, sha256Hex(session.RefreshToken), stored.TokenHash)
+}
+
+func TestStart_RefreshDisabled(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := tokensTest(t, 0)
+
+	repo.EXPECT().Prune(context.Background(), gomock.Any()).Return(nil)
+	repo.EXPECT().InsertRefreshToken(context.Background(), gomock.Any()).Return(nil)
+
+	session, err := useCase.Start(context.Background(), "jdoe", "")
+	require.NoError(t, err)
+	require.NotEmpty(t, session.ID)
+	require.Empty(t, session.RefreshToken)
+
+	_, err = useCase.Refresh(context.Background(), "anything")
+	require.ErrorIs(t, err, tokens.ErrInvalidRefreshToken)
+}
+
+func TestRefresh(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		stored  *entity.RefreshToken
+		mock    func(*mocks.MockTokensRepository)
+		wantErr error
+	}{
+		{
+			name:    "unknown token",
+			stored:  nil,
+			mock:    func(*mocks.MockTokensRepository) {},
+			wantErr: tokens.ErrInvalidRefreshToken,
+		},
+		{
+			name: "expired token",
+			stored: &entity.RefreshToken{
+				SessionID: "session-1",
+				Username:  "jdoe",
+				ExpiresAt: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+			},
+			mock:    func(*mocks.MockTokensRepository) {},
+			wantErr: tokens.ErrInvalidRefreshToken,
+		},
+		{
+			name: "rotates the token",
+			stored: &entity.RefreshToken{
+				SessionID: "session-1",
+				Username:  "jdoe",
+				TenantID:  "tenant-a",
+				ExpiresAt: future(),
+			},
+			mock: func(repo *mocks.MockTokensRepository) {
+				repo.EXPECT().MarkRefreshTokenUsed(context.Background(), sha256Hex("refresh-1")).Return(true, nil)
+				repo.EXPECT().InsertRefreshToken(context.Background(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "reused token ends the session",
+			stored: &entity.RefreshToken{
+				SessionID: "session-1",
+				Username:  "jdoe",
+				Used:      true,
+				ExpiresAt: future(),
+			},
+			mock: func(repo *mocks.MockTokensRepository) {
+				// The session ID is listed, revoking its access tokens.
+				repo.EXPECT().
+					Revoke(context.Background(), gomock.Cond(func(r *entity.RevokedToken) bool { return r.ID == "session-1" })).
+					Return(nil)
+				repo.EXPECT().DeleteSession(context.Background(), "session-1").Return(nil)
+			},
+			wantErr: tokens.ErrInvalidRefreshToken,
+		},
+		{
+			name: "concurrent use ends the session",
+			stored: &entity.RefreshToken{
+				SessionID: "session-1",
+				Username:  "jdoe",
+				ExpiresAt: future(),
+			},
+			mock: func(repo *mocks.MockTokensRepository) {
+				repo.EXPECT().MarkRefreshTokenUsed(context.Background(), sha256Hex("refresh-1")).Return(false, nil)
+				repo.EXPECT().Revoke(context.Background(), gomock.Any()).Return(nil)
+				repo.EXPECT().DeleteSession(context.Background(), "session-1").Return(nil)
+			},
+			wantErr: tokens.ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, repo := tokensTest(t, 24*time.Hour)
+
+			repo.EXPECT().GetRefreshToken(context.Background(), sha256Hex("refresh-1")).Return(tc.stored, nil)
+			tc.mock(repo)
+
+			session, err := useCase.Refresh(context.Background(), "refresh-1")
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				require.Nil(t, session)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "session-1", session.ID)
+			require.Equal(t, "tenant-a", session.TenantID)
+			require.NotEmpty(t, session.RefreshToken)
+			require.NotEqual(t, "refresh-1", session.RefreshToken)
+		})
+	}
+}
+
+func TestEndAll(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := tokensTest(t, 24*time.Hour)
+
+	repo.EXPECT().
+		GetRefreshTokensByUser(context.Background(), "jdoe", "tenant-a").
+		Return([]entity.RefreshToken{
+			{SessionID: "session-1"},
+			{SessionID: "session-1"},
+			{SessionID: "session-2"},
+		}, nil)
+
+	var revoked []string
+
+	repo.EXPECT().
+		Revoke(context.Background(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, t *entity.RevokedToken) error {
+			revoked = append(revoked, t.ID)
+
+			return nil
+		}).Times(2)
+	repo.EXPECT().DeleteSession(context.Background(), "session-1").Return(nil)
+	repo.EXPECT().DeleteSession(context.Background(), "session-2").Return(nil)
+
+	ended, err := useCase.EndAll(context.Background(), "jdoe", "tenant-a")
+	require.NoError(t, err)
+	require.Equal(t, 2, ended)
+	require.Equal(t, []string{"session-1", "session-2"}, revoked)
+}
+
+func TestIsRevoked(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := tokensTest(t, 24*time.Hour)
+
+	revoked, err := useCase.IsRevoked(context.Background(), "", "")
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	repo.EXPECT().IsRevoked(context.Background(), []string{"jti-1"}).Return(true, nil)
+
+	revoked, err = useCase.IsRevoked(context.Background(), "jti-1", "")
+	require.NoError(t, err)
+	require.True(t, revoked)
+}
@@ -0,0 +1,19 @@
+package trash
+
+import "context"
+
+type trashedKey struct{}
+
+// WithTrashed returns a context in which repositories look entities up by
+// key in the trash as well as among the live ones. Lists and counts still
+// see live entities only. A database migration reads trashed entities so.
+func WithTrashed(ctx context.Context) context.Context {
+	return context.WithValue(ctx, trashedKey{}, true)
+}
+
+// Trashed tells whether lookups by key in ctx reach the trash too.
+func Trashed(ctx context.Context) bool {
+	trashed, _ := ctx.Value(trashedKey{}).(bool)
+
+	return trashed
+}
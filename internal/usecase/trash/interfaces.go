@@ -0,0 +1,33 @@
+package trash
+
+import (
+	"context"
+	"time"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+)
+
+type (
+	// Repository reaches the soft-deleted entities of every kind. Restore and
+	// Purge only touch entities in the trash; GetExpired lists those deleted
+	// before a time, RFC 3339 in UTC, across tenants and oldest first.
+	// MoveToTrash moves a live entity to the trash as deleted at a given
+	// time, as a database migration copies one, without checking what
+	// refers to it.
+	Repository interface {
+		GetCount(ctx context.Context, kind, tenantID string) (int, error)
+		Get(ctx context.Context, kind string, top, skip int, tenantID string) ([]entity.TrashItem, error)
+		Restore(ctx context.Context, kind, id, tenantID string) (bool, error)
+		Purge(ctx context.Context, kind, id, tenantID string) (bool, error)
+		GetExpired(ctx context.Context, kind, deletedBefore string, top, skip int) ([]entity.TrashItem, error)
+		MoveToTrash(ctx context.Context, kind, id, tenantID, deletedAt string) (bool, error)
+	}
+	Feature interface {
+		GetCount(ctx context.Context, kind, tenantID string) (int, error)
+		Get(ctx context.Context, kind string, top, skip int, tenantID string) ([]dto.TrashItem, error)
+		Restore(ctx context.Context, kind, id, tenantID string) error
+		Purge(ctx context.Context, kind, id, tenantID string) error
+		PurgeExpired(ctx context.Context, now time.Time) (int, error)
+	}
+)
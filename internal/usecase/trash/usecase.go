@@ -0,0 +1,193 @@
+// Package trash restores and purges deleted devices and configurations.
+//
+// Deleting one only marks it deleted: it drops out of every listing and
+// lookup but stays in the trash, with its stored credentials, until it is
+// restored, purged by hand or, once the retention period has passed, purged
+// automatically.
+package trash
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// purgeBatchSize is how many expired entities are read per query.
+const purgeBatchSize = 100
+
+var (
+	ErrTrashUseCase = consoleerrors.CreateConsoleError("TrashUseCase")
+	ErrDatabase     = repoerrors.DatabaseError{Console: ErrTrashUseCase}
+	ErrNotFound     = repoerrors.NotFoundError{Console: ErrTrashUseCase}
+	ErrNotValid     = dto.NotValidError{Console: ErrTrashUseCase}
+
+	errUnknownKind = errors.New("kind must be devices, profiles, ciraconfigs, wirelessconfigs, ieee8021xconfigs or domains")
+)
+
+// UseCase -.
+type UseCase struct {
+	repo      Repository
+	retention time.Duration
+	log       logger.Interface
+}
+
+// New -. A zero retention keeps entities in the trash until purged by hand.
+func New(r Repository, retention time.Duration, log logger.Interface) *UseCase {
+	return &UseCase{
+		repo:      r,
+		retention: retention,
+		log:       log,
+	}
+}
+
+func validateKind(call, kind string) error {
+	if !slices.Contains(entity.TrashKinds, kind) {
+		return ErrNotValid.Wrap(call, "validateKind", errUnknownKind)
+	}
+
+	return nil
+}
+
+func (uc *UseCase) GetCount(ctx context.Context, kind, tenantID string) (int, error) {
+	if err := validateKind("GetCount", kind); err != nil {
+		return 0, err
+	}
+
+	count, err := uc.repo.GetCount(ctx, kind, tenantID)
+	if err != nil {
+		return 0, ErrDatabase.Wrap("GetCount", "uc.repo.GetCount", err)
+	}
+
+	return count, nil
+}
+
+func (uc *UseCase) Get(ctx context.Context, kind string, top, skip int, tenantID string) ([]dto.TrashItem, error) {
+	if err := validateKind("Get", kind); err != nil {
+		return nil, err
+	}
+
+	data, err := uc.repo.Get(ctx, kind, top, skip, tenantID)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Get", "uc.repo.Get", err)
+	}
+
+	d1 := make([]dto.TrashItem, len(data))
+
+	for i := range data {
+		d1[i] = *uc.entityToDTO(&data[i])
+	}
+
+	return d1, nil
+}
+
+func (uc *UseCase) Restore(ctx context.Context, kind, id, tenantID string) error {
+	if err := validateKind("Restore", kind); err != nil {
+		return err
+	}
+
+	restored, err := uc.repo.Restore(ctx, kind, id, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Restore", "uc.repo.Restore", err)
+	}
+
+	if !restored {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (uc *UseCase) Purge(ctx context.Context, kind, id, tenantID string) error {
+	if err := validateKind("Purge", kind); err != nil {
+		return err
+	}
+
+	purged, err := uc.repo.Purge(ctx, kind, id, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Purge", "uc.repo.Purge", err)
+	}
+
+	if !purged {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// PurgeExpired purges the entities that have been in the trash longer than
+// the retention period, in every tenant, and tells how many. One still
+// referenced by another in the trash, which is purged later, is skipped
+// until a later run.
+func (uc *UseCase) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	if uc.retention <= 0 {
+		return 0, nil
+	}
+
+	deletedBefore := now.Add(-uc.retention).UTC().Format(time.RFC3339)
+	purged := 0
+
+	for _, kind := range entity.TrashKinds {
+		skipped := 0
+
+		for {
+			page, err := uc.repo.GetExpired(ctx, kind, deletedBefore, purgeBatchSize, skipped)
+			if err != nil {
+				return purged, ErrDatabase.Wrap("PurgeExpired", "uc.repo.GetExpired", err)
+			}
+
+			for i := range page {
+				item := &page[i]
+
+				ok, err := uc.repo.Purge(ctx, kind, item.ID, item.TenantID)
+				if err != nil {
+					uc.log.Warn("trash: could not purge %s %q in tenant %q: %v", kind, item.ID, item.TenantID, err)
+
+					skipped++
+
+					continue
+				}
+
+				if ok {
+					purged++
+				}
+			}
+
+			if len(page) < purgeBatchSize {
+				break
+			}
+		}
+	}
+
+	if purged > 0 {
+		uc.log.Info("trash: purged %d entities deleted before %s", purged, deletedBefore)
+	}
+
+	return purged, nil
+}
+
+func (uc *UseCase) entityToDTO(d *entity.TrashItem) *dto.TrashItem {
+	d1 := &dto.TrashItem{
+		Kind:     d.Kind,
+		ID:       d.ID,
+		Name:     d.Name,
+		TenantID: d.TenantID,
+	}
+
+	if deletedAt, err := time.Parse(time.RFC3339, d.DeletedAt); err == nil {
+		d1.DeletedAt = deletedAt
+
+		if uc.retention > 0 {
+			purgeAt := deletedAt.Add(uc.retention)
+			d1.PurgeAt = &purgeAt
+		}
+	}
+
+	return d1
+}
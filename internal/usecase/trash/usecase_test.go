@@ -0,0 +1,103 @@
+package trash_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+var errInUse = errors.New("in use")
+
+func trashTest(t *testing.T, retention time.Duration) (*trash.UseCase, *mocks.MockTrashRepository) {
+	t.Helper()
+
+	repo := mocks.NewMockTrashRepository(gomock.NewController(t))
+
+	return trash.New(repo, retention, logger.New("error")), repo
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := trashTest(t, 24*time.Hour)
+
+	repo.EXPECT().
+		Get(context.Background(), entity.TrashDevices, 10, 0, "tenant").
+		Return([]entity.TrashItem{{Kind: entity.TrashDevices, ID: "guid", Name: "host", TenantID: "tenant", DeletedAt: "2026-10-19T00:00:00Z"}}, nil)
+
+	items, err := useCase.Get(context.Background(), entity.TrashDevices, 10, 0, "tenant")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), items[0].DeletedAt)
+	require.Equal(t, time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), *items[0].PurgeAt)
+
+	_, err = useCase.Get(context.Background(), "users", 10, 0, "tenant")
+	require.ErrorAs(t, err, &dto.NotValidError{})
+}
+
+func TestRestoreAndPurge(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := trashTest(t, 0)
+
+	repo.EXPECT().Restore(context.Background(), entity.TrashProfiles, "acm", "").Return(true, nil)
+	require.NoError(t, useCase.Restore(context.Background(), entity.TrashProfiles, "acm", ""))
+
+	repo.EXPECT().Restore(context.Background(), entity.TrashProfiles, "missing", "").Return(false, nil)
+	require.ErrorIs(t, useCase.Restore(context.Background(), entity.TrashProfiles, "missing", ""), trash.ErrNotFound)
+
+	repo.EXPECT().Purge(context.Background(), entity.TrashDomains, "domain", "").Return(false, nil)
+	require.ErrorIs(t, useCase.Purge(context.Background(), entity.TrashDomains, "domain", ""), trash.ErrNotFound)
+
+	require.Error(t, useCase.Purge(context.Background(), "users", "admin", ""))
+}
+
+func TestPurgeExpired(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := trashTest(t, time.Hour)
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	deletedBefore := "2026-10-19T11:00:00Z"
+
+	// A full page of profiles, one of which cannot be purged yet, so the next
+	// page starts past it.
+	profiles := make([]entity.TrashItem, 100)
+	for i := range profiles {
+		profiles[i] = entity.TrashItem{Kind: entity.TrashProfiles, ID: fmt.Sprintf("p%d", i)}
+	}
+
+	for _, kind := range entity.TrashKinds {
+		switch kind {
+		case entity.TrashProfiles:
+			repo.EXPECT().GetExpired(context.Background(), kind, deletedBefore, 100, 0).Return(profiles, nil)
+			repo.EXPECT().Purge(context.Background(), kind, gomock.Not("p0"), "").Return(true, nil).Times(99)
+			repo.EXPECT().Purge(context.Background(), kind, "p0", "").Return(false, errInUse)
+			repo.EXPECT().GetExpired(context.Background(), kind, deletedBefore, 100, 1).Return(nil, nil)
+		default:
+			repo.EXPECT().GetExpired(context.Background(), kind, deletedBefore, 100, 0).Return(nil, nil)
+		}
+	}
+
+	purged, err := useCase.PurgeExpired(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, 99, purged)
+
+	// Without a retention period nothing expires.
+	useCase, _ = trashTest(t, 0)
+
+	purged, err = useCase.PurgeExpired(context.Background(), now)
+	require.NoError(t, err)
+	require.Zero(t, purged)
+}
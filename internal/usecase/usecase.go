@@ -6,16 +6,30 @@ import (
 	"github.com/device-management-toolkit/go-wsman-messages/v2/pkg/security"
 
 	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/usecase/accesspolicies"
 	"github.com/device-management-toolkit/console/internal/usecase/amtexplorer"
+	"github.com/device-management-toolkit/console/internal/usecase/apikeys"
+	"github.com/device-management-toolkit/console/internal/usecase/audit"
+	"github.com/device-management-toolkit/console/internal/usecase/backup"
 	"github.com/device-management-toolkit/console/internal/usecase/ciraconfigs"
+	"github.com/device-management-toolkit/console/internal/usecase/credentials"
+	"github.com/device-management-toolkit/console/internal/usecase/devicegroups"
 	"github.com/device-management-toolkit/console/internal/usecase/devices"
 	"github.com/device-management-toolkit/console/internal/usecase/devices/wsman"
 	"github.com/device-management-toolkit/console/internal/usecase/domains"
 	"github.com/device-management-toolkit/console/internal/usecase/export"
 	"github.com/device-management-toolkit/console/internal/usecase/ieee8021xconfigs"
+	"github.com/device-management-toolkit/console/internal/usecase/keyrotation"
+	"github.com/device-management-toolkit/console/internal/usecase/ldapauth"
+	"github.com/device-management-toolkit/console/internal/usecase/lockouts"
 	"github.com/device-management-toolkit/console/internal/usecase/profiles"
 	"github.com/device-management-toolkit/console/internal/usecase/profilewificonfigs"
 	"github.com/device-management-toolkit/console/internal/usecase/sqldb"
+	"github.com/device-management-toolkit/console/internal/usecase/tags"
+	"github.com/device-management-toolkit/console/internal/usecase/tenants"
+	"github.com/device-management-toolkit/console/internal/usecase/tokens"
+	"github.com/device-management-toolkit/console/internal/usecase/trash"
+	"github.com/device-management-toolkit/console/internal/usecase/users"
 	"github.com/device-management-toolkit/console/internal/usecase/wificonfigs"
 	"github.com/device-management-toolkit/console/pkg/db"
 	"github.com/device-management-toolkit/console/pkg/logger"
@@ -32,6 +46,17 @@ type Repos struct {
 	IEEE8021xConfigs   ieee8021xconfigs.Repository
 	CIRAConfigs        ciraconfigs.Repository
 	WirelessConfigs    wificonfigs.Repository
+	Users              users.Repository
+	APIKeys            apikeys.Repository
+	Tokens             tokens.Repository
+	Audit              audit.Repository
+	AccessPolicies     accesspolicies.Repository
+	Trash              trash.Repository
+	DeviceGroups       devicegroups.Repository
+	Tags               tags.Repository
+	Tenants            tenants.Repository
+	// Transactor runs repository calls in one transaction.
+	Transactor backup.Transactor
 
 	// Closer releases the underlying driver.
 	Closer io.Closer
@@ -48,6 +73,16 @@ func NewSQLRepos(database *db.SQL, log logger.Interface) *Repos {
 		IEEE8021xConfigs:   sqldb.NewIEEE8021xRepo(database, log),
 		CIRAConfigs:        sqldb.NewCIRARepo(database, log),
 		WirelessConfigs:    sqldb.NewWirelessRepo(database, log),
+		Users:              sqldb.NewUserRepo(database, log),
+		APIKeys:            sqldb.NewAPIKeyRepo(database, log),
+		Tokens:             sqldb.NewTokenRepo(database, log),
+		Audit:              sqldb.NewAuditRepo(database, log),
+		AccessPolicies:     sqldb.NewAccessPolicyRepo(database, log),
+		Trash:              sqldb.NewTrashRepo(database, log),
+		DeviceGroups:       sqldb.NewDeviceGroupRepo(database, log),
+		Tags:               sqldb.NewTagRepo(database, log),
+		Tenants:            sqldb.NewTenantRepo(database, log),
+		Transactor:         database,
 		Closer: CloserFunc(func() error {
 			database.Close()
 
@@ -72,15 +107,28 @@ type Usecases struct {
 	CIRAConfigs        ciraconfigs.Feature
 	WirelessProfiles   wificonfigs.Feature
 	Exporter           export.Exporter
+	Users              users.Feature
+	APIKeys            apikeys.Feature
+	Tokens             tokens.Feature
+	Lockouts           lockouts.Feature
+	Audit              audit.Feature
+	AccessPolicies     accesspolicies.Feature
+	Backup             backup.Feature
+	Trash              trash.Feature
+	DeviceGroups       devicegroups.Feature
+	Tags               tags.Feature
+	// LDAP is nil unless a directory is configured.
+	LDAP ldapauth.Feature
 }
 
 // NewUseCases wires every use case from a repo bundle. The caller picks the
 // backend via one of the Repos constructors; this function doesn't care which.
 func NewUseCases(repos *Repos, log logger.Interface, certStore security.Storager) *Usecases {
-	key := config.ConsoleConfig.EncryptionKey
-	safeRequirements := security.Crypto{
-		EncryptionKey: key,
-	}
+	safeRequirements := keyrotation.NewCryptor(config.ConsoleConfig.EncryptionKey, config.ConsoleConfig.PreviousEncryptionKey)
+	// Groups match the devices as stored, so they sit below the secret store.
+	groups := devicegroups.New(repos.DeviceGroups, repos.Devices, log)
+	repos = withDeviceGroups(repos, groups)
+	repos = withCredentialStore(repos, certStore, safeRequirements, log)
 
 	wsman1 := wsman.NewGoWSMANMessages(log, safeRequirements)
 	wsman2 := amtexplorer.NewGoWSMANMessages(log, safeRequirements)
@@ -90,15 +138,101 @@ func NewUseCases(repos *Repos, log logger.Interface, certStore security.Storager
 	domains1 := domains.New(repos.Domains, log, safeRequirements, certStore)
 	wificonfig := wificonfigs.New(repos.WirelessConfigs, ieee, log, safeRequirements)
 
-	return &Usecases{
+	devices1 := devices.New(repos.Devices, wsman1, devices.NewRedirector(safeRequirements), log, safeRequirements)
+	devices1.SetSessionPolicies(sessionPolicies(config.ConsoleConfig.Redirection))
+
+	audit1 := audit.New(repos.Audit, log, config.ConsoleConfig.AuditKey, config.ConsoleConfig.PreviousAuditKeys)
+	devices1.SetAuditRecorder(audit1)
+
+	accessPolicies := accesspolicies.New(repos.AccessPolicies, log)
+
+	// The explorer reaches devices too, so it honours access policies alike.
+	explorerDevices := devices.NewScopedRepository(repos.Devices)
+
+	uc := &Usecases{
 		Domains:            domains1,
-		Devices:            devices.New(repos.Devices, wsman1, devices.NewRedirector(safeRequirements), log, safeRequirements),
-		AMTExplorer:        amtexplorer.New(repos.Devices, wsman2, log, safeRequirements),
+		Devices:            devices1,
+		AMTExplorer:        amtexplorer.New(explorerDevices, wsman2, log, safeRequirements),
 		Profiles:           profiles.New(repos.Profiles, repos.WirelessConfigs, pwc, ieee, log, domains1, repos.CIRAConfigs, safeRequirements, config.ConsoleConfig.DisableCIRA),
 		IEEE8021xProfiles:  ieee,
 		CIRAConfigs:        ciraconfigs.New(repos.CIRAConfigs, log, safeRequirements),
 		WirelessProfiles:   wificonfig,
 		ProfileWiFiConfigs: pwc,
 		Exporter:           export.NewFileExporter(),
+		Users:              users.New(repos.Users, log, config.ConsoleConfig.PasswordPolicy),
+		APIKeys:            apikeys.New(repos.APIKeys, repos.Devices, log),
+		Tokens:             tokens.New(repos.Tokens, log, config.ConsoleConfig.JWTExpiration, config.ConsoleConfig.RefreshTokenExpiration),
+		Lockouts:           lockouts.New(log, config.ConsoleConfig.Lockout),
+		Audit:              audit1,
+		AccessPolicies:     accessPolicies,
+		Backup: backup.New(backup.Repositories{
+			CIRAConfigs:        repos.CIRAConfigs,
+			IEEE8021xConfigs:   repos.IEEE8021xConfigs,
+			WirelessConfigs:    repos.WirelessConfigs,
+			Domains:            repos.Domains,
+			Profiles:           repos.Profiles,
+			ProfileWiFiConfigs: repos.ProfileWiFiConfigs,
+			Devices:            repos.Devices,
+			Transactor:         repos.Transactor,
+		}, domains1, log, safeRequirements),
+		Trash:        trash.New(repos.Trash, config.ConsoleConfig.TrashRetention, log),
+		DeviceGroups: groups,
+		Tags:         tags.New(repos.Tags, groups, []tags.Referrer{accessPolicies, groups}, log),
+	}
+
+	if ldapConfig := config.ConsoleConfig.LDAP; ldapConfig.Enabled() {
+		uc.LDAP = ldapauth.New(ldapConfig, ldapauth.NewDialer(ldapConfig), log)
+	}
+
+	return uc
+}
+
+// sessionPolicies maps the redirection config onto the devices use case.
+func sessionPolicies(cfg config.Redirection) devices.SessionPolicies {
+	policy := func(p config.RedirectionPolicy) devices.SessionPolicy {
+		return devices.SessionPolicy{IdleTimeout: p.IdleTimeout, MaxDuration: p.MaxDuration, BandwidthLimit: p.BandwidthLimit}
+	}
+
+	return devices.SessionPolicies{
+		Modes: map[string]devices.SessionPolicy{
+			"kvm":  policy(cfg.KVM),
+			"sol":  policy(cfg.SOL),
+			"ider": policy(cfg.IDER),
+		},
+		WarningPeriod:  cfg.WarningPeriod,
+		BandwidthLimit: cfg.BandwidthLimit,
+	}
+}
+
+// withDeviceGroups wraps the device, trash and tag repositories to match the
+// devices they write against the group rules.
+func withDeviceGroups(repos *Repos, groups *devicegroups.UseCase) *Repos {
+	wrapped := *repos
+	wrapped.Devices = devicegroups.NewDeviceRepository(repos.Devices, groups)
+	wrapped.Trash = devicegroups.NewTrashRepository(repos.Trash, groups)
+	wrapped.Tags = devicegroups.NewTagRepository(repos.Tags, groups)
+
+	return &wrapped
+}
+
+// withCredentialStore wraps the device and profile repositories to keep their
+// passwords in the secret store, if configured to.
+func withCredentialStore(repos *Repos, certStore security.Storager, crypto security.Cryptor, log logger.Interface) *Repos {
+	if !config.ConsoleConfig.StoreCredentials {
+		return repos
+	}
+
+	store, ok := certStore.(credentials.ObjectStorager)
+	if !ok {
+		log.Warn("secrets.store_credentials is set but no secret store is configured; credentials stay in the database")
+
+		return repos
 	}
+
+	wrapped := *repos
+	wrapped.Devices = credentials.NewDeviceRepository(repos.Devices, repos.Trash, store, crypto, log)
+	wrapped.Profiles = credentials.NewProfileRepository(repos.Profiles, repos.Trash, store, crypto, log)
+	wrapped.Trash = credentials.NewTrashRepository(repos.Trash, store, log)
+
+	return &wrapped
 }
@@ -12,7 +12,9 @@ import (
 
 	"github.com/device-management-toolkit/console/config"
 	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/audit"
 	"github.com/device-management-toolkit/console/internal/usecase/ciraconfigs"
+	"github.com/device-management-toolkit/console/internal/usecase/devicegroups"
 	"github.com/device-management-toolkit/console/internal/usecase/devices"
 	"github.com/device-management-toolkit/console/internal/usecase/devices/wsman"
 	"github.com/device-management-toolkit/console/internal/usecase/domains"
@@ -37,6 +39,7 @@ func setupConfig() {
 		config.ConsoleConfig = &config.Config{
 			App: config.App{
 				EncryptionKey: "test",
+				AuditKey:      "test",
 			},
 		}
 	})
@@ -49,6 +52,14 @@ func TestUsecases(t *testing.T) {
 		EncryptionKey: "test",
 	}
 
+	// Devices are matched against the group rules as they are written.
+	groups := devicegroups.New(sqldb.NewDeviceGroupRepo(&db.SQL{}, mocks.NewMockLogger(nil)), sqldb.NewDeviceRepo(&db.SQL{}, mocks.NewMockLogger(nil)), mocks.NewMockLogger(nil))
+	deviceRepo := devicegroups.NewDeviceRepository(sqldb.NewDeviceRepo(&db.SQL{}, mocks.NewMockLogger(nil)), groups)
+
+	expectedDevices := devices.New(deviceRepo, wsman.NewGoWSMANMessages(mocks.NewMockLogger(nil), safeRequirements), devices.NewRedirector(safeRequirements), mocks.NewMockLogger(nil), safeRequirements)
+	expectedDevices.SetSessionPolicies(sessionPolicies(config.Redirection{}))
+	expectedDevices.SetAuditRecorder(audit.New(sqldb.NewAuditRepo(&db.SQL{}, mocks.NewMockLogger(nil)), mocks.NewMockLogger(nil), "test", nil))
+
 	tests := []usecaseTest{
 		{
 			name: "NewUseCases initializes correctly",
@@ -62,7 +73,7 @@ func TestUsecases(t *testing.T) {
 			},
 			expectedResult: &Usecases{
 				Domains: domains.New(sqldb.NewDomainRepo(&db.SQL{}, mocks.NewMockLogger(nil)), mocks.NewMockLogger(nil), safeRequirements, nil),
-				Devices: devices.New(sqldb.NewDeviceRepo(&db.SQL{}, mocks.NewMockLogger(nil)), wsman.NewGoWSMANMessages(mocks.NewMockLogger(nil), safeRequirements), devices.NewRedirector(safeRequirements), mocks.NewMockLogger(nil), safeRequirements),
+				Devices: expectedDevices,
 				Profiles: profiles.New(
 					sqldb.NewProfileRepo(&db.SQL{}, mocks.NewMockLogger(nil)),
 					sqldb.NewWirelessRepo(&db.SQL{}, mocks.NewMockLogger(nil)),
@@ -97,6 +108,11 @@ func TestUsecases(t *testing.T) {
 			assert.NotNil(t, uc.IEEE8021xProfiles)
 			assert.NotNil(t, uc.CIRAConfigs)
 			assert.NotNil(t, uc.WirelessProfiles)
+			assert.NotNil(t, uc.Audit)
+			assert.NotNil(t, uc.AccessPolicies)
+			assert.NotNil(t, uc.Backup)
+			assert.NotNil(t, uc.DeviceGroups)
+			assert.NotNil(t, uc.Tags)
 
 			assert.Equal(t, tc.expectedResult.Domains, uc.Domains)
 			assert.Equal(t, tc.expectedResult.Devices, uc.Devices)
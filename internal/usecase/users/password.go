@@ -0,0 +1,65 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/device-management-toolkit/console/config"
+)
+
+// bcrypt ignores everything past 72 bytes, so longer passwords are refused
+// rather than silently truncated.
+const maxPasswordBytes = 72
+
+var (
+	errPasswordTooLong    = fmt.Errorf("password must be at most %d bytes", maxPasswordBytes)
+	errPasswordIsUsername = errors.New("password must not contain the username")
+	errPasswordReused     = errors.New("new password must differ from the current one")
+	errPasswordTooShort   = errors.New("password is too short")
+	errPasswordTooSimple  = errors.New("password needs more kinds of characters")
+	errUnknownRole        = errors.New("unknown role")
+)
+
+func unknownRole(role string) error {
+	return fmt.Errorf("%w %q (want super-admin, admin, operator, helpdesk or read-only)", errUnknownRole, role)
+}
+
+// checkPasswordPolicy enforces the configured length and character-class
+// minimums.
+func checkPasswordPolicy(policy config.PasswordPolicy, username, password string) error {
+	if len(password) > maxPasswordBytes {
+		return errPasswordTooLong
+	}
+
+	if len([]rune(password)) < policy.MinLength {
+		return fmt.Errorf("%w: at least %d characters are required", errPasswordTooShort, policy.MinLength)
+	}
+
+	if username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return errPasswordIsUsername
+	}
+
+	var lower, upper, digit, symbol int
+
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	if classes := lower + upper + digit + symbol; classes < policy.MinCharacterClasses {
+		return fmt.Errorf("%w: use at least %d of lower case, upper case, digits and symbols",
+			errPasswordTooSimple, policy.MinCharacterClasses)
+	}
+
+	return nil
+}
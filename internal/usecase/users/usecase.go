@@ -0,0 +1,272 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/rbac"
+	"github.com/device-management-toolkit/console/internal/repoerrors"
+	"github.com/device-management-toolkit/console/pkg/consoleerrors"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+// UseCase -.
+type UseCase struct {
+	repo   Repository
+	log    logger.Interface
+	policy config.PasswordPolicy
+}
+
+var (
+	ErrUsersUseCase = consoleerrors.CreateConsoleError("UsersUseCase")
+	ErrDatabase     = repoerrors.DatabaseError{Console: ErrUsersUseCase}
+	ErrNotFound     = repoerrors.NotFoundError{Console: ErrUsersUseCase}
+	ErrNotValid     = dto.NotValidError{Console: ErrUsersUseCase}
+
+	ErrInvalidCredentials     = errors.New("invalid credentials")
+	ErrPasswordChangeRequired = errors.New("password change required: set a new password before logging in")
+	// ErrUnknownUser is the ErrInvalidCredentials of a username with no
+	// account, after which a login may be tried against the directory.
+	ErrUnknownUser = fmt.Errorf("%w: no such user", ErrInvalidCredentials)
+
+	// dummyHash is compared against when the user does not exist, so unknown
+	// and known usernames take the same time to reject.
+	dummyHash = sync.OnceValue(func() []byte {
+		hash, _ := bcrypt.GenerateFromPassword([]byte("console-dummy-password"), bcrypt.DefaultCost)
+
+		return hash
+	})
+)
+
+// New -.
+func New(r Repository, log logger.Interface, policy config.PasswordPolicy) *UseCase {
+	return &UseCase{
+		repo:   r,
+		log:    log,
+		policy: policy,
+	}
+}
+
+func (uc *UseCase) GetCount(ctx context.Context, tenantID string) (int, error) {
+	count, err := uc.repo.GetCount(ctx, tenantID)
+	if err != nil {
+		return 0, ErrDatabase.Wrap("GetCount", "uc.repo.GetCount", err)
+	}
+
+	return count, nil
+}
+
+func (uc *UseCase) Get(ctx context.Context, top, skip int, tenantID string) ([]dto.User, error) {
+	data, err := uc.repo.Get(ctx, top, skip, tenantID)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Get", "uc.repo.Get", err)
+	}
+
+	d1 := make([]dto.User, len(data))
+
+	for i := range data {
+		d1[i] = *entityToDTO(&data[i])
+	}
+
+	return d1, nil
+}
+
+// GetByUsername hides users of other tenants as if they did not exist.
+func (uc *UseCase) GetByUsername(ctx context.Context, username, tenantID string) (*dto.User, error) {
+	data, err := uc.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("GetByUsername", "uc.repo.GetByUsername", err)
+	}
+
+	if data == nil || data.TenantID != tenantID {
+		return nil, ErrNotFound
+	}
+
+	return entityToDTO(data), nil
+}
+
+func (uc *UseCase) Delete(ctx context.Context, username, tenantID string) error {
+	isSuccessful, err := uc.repo.Delete(ctx, username, tenantID)
+	if err != nil {
+		return ErrDatabase.Wrap("Delete", "uc.repo.Delete", err)
+	}
+
+	if !isSuccessful {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Update changes a user's role and forced-change flag. A password in the
+// request resets it, and the user must change it at their next login.
+func (uc *UseCase) Update(ctx context.Context, d *dto.User) (*dto.User, error) {
+	existing, err := uc.repo.GetByUsername(ctx, d.Username)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Update", "uc.repo.GetByUsername", err)
+	}
+
+	if existing == nil || existing.TenantID != d.TenantID {
+		return nil, ErrNotFound
+	}
+
+	role, ok := rbac.ParseRole(d.Role)
+	if !ok {
+		return nil, ErrNotValid.Wrap("Update", "rbac.ParseRole", unknownRole(d.Role))
+	}
+
+	existing.Role = string(role)
+	existing.MustChangePassword = d.MustChangePassword
+
+	if d.Password != "" {
+		if err := uc.setPassword(existing, d.Password); err != nil {
+			return nil, err
+		}
+
+		existing.MustChangePassword = true
+	}
+
+	updated, err := uc.repo.Update(ctx, existing)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("Update", "uc.repo.Update", err)
+	}
+
+	if !updated {
+		return nil, ErrNotFound
+	}
+
+	return uc.GetByUsername(ctx, d.Username, d.TenantID)
+}
+
+// Insert creates a user with an initial password, which they must change at
+// their first login.
+func (uc *UseCase) Insert(ctx context.Context, d *dto.User) (*dto.User, error) {
+	role, ok := rbac.ParseRole(d.Role)
+	if !ok {
+		return nil, ErrNotValid.Wrap("Insert", "rbac.ParseRole", unknownRole(d.Role))
+	}
+
+	u := &entity.User{
+		Username:           d.Username,
+		Role:               string(role),
+		MustChangePassword: true,
+		CreationDate:       time.Now().UTC().Format(time.RFC3339),
+		CreatedBy:          d.CreatedBy,
+		TenantID:           d.TenantID,
+	}
+
+	if err := uc.setPassword(u, d.Password); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.repo.Insert(ctx, u); err != nil {
+		return nil, ErrDatabase.Wrap("Insert", "uc.repo.Insert", err)
+	}
+
+	return uc.GetByUsername(ctx, d.Username, d.TenantID)
+}
+
+// Authenticate checks a user's password. It returns ErrInvalidCredentials for
+// an unknown user (as ErrUnknownUser) or wrong password, and ErrPasswordChangeRequired when the
+// password is right but must be changed first.
+func (uc *UseCase) Authenticate(ctx context.Context, username, password string) (*dto.User, error) {
+	u, err := uc.verify(ctx, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.MustChangePassword {
+		return nil, ErrPasswordChangeRequired
+	}
+
+	return entityToDTO(u), nil
+}
+
+// ChangePassword sets a new password for a user who proves the current one.
+// It clears a forced change.
+func (uc *UseCase) ChangePassword(ctx context.Context, change dto.PasswordChange) error {
+	u, err := uc.verify(ctx, change.Username, change.Password)
+	if err != nil {
+		return err
+	}
+
+	if change.NewPassword == change.Password {
+		return ErrNotValid.Wrap("ChangePassword", "uc.setPassword", errPasswordReused)
+	}
+
+	if err := uc.setPassword(u, change.NewPassword); err != nil {
+		return err
+	}
+
+	u.MustChangePassword = false
+
+	if _, err := uc.repo.Update(ctx, u); err != nil {
+		return ErrDatabase.Wrap("ChangePassword", "uc.repo.Update", err)
+	}
+
+	return nil
+}
+
+func (uc *UseCase) verify(ctx context.Context, username, password string) (*entity.User, error) {
+	u, err := uc.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, ErrDatabase.Wrap("verify", "uc.repo.GetByUsername", err)
+	}
+
+	if u == nil {
+		_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+
+		return nil, ErrUnknownUser
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return u, nil
+}
+
+func (uc *UseCase) setPassword(u *entity.User, password string) error {
+	if err := checkPasswordPolicy(uc.policy, u.Username, password); err != nil {
+		return ErrNotValid.Wrap("setPassword", "checkPasswordPolicy", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("users - setPassword - bcrypt.GenerateFromPassword: %w", err)
+	}
+
+	u.PasswordHash = string(hash)
+	u.PasswordChangedAt = time.Now().UTC().Format(time.RFC3339)
+
+	return nil
+}
+
+func entityToDTO(u *entity.User) *dto.User {
+	return &dto.User{
+		Username:           u.Username,
+		Role:               u.Role,
+		MustChangePassword: u.MustChangePassword,
+		PasswordChangedAt:  parseTime(u.PasswordChangedAt),
+		CreationDate:       parseTime(u.CreationDate),
+		CreatedBy:          u.CreatedBy,
+		TenantID:           u.TenantID,
+	}
+}
+
+func parseTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+
+	return &t
+}
@@ -0,0 +1,235 @@
+package users_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/device-management-toolkit/console/config"
+	"github.com/device-management-toolkit/console/internal/entity"
+	"github.com/device-management-toolkit/console/internal/entity/dto/v1"
+	"github.com/device-management-toolkit/console/internal/mocks"
+	"github.com/device-management-toolkit/console/internal/usecase/users"
+	"github.com/device-management-toolkit/console/pkg/logger"
+)
+
+const (
+	initialPassword = "Initial-Passw0rd"
+	newPassword     = "Correct-Horse-42"
+)
+
+func usersTest(t *testing.T) (*users.UseCase, *mocks.MockUsersRepository) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	repo := mocks.NewMockUsersRepository(mockCtl)
+	policy := config.PasswordPolicy{MinLength: 12, MinCharacterClasses: 3}
+
+	return users.New(repo, logger.New("error"), policy), repo
+}
+
+func hashed(t *testing.T, password string) string {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	return string(hash)
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := usersTest(t)
+
+	var stored *entity.User
+
+	repo.EXPECT().
+		Insert(context.Background(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, u *entity.User) (string, error) {
+			stored = u
+
+			return "", nil
+		})
+	repo.EXPECT().
+		GetByUsername(context.Background(), "jdoe").
+		DoAndReturn(func(_ context.Context, _ string) (*entity.User, error) { return stored, nil })
+
+	got, err := useCase.Insert(context.Background(), &dto.User{
+		Username:  "jdoe",
+		Role:      "Operator",
+		Password:  initialPassword,
+		CreatedBy: "admin",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "operator", got.Role)
+	require.True(t, got.MustChangePassword)
+	require.Equal(t, "admin", got.CreatedBy)
+	require.Empty(t, got.Password)
+	require.NotNil(t, got.CreationDate)
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(stored.PasswordHash), []byte(initialPassword)))
+}
+
+func TestInsert_Rejected(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		role     string
+		password string
+	}{
+		{name: "unknown role", role: "root", password: initialPassword},
+		{name: "too short", role: "admin", password: "Sh0rt-pw"},
+		{name: "too simple", role: "admin", password: "alllowercaseletters"},
+		{name: "contains username", role: "admin", password: "JDoe-Passw0rd-1"},
+		{name: "too long", role: "admin", password: "Aa1-" + string(make([]byte, 72))},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, _ := usersTest(t)
+
+			_, err := useCase.Insert(context.Background(), &dto.User{Username: "jdoe", Role: tc.role, Password: tc.password})
+
+			var notValid dto.NotValidError
+			require.ErrorAs(t, err, &notValid)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		user     *entity.User
+		password string
+		err      error
+	}{
+		{
+			name:     "success",
+			user:     &entity.User{Username: "jdoe", Role: "helpdesk", PasswordHash: hashed(t, newPassword)},
+			password: newPassword,
+		},
+		{
+			name:     "wrong password",
+			user:     &entity.User{Username: "jdoe", Role: "helpdesk", PasswordHash: hashed(t, newPassword)},
+			password: "wrong",
+			err:      users.ErrInvalidCredentials,
+		},
+		{
+			name:     "unknown user",
+			password: newPassword,
+			err:      users.ErrInvalidCredentials,
+		},
+		{
+			name:     "change required",
+			user:     &entity.User{Username: "jdoe", Role: "helpdesk", PasswordHash: hashed(t, initialPassword), MustChangePassword: true},
+			password: initialPassword,
+			err:      users.ErrPasswordChangeRequired,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, repo := usersTest(t)
+
+			repo.EXPECT().GetByUsername(context.Background(), "jdoe").Return(tc.user, nil)
+
+			got, err := useCase.Authenticate(context.Background(), "jdoe", tc.password)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				require.Equal(t, tc.user == nil, errors.Is(err, users.ErrUnknownUser), "only unknown users may be tried elsewhere")
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "helpdesk", got.Role)
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := usersTest(t)
+
+	user := &entity.User{Username: "jdoe", Role: "operator", PasswordHash: hashed(t, initialPassword), MustChangePassword: true}
+
+	repo.EXPECT().GetByUsername(context.Background(), "jdoe").Return(user, nil)
+	repo.EXPECT().
+		Update(context.Background(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, u *entity.User) (bool, error) {
+			require.False(t, u.MustChangePassword)
+			require.NoError(t, bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(newPassword)))
+
+			return true, nil
+		})
+
+	err := useCase.ChangePassword(context.Background(), dto.PasswordChange{
+		Username:    "jdoe",
+		Password:    initialPassword,
+		NewPassword: newPassword,
+	})
+	require.NoError(t, err)
+}
+
+func TestChangePassword_Rejected(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := usersTest(t)
+
+	user := &entity.User{Username: "jdoe", PasswordHash: hashed(t, initialPassword), MustChangePassword: true}
+
+	repo.EXPECT().GetByUsername(context.Background(), "jdoe").Return(user, nil).Times(2)
+
+	err := useCase.ChangePassword(context.Background(), dto.PasswordChange{
+		Username: "jdoe", Password: "wrong", NewPassword: newPassword,
+	})
+	require.ErrorIs(t, err, users.ErrInvalidCredentials)
+
+	err = useCase.ChangePassword(context.Background(), dto.PasswordChange{
+		Username: "jdoe", Password: initialPassword, NewPassword: initialPassword,
+	})
+
+	var notValid dto.NotValidError
+	require.ErrorAs(t, err, &notValid)
+}
+
+func TestUpdate_PasswordResetForcesChange(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := usersTest(t)
+
+	user := &entity.User{Username: "jdoe", Role: "operator", PasswordHash: hashed(t, newPassword)}
+
+	repo.EXPECT().GetByUsername(context.Background(), "jdoe").Return(user, nil).Times(2)
+	repo.EXPECT().Update(context.Background(), user).Return(true, nil)
+
+	got, err := useCase.Update(context.Background(), &dto.User{Username: "jdoe", Role: "admin", Password: initialPassword})
+	require.NoError(t, err)
+	require.Equal(t, "admin", got.Role)
+	require.True(t, got.MustChangePassword)
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := usersTest(t)
+
+	repo.EXPECT().Delete(context.Background(), "ghost", "").Return(false, nil)
+
+	err := useCase.Delete(context.Background(), "ghost", "")
+	require.ErrorIs(t, err, users.ErrNotFound)
+}
@@ -0,0 +1,49 @@
+// Package versions names the versions of stored entities and carries the
+// ones a request's If-Match header expects through its context, so that
+// repositories update or delete an entity only while it is still at one of
+// them, without each signature naming them. A context without any matches
+// every version.
+package versions
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+)
+
+// New returns a version for an entity just inserted or updated. Versions are
+// opaque: they only tell whether an entity changed.
+func New() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+type contextKey struct{}
+
+// WithIfMatch returns a copy of ctx that expects one of versions. An empty
+// list matches no version.
+func WithIfMatch(ctx context.Context, versions []string) context.Context {
+	if versions == nil {
+		versions = []string{}
+	}
+
+	return context.WithValue(ctx, contextKey{}, versions)
+}
+
+// IfMatch returns the versions ctx expects, and whether it expects any at
+// all.
+func IfMatch(ctx context.Context) ([]string, bool) {
+	versions, ok := ctx.Value(contextKey{}).([]string)
+
+	return versions, ok
+}
+
+// Stale tells whether ctx expects versions other than version.
+func Stale(ctx context.Context, version string) bool {
+	expected, ok := IfMatch(ctx)
+
+	return ok && !slices.Contains(expected, version)
+}
@@ -0,0 +1,40 @@
+package versions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIfMatch(t *testing.T) {
+	t.Parallel()
+
+	_, ok := IfMatch(context.Background())
+	require.False(t, ok, "a context without versions matches every version")
+
+	got, ok := IfMatch(WithIfMatch(context.Background(), []string{"a", "b"}))
+	require.True(t, ok)
+	require.Equal(t, []string{"a", "b"}, got)
+
+	got, ok = IfMatch(WithIfMatch(context.Background(), nil))
+	require.True(t, ok)
+	require.Empty(t, got, "matches no version")
+}
+
+func TestStale(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, Stale(context.Background(), "a"))
+
+	ctx := WithIfMatch(context.Background(), []string{"a", "b"})
+	require.False(t, Stale(ctx, "b"))
+	require.True(t, Stale(ctx, "c"))
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	require.Len(t, New(), 16)
+	require.NotEqual(t, New(), New())
+}
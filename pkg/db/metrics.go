@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The provider label values of QueriesCanceled.
+const (
+	ProviderPostgres = "postgres"
+	ProviderSQLite   = "sqlite"
+	ProviderMongo    = "mongo"
+)
+
+// QueriesCanceled counts database calls cut short by their context, per
+// provider and reason ("canceled" when the client went away, "timeout" when
+// a deadline passed).
+var QueriesCanceled = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "db_queries_canceled_total",
+		Help: "Database calls cut short by a canceled or timed-out context (per provider and reason)",
+	},
+	[]string{"provider", "reason"},
+)
+
+// ObserveCanceled counts err in QueriesCanceled when it is a context
+// cancellation or deadline; other errors, and nil, are ignored.
+func ObserveCanceled(provider string, err error) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		QueriesCanceled.WithLabelValues(provider, "timeout").Inc()
+	case errors.Is(err, context.Canceled):
+		QueriesCanceled.WithLabelValues(provider, "canceled").Inc()
+	}
+}
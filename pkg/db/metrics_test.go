@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	canceled := QueriesCanceled.WithLabelValues(ProviderPostgres, "canceled")
+	timedOut := QueriesCanceled.WithLabelValues(ProviderSQLite, "timeout")
+	canceledBefore := testutil.ToFloat64(canceled)
+	timedOutBefore := testutil.ToFloat64(timedOut)
+
+	// A call that finishes in time is not counted.
+	ctx, cancel := (&SQL{queryTimeout: time.Minute}).WithTimeout(context.Background())
+	_, ok := ctx.Deadline()
+	require.True(t, ok)
+	cancel()
+
+	ctx, cancel = (&SQL{}).WithTimeout(context.Background())
+	_, ok = ctx.Deadline()
+	require.False(t, ok, "no timeout configured")
+	cancel()
+
+	parent, stop := context.WithCancel(context.Background())
+	stop()
+
+	_, cancel = (&SQL{queryTimeout: time.Minute}).WithTimeout(parent)
+	cancel()
+
+	ctx, cancel = (&SQL{queryTimeout: time.Millisecond, IsEmbedded: true}).WithTimeout(context.Background())
+	<-ctx.Done()
+	cancel()
+
+	require.InDelta(t, canceledBefore+1, testutil.ToFloat64(canceled), 0)
+	require.InDelta(t, timedOutBefore+1, testutil.ToFloat64(timedOut), 0)
+}
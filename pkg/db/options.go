@@ -32,3 +32,11 @@ func EnableForeignKeys(value bool) Option {
 		c.enableForeignKeys = value
 	}
 }
+
+// QueryTimeout bounds each repository call; zero or less leaves only the
+// caller's deadline.
+func QueryTimeout(timeout time.Duration) Option {
+	return func(c *SQL) {
+		c.queryTimeout = timeout
+	}
+}
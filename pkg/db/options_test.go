@@ -46,3 +46,13 @@ func TestEnableForeignKeys(t *testing.T) {
 
 	assert.Equal(t, expectedValue, true, "EnableForeignKeys() should set the enableForeignKeys correctly")
 }
+
+func TestQueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	expectedTimeout := 30 * time.Second
+	sql := &SQL{}
+	QueryTimeout(expectedTimeout)(sql)
+
+	assert.Equal(t, expectedTimeout, sql.queryTimeout, "QueryTimeout() should set the queryTimeout correctly")
+}
@@ -29,6 +29,7 @@ type SQL struct {
 	maxPoolSize  int
 	connAttempts int
 	connTimeout  time.Duration
+	queryTimeout time.Duration
 
 	Builder           squirrel.StatementBuilderType
 	Pool              *sql.DB
@@ -123,6 +124,30 @@ func setupHostedDB(db *SQL, url string, dbOpen OpenFunc) error {
 	return nil
 }
 
+// WithTimeout derives the context of one repository call from the caller's
+// ctx, bounded by the QueryTimeout. The returned cancel must be called once
+// the call is done; it counts the call in QueriesCanceled if ctx was
+// canceled or timed out by then.
+func (p *SQL) WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	provider := ProviderPostgres
+	if p.IsEmbedded {
+		provider = ProviderSQLite
+	}
+
+	var cancel context.CancelFunc
+
+	if p.queryTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, p.queryTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	return ctx, func() {
+		ObserveCanceled(provider, ctx.Err())
+		cancel()
+	}
+}
+
 // Close -.
 func (p *SQL) Close() {
 	if p.Pool != nil {
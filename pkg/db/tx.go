@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Querier is what repositories run statements on: the pool, or the
+// transaction a call joins.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// Conn returns the transaction ctx carries, if it was derived from the ctx
+// InTx hands its callback, or else the pool.
+func (p *SQL) Conn(ctx context.Context) Querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return p.Pool
+}
+
+// InTx runs fn in one transaction: repository calls made with the ctx fn is
+// given join it, and it is committed if fn returns nil and rolled back
+// otherwise. Called with a ctx already in a transaction, fn simply joins it.
+func (p *SQL) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := p.Pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return errors.Join(err, ignoreDone(tx.Rollback()))
+	}
+
+	return tx.Commit()
+}
+
+// ignoreDone drops the error of rolling back a transaction that the driver
+// already ended, as it does when its ctx is canceled.
+func ignoreDone(err error) error {
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestInTx(t *testing.T) {
+	t.Parallel()
+
+	pool, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	// One connection, so every statement sees the same in-memory database.
+	pool.SetMaxOpenConns(1)
+	t.Cleanup(func() { pool.Close() })
+
+	p := &SQL{Pool: pool}
+	ctx := context.Background()
+
+	_, err = p.Conn(ctx).ExecContext(ctx, "CREATE TABLE t (n INTEGER)")
+	require.NoError(t, err)
+
+	count := func() int {
+		var n int
+
+		require.NoError(t, p.Conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM t").Scan(&n))
+
+		return n
+	}
+
+	errBoom := errors.New("boom")
+
+	err = p.InTx(ctx, func(ctx context.Context) error {
+		_, err := p.Conn(ctx).ExecContext(ctx, "INSERT INTO t VALUES (1)")
+		require.NoError(t, err)
+
+		// Nested calls join the outer transaction.
+		return p.InTx(ctx, func(ctx context.Context) error {
+			_, err := p.Conn(ctx).ExecContext(ctx, "INSERT INTO t VALUES (2)")
+			require.NoError(t, err)
+
+			return errBoom
+		})
+	})
+	require.ErrorIs(t, err, errBoom)
+	require.Equal(t, 0, count(), "rolled back")
+
+	err = p.InTx(ctx, func(ctx context.Context) error {
+		_, err := p.Conn(ctx).ExecContext(ctx, "INSERT INTO t VALUES (1)")
+
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, count(), "committed")
+}
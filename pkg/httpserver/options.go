@@ -1,6 +1,7 @@
 package httpserver
 
 import (
+	"crypto/tls"
 	"net"
 	"time"
 
@@ -26,6 +27,14 @@ func TLS(enable bool, certFile, keyFile string) Option {
 	}
 }
 
+// TLSConfig sets the TLS config, e.g. to verify client certificates. Its
+// minimum version is raised to TLS 1.3.
+func TLSConfig(cfg *tls.Config) Option {
+	return func(s *Server) {
+		s.server.TLSConfig = cfg
+	}
+}
+
 // Listener injects a pre-bound listener (useful for tests to avoid binding real ports).
 func Listener(l net.Listener) Option {
 	return func(s *Server) {
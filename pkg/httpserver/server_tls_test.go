@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -333,3 +334,31 @@ func TestTLS_Mismatch_ReturnsError(t *testing.T) { //nolint:paralleltest // serv
 
 	_ = s.Shutdown()
 }
+
+func TestTLS_WithTLSConfig_KeepsClientAuth(t *testing.T) { //nolint:paralleltest // binds a port
+	cert, key := writeTempCertPair(t)
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte("ok")) })
+
+	l := newTestListener(t)
+	s := New(handler, Listener(l), TLS(true, cert, key), TLSConfig(&tls.Config{ClientAuth: tls.RequireAnyClientCert}))
+
+	defer func() { _ = s.Shutdown() }()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}} //nolint:gosec // test-only self-signed cert
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://"+l.Addr().String()+"/", http.NoBody)
+	if err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatalf("expected the handshake to require a client certificate")
+	}
+
+	if !strings.Contains(err.Error(), "certificate required") {
+		t.Fatalf("expected a certificate required alert, got %v", err)
+	}
+}